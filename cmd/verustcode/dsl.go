@@ -0,0 +1,518 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/database"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/dsltest"
+	"github.com/verustcode/verustcode/internal/engine/utils"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/report"
+	"github.com/verustcode/verustcode/internal/store"
+)
+
+// dslCmd groups the review DSL commands
+var dslCmd = &cobra.Command{
+	Use:   "dsl",
+	Short: "Review and report DSL tools",
+}
+
+// dslTestCmd represents the dsl test command
+var dslTestCmd = &cobra.Command{
+	Use:   "test <review-file> <fixture-dir>...",
+	Short: "Run a review file against test fixtures",
+	Long: `Run a review file against test fixtures with the mock agent, and check the
+rendered prompts, findings, skipped rules and published outputs.
+
+A fixture directory contains:
+  repo/         repository snapshot at the head commit
+  base/         repository snapshot at the base commit (optional, default: empty)
+  pr.yaml       PR metadata (number, title, description, labels, author, ...)
+  responses/    canned agent responses: <rule-id>.json, or <rule-id>/ with one file per call
+  expect.yaml   expected rule status, findings and outputs (optional)
+  golden/       golden rendered prompts: <rule-id>.prompt
+
+A directory without repo/ runs each of its fixture subdirectories.
+
+Example:
+  verustcode dsl test config/reviews/default.yaml tests/review-fixtures
+  verustcode dsl test config/reviews/default.yaml tests/review-fixtures --update`,
+	Args: cobra.MinimumNArgs(2),
+	Run:  runDSLTest,
+}
+
+// runDSLTest runs the dsl test command
+func runDSLTest(cmd *cobra.Command, args []string) {
+	update, _ := cmd.Flags().GetBool("update")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	loader := dsl.NewLoader()
+	loader.SetResolver(dsl.NewResolver(filepath.Dir(args[0])))
+	rulesConfig, err := loader.Load(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load review file: %v\n", err)
+		os.Exit(1)
+	}
+
+	fixtures, err := findFixtures(args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	var results []*dsltest.Result
+	failed := 0
+	for _, dir := range fixtures {
+		result, err := dsltest.Run(context.Background(), rulesConfig, dir, &dsltest.Options{Update: update})
+		if err != nil {
+			result = &dsltest.Result{Fixture: dir, Failures: []string{err.Error()}}
+		}
+		if !result.Passed() {
+			failed++
+		}
+		results = append(results, result)
+		if !jsonOutput {
+			printDSLTestResult(result)
+		}
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(results)
+	} else {
+		fmt.Printf("\n%d fixtures, %d passed, %d failed\n", len(results), len(results)-failed, failed)
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// printDSLTestResult prints the outcome of a fixture
+func printDSLTestResult(result *dsltest.Result) {
+	status := "PASS"
+	if !result.Passed() {
+		status = "FAIL"
+	}
+	fmt.Printf("%s %s\n", status, result.Fixture)
+
+	for _, rule := range result.Rules {
+		detail := ""
+		switch {
+		case rule.SkipReason != "":
+			detail = ": " + rule.SkipReason
+		case rule.Error != "":
+			detail = ": " + rule.Error
+		case rule.Status == "completed":
+			detail = fmt.Sprintf(" (%d findings)", len(rule.Findings))
+		}
+		fmt.Printf("  %s %s%s\n", rule.RuleID, rule.Status, detail)
+	}
+	for _, failure := range result.Failures {
+		fmt.Printf("  - %s\n", failure)
+	}
+}
+
+// findFixtures returns the fixture directories of the arguments.
+// A directory without a repo/ snapshot is expanded to its fixture subdirectories.
+func findFixtures(dirs []string) ([]string, error) {
+	var fixtures []string
+	for _, dir := range dirs {
+		if isFixtureDir(dir) {
+			fixtures = append(fixtures, dir)
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture directory: %w", err)
+		}
+		var found []string
+		for _, entry := range entries {
+			sub := filepath.Join(dir, entry.Name())
+			if entry.IsDir() && isFixtureDir(sub) {
+				found = append(found, sub)
+			}
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("no fixtures found in %s (a fixture contains a %s/ directory)", dir, dsltest.RepoDir)
+		}
+		sort.Strings(found)
+		fixtures = append(fixtures, found...)
+	}
+	return fixtures, nil
+}
+
+// isFixtureDir returns true if dir contains a repository snapshot
+func isFixtureDir(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, dsltest.RepoDir))
+	return err == nil && info.IsDir()
+}
+
+// dslValidateCmd represents the dsl validate command
+var dslValidateCmd = &cobra.Command{
+	Use:   "validate [path]...",
+	Short: "Validate review and report files",
+	Long: `Validate review and report files with the strict parser: unknown keys,
+missing goal areas and invalid values are errors.
+
+Paths are files or directories (searched recursively for *.yaml and *.yml).
+Without paths, config/reviews and config/reports are validated.
+
+Example:
+  verustcode dsl validate
+  verustcode dsl validate config/reviews/default.yaml .verust-review.yaml`,
+	Run: runDSLValidate,
+}
+
+// dslFmtCmd represents the dsl fmt command
+var dslFmtCmd = &cobra.Command{
+	Use:   "fmt [path]...",
+	Short: "Format review and report files",
+	Long: `Format review and report files with a canonical key order (the order of the
+DSL reference) and 2-space indentation. Comments are preserved.
+
+Without -w, the formatted files are printed to stdout.
+Without paths, config/reviews and config/reports are formatted.`,
+	Run: runDSLFmt,
+}
+
+// dslExplainCmd represents the dsl explain command
+var dslExplainCmd = &cobra.Command{
+	Use:   "explain <repo-url>",
+	Short: "Print the effective review config of a repository",
+	Long: `Print the effective review config of a repository: the review file configured
+for the repository (or default.yaml), combined with .verust-review.yaml of a local
+checkout as allowed by the repository's in-repo config policy.
+
+Rules are printed after include, extends and rule_base are resolved.
+
+Example:
+  verustcode dsl explain https://github.com/org/repo
+  verustcode dsl explain https://github.com/org/repo --repo-path ../repo --base origin/main`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDSLExplain,
+}
+
+// dslMigrateCmd represents the dsl migrate command
+var dslMigrateCmd = &cobra.Command{
+	Use:   "migrate [path]...",
+	Short: "Upgrade review and report files to the current DSL version",
+	Long: `Upgrade review and report files written for an older DSL version to the current
+version, and print the applied migrations. Files are only rewritten with -w.
+
+Without paths, config/reviews and config/reports are migrated.`,
+	Run: runDSLMigrate,
+}
+
+// dslSchemaCmd represents the dsl schema command
+var dslSchemaCmd = &cobra.Command{
+	Use:   "schema <review|report>",
+	Short: "Print the JSON Schema of review or report files",
+	Long: `Print the JSON Schema of review or report files, for editor autocompletion and
+validation. The running server also serves the schemas at
+/api/v1/schemas/review-dsl.json and /api/v1/schemas/report-dsl.json.
+
+Example (YAML language server):
+  verustcode dsl schema review > .vscode/verustcode-review.schema.json`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{dsl.DSLKindReview, dsl.DSLKindReport},
+	Run:       runDSLSchema,
+}
+
+// dslFile is a review or report file found by the dsl commands
+type dslFile struct {
+	path string
+	// root is the directory against which include and extends references are resolved
+	root string
+}
+
+// findDSLFiles returns the YAML files of the paths.
+// Without paths, the files in config/reviews and config/reports are returned.
+func findDSLFiles(paths []string) ([]dslFile, error) {
+	if len(paths) == 0 {
+		for _, dir := range []string{config.ReviewsDir, report.DefaultReportsConfigDir} {
+			if info, err := os.Stat(dir); err == nil && info.IsDir() {
+				paths = append(paths, dir)
+			}
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no paths given and neither %s nor %s exists", config.ReviewsDir, report.DefaultReportsConfigDir)
+		}
+	}
+
+	var files []dslFile
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, dslFile{path: path, root: filepath.Dir(path)})
+			continue
+		}
+		err = filepath.WalkDir(path, func(file string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := filepath.Ext(file)
+			if !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				files = append(files, dslFile{path: file, root: path})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// readDSLFile reads a DSL file and detects its kind
+func readDSLFile(path string) ([]byte, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	kind, err := dsl.DetectDSLKind(data)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, kind, nil
+}
+
+// runDSLValidate runs the dsl validate command
+func runDSLValidate(cmd *cobra.Command, args []string) {
+	files, err := findDSLFiles(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	failed := 0
+	for _, file := range files {
+		summary, warning, err := validateDSLFile(file)
+		if err != nil {
+			failed++
+			fmt.Printf("FAIL %s: %v\n", file.path, err)
+			continue
+		}
+		fmt.Printf("OK   %s (%s)\n", file.path, summary)
+		if warning != "" {
+			fmt.Printf("     warning: %s\n", warning)
+		}
+	}
+
+	fmt.Printf("\n%d files, %d valid, %d invalid\n", len(files), len(files)-failed, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// validateDSLFile validates a DSL file with the strict loaders.
+// Returns a summary of the file and a warning for outdated DSL versions.
+func validateDSLFile(file dslFile) (string, string, error) {
+	data, kind, err := readDSLFile(file.path)
+	if err != nil {
+		return "", "", err
+	}
+
+	_, migrations, err := dsl.MigrateDSL(data, kind)
+	if err != nil {
+		return "", "", err
+	}
+	warning := ""
+	if len(migrations) > 0 {
+		warning = fmt.Sprintf("DSL version is missing or older than %s, run 'verustcode dsl migrate -w %s'", dsl.CurrentDSLVersion, file.path)
+	}
+
+	if kind == dsl.DSLKindReport {
+		reportConfig, err := dsl.NewStrictReportLoader().LoadFile(file.path)
+		if err != nil {
+			return "", "", err
+		}
+		return fmt.Sprintf("report %s", reportConfig.ID), warning, nil
+	}
+
+	loader := dsl.NewStrictLoader()
+	loader.SetResolver(dsl.NewResolver(file.root))
+	rulesConfig, err := loader.Load(file.path)
+	if err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("review, %d rules", len(rulesConfig.Rules)), warning, nil
+}
+
+// runDSLFmt runs the dsl fmt command
+func runDSLFmt(cmd *cobra.Command, args []string) {
+	write, _ := cmd.Flags().GetBool("write")
+	list, _ := cmd.Flags().GetBool("list")
+
+	files, err := findDSLFiles(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	failed := false
+	for _, file := range files {
+		data, kind, err := readDSLFile(file.path)
+		if err == nil {
+			var formatted []byte
+			formatted, err = dsl.FormatDSL(data, kind)
+			if err == nil {
+				err = outputDSLFile(file.path, data, formatted, write, list)
+			}
+		}
+		if err != nil {
+			failed = true
+			fmt.Fprintf(os.Stderr, "%s: %v\n", file.path, err)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// runDSLMigrate runs the dsl migrate command
+func runDSLMigrate(cmd *cobra.Command, args []string) {
+	write, _ := cmd.Flags().GetBool("write")
+
+	files, err := findDSLFiles(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	failed := false
+	for _, file := range files {
+		data, kind, err := readDSLFile(file.path)
+		if err == nil {
+			var migrated []byte
+			var migrations []string
+			migrated, migrations, err = dsl.MigrateDSL(data, kind)
+			if err == nil {
+				if len(migrations) == 0 {
+					fmt.Printf("%s: up to date (version %s)\n", file.path, dsl.CurrentDSLVersion)
+					continue
+				}
+				for _, migration := range migrations {
+					fmt.Printf("%s: %s\n", file.path, migration)
+				}
+				if write {
+					err = os.WriteFile(file.path, migrated, 0644)
+				}
+			}
+		}
+		if err != nil {
+			failed = true
+			fmt.Fprintf(os.Stderr, "%s: %v\n", file.path, err)
+		}
+	}
+	if !write {
+		fmt.Println("\nRun with -w to rewrite the files.")
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// outputDSLFile writes a formatted file in place (write), lists it if it changed (list),
+// or prints it to stdout
+func outputDSLFile(path string, original, formatted []byte, write, list bool) error {
+	changed := string(original) != string(formatted)
+	if list && changed {
+		fmt.Println(path)
+	}
+	if write {
+		if !changed {
+			return nil
+		}
+		return os.WriteFile(path, formatted, 0644)
+	}
+	if !list {
+		_, err := os.Stdout.Write(formatted)
+		return err
+	}
+	return nil
+}
+
+// runDSLExplain runs the dsl explain command
+func runDSLExplain(cmd *cobra.Command, args []string) {
+	repoURL := args[0]
+	repoPath, _ := cmd.Flags().GetString("repo-path")
+	baseCommit, _ := cmd.Flags().GetString("base")
+
+	// Repository settings are stored in the database; without a bootstrap config,
+	// the default review file and policy apply
+	var repoConfig *model.RepositoryReviewConfig
+	if _, err := loadConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "[WARNING] %v\nUsing the default review file and in-repo config policy.\n\n", err)
+	} else {
+		defer database.Close()
+		repoConfig, err = store.NewStore(database.Get()).RepositoryConfig().GetByRepoURL(repoURL)
+		if err != nil {
+			repoConfig = nil
+		}
+	}
+
+	loader := dsl.NewLoader()
+	reviewFile := config.DefaultReviewFile
+	if repoConfig != nil && repoConfig.ReviewFile != "" {
+		reviewFile = repoConfig.ReviewFile
+	}
+	serverConfig, err := loader.Load(filepath.Join(config.ReviewsDir, reviewFile))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load review file %s: %v\n", reviewFile, err)
+		os.Exit(1)
+	}
+
+	policy := utils.InRepoConfigPolicyOf(repoConfig)
+	if repoPath != "" && baseCommit == "" {
+		baseCommit = "HEAD"
+	}
+	effective := utils.ApplyInRepoConfigPolicy(loader, policy, serverConfig, repoPath, baseCommit)
+
+	out, err := yaml.Marshal(effective.Config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode review config: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("# Effective review config of %s\n", repoURL)
+	fmt.Printf("# Review file: %s\n", reviewFile)
+	fmt.Printf("# In-repo config policy: %s\n", policy)
+	fmt.Printf("# Source: %s\n", effective.Source)
+	if repoPath == "" {
+		fmt.Printf("# No --repo-path given, %s of the repository is not considered\n", config.RepoRootReviewPath)
+	}
+	for _, rejection := range effective.Rejections {
+		fmt.Printf("# Rejected: %s\n", rejection)
+	}
+	os.Stdout.Write(out)
+}
+
+// runDSLSchema runs the dsl schema command
+func runDSLSchema(cmd *cobra.Command, args []string) {
+	schema := dsl.GetDSLSchema(args[0]+"-dsl", dsl.DSLSchemaOptions{AgentTypes: base.List()})
+	if schema == nil {
+		fmt.Fprintf(os.Stderr, "Unknown schema %q (valid: %s, %s)\n", args[0], dsl.DSLKindReview, dsl.DSLKindReport)
+		os.Exit(1)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(schema)
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/database"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/eval"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/shared"
+	"github.com/verustcode/verustcode/internal/store"
+)
+
+// evalCmd groups the evaluation commands
+var evalCmd = &cobra.Command{
+	Use:   "eval",
+	Short: "Measure the precision and recall of review rules",
+	Long: `Measure the precision and recall of review rules and models against a dataset
+of labeled pull requests, and compare the stored results of earlier evaluations.`,
+}
+
+// evalRunCmd represents the eval run command
+var evalRunCmd = &cobra.Command{
+	Use:   "run <dataset-file>",
+	Short: "Evaluate review rules against a labeled dataset",
+	Long: `Review every case of a dataset with the selected rules, once per configuration,
+and report precision, recall, cost and latency per configuration.
+
+A dataset is a YAML file of cases: a commit range of a git repository (a local path
+relative to the dataset file, or a clone URL), the PR metadata and the expected findings:
+
+  name: security-bench
+  cases:
+    - id: sqli-user-lookup
+      repo: repos/shop
+      base: 3f2a1c0
+      head: 9c1d7e4
+      pr:
+        title: Add user lookup
+      expected:
+        - file: store/users.go
+          lines: [42, 45]
+          category: security
+          title: SQL injection
+
+A configuration is "agent" or "agent:model" and replaces the agent and model of every
+rule; without --config the rules run as configured. Agents are called for real, with the
+server's agent and review settings. Results are stored for 'verustcode eval list'.
+
+Example:
+  verustcode eval run eval/security.yaml --rules security
+  verustcode eval run eval/security.yaml --config cursor:sonnet-4.5 --config gemini:gemini-2.5-pro`,
+	Args: cobra.ExactArgs(1),
+	Run:  runEvalRun,
+}
+
+// evalListCmd represents the eval list command
+var evalListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored evaluation results",
+	Long: `List stored evaluation results, newest first, to compare configurations.
+
+Example:
+  verustcode eval list --dataset security-bench`,
+	Args: cobra.NoArgs,
+	Run:  runEvalList,
+}
+
+// runEvalRun runs the eval run command
+func runEvalRun(cmd *cobra.Command, args []string) {
+	reviewFile, _ := cmd.Flags().GetString("review-file")
+	ruleIDs, _ := cmd.Flags().GetStringSlice("rules")
+	specs, _ := cmd.Flags().GetStringArray("config")
+	tolerance, _ := cmd.Flags().GetInt("line-tolerance")
+	minSimilarity, _ := cmd.Flags().GetFloat64("min-similarity")
+	noSave, _ := cmd.Flags().GetBool("no-save")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	ds, err := eval.LoadDataset(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	defer database.Close()
+	dataStore := store.NewStore(database.Get())
+
+	if reviewFile == "" {
+		reviewFile = filepath.Join(config.ReviewsDir, config.DefaultReviewFile)
+	}
+	loader := dsl.NewLoader()
+	loader.SetResolver(dsl.NewResolver(filepath.Dir(reviewFile)))
+	rules, err := loader.Load(reviewFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load review file: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts := &eval.Options{
+		Rules:        rules,
+		RuleIDs:      ruleIDs,
+		Agents:       shared.InitAgents(cfg, dataStore),
+		ReviewConfig: &cfg.Review,
+		Match:        eval.MatchOptions{LineTolerance: tolerance, MinSimilarity: minSimilarity},
+	}
+	for _, spec := range specs {
+		opts.Configurations = append(opts.Configurations, eval.ParseConfiguration(spec))
+	}
+
+	report, err := eval.Run(context.Background(), ds, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Evaluation failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !noSave {
+		if _, err := report.Save(dataStore); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to store results: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+		return
+	}
+	printEvalReport(report)
+}
+
+// printEvalReport prints the metrics of each configuration and the unmatched findings
+func printEvalReport(report *eval.Report) {
+	fmt.Printf("Dataset %s, rules: %s\n\n", report.Dataset, strings.Join(report.Rules, ", "))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONFIGURATION\tPRECISION\tRECALL\tF1\tTP\tFP\tFN\tFAILED\tCOST\tTOKENS\tLATENCY")
+	for _, r := range report.Results {
+		fmt.Fprintf(w, "%s\t%.2f\t%.2f\t%.2f\t%d\t%d\t%d\t%d\t$%.4f\t%d\t%.1fs\n",
+			r.Name, r.Precision, r.Recall, r.F1, r.TruePositives, r.FalsePositives, r.FalseNegatives,
+			r.FailedCases, r.Usage.Cost, r.Usage.TotalTokens(), float64(r.Latency)/1000)
+	}
+	w.Flush()
+
+	for _, r := range report.Results {
+		for _, c := range r.Cases {
+			if c.Error == "" && len(c.Missed) == 0 && len(c.Unexpected) == 0 {
+				continue
+			}
+			fmt.Printf("\n%s / %s\n", r.Name, c.CaseID)
+			if c.Error != "" {
+				fmt.Printf("  failed: %s\n", c.Error)
+			}
+			for _, missed := range c.Missed {
+				fmt.Printf("  - missed: %s\n", missed)
+			}
+			for _, unexpected := range c.Unexpected {
+				fmt.Printf("  + unexpected: %s\n", unexpected)
+			}
+		}
+	}
+}
+
+// runEvalList runs the eval list command
+func runEvalList(cmd *cobra.Command, args []string) {
+	dataset, _ := cmd.Flags().GetString("dataset")
+	limit, _ := cmd.Flags().GetInt("limit")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	if _, err := loadConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	defer database.Close()
+
+	runs, err := store.NewStore(database.Get()).Eval().ListRuns(dataset, limit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list evaluation results: %v\n", err)
+		os.Exit(1)
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(runs)
+		return
+	}
+	printEvalRuns(runs)
+}
+
+// printEvalRuns prints stored evaluation runs as a table
+func printEvalRuns(runs []model.EvalRun) {
+	if len(runs) == 0 {
+		fmt.Println("No evaluation results")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tDATE\tDATASET\tCONFIGURATION\tRULES\tCASES\tPRECISION\tRECALL\tF1\tCOST\tLATENCY")
+	for _, run := range runs {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\t%.2f\t%.2f\t%.2f\t$%.4f\t%.1fs\n",
+			run.ID, run.CreatedAt.Format("2006-01-02 15:04"), run.Dataset, run.Configuration,
+			strings.Join(run.Rules, ","), run.Cases, run.Precision, run.Recall, run.F1,
+			run.Cost, float64(run.Latency)/1000)
+	}
+	w.Flush()
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/verustcode/verustcode/internal/config"
 	"github.com/verustcode/verustcode/internal/database"
 	"github.com/verustcode/verustcode/internal/engine"
+	"github.com/verustcode/verustcode/internal/eval"
 	"github.com/verustcode/verustcode/internal/notification"
 	"github.com/verustcode/verustcode/internal/report"
 	"github.com/verustcode/verustcode/internal/server"
@@ -102,12 +103,52 @@ func init() {
 	// Add commands
 	rootCmd.AddCommand(serveCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(dslCmd)
+	dslCmd.AddCommand(dslTestCmd)
+	dslCmd.AddCommand(dslValidateCmd)
+	dslCmd.AddCommand(dslFmtCmd)
+	dslCmd.AddCommand(dslExplainCmd)
+	dslCmd.AddCommand(dslMigrateCmd)
+	dslCmd.AddCommand(dslSchemaCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(evalCmd)
+	evalCmd.AddCommand(evalRunCmd)
+	evalCmd.AddCommand(evalListCmd)
+	rootCmd.AddCommand(sandboxExecCmd)
 
 	// Serve command flags
 	serveCmd.Flags().String("host", "", "server host (overrides config)")
 	serveCmd.Flags().Int("port", 0, "server port (overrides config)")
 	serveCmd.Flags().Bool("debug", false, "enable debug mode")
 	serveCmd.Flags().Bool("check", false, "run interactive environment check before starting server")
+
+	// DSL test command flags
+	dslTestCmd.Flags().Bool("update", false, "rewrite golden prompts instead of comparing them")
+	dslTestCmd.Flags().Bool("json", false, "print results as JSON")
+
+	// DSL fmt, migrate and explain command flags
+	dslFmtCmd.Flags().BoolP("write", "w", false, "write the result to the files instead of stdout")
+	dslFmtCmd.Flags().BoolP("list", "l", false, "list the files whose formatting differs")
+	dslMigrateCmd.Flags().BoolP("write", "w", false, "rewrite the migrated files")
+	dslExplainCmd.Flags().String("repo-path", "", "local checkout of the repository, to consider its "+config.RepoRootReviewPath)
+	dslExplainCmd.Flags().String("base", "", "base commit of the change, read by the base_branch policy (default: HEAD)")
+
+	// Replay command flags
+	replayCmd.Flags().String("repo-path", "", "checkout of the reviewed commit used as workspace")
+	replayCmd.Flags().String("review-file", "", "review file whose rules replace the recorded rule configurations")
+	replayCmd.Flags().Bool("json", false, "print the result as JSON")
+
+	// Eval command flags
+	evalRunCmd.Flags().String("review-file", "", "review file whose rules are evaluated (default: config/reviews/default.yaml)")
+	evalRunCmd.Flags().StringSlice("rules", nil, "IDs of the evaluated rules (default: all rules)")
+	evalRunCmd.Flags().StringArray("config", nil, "evaluated configuration as agent or agent:model (repeatable, default: the rules as configured)")
+	evalRunCmd.Flags().Int("line-tolerance", eval.DefaultLineTolerance, "number of lines a predicted and an expected finding may be apart")
+	evalRunCmd.Flags().Float64("min-similarity", eval.DefaultMinSimilarity, "minimum similarity (0.0-1.0) of the category and text of matched findings")
+	evalRunCmd.Flags().Bool("no-save", false, "don't store the results")
+	evalRunCmd.Flags().Bool("json", false, "print the report as JSON")
+	evalListCmd.Flags().String("dataset", "", "only list results of this dataset")
+	evalListCmd.Flags().Int("limit", 20, "maximum number of results (0 = all)")
+	evalListCmd.Flags().Bool("json", false, "print the results as JSON")
 }
 
 func main() {
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/verustcode/verustcode/internal/cassette"
+	"github.com/verustcode/verustcode/internal/dsl"
+)
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay <cassette-file>",
+	Short: "Replay a recorded review offline",
+	Long: `Replay a review recorded into a cassette through the current review pipeline,
+with every agent answering from the cassette instead of being called. Nothing is
+published; the outcome of each rule is printed along with the agent calls whose
+prompt differs from the recorded one.
+
+Cassettes are recorded when the "recording" review setting is enabled and are
+downloaded from GET /api/v1/admin/reviews/{id}/cassette.
+
+Example:
+  verustcode replay d1a2b3c4.cassette.json
+  verustcode replay d1a2b3c4.cassette.json --repo-path ../checkout --review-file config/reviews/default.yaml`,
+	Args: cobra.ExactArgs(1),
+	Run:  runReplay,
+}
+
+// runReplay runs the replay command
+func runReplay(cmd *cobra.Command, args []string) {
+	repoPath, _ := cmd.Flags().GetString("repo-path")
+	reviewFile, _ := cmd.Flags().GetString("review-file")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	cas, err := cassette.Load(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	opts := &cassette.ReplayOptions{RepoPath: repoPath}
+	if reviewFile != "" {
+		loader := dsl.NewLoader()
+		loader.SetResolver(dsl.NewResolver(filepath.Dir(reviewFile)))
+		if opts.Rules, err = loader.Load(reviewFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load review file: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	result, err := cassette.Replay(context.Background(), cas, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to replay cassette: %v\n", err)
+		os.Exit(1)
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(result)
+		return
+	}
+	printReplayResult(result)
+}
+
+// printReplayResult prints the outcome of a replay
+func printReplayResult(result *cassette.ReplayResult) {
+	fmt.Printf("Replayed review %s\n", result.ReviewID)
+
+	for _, rule := range result.Rules {
+		detail := ""
+		switch {
+		case rule.SkipReason != "":
+			detail = ": " + rule.SkipReason
+		case rule.Error != "":
+			detail = ": " + rule.Error
+		case rule.Status == "completed":
+			detail = fmt.Sprintf(" (%d findings)", len(rule.Findings))
+		}
+		fmt.Printf("  %s %s%s\n", rule.RuleID, rule.Status, detail)
+	}
+
+	for _, m := range result.Mismatches {
+		fmt.Printf("  - prompt of %s call %d differs from the recording at line %d\n",
+			m.RuleID, m.Index+1, firstDifferentLine(m.Recorded, m.Actual))
+	}
+	for _, ruleID := range result.UnservedRuleIDs() {
+		fmt.Printf("  - %d recorded calls of %s were not replayed\n", result.Unserved[ruleID], ruleID)
+	}
+}
+
+// firstDifferentLine returns the first line (1-based) at which a and b differ
+func firstDifferentLine(a, b string) int {
+	linesA := strings.Split(a, "\n")
+	linesB := strings.Split(b, "\n")
+	for i := range linesA {
+		if i >= len(linesB) || linesA[i] != linesB[i] {
+			return i + 1
+		}
+	}
+	return len(linesA) + 1
+}
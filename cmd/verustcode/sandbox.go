@@ -0,0 +1,24 @@
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/verustcode/verustcode/internal/llm"
+)
+
+// sandboxExecCmd runs an agent CLI inside the sandbox. It is started by bubblewrap
+// when an agent has a sandbox configured and is not meant to be run by hand.
+var sandboxExecCmd = &cobra.Command{
+	Use:                llm.SandboxHelperCommand + " -- <command> [args...]",
+	Short:              "Run an agent CLI inside the sandbox (internal)",
+	Hidden:             true,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && args[0] == "--" {
+			args = args[1:]
+		}
+		os.Exit(llm.RunSandboxHelper(args))
+	},
+}
@@ -8,7 +8,9 @@ import { toast } from '@/hooks/useToast'
 import i18n from '@/i18n'
 import type { RepositoryConfigItem, CreateRepositoryConfigRequest, UpdateRepositoryConfigRequest } from '@/types/repository'
 import type { TaskLogsResponse } from '@/types/api'
+import type { GenerateBaselineResponse } from '@/types/review'
 import type { FindingsListParams, FindingsListResponse } from '@/types/finding'
+import type { AreaDefinition } from '@/types/rule'
 
 // API base URL - uses proxy in development
 const API_BASE_URL = '/api/v1'
@@ -245,6 +247,9 @@ export const api = {
     retry: (id: string) => post<{ message: string }>(`/reviews/${id}/retry`),
     retryRule: (reviewId: string, ruleId: string) => 
       post<{ message: string }>(`/reviews/${reviewId}/rules/${ruleId}/retry`),
+    // Generate a baseline file that accepts the findings of a completed review
+    generateBaseline: (id: string, data?: { reason?: string; expires?: string }) =>
+      post<GenerateBaselineResponse>(`/reviews/${id}/baseline`, data),
     // Get logs for a specific review
     getLogs: (id: string, params?: { page?: number; page_size?: number; level?: string }) =>
       get<TaskLogsResponse>(`/reviews/${id}/logs`, { params }),
@@ -265,6 +270,18 @@ export const api = {
     rules: {
       list: () => get<{ files: { name: string; path: string; modified_at: string }[] }>('/admin/rules'),
       get: (name: string) => get<{ content: string; hash: string }>(`/admin/rules/${name}`),
+      resolved: (name: string) =>
+        get<{ content: string; rules: { id: string; source: string }[]; includes: Record<string, string[]> }>(
+          `/admin/rules/${name}/resolved`
+        ),
+      // Render the final prompt of a rule for a sample PR (content: unsaved file content)
+      preview: (
+        name: string,
+        ruleId: string,
+        content?: string,
+        pr?: { number?: number; title?: string; description?: string; ref?: string; changed_files?: string[] }
+      ) =>
+        post<{ rule_id: string; prompt: string }>(`/admin/rules/${name}/preview`, { rule_id: ruleId, content, pr }),
       save: (name: string, content: string, hash: string) => 
         put<{ message: string; hash: string }>(`/admin/rules/${name}`, { content, hash }),
       validate: (content: string) => post<{ valid: boolean; errors?: string[] }>('/admin/rules/validate', { content }),
@@ -273,6 +290,14 @@ export const api = {
         post<{ message: string; name: string; hash: string }>('/admin/rules', { name, copy_from: copyFrom }),
     },
 
+    // Review areas (built-in and organization-wide custom areas)
+    areas: {
+      list: () => get<{ data: AreaDefinition[]; total: number }>('/admin/areas'),
+      create: (area: AreaDefinition) => post<AreaDefinition>('/admin/areas', area),
+      update: (id: string, area: Omit<AreaDefinition, 'id'>) => put<AreaDefinition>(`/admin/areas/${id}`, area),
+      delete: (id: string) => del<{ message: string }>(`/admin/areas/${id}`),
+    },
+
     // Review files (list available review config files)
     reviewFiles: {
       list: () => get<{ files: string[] }>('/admin/review-files'),
@@ -328,10 +353,12 @@ export const api = {
       testAgent: (data: { 
         name: string; 
         cli_path?: string; 
+        base_url?: string; 
         api_key?: string; 
         default_model?: string; 
         fallback_models?: string[]; 
-        timeout?: number 
+        timeout?: number; 
+        thinking_budget?: number 
       }) =>
         post<{ success: boolean; message: string; data?: string }>('/admin/settings/agents/test', data),
       // Test notification configuration
@@ -33,7 +33,7 @@ import { Label } from '@/components/ui/label'
 import { api } from '@/lib/api'
 import { formatRelativeTime, formatRepoUrl } from '@/lib/utils'
 import { toast } from '@/hooks/useToast'
-import type { RepositoryConfigItem } from '@/types/repository'
+import type { InRepoConfigPolicy, RepositoryConfigItem, UpdateRepositoryConfigRequest } from '@/types/repository'
 
 // Page size options and storage key
 const PAGE_SIZE_OPTIONS = [10, 20, 50, 100] as const
@@ -81,6 +81,7 @@ export default function Repositories() {
   const [formUrl, setFormUrl] = useState('')
   const [formReviewFile, setFormReviewFile] = useState('')
   const [formDescription, setFormDescription] = useState('')
+  const [formPolicy, setFormPolicy] = useState<InRepoConfigPolicy>('base_branch')
   const [urlParseError, setUrlParseError] = useState('')
 
   // Fetch repositories
@@ -146,7 +147,7 @@ export default function Repositories() {
   })
 
   const updateMutation = useMutation({
-    mutationFn: ({ id, data }: { id: number; data: { review_file?: string; description?: string } }) =>
+    mutationFn: ({ id, data }: { id: number; data: UpdateRepositoryConfigRequest }) =>
       api.admin.repositories.update(id, data),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ['repositories'] })
@@ -175,6 +176,7 @@ export default function Repositories() {
     setFormUrl('')
     setFormReviewFile('')
     setFormDescription('')
+    setFormPolicy('base_branch')
     setUrlParseError('')
   }
 
@@ -189,6 +191,7 @@ export default function Repositories() {
     setSelectedRepo(repo)
     setFormReviewFile(repo.review_file || '')
     setFormDescription(repo.description || '')
+    setFormPolicy(repo.in_repo_config_policy || 'base_branch')
     setShowEditDialog(true)
   }
 
@@ -212,6 +215,7 @@ export default function Repositories() {
       repo_url: formUrl.trim(),
       review_file: formReviewFile || undefined,
       description: formDescription || undefined,
+      in_repo_config_policy: formPolicy,
     })
   }
 
@@ -223,6 +227,7 @@ export default function Repositories() {
       data: {
         review_file: formReviewFile || undefined,
         description: formDescription || undefined,
+        in_repo_config_policy: formPolicy,
       },
     })
   }
@@ -547,6 +552,22 @@ export default function Repositories() {
                 </SelectContent>
               </Select>
             </div>
+            <div className="grid gap-2">
+              <Label htmlFor="inRepoConfigPolicy">{t('repositories.inRepoConfigPolicy')}</Label>
+              <Select value={formPolicy} onValueChange={(value) => setFormPolicy(value as InRepoConfigPolicy)}>
+                <SelectTrigger id="inRepoConfigPolicy">
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  <SelectItem value="base_branch">{t('repositories.inRepoConfigPolicies.base_branch')}</SelectItem>
+                  <SelectItem value="merge">{t('repositories.inRepoConfigPolicies.merge')}</SelectItem>
+                  <SelectItem value="ignore">{t('repositories.inRepoConfigPolicies.ignore')}</SelectItem>
+                </SelectContent>
+              </Select>
+              <p className="text-xs text-[hsl(var(--muted-foreground))]">
+                {t('repositories.inRepoConfigPolicyHint')}
+              </p>
+            </div>
             <div className="grid gap-2">
               <Label htmlFor="description">{t('repositories.description')}</Label>
               <Input
@@ -596,6 +617,22 @@ export default function Repositories() {
                 </SelectContent>
               </Select>
             </div>
+            <div className="grid gap-2">
+              <Label htmlFor="editInRepoConfigPolicy">{t('repositories.inRepoConfigPolicy')}</Label>
+              <Select value={formPolicy} onValueChange={(value) => setFormPolicy(value as InRepoConfigPolicy)}>
+                <SelectTrigger id="editInRepoConfigPolicy">
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  <SelectItem value="base_branch">{t('repositories.inRepoConfigPolicies.base_branch')}</SelectItem>
+                  <SelectItem value="merge">{t('repositories.inRepoConfigPolicies.merge')}</SelectItem>
+                  <SelectItem value="ignore">{t('repositories.inRepoConfigPolicies.ignore')}</SelectItem>
+                </SelectContent>
+              </Select>
+              <p className="text-xs text-[hsl(var(--muted-foreground))]">
+                {t('repositories.inRepoConfigPolicyHint')}
+              </p>
+            </div>
             <div className="grid gap-2">
               <Label htmlFor="editDescription">{t('repositories.description')}</Label>
               <Input
@@ -23,6 +23,7 @@ import {
   CheckCircle2,
   CircleDot,
   CircleAlert,
+  FileDown,
 } from 'lucide-react'
 import { JsonView } from 'react-json-view-lite'
 import 'react-json-view-lite/dist/index.css'
@@ -1031,6 +1032,27 @@ export default function ReviewDetail() {
     },
   })
 
+  // Generate baseline mutation - downloads a baseline file accepting the review's findings
+  const baselineMutation = useMutation({
+    mutationFn: () => api.reviews.generateBaseline(id!),
+    onSuccess: (data) => {
+      const blob = new Blob([data.content], { type: 'application/yaml' })
+      const url = URL.createObjectURL(blob)
+      const link = document.createElement('a')
+      link.href = url
+      link.download = 'baseline.yaml'
+      link.click()
+      URL.revokeObjectURL(url)
+      toast({
+        title: t('reviews.baseline.success'),
+        description: t('reviews.baseline.successDescription', { count: data.count, path: data.path }),
+      })
+    },
+    onError: () => {
+      // Error is already handled by global toast in api.ts
+    },
+  })
+
   // Handle rule retry
   const handleRetryRule = (ruleId: string) => {
     setRetryingRuleId(ruleId)
@@ -1133,6 +1155,19 @@ export default function ReviewDetail() {
             )}
           </div>
 
+          {/* Generate baseline button */}
+          {review.status === 'completed' && (
+            <Button
+              size="sm"
+              variant="outline"
+              onClick={() => baselineMutation.mutate()}
+              disabled={baselineMutation.isPending}
+            >
+              <FileDown className="mr-1.5 h-3.5 w-3.5" />
+              {baselineMutation.isPending ? t('reviews.baseline.generating') : t('reviews.baseline.button')}
+            </Button>
+          )}
+
           {/* View logs button */}
           <LogViewer taskType="review" taskId={review.id} />
         </div>
@@ -1231,6 +1266,19 @@ export default function ReviewDetail() {
               </div>
             </div>
 
+            {/* Review config source */}
+            {review.config_source && (
+              <div className="flex items-start gap-3">
+                <FileCode className="mt-0.5 h-4 w-4 text-[hsl(var(--muted-foreground))]" />
+                <div>
+                  <div className="text-sm text-[hsl(var(--muted-foreground))]">
+                    {t('reviews.detail.configSource')}
+                  </div>
+                  <Badge variant="outline">{t(`reviews.detail.configSources.${review.config_source}`)}</Badge>
+                </div>
+              </div>
+            )}
+
             {/* Author */}
             {review.author && (
               <div className="flex items-start gap-3">
@@ -1293,6 +1341,23 @@ export default function ReviewDetail() {
             </>
           )}
 
+          {/* Rejected in-repository config overrides */}
+          {review.config_rejections && review.config_rejections.length > 0 && (
+            <>
+              <Separator className="my-6" />
+              <div className="rounded-md bg-yellow-500/10 p-4">
+                <div className="mb-2 text-sm font-semibold text-yellow-700 dark:text-yellow-400">
+                  {t('reviews.detail.configRejections')}
+                </div>
+                <ul className="list-disc space-y-1 pl-5 text-sm">
+                  {review.config_rejections.map((rejection) => (
+                    <li key={rejection}>{rejection}</li>
+                  ))}
+                </ul>
+              </div>
+            </>
+          )}
+
           {/* Error message */}
           {review.error_message && (
             <>
@@ -1354,6 +1419,14 @@ export default function ReviewDetail() {
                             </span>
                           </>
                         )}
+                        {rule.suppressed_count > 0 && (
+                          <>
+                            <div className="h-5 w-px bg-[hsl(var(--border))]" />
+                            <span className="px-3 py-1.5 text-sm text-[hsl(var(--muted-foreground))]">
+                              {t('reviews.baseline.suppressed', { count: rule.suppressed_count })}
+                            </span>
+                          </>
+                        )}
                         {rule.status === 'failed' && (
                           <>
                             <div className="h-5 w-px bg-[hsl(var(--border))]" />
@@ -45,11 +45,21 @@ interface SettingsData {
   }
   agents?: Record<string, {
     cli_path?: string
+    base_url?: string
     api_key?: string
     timeout?: number
+    thinking_budget?: number
     default_model?: string
     fallback_models?: string[]
     extra_args?: string
+    api_keys?: string[]
+    limits?: {
+      max_concurrent?: number
+      model_concurrency?: Record<string, number>
+      requests_per_minute?: number
+      burst?: number
+      key_selection?: 'round_robin' | 'least_loaded'
+    }
   }>
   review?: {
     workspace?: string
@@ -63,6 +73,7 @@ interface SettingsData {
       show_model?: boolean
       custom_text?: string
     }
+    context_commands?: string[] // commands rules may run as context providers, e.g. "go vet"
   }
   report?: {
     workspace?: string
@@ -81,6 +92,19 @@ interface SettingsData {
   }
 }
 
+// Agents calling an HTTP API instead of a CLI, with their default endpoint and model
+const API_AGENTS = ['openai', 'anthropic', 'ollama']
+const API_BASE_URLS: Record<string, string> = {
+  openai: 'https://api.openai.com/v1',
+  anthropic: 'https://api.anthropic.com',
+  ollama: 'http://localhost:11434',
+}
+const API_DEFAULT_MODELS: Record<string, string> = {
+  openai: 'gpt-4o',
+  anthropic: 'claude-sonnet-4-5',
+  ollama: 'qwen2.5-coder',
+}
+
 /**
  * Settings management page - editable form for runtime configuration
  */
@@ -151,15 +175,19 @@ export default function Settings() {
       const testParams: {
         name: string
         cli_path?: string
+        base_url?: string
         api_key?: string
         default_model?: string
         fallback_models?: string[]
         timeout?: number
+        thinking_budget?: number
       } = {
         name: agentName,
         cli_path: agent.cli_path,
+        base_url: agent.base_url,
         api_key: agent.api_key,
         timeout: agent.timeout,
+        thinking_budget: agent.thinking_budget,
       }
       
       // Qoder doesn't support model parameters
@@ -340,6 +368,9 @@ export default function Settings() {
   // Check if API key exists (non-empty)
   // Masked values are handled by the server, similar to git provider tokens
   const hasValidApiKey = (agentName: string): boolean => {
+    // A local Ollama endpoint needs no API key
+    if (agentName === 'ollama') return true
+
     const agent = getAgent(agentName)
     const apiKey = agent.api_key
     
@@ -665,29 +696,46 @@ export default function Settings() {
                   <TabsTrigger value="cursor">Cursor</TabsTrigger>
                   <TabsTrigger value="gemini">Gemini</TabsTrigger>
                   <TabsTrigger value="qoder">Qoder</TabsTrigger>
+                  <TabsTrigger value="openai">OpenAI</TabsTrigger>
+                  <TabsTrigger value="anthropic">Anthropic</TabsTrigger>
+                  <TabsTrigger value="ollama">Ollama</TabsTrigger>
                   <TabsTrigger value="mock">Mock</TabsTrigger>
                 </TabsList>
 
-                {['cursor', 'gemini', 'qoder', 'mock'].map((agent) => (
+                {['cursor', 'gemini', 'qoder', 'openai', 'anthropic', 'ollama', 'mock'].map((agent) => (
                   <TabsContent key={agent} value={agent} className="space-y-4 pt-4">
                     {agent !== 'mock' && (
                       <>
                         <div className={agent === 'qoder' ? 'grid grid-cols-1 gap-3' : 'grid grid-cols-2 gap-3'}>
-                          <div className="grid gap-1.5">
-                            <Label className="text-sm font-medium text-[hsl(var(--foreground))]">{t('config.cliPath')}</Label>
-                            <Input
-                              value={getAgent(agent).cli_path || ''}
-                              onChange={(e) => updateAgent(agent, 'cli_path', e.target.value)}
-                              placeholder={agent === 'cursor' ? 'cursor-agent' : agent}
-                            />
-                          </div>
+                          {API_AGENTS.includes(agent) ? (
+                            <div className="grid gap-1.5">
+                              <div className="flex items-center gap-1.5">
+                                <Label className="text-sm font-medium text-[hsl(var(--foreground))]">{t('config.baseUrl')}</Label>
+                                <FieldHelpIcon content={t('config.baseUrlDesc')} />
+                              </div>
+                              <Input
+                                value={getAgent(agent).base_url || ''}
+                                onChange={(e) => updateAgent(agent, 'base_url', e.target.value)}
+                                placeholder={API_BASE_URLS[agent]}
+                              />
+                            </div>
+                          ) : (
+                            <div className="grid gap-1.5">
+                              <Label className="text-sm font-medium text-[hsl(var(--foreground))]">{t('config.cliPath')}</Label>
+                              <Input
+                                value={getAgent(agent).cli_path || ''}
+                                onChange={(e) => updateAgent(agent, 'cli_path', e.target.value)}
+                                placeholder={agent === 'cursor' ? 'cursor-agent' : agent}
+                              />
+                            </div>
+                          )}
                           {agent !== 'qoder' && (
                             <div className="grid gap-1.5">
                               <Label className="text-sm font-medium text-[hsl(var(--foreground))]">{t('config.defaultModel')}</Label>
                               <Input
                                 value={getAgent(agent).default_model || ''}
                                 onChange={(e) => updateAgent(agent, 'default_model', e.target.value)}
-                                placeholder={agent === 'cursor' ? 'composer-1' : ''}
+                                placeholder={agent === 'cursor' ? 'composer-1' : API_DEFAULT_MODELS[agent] || ''}
                               />
                             </div>
                           )}
@@ -695,7 +743,7 @@ export default function Settings() {
                         <div className={agent === 'qoder' ? 'grid grid-cols-1 gap-3' : 'grid grid-cols-2 gap-3'}>
                           <div className="grid gap-1.5">
                             <div className="flex items-center gap-1.5">
-                              <Label className="text-sm font-medium text-[hsl(var(--foreground))]">{t('config.apiKey')}<span className="text-red-500 ml-0.5">*</span></Label>
+                              <Label className="text-sm font-medium text-[hsl(var(--foreground))]">{t('config.apiKey')}{agent !== 'ollama' && <span className="text-red-500 ml-0.5">*</span>}</Label>
                               <FieldHelpIcon content={t('config.apiKeyDesc')} />
                             </div>
                             <SecretInput
@@ -728,14 +776,29 @@ export default function Settings() {
                               placeholder="600"
                             />
                           </div>
-                          <div className="grid gap-1.5">
-                            <Label className="text-sm font-medium text-[hsl(var(--foreground))]">{t('config.extraArgs')}</Label>
-                            <Input
-                              value={getAgent(agent).extra_args || ''}
-                              onChange={(e) => updateAgent(agent, 'extra_args', e.target.value)}
-                              placeholder="--debug --verbose"
-                            />
-                          </div>
+                          {agent === 'anthropic' || agent === 'ollama' ? (
+                            <div className="grid gap-1.5">
+                              <div className="flex items-center gap-1.5">
+                                <Label className="text-sm font-medium text-[hsl(var(--foreground))]">{t('config.thinkingBudget')}</Label>
+                                <FieldHelpIcon content={t('config.thinkingBudgetDesc')} />
+                              </div>
+                              <Input
+                                type="number"
+                                value={getAgent(agent).thinking_budget || 0}
+                                onChange={(e) => updateAgent(agent, 'thinking_budget', parseInt(e.target.value) || 0)}
+                                placeholder="0"
+                              />
+                            </div>
+                          ) : (
+                            <div className="grid gap-1.5">
+                              <Label className="text-sm font-medium text-[hsl(var(--foreground))]">{t('config.extraArgs')}</Label>
+                              <Input
+                                value={getAgent(agent).extra_args || ''}
+                                onChange={(e) => updateAgent(agent, 'extra_args', e.target.value)}
+                                placeholder="--debug --verbose"
+                              />
+                            </div>
+                          )}
                         </div>
                       </>
                     )}
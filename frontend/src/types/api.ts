@@ -57,6 +57,20 @@ export interface QueueStats {
   total_running: number
   repo_count: number
   repos: Record<string, RepoStats>
+  agent_calls?: AgentPoolStatus[]
+}
+
+// Running and queued calls of an agent in the agent pool
+export interface AgentPoolStatus {
+  agent: string
+  active: number
+  queued: number
+  max_concurrent?: number
+  requests_per_minute?: number
+  key_selection: 'round_robin' | 'least_loaded'
+  models?: { model: string; active: number; limit?: number }[]
+  keys: { key: string; active: number; total_calls: number }[]
+  queued_calls?: { review_id?: string; rule_id?: string; model?: string; queued_at: string }[]
 }
 
 export interface RepoStats {
@@ -73,6 +87,23 @@ export interface ServerStatus {
   started_at: string    // Server start time in RFC3339 format
   go_version: string    // Go runtime version
   memory_usage: number  // Memory usage in bytes (heap alloc)
+  agents: AgentBreakerStatus[] // Circuit breaker state per agent
+}
+
+// Agent circuit breaker status
+export type BreakerState = 'closed' | 'open' | 'half_open'
+
+export interface AgentBreakerStatus {
+  agent: string
+  state: BreakerState
+  consecutive_failures: number
+  error_rate: number       // Failure ratio of recent calls (0-1)
+  recent_requests: number  // Number of calls in the error rate window
+  total_requests: number
+  total_failures: number
+  opened_at?: string
+  last_failure_at?: string
+  last_error?: string
 }
 
 // Task Log types
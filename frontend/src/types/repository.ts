@@ -2,12 +2,22 @@
  * Repository configuration types
  */
 
+// Trust policy for the repository's own .verust-review.yaml
+export type InRepoConfigPolicy = 'ignore' | 'base_branch' | 'merge'
+
+// Action taken once a repository's monthly budget is exceeded
+export type BudgetAction = 'block' | 'downgrade'
+
 // Repository with its review configuration
 export interface RepositoryConfigItem {
   id: number
   repo_url: string
   review_file: string
   description?: string
+  in_repo_config_policy: InRepoConfigPolicy
+  monthly_budget: number // USD, 0 = no budget
+  budget_action?: BudgetAction
+  budget_model?: string
   review_count: number
   last_review_at?: string
   created_at?: string
@@ -27,12 +37,20 @@ export interface CreateRepositoryConfigRequest {
   repo_url: string
   review_file?: string
   description?: string
+  in_repo_config_policy?: InRepoConfigPolicy
+  monthly_budget?: number
+  budget_action?: BudgetAction
+  budget_model?: string
 }
 
 // Update repository config request
 export interface UpdateRepositoryConfigRequest {
   review_file?: string
   description?: string
+  in_repo_config_policy?: InRepoConfigPolicy
+  monthly_budget?: number
+  budget_action?: BudgetAction
+  budget_model?: string
 }
 
 
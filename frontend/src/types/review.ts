@@ -2,6 +2,17 @@
  * Review related types
  */
 
+// Token usage and estimated cost of agent executions
+export interface TokenUsage {
+  prompt_tokens: number     // prompt tokens, including cached tokens
+  completion_tokens: number
+  cached_tokens: number     // prompt tokens read from the provider's prompt cache
+  cost: number              // estimated cost in USD
+}
+
+// Outcome of validating structured agent output against the rule's JSON Schema
+export type OutputValidationStatus = 'valid' | 'repaired' | 'salvaged' | 'invalid'
+
 // Review status enum
 export type ReviewStatus = 'pending' | 'running' | 'completed' | 'failed' | 'cancelled'
 
@@ -11,8 +22,11 @@ export type RuleStatus = 'pending' | 'running' | 'completed' | 'failed' | 'skipp
 // Run status enum
 export type RunStatus = 'pending' | 'running' | 'completed' | 'failed'
 
+// Source of the effective review configuration
+export type ConfigSource = 'review_file' | 'server' | 'base_branch' | 'merged'
+
 // Review model
-export interface Review {
+export interface Review extends TokenUsage {
   id: string // UUID
   created_at: string
   updated_at: string
@@ -37,12 +51,15 @@ export interface Review {
   lines_added?: number
   lines_deleted?: number
   files_changed?: number
+  // Effective review configuration
+  config_source?: ConfigSource
+  config_rejections?: string[]
   error_message?: string
   rules?: ReviewRule[]
 }
 
 // Review rule model
-export interface ReviewRule {
+export interface ReviewRule extends TokenUsage {
   id: number
   created_at: string
   updated_at: string
@@ -50,23 +67,34 @@ export interface ReviewRule {
   rule_index: number
   rule_id: string
   rule_config?: Record<string, unknown>
+  model?: string // model reported by the agent
   status: RuleStatus
   multi_run_enabled: boolean
   multi_run_runs: number
   current_run_index: number
+  chunk_count: number // 0 when the rule is not chunked
+  current_chunk_index: number
   findings_count: number
+  suppressed_count: number // findings suppressed by verust:ignore directives or the baseline
+  output_validation?: OutputValidationStatus
+  output_repairs: number // repair prompts sent for invalid output
+  output_dropped: number // invalid findings dropped when salvaging
+  output_errors?: string // validation errors, one per line
+  cached: boolean // agent result served from the response cache
   prompt?: string // Rendered prompt text (markdown format)
   started_at?: string
   completed_at?: string
   duration?: number
   error_message?: string
+  skip_reason?: string // why the rule was skipped (e.g. its when clause did not match)
   retry_count: number // number of retry attempts for this rule
   runs?: ReviewRuleRun[]
+  chunks?: ReviewRuleChunk[]
   results?: ReviewResult[]
 }
 
 // Review rule run model
-export interface ReviewRuleRun {
+export interface ReviewRuleRun extends TokenUsage {
   id: number
   created_at: string
   updated_at: string
@@ -82,6 +110,25 @@ export interface ReviewRuleRun {
   error_message?: string
 }
 
+// Review rule chunk model (one batch of changed files in chunked execution)
+export interface ReviewRuleChunk {
+  id: number
+  created_at: string
+  updated_at: string
+  review_rule_id: number
+  chunk_index: number
+  files: string // newline-separated file paths
+  file_count: number
+  diff_lines: number
+  agent: string
+  status: RunStatus
+  findings_count: number
+  started_at?: string
+  completed_at?: string
+  duration?: number
+  error_message?: string
+}
+
 // Review result model
 export interface ReviewResult {
   id: number
@@ -99,3 +146,10 @@ export interface CreateReviewRequest {
   agent?: string
   pr_number?: number
 }
+
+// Generated baseline file (.verustcode/baseline.yaml)
+export interface GenerateBaselineResponse {
+  path: string
+  count: number
+  content: string
+}
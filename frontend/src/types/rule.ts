@@ -6,11 +6,22 @@
 export interface AgentConfig {
   type?: string  // e.g., "cursor", "gemini"
   model?: string // e.g., "sonnet-4.5"
+  fallback?: string[] // e.g., ["gemini", "qoder"], used when the primary agent is unavailable
+}
+
+// Custom review area declared in a review file or via the admin API
+export interface AreaDefinition {
+  id: string // kebab-case, e.g. "pci-compliance"
+  group?: string // defaults to "custom"
+  description: string
+  builtin?: boolean // set by the admin API for built-in areas
 }
 
 // Root configuration
 export interface ReviewRulesConfig {
   version?: string
+  include?: string[] // review files in config/reviews or versioned git URLs (<repo-url>//<path>@<version>)
+  areas?: AreaDefinition[] // custom review areas usable by rules in this file
   rule_base?: RuleBaseConfig
   rules: ReviewRuleConfig[]
 }
@@ -19,20 +30,40 @@ export interface ReviewRulesConfig {
 export interface RuleBaseConfig {
   agent?: AgentConfig
   constraints?: ConstraintsConfig
+  policy?: PolicyStatementConfig[]
   output?: OutputConfig
 }
 
 // Individual rule configuration
 export interface ReviewRuleConfig {
   id: string
+  extends?: string // <file>#<rule-id>, e.g. "shared/security.yaml#security"
   role: string
   description?: string
   agent?: AgentConfig
   reference_docs?: string[]
   goals: GoalsConfig
   constraints?: ConstraintsConfig
+  policy?: PolicyStatementConfig[]
   output?: OutputConfig
   multi_run?: MultiRunConfig
+  chunking?: ChunkingConfig
+  when?: WhenConfig
+  context?: ContextProviderConfig[]
+  prompt_template?: PromptTemplateConfig
+  locked?: boolean
+  cache?: boolean // false keeps the rule out of the response cache
+}
+
+// Policy statement, applied in order to the findings matching `when` (all findings if empty)
+// Exactly one action (set_severity, adjust_severity, drop, max_per_file) per statement
+export interface PolicyStatementConfig {
+  name?: string
+  when?: string // expression over finding fields, e.g. 'file.glob("**/*_test.go")'
+  set_severity?: string
+  adjust_severity?: number // levels, e.g. -1 to downgrade by one level
+  drop?: boolean
+  max_per_file?: number
 }
 
 // Multi-run configuration
@@ -43,6 +74,65 @@ export interface MultiRunConfig {
   merge_model?: string
 }
 
+// Chunking configuration
+// Chunking is enabled when max_files or max_diff_lines is set
+export interface ChunkingConfig {
+  max_files?: number
+  max_diff_lines?: number
+  group_by_directory?: boolean
+  merge_model?: string
+}
+
+// Context provider configuration
+// Provider output is truncated to max_tokens and added to the prompt context
+export type ContextProviderType = 'file' | 'command' | 'diff' | 'git_log'
+
+export interface ContextProviderConfig {
+  type: ContextProviderType
+  name?: string
+  max_tokens?: number // default 2000
+  paths?: string[] // file: glob patterns
+  max_files?: number // file: default 10
+  max_file_size?: number // file: bytes per file, default 32KB
+  command?: string // command: must be allowed by the context_commands review setting
+  timeout?: number // command: seconds, default 60
+  context_lines?: number // diff: default 3
+  max_commits?: number // git_log: default 10
+}
+
+// Prompt template configuration (Go text/template syntax)
+// Defining blocks (role, goals, constraints, context) overrides only those blocks of the built-in prompt
+export interface PromptTemplateConfig {
+  file?: string // template file in config/reviews
+  inline?: string
+}
+
+// When configuration
+// All configured conditions must match for the rule to run; otherwise it is skipped
+export interface WhenConfig {
+  paths?: PathsCondition
+  languages?: string[]
+  branches?: string[]
+  labels?: string[]
+  authors?: string[]
+  exclude_authors?: string[]
+  diff_size?: DiffSizeCondition
+}
+
+// Changed file path condition (glob patterns)
+export interface PathsCondition {
+  include?: string[]
+  exclude?: string[]
+}
+
+// Change set size condition (0 = no limit)
+export interface DiffSizeCondition {
+  min_lines?: number
+  max_lines?: number
+  min_files?: number
+  max_files?: number
+}
+
 // Goals configuration
 export interface GoalsConfig {
   areas?: string[]
@@ -48,6 +48,20 @@ export interface WeeklyRevision {
   layer_3_label: string   // label for layer 3 (e.g., "5+次")
 }
 
+// Aggregated token usage and estimated cost
+export interface UsageStats {
+  prompt_tokens: number
+  completion_tokens: number
+  cached_tokens: number
+  cost: number         // estimated cost in USD
+  total_tokens: number // prompt + completion tokens
+}
+
+// Weekly token usage statistics
+export interface WeeklyUsage extends UsageStats {
+  week: string // ISO week format: "2024-W01"
+}
+
 // Issue severity statistics
 export interface IssueSeverityStats {
   severity: string // critical, high, medium, low, info
@@ -69,6 +83,8 @@ export interface RepoStats {
   revision_stats: WeeklyRevision[]
   issue_severity_stats: IssueSeverityStats[]   // issues grouped by severity
   issue_category_stats: IssueCategoryStats[]   // issues grouped by category
+  usage_stats: WeeklyUsage[]                   // token usage and cost per week
+  total_usage: UsageStats                      // token usage and cost in the time range
 }
 
 // Time range options
@@ -8,9 +8,12 @@ package agents
 
 import (
 	// Import all agent implementations to trigger their init() registration
+	_ "github.com/verustcode/verustcode/internal/agent/anthropic"
 	_ "github.com/verustcode/verustcode/internal/agent/cursor"
 	_ "github.com/verustcode/verustcode/internal/agent/gemini"
 	_ "github.com/verustcode/verustcode/internal/agent/mock"
+	_ "github.com/verustcode/verustcode/internal/agent/ollama"
+	_ "github.com/verustcode/verustcode/internal/agent/openai"
 	_ "github.com/verustcode/verustcode/internal/agent/qoder"
 	// Add new agent imports here when implementing new agents
 )
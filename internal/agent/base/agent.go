@@ -6,6 +6,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/verustcode/verustcode/internal/llm"
 	"github.com/verustcode/verustcode/internal/store"
 )
 
@@ -32,6 +33,13 @@ type ReviewRequest struct {
 
 	// Model is an optional model override for this request
 	Model string `json:"model,omitempty"`
+
+	// APIKey overrides the agent's configured API key (set by the agent pool, never serialized)
+	APIKey string `json:"-"`
+
+	// SessionID continues the conversation of an earlier result (ReviewResult.SessionID).
+	// Empty starts a new conversation.
+	SessionID string `json:"session_id,omitempty"`
 }
 
 // ReviewResult represents the raw AI response for a code review
@@ -58,6 +66,13 @@ type ReviewResult struct {
 	AgentVersion string `json:"agent_version,omitempty"`
 	ModelName    string `json:"model_name,omitempty"` // Model name used for this review
 
+	// Usage is the token usage reported by the backend (nil if not reported)
+	Usage *llm.Usage `json:"usage,omitempty"`
+
+	// SessionID identifies the conversation, so follow-up prompts can continue it
+	// (empty if the backend keeps no session)
+	SessionID string `json:"session_id,omitempty"`
+
 	// Error handling
 	Success bool   `json:"success"`
 	Error   string `json:"error,omitempty"`
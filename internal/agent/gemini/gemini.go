@@ -114,6 +114,7 @@ func (a *GeminiAgent) loadConfigFromDB() {
 	if agentCfg.CLIPath != "" {
 		clientConfig.CLIPath = agentCfg.CLIPath
 	}
+	clientConfig.Sandbox = agentCfg.Sandbox
 	if agentCfg.Timeout > 0 {
 		a.timeout = time.Duration(agentCfg.Timeout) * time.Second
 	}
@@ -134,7 +135,7 @@ func (a *GeminiAgent) ExecuteWithPrompt(ctx context.Context, req *base.ReviewReq
 	)
 
 	// Execute using LLM client
-	output, modelName, err := a.executeWithClient(ctx, req, prompt)
+	resp, err := a.executeWithClient(ctx, req, prompt)
 	if err != nil {
 		logger.Error("Gemini agent execution failed",
 			zap.Error(err),
@@ -149,8 +150,10 @@ func (a *GeminiAgent) ExecuteWithPrompt(ctx context.Context, req *base.ReviewReq
 
 	// DSL mode uses markdown output, no JSON parsing needed
 	// Use raw output directly as text
-	result.Text = output
-	result.ModelName = modelName
+	result.Text = resp.Content
+	result.ModelName = resp.Model
+	result.Usage = resp.Usage
+	result.SessionID = resp.SessionID
 
 	result.CompletedAt = time.Now()
 	result.Duration = result.CompletedAt.Sub(result.StartedAt)
@@ -164,8 +167,8 @@ func (a *GeminiAgent) ExecuteWithPrompt(ctx context.Context, req *base.ReviewReq
 }
 
 // executeWithClient executes the prompt using the LLM client
-// Returns: (output content, model name, error)
-func (a *GeminiAgent) executeWithClient(ctx context.Context, req *base.ReviewRequest, prompt string) (string, string, error) {
+// Returns the LLM response, including the model name and token usage
+func (a *GeminiAgent) executeWithClient(ctx context.Context, req *base.ReviewRequest, prompt string) (*llm.Response, error) {
 	// Load latest configuration from database before execution
 	a.loadConfigFromDB()
 
@@ -187,15 +190,26 @@ func (a *GeminiAgent) executeWithClient(ctx context.Context, req *base.ReviewReq
 		llmReq = llmReq.WithModel(req.Model)
 	}
 
-	// Execute (LLM client will use DefaultModel if request model is empty)
-	resp, err := a.client.Execute(ctx, llmReq)
+	// Use the API key selected by the agent pool
+	if req.APIKey != "" {
+		llmReq = llmReq.WithAPIKey(req.APIKey)
+	}
+
+	// Resume an earlier conversation; the CLI creates a new session otherwise
+	if req.SessionID != "" {
+		llmReq = llmReq.WithSessionID(req.SessionID)
+	}
+
+	// Execute (LLM client will use DefaultModel if request model is empty).
+	// The stream-json output is used without callback: only its result event reports token usage.
+	resp, err := a.client.ExecuteStream(ctx, llmReq, nil)
 	if err != nil {
-		return "", "", &base.AgentError{
+		return nil, &base.AgentError{
 			Agent:   AgentName,
 			Message: "LLM client execution failed",
 			Err:     err,
 		}
 	}
 
-	return resp.Content, resp.Model, nil
+	return resp, nil
 }
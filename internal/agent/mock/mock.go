@@ -59,6 +59,16 @@ func NewAgent() (base.Agent, error) {
 	}, nil
 }
 
+// NewAgentWithClient creates a Mock agent that executes prompts with the given LLM client,
+// e.g. a mock client answering with canned responses
+func NewAgentWithClient(client llm.Client) *MockAgent {
+	return &MockAgent{
+		client:  client,
+		timeout: llm.DefaultTimeout,
+		version: Version,
+	}
+}
+
 // Name returns the agent identifier
 func (a *MockAgent) Name() string {
 	return AgentName
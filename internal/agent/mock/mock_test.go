@@ -8,6 +8,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/llm"
+	llmmock "github.com/verustcode/verustcode/internal/llm/mock"
 	"github.com/verustcode/verustcode/internal/store"
 	"github.com/verustcode/verustcode/pkg/logger"
 )
@@ -119,3 +121,21 @@ func TestMockAgent_ExecuteWithPrompt_Error(t *testing.T) {
 	require.NotNil(t, result)
 	assert.True(t, result.Success)
 }
+
+func TestNewAgentWithClient(t *testing.T) {
+	client := llmmock.NewClientWithResponder(nil, func(req *llm.Request) (string, error) {
+		return "canned review of " + req.GetMetadata("rule_id"), nil
+	})
+	agent := NewAgentWithClient(client)
+	assert.Equal(t, AgentName, agent.Name())
+
+	req := &base.ReviewRequest{
+		RequestID: "test-request-004",
+		RepoPath:  "/tmp/test-repo",
+		RuleID:    "security-rule",
+	}
+
+	result, err := agent.ExecuteWithPrompt(context.Background(), req, "Review code")
+	require.NoError(t, err)
+	assert.Equal(t, "canned review of security-rule", result.Text)
+}
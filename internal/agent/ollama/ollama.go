@@ -0,0 +1,235 @@
+// Package ollama implements the Agent interface for a local Ollama endpoint,
+// for air-gapped deployments. Like the OpenAI-compatible agent, the model explores
+// the repository through the read-only tools of the tools package.
+package ollama
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/agent/tools"
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/llm"
+	"github.com/verustcode/verustcode/internal/store"
+
+	// Import Ollama client to register it
+	_ "github.com/verustcode/verustcode/internal/llm/ollama"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// AgentName is the identifier for the Ollama agent
+const AgentName = "ollama"
+
+// Version is the current version of the Ollama agent
+const Version = "1.0.0"
+
+// DefaultModel is the model used when none is configured
+const DefaultModel = "qwen2.5-coder"
+
+func init() {
+	// Register Ollama agent factory
+	base.Register(AgentName, NewAgent)
+}
+
+// OllamaAgent implements the Agent interface for Ollama endpoints
+// It uses the llm.Client interface for actual execution
+type OllamaAgent struct {
+	client  llm.Client
+	store   store.Store // Database store for reading runtime configuration
+	timeout time.Duration
+	version string
+}
+
+// NewAgent creates a new Ollama agent instance
+func NewAgent() (base.Agent, error) {
+	// Create LLM client configuration
+	config := llm.NewClientConfig(AgentName).WithDefaultModel(DefaultModel)
+
+	// Create the LLM client
+	client, err := llm.Create(AgentName, config)
+	if err != nil {
+		return nil, &base.AgentError{
+			Agent:   AgentName,
+			Message: "failed to create LLM client",
+			Err:     err,
+		}
+	}
+
+	return &OllamaAgent{
+		client:  client,
+		timeout: llm.DefaultTimeout,
+		version: Version,
+	}, nil
+}
+
+// Name returns the agent identifier
+func (a *OllamaAgent) Name() string {
+	return AgentName
+}
+
+// Version returns the agent version
+func (a *OllamaAgent) Version() string {
+	return a.version
+}
+
+// Available returns true: the local endpoint is only checked when a review runs
+func (a *OllamaAgent) Available() bool {
+	return a.client.Available()
+}
+
+// SetStore sets the database store for reading runtime configuration
+func (a *OllamaAgent) SetStore(s store.Store) {
+	a.store = s
+}
+
+// loadConfigFromDB loads agent configuration from database and applies to LLM client
+func (a *OllamaAgent) loadConfigFromDB() {
+	if a.store == nil {
+		return
+	}
+
+	agentCfg, err := config.GetAgentConfig(a.store, AgentName)
+	if err != nil {
+		logger.Warn("Failed to load agent config from database",
+			zap.String("agent", AgentName),
+			zap.Error(err),
+		)
+		return
+	}
+	if agentCfg == nil {
+		return
+	}
+
+	clientConfig := a.client.GetConfig()
+	if clientConfig == nil {
+		return
+	}
+
+	// Apply configuration from database
+	if agentCfg.DefaultModel != "" {
+		clientConfig.DefaultModel = agentCfg.DefaultModel
+	} else {
+		// Use default model for ollama agent when not configured
+		clientConfig.DefaultModel = DefaultModel
+	}
+	if agentCfg.APIKey != "" {
+		clientConfig.APIKey = agentCfg.APIKey
+	}
+	if agentCfg.BaseURL != "" {
+		clientConfig.BaseURL = agentCfg.BaseURL
+	}
+	clientConfig.ThinkingBudget = agentCfg.ThinkingBudget
+	if agentCfg.Timeout > 0 {
+		a.timeout = time.Duration(agentCfg.Timeout) * time.Second
+	}
+}
+
+// ExecuteWithPrompt performs code review using a custom prompt (DSL mode)
+// In DSL mode, the prompt is rendered from templates and expects markdown output,
+// so we don't parse the output as JSON - just use it directly as summary.
+func (a *OllamaAgent) ExecuteWithPrompt(ctx context.Context, req *base.ReviewRequest, prompt string) (*base.ReviewResult, error) {
+	startTime := time.Now()
+	result := base.NewResult(req.RequestID, a.Name())
+	result.StartedAt = startTime
+	result.AgentVersion = a.version
+
+	logger.Info("Starting Ollama agent review with custom prompt",
+		zap.String("review_id", req.ReviewID),
+		zap.String("request_id", req.RequestID),
+		zap.String("repo_path", req.RepoPath),
+	)
+
+	// Execute using LLM client
+	resp, err := a.executeWithClient(ctx, req, prompt)
+	if err != nil {
+		logger.Error("Ollama agent execution failed",
+			zap.Error(err),
+			zap.String("request_id", req.RequestID),
+		)
+		result.Success = false
+		result.Error = err.Error()
+		result.CompletedAt = time.Now()
+		result.Duration = result.CompletedAt.Sub(result.StartedAt)
+		return result, err
+	}
+
+	// DSL mode uses markdown output, no JSON parsing needed
+	// Use raw output directly as text
+	result.Text = resp.Content
+	result.ModelName = resp.Model
+	result.Usage = resp.Usage
+	result.SessionID = resp.SessionID
+
+	result.CompletedAt = time.Now()
+	result.Duration = result.CompletedAt.Sub(result.StartedAt)
+
+	logger.Info("Ollama agent review completed",
+		zap.String("request_id", req.RequestID),
+		zap.Duration("duration", result.Duration),
+	)
+
+	return result, nil
+}
+
+// executeWithClient executes the prompt using the LLM client
+// Returns the LLM response, including the model name and token usage
+func (a *OllamaAgent) executeWithClient(ctx context.Context, req *base.ReviewRequest, prompt string) (*llm.Response, error) {
+	// Load latest configuration from database before execution
+	a.loadConfigFromDB()
+
+	// Build request with metadata including rule_id and review_id
+	metadata := make(map[string]string)
+	if req.RuleID != "" {
+		metadata["rule_id"] = req.RuleID
+	}
+	if req.ReviewID != "" {
+		metadata["review_id"] = req.ReviewID
+	}
+
+	llmReq := llm.NewRequest(prompt).
+		WithWorkDir(req.RepoPath).
+		WithTools(tools.Workspace(req.RepoPath)).
+		WithOptions(&llm.RequestOptions{
+			Timeout:  a.timeout,
+			Metadata: metadata,
+		})
+
+	// Set model if specified in request (DSL override)
+	if req.Model != "" {
+		llmReq = llmReq.WithModel(req.Model)
+	}
+
+	// Use the API key selected by the agent pool
+	if req.APIKey != "" {
+		llmReq = llmReq.WithAPIKey(req.APIKey)
+	}
+
+	// Keep the conversation in a session, so follow-up prompts (e.g. output repairs) can continue it
+	sessionID := req.SessionID
+	if sessionID == "" {
+		var err error
+		if sessionID, err = a.client.CreateSession(ctx); err != nil {
+			return nil, &base.AgentError{
+				Agent:   AgentName,
+				Message: "failed to create session",
+				Err:     err,
+			}
+		}
+	}
+	llmReq = llmReq.WithSessionID(sessionID)
+
+	// Execute (LLM client will use DefaultModel if request model is empty)
+	resp, err := a.client.Execute(ctx, llmReq)
+	if err != nil {
+		return nil, &base.AgentError{
+			Agent:   AgentName,
+			Message: "LLM client execution failed",
+			Err:     err,
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,105 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+)
+
+func TestNewAgent(t *testing.T) {
+	agent, err := NewAgent()
+	if err != nil {
+		t.Fatalf("Failed to create Ollama agent: %v", err)
+	}
+
+	if agent.Name() != AgentName {
+		t.Errorf("Expected agent name %s, got %s", AgentName, agent.Name())
+	}
+
+	if agent.Version() != Version {
+		t.Errorf("Expected version %s, got %s", Version, agent.Version())
+	}
+
+	// SetStore with nil should not panic
+	agent.SetStore(nil)
+}
+
+func TestAgentRegistration(t *testing.T) {
+	agent, err := base.Create(AgentName)
+	if err != nil {
+		t.Fatalf("Failed to create Ollama agent via factory: %v", err)
+	}
+
+	if agent.Name() != AgentName {
+		t.Errorf("Expected agent name %s, got %s", AgentName, agent.Name())
+	}
+}
+
+// TestExecuteWithPrompt runs a review against an httptest stand-in that reads a file through the tools
+func TestExecuteWithPrompt(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var body struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Role     string `json:"role"`
+				Content  string `json:"content"`
+				ToolName string `json:"tool_name"`
+			} `json:"messages"`
+			Tools []json.RawMessage `json:"tools"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		if requests == 1 {
+			if len(body.Tools) != 4 || body.Model != DefaultModel {
+				t.Errorf("first request has %d tools and model %s, want 4 repository tools and %s", len(body.Tools), body.Model, DefaultModel)
+			}
+			fmt.Fprint(w, `{"message":{"role":"assistant","content":"","tool_calls":[
+				{"function":{"name":"read_file","arguments":{"path":"main.go"}}}]},"done":true}`)
+			return
+		}
+		toolResult := body.Messages[len(body.Messages)-1]
+		if toolResult.Role != "tool" || toolResult.ToolName != "read_file" || !strings.Contains(toolResult.Content, "package main") {
+			t.Errorf("tool result = %+v, want the file content", toolResult)
+		}
+		fmt.Fprint(w, `{"message":{"role":"assistant","content":"## Summary\nNo issues."},"done":true}`)
+	}))
+	defer server.Close()
+
+	agent, err := NewAgent()
+	if err != nil {
+		t.Fatalf("Failed to create Ollama agent: %v", err)
+	}
+	agent.(*OllamaAgent).client.GetConfig().BaseURL = server.URL
+
+	result, err := agent.ExecuteWithPrompt(context.Background(), &base.ReviewRequest{
+		RequestID: "req-1",
+		RepoPath:  repo,
+	}, "Review the repository")
+	if err != nil {
+		t.Fatalf("ExecuteWithPrompt() error = %v", err)
+	}
+	if result.Text != "## Summary\nNo issues." || result.ModelName != DefaultModel {
+		t.Errorf("result = %+v", result)
+	}
+	if requests != 2 {
+		t.Errorf("got %d requests, want 2", requests)
+	}
+}
@@ -0,0 +1,67 @@
+package pool
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/store"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// pooledAgent is an agent whose executions go through the pool
+type pooledAgent struct {
+	base.Agent
+	pool  *Pool
+	store store.Store
+}
+
+// Wrap returns agent with its executions limited by the pool.
+// The limits and API keys are read from the agents settings before each execution.
+func (p *Pool) Wrap(agent base.Agent) base.Agent {
+	return &pooledAgent{Agent: agent, pool: p}
+}
+
+// SetStore sets the store used to read the agent's limits and passes it to the agent
+func (a *pooledAgent) SetStore(s store.Store) {
+	a.store = s
+	a.Agent.SetStore(s)
+}
+
+// ExecuteWithPrompt waits for a slot in the pool and executes the prompt with the selected API key
+func (a *pooledAgent) ExecuteWithPrompt(ctx context.Context, req *base.ReviewRequest, prompt string) (*base.ReviewResult, error) {
+	model := req.Model
+	agentCfg, err := config.GetAgentConfig(a.store, a.Name())
+	if err != nil {
+		logger.Warn("Failed to load agent limits from database",
+			zap.String("agent", a.Name()),
+			zap.Error(err),
+		)
+	}
+	if agentCfg != nil {
+		a.pool.Configure(a.Name(), agentCfg.Limits, agentCfg.Keys())
+		if model == "" {
+			model = agentCfg.DefaultModel
+		}
+	}
+
+	lease, err := a.pool.Acquire(ctx, a.Name(), Call{ReviewID: req.ReviewID, RuleID: req.RuleID, Model: model})
+	if err != nil {
+		result := base.NewResult(req.RequestID, a.Name())
+		result.Success = false
+		result.Error = err.Error()
+		result.CompletedAt = time.Now()
+		return result, &base.AgentError{Agent: a.Name(), Message: "cancelled while waiting for the agent pool", Err: err}
+	}
+	defer lease.Release()
+
+	if key := lease.APIKey(); key != "" {
+		keyed := *req
+		keyed.APIKey = key
+		req = &keyed
+	}
+	return a.Agent.ExecuteWithPrompt(ctx, req, prompt)
+}
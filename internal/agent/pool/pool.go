@@ -0,0 +1,448 @@
+// Package pool implements the agent execution pool.
+// A review can fan out into many agent calls (multi-run, chunking, repair prompts),
+// so the pool bounds the number of concurrent calls per agent and per model,
+// rate-limits call starts per API key with token buckets, and spreads calls
+// across the API keys configured for an agent.
+package pool
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// Key selection strategies
+const (
+	// SelectionRoundRobin uses the API keys in turn (default)
+	SelectionRoundRobin = "round_robin"
+	// SelectionLeastLoaded uses the API key with the fewest running calls
+	SelectionLeastLoaded = "least_loaded"
+)
+
+// defaultPool is the pool shared by the review and report engines
+var defaultPool = New()
+
+// Default returns the process-wide agent pool
+func Default() *Pool {
+	return defaultPool
+}
+
+// Call describes an agent call waiting for or holding a slot, for logs and the queue status
+type Call struct {
+	ReviewID string
+	RuleID   string
+	Model    string
+}
+
+// Pool limits the agent calls of all agents
+type Pool struct {
+	mu     sync.Mutex
+	agents map[string]*agentState
+	now    func() time.Time
+}
+
+// New creates an empty pool; agents without configuration have no limits
+func New() *Pool {
+	return &Pool{
+		agents: make(map[string]*agentState),
+		now:    time.Now,
+	}
+}
+
+// agentState holds the limits and the running and waiting calls of one agent
+type agentState struct {
+	name    string
+	limits  config.AgentLimits
+	keys    []*keyState
+	next    int // next key index for round-robin selection
+	active  int
+	models  map[string]int // running calls per model
+	waiting []*waiter      // calls waiting for a slot, in arrival order
+	changed chan struct{}  // closed when slots may have become available
+}
+
+// keyState holds the token bucket and load of one API key
+type keyState struct {
+	key      string
+	active   int
+	calls    int64
+	tokens   float64
+	refilled time.Time
+}
+
+// waiter is a call waiting for a slot
+type waiter struct {
+	call     Call
+	queuedAt time.Time
+}
+
+// Lease is a slot held by a running call. Release must be called when the call finishes.
+type Lease struct {
+	pool     *Pool
+	agent    *agentState
+	key      *keyState
+	model    string
+	released bool
+}
+
+// APIKey returns the API key selected for the call ("" = the agent's own configuration)
+func (l *Lease) APIKey() string {
+	return l.key.key
+}
+
+// Release frees the slot of the call
+func (l *Lease) Release() {
+	l.pool.mu.Lock()
+	defer l.pool.mu.Unlock()
+	if l.released {
+		return
+	}
+	l.released = true
+	l.agent.active--
+	l.agent.models[l.model]--
+	if l.agent.models[l.model] <= 0 {
+		delete(l.agent.models, l.model)
+	}
+	l.key.active--
+	l.agent.broadcast()
+}
+
+// Configure updates the limits and API keys of an agent. Without keys, calls use the
+// agent's own configuration. Running calls of removed keys keep their slot until released.
+func (p *Pool) Configure(agent string, limits config.AgentLimits, keys []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	a := p.agent(agent)
+	a.limits = limits
+	if len(keys) == 0 {
+		keys = []string{""}
+	}
+
+	existing := make(map[string]*keyState, len(a.keys))
+	for _, k := range a.keys {
+		existing[k.key] = k
+	}
+	a.keys = make([]*keyState, 0, len(keys))
+	for _, key := range keys {
+		k, ok := existing[key]
+		if !ok {
+			k = &keyState{key: key}
+		}
+		a.keys = append(a.keys, k)
+	}
+	if a.next >= len(a.keys) {
+		a.next = 0
+	}
+	a.broadcast()
+}
+
+// Acquire waits until the call may start on agent and returns its lease.
+// Calls of the same agent and model start in arrival order.
+// Returns the context error if ctx is done before a slot is available.
+func (p *Pool) Acquire(ctx context.Context, agent string, call Call) (*Lease, error) {
+	p.mu.Lock()
+	a := p.agent(agent)
+	w := &waiter{call: call, queuedAt: p.now()}
+	a.waiting = append(a.waiting, w)
+
+	queued := false
+	for {
+		key, wait := a.tryAcquire(w, p.now())
+		if key != nil {
+			p.mu.Unlock()
+			if queued {
+				logger.Info("Agent call started after waiting in the pool",
+					zap.String("review_id", call.ReviewID),
+					zap.String("rule_id", call.RuleID),
+					zap.String("agent", agent),
+					zap.String("model", call.Model),
+					zap.Duration("waited", p.now().Sub(w.queuedAt)),
+				)
+			}
+			return &Lease{pool: p, agent: a, key: key, model: call.Model}, nil
+		}
+
+		if !queued {
+			queued = true
+			logger.Info("Agent call queued by the pool",
+				zap.String("review_id", call.ReviewID),
+				zap.String("rule_id", call.RuleID),
+				zap.String("agent", agent),
+				zap.String("model", call.Model),
+				zap.Int("active", a.active),
+				zap.Int("queued", len(a.waiting)),
+			)
+		}
+		changed := a.changed
+		p.mu.Unlock()
+
+		var timer *time.Timer
+		var expired <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			expired = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			p.mu.Lock()
+			a.remove(w)
+			a.broadcast()
+			p.mu.Unlock()
+			return nil, ctx.Err()
+		case <-changed:
+		case <-expired:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		p.mu.Lock()
+	}
+}
+
+// agent returns the state of an agent, creating it without limits if necessary. Caller must hold p.mu.
+func (p *Pool) agent(name string) *agentState {
+	a, ok := p.agents[name]
+	if !ok {
+		a = &agentState{
+			name:    name,
+			keys:    []*keyState{{}},
+			models:  make(map[string]int),
+			changed: make(chan struct{}),
+		}
+		p.agents[name] = a
+	}
+	return a
+}
+
+// tryAcquire takes a slot for w if the limits allow it. Otherwise it returns a nil key and,
+// if only the rate limit is in the way, the time until a key has a token again.
+// Caller must hold the pool lock.
+func (a *agentState) tryAcquire(w *waiter, now time.Time) (*keyState, time.Duration) {
+	model := w.call.Model
+	for _, other := range a.waiting {
+		if other == w {
+			break
+		}
+		if other.call.Model == model {
+			return nil, 0
+		}
+	}
+	if a.limits.MaxConcurrent > 0 && a.active >= a.limits.MaxConcurrent {
+		return nil, 0
+	}
+	if limit := a.limits.ModelConcurrency[model]; limit > 0 && a.models[model] >= limit {
+		return nil, 0
+	}
+
+	key, wait := a.selectKey(now)
+	if key == nil {
+		return nil, wait
+	}
+
+	a.remove(w)
+	a.active++
+	a.models[model]++
+	key.active++
+	key.calls++
+	if a.limits.RequestsPerMinute > 0 {
+		key.tokens--
+	}
+	// Later calls of other models may have been waiting for this one to leave the queue
+	a.broadcast()
+	return key, 0
+}
+
+// selectKey returns a key with a token according to the selection strategy, or the time
+// until the first key gets a token. Caller must hold the pool lock.
+func (a *agentState) selectKey(now time.Time) (*keyState, time.Duration) {
+	var selected *keyState
+	var wait time.Duration
+	for i := range a.keys {
+		index := i
+		if a.limits.KeySelection != SelectionLeastLoaded {
+			index = (a.next + i) % len(a.keys)
+		}
+		k := a.keys[index]
+		if keyWait := k.refill(now, a.limits); keyWait > 0 {
+			if wait == 0 || keyWait < wait {
+				wait = keyWait
+			}
+			continue
+		}
+		if a.limits.KeySelection != SelectionLeastLoaded {
+			a.next = (index + 1) % len(a.keys)
+			return k, 0
+		}
+		if selected == nil || k.active < selected.active || (k.active == selected.active && k.calls < selected.calls) {
+			selected = k
+		}
+	}
+	if selected != nil {
+		return selected, 0
+	}
+	return nil, wait
+}
+
+// refill adds the tokens earned since the last refill and returns how long until the key
+// has a token (0 if it has one or calls are not rate-limited)
+func (k *keyState) refill(now time.Time, limits config.AgentLimits) time.Duration {
+	if limits.RequestsPerMinute <= 0 {
+		return 0
+	}
+	burst := float64(limits.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	perSecond := float64(limits.RequestsPerMinute) / 60
+	if k.refilled.IsZero() {
+		k.tokens = burst
+	} else {
+		k.tokens += now.Sub(k.refilled).Seconds() * perSecond
+		if k.tokens > burst {
+			k.tokens = burst
+		}
+	}
+	k.refilled = now
+	if k.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - k.tokens) / perSecond * float64(time.Second))
+}
+
+// remove removes w from the waiting calls. Caller must hold the pool lock.
+func (a *agentState) remove(w *waiter) {
+	for i, other := range a.waiting {
+		if other == w {
+			a.waiting = append(a.waiting[:i], a.waiting[i+1:]...)
+			return
+		}
+	}
+}
+
+// broadcast wakes up the waiting calls. Caller must hold the pool lock.
+func (a *agentState) broadcast() {
+	close(a.changed)
+	a.changed = make(chan struct{})
+}
+
+// Status is a snapshot of an agent's pool, used by the queue status API
+type Status struct {
+	Agent             string        `json:"agent"`
+	Active            int           `json:"active"`
+	Queued            int           `json:"queued"`
+	MaxConcurrent     int           `json:"max_concurrent,omitempty"`
+	RequestsPerMinute int           `json:"requests_per_minute,omitempty"`
+	KeySelection      string        `json:"key_selection"`
+	Models            []ModelStatus `json:"models,omitempty"`
+	Keys              []KeyStatus   `json:"keys"`
+	QueuedCalls       []QueuedCall  `json:"queued_calls,omitempty"`
+}
+
+// ModelStatus is the number of running calls of a model
+type ModelStatus struct {
+	Model  string `json:"model"`
+	Active int    `json:"active"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// KeyStatus is the load of an API key (the key itself is masked)
+type KeyStatus struct {
+	Key        string `json:"key"`
+	Active     int    `json:"active"`
+	TotalCalls int64  `json:"total_calls"`
+}
+
+// QueuedCall is a call waiting for a slot
+type QueuedCall struct {
+	ReviewID string    `json:"review_id,omitempty"`
+	RuleID   string    `json:"rule_id,omitempty"`
+	Model    string    `json:"model,omitempty"`
+	QueuedAt time.Time `json:"queued_at"`
+}
+
+// Statuses returns snapshots of all agents that have been called, sorted by agent name
+func (p *Pool) Statuses() []Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	statuses := make([]Status, 0, len(p.agents))
+	for _, a := range p.agents {
+		selection := a.limits.KeySelection
+		if selection == "" {
+			selection = SelectionRoundRobin
+		}
+		status := Status{
+			Agent:             a.name,
+			Active:            a.active,
+			Queued:            len(a.waiting),
+			MaxConcurrent:     a.limits.MaxConcurrent,
+			RequestsPerMinute: a.limits.RequestsPerMinute,
+			KeySelection:      selection,
+			Keys:              make([]KeyStatus, 0, len(a.keys)),
+		}
+
+		models := make(map[string]bool)
+		for model := range a.models {
+			models[model] = true
+		}
+		for model, limit := range a.limits.ModelConcurrency {
+			if limit > 0 {
+				models[model] = true
+			}
+		}
+		for model := range models {
+			status.Models = append(status.Models, ModelStatus{
+				Model:  model,
+				Active: a.models[model],
+				Limit:  a.limits.ModelConcurrency[model],
+			})
+		}
+		sort.Slice(status.Models, func(i, j int) bool {
+			return status.Models[i].Model < status.Models[j].Model
+		})
+
+		for _, k := range a.keys {
+			status.Keys = append(status.Keys, KeyStatus{
+				Key:        maskKey(k.key),
+				Active:     k.active,
+				TotalCalls: k.calls,
+			})
+		}
+		for _, w := range a.waiting {
+			status.QueuedCalls = append(status.QueuedCalls, QueuedCall{
+				ReviewID: w.call.ReviewID,
+				RuleID:   w.call.RuleID,
+				Model:    w.call.Model,
+				QueuedAt: w.queuedAt,
+			})
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Agent < statuses[j].Agent
+	})
+	return statuses
+}
+
+// maskKey masks an API key for the status API, keeping the first and last 4 characters
+func maskKey(key string) string {
+	switch {
+	case key == "":
+		return "default"
+	case len(key) <= 8:
+		return "****"
+	default:
+		return key[:4] + "****" + key[len(key)-4:]
+	}
+}
@@ -0,0 +1,232 @@
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/config"
+)
+
+// acquireAsync acquires a lease in a goroutine and returns the channel receiving it
+func acquireAsync(ctx context.Context, p *Pool, agent string, call Call) <-chan *Lease {
+	leases := make(chan *Lease, 1)
+	go func() {
+		lease, err := p.Acquire(ctx, agent, call)
+		if err == nil {
+			leases <- lease
+		}
+		close(leases)
+	}()
+	return leases
+}
+
+// waitQueued waits until n calls of agent are queued
+func waitQueued(t *testing.T, p *Pool, agent string, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		for _, s := range p.Statuses() {
+			if s.Agent == agent {
+				return s.Queued == n
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond)
+}
+
+func TestPool_NoLimits(t *testing.T) {
+	p := New()
+	for i := 0; i < 10; i++ {
+		lease, err := p.Acquire(context.Background(), "cursor", Call{})
+		require.NoError(t, err)
+		assert.Empty(t, lease.APIKey(), "without keys the agent uses its own configuration")
+	}
+	assert.Equal(t, 10, p.Statuses()[0].Active)
+}
+
+func TestPool_MaxConcurrent(t *testing.T) {
+	p := New()
+	p.Configure("openai", config.AgentLimits{MaxConcurrent: 1}, nil)
+
+	first, err := p.Acquire(context.Background(), "openai", Call{ReviewID: "r1"})
+	require.NoError(t, err)
+
+	second := acquireAsync(context.Background(), p, "openai", Call{ReviewID: "r2", RuleID: "security"})
+	waitQueued(t, p, "openai", 1)
+
+	status := p.Statuses()[0]
+	assert.Equal(t, 1, status.Active)
+	require.Len(t, status.QueuedCalls, 1)
+	assert.Equal(t, "r2", status.QueuedCalls[0].ReviewID)
+	assert.Equal(t, "security", status.QueuedCalls[0].RuleID)
+
+	first.Release()
+	first.Release() // releasing twice frees one slot only
+	select {
+	case lease := <-second:
+		require.NotNil(t, lease)
+		lease.Release()
+	case <-time.After(time.Second):
+		t.Fatal("queued call did not start after release")
+	}
+	assert.Equal(t, 0, p.Statuses()[0].Active)
+}
+
+func TestPool_ModelConcurrency(t *testing.T) {
+	p := New()
+	p.Configure("openai", config.AgentLimits{ModelConcurrency: map[string]int{"gpt-4o": 1}}, nil)
+
+	lease, err := p.Acquire(context.Background(), "openai", Call{Model: "gpt-4o"})
+	require.NoError(t, err)
+	defer lease.Release()
+
+	blocked := acquireAsync(context.Background(), p, "openai", Call{Model: "gpt-4o"})
+	waitQueued(t, p, "openai", 1)
+
+	// Other models are not limited by the gpt-4o limit, nor by its queued call
+	other, err := p.Acquire(context.Background(), "openai", Call{Model: "gpt-4o-mini"})
+	require.NoError(t, err)
+	other.Release()
+
+	select {
+	case <-blocked:
+		t.Fatal("call started beyond the model limit")
+	default:
+	}
+
+	status := p.Statuses()[0]
+	require.Len(t, status.Models, 1)
+	assert.Equal(t, ModelStatus{Model: "gpt-4o", Active: 1, Limit: 1}, status.Models[0])
+}
+
+func TestPool_Cancel(t *testing.T) {
+	p := New()
+	p.Configure("gemini", config.AgentLimits{MaxConcurrent: 1}, nil)
+	lease, err := p.Acquire(context.Background(), "gemini", Call{})
+	require.NoError(t, err)
+	defer lease.Release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = p.Acquire(ctx, "gemini", Call{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, p.Statuses()[0].Queued)
+}
+
+func TestPool_RoundRobin(t *testing.T) {
+	p := New()
+	p.Configure("openai", config.AgentLimits{}, []string{"key-a", "key-b", "key-c"})
+
+	var keys []string
+	for i := 0; i < 4; i++ {
+		lease, err := p.Acquire(context.Background(), "openai", Call{})
+		require.NoError(t, err)
+		keys = append(keys, lease.APIKey())
+		lease.Release()
+	}
+	assert.Equal(t, []string{"key-a", "key-b", "key-c", "key-a"}, keys)
+}
+
+func TestPool_LeastLoaded(t *testing.T) {
+	p := New()
+	p.Configure("openai", config.AgentLimits{KeySelection: SelectionLeastLoaded}, []string{"key-a", "key-b"})
+
+	first, err := p.Acquire(context.Background(), "openai", Call{})
+	require.NoError(t, err)
+	second, err := p.Acquire(context.Background(), "openai", Call{})
+	require.NoError(t, err)
+	assert.Equal(t, "key-a", first.APIKey())
+	assert.Equal(t, "key-b", second.APIKey())
+
+	// key-b is free again but has served as many calls as key-a: both are equally loaded
+	second.Release()
+	third, err := p.Acquire(context.Background(), "openai", Call{})
+	require.NoError(t, err)
+	assert.Equal(t, "key-b", third.APIKey(), "key-a still runs a call")
+}
+
+func TestPool_RateLimit(t *testing.T) {
+	p := New()
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+	p.Configure("openai", config.AgentLimits{RequestsPerMinute: 60, Burst: 2}, []string{"key-a"})
+
+	p.mu.Lock()
+	a := p.agents["openai"]
+	for i := 0; i < 2; i++ {
+		key, wait := a.tryAcquire(&waiter{}, now)
+		require.NotNil(t, key, "burst allows two calls")
+		assert.Zero(t, wait)
+	}
+	key, wait := a.tryAcquire(&waiter{}, now)
+	assert.Nil(t, key)
+	assert.Equal(t, time.Second, wait, "one token per second")
+
+	key, _ = a.tryAcquire(&waiter{}, now.Add(time.Second))
+	assert.NotNil(t, key)
+	p.mu.Unlock()
+}
+
+func TestPool_RateLimitSpreadsKeys(t *testing.T) {
+	p := New()
+	p.Configure("openai", config.AgentLimits{RequestsPerMinute: 1, KeySelection: SelectionLeastLoaded}, []string{"key-a", "key-b"})
+
+	var keys []string
+	for i := 0; i < 2; i++ {
+		lease, err := p.Acquire(context.Background(), "openai", Call{})
+		require.NoError(t, err)
+		keys = append(keys, lease.APIKey())
+		lease.Release()
+	}
+	assert.ElementsMatch(t, []string{"key-a", "key-b"}, keys, "a key without tokens is skipped")
+}
+
+func TestPool_StatusMasksKeys(t *testing.T) {
+	p := New()
+	p.Configure("openai", config.AgentLimits{}, []string{"sk-1234567890abcdef", "short"})
+	p.Configure("cursor", config.AgentLimits{}, nil)
+
+	statuses := p.Statuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "cursor", statuses[0].Agent)
+	assert.Equal(t, "default", statuses[0].Keys[0].Key)
+	assert.Equal(t, SelectionRoundRobin, statuses[1].KeySelection)
+	assert.Equal(t, "sk-1****cdef", statuses[1].Keys[0].Key)
+	assert.Equal(t, "****", statuses[1].Keys[1].Key)
+}
+
+// recordingAgent records the requests it executes
+type recordingAgent struct {
+	base.Agent
+	requests []*base.ReviewRequest
+}
+
+func (a *recordingAgent) Name() string { return "recording" }
+
+func (a *recordingAgent) ExecuteWithPrompt(ctx context.Context, req *base.ReviewRequest, prompt string) (*base.ReviewResult, error) {
+	a.requests = append(a.requests, req)
+	return base.NewResult(req.RequestID, a.Name()), nil
+}
+
+func TestWrap_SetsAPIKey(t *testing.T) {
+	p := New()
+	p.Configure("recording", config.AgentLimits{}, []string{"key-a", "key-b"})
+	inner := &recordingAgent{}
+	agent := p.Wrap(inner)
+
+	req := &base.ReviewRequest{RequestID: "req-1", Model: "gpt-4o"}
+	for i := 0; i < 2; i++ {
+		_, err := agent.ExecuteWithPrompt(context.Background(), req, "prompt")
+		require.NoError(t, err)
+	}
+
+	require.Len(t, inner.requests, 2)
+	assert.Equal(t, "key-a", inner.requests[0].APIKey)
+	assert.Equal(t, "key-b", inner.requests[1].APIKey)
+	assert.Empty(t, req.APIKey, "the caller's request is not modified")
+	assert.Equal(t, 0, p.Statuses()[0].Active, "the slot is released after execution")
+}
@@ -0,0 +1,419 @@
+// Package tools provides the read-only repository tools given to API agents
+// through function calling (list files, read file, grep, git diff), so that the
+// model can explore the checkout the way the CLI agents do.
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/llm"
+)
+
+// Output limits keep tool results within the context window of the model
+const (
+	// MaxOutputBytes is the maximum size of a tool output
+	MaxOutputBytes = 64 * 1024
+
+	// MaxListedFiles is the maximum number of files listed by list_files
+	MaxListedFiles = 1000
+
+	// MaxGrepMatches is the maximum number of matches returned by grep
+	MaxGrepMatches = 200
+
+	// MaxReadLines is the maximum number of lines returned by read_file
+	MaxReadLines = 2000
+
+	// maxFileSize is the size above which read_file rejects files and grep skips them
+	maxFileSize = 1024 * 1024
+)
+
+// truncationNote is appended to outputs cut to MaxOutputBytes
+const truncationNote = "\n... (output truncated)"
+
+// Workspace returns the repository tools for the review workspace at repoPath,
+// or none if the workspace is not a directory (e.g., prompts without checkout).
+func Workspace(repoPath string) []*llm.Tool {
+	if repoPath == "" {
+		return nil
+	}
+	if info, err := os.Stat(repoPath); err != nil || !info.IsDir() {
+		return nil
+	}
+	return Repository(repoPath)
+}
+
+// Repository returns the read-only tools for the repository at repoPath.
+// Paths given by the model are relative to the repository root and may not leave it.
+func Repository(repoPath string) []*llm.Tool {
+	r := &repository{root: repoPath}
+	return []*llm.Tool{
+		{
+			Name:        "list_files",
+			Description: "List the files of the repository, optionally under a directory and filtered by a glob pattern such as \"**/*.go\".",
+			Parameters: objectSchema(map[string]interface{}{
+				"path":    stringProperty("Directory relative to the repository root (default: the root)"),
+				"pattern": stringProperty("Glob pattern matched against paths relative to the repository root"),
+			}),
+			Handler: r.listFiles,
+		},
+		{
+			Name:        "read_file",
+			Description: "Read a file of the repository. Lines are prefixed with their line number.",
+			Parameters: objectSchema(map[string]interface{}{
+				"path":       stringProperty("File path relative to the repository root"),
+				"start_line": integerProperty("First line to read (default: 1)"),
+				"end_line":   integerProperty("Last line to read (default: the end of the file)"),
+			}, "path"),
+			Handler: r.readFile,
+		},
+		{
+			Name:        "grep",
+			Description: "Search the repository files for a regular expression (RE2 syntax). Returns matching lines as path:line: text.",
+			Parameters: objectSchema(map[string]interface{}{
+				"pattern": stringProperty("Regular expression to search for"),
+				"path":    stringProperty("Glob pattern restricting the searched files, such as \"internal/**/*.go\""),
+			}, "pattern"),
+			Handler: r.grep,
+		},
+		{
+			Name:        "git_diff",
+			Description: "Show the git diff between two revisions (default: the last commit), optionally restricted to some paths.",
+			Parameters: objectSchema(map[string]interface{}{
+				"base":  stringProperty("Base revision (default: HEAD~1)"),
+				"head":  stringProperty("Head revision (default: HEAD)"),
+				"paths": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Paths to restrict the diff to"},
+			}),
+			Handler: r.gitDiff,
+		},
+	}
+}
+
+// repository implements the tools over a repository checkout
+type repository struct {
+	root string
+}
+
+// listFiles implements list_files
+func (r *repository) listFiles(ctx context.Context, arguments string) (string, error) {
+	var args struct {
+		Path    string `json:"path"`
+		Pattern string `json:"pattern"`
+	}
+	if err := decodeArguments(arguments, &args); err != nil {
+		return "", err
+	}
+
+	var files []string
+	total := 0
+	err := r.walk(ctx, args.Path, func(rel string, d fs.DirEntry) {
+		if args.Pattern != "" && !dsl.MatchGlob(args.Pattern, rel) {
+			return
+		}
+		total++
+		if len(files) < MaxListedFiles {
+			files = append(files, rel)
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sort.Strings(files)
+	out := strings.Join(files, "\n")
+	if total > len(files) {
+		out += fmt.Sprintf("\n(%d more files not shown)", total-len(files))
+	}
+	if total == 0 {
+		out = "(no files)"
+	}
+	return truncate(out), nil
+}
+
+// readFile implements read_file
+func (r *repository) readFile(ctx context.Context, arguments string) (string, error) {
+	var args struct {
+		Path      string `json:"path"`
+		StartLine int    `json:"start_line"`
+		EndLine   int    `json:"end_line"`
+	}
+	if err := decodeArguments(arguments, &args); err != nil {
+		return "", err
+	}
+	if args.Path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+
+	path, err := r.resolve(args.Path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read %s", args.Path)
+	}
+	if info.Size() > maxFileSize {
+		return "", fmt.Errorf("%s is too large (%d bytes, the limit is %d)", args.Path, info.Size(), maxFileSize)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read %s", args.Path)
+	}
+	if isBinary(data) {
+		return "", fmt.Errorf("%s is a binary file", args.Path)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	start, end := args.StartLine, args.EndLine
+	if start < 1 {
+		start = 1
+	}
+	if end < 1 || end > len(lines) {
+		end = len(lines)
+	}
+	if start > end {
+		return "", fmt.Errorf("invalid line range %d-%d (the file has %d lines)", args.StartLine, args.EndLine, len(lines))
+	}
+	if end-start+1 > MaxReadLines {
+		end = start + MaxReadLines - 1
+	}
+
+	var sb strings.Builder
+	for i := start; i <= end; i++ {
+		fmt.Fprintf(&sb, "%6d| %s\n", i, lines[i-1])
+	}
+	if end < len(lines) {
+		fmt.Fprintf(&sb, "(lines %d-%d of %d)\n", start, end, len(lines))
+	}
+	return truncate(sb.String()), nil
+}
+
+// grep implements grep
+func (r *repository) grep(ctx context.Context, arguments string) (string, error) {
+	var args struct {
+		Pattern string `json:"pattern"`
+		Path    string `json:"path"`
+	}
+	if err := decodeArguments(arguments, &args); err != nil {
+		return "", err
+	}
+	re, err := regexp.Compile(args.Pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid pattern: %v", err)
+	}
+
+	var matches []string
+	total := 0
+	err = r.walk(ctx, "", func(rel string, d fs.DirEntry) {
+		if args.Path != "" && !dsl.MatchGlob(args.Path, rel) {
+			return
+		}
+		if info, err := d.Info(); err != nil || info.Size() > maxFileSize {
+			return
+		}
+		data, err := os.ReadFile(filepath.Join(r.root, filepath.FromSlash(rel)))
+		if err != nil || isBinary(data) {
+			return
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 64*1024), maxFileSize)
+		for line := 1; scanner.Scan(); line++ {
+			if !re.Match(scanner.Bytes()) {
+				continue
+			}
+			total++
+			if len(matches) < MaxGrepMatches {
+				matches = append(matches, fmt.Sprintf("%s:%d: %s", rel, line, strings.TrimSpace(scanner.Text())))
+			}
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if total == 0 {
+		return "(no matches)", nil
+	}
+	out := strings.Join(matches, "\n")
+	if total > len(matches) {
+		out += fmt.Sprintf("\n(%d more matches not shown)", total-len(matches))
+	}
+	return truncate(out), nil
+}
+
+// gitDiff implements git_diff
+func (r *repository) gitDiff(ctx context.Context, arguments string) (string, error) {
+	var args struct {
+		Base  string   `json:"base"`
+		Head  string   `json:"head"`
+		Paths []string `json:"paths"`
+	}
+	if err := decodeArguments(arguments, &args); err != nil {
+		return "", err
+	}
+	if args.Base == "" {
+		args.Base = "HEAD~1"
+	}
+	if args.Head == "" {
+		args.Head = "HEAD"
+	}
+	// Revisions starting with "-" would be parsed as options
+	for _, revision := range []string{args.Base, args.Head} {
+		if strings.HasPrefix(revision, "-") || strings.ContainsAny(revision, " \t\n") {
+			return "", fmt.Errorf("invalid revision: %s", revision)
+		}
+	}
+
+	gitArgs := []string{"-C", r.root, "diff", "--no-color", "--no-ext-diff", args.Base, args.Head, "--"}
+	for _, path := range args.Paths {
+		if _, err := r.resolve(path); err != nil {
+			return "", err
+		}
+		gitArgs = append(gitArgs, path)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", gitArgs...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git diff failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	if stdout.Len() == 0 {
+		return "(no changes)", nil
+	}
+	return truncate(stdout.String()), nil
+}
+
+// resolve returns the absolute path of a path relative to the repository root.
+// Paths leaving the repository, directly or through symlinks, and the .git directory are rejected.
+func (r *repository) resolve(rel string) (string, error) {
+	clean := filepath.Clean("/" + filepath.FromSlash(strings.TrimSpace(rel)))
+	if filepath.IsAbs(rel) || strings.Contains(filepath.ToSlash(rel), "../") || rel == ".." {
+		return "", fmt.Errorf("path must be relative to the repository root: %s", rel)
+	}
+	if clean == "/.git" || strings.HasPrefix(clean, "/.git/") {
+		return "", fmt.Errorf("access to .git is not allowed")
+	}
+
+	path := filepath.Join(r.root, clean)
+	root, err := filepath.EvalSymlinks(r.root)
+	if err != nil {
+		return "", fmt.Errorf("repository is not accessible")
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("%s does not exist", rel)
+	}
+	if resolved != root && !strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("path is outside the repository: %s", rel)
+	}
+	// A symlink inside the repository may point into .git
+	if inRepo, err := filepath.Rel(root, resolved); err == nil {
+		inRepo = filepath.ToSlash(inRepo)
+		if inRepo == ".git" || strings.HasPrefix(inRepo, ".git/") {
+			return "", fmt.Errorf("access to .git is not allowed")
+		}
+	}
+	return resolved, nil
+}
+
+// walk calls fn for the regular files under a directory with their path relative to the root,
+// skipping the .git directory and symlinks
+func (r *repository) walk(ctx context.Context, dir string, fn func(rel string, d fs.DirEntry)) error {
+	root, err := r.resolve("")
+	if err != nil {
+		return err
+	}
+	if dir, err = r.resolve(dir); err != nil {
+		return err
+	}
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			return nil // skip unreadable entries
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		fn(filepath.ToSlash(rel), d)
+		return nil
+	})
+}
+
+// decodeArguments decodes the JSON arguments of a tool call
+func decodeArguments(arguments string, target interface{}) error {
+	if strings.TrimSpace(arguments) == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(arguments), target); err != nil {
+		return fmt.Errorf("invalid arguments: %v", err)
+	}
+	return nil
+}
+
+// isBinary reports whether data looks like a binary file
+func isBinary(data []byte) bool {
+	head := data
+	if len(head) > 8000 {
+		head = head[:8000]
+	}
+	return bytes.IndexByte(head, 0) >= 0
+}
+
+// truncate cuts an output to MaxOutputBytes
+func truncate(s string) string {
+	if len(s) <= MaxOutputBytes {
+		return s
+	}
+	cut := MaxOutputBytes
+	if nl := strings.LastIndexByte(s[:cut], '\n'); nl > cut/2 {
+		cut = nl
+	}
+	return s[:cut] + truncationNote
+}
+
+// objectSchema returns the JSON Schema of an arguments object
+func objectSchema(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// stringProperty returns the JSON Schema of a string argument
+func stringProperty(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description}
+}
+
+// integerProperty returns the JSON Schema of an integer argument
+func integerProperty(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "integer", "description": description}
+}
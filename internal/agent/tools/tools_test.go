@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/verustcode/verustcode/internal/llm"
+)
+
+// newRepo creates a git repository with two commits
+func newRepo(t *testing.T) string {
+	t.Helper()
+	repo := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		path := filepath.Join(repo, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("main.go", "package main\n\nfunc main() {}\n")
+	write("internal/db/query.go", "package db\n\nconst query = \"SELECT * FROM users WHERE id = \" + id\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+	write("main.go", "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n")
+	git("commit", "-q", "-am", "hello")
+	return repo
+}
+
+func call(t *testing.T, tools []*llm.Tool, name, arguments string) (string, error) {
+	t.Helper()
+	tool := llm.FindTool(tools, name)
+	if tool == nil {
+		t.Fatalf("tool %s not found", name)
+	}
+	return tool.Handler(context.Background(), arguments)
+}
+
+func TestRepository(t *testing.T) {
+	repo := newRepo(t)
+	tools := Repository(repo)
+
+	out, err := call(t, tools, "list_files", `{}`)
+	if err != nil || out != "internal/db/query.go\nmain.go" {
+		t.Errorf("list_files = %q, %v", out, err)
+	}
+	out, err = call(t, tools, "list_files", `{"path":"internal","pattern":"*.go"}`)
+	if err != nil || out != "internal/db/query.go" {
+		t.Errorf("list_files(internal) = %q, %v", out, err)
+	}
+
+	out, err = call(t, tools, "read_file", `{"path":"main.go","start_line":3,"end_line":4}`)
+	if err != nil || out != "     3| func main() {\n     4| \tprintln(\"hello\")\n(lines 3-4 of 5)\n" {
+		t.Errorf("read_file = %q, %v", out, err)
+	}
+
+	out, err = call(t, tools, "grep", `{"pattern":"SELECT .* WHERE","path":"**/*.go"}`)
+	if err != nil || !strings.HasPrefix(out, "internal/db/query.go:3: const query") {
+		t.Errorf("grep = %q, %v", out, err)
+	}
+
+	out, err = call(t, tools, "git_diff", `{"paths":["main.go"]}`)
+	if err != nil || !strings.Contains(out, "+\tprintln(\"hello\")") {
+		t.Errorf("git_diff = %q, %v", out, err)
+	}
+}
+
+func TestRepository_Confinement(t *testing.T) {
+	repo := newRepo(t)
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(repo, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(".git", filepath.Join(repo, "gitdir")); err != nil {
+		t.Fatal(err)
+	}
+	tools := Repository(repo)
+
+	rejected := map[string]string{
+		"read_file":  `{"path":"../secret.txt"}`,
+		"list_files": `{"path":"/etc"}`,
+		"git_diff":   `{"base":"--output=/tmp/x"}`,
+	}
+	for name, arguments := range rejected {
+		if _, err := call(t, tools, name, arguments); err == nil {
+			t.Errorf("%s(%s) should be rejected", name, arguments)
+		}
+	}
+	if _, err := call(t, tools, "list_files", `{"path":"gitdir"}`); err == nil {
+		t.Error("list_files(gitdir) should be rejected")
+	}
+	for _, path := range []string{"link.txt", ".git/config", "gitdir/config"} {
+		if _, err := call(t, tools, "read_file", `{"path":"`+path+`"}`); err == nil {
+			t.Errorf("read_file(%s) should be rejected", path)
+		}
+	}
+
+	// Symlinks and the .git directory are not listed or searched
+	out, _ := call(t, tools, "list_files", `{}`)
+	if strings.Contains(out, "link.txt") || strings.Contains(out, ".git/") {
+		t.Errorf("list_files = %q", out)
+	}
+	if out, _ := call(t, tools, "grep", `{"pattern":"secret|repositoryformatversion"}`); out != "(no matches)" {
+		t.Errorf("grep = %q", out)
+	}
+}
+
+func TestRepository_LargeFile(t *testing.T) {
+	repo := newRepo(t)
+	large := strings.Repeat("x", maxFileSize) + "\n"
+	if err := os.WriteFile(filepath.Join(repo, "large.txt"), []byte(large), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tools := Repository(repo)
+
+	if _, err := call(t, tools, "read_file", `{"path":"large.txt"}`); err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("read_file(large.txt) error = %v, want too large", err)
+	}
+	if out, _ := call(t, tools, "grep", `{"pattern":"xxx"}`); out != "(no matches)" {
+		t.Errorf("grep = %q", out)
+	}
+}
+
+func TestWorkspace(t *testing.T) {
+	if Workspace("") != nil || Workspace(filepath.Join(t.TempDir(), "missing")) != nil {
+		t.Error("Workspace() should return no tools without a workspace directory")
+	}
+	if tools := Workspace(t.TempDir()); len(tools) != 4 {
+		t.Errorf("Workspace() returned %d tools, want 4", len(tools))
+	}
+}
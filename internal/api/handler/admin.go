@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -15,6 +16,9 @@ import (
 
 	"github.com/verustcode/verustcode/consts"
 	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/engine"
+	"github.com/verustcode/verustcode/internal/engine/breaker"
+	"github.com/verustcode/verustcode/internal/engine/utils"
 	"github.com/verustcode/verustcode/internal/model"
 	"github.com/verustcode/verustcode/internal/store"
 	"github.com/verustcode/verustcode/pkg/logger"
@@ -25,6 +29,7 @@ type AdminHandler struct {
 	config    *config.Config
 	configDir string
 	store     store.Store
+	engine    *engine.Engine
 }
 
 // NewAdminHandler creates a new admin handler
@@ -38,6 +43,11 @@ func NewAdminHandler(cfg *config.Config, configPath string, s store.Store) *Admi
 	}
 }
 
+// SetEngine sets the review engine used to report runtime state such as agent health
+func (h *AdminHandler) SetEngine(e *engine.Engine) {
+	h.engine = e
+}
+
 // StatsResponse represents the dashboard statistics response
 type StatsResponse struct {
 	TodayReviews    int64   `json:"today_reviews"`
@@ -50,6 +60,10 @@ type StatsResponse struct {
 	FailedToday     int64   `json:"failed_today"`
 	RepositoryCount int64   `json:"repository_count"` // Total number of repositories
 	TotalReports    int64   `json:"total_reports"`    // Total number of reports
+
+	// Token usage and cost of reviews and reports in the current month
+	MonthUsage model.UsageStats  `json:"month_usage"`
+	RepoUsage  []model.RepoUsage `json:"repo_usage"` // per repository with budget, highest cost first
 }
 
 // GetStats handles GET /api/v1/admin/stats
@@ -101,9 +115,58 @@ func (h *AdminHandler) GetStats(c *gin.Context) {
 	// Total reports count
 	stats.TotalReports, _ = reportStore.CountAll()
 
+	// Month-to-date token usage and cost
+	stats.MonthUsage, stats.RepoUsage = h.monthUsage(now)
+
 	c.JSON(http.StatusOK, stats)
 }
 
+// monthUsage returns the token usage of the current month in total and per repository.
+// Repositories with a budget are listed even if they have no usage yet.
+func (h *AdminHandler) monthUsage(now time.Time) (model.UsageStats, []model.RepoUsage) {
+	records, err := utils.LoadUsage(h.store, "", utils.MonthStart(now))
+	if err != nil {
+		logger.Warn("Failed to load token usage for statistics", zap.Error(err))
+	}
+
+	byRepo := make(map[string]*model.RepoUsage)
+	repoUsage := func(repoURL string) *model.RepoUsage {
+		if byRepo[repoURL] == nil {
+			byRepo[repoURL] = &model.RepoUsage{RepoURL: repoURL}
+		}
+		return byRepo[repoURL]
+	}
+
+	var total model.TokenUsage
+	for _, r := range records {
+		total.Add(r.TokenUsage)
+		repoUsage(r.RepoURL).TokenUsage.Add(r.TokenUsage)
+	}
+
+	repoConfigs, _ := h.store.RepositoryConfig().ListAll()
+	for _, cfg := range repoConfigs {
+		if cfg.MonthlyBudget > 0 {
+			usage := repoUsage(cfg.RepoURL)
+			usage.MonthlyBudget = cfg.MonthlyBudget
+			usage.BudgetAction = utils.BudgetActionOf(&cfg)
+		}
+	}
+
+	result := make([]model.RepoUsage, 0, len(byRepo))
+	for _, usage := range byRepo {
+		usage.TotalTokens = usage.TokenUsage.TotalTokens()
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Cost != result[j].Cost {
+			return result[i].Cost > result[j].Cost
+		}
+		return result[i].RepoURL < result[j].RepoURL
+	})
+
+	return model.NewUsageStats(total), result
+}
+
 // ServerStatusResponse represents the server status response
 type ServerStatusResponse struct {
 	Version     string `json:"version"`      // Application version
@@ -113,6 +176,9 @@ type ServerStatusResponse struct {
 	StartedAt   string `json:"started_at"`   // Server start time in RFC3339 format
 	GoVersion   string `json:"go_version"`   // Go runtime version
 	MemoryUsage int64  `json:"memory_usage"` // Memory usage in bytes (heap alloc)
+
+	// Agents lists the circuit breaker state of each agent that has been called
+	Agents []breaker.Status `json:"agents"`
 }
 
 // GetStatus handles GET /api/v1/admin/status
@@ -133,6 +199,12 @@ func (h *AdminHandler) GetStatus(c *gin.Context) {
 		StartedAt:   startedAt.Format(time.RFC3339),
 		GoVersion:   runtime.Version(),
 		MemoryUsage: int64(memStats.Alloc), // Current heap allocation
+		Agents:      []breaker.Status{},
+	}
+	if h.engine != nil {
+		if agents := h.engine.GetAgentBreakerStatuses(); agents != nil {
+			status.Agents = agents
+		}
 	}
 
 	c.JSON(http.StatusOK, status)
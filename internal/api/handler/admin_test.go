@@ -794,6 +794,37 @@ func TestRepositoryHandler_CreateRepositoryConfig_InvalidRequest(t *testing.T) {
 	}
 }
 
+// TestRepositoryHandler_CreateRepositoryConfig_InvalidBudget tests creating with an invalid budget
+func TestRepositoryHandler_CreateRepositoryConfig_InvalidBudget(t *testing.T) {
+	router := SetupTestRouter()
+	testStore, cleanup := store.SetupTestDB(t)
+	defer cleanup()
+
+	handler := NewRepositoryHandler(testStore)
+	router.POST("/api/v1/admin/repositories", handler.CreateRepositoryConfig)
+
+	tests := []struct {
+		name    string
+		reqBody map[string]interface{}
+	}{
+		{"negative budget", map[string]interface{}{"repo_url": "https://github.com/test/repo", "monthly_budget": -1}},
+		{"unknown action", map[string]interface{}{"repo_url": "https://github.com/test/repo", "monthly_budget": 10, "budget_action": "pause"}},
+		{"downgrade without model", map[string]interface{}{"repo_url": "https://github.com/test/repo", "monthly_budget": 10, "budget_action": "downgrade"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := CreateTestRequest("POST", "/api/v1/admin/repositories", tt.reqBody)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d. Body: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
 // TestRepositoryHandler_UpdateRepositoryConfig_Success tests successfully updating a repository config
 func TestRepositoryHandler_UpdateRepositoryConfig_Success(t *testing.T) {
 	testReviewsDir := config.ReviewsDir
@@ -0,0 +1,242 @@
+// Package handler provides HTTP handlers for the API.
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/engine"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/store"
+	"github.com/verustcode/verustcode/pkg/errors"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// AreaHandler handles review area related HTTP requests
+type AreaHandler struct {
+	engine *engine.Engine
+	store  store.Store
+}
+
+// NewAreaHandler creates a new area handler
+func NewAreaHandler(e *engine.Engine, s store.Store) *AreaHandler {
+	return &AreaHandler{
+		engine: e,
+		store:  s,
+	}
+}
+
+// AreaRequest represents the request body for creating or updating a custom area
+type AreaRequest struct {
+	ID          string `json:"id"` // required on create, ignored on update
+	Group       string `json:"group"`
+	Description string `json:"description" binding:"required"`
+}
+
+// AreaItem represents a review area in API responses
+type AreaItem struct {
+	ID          string `json:"id"`
+	Group       string `json:"group"`
+	Description string `json:"description"`
+	Builtin     bool   `json:"builtin"`
+}
+
+// ListAreas handles GET /api/v1/admin/areas
+// Returns built-in areas followed by organization-wide custom areas.
+func (h *AreaHandler) ListAreas(c *gin.Context) {
+	items := make([]AreaItem, 0, len(dsl.AllAreas))
+	for _, area := range dsl.AllAreas {
+		items = append(items, AreaItem{
+			ID:          area.ID,
+			Group:       string(area.Group),
+			Description: area.Description,
+			Builtin:     true,
+		})
+	}
+
+	custom, err := h.store.Area().ListAll()
+	if err != nil {
+		logger.Error("Failed to list custom areas", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    errors.ErrCodeDBQuery,
+			"message": "Failed to list custom areas",
+		})
+		return
+	}
+	for i := range custom {
+		items = append(items, toAreaItem(&custom[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  items,
+		"total": len(items),
+	})
+}
+
+// CreateArea handles POST /api/v1/admin/areas
+func (h *AreaHandler) CreateArea(c *gin.Context) {
+	var req AreaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	area, ok := toCustomArea(c, strings.TrimSpace(req.ID), &req)
+	if !ok {
+		return
+	}
+
+	if _, err := h.store.Area().GetByAreaID(area.AreaID); err == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"code":    errors.ErrCodeConflict,
+			"message": "Area already exists: " + area.AreaID,
+		})
+		return
+	}
+
+	if err := h.store.Area().Create(area); err != nil {
+		logger.Error("Failed to create custom area", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    errors.ErrCodeDBQuery,
+			"message": "Failed to create custom area",
+		})
+		return
+	}
+
+	h.reloadAreas()
+
+	logger.Info("Created custom review area",
+		zap.String("area_id", area.AreaID),
+		zap.String("group", area.Group),
+	)
+
+	c.JSON(http.StatusCreated, toAreaItem(area))
+}
+
+// UpdateArea handles PUT /api/v1/admin/areas/:id
+func (h *AreaHandler) UpdateArea(c *gin.Context) {
+	areaID := c.Param("id")
+
+	var req AreaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	existing, err := h.store.Area().GetByAreaID(areaID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    errors.ErrCodeNotFound,
+			"message": "Custom area not found",
+		})
+		return
+	}
+
+	area, ok := toCustomArea(c, areaID, &req)
+	if !ok {
+		return
+	}
+	existing.Group = area.Group
+	existing.Description = area.Description
+
+	if err := h.store.Area().Save(existing); err != nil {
+		logger.Error("Failed to update custom area", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    errors.ErrCodeDBQuery,
+			"message": "Failed to update custom area",
+		})
+		return
+	}
+
+	h.reloadAreas()
+
+	logger.Info("Updated custom review area", zap.String("area_id", areaID))
+
+	c.JSON(http.StatusOK, toAreaItem(existing))
+}
+
+// DeleteArea handles DELETE /api/v1/admin/areas/:id
+// Review files still using the area fall back to the area ID without description.
+func (h *AreaHandler) DeleteArea(c *gin.Context) {
+	areaID := c.Param("id")
+
+	if _, err := h.store.Area().GetByAreaID(areaID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    errors.ErrCodeNotFound,
+			"message": "Custom area not found",
+		})
+		return
+	}
+
+	if err := h.store.Area().Delete(areaID); err != nil {
+		logger.Error("Failed to delete custom area", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    errors.ErrCodeDBQuery,
+			"message": "Failed to delete custom area",
+		})
+		return
+	}
+
+	h.reloadAreas()
+
+	logger.Info("Deleted custom review area", zap.String("area_id", areaID))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Custom area deleted successfully",
+	})
+}
+
+// reloadAreas refreshes the custom areas used by review files and prompts
+func (h *AreaHandler) reloadAreas() {
+	if h.engine == nil {
+		return
+	}
+	if err := h.engine.ReloadCustomAreas(); err != nil {
+		logger.Warn("Failed to reload custom areas", zap.Error(err))
+	}
+}
+
+// toCustomArea validates an area request and converts it to a CustomArea.
+// Writes a 400 response and returns false if the request is invalid.
+func toCustomArea(c *gin.Context, areaID string, req *AreaRequest) (*model.CustomArea, bool) {
+	def := dsl.AreaDefinition{
+		ID:          areaID,
+		Group:       dsl.AreaGroup(strings.TrimSpace(req.Group)),
+		Description: strings.TrimSpace(req.Description),
+	}
+	if def.Group == "" {
+		def.Group = dsl.AreaGroupCustom
+	}
+	if err := dsl.ValidateAreaDefinition(def); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": err.Error(),
+		})
+		return nil, false
+	}
+
+	return &model.CustomArea{
+		AreaID:      def.ID,
+		Group:       string(def.Group),
+		Description: def.Description,
+	}, true
+}
+
+// toAreaItem converts a custom area model to an API response item
+func toAreaItem(area *model.CustomArea) AreaItem {
+	return AreaItem{
+		ID:          area.AreaID,
+		Group:       area.Group,
+		Description: area.Description,
+	}
+}
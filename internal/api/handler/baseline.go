@@ -0,0 +1,140 @@
+// Package handler provides HTTP handlers for the API.
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/engine/suppression"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/pkg/errors"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// GenerateBaselineRequest represents the request body for generating a baseline
+type GenerateBaselineRequest struct {
+	Reason  string `json:"reason"`  // reason recorded for each new entry
+	Expires string `json:"expires"` // optional expiry date of new entries (YYYY-MM-DD)
+}
+
+// GenerateBaseline handles POST /api/v1/reviews/:id/baseline
+// It returns a .verustcode/baseline.yaml that accepts all findings of a completed review.
+// Findings already suppressed by the baseline keep their entries; findings suppressed
+// by verust:ignore directives are left out.
+func (h *ReviewHandler) GenerateBaseline(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid review ID",
+		})
+		return
+	}
+
+	var req GenerateBaselineRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    errors.ErrCodeValidation,
+				"message": "Invalid request body: " + err.Error(),
+			})
+			return
+		}
+	}
+	if req.Expires != "" {
+		if _, err := time.Parse(suppression.ExpiresLayout, req.Expires); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    errors.ErrCodeValidation,
+				"message": "Invalid expires date, expected YYYY-MM-DD",
+			})
+			return
+		}
+	}
+
+	review, err := h.store.Review().GetByIDWithDetails(id)
+	if err == gorm.ErrRecordNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    errors.ErrCodeReviewNotFound,
+			"message": "Review not found",
+		})
+		return
+	} else if err != nil {
+		logger.Error("Database error", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    errors.ErrCodeDBQuery,
+			"message": "Database error",
+		})
+		return
+	}
+
+	if review.Status != model.ReviewStatusCompleted {
+		c.JSON(http.StatusConflict, gin.H{
+			"code":    errors.ErrCodeReviewPending,
+			"message": "Baseline can only be generated from a completed review",
+		})
+		return
+	}
+
+	baseline := buildBaseline(review, req.Reason, req.Expires)
+	content, err := baseline.Marshal()
+	if err != nil {
+		logger.Error("Failed to encode baseline", zap.String("review_id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    errors.ErrCodeInternal,
+			"message": "Failed to encode baseline",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"path":    config.RepoBaselinePath,
+		"count":   len(baseline.Findings),
+		"content": string(content),
+	})
+}
+
+// buildBaseline collects the findings of the results of a review into a baseline
+func buildBaseline(review *model.Review, reason, expires string) *suppression.Baseline {
+	baseline := &suppression.Baseline{}
+
+	for _, rule := range review.Rules {
+		for _, result := range rule.Results {
+			if findings, ok := result.Data["findings"].([]interface{}); ok {
+				for _, item := range findings {
+					if finding, ok := item.(map[string]interface{}); ok {
+						baseline.Add(rule.RuleID, finding, reason, expires)
+					}
+				}
+			}
+
+			// Keep the entries of findings that the baseline already suppressed
+			suppressed, _ := result.Data["suppressed"].([]interface{})
+			for _, item := range suppressed {
+				entry, ok := item.(map[string]interface{})
+				if !ok || entry["source"] != suppression.SourceBaseline {
+					continue
+				}
+				fingerprint, _ := entry["fingerprint"].(string)
+				if fingerprint == "" {
+					continue
+				}
+				title, _ := entry["title"].(string)
+				location, _ := entry["location"].(string)
+				previousReason, _ := entry["reason"].(string)
+				baseline.AddEntry(suppression.BaselineEntry{
+					Fingerprint: fingerprint,
+					Rule:        rule.RuleID,
+					Title:       title,
+					Location:    location,
+					Reason:      previousReason,
+				})
+			}
+		}
+	}
+	return baseline
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/verustcode/verustcode/internal/engine"
+	"github.com/verustcode/verustcode/internal/engine/suppression"
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+// TestReviewHandler_GenerateBaseline tests generating a baseline from a completed review
+func TestReviewHandler_GenerateBaseline(t *testing.T) {
+	router := SetupTestRouter()
+	mockStore := NewMockStore()
+	handler := NewReviewHandler(&engine.Engine{}, mockStore)
+	router.POST("/api/v1/reviews/:id/baseline", handler.GenerateBaseline)
+
+	finding := map[string]interface{}{"title": "Hardcoded key", "location": "main.go:4-4", "category": "security"}
+	review := &model.Review{
+		ID:     "review-1",
+		Status: model.ReviewStatusCompleted,
+		Rules: []model.ReviewRule{
+			{
+				RuleID: "sec-review",
+				Results: []model.ReviewResult{
+					{Data: model.JSONMap{
+						"findings": []interface{}{finding, finding},
+						"suppressed": []interface{}{
+							map[string]interface{}{"fingerprint": "0123456789abcdef", "source": "baseline", "reason": "legacy"},
+							map[string]interface{}{"fingerprint": "fedcba9876543210", "source": "directive"},
+						},
+					}},
+				},
+			},
+		},
+	}
+	_ = mockStore.Review().Create(review)
+	_ = mockStore.Review().Create(&model.Review{ID: "review-2", Status: model.ReviewStatusRunning})
+
+	req := CreateTestRequest("POST", "/api/v1/reviews/review-1/baseline", map[string]interface{}{
+		"reason":  "accepted",
+		"expires": "2030-01-01",
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Path    string `json:"path"`
+		Count   int    `json:"count"`
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Count != 2 {
+		t.Errorf("count = %d, want 2 (duplicate finding merged, directive left out)", resp.Count)
+	}
+
+	baseline, err := suppression.ParseBaseline([]byte(resp.Content), time.Now())
+	if err != nil {
+		t.Fatalf("generated baseline does not parse: %v", err)
+	}
+	if entry := baseline.Match(suppression.FindingFingerprint("sec-review", finding)); entry == nil || entry.Reason != "accepted" {
+		t.Errorf("Match(finding) = %+v, want entry with the request reason", entry)
+	}
+	if entry := baseline.Match("0123456789abcdef"); entry == nil || entry.Reason != "legacy" {
+		t.Errorf("Match(previous entry) = %+v, want the kept baseline entry", entry)
+	}
+
+	// Invalid expiry date
+	req = CreateTestRequest("POST", "/api/v1/reviews/review-1/baseline", map[string]interface{}{"expires": "tomorrow"})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	AssertErrorResponse(t, w, http.StatusBadRequest)
+
+	// Review not completed
+	req = CreateTestRequest("POST", "/api/v1/reviews/review-2/baseline", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	AssertErrorResponse(t, w, http.StatusConflict)
+
+	// Unknown review
+	req = CreateTestRequest("POST", "/api/v1/reviews/missing/baseline", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	AssertErrorResponse(t, w, http.StatusNotFound)
+}
@@ -0,0 +1,53 @@
+// Package handler provides HTTP handlers for the API.
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/store"
+	"github.com/verustcode/verustcode/pkg/errors"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// CacheHandler handles response cache related HTTP requests
+type CacheHandler struct {
+	store store.Store
+}
+
+// NewCacheHandler creates a new cache handler
+func NewCacheHandler(s store.Store) *CacheHandler {
+	return &CacheHandler{
+		store: s,
+	}
+}
+
+// PurgeCache handles DELETE /api/v1/admin/cache
+// Deletes cached agent results, optionally only those of a repository (repo_url) and/or rule (rule_id).
+func (h *CacheHandler) PurgeCache(c *gin.Context) {
+	repoURL := c.Query("repo_url")
+	ruleID := c.Query("rule_id")
+
+	deleted, err := h.store.ResponseCache().Purge(repoURL, ruleID)
+	if err != nil {
+		logger.Error("Failed to purge response cache", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    errors.ErrCodeDBQuery,
+			"message": "Failed to purge response cache",
+		})
+		return
+	}
+
+	logger.Info("Purged response cache",
+		zap.String("repo_url", repoURL),
+		zap.String("rule_id", ruleID),
+		zap.Int64("deleted", deleted),
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Response cache purged successfully",
+		"deleted": deleted,
+	})
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/store"
+)
+
+// TestCacheHandler_PurgeCache tests purging the response cache with and without filters
+func TestCacheHandler_PurgeCache(t *testing.T) {
+	router := SetupTestRouter()
+	testStore, cleanup := store.SetupTestDB(t)
+	defer cleanup()
+
+	expires := time.Now().Add(time.Hour)
+	for _, entry := range []*model.ResponseCacheEntry{
+		{CacheKey: "a", RepoURL: "https://github.com/org/one", RuleID: "security", ExpiresAt: expires},
+		{CacheKey: "b", RepoURL: "https://github.com/org/one", RuleID: "style", ExpiresAt: expires},
+		{CacheKey: "c", RepoURL: "https://github.com/org/two", RuleID: "security", ExpiresAt: expires},
+	} {
+		if err := testStore.ResponseCache().Put(entry); err != nil {
+			t.Fatalf("Put() failed: %v", err)
+		}
+	}
+
+	handler := NewCacheHandler(testStore)
+	router.DELETE("/api/v1/admin/cache", handler.PurgeCache)
+
+	for _, tt := range []struct {
+		query   string
+		deleted int64
+	}{
+		{query: "?repo_url=https://github.com/org/one&rule_id=security", deleted: 1},
+		{query: "", deleted: 2},
+	} {
+		req := CreateTestRequest("DELETE", "/api/v1/admin/cache"+tt.query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		var resp struct {
+			Deleted int64 `json:"deleted"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if resp.Deleted != tt.deleted {
+			t.Errorf("Purge %q: expected %d deleted entries, got %d", tt.query, tt.deleted, resp.Deleted)
+		}
+	}
+}
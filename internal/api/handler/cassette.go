@@ -0,0 +1,58 @@
+// Package handler provides HTTP handlers for the API.
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/verustcode/verustcode/internal/cassette"
+	"github.com/verustcode/verustcode/internal/store"
+	"github.com/verustcode/verustcode/pkg/errors"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// CassetteHandler handles recorded agent interaction related HTTP requests
+type CassetteHandler struct {
+	store store.Store
+}
+
+// NewCassetteHandler creates a new cassette handler
+func NewCassetteHandler(s store.Store) *CassetteHandler {
+	return &CassetteHandler{
+		store: s,
+	}
+}
+
+// DownloadCassette handles GET /api/v1/admin/reviews/:id/cassette
+// Downloads the recorded agent interactions of a review as a cassette file for `verustcode replay`.
+func (h *CassetteHandler) DownloadCassette(c *gin.Context) {
+	id := c.Param("id")
+
+	cas, err := cassette.Build(h.store, id)
+	if err == gorm.ErrRecordNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    errors.ErrCodeReviewNotFound,
+			"message": "Review not found",
+		})
+		return
+	} else if err == cassette.ErrNotRecorded {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    errors.ErrCodeNotFound,
+			"message": "No agent interactions were recorded for this review (enable the recording review setting)",
+		})
+		return
+	} else if err != nil {
+		logger.Error("Failed to build cassette", zap.String("review_id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    errors.ErrCodeDBQuery,
+			"message": "Failed to build cassette",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename="+id+".cassette.json")
+	c.JSON(http.StatusOK, cas)
+}
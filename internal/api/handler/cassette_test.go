@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/store"
+)
+
+// TestCassetteHandler_DownloadCassette tests downloading the cassette of a recorded review
+func TestCassetteHandler_DownloadCassette(t *testing.T) {
+	router := SetupTestRouter()
+	testStore, cleanup := store.SetupTestDB(t)
+	defer cleanup()
+
+	for _, review := range []*model.Review{
+		{ID: "recorded", Ref: "main", CommitSHA: "abc123", RepoURL: "https://github.com/org/repo", Status: model.ReviewStatusCompleted},
+		{ID: "unrecorded", Ref: "main", CommitSHA: "def456", RepoURL: "https://github.com/org/repo", Status: model.ReviewStatusCompleted},
+	} {
+		if err := testStore.Review().Create(review); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+	}
+	if err := testStore.Cassette().Record(&model.CassetteInteraction{
+		ReviewID: "recorded", RuleID: "security", Agent: "cursor", Prompt: "prompt", Content: "answer",
+	}); err != nil {
+		t.Fatalf("Record() failed: %v", err)
+	}
+
+	handler := NewCassetteHandler(testStore)
+	router.GET("/api/v1/admin/reviews/:id/cassette", handler.DownloadCassette)
+
+	for _, tt := range []struct {
+		id     string
+		status int
+	}{
+		{id: "recorded", status: http.StatusOK},
+		{id: "unrecorded", status: http.StatusNotFound},
+		{id: "missing", status: http.StatusNotFound},
+	} {
+		req := CreateTestRequest("GET", "/api/v1/admin/reviews/"+tt.id+"/cassette", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Fatalf("%s: expected status %d, got %d", tt.id, tt.status, w.Code)
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		if !strings.Contains(w.Header().Get("Content-Disposition"), "recorded.cassette.json") {
+			t.Errorf("Expected attachment file name, got %q", w.Header().Get("Content-Disposition"))
+		}
+		var cassette model.Cassette
+		if err := json.Unmarshal(w.Body.Bytes(), &cassette); err != nil {
+			t.Fatalf("Failed to parse cassette: %v", err)
+		}
+		if cassette.CommitSHA != "abc123" || len(cassette.Interactions) != 1 || cassette.Interactions[0].Content != "answer" {
+			t.Errorf("Unexpected cassette: %+v", cassette)
+		}
+	}
+}
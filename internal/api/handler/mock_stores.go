@@ -341,6 +341,22 @@ func (m *MockReviewStore) UpdateRunStatus(id uint, status model.RunStatus) error
 	return nil
 }
 
+func (m *MockReviewStore) CreateChunk(chunk *model.ReviewRuleChunk) error {
+	return nil
+}
+
+func (m *MockReviewStore) GetChunksByRuleID(ruleID uint) ([]model.ReviewRuleChunk, error) {
+	return nil, nil
+}
+
+func (m *MockReviewStore) DeleteReviewRuleChunksByRuleID(ruleID uint) error {
+	return nil
+}
+
+func (m *MockReviewStore) UpdateChunk(chunk *model.ReviewRuleChunk) error {
+	return nil
+}
+
 func (m *MockReviewStore) CreateResult(result *model.ReviewResult) error {
 	return nil
 }
@@ -426,6 +442,31 @@ func (m *MockReviewStore) ListCompletedByRepoAndDateRange(repoURL string, start
 	return reviews, nil
 }
 
+func (m *MockReviewStore) ListUsageByRepoSince(repoURL string, start time.Time) ([]model.UsageRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var records []model.UsageRecord
+	for _, rule := range m.rules {
+		review, ok := m.reviews[rule.ReviewID]
+		if !ok || (repoURL != "" && review.RepoURL != repoURL) || rule.CreatedAt.Before(start) {
+			continue
+		}
+		if rule.PromptTokens > 0 || rule.CompletionTokens > 0 {
+			records = append(records, model.UsageRecord{RepoURL: review.RepoURL, CreatedAt: rule.CreatedAt, TokenUsage: rule.TokenUsage})
+		}
+	}
+	return records, nil
+}
+
+func (m *MockReviewStore) SumCostByRepoSince(repoURL string, start time.Time) (float64, error) {
+	records, err := m.ListUsageByRepoSince(repoURL, start)
+	var cost float64
+	for _, r := range records {
+		cost += r.Cost
+	}
+	return cost, err
+}
+
 func (m *MockReviewStore) GetReviewResultsByReviewIDs(reviewIDs []string) ([]model.ReviewResult, error) {
 	// Return empty results for now - can be extended if needed
 	return []model.ReviewResult{}, nil
@@ -694,6 +735,15 @@ func (m *MockReportStore) CountAll() (int64, error) {
 	return int64(len(m.reports)), nil
 }
 
+func (m *MockReportStore) ListUsageByRepoSince(repoURL string, start time.Time) ([]model.UsageRecord, error) {
+	// Sections are not tracked by the mock
+	return nil, nil
+}
+
+func (m *MockReportStore) SumCostByRepoSince(repoURL string, start time.Time) (float64, error) {
+	return 0, nil
+}
+
 func (m *MockReportStore) CancelByID(id string) (int64, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
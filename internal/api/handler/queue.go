@@ -0,0 +1,196 @@
+// Package handler provides HTTP handlers for the API.
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/engine"
+	"github.com/verustcode/verustcode/pkg/errors"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// QueueHandler handles queue administration HTTP requests
+type QueueHandler struct {
+	engine *engine.Engine
+}
+
+// NewQueueHandler creates a new queue handler
+func NewQueueHandler(e *engine.Engine) *QueueHandler {
+	return &QueueHandler{engine: e}
+}
+
+// QueuedTaskResponse represents a running or pending task in the queue listing
+type QueuedTaskResponse struct {
+	ReviewID   string `json:"review_id"`
+	RepoURL    string `json:"repo_url"`
+	Ref        string `json:"ref"`
+	PRNumber   int    `json:"pr_number,omitempty"`
+	Source     string `json:"source"`
+	Priority   string `json:"priority"`
+	Running    bool   `json:"running"`
+	Position   int    `json:"position"`
+	EnqueuedAt string `json:"enqueued_at"`
+	// ETASeconds is the estimated number of seconds until the task starts (omitted if unknown)
+	ETASeconds *int64 `json:"eta_seconds,omitempty"`
+}
+
+// RepoQueueRequest represents the request body for pausing or resuming a repository queue
+type RepoQueueRequest struct {
+	RepoURL string `json:"repo_url" binding:"required"`
+}
+
+// MaintenanceRequest represents the request body for toggling maintenance mode
+type MaintenanceRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// ListQueue handles GET /api/v1/admin/queue
+func (h *QueueHandler) ListQueue(c *gin.Context) {
+	stats := h.engine.GetQueueStats()
+	tasks := h.engine.ListQueuedTasks()
+
+	items := make([]QueuedTaskResponse, 0, len(tasks))
+	for _, t := range tasks {
+		item := QueuedTaskResponse{
+			ReviewID:   t.ReviewID,
+			RepoURL:    t.RepoURL,
+			Ref:        t.Ref,
+			PRNumber:   t.PRNumber,
+			Source:     t.Source,
+			Priority:   t.Priority.String(),
+			Running:    t.Running,
+			Position:   t.Position,
+			EnqueuedAt: t.EnqueuedAt.Format(time.RFC3339),
+		}
+		if t.EstimatedStart != nil {
+			seconds := int64(t.EstimatedStart.Seconds())
+			item.ETASeconds = &seconds
+		}
+		items = append(items, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"maintenance":       stats.Maintenance,
+		"total_pending":     stats.TotalPending,
+		"total_running":     stats.TotalRunning,
+		"repo_count":        stats.RepoCount,
+		"paused_repo_count": stats.PausedRepoCount,
+		"pending_by_lane":   stats.PendingByLane,
+		"avg_duration_ms":   stats.AvgTaskDuration.Milliseconds(),
+		"repos":             stats.RepoStats,
+		"tasks":             items,
+		"agent_calls":       h.engine.GetAgentPoolStatuses(),
+	})
+}
+
+// MoveToFront handles POST /api/v1/admin/queue/tasks/:id/front
+func (h *QueueHandler) MoveToFront(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid review ID",
+		})
+		return
+	}
+
+	if err := h.engine.MoveTaskToFront(id); err != nil {
+		respondAppError(c, err)
+		return
+	}
+
+	logger.Info("Queued task moved to front", zap.String("review_id", id))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task moved to front of queue",
+	})
+}
+
+// PauseRepo handles POST /api/v1/admin/queue/repos/pause
+func (h *QueueHandler) PauseRepo(c *gin.Context) {
+	var req RepoQueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if err := h.engine.PauseRepoQueue(req.RepoURL); err != nil {
+		respondAppError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Repository queue paused",
+		"repo_url": req.RepoURL,
+	})
+}
+
+// ResumeRepo handles POST /api/v1/admin/queue/repos/resume
+func (h *QueueHandler) ResumeRepo(c *gin.Context) {
+	var req RepoQueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if err := h.engine.ResumeRepoQueue(req.RepoURL); err != nil {
+		respondAppError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Repository queue resumed",
+		"repo_url": req.RepoURL,
+	})
+}
+
+// SetMaintenance handles PUT /api/v1/admin/queue/maintenance
+func (h *QueueHandler) SetMaintenance(c *gin.Context) {
+	var req MaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if err := h.engine.SetMaintenanceMode(*req.Enabled); err != nil {
+		respondAppError(c, err)
+		return
+	}
+
+	logger.Info("Maintenance mode updated via admin API", zap.Bool("enabled", *req.Enabled))
+
+	c.JSON(http.StatusOK, gin.H{
+		"maintenance": h.engine.InMaintenanceMode(),
+	})
+}
+
+// respondAppError writes an error response, using the AppError status and code when available
+func respondAppError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	code := errors.ErrCodeInternal
+	message := err.Error()
+
+	if appErr, ok := errors.AsAppError(err); ok {
+		status = appErr.HTTPStatus()
+		code = appErr.Code
+		message = appErr.Message
+	}
+
+	c.JSON(status, gin.H{
+		"code":    code,
+		"message": message,
+	})
+}
@@ -31,14 +31,19 @@ func NewRepositoryHandler(s store.Store) *RepositoryHandler {
 
 // RepositoryConfigItem represents a repository with its review config
 type RepositoryConfigItem struct {
-	ID           uint    `json:"id"`
-	RepoURL      string  `json:"repo_url"`
-	ReviewFile   string  `json:"review_file"`
-	Description  string  `json:"description,omitempty"`
-	ReviewCount  int64   `json:"review_count"`             // Number of reviews for this repo
-	LastReviewAt *string `json:"last_review_at,omitempty"` // Last review timestamp
-	CreatedAt    string  `json:"created_at,omitempty"`
-	UpdatedAt    string  `json:"updated_at,omitempty"`
+	ID                 uint                     `json:"id"`
+	RepoURL            string                   `json:"repo_url"`
+	ReviewFile         string                   `json:"review_file"`
+	Description        string                   `json:"description,omitempty"`
+	InRepoConfigPolicy model.InRepoConfigPolicy `json:"in_repo_config_policy"`      // trust policy for .verust-review.yaml
+	MonthlyBudget      float64                  `json:"monthly_budget"`             // USD, 0 = no budget
+	BudgetAction       model.BudgetAction       `json:"budget_action,omitempty"`    // block or downgrade
+	BudgetModel        string                   `json:"budget_model,omitempty"`     // model used by downgrade
+	InjectionAction    model.InjectionAction    `json:"injection_action,omitempty"` // warn, strip or quarantine
+	ReviewCount        int64                    `json:"review_count"`               // Number of reviews for this repo
+	LastReviewAt       *string                  `json:"last_review_at,omitempty"`   // Last review timestamp
+	CreatedAt          string                   `json:"created_at,omitempty"`
+	UpdatedAt          string                   `json:"updated_at,omitempty"`
 }
 
 // ListRepositoriesResponse represents the response for listing repositories
@@ -54,12 +59,32 @@ type CreateRepositoryConfigRequest struct {
 	RepoURL     string `json:"repo_url" binding:"required"`
 	ReviewFile  string `json:"review_file"`
 	Description string `json:"description"`
+	// InRepoConfigPolicy is ignore, base_branch or merge (default: base_branch)
+	InRepoConfigPolicy model.InRepoConfigPolicy `json:"in_repo_config_policy"`
+	// MonthlyBudget is the monthly cost budget in USD (0 = no budget)
+	MonthlyBudget float64 `json:"monthly_budget"`
+	// BudgetAction is block or downgrade (default: block)
+	BudgetAction model.BudgetAction `json:"budget_action"`
+	// BudgetModel is the model used by the downgrade action
+	BudgetModel string `json:"budget_model"`
+	// InjectionAction is warn, strip or quarantine (default: warn)
+	InjectionAction model.InjectionAction `json:"injection_action"`
 }
 
 // UpdateRepositoryConfigRequest represents the request to update a repository config
 type UpdateRepositoryConfigRequest struct {
 	ReviewFile  string `json:"review_file"`
 	Description string `json:"description"`
+	// InRepoConfigPolicy is ignore, base_branch or merge (empty keeps the current policy)
+	InRepoConfigPolicy model.InRepoConfigPolicy `json:"in_repo_config_policy"`
+	// MonthlyBudget is the monthly cost budget in USD, 0 removes the budget (null keeps the current budget)
+	MonthlyBudget *float64 `json:"monthly_budget"`
+	// BudgetAction is block or downgrade (empty keeps the current action)
+	BudgetAction model.BudgetAction `json:"budget_action"`
+	// BudgetModel is the model used by the downgrade action (null keeps the current model)
+	BudgetModel *string `json:"budget_model"`
+	// InjectionAction is warn, strip or quarantine (empty keeps the current action)
+	InjectionAction model.InjectionAction `json:"injection_action"`
 }
 
 // ParseRepoUrlRequest represents the request body for parsing repository URL
@@ -125,14 +150,19 @@ func (h *RepositoryHandler) ListRepositories(c *gin.Context) {
 		}
 
 		items = append(items, RepositoryConfigItem{
-			ID:           r.ID,
-			RepoURL:      r.RepoURL,
-			ReviewFile:   r.ReviewFile,
-			Description:  r.Description,
-			ReviewCount:  r.ReviewCount,
-			LastReviewAt: lastReviewAtStr,
-			CreatedAt:    r.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:    r.UpdatedAt.Format(time.RFC3339),
+			ID:                 r.ID,
+			RepoURL:            r.RepoURL,
+			ReviewFile:         r.ReviewFile,
+			Description:        r.Description,
+			InRepoConfigPolicy: r.InRepoConfigPolicy,
+			MonthlyBudget:      r.MonthlyBudget,
+			BudgetAction:       r.BudgetAction,
+			BudgetModel:        r.BudgetModel,
+			InjectionAction:    r.InjectionAction,
+			ReviewCount:        r.ReviewCount,
+			LastReviewAt:       lastReviewAtStr,
+			CreatedAt:          r.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:          r.UpdatedAt.Format(time.RFC3339),
 		})
 	}
 
@@ -164,6 +194,33 @@ func (h *RepositoryHandler) CreateRepositoryConfig(c *gin.Context) {
 		return
 	}
 
+	// Validate in-repository config policy if specified
+	if req.InRepoConfigPolicy != "" && !req.InRepoConfigPolicy.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid in_repo_config_policy: " + string(req.InRepoConfigPolicy) + " (expected ignore, base_branch or merge)",
+		})
+		return
+	}
+
+	// Validate prompt injection action if specified
+	if req.InjectionAction != "" && !req.InjectionAction.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid injection_action: " + string(req.InjectionAction) + " (expected warn, strip or quarantine)",
+		})
+		return
+	}
+
+	// Validate budget settings
+	if msg := validateBudget(req.MonthlyBudget, req.BudgetAction, req.BudgetModel); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": msg,
+		})
+		return
+	}
+
 	// Check if config already exists
 	existing, _ := h.store.RepositoryConfig().GetByRepoURL(req.RepoURL)
 	if existing != nil {
@@ -179,6 +236,12 @@ func (h *RepositoryHandler) CreateRepositoryConfig(c *gin.Context) {
 		RepoURL:     req.RepoURL,
 		ReviewFile:  req.ReviewFile,
 		Description: req.Description,
+		// An empty policy is stored as the column default
+		InRepoConfigPolicy: req.InRepoConfigPolicy,
+		MonthlyBudget:      req.MonthlyBudget,
+		BudgetAction:       req.BudgetAction,
+		BudgetModel:        req.BudgetModel,
+		InjectionAction:    req.InjectionAction,
 	}
 
 	if err := h.store.RepositoryConfig().Create(cfg); err != nil {
@@ -231,6 +294,24 @@ func (h *RepositoryHandler) UpdateRepositoryConfig(c *gin.Context) {
 		return
 	}
 
+	// Validate in-repository config policy if specified
+	if req.InRepoConfigPolicy != "" && !req.InRepoConfigPolicy.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid in_repo_config_policy: " + string(req.InRepoConfigPolicy) + " (expected ignore, base_branch or merge)",
+		})
+		return
+	}
+
+	// Validate prompt injection action if specified
+	if req.InjectionAction != "" && !req.InjectionAction.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid injection_action: " + string(req.InjectionAction) + " (expected warn, strip or quarantine)",
+		})
+		return
+	}
+
 	// Find existing config
 	cfg, err := h.store.RepositoryConfig().GetByID(id)
 	if err != nil {
@@ -244,6 +325,30 @@ func (h *RepositoryHandler) UpdateRepositoryConfig(c *gin.Context) {
 	// Update fields
 	cfg.ReviewFile = req.ReviewFile
 	cfg.Description = req.Description
+	if req.InRepoConfigPolicy != "" {
+		cfg.InRepoConfigPolicy = req.InRepoConfigPolicy
+	}
+	if req.MonthlyBudget != nil {
+		cfg.MonthlyBudget = *req.MonthlyBudget
+	}
+	if req.BudgetAction != "" {
+		cfg.BudgetAction = req.BudgetAction
+	}
+	if req.BudgetModel != nil {
+		cfg.BudgetModel = *req.BudgetModel
+	}
+	if req.InjectionAction != "" {
+		cfg.InjectionAction = req.InjectionAction
+	}
+
+	// Validate the resulting budget settings
+	if msg := validateBudget(cfg.MonthlyBudget, cfg.BudgetAction, cfg.BudgetModel); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": msg,
+		})
+		return
+	}
 
 	if err := h.store.RepositoryConfig().Save(cfg); err != nil {
 		logger.Error("Failed to update repository config", zap.Error(err))
@@ -257,6 +362,9 @@ func (h *RepositoryHandler) UpdateRepositoryConfig(c *gin.Context) {
 	logger.Info("Updated repository config",
 		zap.Uint("id", id),
 		zap.String("review_file", req.ReviewFile),
+		zap.String("in_repo_config_policy", string(cfg.InRepoConfigPolicy)),
+		zap.Float64("monthly_budget", cfg.MonthlyBudget),
+		zap.String("injection_action", string(cfg.InjectionAction)),
 	)
 
 	c.JSON(http.StatusOK, gin.H{
@@ -264,6 +372,20 @@ func (h *RepositoryHandler) UpdateRepositoryConfig(c *gin.Context) {
 	})
 }
 
+// validateBudget validates budget settings and returns an error message, or "" if they are valid
+func validateBudget(monthlyBudget float64, action model.BudgetAction, budgetModel string) string {
+	if monthlyBudget < 0 {
+		return "Invalid monthly_budget: must not be negative"
+	}
+	if action != "" && !action.IsValid() {
+		return "Invalid budget_action: " + string(action) + " (expected block or downgrade)"
+	}
+	if action == model.BudgetActionDowngrade && budgetModel == "" {
+		return "budget_model is required for budget_action downgrade"
+	}
+	return ""
+}
+
 // DeleteRepositoryConfig handles DELETE /api/v1/admin/repositories/:id
 func (h *RepositoryHandler) DeleteRepositoryConfig(c *gin.Context) {
 	idStr := c.Param("id")
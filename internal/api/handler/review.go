@@ -257,6 +257,7 @@ func (h *ReviewHandler) CancelReview(c *gin.Context) {
 	rowsAffected, err := h.store.Review().UpdateStatusIfAllowed(id, model.ReviewStatusCancelled, []model.ReviewStatus{
 		model.ReviewStatusPending,
 		model.ReviewStatusRunning,
+		model.ReviewStatusQuarantined,
 	})
 
 	if err != nil {
@@ -321,6 +322,55 @@ func (h *ReviewHandler) RetryReview(c *gin.Context) {
 	})
 }
 
+// ApproveReview handles POST /api/v1/admin/reviews/:id/approve
+// It releases a review quarantined because of a possible prompt injection and queues it again
+func (h *ReviewHandler) ApproveReview(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid review ID",
+		})
+		return
+	}
+
+	// Get username from context (set by auth middleware)
+	username, _ := c.Get("username")
+	usernameStr, _ := username.(string)
+	if usernameStr == "" {
+		usernameStr = "admin"
+	}
+
+	err := h.engine.Approve(id, usernameStr)
+	if err != nil {
+		appErr, ok := errors.AsAppError(err)
+		status := http.StatusInternalServerError
+		code := errors.ErrCodeInternal
+		message := err.Error()
+
+		if ok {
+			status = appErr.HTTPStatus()
+			code = appErr.Code
+			message = appErr.Message
+		}
+
+		c.JSON(status, gin.H{
+			"code":    code,
+			"message": message,
+		})
+		return
+	}
+
+	logger.Info("Quarantined review approved",
+		zap.String("review_id", id),
+		zap.String("username", usernameStr),
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Review approved",
+	})
+}
+
 // RetryReviewRule handles POST /api/v1/reviews/:id/rules/:rule_id/retry
 // This endpoint allows retrying a single failed rule within a review
 // The rule retry runs asynchronously in a separate goroutine
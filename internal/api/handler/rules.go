@@ -16,6 +16,8 @@ import (
 
 	"github.com/verustcode/verustcode/internal/config"
 	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/engine/executor"
+	"github.com/verustcode/verustcode/internal/prompt"
 	"github.com/verustcode/verustcode/pkg/errors"
 	"github.com/verustcode/verustcode/pkg/logger"
 )
@@ -125,6 +127,192 @@ func (h *RulesHandler) GetRule(c *gin.Context) {
 	})
 }
 
+// ResolvedRuleInfo describes a rule in a resolved review file
+type ResolvedRuleInfo struct {
+	ID     string `json:"id"`
+	Source string `json:"source"` // review file defining the rule
+}
+
+// GetResolvedRule handles GET /api/v1/admin/rules/:name/resolved
+// Returns the review file with all include and extends references resolved
+func (h *RulesHandler) GetResolvedRule(c *gin.Context) {
+	name := c.Param("name")
+
+	// Validate filename and build safe path (from config/reviews/ directory)
+	filePath, ok := safeJoinPath(reviewsDir, name)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid rule file name",
+		})
+		return
+	}
+
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    errors.ErrCodeNotFound,
+			"message": "Rule file not found",
+		})
+		return
+	}
+
+	loader := dsl.NewLoader()
+	loader.SetResolver(dsl.NewResolver(reviewsDir))
+	resolved, err := loader.Load(filePath)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeConfigInvalid,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	content, err := yaml.Marshal(resolved)
+	if err != nil {
+		logger.Error("Failed to marshal resolved rule file", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    errors.ErrCodeInternal,
+			"message": "Failed to render resolved rule file",
+		})
+		return
+	}
+
+	rules := make([]ResolvedRuleInfo, 0, len(resolved.Rules))
+	for i := range resolved.Rules {
+		rules = append(rules, ResolvedRuleInfo{
+			ID:     resolved.Rules[i].ID,
+			Source: resolved.Rules[i].Source(),
+		})
+	}
+
+	includes := resolved.IncludeGraph()
+	if includes == nil {
+		includes = map[string][]string{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"content":  string(content),
+		"rules":    rules,
+		"includes": includes,
+	})
+}
+
+// PreviewPromptRequest represents the request to preview the prompt of a rule
+type PreviewPromptRequest struct {
+	RuleID  string           `json:"rule_id" binding:"required"`
+	Content string           `json:"content"` // optional: unsaved file content, defaults to the saved file
+	PR      *PreviewPRSample `json:"pr"`      // optional: sample PR, defaults to defaultPreviewPR
+}
+
+// PreviewPRSample describes the sample PR used to render a prompt preview
+type PreviewPRSample struct {
+	Number       int      `json:"number"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	Ref          string   `json:"ref"`
+	ChangedFiles []string `json:"changed_files"`
+}
+
+// defaultPreviewPR is the sample PR used when the preview request does not specify one
+var defaultPreviewPR = PreviewPRSample{
+	Number:       1,
+	Title:        "Add input validation to user registration",
+	Description:  "Validates email and password on registration and returns field errors.",
+	Ref:          "feature/registration-validation",
+	ChangedFiles: []string{"internal/user/register.go", "internal/user/register_test.go"},
+}
+
+// PreviewPrompt handles POST /api/v1/admin/rules/:name/preview
+// Renders the final prompt of a rule (including its prompt_template) for a sample PR
+func (h *RulesHandler) PreviewPrompt(c *gin.Context) {
+	name := c.Param("name")
+
+	filePath, ok := safeJoinPath(reviewsDir, name)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid rule file name",
+		})
+		return
+	}
+
+	var req PreviewPromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	var rulesConfig *dsl.ReviewRulesConfig
+	var err error
+	if req.Content != "" {
+		rulesConfig, err = dsl.NewParser().ParseFile([]byte(req.Content), filePath, dsl.NewResolver(reviewsDir))
+	} else {
+		if _, statErr := os.Stat(filePath); os.IsNotExist(statErr) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"code":    errors.ErrCodeNotFound,
+				"message": "Rule file not found",
+			})
+			return
+		}
+		loader := dsl.NewLoader()
+		loader.SetResolver(dsl.NewResolver(reviewsDir))
+		rulesConfig, err = loader.Load(filePath)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeConfigInvalid,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var rule *dsl.ReviewRuleConfig
+	for i := range rulesConfig.Rules {
+		if rulesConfig.Rules[i].ID == req.RuleID {
+			rule = &rulesConfig.Rules[i]
+			break
+		}
+	}
+	if rule == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    errors.ErrCodeNotFound,
+			"message": fmt.Sprintf("Rule not found: %s", req.RuleID),
+		})
+		return
+	}
+
+	sample := defaultPreviewPR
+	if req.PR != nil {
+		sample = *req.PR
+	}
+	buildCtx := &prompt.BuildContext{
+		Ref:           sample.Ref,
+		PRNumber:      sample.Number,
+		PRTitle:       sample.Title,
+		PRDescription: sample.Description,
+		ChangedFiles:  sample.ChangedFiles,
+		Areas:         rulesConfig.Areas,
+	}
+
+	spec := prompt.NewBuilder().Build(rule, buildCtx)
+	promptText, err := executor.RenderPrompt(rule, spec, buildCtx)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeConfigInvalid,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rule_id": rule.ID,
+		"prompt":  promptText,
+	})
+}
+
 // SaveRule handles PUT /api/v1/admin/rules/:name
 // Supports optimistic locking via hash parameter
 func (h *RulesHandler) SaveRule(c *gin.Context) {
@@ -0,0 +1,373 @@
+// Package handler provides HTTP handlers for the API.
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/engine"
+	"github.com/verustcode/verustcode/internal/engine/scheduler"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/store"
+	"github.com/verustcode/verustcode/pkg/errors"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+const (
+	// defaultScheduleRunsLimit is the default number of history entries returned
+	defaultScheduleRunsLimit = 20
+	// maxScheduleRunsLimit caps the number of history entries returned
+	maxScheduleRunsLimit = 200
+)
+
+// ScheduleHandler handles scheduled audit related HTTP requests
+type ScheduleHandler struct {
+	engine *engine.Engine
+	store  store.Store
+}
+
+// NewScheduleHandler creates a new schedule handler
+func NewScheduleHandler(e *engine.Engine, s store.Store) *ScheduleHandler {
+	return &ScheduleHandler{
+		engine: e,
+		store:  s,
+	}
+}
+
+// ScheduleRequest represents the request body for creating or updating a schedule
+type ScheduleRequest struct {
+	Name        string `json:"name" binding:"required"`
+	RepoURL     string `json:"repo_url" binding:"required"`
+	Ref         string `json:"ref" binding:"required"`
+	CronExpr    string `json:"cron_expr" binding:"required"`
+	ReviewFile  string `json:"review_file"`
+	Mode        string `json:"mode"`
+	Enabled     *bool  `json:"enabled"`
+	Description string `json:"description"`
+}
+
+// ScheduleItem represents a schedule in API responses
+type ScheduleItem struct {
+	model.ReviewSchedule
+	// NextRunAt is the next time the schedule fires (omitted when disabled)
+	NextRunAt *string `json:"next_run_at,omitempty"`
+}
+
+// ListSchedules handles GET /api/v1/admin/schedules
+// Supports optional repo_url query parameter to filter by repository.
+func (h *ScheduleHandler) ListSchedules(c *gin.Context) {
+	var (
+		schedules []model.ReviewSchedule
+		err       error
+	)
+	if repoURL := c.Query("repo_url"); repoURL != "" {
+		schedules, err = h.store.Schedule().ListByRepoURL(repoURL)
+	} else {
+		schedules, err = h.store.Schedule().ListAll()
+	}
+	if err != nil {
+		logger.Error("Failed to list schedules", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    errors.ErrCodeDBQuery,
+			"message": "Failed to list schedules",
+		})
+		return
+	}
+
+	items := make([]ScheduleItem, 0, len(schedules))
+	for _, sched := range schedules {
+		items = append(items, h.toScheduleItem(sched))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  items,
+		"total": len(items),
+	})
+}
+
+// GetSchedule handles GET /api/v1/admin/schedules/:id
+func (h *ScheduleHandler) GetSchedule(c *gin.Context) {
+	id, ok := parseScheduleID(c)
+	if !ok {
+		return
+	}
+
+	sched, err := h.store.Schedule().GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    errors.ErrCodeNotFound,
+			"message": "Schedule not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.toScheduleItem(*sched))
+}
+
+// CreateSchedule handles POST /api/v1/admin/schedules
+func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	sched := &model.ReviewSchedule{Enabled: true}
+	if err := applyScheduleRequest(sched, &req); err != nil {
+		respondAppError(c, err)
+		return
+	}
+
+	if err := h.store.Schedule().Create(sched); err != nil {
+		logger.Error("Failed to create schedule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    errors.ErrCodeDBQuery,
+			"message": "Failed to create schedule",
+		})
+		return
+	}
+
+	h.reloadSchedules()
+
+	logger.Info("Created review schedule",
+		zap.Uint("id", sched.ID),
+		zap.String("name", sched.Name),
+		zap.String("repo_url", sched.RepoURL),
+		zap.String("cron_expr", sched.CronExpr),
+	)
+
+	c.JSON(http.StatusCreated, h.toScheduleItem(*sched))
+}
+
+// UpdateSchedule handles PUT /api/v1/admin/schedules/:id
+func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
+	id, ok := parseScheduleID(c)
+	if !ok {
+		return
+	}
+
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	sched, err := h.store.Schedule().GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    errors.ErrCodeNotFound,
+			"message": "Schedule not found",
+		})
+		return
+	}
+
+	if err := applyScheduleRequest(sched, &req); err != nil {
+		respondAppError(c, err)
+		return
+	}
+
+	if err := h.store.Schedule().Save(sched); err != nil {
+		logger.Error("Failed to update schedule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    errors.ErrCodeDBQuery,
+			"message": "Failed to update schedule",
+		})
+		return
+	}
+
+	h.reloadSchedules()
+
+	logger.Info("Updated review schedule",
+		zap.Uint("id", id),
+		zap.String("cron_expr", sched.CronExpr),
+		zap.Bool("enabled", sched.Enabled),
+	)
+
+	c.JSON(http.StatusOK, h.toScheduleItem(*sched))
+}
+
+// DeleteSchedule handles DELETE /api/v1/admin/schedules/:id
+func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	id, ok := parseScheduleID(c)
+	if !ok {
+		return
+	}
+
+	sched, err := h.store.Schedule().GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    errors.ErrCodeNotFound,
+			"message": "Schedule not found",
+		})
+		return
+	}
+
+	if err := h.store.Schedule().Delete(id); err != nil {
+		logger.Error("Failed to delete schedule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    errors.ErrCodeDBQuery,
+			"message": "Failed to delete schedule",
+		})
+		return
+	}
+
+	h.reloadSchedules()
+
+	logger.Info("Deleted review schedule",
+		zap.Uint("id", id),
+		zap.String("name", sched.Name),
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Schedule deleted successfully",
+	})
+}
+
+// TriggerSchedule handles POST /api/v1/admin/schedules/:id/trigger
+// Runs the schedule immediately, independent of its cron expression.
+func (h *ScheduleHandler) TriggerSchedule(c *gin.Context) {
+	id, ok := parseScheduleID(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.engine.TriggerSchedule(id)
+	if err != nil {
+		respondAppError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, run)
+}
+
+// ListScheduleRuns handles GET /api/v1/admin/schedules/:id/runs
+// Supports optional limit query parameter (default 20, max 200).
+func (h *ScheduleHandler) ListScheduleRuns(c *gin.Context) {
+	id, ok := parseScheduleID(c)
+	if !ok {
+		return
+	}
+
+	limit := defaultScheduleRunsLimit
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if limit > maxScheduleRunsLimit {
+		limit = maxScheduleRunsLimit
+	}
+
+	if _, err := h.store.Schedule().GetByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    errors.ErrCodeNotFound,
+			"message": "Schedule not found",
+		})
+		return
+	}
+
+	runs, err := h.store.Schedule().ListRuns(id, limit)
+	if err != nil {
+		logger.Error("Failed to list schedule runs", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    errors.ErrCodeDBQuery,
+			"message": "Failed to list schedule runs",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  runs,
+		"total": len(runs),
+	})
+}
+
+// toScheduleItem converts a schedule to its API representation
+func (h *ScheduleHandler) toScheduleItem(sched model.ReviewSchedule) ScheduleItem {
+	item := ScheduleItem{ReviewSchedule: sched}
+	if !sched.Enabled {
+		return item
+	}
+
+	// Prefer the registered cron entry; fall back to computing from the expression
+	next := h.engine.GetScheduleNextRun(sched.ID)
+	if next == nil {
+		if t, err := scheduler.NextRunTime(sched.CronExpr, time.Now()); err == nil {
+			next = &t
+		}
+	}
+	if next != nil {
+		s := next.Format(time.RFC3339)
+		item.NextRunAt = &s
+	}
+	return item
+}
+
+// reloadSchedules re-registers cron entries after a schedule change
+func (h *ScheduleHandler) reloadSchedules() {
+	if err := h.engine.ReloadSchedules(); err != nil {
+		logger.Warn("Failed to reload schedules", zap.Error(err))
+	}
+}
+
+// applyScheduleRequest validates a schedule request and copies it onto sched
+func applyScheduleRequest(sched *model.ReviewSchedule, req *ScheduleRequest) error {
+	req.CronExpr = strings.TrimSpace(req.CronExpr)
+	if err := scheduler.ValidateCronExpr(req.CronExpr); err != nil {
+		return err
+	}
+
+	mode := model.ScheduleMode(req.Mode)
+	switch mode {
+	case "":
+		mode = model.ScheduleModeFull
+	case model.ScheduleModeFull, model.ScheduleModeIncremental:
+	default:
+		return errors.New(errors.ErrCodeValidation,
+			fmt.Sprintf("invalid mode %q, must be %q or %q", req.Mode, model.ScheduleModeFull, model.ScheduleModeIncremental))
+	}
+
+	if req.ReviewFile != "" {
+		if !validateFilename(req.ReviewFile) || !dsl.ReviewFileExists(req.ReviewFile) {
+			return errors.New(errors.ErrCodeValidation, "Review file does not exist: "+req.ReviewFile)
+		}
+	}
+
+	sched.Name = req.Name
+	sched.RepoURL = req.RepoURL
+	sched.Ref = req.Ref
+	sched.CronExpr = req.CronExpr
+	sched.ReviewFile = req.ReviewFile
+	sched.Mode = mode
+	sched.Description = req.Description
+	if req.Enabled != nil {
+		sched.Enabled = *req.Enabled
+	}
+	return nil
+}
+
+// parseScheduleID parses the :id path parameter, writing an error response on failure
+func parseScheduleID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    errors.ErrCodeValidation,
+			"message": "Invalid schedule ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
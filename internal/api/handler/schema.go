@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/verustcode/verustcode/internal/agent/base"
 	"github.com/verustcode/verustcode/internal/dsl"
 )
 
@@ -17,9 +18,10 @@ func NewSchemaHandler() *SchemaHandler {
 	return &SchemaHandler{}
 }
 
-// GetSchema returns the default JSON schema.
+// GetSchema returns a JSON schema.
 // GET /api/v1/schemas/:name
-// Note: Only "default" schema is supported since schemas are now embedded in code.
+// "default" is the output schema of review findings; "review-dsl" and "report-dsl"
+// are the schemas of review and report files, generated from the DSL.
 func (h *SchemaHandler) GetSchema(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
@@ -27,12 +29,15 @@ func (h *SchemaHandler) GetSchema(c *gin.Context) {
 		return
 	}
 
-	// Only "default" schema is supported (embedded in code)
-	if name != "default" && name != "default.json" {
-		c.JSON(http.StatusNotFound, gin.H{"error": "schema not found, only 'default' schema is available"})
+	if name == "default" || name == "default.json" {
+		c.JSON(http.StatusOK, dsl.GetDefaultJSONSchema())
 		return
 	}
 
-	schema := dsl.GetDefaultJSONSchema()
-	c.JSON(http.StatusOK, schema)
+	if schema := dsl.GetDSLSchema(name, dsl.DSLSchemaOptions{AgentTypes: base.List()}); schema != nil {
+		c.JSON(http.StatusOK, schema)
+		return
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{"error": "schema not found, available: default, review-dsl, report-dsl"})
 }
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestSchemaHandler_GetSchema tests serving the output and DSL schemas
+func TestSchemaHandler_GetSchema(t *testing.T) {
+	router := SetupTestRouter()
+	handler := NewSchemaHandler()
+	router.GET("/api/v1/schemas/:name", handler.GetSchema)
+
+	tests := []struct {
+		name      string
+		path      string
+		wantCode  int
+		wantTitle string
+	}{
+		{"default schema", "/api/v1/schemas/default", http.StatusOK, ""},
+		{"review DSL schema", "/api/v1/schemas/review-dsl.json", http.StatusOK, "VerustCode review file"},
+		{"report DSL schema", "/api/v1/schemas/report-dsl", http.StatusOK, "VerustCode report file"},
+		{"unknown schema", "/api/v1/schemas/unknown", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := CreateTestRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("GET %s = %d, want %d", tt.path, w.Code, tt.wantCode)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if tt.wantTitle != "" && body["title"] != tt.wantTitle {
+				t.Errorf("title = %v, want %q", body["title"], tt.wantTitle)
+			}
+		})
+	}
+}
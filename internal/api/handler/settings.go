@@ -16,6 +16,7 @@ import (
 	"github.com/verustcode/verustcode/internal/agent/base"
 	"github.com/verustcode/verustcode/internal/config"
 	"github.com/verustcode/verustcode/internal/git/provider"
+	"github.com/verustcode/verustcode/internal/llm"
 	"github.com/verustcode/verustcode/internal/model"
 	"github.com/verustcode/verustcode/internal/notification"
 	"github.com/verustcode/verustcode/internal/store"
@@ -96,6 +97,9 @@ func maskSettingsValue(key string, value interface{}) interface{} {
 					masked[k] = maskSettingsValue(k, val)
 				}
 				result[i] = masked
+			} else if str, ok := item.(string); ok && isSensitiveKey(key) && str != "" {
+				// Lists of secrets (like an agent's api_keys)
+				result[i] = maskSensitiveValue(str)
 			} else {
 				result[i] = item
 			}
@@ -445,10 +449,14 @@ func (h *SettingsHandler) getRealProviderCredentials(providerType string) (*conf
 type TestAgentRequest struct {
 	Name           string   `json:"name" binding:"required"`
 	CLIPath        string   `json:"cli_path"`
+	BaseURL        string   `json:"base_url"`
 	APIKey         string   `json:"api_key"`
 	DefaultModel   string   `json:"default_model"`
 	FallbackModels []string `json:"fallback_models"`
 	Timeout        int      `json:"timeout"`
+	ThinkingBudget int      `json:"thinking_budget"`
+
+	Sandbox *llm.SandboxConfig `json:"sandbox"`
 }
 
 // TestAgent tests an agent connection by running a simple prompt
@@ -486,10 +494,13 @@ func (h *SettingsHandler) TestAgent(c *gin.Context) {
 	// Create agent config object
 	agentDetail := config.AgentDetail{
 		CLIPath:        req.CLIPath,
+		BaseURL:        req.BaseURL,
 		APIKey:         req.APIKey,
 		DefaultModel:   req.DefaultModel,
 		FallbackModels: req.FallbackModels,
 		Timeout:        req.Timeout,
+		ThinkingBudget: req.ThinkingBudget,
+		Sandbox:        req.Sandbox,
 	}
 
 	// Marshal to JSON string
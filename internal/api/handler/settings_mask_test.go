@@ -180,6 +180,22 @@ func TestMaskSettingsValue(t *testing.T) {
 		}
 	})
 
+	t.Run("List of secrets under sensitive key", func(t *testing.T) {
+		input := map[string]interface{}{
+			"api_keys": []interface{}{"sk-first-secret-key", "sk-second-secret-key"},
+			"models":   []interface{}{"gpt-4o-mini-model"},
+		}
+
+		resultMap := maskSettingsValue("openai", input).(map[string]interface{})
+		keys := resultMap["api_keys"].([]interface{})
+		if keys[0] != "sk-f****-key" || keys[1] != "sk-s****-key" {
+			t.Errorf("api_keys should be masked, got %v", keys)
+		}
+		if models := resultMap["models"].([]interface{}); models[0] != "gpt-4o-mini-model" {
+			t.Errorf("models should be unchanged, got %v", models)
+		}
+	})
+
 	t.Run("Array of maps with sensitive fields", func(t *testing.T) {
 		input := []interface{}{
 			map[string]interface{}{
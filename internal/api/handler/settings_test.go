@@ -3,9 +3,13 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	// Import the Ollama agent to register it for TestAgent
+	_ "github.com/verustcode/verustcode/internal/agent/ollama"
 )
 
 // TestSettingsHandler_GetAllSettings tests getting all settings
@@ -234,3 +238,48 @@ func TestSettingsHandler_UpdateSettingsByCategory_GitCategory(t *testing.T) {
 	}
 }
 
+// TestSettingsHandler_TestAgent tests an API agent against an httptest stand-in for a local endpoint
+func TestSettingsHandler_TestAgent(t *testing.T) {
+	var model string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Model string `json:"model"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		model = body.Model
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"message":{"role":"assistant","content":"4"},"done":true}`)
+	}))
+	defer server.Close()
+
+	router := SetupTestRouter()
+	handler := NewSettingsHandler(NewMockStore())
+	router.POST("/api/admin/settings/agents/test", handler.TestAgent)
+
+	test := func(req TestAgentRequest) map[string]interface{} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, CreateTestRequest("POST", "/api/admin/settings/agents/test", req))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var response map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		return response
+	}
+
+	response := test(TestAgentRequest{Name: "ollama", BaseURL: server.URL, DefaultModel: "llama-test"})
+	if response["success"] != true || response["data"] != "4" {
+		t.Errorf("response = %v, want a successful test", response)
+	}
+	if model != "llama-test" {
+		t.Errorf("model = %s, want the configured model", model)
+	}
+
+	// An unreachable endpoint fails the test
+	response = test(TestAgentRequest{Name: "ollama", BaseURL: "http://127.0.0.1:1", Timeout: 1})
+	if response["success"] != false {
+		t.Errorf("response = %v, want a failed test", response)
+	}
+}
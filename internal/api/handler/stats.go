@@ -11,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"github.com/verustcode/verustcode/internal/engine/utils"
 	"github.com/verustcode/verustcode/internal/model"
 	"github.com/verustcode/verustcode/internal/store"
 	"github.com/verustcode/verustcode/pkg/errors"
@@ -96,6 +97,16 @@ func (h *StatsHandler) GetRepoStats(c *gin.Context) {
 	// Calculate issue statistics from review results
 	stats.IssueSeverityStats, stats.IssueCategoryStats = h.calculateIssueStats(reviewResults)
 
+	// Calculate token usage and cost of reviews and reports (all statuses, since failed runs are billed too)
+	usage, err := utils.LoadUsage(h.store, repoURL, startTime)
+	if err != nil {
+		logger.Warn("Failed to fetch token usage for statistics",
+			zap.Error(err),
+		)
+		// Continue without usage stats if this fails
+	}
+	stats.UsageStats, stats.TotalUsage = h.calculateUsageStats(usage, generateWeekRange(startTime, now))
+
 	c.JSON(http.StatusOK, stats)
 }
 
@@ -296,6 +307,28 @@ func (h *StatsHandler) calculateRevisionStats(weeklyData map[string][]model.Revi
 	return stats
 }
 
+// calculateUsageStats calculates token usage and cost per week and in total
+func (h *StatsHandler) calculateUsageStats(records []model.UsageRecord, allWeeks []string) ([]model.WeeklyUsage, model.UsageStats) {
+	weekly := make(map[string]model.TokenUsage)
+	var total model.TokenUsage
+	for _, r := range records {
+		week := getISOWeek(r.CreatedAt)
+		usage := weekly[week]
+		usage.Add(r.TokenUsage)
+		weekly[week] = usage
+		total.Add(r.TokenUsage)
+	}
+
+	result := make([]model.WeeklyUsage, 0, len(allWeeks))
+	for _, week := range allWeeks {
+		result = append(result, model.WeeklyUsage{
+			Week:       week,
+			UsageStats: model.NewUsageStats(weekly[week]),
+		})
+	}
+	return result, model.NewUsageStats(total)
+}
+
 // getISOWeek returns the ISO week string for a given time (format: "2024-W01")
 func getISOWeek(t time.Time) string {
 	year, week := t.ISOWeek()
@@ -496,3 +496,40 @@ func TestStatsHandler_percentile(t *testing.T) {
 		t.Errorf("P90 should be around 4.5, got %f", result)
 	}
 }
+
+// TestStatsHandler_calculateUsageStats tests weekly token usage and cost aggregation
+func TestStatsHandler_calculateUsageStats(t *testing.T) {
+	handler := NewStatsHandler(NewMockStore())
+
+	monday := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	records := []model.UsageRecord{
+		{CreatedAt: monday, TokenUsage: model.TokenUsage{PromptTokens: 1000, CompletionTokens: 200, CachedTokens: 400, Cost: 0.5}},
+		{CreatedAt: monday.AddDate(0, 0, 2), TokenUsage: model.TokenUsage{PromptTokens: 500, CompletionTokens: 100, Cost: 0.25}},
+		{CreatedAt: monday.AddDate(0, 0, 7), TokenUsage: model.TokenUsage{PromptTokens: 100, CompletionTokens: 50, Cost: 0.1}},
+	}
+	weeks := []string{"2024-W02", "2024-W03", "2024-W04"}
+
+	weekly, total := handler.calculateUsageStats(records, weeks)
+
+	if len(weekly) != len(weeks) {
+		t.Fatalf("Expected %d weeks, got %d", len(weeks), len(weekly))
+	}
+	if weekly[0].TotalTokens != 0 || weekly[0].Cost != 0 {
+		t.Errorf("Expected empty usage for %s, got %+v", weeks[0], weekly[0])
+	}
+	if weekly[1].PromptTokens != 1500 || weekly[1].CompletionTokens != 300 || weekly[1].CachedTokens != 400 {
+		t.Errorf("Unexpected usage for %s: %+v", weeks[1], weekly[1])
+	}
+	if weekly[1].TotalTokens != 1800 {
+		t.Errorf("Expected 1800 total tokens for %s, got %d", weeks[1], weekly[1].TotalTokens)
+	}
+	if weekly[2].TotalTokens != 150 {
+		t.Errorf("Expected 150 total tokens for %s, got %d", weeks[2], weekly[2].TotalTokens)
+	}
+	if total.TotalTokens != 1950 {
+		t.Errorf("Expected 1950 total tokens, got %d", total.TotalTokens)
+	}
+	if total.Cost < 0.849 || total.Cost > 0.851 {
+		t.Errorf("Expected total cost 0.85, got %f", total.Cost)
+	}
+}
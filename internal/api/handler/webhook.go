@@ -101,19 +101,54 @@ func (h *WebhookHandler) HandleWebhook(c *gin.Context) {
 }
 
 // handlePushEvent handles push webhook events
+// The review covers the pushed range (before..after) and is attributed to the head commit author.
 func (h *WebhookHandler) handlePushEvent(c *gin.Context, event *provider.WebhookEvent) {
+	// Branch deletions have nothing to review
+	if event.IsBranchDeletion() {
+		logger.Info("Push event deleted branch, skipping review",
+			zap.String("provider", event.Provider),
+			zap.String("repo", event.Owner+"/"+event.Repo),
+			zap.String("ref", event.Ref),
+		)
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Branch deletion, skipping review",
+			"ref":     event.Ref,
+		})
+		return
+	}
+
 	// Build repository URL from provider configuration or default
 	repoURL := h.buildRepoURL(event)
 
+	// The previous push is the base of the diff range.
+	// A newly created branch has no previous push, so the whole branch head is reviewed.
+	baseSHA := event.BeforeSHA
+	if provider.IsNullSHA(baseSHA) {
+		baseSHA = ""
+	}
+
+	// Attribute the review to the author of the head commit, falling back to the pusher
+	author := event.Sender
+	var headMessage string
+	if head := event.HeadCommit(); head != nil {
+		if a := head.Author(); a != "" {
+			author = a
+		}
+		headMessage = head.Message
+	}
+
 	// Create review with unique ID
 	review := &model.Review{
-		ID:          idgen.NewReviewID(),
-		RepoURL:     repoURL,
-		Ref:         event.Ref,
-		CommitSHA:   event.CommitSHA,
-		Status:      model.ReviewStatusPending,
-		Source:      "webhook",
-		TriggeredBy: event.Sender,
+		ID:            idgen.NewReviewID(),
+		RepoURL:       repoURL,
+		Ref:           event.Ref,
+		CommitSHA:     event.CommitSHA,
+		BaseCommitSHA: baseSHA,
+		Status:        model.ReviewStatusPending,
+		Source:        "webhook",
+		TriggeredBy:   event.Sender,
+		Author:        author,
+		CommitCount:   len(event.Commits),
 	}
 
 	if err := h.store.Review().Create(review); err != nil {
@@ -135,8 +170,14 @@ func (h *WebhookHandler) handlePushEvent(c *gin.Context, event *provider.Webhook
 		)
 	}
 
-	// Submit to engine (push event, no PR info)
-	_, err := h.engine.Submit(review, nil)
+	// Submit to engine with the pushed range; push events have no PR number
+	prInfo := &engine.PRInfo{
+		Title:        firstLine(headMessage),
+		PushCommits:  formatPushedCommits(event.Commits),
+		BaseSHA:      baseSHA,
+		ChangedFiles: event.ChangedFiles,
+	}
+	_, err := h.engine.Submit(review, prInfo)
 	if err != nil {
 		if dbErr := h.store.Review().UpdateStatusWithError(review.ID, model.ReviewStatusFailed, err.Error()); dbErr != nil {
 			logger.Error("Failed to update review status after engine submission failure", zap.Error(dbErr))
@@ -457,3 +498,39 @@ func (h *WebhookHandler) handlePRMergedEvent(c *gin.Context, event *provider.Web
 		"affected_rows": rowsAffected,
 	})
 }
+
+// maxPushedCommitsListed caps the number of commits listed for a push review
+const maxPushedCommitsListed = 50
+
+// formatPushedCommits formats pushed commits as a list of "short-sha subject (author)" lines
+func formatPushedCommits(commits []provider.PushCommit) string {
+	if len(commits) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for i, commit := range commits {
+		if i == maxPushedCommitsListed {
+			sb.WriteString(fmt.Sprintf("- ... and %d more commits\n", len(commits)-maxPushedCommitsListed))
+			break
+		}
+		sha := commit.SHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		sb.WriteString(fmt.Sprintf("- %s %s", sha, firstLine(commit.Message)))
+		if author := commit.Author(); author != "" {
+			sb.WriteString(fmt.Sprintf(" (%s)", author))
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// firstLine returns the first line of a commit message
+func firstLine(message string) string {
+	if idx := strings.IndexByte(message, '\n'); idx >= 0 {
+		message = message[:idx]
+	}
+	return strings.TrimSpace(message)
+}
@@ -72,6 +72,14 @@ func (m *MockProvider) UpdateComment(ctx context.Context, owner, repo string, co
 	return nil
 }
 
+func (m *MockProvider) ListCommitComments(ctx context.Context, owner, repo, commitSHA string) ([]*provider.Comment, error) {
+	return nil, nil
+}
+
+func (m *MockProvider) UpdateCommitComment(ctx context.Context, owner, repo, commitSHA string, commentID int64, body string) error {
+	return nil
+}
+
 func (m *MockProvider) ParseWebhook(req *http.Request, secret string) (*provider.WebhookEvent, error) {
 	if m.parseWebhookFunc != nil {
 		return m.parseWebhookFunc(req, secret)
@@ -419,3 +427,18 @@ func TestWebhookHandler_HandleWebhook_NoSecret(t *testing.T) {
 		t.Errorf("Expected status 400 or 404, got %d", w.Code)
 	}
 }
+
+func TestFormatPushedCommits(t *testing.T) {
+	commits := []provider.PushCommit{
+		{SHA: "1111111aaaa", Message: "Add feature\n\nLong body", AuthorLogin: "alice"},
+		{SHA: "2222222bbbb", Message: "Fix typo", AuthorName: "Bob"},
+	}
+
+	assert.Equal(t, "- 1111111 Add feature (alice)\n- 2222222 Fix typo (Bob)", formatPushedCommits(commits))
+	assert.Equal(t, "", formatPushedCommits(nil))
+}
+
+func TestFirstLine(t *testing.T) {
+	assert.Equal(t, "Subject", firstLine("Subject\n\nBody"))
+	assert.Equal(t, "Only", firstLine("  Only  "))
+}
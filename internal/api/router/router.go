@@ -58,6 +58,7 @@ func SetupWithConfigPath(r *gin.Engine, e *engine.Engine, re *report.Engine, cfg
 
 	// Initialize admin handler
 	adminHandler := handler.NewAdminHandler(cfg, configPath, s)
+	adminHandler.SetEngine(e)
 
 	// Webhook routes (public - requires webhook secret validation instead)
 	webhookHandler := handler.NewWebhookHandler(e, s)
@@ -93,6 +94,7 @@ func SetupWithConfigPath(r *gin.Engine, e *engine.Engine, re *report.Engine, cfg
 			"total_running": stats.TotalRunning,
 			"repo_count":    stats.RepoCount,
 			"repos":         stats.RepoStats,
+			"agent_calls":   e.GetAgentPoolStatuses(),
 		})
 	})
 
@@ -106,6 +108,7 @@ func SetupWithConfigPath(r *gin.Engine, e *engine.Engine, re *report.Engine, cfg
 		reviews.POST("/:id/cancel", reviewHandler.CancelReview)
 		reviews.POST("/:id/retry", reviewHandler.RetryReview)
 		reviews.POST("/:id/rules/:rule_id/retry", reviewHandler.RetryReviewRule) // Retry single rule
+		reviews.POST("/:id/baseline", reviewHandler.GenerateBaseline)            // Generate baseline file from findings
 	}
 
 	// Report routes - protected by JWT authentication
@@ -175,6 +178,8 @@ func SetupWithConfigPath(r *gin.Engine, e *engine.Engine, re *report.Engine, cfg
 		admin.POST("/rules", rulesHandler.CreateRuleFile)
 		admin.GET("/rules/:name", rulesHandler.GetRule)
 		admin.PUT("/rules/:name", rulesHandler.SaveRule)
+		admin.GET("/rules/:name/resolved", rulesHandler.GetResolvedRule)
+		admin.POST("/rules/:name/preview", rulesHandler.PreviewPrompt)
 		admin.POST("/rules/validate", rulesHandler.ValidateRule)
 
 		// Review files list (available review config files)
@@ -205,6 +210,42 @@ func SetupWithConfigPath(r *gin.Engine, e *engine.Engine, re *report.Engine, cfg
 		admin.POST("/settings/agents/test", settingsHandler.TestAgent)
 		admin.POST("/settings/notifications/test", settingsHandler.TestNotificationConfig)
 
+		// Queue administration (priority lanes, pause/resume, maintenance mode)
+		queueHandler := handler.NewQueueHandler(e)
+		admin.GET("/queue", queueHandler.ListQueue)
+		admin.POST("/queue/tasks/:id/front", queueHandler.MoveToFront)
+		admin.POST("/queue/repos/pause", queueHandler.PauseRepo)
+		admin.POST("/queue/repos/resume", queueHandler.ResumeRepo)
+		admin.PUT("/queue/maintenance", queueHandler.SetMaintenance)
+
+		// Scheduled audits (cron-style repository reviews)
+		scheduleHandler := handler.NewScheduleHandler(e, s)
+		admin.GET("/schedules", scheduleHandler.ListSchedules)
+		admin.POST("/schedules", scheduleHandler.CreateSchedule)
+		admin.GET("/schedules/:id", scheduleHandler.GetSchedule)
+		admin.PUT("/schedules/:id", scheduleHandler.UpdateSchedule)
+		admin.DELETE("/schedules/:id", scheduleHandler.DeleteSchedule)
+		admin.POST("/schedules/:id/trigger", scheduleHandler.TriggerSchedule)
+		admin.GET("/schedules/:id/runs", scheduleHandler.ListScheduleRuns)
+
+		// Review areas (built-in and organization-wide custom areas)
+		areaHandler := handler.NewAreaHandler(e, s)
+		admin.GET("/areas", areaHandler.ListAreas)
+		admin.POST("/areas", areaHandler.CreateArea)
+		admin.PUT("/areas/:id", areaHandler.UpdateArea)
+		admin.DELETE("/areas/:id", areaHandler.DeleteArea)
+
+		// Response cache of agent results
+		cacheHandler := handler.NewCacheHandler(s)
+		admin.DELETE("/cache", cacheHandler.PurgeCache)
+
+		// Recorded agent interactions
+		cassetteHandler := handler.NewCassetteHandler(s)
+		admin.GET("/reviews/:id/cassette", cassetteHandler.DownloadCassette)
+
+		// Approval of reviews quarantined because of a possible prompt injection
+		admin.POST("/reviews/:id/approve", reviewHandler.ApproveReview)
+
 		// Notification management
 		notificationHandler := handler.NewNotificationHandler()
 		admin.GET("/notifications/status", notificationHandler.GetNotificationStatus)
@@ -0,0 +1,86 @@
+// Package cassette builds downloadable cassettes of recorded reviews and replays them offline.
+//
+// A cassette holds the reviewed change, the configuration snapshots of the review's rules and
+// every recorded agent interaction: the exact prompt, agent, model, head commit SHA and raw
+// stream of chunks. Interactions are recorded when the recording review setting is enabled.
+package cassette
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/store"
+)
+
+// ErrNotRecorded is returned by Build if no agent interaction was recorded for the review
+var ErrNotRecorded = errors.New("no agent interactions recorded for review")
+
+// Build returns the cassette of a review from its recorded interactions.
+// Returns gorm.ErrRecordNotFound if the review does not exist, or ErrNotRecorded.
+func Build(s store.Store, reviewID string) (*model.Cassette, error) {
+	review, err := s.Review().GetByID(reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	interactions, err := s.Cassette().ListByReviewID(reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recorded interactions: %w", err)
+	}
+	if len(interactions) == 0 {
+		return nil, ErrNotRecorded
+	}
+
+	rules, err := s.Review().GetRulesByReviewID(reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load review rules: %w", err)
+	}
+
+	c := &model.Cassette{
+		Version:       model.CassetteVersion,
+		ReviewID:      review.ID,
+		RepoURL:       review.RepoURL,
+		Ref:           review.Ref,
+		CommitSHA:     review.CommitSHA,
+		BaseCommitSHA: review.BaseCommitSHA,
+		PRNumber:      review.PRNumber,
+		Author:        review.Author,
+		Rules:         make([]model.CassetteRule, 0, len(rules)),
+		Interactions:  interactions,
+	}
+
+	// PR metadata is not stored on the review, only on the requests sent to agents
+	for _, interaction := range interactions {
+		if interaction.PRTitle != "" || len(interaction.ChangedFiles) > 0 {
+			c.PRTitle = interaction.PRTitle
+			c.PRDescription = interaction.PRDescription
+			c.ChangedFiles = interaction.ChangedFiles
+			break
+		}
+	}
+
+	for _, rule := range rules {
+		c.Rules = append(c.Rules, model.CassetteRule{RuleID: rule.RuleID, RuleConfig: rule.RuleConfig})
+	}
+	return c, nil
+}
+
+// Load reads a cassette file
+func Load(path string) (*model.Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var c model.Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	if c.Version != model.CassetteVersion {
+		return nil, fmt.Errorf("cassette %s has unsupported version %d (expected %d)", path, c.Version, model.CassetteVersion)
+	}
+	return &c, nil
+}
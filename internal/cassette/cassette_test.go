@@ -0,0 +1,109 @@
+package cassette
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/engine/utils"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/store"
+)
+
+const findingsOutput = `{"summary":"ok","findings":[{"severity":"high","title":"SQL injection","description":"d","category":"security"}]}`
+
+// recordReview stores a review with a rule and its recorded interactions
+func recordReview(t *testing.T, s store.Store, interactions ...*model.CassetteInteraction) {
+	t.Helper()
+	require.NoError(t, s.Review().Create(&model.Review{
+		ID:        "review-1",
+		Ref:       "main",
+		CommitSHA: "abc123",
+		RepoURL:   "https://github.com/org/repo",
+		PRNumber:  7,
+		Status:    model.ReviewStatusCompleted,
+	}))
+
+	ruleConfig, err := utils.RuleConfigToJSONMap(&dsl.ReviewRuleConfig{
+		ID:    "security",
+		Agent: dsl.AgentConfig{Type: "cursor"},
+		Goals: dsl.GoalsConfig{Areas: []string{"security-vulnerabilities"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.Review().CreateRule(&model.ReviewRule{ReviewID: "review-1", RuleID: "security", RuleConfig: ruleConfig}))
+
+	for _, interaction := range interactions {
+		require.NoError(t, s.Cassette().Record(interaction))
+	}
+}
+
+func TestBuild(t *testing.T) {
+	s, cleanup := store.SetupTestDB(t)
+	defer cleanup()
+
+	_, err := Build(s, "missing")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	recordReview(t, s)
+	_, err = Build(s, "review-1")
+	assert.ErrorIs(t, err, ErrNotRecorded)
+
+	require.NoError(t, s.Cassette().Record(&model.CassetteInteraction{
+		ReviewID: "review-1", RuleID: "security", Agent: "cursor",
+		PRTitle: "Add login", ChangedFiles: model.StringArray{"login.go"}, Content: findingsOutput,
+	}))
+	c, err := Build(s, "review-1")
+	require.NoError(t, err)
+	assert.Equal(t, model.CassetteVersion, c.Version)
+	assert.Equal(t, "abc123", c.CommitSHA)
+	assert.Equal(t, 7, c.PRNumber)
+	assert.Equal(t, "Add login", c.PRTitle)
+	assert.Equal(t, []string{"login.go"}, c.ChangedFiles)
+	require.Len(t, c.Rules, 1)
+	assert.Equal(t, "security", c.Rules[0].RuleID)
+	assert.Len(t, c.Interactions, 1)
+
+	// A downloaded cassette can be loaded back
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "review-1.cassette.json")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, c.Interactions[0].Content, loaded.Interactions[0].Content)
+}
+
+func TestReplay(t *testing.T) {
+	s, cleanup := store.SetupTestDB(t)
+	defer cleanup()
+	recordReview(t, s, &model.CassetteInteraction{
+		ReviewID: "review-1", RuleID: "security", Agent: "cursor", Model: "sonnet",
+		Prompt: "recorded prompt", Content: findingsOutput,
+	})
+	c, err := Build(s, "review-1")
+	require.NoError(t, err)
+
+	result, err := Replay(context.Background(), c, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "review-1", result.ReviewID)
+	require.Len(t, result.Rules, 1)
+	rule := result.Rules[0]
+	assert.Equal(t, "security", rule.RuleID)
+	assert.Equal(t, string(model.RuleStatusCompleted), rule.Status, rule.Error)
+	assert.Equal(t, "sonnet", rule.Model)
+	require.Len(t, rule.Findings, 1)
+	assert.Equal(t, "SQL injection", rule.Findings[0]["title"])
+
+	// The current pipeline renders another prompt than the recorded one
+	require.Len(t, result.Mismatches, 1)
+	assert.Equal(t, "recorded prompt", result.Mismatches[0].Recorded)
+	assert.Empty(t, result.Unserved)
+}
@@ -0,0 +1,330 @@
+// Package cassette builds downloadable cassettes of recorded reviews and replays them offline.
+// This file contains the offline replay of a cassette through the review pipeline.
+package cassette
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/engine/executor"
+	"github.com/verustcode/verustcode/internal/engine/runner"
+	"github.com/verustcode/verustcode/internal/llm"
+	"github.com/verustcode/verustcode/internal/llm/replay"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/prompt"
+	"github.com/verustcode/verustcode/internal/store"
+	"github.com/verustcode/verustcode/pkg/idgen"
+)
+
+// ReplayOptions configures a replay
+type ReplayOptions struct {
+	// RepoPath is a checkout of the reviewed commit used as workspace (optional).
+	// Without it, prompts that embed workspace content differ from the recorded ones.
+	RepoPath string
+
+	// Rules replaces the recorded rule configurations, e.g. with the current review file (optional)
+	Rules *dsl.ReviewRulesConfig
+}
+
+// ReplayResult is the outcome of replaying a cassette
+type ReplayResult struct {
+	// ReviewID is the recorded review
+	ReviewID string `json:"review_id"`
+
+	// Rules holds the outcome of each rule, in rule order
+	Rules []RuleOutcome `json:"rules"`
+
+	// Mismatches lists the agent calls whose prompt differs from the recorded prompt
+	Mismatches []replay.Mismatch `json:"mismatches,omitempty"`
+
+	// Unserved counts the recorded interactions of each rule no agent call was answered with
+	Unserved map[string]int `json:"unserved,omitempty"`
+}
+
+// RuleOutcome is the outcome of a replayed rule
+type RuleOutcome struct {
+	RuleID     string `json:"rule_id"`
+	Status     string `json:"status"`
+	SkipReason string `json:"skip_reason,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Model      string `json:"model,omitempty"`
+
+	// Findings lists the findings of the rule's result
+	Findings []map[string]interface{} `json:"findings,omitempty"`
+}
+
+// Replay runs the cassette's review through the current pipeline (prompt rendering, output
+// validation, policies and suppressions), with every agent answering from the cassette.
+// Nothing is published: rule outputs are dropped.
+func Replay(ctx context.Context, c *model.Cassette, opts *ReplayOptions) (*ReplayResult, error) {
+	if opts == nil {
+		opts = &ReplayOptions{}
+	}
+
+	rules, err := replayRules(c, opts.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	workDir, err := os.MkdirTemp("", "verustcode-replay-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	repoPath := opts.RepoPath
+	if repoPath == "" {
+		repoPath = filepath.Join(workDir, "repo")
+		if err := os.MkdirAll(repoPath, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create workspace: %w", err)
+		}
+	}
+
+	s, closeStore, err := openStore(filepath.Join(workDir, "replay.db"))
+	if err != nil {
+		return nil, err
+	}
+	defer closeStore()
+
+	client := replay.NewClient(c)
+	exec := executor.NewExecutor(&config.Config{}, replayAgents(rules, client), prompt.NewBuilder(), s)
+	r := runner.NewRunner(&config.Config{}, s, exec, prompt.NewBuilder())
+
+	now := time.Now()
+	review := &model.Review{
+		ID:            idgen.NewReviewID(),
+		Ref:           c.Ref,
+		CommitSHA:     c.CommitSHA,
+		BaseCommitSHA: c.BaseCommitSHA,
+		PRNumber:      c.PRNumber,
+		RepoURL:       c.RepoURL,
+		RepoPath:      repoPath,
+		Source:        "cli",
+		Author:        c.Author,
+		Status:        model.ReviewStatusRunning,
+		StartedAt:     &now,
+		FilesChanged:  len(c.ChangedFiles),
+	}
+	if err := s.Review().Create(review); err != nil {
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+
+	req := &runner.ReviewRequest{
+		RepoPath:          repoPath,
+		RepoURL:           c.RepoURL,
+		Ref:               c.Ref,
+		CommitSHA:         c.CommitSHA,
+		PRNumber:          c.PRNumber,
+		PRTitle:           c.PRTitle,
+		PRDescription:     c.PRDescription,
+		BaseCommitSHA:     c.BaseCommitSHA,
+		Source:            review.Source,
+		Author:            c.Author,
+		FilesChanged:      len(c.ChangedFiles),
+		ChangedFiles:      c.ChangedFiles,
+		Commits:           []string{c.CommitSHA},
+		ReviewRulesConfig: rules,
+		OutputDir:         filepath.Join(workDir, "output"),
+	}
+
+	// Rule failures are reported through the rule outcomes
+	_, _ = r.RunReviewWithTracking(ctx, req, review, nil)
+
+	result := &ReplayResult{
+		ReviewID:   c.ReviewID,
+		Mismatches: client.Mismatches(),
+		Unserved:   client.Unserved(),
+	}
+	if result.Rules, err = collectOutcomes(s, review.ID, rules); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// replayRules returns the rules to replay without outputs: the given rules, or the
+// recorded rule configurations
+func replayRules(c *model.Cassette, rules *dsl.ReviewRulesConfig) (*dsl.ReviewRulesConfig, error) {
+	replayed := &dsl.ReviewRulesConfig{}
+	if rules != nil {
+		copied := *rules
+		copied.Rules = append([]dsl.ReviewRuleConfig(nil), rules.Rules...)
+		replayed = &copied
+	} else {
+		for _, recorded := range c.Rules {
+			data, err := json.Marshal(recorded.RuleConfig)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read configuration of rule %s: %w", recorded.RuleID, err)
+			}
+			var rule dsl.ReviewRuleConfig
+			if err := json.Unmarshal(data, &rule); err != nil {
+				return nil, fmt.Errorf("failed to read configuration of rule %s: %w", recorded.RuleID, err)
+			}
+			replayed.Rules = append(replayed.Rules, rule)
+		}
+	}
+	if len(replayed.Rules) == 0 {
+		return nil, fmt.Errorf("cassette of review %s has no rules to replay", c.ReviewID)
+	}
+
+	for i := range replayed.Rules {
+		replayed.Rules[i].Output = nil
+	}
+	return replayed, nil
+}
+
+// openStore opens a SQLite database for the replay
+func openStore(path string) (store.Store, func(), error) {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := db.AutoMigrate(model.AllModels()...); err != nil {
+		return nil, nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	closeFn := func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}
+	return store.NewStore(db), closeFn, nil
+}
+
+// replayAgents returns a replay agent under the name of every agent referenced by the rules
+func replayAgents(rulesConfig *dsl.ReviewRulesConfig, client *replay.Client) map[string]base.Agent {
+	agents := make(map[string]base.Agent)
+	for i := range rulesConfig.Rules {
+		rule := &rulesConfig.Rules[i]
+		for _, name := range append([]string{rule.Agent.GetType()}, rule.Agent.Fallback...) {
+			if name != "" {
+				agents[name] = &replayAgent{name: name, client: client}
+			}
+		}
+	}
+	return agents
+}
+
+// replayAgent is an agent answering from the cassette
+type replayAgent struct {
+	name   string
+	client *replay.Client
+}
+
+// Name returns the name of the recorded agent
+func (a *replayAgent) Name() string {
+	return a.name
+}
+
+// Version returns the agent version
+func (a *replayAgent) Version() string {
+	return replay.ClientName
+}
+
+// Available always returns true for replay agents
+func (a *replayAgent) Available() bool {
+	return true
+}
+
+// SetStore sets the database store (replay agents don't use it)
+func (a *replayAgent) SetStore(s store.Store) {}
+
+// ExecuteWithPrompt answers the prompt with the rule's next recorded interaction
+func (a *replayAgent) ExecuteWithPrompt(ctx context.Context, req *base.ReviewRequest, promptText string) (*base.ReviewResult, error) {
+	result := base.NewResult(req.RequestID, a.name)
+	result.StartedAt = time.Now()
+
+	llmReq := llm.NewRequest(promptText).
+		WithModel(req.Model).
+		WithOptions(&llm.RequestOptions{Metadata: map[string]string{"rule_id": req.RuleID}})
+
+	resp, err := a.client.ExecuteStream(ctx, llmReq, nil)
+	result.CompletedAt = time.Now()
+	result.Duration = result.CompletedAt.Sub(result.StartedAt)
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		return result, &base.AgentError{Agent: a.name, Message: "replay failed", Err: err}
+	}
+
+	result.Text = resp.Content
+	result.ModelName = resp.Model
+	result.Usage = resp.Usage
+	result.SessionID = resp.SessionID
+	return result, nil
+}
+
+// collectOutcomes reads the outcome of each rule from the store
+func collectOutcomes(s store.Store, reviewID string, rulesConfig *dsl.ReviewRulesConfig) ([]RuleOutcome, error) {
+	reviewRules, err := s.Review().GetRulesByReviewID(reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load review rules: %w", err)
+	}
+	byID := make(map[string]*model.ReviewRule, len(reviewRules))
+	for i := range reviewRules {
+		byID[reviewRules[i].RuleID] = &reviewRules[i]
+	}
+
+	outcomes := make([]RuleOutcome, 0, len(rulesConfig.Rules))
+	for _, rule := range rulesConfig.Rules {
+		outcome := RuleOutcome{RuleID: rule.ID, Status: string(model.RuleStatusPending)}
+		reviewRule, ok := byID[rule.ID]
+		if !ok {
+			outcomes = append(outcomes, outcome)
+			continue
+		}
+
+		outcome.Status = string(reviewRule.Status)
+		outcome.SkipReason = reviewRule.SkipReason
+		outcome.Error = reviewRule.ErrorMessage
+		outcome.Model = reviewRule.Model
+
+		results, err := s.Review().GetResultsByRuleID(reviewRule.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load results of rule %s: %w", rule.ID, err)
+		}
+		for _, res := range results {
+			outcome.Findings = append(outcome.Findings, extractFindings(res.Data)...)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes, nil
+}
+
+// extractFindings returns the findings array of a result
+func extractFindings(data model.JSONMap) []map[string]interface{} {
+	items, ok := data["findings"].([]interface{})
+	if !ok {
+		return nil
+	}
+	findings := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			findings = append(findings, m)
+		}
+	}
+	return findings
+}
+
+// UnservedRuleIDs returns the rules with unserved interactions in order
+func (r *ReplayResult) UnservedRuleIDs() []string {
+	ids := make([]string, 0, len(r.Unserved))
+	for id := range r.Unserved {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -13,8 +13,11 @@ import (
 	"github.com/verustcode/verustcode/internal/llm"
 
 	// Import LLM client implementations to register their factories
+	_ "github.com/verustcode/verustcode/internal/llm/anthropic"
 	_ "github.com/verustcode/verustcode/internal/llm/cursor"
 	_ "github.com/verustcode/verustcode/internal/llm/gemini"
+	_ "github.com/verustcode/verustcode/internal/llm/ollama"
+	_ "github.com/verustcode/verustcode/internal/llm/openai"
 	_ "github.com/verustcode/verustcode/internal/llm/qoder"
 )
 
@@ -33,6 +36,7 @@ type AgentValidationResult struct {
 	CLIAvailable bool
 	APIKeySet    bool
 	CLIPath      string
+	Endpoint     string // API endpoint (API agents only)
 	Error        error
 }
 
@@ -210,6 +214,12 @@ func (c *Checker) validateAgents(agentNames []string, cfg *config.Config) []Agen
 			continue
 		}
 
+		// API agents talk HTTP to their endpoint and need no CLI tool
+		if isAPIAgent(agentName) {
+			results = append(results, validateAPIAgent(result, agentDetail))
+			continue
+		}
+
 		// Check CLI path
 		cliPath := agentDetail.CLIPath
 		if cliPath == "" {
@@ -266,6 +276,39 @@ func (c *Checker) validateAgents(agentNames []string, cfg *config.Config) []Agen
 	return results
 }
 
+// isAPIAgent returns true for agents that call an HTTP API instead of a CLI tool
+func isAPIAgent(agentName string) bool {
+	switch agentName {
+	case "openai", "anthropic", "ollama":
+		return true
+	default:
+		return false
+	}
+}
+
+// validateAPIAgent validates the configuration of an API agent.
+// Hosted APIs require an API key unless a custom endpoint (e.g. a model gateway) is configured,
+// local endpoints (Ollama) require nothing.
+func validateAPIAgent(result AgentValidationResult, agentDetail *config.AgentDetail) AgentValidationResult {
+	result.Endpoint = agentDetail.BaseURL
+	result.APIKeySet = agentDetail.APIKey != ""
+
+	llmConfig := llm.NewClientConfig(result.AgentName).
+		WithBaseURL(agentDetail.BaseURL).
+		WithAPIKey(agentDetail.APIKey)
+	client, err := llm.Create(result.AgentName, llmConfig)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to create LLM client for agent '%s': %v", result.AgentName, err)
+		return result
+	}
+	defer client.Close()
+
+	if !client.Available() {
+		result.Error = fmt.Errorf("API key or base URL not configured for agent '%s' in settings", result.AgentName)
+	}
+	return result
+}
+
 // getDefaultCLIName returns the default CLI command name for an agent
 func getDefaultCLIName(agentName string) string {
 	switch agentName {
@@ -291,6 +334,12 @@ func (c *Checker) printAgentValidationResults(results []AgentValidationResult) {
 	for _, r := range results {
 		if r.Error != nil {
 			red.Printf("  ✗ %s: %v\n", r.AgentName, r.Error)
+		} else if isAPIAgent(r.AgentName) {
+			endpoint := r.Endpoint
+			if endpoint == "" {
+				endpoint = "default"
+			}
+			green.Printf("  ✓ %s (Endpoint: %s)\n", r.AgentName, endpoint)
 		} else {
 			green.Printf("  ✓ %s (CLI: %s, API Key: configured)\n", r.AgentName, r.CLIPath)
 		}
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/verustcode/verustcode/internal/config"
 )
 
 // TestValidateBootstrapYaml tests validateBootstrapYaml
@@ -234,6 +236,38 @@ func TestGetDefaultCLIName(t *testing.T) {
 	}
 }
 
+// TestValidateAPIAgent tests validateAPIAgent
+func TestValidateAPIAgent(t *testing.T) {
+	c := &Checker{}
+	cfg := &config.Config{Agents: map[string]config.AgentDetail{
+		"openai": {BaseURL: "http://gateway.internal/v1"},
+	}}
+
+	results := c.validateAgents([]string{"openai"}, cfg)
+	if len(results) != 1 || results[0].Error != nil {
+		t.Fatalf("validateAgents() = %+v, want openai valid without CLI", results)
+	}
+	if results[0].Endpoint != "http://gateway.internal/v1" {
+		t.Errorf("Endpoint = %s", results[0].Endpoint)
+	}
+
+	// Without API key and endpoint
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	for _, name := range []string{"openai", "anthropic"} {
+		result := validateAPIAgent(AgentValidationResult{AgentName: name}, &config.AgentDetail{})
+		if result.Error == nil {
+			t.Errorf("validateAPIAgent(%s) should fail without API key and base URL", name)
+		}
+	}
+
+	// A local Ollama endpoint needs no configuration
+	result := validateAPIAgent(AgentValidationResult{AgentName: "ollama"}, &config.AgentDetail{})
+	if result.Error != nil {
+		t.Errorf("validateAPIAgent(ollama) error = %v", result.Error)
+	}
+}
+
 // TestValidationResult tests ValidationResult struct
 func TestValidationResult(t *testing.T) {
 	result := ValidationResult{
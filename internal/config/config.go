@@ -4,13 +4,17 @@ package config
 
 import (
 	"os"
+	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/verustcode/verustcode/consts"
+	"github.com/verustcode/verustcode/internal/llm"
 	"github.com/verustcode/verustcode/pkg/logger"
 	"github.com/verustcode/verustcode/pkg/telemetry"
 )
@@ -44,6 +48,8 @@ const (
 	RepoRootReviewPath = ".verust-review.yaml"
 	// RepoEmbeddedReviewPath is the path for embedded review config in repositories (deprecated, use RepoRootReviewPath)
 	RepoEmbeddedReviewPath = ".verustcode/review.yaml"
+	// RepoBaselinePath is the path of the baseline file of accepted findings in repositories
+	RepoBaselinePath = ".verustcode/baseline.yaml"
 )
 
 // ReviewFilePath returns the full path to the default review configuration file
@@ -107,10 +113,45 @@ type ProviderConfig struct {
 // AgentDetail holds specific agent configuration
 type AgentDetail struct {
 	CLIPath        string   `yaml:"cli_path" json:"cli_path"`
+	BaseURL        string   `yaml:"base_url" json:"base_url"` // API endpoint (API agents only)
 	APIKey         string   `yaml:"api_key" json:"api_key"`
 	Timeout        int      `yaml:"timeout" json:"timeout"`                 // seconds
 	DefaultModel   string   `yaml:"default_model" json:"default_model"`     // default model to use
 	FallbackModels []string `yaml:"fallback_models" json:"fallback_models"` // fallback model list
+	ThinkingBudget int      `yaml:"thinking_budget" json:"thinking_budget"` // extended thinking token budget (API agents only)
+
+	// APIKeys are additional API keys of the agent; calls are spread across APIKey and APIKeys
+	APIKeys []string `yaml:"api_keys,omitempty" json:"api_keys,omitempty"`
+
+	// Limits bounds the concurrency and rate of calls to the agent
+	Limits AgentLimits `yaml:"limits" json:"limits"`
+
+	// Sandbox isolates the agent CLI from the server (CLI agents only): read-only workspace,
+	// no access to data/ and config/, allowlisted outbound hosts and resource limits
+	Sandbox *llm.SandboxConfig `yaml:"sandbox,omitempty" json:"sandbox,omitempty"`
+}
+
+// AgentLimits configures the execution pool of an agent. Zero values mean no limit.
+type AgentLimits struct {
+	MaxConcurrent     int            `yaml:"max_concurrent" json:"max_concurrent"`           // concurrent calls of the agent
+	ModelConcurrency  map[string]int `yaml:"model_concurrency" json:"model_concurrency"`     // concurrent calls per model name
+	RequestsPerMinute int            `yaml:"requests_per_minute" json:"requests_per_minute"` // call starts per minute and API key (token bucket)
+	Burst             int            `yaml:"burst" json:"burst"`                             // token bucket size (default: 1)
+	KeySelection      string         `yaml:"key_selection" json:"key_selection"`             // round_robin (default) or least_loaded
+}
+
+// Keys returns the API keys of the agent: APIKey followed by APIKeys, without empty and duplicate keys
+func (d *AgentDetail) Keys() []string {
+	keys := make([]string, 0, 1+len(d.APIKeys))
+	seen := make(map[string]bool, 1+len(d.APIKeys))
+	for _, key := range append([]string{d.APIKey}, d.APIKeys...) {
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
 }
 
 // ReviewConfig holds review process configuration
@@ -122,6 +163,218 @@ type ReviewConfig struct {
 	RetryDelay     int                  `yaml:"retry_delay"`     // Delay between retry attempts in seconds
 	OutputLanguage string               `yaml:"output_language"` // Output language for review results (ISO 639-1 code, e.g., en, zh-cn)
 	OutputMetadata OutputMetadataConfig `yaml:"output_metadata"` // Output metadata configuration
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuit_breaker"` // Per-agent circuit breaker configuration
+	OutputRepair   OutputRepairConfig   `yaml:"output_repair"`   // Validation and repair of structured agent output
+	ResponseCache  ResponseCacheConfig  `yaml:"response_cache"`  // Cache of agent results for identical executions
+	Recording      RecordingConfig      `yaml:"recording"`       // Recording of agent interactions into cassettes
+
+	// PromptInjection configures the screening of PR-supplied content for prompt injection
+	PromptInjection PromptInjectionConfig `yaml:"prompt_injection"`
+
+	// ContextCommands lists the commands rules may run as context providers, e.g. "go vet ./..." or
+	// "golangci-lint run --out-format json". A command is allowed if its arguments equal the arguments
+	// of an entry. Empty disables command providers.
+	ContextCommands []string `yaml:"context_commands"`
+
+	// ContextSandbox isolates context provider commands (default: DefaultContextSandbox)
+	ContextSandbox *llm.SandboxConfig `yaml:"context_sandbox,omitempty"`
+
+	// ModelPrices is the price table used to estimate the cost of agent executions, keyed by model name.
+	// A model without an exact entry uses the entry with the longest matching prefix; unpriced models cost 0.
+	ModelPrices map[string]ModelPrice `yaml:"model_prices"`
+}
+
+// GetContextSandbox returns the sandbox of context provider commands.
+// Without a context_sandbox setting, commands run in the DefaultContextSandbox.
+func (c *ReviewConfig) GetContextSandbox() *llm.SandboxConfig {
+	if c.ContextSandbox == nil {
+		return DefaultContextSandbox()
+	}
+	return c.ContextSandbox
+}
+
+// contextSandboxEnv are the Go environment variables passed into the default context sandbox
+var contextSandboxEnv = []string{"GOPATH", "GOMODCACHE", "GOROOT", "GOFLAGS"}
+
+// DefaultContextSandbox returns the sandbox of context provider commands without a context_sandbox
+// setting: no network access, and the Go toolchain and module cache mounted read-only, so that Go
+// commands (e.g. "go vet ./...") build offline. Their build cache is created in the sandbox's
+// private home directory.
+func DefaultContextSandbox() *llm.SandboxConfig {
+	var readOnly []string
+	if goRoot := goRoot(); goRoot != "" {
+		readOnly = append(readOnly, goRoot)
+	}
+	if modCache := goModCache(); modCache != "" {
+		readOnly = append(readOnly, modCache)
+	}
+	return &llm.SandboxConfig{
+		Enabled:       true,
+		ReadOnlyPaths: readOnly,
+		PassEnv:       contextSandboxEnv,
+	}
+}
+
+// goRoot returns the installation directory of the go command in PATH, or "" if there is none
+func goRoot() string {
+	root := os.Getenv("GOROOT")
+	if root == "" {
+		path, err := exec.LookPath("go")
+		if err != nil {
+			return ""
+		}
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		root = filepath.Dir(filepath.Dir(path))
+	}
+	// Only a Go installation is mounted, never a directory the go command happens to be in
+	if info, err := os.Stat(filepath.Join(root, "src", "runtime")); err != nil || !info.IsDir() {
+		return ""
+	}
+	return root
+}
+
+// goModCache returns the Go module cache directory, as resolved by the go command
+func goModCache() string {
+	if modCache := os.Getenv("GOMODCACHE"); modCache != "" {
+		return modCache
+	}
+	goPath := os.Getenv("GOPATH")
+	if goPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		goPath = filepath.Join(home, "go")
+	}
+	return filepath.Join(filepath.SplitList(goPath)[0], "pkg", "mod")
+}
+
+// CircuitBreakerConfig configures the per-agent circuit breakers.
+// Zero values fall back to defaults.
+type CircuitBreakerConfig struct {
+	// Disabled turns circuit breaking off (agents are always called)
+	Disabled bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+
+	// ConsecutiveFailures opens the breaker after N consecutive failures (default: 5)
+	ConsecutiveFailures int `yaml:"consecutive_failures,omitempty" json:"consecutive_failures,omitempty"`
+
+	// ErrorRate opens the breaker when the failure ratio of recent calls reaches this value (0-1, default: 0.5)
+	ErrorRate float64 `yaml:"error_rate,omitempty" json:"error_rate,omitempty"`
+
+	// WindowSize is the number of recent calls used for the error rate (default: 20)
+	WindowSize int `yaml:"window_size,omitempty" json:"window_size,omitempty"`
+
+	// MinRequests is the minimum number of calls in the window before the error rate applies (default: 10)
+	MinRequests int `yaml:"min_requests,omitempty" json:"min_requests,omitempty"`
+
+	// OpenTimeout is how long the breaker stays open before allowing a probe, in seconds (default: 60)
+	OpenTimeout int `yaml:"open_timeout,omitempty" json:"open_timeout,omitempty"`
+
+	// HalfOpenProbes is the number of successful probes required to close the breaker (default: 1)
+	HalfOpenProbes int `yaml:"half_open_probes,omitempty" json:"half_open_probes,omitempty"`
+}
+
+// DefaultOutputRepairAttempts is the default number of repair prompts per invalid agent output
+const DefaultOutputRepairAttempts = 2
+
+// OutputRepairConfig configures the validation of structured agent output against the rule's
+// JSON Schema. Invalid output is sent back to the agent with the validation errors; if it
+// is still invalid afterwards, only the valid findings are kept.
+type OutputRepairConfig struct {
+	// Disabled turns repair prompts off (invalid output is salvaged right away)
+	Disabled bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+
+	// MaxAttempts is the maximum number of repair prompts per agent output (default: 2)
+	MaxAttempts int `yaml:"max_attempts,omitempty" json:"max_attempts,omitempty"`
+}
+
+// GetMaxAttempts returns the number of repair prompts per agent output (0 when disabled)
+func (c OutputRepairConfig) GetMaxAttempts() int {
+	if c.Disabled {
+		return 0
+	}
+	if c.MaxAttempts <= 0 {
+		return DefaultOutputRepairAttempts
+	}
+	return c.MaxAttempts
+}
+
+// DefaultResponseCacheTTL is the default time a cached agent result is reused, in seconds
+const DefaultResponseCacheTTL = 24 * 60 * 60
+
+// ResponseCacheConfig configures the response cache. When enabled, the parsed result of an agent
+// execution is stored under a hash of the rendered prompt, agent, model, head commit SHA and output
+// schema; an identical execution (e.g. a retry of the same commit) reuses it instead of calling the agent.
+type ResponseCacheConfig struct {
+	// Enabled turns the response cache on (off by default)
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+
+	// TTL is how long a cached result is reused, in seconds (default: 86400)
+	TTL int `yaml:"ttl,omitempty" json:"ttl,omitempty"`
+}
+
+// GetTTL returns how long a cached result is reused
+func (c ResponseCacheConfig) GetTTL() time.Duration {
+	if c.TTL <= 0 {
+		return DefaultResponseCacheTTL * time.Second
+	}
+	return time.Duration(c.TTL) * time.Second
+}
+
+// RecordingConfig configures the recording of agent interactions. When enabled, every agent
+// execution is stored with its exact prompt, agent, model, head commit SHA and raw stream of
+// chunks, so that the review can be downloaded as a cassette and replayed offline.
+type RecordingConfig struct {
+	// Enabled turns recording on (off by default)
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+}
+
+// DefaultInjectionMaxFileSize is the default number of bytes of a changed file screened for prompt injection
+const DefaultInjectionMaxFileSize = 64 * 1024
+
+// PromptInjectionConfig configures the screening of PR-supplied content (title, description,
+// commit messages and optionally changed files) for prompt injection before the rules run.
+// What happens to a review with detections is set per repository (warn, strip or quarantine).
+type PromptInjectionConfig struct {
+	// Enabled turns screening on (off by default)
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+
+	// PatternSets lists the built-in pattern sets to match (default: all)
+	PatternSets []string `yaml:"pattern_sets,omitempty" json:"pattern_sets,omitempty"`
+
+	// CustomPatterns are additional regular expressions, matched case-insensitively
+	CustomPatterns []string `yaml:"custom_patterns,omitempty" json:"custom_patterns,omitempty"`
+
+	// ScanFiles also screens the contents of the changed files
+	ScanFiles bool `yaml:"scan_files,omitempty" json:"scan_files,omitempty"`
+
+	// MaxFileSize is the number of bytes of a changed file that are screened (default: 65536)
+	MaxFileSize int `yaml:"max_file_size,omitempty" json:"max_file_size,omitempty"`
+
+	// Classifier asks a model to classify the content the patterns did not flag
+	Classifier InjectionClassifierConfig `yaml:"classifier,omitempty" json:"classifier,omitempty"`
+}
+
+// GetMaxFileSize returns the number of bytes of a changed file that are screened
+func (c PromptInjectionConfig) GetMaxFileSize() int {
+	if c.MaxFileSize <= 0 {
+		return DefaultInjectionMaxFileSize
+	}
+	return c.MaxFileSize
+}
+
+// InjectionClassifierConfig configures the classifier model pass of prompt injection screening
+type InjectionClassifierConfig struct {
+	// Enabled turns the classifier pass on (off by default)
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+
+	// Agent is the agent that runs the classifier model
+	Agent string `yaml:"agent,omitempty" json:"agent,omitempty"`
+
+	// Model is the classifier model (empty uses the agent's default model)
+	Model string `yaml:"model,omitempty" json:"model,omitempty"`
 }
 
 // OutputMetadataConfig configures metadata appended to review output
@@ -418,6 +671,9 @@ func (d *AgentDetail) ToLLMClientConfig(name string) map[string]interface{} {
 		"api_key":  d.APIKey,
 	}
 
+	if d.BaseURL != "" {
+		config["base_url"] = d.BaseURL
+	}
 	if d.Timeout > 0 {
 		config["timeout"] = d.Timeout
 	}
@@ -427,6 +683,9 @@ func (d *AgentDetail) ToLLMClientConfig(name string) map[string]interface{} {
 	if len(d.FallbackModels) > 0 {
 		config["fallback_models"] = d.FallbackModels
 	}
+	if d.ThinkingBudget > 0 {
+		config["thinking_budget"] = d.ThinkingBudget
+	}
 
 	return config
 }
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/verustcode/verustcode/internal/llm"
 )
 
 func TestDefault(t *testing.T) {
@@ -207,6 +209,18 @@ func TestConfig_GetAgent(t *testing.T) {
 	}
 }
 
+func TestAgentDetail_Keys(t *testing.T) {
+	detail := AgentDetail{APIKey: "key-a", APIKeys: []string{"key-b", "", "key-a", "key-c"}}
+	keys := detail.Keys()
+	if len(keys) != 3 || keys[0] != "key-a" || keys[1] != "key-b" || keys[2] != "key-c" {
+		t.Errorf("Keys() = %v, want [key-a key-b key-c]", keys)
+	}
+
+	if keys := (&AgentDetail{}).Keys(); len(keys) != 0 {
+		t.Errorf("Keys() = %v, want none", keys)
+	}
+}
+
 func TestLoad(t *testing.T) {
 	// 创建临时配置文件
 	tmpDir := t.TempDir()
@@ -334,3 +348,32 @@ func TestLoad_FileNotFound(t *testing.T) {
 		t.Error("Load() expected error for nonexistent file, got nil")
 	}
 }
+
+func TestDefaultContextSandbox(t *testing.T) {
+	modCache := t.TempDir()
+	t.Setenv("GOMODCACHE", modCache)
+	t.Setenv("GOROOT", t.TempDir()) // not a Go installation
+
+	var cfg ReviewConfig
+	sandbox := cfg.GetContextSandbox()
+	if !sandbox.Enabled || len(sandbox.AllowedHosts) != 0 {
+		t.Errorf("default context sandbox = %+v, want enabled without network access", sandbox)
+	}
+	if len(sandbox.ReadOnlyPaths) != 1 || sandbox.ReadOnlyPaths[0] != modCache {
+		t.Errorf("ReadOnlyPaths = %v, want only the module cache %s", sandbox.ReadOnlyPaths, modCache)
+	}
+	for _, name := range []string{"GOPATH", "GOMODCACHE"} {
+		found := false
+		for _, passed := range sandbox.PassEnv {
+			found = found || passed == name
+		}
+		if !found {
+			t.Errorf("PassEnv = %v, want %s", sandbox.PassEnv, name)
+		}
+	}
+
+	cfg.ContextSandbox = &llm.SandboxConfig{Enabled: false}
+	if cfg.GetContextSandbox().Enabled {
+		t.Error("a context_sandbox setting must replace the default")
+	}
+}
@@ -150,6 +150,13 @@ func (c *RuntimeConfig) loadFromDatabase(s store.Store) error {
 				if len(dbAgent.FallbackModels) > 0 {
 					existingAgent.FallbackModels = dbAgent.FallbackModels
 				}
+				if len(dbAgent.APIKeys) > 0 {
+					existingAgent.APIKeys = dbAgent.APIKeys
+				}
+				existingAgent.Limits = dbAgent.Limits
+				if dbAgent.Sandbox != nil {
+					existingAgent.Sandbox = dbAgent.Sandbox
+				}
 				
 				c.Agents[key] = existingAgent
 			}
@@ -227,13 +234,21 @@ func SaveSettingsToDatabase(cfg *Config, s store.Store, username string) error {
 
 	// Save review settings
 	reviewSettings := map[string]interface{}{
-		"workspace":       cfg.Review.Workspace,
-		"max_concurrent":  cfg.Review.MaxConcurrent,
-		"retention_days":  cfg.Review.RetentionDays,
-		"max_retries":     cfg.Review.MaxRetries,
-		"retry_delay":     cfg.Review.RetryDelay,
-		"output_language": cfg.Review.OutputLanguage,
-		"output_metadata": cfg.Review.OutputMetadata,
+		"workspace":        cfg.Review.Workspace,
+		"max_concurrent":   cfg.Review.MaxConcurrent,
+		"retention_days":   cfg.Review.RetentionDays,
+		"max_retries":      cfg.Review.MaxRetries,
+		"retry_delay":      cfg.Review.RetryDelay,
+		"output_language":  cfg.Review.OutputLanguage,
+		"output_metadata":  cfg.Review.OutputMetadata,
+		"circuit_breaker":  cfg.Review.CircuitBreaker,
+		"context_commands": cfg.Review.ContextCommands,
+		"context_sandbox":  cfg.Review.ContextSandbox,
+		"model_prices":     cfg.Review.ModelPrices,
+		"output_repair":    cfg.Review.OutputRepair,
+		"response_cache":   cfg.Review.ResponseCache,
+		"recording":        cfg.Review.Recording,
+		"prompt_injection": cfg.Review.PromptInjection,
 	}
 	if err := svc.SetCategory(string(model.SettingCategoryReview), reviewSettings, username); err != nil {
 		return fmt.Errorf("failed to save review settings: %w", err)
@@ -0,0 +1,58 @@
+package config
+
+import "strings"
+
+// tokensPerPriceUnit is the number of tokens a ModelPrice refers to
+const tokensPerPriceUnit = 1_000_000
+
+// ModelPrice is the price of a model in USD per million tokens.
+type ModelPrice struct {
+	// Input is the price of prompt tokens
+	Input float64 `yaml:"input" json:"input"`
+
+	// Output is the price of completion tokens
+	Output float64 `yaml:"output" json:"output"`
+
+	// CachedInput is the price of prompt tokens read from the prompt cache (0 = same as Input)
+	CachedInput float64 `yaml:"cached_input,omitempty" json:"cached_input,omitempty"`
+}
+
+// Cost returns the cost in USD of the given token counts.
+// cachedTokens are part of promptTokens.
+func (p ModelPrice) Cost(promptTokens, completionTokens, cachedTokens int) float64 {
+	cachedPrice := p.CachedInput
+	if cachedPrice == 0 {
+		cachedPrice = p.Input
+	}
+	if cachedTokens > promptTokens {
+		cachedTokens = promptTokens
+	}
+	cost := float64(promptTokens-cachedTokens)*p.Input +
+		float64(cachedTokens)*cachedPrice +
+		float64(completionTokens)*p.Output
+	return cost / tokensPerPriceUnit
+}
+
+// PriceFor returns the price of a model.
+// An exact entry wins; otherwise the entry with the longest prefix of the model name is used,
+// so "claude-sonnet-4" also prices "claude-sonnet-4-20250514".
+// Returns false if the model has no price.
+func PriceFor(prices map[string]ModelPrice, model string) (ModelPrice, bool) {
+	if model == "" {
+		return ModelPrice{}, false
+	}
+	if price, ok := prices[model]; ok {
+		return price, true
+	}
+
+	var best string
+	for name := range prices {
+		if name != "" && strings.HasPrefix(model, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return ModelPrice{}, false
+	}
+	return prices[best], true
+}
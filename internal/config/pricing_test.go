@@ -0,0 +1,51 @@
+package config
+
+import (
+	"math"
+	"testing"
+)
+
+func TestModelPrice_Cost(t *testing.T) {
+	price := ModelPrice{Input: 3, Output: 15, CachedInput: 0.3}
+
+	// 900k uncached + 100k cached prompt tokens, 200k completion tokens
+	got := price.Cost(1_000_000, 200_000, 100_000)
+	want := 0.9*3 + 0.1*0.3 + 0.2*15
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("Cost() = %v, want %v", got, want)
+	}
+
+	// Cached tokens use the input price when no cached price is configured
+	price.CachedInput = 0
+	if got := price.Cost(1_000_000, 0, 500_000); math.Abs(got-3) > 1e-9 {
+		t.Errorf("Cost() without cached price = %v, want 3", got)
+	}
+}
+
+func TestPriceFor(t *testing.T) {
+	prices := map[string]ModelPrice{
+		"claude-sonnet-4":   {Input: 3, Output: 15},
+		"claude-sonnet-4-5": {Input: 4, Output: 16},
+		"gpt-4o":            {Input: 2.5, Output: 10},
+	}
+
+	tests := []struct {
+		model string
+		want  float64 // input price, 0 = no price
+	}{
+		{"gpt-4o", 2.5},
+		{"claude-sonnet-4-20250514", 3},
+		{"claude-sonnet-4-5-20250929", 4},
+		{"gemini-2.5-pro", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			price, ok := PriceFor(prices, tt.model)
+			if ok != (tt.want != 0) || price.Input != tt.want {
+				t.Errorf("PriceFor(%q) = %+v, %v; want input price %v", tt.model, price, ok, tt.want)
+			}
+		})
+	}
+}
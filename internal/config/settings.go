@@ -12,6 +12,7 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"github.com/verustcode/verustcode/internal/llm"
 	"github.com/verustcode/verustcode/internal/model"
 	"github.com/verustcode/verustcode/internal/store"
 	"github.com/verustcode/verustcode/pkg/logger"
@@ -334,6 +335,25 @@ func (s *SettingsService) setCategoryWithoutTx(category string, settings map[str
 	return nil
 }
 
+// restoreMaskedList restores the masked items of the list field of a stored object from the
+// item at the same position in the stored list
+func restoreMaskedList(list []interface{}, old model.SystemSetting, field string) []interface{} {
+	var oldObj map[string]interface{}
+	if old.Value != "" {
+		json.Unmarshal([]byte(old.Value), &oldObj)
+	}
+	oldList, _ := oldObj[field].([]interface{})
+
+	result := make([]interface{}, len(list))
+	for i, item := range list {
+		result[i] = item
+		if strVal, ok := item.(string); ok && isMaskedValue(strVal) && i < len(oldList) {
+			result[i] = oldList[i]
+		}
+	}
+	return result
+}
+
 // restoreMaskedValues recursively restores masked sensitive values from existing settings
 func restoreMaskedValues(key string, value interface{}, existingMap map[string]model.SystemSetting) interface{} {
 	switch v := value.(type) {
@@ -357,6 +377,11 @@ func restoreMaskedValues(key string, value interface{}, existingMap map[string]m
 		result := make(map[string]interface{})
 		for k, val := range v {
 			// For nested objects, we need to check against the stored JSON
+			if list, ok := val.([]interface{}); ok && isSensitiveKey(k) {
+				// Lists of secrets (like an agent's api_keys) are restored by position
+				result[k] = restoreMaskedList(list, existingMap[key], k)
+				continue
+			}
 			if strVal, ok := val.(string); ok && isSensitiveKey(k) && isMaskedValue(strVal) {
 				// Try to get original value from the parent key's stored object
 				if old, exists := existingMap[key]; exists {
@@ -695,6 +720,47 @@ func (s *SettingsService) GetReviewConfig() (*ReviewConfig, error) {
 					cfg.OutputMetadata.CustomText = defaultCustomText
 				}
 			}
+		case "circuit_breaker":
+			var breaker CircuitBreakerConfig
+			if err := json.Unmarshal([]byte(setting.Value), &breaker); err == nil {
+				cfg.CircuitBreaker = breaker
+			}
+		case "context_commands":
+			var commands []string
+			if err := json.Unmarshal([]byte(setting.Value), &commands); err == nil {
+				cfg.ContextCommands = commands
+			}
+		case "context_sandbox":
+			// null keeps the default sandbox
+			var sandbox *llm.SandboxConfig
+			if err := json.Unmarshal([]byte(setting.Value), &sandbox); err == nil {
+				cfg.ContextSandbox = sandbox
+			}
+		case "model_prices":
+			var prices map[string]ModelPrice
+			if err := json.Unmarshal([]byte(setting.Value), &prices); err == nil {
+				cfg.ModelPrices = prices
+			}
+		case "output_repair":
+			var repair OutputRepairConfig
+			if err := json.Unmarshal([]byte(setting.Value), &repair); err == nil {
+				cfg.OutputRepair = repair
+			}
+		case "response_cache":
+			var cache ResponseCacheConfig
+			if err := json.Unmarshal([]byte(setting.Value), &cache); err == nil {
+				cfg.ResponseCache = cache
+			}
+		case "recording":
+			var recording RecordingConfig
+			if err := json.Unmarshal([]byte(setting.Value), &recording); err == nil {
+				cfg.Recording = recording
+			}
+		case "prompt_injection":
+			var injection PromptInjectionConfig
+			if err := json.Unmarshal([]byte(setting.Value), &injection); err == nil {
+				cfg.PromptInjection = injection
+			}
 		}
 	}
 
@@ -4,6 +4,7 @@ package config
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/verustcode/verustcode/internal/model"
@@ -135,6 +136,26 @@ func TestRestoreMaskedValues(t *testing.T) {
 		}
 	})
 
+	t.Run("Restore masked items of a nested list", func(t *testing.T) {
+		existingMap := map[string]model.SystemSetting{
+			"openai": {
+				Key:   "openai",
+				Value: `{"api_keys":["sk-first-secret-key","sk-second-secret-key"]}`,
+			},
+		}
+
+		// The first key is kept masked, the second is replaced and a third is added
+		input := map[string]interface{}{
+			"api_keys": []interface{}{"sk-f****-key", "sk-new-secret-key", "sk-third-secret-key"},
+		}
+
+		result := restoreMaskedValues("openai", input, existingMap).(map[string]interface{})
+		expected := []interface{}{"sk-first-secret-key", "sk-new-secret-key", "sk-third-secret-key"}
+		if !reflect.DeepEqual(result["api_keys"], expected) {
+			t.Errorf("Expected %v, got %v", expected, result["api_keys"])
+		}
+	})
+
 	t.Run("Restore array of objects", func(t *testing.T) {
 		// Existing array with two providers
 		existingArray := []interface{}{
@@ -126,6 +126,12 @@ func migrate() error {
 		return errors.Wrap(errors.ErrCodeDBMigration, "failed to migrate to remove summary columns", err)
 	}
 
+	// Replace the legacy PR/commit unique index with a partial index (PR reviews only)
+	if err := migrateReviewPRIndex(db); err != nil {
+		logger.Error("Failed to migrate reviews PR index", zap.Error(err))
+		return errors.Wrap(errors.ErrCodeDBMigration, "failed to migrate reviews PR index", err)
+	}
+
 	models := model.AllModels()
 	if err := db.AutoMigrate(models...); err != nil {
 		logger.Error("Failed to run database migrations", zap.Error(err))
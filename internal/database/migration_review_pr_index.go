@@ -0,0 +1,41 @@
+// Package database provides database initialization and connection management.
+package database
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// migrateReviewPRIndex drops the legacy idx_pr_url_commit unique index on reviews.
+//
+// The legacy index covered every review, so two non-PR reviews (empty pr_url) for the
+// same commit collided. It is replaced by the partial index idx_reviews_pr_url_commit,
+// which GORM auto-migration creates afterwards:
+//
+//	CREATE UNIQUE INDEX idx_reviews_pr_url_commit ON reviews(pr_url, commit_sha) WHERE pr_url <> ''
+//
+// The migration is idempotent - it only drops the index if it still exists.
+func migrateReviewPRIndex(db *gorm.DB) error {
+	var indexExists bool
+	err := db.Raw("SELECT COUNT(*) > 0 FROM sqlite_master WHERE type='index' AND name='idx_pr_url_commit'").Scan(&indexExists).Error
+	if err != nil {
+		logger.Error("Failed to check if idx_pr_url_commit index exists", zap.Error(err))
+		return fmt.Errorf("failed to check index existence: %w", err)
+	}
+
+	if !indexExists {
+		return nil
+	}
+
+	logger.Info("Dropping legacy idx_pr_url_commit index on reviews table")
+	if err := db.Exec("DROP INDEX IF EXISTS idx_pr_url_commit").Error; err != nil {
+		logger.Error("Failed to drop idx_pr_url_commit index", zap.Error(err))
+		return fmt.Errorf("failed to drop index: %w", err)
+	}
+
+	return nil
+}
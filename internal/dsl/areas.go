@@ -2,6 +2,14 @@
 // This file defines all available review areas and their groupings.
 package dsl
 
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
 // AreaGroup defines the category grouping for areas
 type AreaGroup string
 
@@ -29,13 +37,18 @@ const (
 
 	// AreaGroupDocumentation focuses on code documentation quality
 	AreaGroupDocumentation AreaGroup = "documentation"
+
+	// AreaGroupCustom is the default group of user-defined areas
+	AreaGroupCustom AreaGroup = "custom"
 )
 
 // AreaDefinition defines information for a single area
+// Built-in areas are listed in AllAreas; user-defined areas can be declared in the
+// `areas:` section of a review file, or organization-wide via the admin API.
 type AreaDefinition struct {
-	ID          string    // Area identifier (kebab-case format)
-	Group       AreaGroup // Group this area belongs to
-	Description string    // Concise core description
+	ID          string    `yaml:"id" json:"id"`                           // Area identifier (kebab-case format)
+	Group       AreaGroup `yaml:"group,omitempty" json:"group,omitempty"` // Group this area belongs to (default: custom)
+	Description string    `yaml:"description" json:"description"`         // Concise core description
 }
 
 // AllAreas contains all available area definitions (organized by group)
@@ -144,29 +157,115 @@ func init() {
 	}
 }
 
-// GetAreasByGroup returns all areas in the specified group
+// areaIDPattern matches valid area identifiers (kebab-case)
+var areaIDPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// customAreas holds organization-wide custom areas, keyed by area ID
+var customAreas = struct {
+	sync.RWMutex
+	byID map[string]AreaDefinition
+}{byID: make(map[string]AreaDefinition)}
+
+// SetCustomAreas replaces the organization-wide custom areas.
+// Areas that redefine a built-in area are ignored.
+func SetCustomAreas(areas []AreaDefinition) {
+	byID := make(map[string]AreaDefinition, len(areas))
+	for _, area := range areas {
+		if IsBuiltinArea(area.ID) {
+			continue
+		}
+		if area.Group == "" {
+			area.Group = AreaGroupCustom
+		}
+		byID[area.ID] = area
+	}
+
+	customAreas.Lock()
+	customAreas.byID = byID
+	customAreas.Unlock()
+}
+
+// GetCustomAreas returns the organization-wide custom areas, sorted by ID
+func GetCustomAreas() []AreaDefinition {
+	customAreas.RLock()
+	defer customAreas.RUnlock()
+
+	areas := make([]AreaDefinition, 0, len(customAreas.byID))
+	for _, area := range customAreas.byID {
+		areas = append(areas, area)
+	}
+	sort.Slice(areas, func(i, j int) bool {
+		return areas[i].ID < areas[j].ID
+	})
+	return areas
+}
+
+// getCustomArea returns an organization-wide custom area
+func getCustomArea(areaID string) (AreaDefinition, bool) {
+	customAreas.RLock()
+	defer customAreas.RUnlock()
+	area, exists := customAreas.byID[areaID]
+	return area, exists
+}
+
+// GetAreasByGroup returns all areas in the specified group, including custom areas
 func GetAreasByGroup(group AreaGroup) []AreaDefinition {
-	return AreasByGroup[group]
+	areas := append([]AreaDefinition(nil), AreasByGroup[group]...)
+	for _, area := range GetCustomAreas() {
+		if area.Group == group {
+			areas = append(areas, area)
+		}
+	}
+	return areas
 }
 
 // GetAreaDescription returns the description for the specified area
 func GetAreaDescription(areaID string) (string, bool) {
-	desc, exists := AreaDescriptions[areaID]
-	return desc, exists
+	if desc, exists := AreaDescriptions[areaID]; exists {
+		return desc, true
+	}
+	area, exists := getCustomArea(areaID)
+	return area.Description, exists
 }
 
 // GetAreaGroup returns the group for the specified area
 func GetAreaGroup(areaID string) (AreaGroup, bool) {
-	group, exists := AreaGroups[areaID]
-	return group, exists
+	if group, exists := AreaGroups[areaID]; exists {
+		return group, true
+	}
+	area, exists := getCustomArea(areaID)
+	return area.Group, exists
 }
 
-// IsValidArea checks if the specified area ID is valid (defined)
+// IsValidArea checks if the specified area ID is valid (built-in or custom)
 func IsValidArea(areaID string) bool {
+	_, exists := GetAreaDescription(areaID)
+	return exists
+}
+
+// IsBuiltinArea checks if the specified area ID is a built-in area
+func IsBuiltinArea(areaID string) bool {
 	_, exists := AreaDescriptions[areaID]
 	return exists
 }
 
+// ValidateAreaDefinition validates a user-defined area
+func ValidateAreaDefinition(area AreaDefinition) error {
+	if !areaIDPattern.MatchString(area.ID) {
+		return fmt.Errorf("area id %q must be kebab-case (e.g. gdpr-pii-handling)", area.ID)
+	}
+	if IsBuiltinArea(area.ID) {
+		return fmt.Errorf("area %q is a built-in area and cannot be redefined", area.ID)
+	}
+	if area.Group != "" && !areaIDPattern.MatchString(string(area.Group)) {
+		return fmt.Errorf("area %q: group %q must be kebab-case", area.ID, area.Group)
+	}
+	if strings.TrimSpace(area.Description) == "" {
+		return fmt.Errorf("area %q: description is required", area.ID)
+	}
+	return nil
+}
+
 // GetAllGroups returns all area groups
 func GetAllGroups() []AreaGroup {
 	return []AreaGroup{
@@ -371,3 +371,69 @@ func TestAreaMapsConsistency(t *testing.T) {
 		}
 	}
 }
+
+// TestCustomAreas tests registering organization-wide custom areas
+func TestCustomAreas(t *testing.T) {
+	SetCustomAreas([]AreaDefinition{
+		{ID: "pci-dss-logging", Group: "compliance", Description: "PCI-DSS logging requirements"},
+		{ID: "feature-flags", Description: "Feature flag hygiene"},
+		{ID: "business-logic", Description: "Redefined built-in"},
+	})
+	defer SetCustomAreas(nil)
+
+	if desc, ok := GetAreaDescription("pci-dss-logging"); !ok || desc != "PCI-DSS logging requirements" {
+		t.Errorf("GetAreaDescription(pci-dss-logging) = %q, %v", desc, ok)
+	}
+	if group, ok := GetAreaGroup("feature-flags"); !ok || group != AreaGroupCustom {
+		t.Errorf("GetAreaGroup(feature-flags) = %q, %v, want %q", group, ok, AreaGroupCustom)
+	}
+	if desc, _ := GetAreaDescription("business-logic"); desc != AreaDescriptions["business-logic"] {
+		t.Errorf("Custom area must not override built-in area, got %q", desc)
+	}
+	if !IsValidArea("feature-flags") || IsBuiltinArea("feature-flags") {
+		t.Error("feature-flags should be a valid, non built-in area")
+	}
+	if got := len(GetCustomAreas()); got != 2 {
+		t.Errorf("GetCustomAreas() returned %d areas, want 2", got)
+	}
+	found := false
+	for _, area := range GetAreasByGroup("compliance") {
+		if area.ID == "pci-dss-logging" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("GetAreasByGroup(compliance) should include pci-dss-logging")
+	}
+
+	SetCustomAreas(nil)
+	if IsValidArea("pci-dss-logging") {
+		t.Error("pci-dss-logging should be removed after SetCustomAreas(nil)")
+	}
+}
+
+// TestValidateAreaDefinition tests validation of user-defined areas
+func TestValidateAreaDefinition(t *testing.T) {
+	tests := []struct {
+		name    string
+		area    AreaDefinition
+		wantErr bool
+	}{
+		{"valid", AreaDefinition{ID: "gdpr-pii", Group: "compliance", Description: "GDPR PII handling"}, false},
+		{"default group", AreaDefinition{ID: "gdpr-pii", Description: "GDPR PII handling"}, false},
+		{"not kebab-case", AreaDefinition{ID: "GDPR_PII", Description: "GDPR PII handling"}, true},
+		{"empty id", AreaDefinition{Description: "GDPR PII handling"}, true},
+		{"built-in", AreaDefinition{ID: "business-logic", Description: "Business logic"}, true},
+		{"invalid group", AreaDefinition{ID: "gdpr-pii", Group: "Compliance Team", Description: "GDPR"}, true},
+		{"missing description", AreaDefinition{ID: "gdpr-pii", Description: "  "}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAreaDefinition(tt.area)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAreaDefinition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
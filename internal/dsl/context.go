@@ -0,0 +1,132 @@
+// Package dsl provides DSL configuration parsing and validation.
+// This file defines context providers (`context`), which attach extra evidence to the prompt.
+package dsl
+
+import (
+	"fmt"
+
+	"github.com/verustcode/verustcode/pkg/errors"
+)
+
+// Context provider types
+const (
+	ContextTypeFile    = "file"    // contents of files matching globs
+	ContextTypeCommand = "command" // output of a whitelisted command run in the workspace
+	ContextTypeDiff    = "diff"    // unified diff of the change set
+	ContextTypeGitLog  = "git_log" // recent history of the changed files
+)
+
+// Context provider defaults and limits
+const (
+	DefaultContextMaxTokens    = 2000      // token budget of a single provider
+	DefaultContextTotalTokens  = 8000      // token budget of all providers of a rule
+	DefaultContextMaxFiles     = 10        // files attached by a file provider
+	DefaultContextMaxFileSize  = 32 * 1024 // bytes read per file
+	DefaultContextTimeout      = 60        // command timeout in seconds
+	MaxContextTimeout          = 600       // maximum command timeout in seconds
+	DefaultContextLines        = 3         // diff context lines
+	DefaultContextMaxCommits   = 10        // commits listed by a git_log provider
+	MaxContextProvidersPerRule = 10
+)
+
+// ContextProviderConfig configures a context provider of a rule.
+// The provider output is truncated to its token budget and added to the prompt context.
+type ContextProviderConfig struct {
+	// Type is the provider type: file, command, diff or git_log
+	Type string `yaml:"type" json:"type"`
+
+	// Name is the title of the output in the prompt (defaults to the type and its main setting)
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	// MaxTokens is the token budget of the output (default: 2000)
+	MaxTokens int `yaml:"max_tokens,omitempty" json:"max_tokens,omitempty"`
+
+	// Paths lists glob patterns of files to attach (file)
+	Paths []string `yaml:"paths,omitempty" json:"paths,omitempty"`
+
+	// MaxFiles is the maximum number of files to attach (file, default: 10)
+	MaxFiles int `yaml:"max_files,omitempty" json:"max_files,omitempty"`
+
+	// MaxFileSize is the maximum number of bytes read per file (file, default: 32KB)
+	MaxFileSize int `yaml:"max_file_size,omitempty" json:"max_file_size,omitempty"`
+
+	// Command is the command to run in the workspace (command).
+	// It is not run by a shell and must be allowed by the context_commands review setting.
+	Command string `yaml:"command,omitempty" json:"command,omitempty"`
+
+	// Timeout is the command timeout in seconds (command, default: 60)
+	Timeout int `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+
+	// ContextLines is the number of unchanged lines around each change (diff, default: 3)
+	ContextLines *int `yaml:"context_lines,omitempty" json:"context_lines,omitempty"`
+
+	// MaxCommits is the number of recent commits to list (git_log, default: 10)
+	MaxCommits int `yaml:"max_commits,omitempty" json:"max_commits,omitempty"`
+}
+
+// Title returns the title of the provider output in the prompt
+func (c *ContextProviderConfig) Title() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	switch c.Type {
+	case ContextTypeCommand:
+		return c.Command
+	case ContextTypeFile:
+		return fmt.Sprintf("Files: %v", c.Paths)
+	case ContextTypeDiff:
+		return "Diff"
+	case ContextTypeGitLog:
+		return "Git log"
+	}
+	return c.Type
+}
+
+// validateContext validates the context providers of a rule
+func (p *Parser) validateContext(providers []ContextProviderConfig, prefix, id string) error {
+	if len(providers) > MaxContextProvidersPerRule {
+		return errors.New(errors.ErrCodeConfigInvalid,
+			fmt.Sprintf("%s (%s): context cannot exceed %d providers, got %d",
+				prefix, id, MaxContextProvidersPerRule, len(providers)))
+	}
+
+	for i := range providers {
+		c := &providers[i]
+		field := fmt.Sprintf("context[%d]", i)
+
+		if c.MaxTokens < 0 || c.MaxFiles < 0 || c.MaxFileSize < 0 || c.Timeout < 0 || c.MaxCommits < 0 ||
+			(c.ContextLines != nil && *c.ContextLines < 0) {
+			return errors.New(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("%s (%s): %s: limits must not be negative", prefix, id, field))
+		}
+
+		switch c.Type {
+		case ContextTypeFile:
+			if len(c.Paths) == 0 {
+				return errors.New(errors.ErrCodeConfigInvalid,
+					fmt.Sprintf("%s (%s): %s: file provider requires paths", prefix, id, field))
+			}
+			for j, pattern := range c.Paths {
+				if pattern == "" || !validGlob(pattern) {
+					return errors.New(errors.ErrCodeConfigInvalid,
+						fmt.Sprintf("%s (%s): %s.paths[%d]: invalid glob pattern %q", prefix, id, field, j, pattern))
+				}
+			}
+		case ContextTypeCommand:
+			if c.Command == "" {
+				return errors.New(errors.ErrCodeConfigInvalid,
+					fmt.Sprintf("%s (%s): %s: command provider requires command", prefix, id, field))
+			}
+			if c.Timeout > MaxContextTimeout {
+				return errors.New(errors.ErrCodeConfigInvalid,
+					fmt.Sprintf("%s (%s): %s: timeout cannot exceed %d seconds", prefix, id, field, MaxContextTimeout))
+			}
+		case ContextTypeDiff, ContextTypeGitLog:
+		default:
+			return errors.New(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("%s (%s): %s: invalid type %q (valid: %s, %s, %s, %s)", prefix, id, field, c.Type,
+					ContextTypeFile, ContextTypeCommand, ContextTypeDiff, ContextTypeGitLog))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+// Package dsl provides DSL configuration parsing and validation.
+// This file generates JSON Schemas of the review and report DSL from the Go structs,
+// so that editors can autocomplete and validate review and report files.
+package dsl
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DSL schema names, as served by the schema API
+const (
+	ReviewDSLSchemaName = "review-dsl"
+	ReportDSLSchemaName = "report-dsl"
+)
+
+// DSLSchemaOptions configures the generated DSL schemas
+type DSLSchemaOptions struct {
+	// AgentTypes are the registered agent types.
+	// agent.type and agent.fallback are not restricted when empty.
+	AgentTypes []string
+}
+
+// optionalDSLFields are the fields (Type.field) without omitempty that may be left out of a file.
+// Every other field without omitempty is required by the schema.
+var optionalDSLFields = map[string]bool{
+	"ReviewRulesConfig.rules": true, // a file may only include other files
+	"ReviewRuleConfig.goals":  true, // inherited with extends
+	"OutputConfig.channels":   true,
+	"ReportConfig.structure":  true,
+	"ReportConfig.section":    true,
+	"ReportConfig.summary":    true,
+}
+
+// ReviewDSLSchema returns the JSON Schema of review files (ReviewRulesConfig).
+// Goal areas list the built-in and organization-wide custom areas; other kebab-case
+// areas are accepted, since a review file may declare its own areas.
+func ReviewDSLSchema(opts DSLSchemaOptions) map[string]interface{} {
+	g := newDSLSchemaGenerator(opts)
+	schema := g.objectSchema(reflect.TypeOf(ReviewRulesConfig{}))
+	return g.document(schema, "VerustCode review file",
+		"Review rules of a VerustCode review file (config/reviews/*.yaml or .verust-review.yaml)")
+}
+
+// ReportDSLSchema returns the JSON Schema of report files (ReportConfig)
+func ReportDSLSchema(opts DSLSchemaOptions) map[string]interface{} {
+	g := newDSLSchemaGenerator(opts)
+	schema := g.objectSchema(reflect.TypeOf(ReportConfig{}))
+	return g.document(schema, "VerustCode report file",
+		"Report type definition of a VerustCode report file (config/reports/*.yaml)")
+}
+
+// GetDSLSchema returns the DSL schema with the given name, or nil if there is none
+func GetDSLSchema(name string, opts DSLSchemaOptions) map[string]interface{} {
+	switch strings.TrimSuffix(name, ".json") {
+	case ReviewDSLSchemaName:
+		return ReviewDSLSchema(opts)
+	case ReportDSLSchemaName:
+		return ReportDSLSchema(opts)
+	}
+	return nil
+}
+
+// dslSchemaGenerator builds a JSON Schema by reflecting over the DSL structs.
+// Struct types are added to definitions by Go type name.
+type dslSchemaGenerator struct {
+	definitions map[string]interface{}
+
+	// fieldSchemas holds the constraints of fields (Type.field), merged into the generated schema
+	fieldSchemas map[string]map[string]interface{}
+}
+
+// newDSLSchemaGenerator creates a generator with the constraints enforced by the validators
+func newDSLSchemaGenerator(opts DSLSchemaOptions) *dslSchemaGenerator {
+	refDocs := func(max int) map[string]interface{} {
+		return map[string]interface{}{"maxItems": max}
+	}
+	fieldSchemas := map[string]map[string]interface{}{
+		// Review DSL
+		"ReviewRuleConfig.reference_docs":    refDocs(MaxReferenceDocs),
+		"ReviewRuleConfig.context":           {"maxItems": MaxContextProvidersPerRule},
+		"GoalsConfig.areas":                  {"items": areaItemSchema()},
+		"AreaDefinition.id":                  {"pattern": areaIDPattern.String()},
+		"AreaDefinition.group":               {"pattern": areaIDPattern.String()},
+		"SeverityConfig.min_report":          {"enum": SeverityLevels},
+		"ReviewRuleConfig.policy":            {"maxItems": MaxPolicyStatements},
+		"RuleBaseConfig.policy":              {"maxItems": MaxPolicyStatements},
+		"PolicyStatementConfig.set_severity": {"enum": SeverityLevels},
+		"PolicyStatementConfig.max_per_file": {"minimum": 0},
+		"OutputStyleConfig.tone":             {"enum": validOutputTones},
+		"OutputItemConfig.type":              {"enum": validChannelTypes},
+		"OutputItemConfig.format":            {"enum": validOutputFormats},
+		"OutputItemConfig.timeout":           {"minimum": MinWebhookTimeout, "maximum": MaxWebhookTimeout},
+		"OutputItemConfig.max_retries":       {"minimum": MinWebhookRetries, "maximum": MaxWebhookRetries},
+		"OutputItemConfig.header_secret":     {"minLength": MinHeaderSecretLength, "maxLength": MaxHeaderSecretLength},
+		"ExtraFieldConfig.type":              {"enum": validExtraFieldTypes},
+		"MultiRunConfig.runs":                {"minimum": 0, "maximum": MaxMultiRunRuns},
+		"ChunkingConfig.max_files":           {"minimum": 0},
+		"ChunkingConfig.max_diff_lines":      {"minimum": 0},
+		"ContextProviderConfig.type":         {"enum": []string{ContextTypeFile, ContextTypeCommand, ContextTypeDiff, ContextTypeGitLog}},
+		"ContextProviderConfig.timeout":      {"minimum": 0, "maximum": MaxContextTimeout},
+		"ContextProviderConfig.max_tokens":   {"minimum": 0},
+
+		// Report DSL
+		"ReportStyleConfig.heading_level":      {"minimum": 1, "maximum": 4},
+		"ReportStyleConfig.max_section_length": {"minimum": minSectionLength},
+		"StructurePhase.reference_docs":        refDocs(maxReferenceDocs),
+		"SectionPhase.reference_docs":          refDocs(maxReferenceDocs),
+		"SectionSummaryConfig.max_length":      {"minimum": 0, "maximum": maxSectionSummaryLength},
+	}
+
+	if len(opts.AgentTypes) > 0 {
+		agents := append([]string(nil), opts.AgentTypes...)
+		sort.Strings(agents)
+		fieldSchemas["AgentConfig.type"] = map[string]interface{}{"enum": agents}
+		fieldSchemas["AgentConfig.fallback"] = map[string]interface{}{
+			"items": map[string]interface{}{"type": "string", "enum": agents},
+		}
+	}
+
+	return &dslSchemaGenerator{
+		definitions:  make(map[string]interface{}),
+		fieldSchemas: fieldSchemas,
+	}
+}
+
+// areaItemSchema returns the schema of a goal area: a known area or a kebab-case area ID
+func areaItemSchema() map[string]interface{} {
+	var known []interface{}
+	areas := append(append([]AreaDefinition(nil), AllAreas...), GetCustomAreas()...)
+	for _, area := range areas {
+		known = append(known, map[string]interface{}{
+			"const":       area.ID,
+			"description": area.Description,
+		})
+	}
+	known = append(known, map[string]interface{}{
+		"type":        "string",
+		"pattern":     areaIDPattern.String(),
+		"description": "Area declared in the areas section of the review file",
+	})
+	return map[string]interface{}{"anyOf": known}
+}
+
+// document wraps the root schema with the schema metadata and definitions
+func (g *dslSchemaGenerator) document(root map[string]interface{}, title, description string) map[string]interface{} {
+	root["$schema"] = "http://json-schema.org/draft-07/schema#"
+	root["title"] = title
+	root["description"] = description
+	root["definitions"] = g.definitions
+	return root
+}
+
+// typeSchema returns the schema of a Go type
+func (g *dslSchemaGenerator) typeSchema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		name := t.Name()
+		if _, exists := g.definitions[name]; !exists {
+			g.definitions[name] = nil // reserve the name for recursive types
+			g.definitions[name] = g.objectSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/definitions/" + name}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.typeSchema(t.Elem())}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	}
+	return map[string]interface{}{}
+}
+
+// objectSchema returns the schema of a struct, with a property for each YAML field
+func (g *dslSchemaGenerator) objectSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty, ok := yamlFieldName(field)
+		if !ok {
+			continue
+		}
+
+		key := t.Name() + "." + name
+		schema := g.typeSchema(field.Type)
+		for k, v := range g.fieldSchemas[key] {
+			schema[k] = v
+		}
+		properties[name] = schema
+
+		if !omitempty && !optionalDSLFields[key] {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// yamlFieldName returns the YAML key of a struct field and whether it has omitempty.
+// Returns false for fields that are not part of the YAML document.
+func yamlFieldName(field reflect.StructField) (string, bool, bool) {
+	if field.PkgPath != "" {
+		return "", false, false // unexported
+	}
+	tag := field.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false, false
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	omitempty := false
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, true
+}
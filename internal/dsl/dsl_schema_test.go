@@ -0,0 +1,129 @@
+package dsl
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestReviewDSLSchema tests the generated review DSL schema
+func TestReviewDSLSchema(t *testing.T) {
+	schema := ReviewDSLSchema(DSLSchemaOptions{AgentTypes: []string{"qoder", "cursor"}})
+
+	if _, err := json.Marshal(schema); err != nil {
+		t.Fatalf("schema is not JSON serializable: %v", err)
+	}
+	if schema["additionalProperties"] != false {
+		t.Error("Expected unknown top-level keys to be rejected")
+	}
+
+	definitions := schema["definitions"].(map[string]interface{})
+	rule, ok := definitions["ReviewRuleConfig"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected ReviewRuleConfig definition")
+	}
+	if got := rule["required"]; !reflect.DeepEqual(got, []string{"id"}) {
+		t.Errorf("ReviewRuleConfig required = %v, want [id]", got)
+	}
+	properties := rule["properties"].(map[string]interface{})
+	for _, key := range []string{"extends", "goals", "when", "context", "prompt_template", "locked"} {
+		if _, ok := properties[key]; !ok {
+			t.Errorf("Expected rule property %s", key)
+		}
+	}
+
+	agent := definitions["AgentConfig"].(map[string]interface{})["properties"].(map[string]interface{})
+	if got := agent["type"].(map[string]interface{})["enum"]; !reflect.DeepEqual(got, []string{"cursor", "qoder"}) {
+		t.Errorf("agent.type enum = %v, want sorted agent types", got)
+	}
+
+	severity := definitions["SeverityConfig"].(map[string]interface{})["properties"].(map[string]interface{})
+	if got := severity["min_report"].(map[string]interface{})["enum"]; !reflect.DeepEqual(got, SeverityLevels) {
+		t.Errorf("min_report enum = %v, want %v", got, SeverityLevels)
+	}
+
+	goals := definitions["GoalsConfig"].(map[string]interface{})["properties"].(map[string]interface{})
+	areas := goals["areas"].(map[string]interface{})["items"].(map[string]interface{})["anyOf"].([]interface{})
+	if len(areas) != len(AllAreas)+1 {
+		t.Errorf("Expected %d area entries (built-in areas and file-defined pattern), got %d", len(AllAreas)+1, len(areas))
+	}
+}
+
+// TestReviewDSLSchema_CustomAreas tests that organization-wide custom areas are listed
+func TestReviewDSLSchema_CustomAreas(t *testing.T) {
+	SetCustomAreas([]AreaDefinition{{ID: "gdpr-pii-handling", Description: "PII handling"}})
+	defer SetCustomAreas(nil)
+
+	data, _ := json.Marshal(ReviewDSLSchema(DSLSchemaOptions{}))
+	var schema struct {
+		Definitions struct {
+			GoalsConfig struct {
+				Properties struct {
+					Areas struct {
+						Items struct {
+							AnyOf []struct {
+								Const string `json:"const"`
+							} `json:"anyOf"`
+						} `json:"items"`
+					} `json:"areas"`
+				} `json:"properties"`
+			} `json:"GoalsConfig"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, area := range schema.Definitions.GoalsConfig.Properties.Areas.Items.AnyOf {
+		if area.Const == "gdpr-pii-handling" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected custom area in goals.areas")
+	}
+}
+
+// TestReportDSLSchema tests the generated report DSL schema
+func TestReportDSLSchema(t *testing.T) {
+	schema := ReportDSLSchema(DSLSchemaOptions{})
+	if got := schema["required"]; !reflect.DeepEqual(got, []string{"id", "name"}) {
+		t.Errorf("ReportConfig required = %v, want [id name]", got)
+	}
+	definitions := schema["definitions"].(map[string]interface{})
+	style := definitions["ReportStyleConfig"].(map[string]interface{})["properties"].(map[string]interface{})
+	if got := style["heading_level"].(map[string]interface{})["maximum"]; got != 4 {
+		t.Errorf("heading_level maximum = %v, want 4", got)
+	}
+}
+
+// TestGetDSLSchema tests looking up DSL schemas by name
+func TestGetDSLSchema(t *testing.T) {
+	for _, name := range []string{"review-dsl", "review-dsl.json", "report-dsl", "report-dsl.json"} {
+		if GetDSLSchema(name, DSLSchemaOptions{}) == nil {
+			t.Errorf("GetDSLSchema(%q) = nil", name)
+		}
+	}
+	if GetDSLSchema("default", DSLSchemaOptions{}) != nil {
+		t.Error("Expected nil for an unknown schema")
+	}
+}
+
+// TestStrictParser_UnknownKeys tests that the strict parser rejects unknown keys
+func TestStrictParser_UnknownKeys(t *testing.T) {
+	content := `
+version: "1.0"
+rules:
+  - id: security
+    goals:
+      areas: [security]
+    severty:
+      min_report: high
+`
+	if _, err := NewParser().Parse([]byte(content)); err != nil {
+		t.Errorf("Parse() unexpected error: %v", err)
+	}
+	if _, err := NewStrictParser().Parse([]byte(content)); err == nil {
+		t.Error("Expected strict parser to reject the unknown key")
+	}
+}
@@ -0,0 +1,267 @@
+// Package dsl provides DSL configuration parsing and validation.
+// This file formats review and report files with a canonical key order.
+package dsl
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/verustcode/verustcode/pkg/errors"
+)
+
+// DSL file kinds
+const (
+	DSLKindReview = "review"
+	DSLKindReport = "report"
+)
+
+// dslKindKeys are the top-level keys that identify the kind of a DSL file
+var dslKindKeys = map[string]string{
+	"rules":     DSLKindReview,
+	"include":   DSLKindReview,
+	"rule_base": DSLKindReview,
+	"areas":     DSLKindReview,
+	"structure": DSLKindReport,
+	"section":   DSLKindReport,
+	"summary":   DSLKindReport,
+	"name":      DSLKindReport,
+}
+
+// DetectDSLKind returns the kind of a DSL file (review or report) from its top-level keys
+func DetectDSLKind(data []byte) (string, error) {
+	root, err := parseDSLDocument(data)
+	if err != nil {
+		return "", err
+	}
+	if root != nil {
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if kind, ok := dslKindKeys[root.Content[i].Value]; ok {
+				return kind, nil
+			}
+		}
+	}
+	return "", errors.New(errors.ErrCodeConfigInvalid,
+		"unknown DSL file: expected review rules (rules, include) or a report definition (structure, section, summary)")
+}
+
+// blankLineMarker is the comment that stands in for a blank line while a file is formatted,
+// since the YAML encoder does not keep blank lines
+const blankLineMarker = "#dsl-fmt:blank"
+
+// blockScalarPattern matches a line that starts a block scalar (e.g. `description: |`)
+var blockScalarPattern = regexp.MustCompile(`(^|[:-])\s*[|>][-+0-9]*\s*(#.*)?$`)
+
+// FormatDSL formats a review or report file: keys are sorted in the order of the DSL
+// structs, unknown keys are kept after them, and comments and blank lines are preserved.
+func FormatDSL(data []byte, kind string) ([]byte, error) {
+	doc, err := parseDSLNode(markBlankLines(data))
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return data, nil
+	}
+	sortDSLKeys(doc.Content[0], dslRootType(kind))
+	out, err := encodeDSLNode(doc)
+	if err != nil {
+		return nil, err
+	}
+	out = restoreBlankLines(out)
+	if err := verifyFormatted(data, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// verifyFormatted checks that formatting kept the values and comments of a file.
+// The YAML encoder can lose comments in unusual positions; such files are left as they are.
+func verifyFormatted(original, formatted []byte) error {
+	var before, after interface{}
+	if err := yaml.Unmarshal(original, &before); err != nil {
+		return errors.Wrap(errors.ErrCodeConfigInvalid, "failed to parse YAML", err)
+	}
+	if err := yaml.Unmarshal(formatted, &after); err != nil || !reflect.DeepEqual(before, after) {
+		return errors.New(errors.ErrCodeInternal, "formatting would change the values of the file, leaving it unchanged")
+	}
+
+	comments := make(map[string]int)
+	for _, comment := range commentLines(original) {
+		comments[comment]++
+	}
+	for _, comment := range commentLines(formatted) {
+		comments[comment]--
+	}
+	for comment, count := range comments {
+		if count > 0 {
+			return errors.New(errors.ErrCodeInternal,
+				fmt.Sprintf("formatting would drop the comment %q, leaving the file unchanged", comment))
+		}
+	}
+	return nil
+}
+
+// commentLines returns the full-line comments of a YAML file
+func commentLines(data []byte) []string {
+	var comments []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "#") {
+			comments = append(comments, trimmed)
+		}
+	}
+	return comments
+}
+
+// markBlankLines replaces blank lines outside of block scalars with blankLineMarker comments,
+// indented like the next line so that they stay with it
+func markBlankLines(data []byte) []byte {
+	lines := strings.Split(string(data), "\n")
+	scalarIndent := -1 // indentation of the key of the current block scalar
+	keepTrailing := false
+	var blanks []int
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if trimmed == "" {
+			blanks = append(blanks, i)
+			continue
+		}
+		if scalarIndent >= 0 {
+			if indent > scalarIndent {
+				blanks = blanks[:0] // blank lines within the block scalar
+				continue
+			}
+			if keepTrailing {
+				blanks = blanks[:0] // trailing blank lines kept by the `+` chomping indicator
+			}
+			scalarIndent = -1
+		}
+		for _, j := range blanks {
+			lines[j] = strings.Repeat(" ", indent) + blankLineMarker
+		}
+		blanks = blanks[:0]
+		if !strings.HasPrefix(trimmed, "#") && blockScalarPattern.MatchString(trimmed) {
+			scalarIndent = indent
+			keepTrailing = strings.Contains(trimmed[strings.LastIndexAny(trimmed, "|>"):], "+")
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// restoreBlankLines turns blankLineMarker comments back into blank lines,
+// dropping repeated blank lines and blank lines at the start and end of the file
+func restoreBlankLines(data []byte) []byte {
+	var out []string
+	blank := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == blankLineMarker {
+			blank = true
+			continue
+		}
+		if blank && len(out) > 0 && line != "" {
+			out = append(out, "")
+		}
+		blank = false
+		out = append(out, line)
+	}
+	return []byte(strings.Join(out, "\n"))
+}
+
+// dslRootType returns the Go type of the root of a DSL file
+func dslRootType(kind string) reflect.Type {
+	if kind == DSLKindReport {
+		return reflect.TypeOf(ReportConfig{})
+	}
+	return reflect.TypeOf(ReviewRulesConfig{})
+}
+
+// sortDSLKeys sorts the keys of mapping nodes by the field order of t, recursively
+func sortDSLKeys(node *yaml.Node, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case node.Kind == yaml.SequenceNode && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+		for _, item := range node.Content {
+			sortDSLKeys(item, t.Elem())
+		}
+
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		order := make(map[string]int)
+		fields := make(map[string]reflect.Type)
+		for i := 0; i < t.NumField(); i++ {
+			if name, _, ok := yamlFieldName(t.Field(i)); ok {
+				order[name] = i
+				fields[name] = t.Field(i).Type
+			}
+		}
+
+		type pair struct{ key, value *yaml.Node }
+		pairs := make([]pair, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			pairs = append(pairs, pair{node.Content[i], node.Content[i+1]})
+		}
+		rank := func(p pair) int {
+			if i, ok := order[p.key.Value]; ok {
+				return i
+			}
+			return len(order) // unknown keys last
+		}
+		sort.SliceStable(pairs, func(i, j int) bool {
+			return rank(pairs[i]) < rank(pairs[j])
+		})
+
+		node.Content = node.Content[:0]
+		for _, p := range pairs {
+			if fieldType, ok := fields[p.key.Value]; ok {
+				sortDSLKeys(p.value, fieldType)
+			}
+			node.Content = append(node.Content, p.key, p.value)
+		}
+	}
+}
+
+// parseDSLNode parses a DSL file into a YAML document node.
+// Returns nil for an empty file.
+func parseDSLNode(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrap(errors.ErrCodeConfigInvalid, "failed to parse YAML", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, nil
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New(errors.ErrCodeConfigInvalid, "DSL file must be a YAML mapping")
+	}
+	return &doc, nil
+}
+
+// parseDSLDocument parses a DSL file and returns its root mapping, or nil for an empty file
+func parseDSLDocument(data []byte) (*yaml.Node, error) {
+	doc, err := parseDSLNode(data)
+	if err != nil || doc == nil {
+		return nil, err
+	}
+	return doc.Content[0], nil
+}
+
+// encodeDSLNode encodes a YAML document node with 2-space indentation
+func encodeDSLNode(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, errors.Wrap(errors.ErrCodeInternal, "failed to encode YAML", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, errors.Wrap(errors.ErrCodeInternal, "failed to encode YAML", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,110 @@
+package dsl
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDetectDSLKind tests detecting review and report files
+func TestDetectDSLKind(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"version: \"1.0\"\nrules: []\n", DSLKindReview, false},
+		{"include: [shared/security.yaml]\n", DSLKindReview, false},
+		{"id: wiki\nname: Wiki\nstructure:\n  description: x\n", DSLKindReport, false},
+		{"version: \"1.0\"\n", "", true},
+		{"- a\n- b\n", "", true},
+	}
+	for _, tt := range tests {
+		got, err := DetectDSLKind([]byte(tt.content))
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("DetectDSLKind(%q) = %q, %v; want %q", tt.content, got, err, tt.want)
+		}
+	}
+}
+
+// TestFormatDSL tests the canonical key order of review files
+func TestFormatDSL(t *testing.T) {
+	content := `rules:
+    # Security review
+    - goals:
+        areas: [security]
+      id: security
+      custom_key: 1
+      description: Security
+version: "1.0"
+`
+	want := `version: "1.0"
+rules:
+  # Security review
+  - id: security
+    description: Security
+    goals:
+      areas: [security]
+    custom_key: 1
+`
+	got, err := FormatDSL([]byte(content), DSLKindReview)
+	if err != nil {
+		t.Fatalf("FormatDSL() unexpected error: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("FormatDSL() =\n%s\nwant\n%s", got, want)
+	}
+
+	again, _ := FormatDSL(got, DSLKindReview)
+	if string(again) != string(got) {
+		t.Errorf("FormatDSL() is not idempotent:\n%s", again)
+	}
+}
+
+// TestFormatDSL_BlankLines tests that blank lines are kept, including those of block scalars
+func TestFormatDSL_BlankLines(t *testing.T) {
+	content := `version: "1.0"
+
+rules:
+  - id: docs
+    description: |
+      First paragraph.
+
+      Second paragraph.
+
+    goals:
+      areas: [documentation]
+`
+	got, err := FormatDSL([]byte(content), DSLKindReview)
+	if err != nil {
+		t.Fatalf("FormatDSL() unexpected error: %v", err)
+	}
+	if string(got) != content {
+		t.Errorf("FormatDSL() =\n%s\nwant\n%s", got, content)
+	}
+
+	config := parseTestConfig(t, string(got))
+	if want := "First paragraph.\n\nSecond paragraph.\n"; config.Rules[0].Description != want {
+		t.Errorf("Description = %q, want %q", config.Rules[0].Description, want)
+	}
+}
+
+// TestMigrateDSL tests upgrading files to the current DSL version
+func TestMigrateDSL(t *testing.T) {
+	current := "version: \"" + CurrentDSLVersion + "\"\nrules: []\n"
+	got, applied, err := MigrateDSL([]byte(current), DSLKindReview)
+	if err != nil || len(applied) != 0 || string(got) != current {
+		t.Errorf("Expected a current file to be unchanged, got %q, %v, %v", got, applied, err)
+	}
+
+	got, applied, err = MigrateDSL([]byte("rules: []\n"), DSLKindReview)
+	if err != nil {
+		t.Fatalf("MigrateDSL() unexpected error: %v", err)
+	}
+	if len(applied) != 1 || !strings.HasPrefix(string(got), "version: \""+CurrentDSLVersion+"\"\n") {
+		t.Errorf("Expected the version to be set, got %q, %v", got, applied)
+	}
+
+	if _, _, err := MigrateDSL([]byte("version: \"9.0\"\nrules: []\n"), DSLKindReview); err == nil {
+		t.Error("Expected error for an unsupported version")
+	}
+}
@@ -0,0 +1,616 @@
+// Package dsl provides DSL configuration parsing and validation.
+// This file resolves shared rule libraries (`include` and `extends`).
+package dsl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/pkg/errors"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// Rule library constants
+const (
+	// gitFetchTimeout is the timeout for fetching a review file from a git repository
+	gitFetchTimeout = 60 * time.Second
+
+	// gitCacheTTL is how long fetched git review files are cached
+	gitCacheTTL = 5 * time.Minute
+)
+
+// GitRef identifies a review file in a git repository at a specific version.
+// Format: <repo-url>//<path>@<version>, e.g.
+// https://github.com/org/review-rules.git//reviews/security.yaml@v1.2.0
+type GitRef struct {
+	RepoURL string
+	Path    string
+	Version string // tag, branch or commit SHA
+}
+
+// String returns the canonical form of the reference
+func (g GitRef) String() string {
+	return g.RepoURL + "//" + g.Path + "@" + g.Version
+}
+
+// ParseGitRef parses a versioned git reference.
+// Returns false if ref is not a git reference.
+func ParseGitRef(ref string) (GitRef, bool, error) {
+	if !isGitURL(ref) {
+		return GitRef{}, false, nil
+	}
+
+	// Skip the scheme separator so that "//" only matches the path separator
+	start := 0
+	if i := strings.Index(ref, "://"); i >= 0 {
+		start = i + len("://")
+	}
+	sep := strings.Index(ref[start:], "//")
+	if sep < 0 {
+		return GitRef{}, true, fmt.Errorf("git reference %q must have the form <repo-url>//<path>@<version>", ref)
+	}
+	repoURL := ref[:start+sep]
+	rest := ref[start+sep+2:]
+
+	at := strings.LastIndex(rest, "@")
+	if at <= 0 || at == len(rest)-1 {
+		return GitRef{}, true, fmt.Errorf("git reference %q must pin a version (<repo-url>//<path>@<version>)", ref)
+	}
+
+	filePath := path.Clean(rest[:at])
+	if path.IsAbs(filePath) || filePath == ".." || strings.HasPrefix(filePath, "../") {
+		return GitRef{}, true, fmt.Errorf("git reference %q has an invalid path", ref)
+	}
+
+	// Values starting with "-" would be taken as options by git
+	version := rest[at+1:]
+	if strings.HasPrefix(repoURL, "-") || strings.HasPrefix(version, "-") {
+		return GitRef{}, true, fmt.Errorf("git reference %q has an invalid repository URL or version", ref)
+	}
+
+	return GitRef{RepoURL: repoURL, Path: filePath, Version: version}, true, nil
+}
+
+// isGitURL returns true if ref looks like a git repository URL
+func isGitURL(ref string) bool {
+	return strings.HasPrefix(ref, "https://") ||
+		strings.HasPrefix(ref, "http://") ||
+		strings.HasPrefix(ref, "ssh://") ||
+		strings.HasPrefix(ref, "git@")
+}
+
+// Resolver loads the review files referenced by `include` and `extends`.
+// Local references are resolved against the reviews directory; references
+// inside a review file fetched from git are resolved against the same repository and version.
+type Resolver struct {
+	reviewsDir string
+	fetch      func(ctx context.Context, ref GitRef) ([]byte, error)
+
+	// noGit rejects git references, for review files that are not controlled by the server
+	noGit bool
+}
+
+// NewResolver creates a resolver for the given reviews directory
+func NewResolver(reviewsDir string) *Resolver {
+	return &Resolver{
+		reviewsDir: reviewsDir,
+		fetch:      fetchGitFile,
+	}
+}
+
+// withoutGit returns a copy of the resolver that rejects git references.
+// It resolves the review configs of repositories, which must not make the server fetch other repositories.
+func (r *Resolver) withoutGit() *Resolver {
+	if r == nil {
+		return nil
+	}
+	local := *r
+	local.noGit = true
+	return &local
+}
+
+// sourceFor returns the source name of a review file loaded from path.
+// Files inside the reviews directory are named relative to it.
+func (r *Resolver) sourceFor(filePath string) string {
+	if r == nil {
+		return filePath
+	}
+	absDir, err1 := filepath.Abs(r.reviewsDir)
+	absPath, err2 := filepath.Abs(filePath)
+	if err1 == nil && err2 == nil {
+		if rel, err := filepath.Rel(absDir, absPath); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filePath
+}
+
+// canonical returns the canonical source name of ref, referenced from source from
+func (r *Resolver) canonical(ref, from string) (string, error) {
+	gitRef, isGit, err := ParseGitRef(ref)
+	if err != nil {
+		return "", err
+	}
+	if isGit {
+		if r.noGit {
+			return "", fmt.Errorf("git reference %q is not allowed in a repository review config", ref)
+		}
+		return gitRef.String(), nil
+	}
+
+	// Local references inside a git review file stay in the same repository and version
+	if parent, parentIsGit, _ := ParseGitRef(from); parentIsGit {
+		local := path.Clean(path.Join(path.Dir(parent.Path), ref))
+		if path.IsAbs(ref) || local == ".." || strings.HasPrefix(local, "../") {
+			return "", fmt.Errorf("reference %q escapes the repository of %s", ref, from)
+		}
+		parent.Path = local
+		return parent.String(), nil
+	}
+
+	local := path.Clean(filepath.ToSlash(ref))
+	if path.IsAbs(local) || local == ".." || strings.HasPrefix(local, "../") {
+		return "", fmt.Errorf("reference %q must be a file in the reviews directory", ref)
+	}
+	return local, nil
+}
+
+// read returns the content of the review file with the given canonical source name
+func (r *Resolver) read(source string) ([]byte, error) {
+	gitRef, isGit, err := ParseGitRef(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if isGit {
+		ctx, cancel := context.WithTimeout(context.Background(), gitFetchTimeout)
+		defer cancel()
+		data, err = r.fetch(ctx, gitRef)
+	} else {
+		data, err = os.ReadFile(filepath.Join(r.reviewsDir, filepath.FromSlash(source)))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(expandEnvVars(string(data))), nil
+}
+
+// gitFileCache caches review files fetched from git repositories
+var gitFileCache = struct {
+	sync.Mutex
+	entries map[string]gitCacheEntry
+}{entries: make(map[string]gitCacheEntry)}
+
+// gitCacheEntry is a cached git review file
+type gitCacheEntry struct {
+	data      []byte
+	fetchedAt time.Time
+}
+
+// fetchGitFile fetches a single file from a git repository at the given version.
+// Only the requested version is fetched (shallow), and results are cached for a few minutes.
+func fetchGitFile(ctx context.Context, ref GitRef) ([]byte, error) {
+	key := ref.String()
+
+	gitFileCache.Lock()
+	entry, ok := gitFileCache.entries[key]
+	gitFileCache.Unlock()
+	if ok && time.Since(entry.fetchedAt) < gitCacheTTL {
+		return entry.data, nil
+	}
+
+	dir, err := os.MkdirTemp("", "verustcode-rules-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	run := func(args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return stdout.Bytes(), nil
+	}
+
+	if _, err := run("init", "-q"); err != nil {
+		return nil, err
+	}
+	if _, err := run("fetch", "-q", "--depth", "1", "--end-of-options", ref.RepoURL, ref.Version); err != nil {
+		return nil, err
+	}
+	data, err := run("show", "FETCH_HEAD:"+ref.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Fetched review file from git",
+		zap.String("repo_url", ref.RepoURL),
+		zap.String("path", ref.Path),
+		zap.String("version", ref.Version),
+	)
+
+	gitFileCache.Lock()
+	gitFileCache.entries[key] = gitCacheEntry{data: data, fetchedAt: time.Now()}
+	gitFileCache.Unlock()
+
+	return data, nil
+}
+
+// resolveState tracks the review files loaded while resolving one configuration
+type resolveState struct {
+	resolver *Resolver
+	stack    []string                      // sources being resolved, outermost first
+	files    map[string]*ReviewRulesConfig // resolved files by source
+	graph    map[string][]string           // include/extends edges by source
+}
+
+// onStack returns true if source is currently being resolved
+func (s *resolveState) onStack(source string) bool {
+	for _, f := range s.stack {
+		if f == source {
+			return true
+		}
+	}
+	return false
+}
+
+// addEdge records that from references to
+func (s *resolveState) addEdge(from, to string) {
+	if !containsString(s.graph[from], to) {
+		s.graph[from] = append(s.graph[from], to)
+	}
+}
+
+// Source returns the review file the rule is defined in (empty if unknown)
+func (r *ReviewRuleConfig) Source() string {
+	return r.source
+}
+
+// IncludeGraph returns the include and extends references between the review files
+// that were resolved to build this configuration, keyed by the referencing file
+func (config *ReviewRulesConfig) IncludeGraph() map[string][]string {
+	return config.includeGraph
+}
+
+// ParseFile parses YAML content of the review file at filePath, resolving
+// `include` and `extends` references with the given resolver.
+func (p *Parser) ParseFile(data []byte, filePath string, resolver *Resolver) (*ReviewRulesConfig, error) {
+	state := &resolveState{
+		resolver: resolver,
+		files:    make(map[string]*ReviewRulesConfig),
+		graph:    make(map[string][]string),
+	}
+	return p.parseSource(data, resolver.sourceFor(filePath), state)
+}
+
+// parseSource parses a review file and resolves its includes and extends.
+// Only the outermost file is validated, after all included rules are merged,
+// so that include cycles and rule ID collisions are reported by Validate.
+func (p *Parser) parseSource(data []byte, source string, state *resolveState) (*ReviewRulesConfig, error) {
+	var config ReviewRulesConfig
+	if err := unmarshalYAML(data, &config, p.strict); err != nil {
+		return nil, errors.Wrap(errors.ErrCodeConfigInvalid, fmt.Sprintf("failed to parse YAML%s", sourceSuffix(source)), err)
+	}
+
+	state.stack = append(state.stack, source)
+	defer func() { state.stack = state.stack[:len(state.stack)-1] }()
+
+	// Rules and areas from included files, deduplicated when a file is included more than once
+	var included []ReviewRuleConfig
+	var includedAreas []AreaDefinition
+	seen := make(map[string]bool)
+	for _, ref := range config.Include {
+		sub, err := p.loadSource(ref, source, state)
+		if err != nil {
+			return nil, err
+		}
+		if sub == nil {
+			continue // include cycle, reported by Validate
+		}
+		includedAreas = appendAreas(includedAreas, sub.Areas)
+		for _, rule := range sub.Rules {
+			key := rule.source + "#" + rule.ID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			included = append(included, rule)
+		}
+	}
+
+	// Resolve extends on the raw rules, so that explicitly set fields override the base rule
+	if err := p.resolveExtends(&config, source, state); err != nil {
+		return nil, err
+	}
+	for i := range config.Rules {
+		config.Rules[i].source = source
+	}
+	if err := p.loadPromptTemplates(&config, source, state); err != nil {
+		return nil, err
+	}
+
+	p.applyRuleBase(&config)
+
+	config.Rules = append(included, config.Rules...)
+	config.Areas = appendAreas(includedAreas, config.Areas)
+	config.Include = nil
+	config.includeGraph = state.graph
+
+	if len(state.stack) == 1 {
+		if err := p.Validate(&config); err != nil {
+			return nil, err
+		}
+	}
+	return &config, nil
+}
+
+// loadSource loads and resolves the review file referenced by ref from source.
+// Returns nil without error if the file is already being resolved (a cycle).
+func (p *Parser) loadSource(ref, from string, state *resolveState) (*ReviewRulesConfig, error) {
+	if state.resolver == nil {
+		return nil, errors.New(errors.ErrCodeConfigInvalid,
+			fmt.Sprintf("cannot resolve %q: include and extends are only supported for review files", ref))
+	}
+
+	source, err := state.resolver.canonical(ref, from)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeConfigInvalid, fmt.Sprintf("invalid reference%s", sourceSuffix(from)), err)
+	}
+	state.addEdge(from, source)
+
+	if state.onStack(source) {
+		return nil, nil
+	}
+	if config, ok := state.files[source]; ok {
+		return config, nil
+	}
+
+	data, err := state.resolver.read(source)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeConfigNotFound,
+			fmt.Sprintf("failed to load %s%s", source, sourceSuffix(from)), err)
+	}
+
+	config, err := p.parseSource(data, source, state)
+	if err != nil {
+		return nil, err
+	}
+	state.files[source] = config
+	return config, nil
+}
+
+// appendAreas appends areas, skipping areas with the same definition as an existing one.
+// Conflicting definitions of the same ID are kept and reported by Validate.
+func appendAreas(areas, more []AreaDefinition) []AreaDefinition {
+	for _, area := range more {
+		duplicate := false
+		for _, existing := range areas {
+			if existing == area {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			areas = append(areas, area)
+		}
+	}
+	return areas
+}
+
+// resolveExtends replaces each rule that extends another rule with the merged rule.
+// References have the form <file>#<rule-id>; "#<rule-id>" refers to a rule in the same file.
+func (p *Parser) resolveExtends(config *ReviewRulesConfig, source string, state *resolveState) error {
+	raw := make(map[string]ReviewRuleConfig, len(config.Rules))
+	for _, rule := range config.Rules {
+		if rule.ID != "" {
+			raw[rule.ID] = rule
+		}
+	}
+
+	resolved := make(map[string]ReviewRuleConfig)
+	var resolve func(rule ReviewRuleConfig, visiting []string) (ReviewRuleConfig, error)
+	resolve = func(rule ReviewRuleConfig, visiting []string) (ReviewRuleConfig, error) {
+		if rule.Extends == "" {
+			return rule, nil
+		}
+		if r, ok := resolved[rule.ID]; ok && rule.ID != "" {
+			return r, nil
+		}
+
+		file, baseID, ok := strings.Cut(rule.Extends, "#")
+		if !ok || baseID == "" {
+			return rule, errors.New(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("rule %s: extends %q must have the form <file>#<rule-id>", rule.ID, rule.Extends))
+		}
+
+		var base ReviewRuleConfig
+		if file == "" || (state.resolver != nil && p.sameSource(state.resolver, file, source)) {
+			// Rule in the same file
+			target, found := raw[baseID]
+			if !found {
+				return rule, errors.New(errors.ErrCodeConfigInvalid,
+					fmt.Sprintf("rule %s: extends %q: rule %s not found", rule.ID, rule.Extends, baseID))
+			}
+			if containsString(visiting, baseID) {
+				return rule, errors.New(errors.ErrCodeConfigInvalid,
+					fmt.Sprintf("rule %s: extends cycle: %s -> %s", rule.ID, strings.Join(visiting, " -> "), baseID))
+			}
+			r, err := resolve(target, append(visiting, baseID))
+			if err != nil {
+				return rule, err
+			}
+			base = r
+		} else {
+			sub, err := p.loadSource(file, source, state)
+			if err != nil {
+				return rule, err
+			}
+			if sub == nil {
+				return rule, errors.New(errors.ErrCodeConfigInvalid,
+					fmt.Sprintf("rule %s: extends %q: %s is already being resolved (cycle)", rule.ID, rule.Extends, file))
+			}
+			target := sub.GetRuleByID(baseID)
+			if target == nil {
+				return rule, errors.New(errors.ErrCodeConfigInvalid,
+					fmt.Sprintf("rule %s: extends %q: rule %s not found", rule.ID, rule.Extends, baseID))
+			}
+			base = *target
+		}
+
+		merged := p.mergeRule(rule, base)
+		if rule.ID != "" {
+			resolved[rule.ID] = merged
+		}
+		return merged, nil
+	}
+
+	for i, rule := range config.Rules {
+		merged, err := resolve(rule, []string{rule.ID})
+		if err != nil {
+			return err
+		}
+		config.Rules[i] = merged
+	}
+	return nil
+}
+
+// sameSource returns true if ref refers to the file source
+func (p *Parser) sameSource(resolver *Resolver, ref, source string) bool {
+	canonical, err := resolver.canonical(ref, source)
+	return err == nil && canonical == source
+}
+
+// mergeRule merges a rule over the rule it extends, with the rule's own fields taking precedence
+func (p *Parser) mergeRule(override, base ReviewRuleConfig) ReviewRuleConfig {
+	merged := base
+	merged.ID = mergeString(override.ID, base.ID)
+	merged.Description = mergeString(override.Description, base.Description)
+	merged.Agent = AgentConfig{
+		Type:     mergeString(override.Agent.Type, base.Agent.Type),
+		Model:    mergeString(override.Agent.Model, base.Agent.Model),
+		Fallback: mergeStringSlice(override.Agent.Fallback, base.Agent.Fallback),
+	}
+	merged.ReferenceDocs = mergeStringSlice(override.ReferenceDocs, base.ReferenceDocs)
+	merged.Goals = GoalsConfig{
+		Areas: mergeStringSlice(override.Goals.Areas, base.Goals.Areas),
+		Avoid: mergeStringSlice(override.Goals.Avoid, base.Goals.Avoid),
+	}
+	merged.Constraints = p.mergeConstraints(override.Constraints, base.Constraints)
+	if len(override.Policy) > 0 {
+		merged.Policy = override.Policy
+	}
+	if override.Output != nil {
+		merged.Output = p.mergeOutput(override.Output, base.Output)
+		if override.Output.Schema == nil && base.Output != nil {
+			// Unlike rule_base, an extended rule keeps its output schema
+			merged.Output.Schema = base.Output.Schema
+		}
+	}
+	if override.MultiRun != nil {
+		merged.MultiRun = override.MultiRun
+	}
+	if override.HistoryCompare != nil {
+		merged.HistoryCompare = override.HistoryCompare
+	}
+	if override.Chunking != nil {
+		merged.Chunking = override.Chunking
+	}
+	if override.When != nil {
+		merged.When = override.When
+	}
+	if len(override.Context) > 0 {
+		merged.Context = override.Context
+	}
+	if override.PromptTemplate != nil {
+		merged.PromptTemplate = override.PromptTemplate
+	}
+	if override.Cache != nil {
+		merged.Cache = override.Cache
+	}
+	merged.Locked = override.Locked || base.Locked
+	merged.Extends = ""
+	return merged
+}
+
+// validateIncludes returns an error if the include graph contains a cycle
+func (p *Parser) validateIncludes(graph map[string][]string) error {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var stack []string
+
+	var visit func(source string) []string
+	visit = func(source string) []string {
+		state[source] = visiting
+		stack = append(stack, source)
+		for _, next := range graph[source] {
+			switch state[next] {
+			case visiting:
+				for i, s := range stack {
+					if s == next {
+						return append(append([]string(nil), stack[i:]...), next)
+					}
+				}
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[source] = done
+		return nil
+	}
+
+	sources := make([]string, 0, len(graph))
+	for source := range graph {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	for _, source := range sources {
+		if state[source] == unvisited {
+			if cycle := visit(source); cycle != nil {
+				return errors.New(errors.ErrCodeConfigInvalid,
+					fmt.Sprintf("include cycle detected: %s", strings.Join(cycle, " -> ")))
+			}
+		}
+	}
+	return nil
+}
+
+// sourceName returns a display name for a review file source
+func sourceName(source string) string {
+	if source == "" {
+		return "this file"
+	}
+	return source
+}
+
+// sourceSuffix returns " in <source>" for error messages, or an empty string
+func sourceSuffix(source string) string {
+	if source == "" {
+		return ""
+	}
+	return " in " + source
+}
@@ -0,0 +1,349 @@
+package dsl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeReviewFiles writes review files into a temporary reviews directory
+func writeReviewFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// TestLoader_Load_IncludeAreas tests that custom areas of included files are merged
+func TestLoader_Load_IncludeAreas(t *testing.T) {
+	dir := writeReviewFiles(t, map[string]string{
+		"shared/areas.yaml": `
+version: "1.0"
+areas:
+  - id: feature-flags
+    description: Feature flag hygiene
+rules: []
+`,
+		"team.yaml": `
+version: "1.0"
+include: [shared/areas.yaml]
+areas:
+  - id: feature-flags
+    description: Feature flag hygiene
+rules:
+  - id: flags
+    description: Flags Reviewer
+    goals:
+      areas: [feature-flags]
+`,
+	})
+
+	config, err := loadWithResolver(dir, "team.yaml")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(config.Areas) != 1 || config.Areas[0].ID != "feature-flags" {
+		t.Errorf("Expected included area to be merged once, got %+v", config.Areas)
+	}
+}
+
+// loadWithResolver loads a review file with a resolver for dir
+func loadWithResolver(dir, name string) (*ReviewRulesConfig, error) {
+	loader := NewLoader()
+	loader.SetResolver(NewResolver(dir))
+	return loader.Load(filepath.Join(dir, name))
+}
+
+const sharedSecurityYAML = `
+version: "1.0"
+rule_base:
+  agent:
+    type: gemini
+rules:
+  - id: security
+    description: Shared security review
+    goals:
+      areas: [security-vulnerabilities, injection-attacks]
+      avoid: [style nitpicks]
+`
+
+func TestLoader_Load_IncludeAndExtends(t *testing.T) {
+	dir := writeReviewFiles(t, map[string]string{
+		"shared/security.yaml": sharedSecurityYAML,
+		"backend.yaml": `
+version: "1.0"
+include:
+  - shared/security.yaml
+rule_base:
+  agent:
+    type: cursor
+rules:
+  - id: strict-security
+    extends: shared/security.yaml#security
+    goals:
+      areas: [authentication]
+  - id: quality
+    goals:
+      areas: [readability]
+`,
+	})
+
+	config, err := loadWithResolver(dir, "backend.yaml")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if got := config.GetRuleIDs(); strings.Join(got, ",") != "security,strict-security,quality" {
+		t.Fatalf("Unexpected rule IDs: %v", got)
+	}
+
+	included := config.GetRuleByID("security")
+	if included.Agent.Type != "gemini" {
+		t.Errorf("Included rule should keep its file's rule_base agent, got %q", included.Agent.Type)
+	}
+	if included.Source() != "shared/security.yaml" {
+		t.Errorf("Included rule source = %q", included.Source())
+	}
+
+	extended := config.GetRuleByID("strict-security")
+	if strings.Join(extended.Goals.Areas, ",") != "authentication" {
+		t.Errorf("Extending rule should override areas, got %v", extended.Goals.Areas)
+	}
+	if len(extended.Goals.Avoid) != 1 || extended.Description != "Shared security review" {
+		t.Errorf("Extending rule should inherit unset fields, got %+v", extended)
+	}
+	if extended.Agent.Type != "gemini" || extended.Extends != "" {
+		t.Errorf("Unexpected extended rule: agent=%q extends=%q", extended.Agent.Type, extended.Extends)
+	}
+	if extended.Source() != "backend.yaml" {
+		t.Errorf("Extending rule source = %q", extended.Source())
+	}
+
+	if got := config.IncludeGraph()["backend.yaml"]; len(got) != 1 || got[0] != "shared/security.yaml" {
+		t.Errorf("Unexpected include graph: %v", config.IncludeGraph())
+	}
+}
+
+func TestLoader_Load_IncludeDiamond(t *testing.T) {
+	dir := writeReviewFiles(t, map[string]string{
+		"shared/security.yaml": sharedSecurityYAML,
+		"a.yaml":               "include: [shared/security.yaml]\nrules:\n  - id: a\n",
+		"b.yaml":               "include: [shared/security.yaml]\nrules:\n  - id: b\n",
+		"main.yaml":            "include: [a.yaml, b.yaml]\nrules:\n  - id: main\n",
+	})
+
+	config, err := loadWithResolver(dir, "main.yaml")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := strings.Join(config.GetRuleIDs(), ","); got != "security,a,b,main" {
+		t.Errorf("Unexpected rule IDs: %s", got)
+	}
+}
+
+func TestLoader_Load_IncludeCycle(t *testing.T) {
+	dir := writeReviewFiles(t, map[string]string{
+		"a.yaml": "include: [b.yaml]\nrules:\n  - id: a\n",
+		"b.yaml": "include: [a.yaml]\nrules:\n  - id: b\n",
+	})
+
+	_, err := loadWithResolver(dir, "a.yaml")
+	if err == nil || !strings.Contains(err.Error(), "include cycle detected: a.yaml -> b.yaml -> a.yaml") {
+		t.Fatalf("Expected include cycle error, got %v", err)
+	}
+}
+
+func TestLoader_Load_IncludeIDCollision(t *testing.T) {
+	dir := writeReviewFiles(t, map[string]string{
+		"shared/security.yaml": sharedSecurityYAML,
+		"main.yaml":            "include: [shared/security.yaml]\nrules:\n  - id: security\n",
+	})
+
+	_, err := loadWithResolver(dir, "main.yaml")
+	if err == nil || !strings.Contains(err.Error(), "defined in shared/security.yaml and main.yaml") {
+		t.Fatalf("Expected ID collision error, got %v", err)
+	}
+}
+
+func TestLoader_Load_IncludeErrors(t *testing.T) {
+	dir := writeReviewFiles(t, map[string]string{
+		"missing.yaml":  "include: [nope.yaml]\nrules:\n  - id: a\n",
+		"escape.yaml":   "include: [../outside.yaml]\nrules:\n  - id: a\n",
+		"badrule.yaml":  "include: []\nrules:\n  - id: a\n    extends: \"#nope\"\n",
+		"unpinned.yaml": "include: [\"https://example.com/rules.git//security.yaml\"]\nrules:\n  - id: a\n",
+	})
+
+	for _, name := range []string{"missing.yaml", "escape.yaml", "badrule.yaml", "unpinned.yaml"} {
+		if _, err := loadWithResolver(dir, name); err == nil {
+			t.Errorf("Load(%s) expected error, got nil", name)
+		}
+	}
+}
+
+func TestLoader_Load_IncludeGit(t *testing.T) {
+	dir := writeReviewFiles(t, map[string]string{
+		"main.yaml": `
+include:
+  - https://example.com/org/review-rules.git//reviews/security.yaml@v1.2.0
+rules:
+  - id: main
+    extends: https://example.com/org/review-rules.git//reviews/common.yaml@v1.2.0#common
+`,
+	})
+
+	remote := map[string]string{
+		"reviews/security.yaml": "include: [common.yaml]\nrules:\n  - id: security\n",
+		"reviews/common.yaml":   "rules:\n  - id: common\n    goals:\n      areas: [readability]\n",
+	}
+
+	var fetched []string
+	resolver := NewResolver(dir)
+	resolver.fetch = func(ctx context.Context, ref GitRef) ([]byte, error) {
+		fetched = append(fetched, ref.String())
+		if ref.RepoURL != "https://example.com/org/review-rules.git" || ref.Version != "v1.2.0" {
+			return nil, fmt.Errorf("unexpected ref %s", ref)
+		}
+		content, ok := remote[ref.Path]
+		if !ok {
+			return nil, fmt.Errorf("not found: %s", ref.Path)
+		}
+		return []byte(content), nil
+	}
+
+	loader := NewLoader()
+	loader.SetResolver(resolver)
+	config, err := loader.Load(filepath.Join(dir, "main.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if got := strings.Join(config.GetRuleIDs(), ","); got != "common,security,main" {
+		t.Errorf("Unexpected rule IDs: %s", got)
+	}
+	if got := config.GetRuleByID("main").Goals.Areas; len(got) != 1 || got[0] != "readability" {
+		t.Errorf("Expected areas inherited from git rule, got %v", got)
+	}
+	if len(fetched) != 2 {
+		t.Errorf("Expected each git file to be fetched once, got %v", fetched)
+	}
+}
+
+// TestLoader_LoadFromRepoRoot_GitReferences tests that repository review configs can't fetch git files
+func TestLoader_LoadFromRepoRoot_GitReferences(t *testing.T) {
+	reviewsDir := writeReviewFiles(t, map[string]string{
+		"shared.yaml": "rules:\n  - id: shared\n",
+	})
+	repoDir := writeReviewFiles(t, map[string]string{
+		".verust-review.yaml": "include:\n  - https://example.com/org/rules.git//security.yaml@v1\nrules:\n  - id: repo\n",
+	})
+
+	resolver := NewResolver(reviewsDir)
+	resolver.fetch = func(ctx context.Context, ref GitRef) ([]byte, error) {
+		t.Errorf("Unexpected fetch of %s", ref)
+		return nil, fmt.Errorf("unexpected fetch")
+	}
+	loader := NewLoader()
+	loader.SetResolver(resolver)
+
+	if _, err := loader.LoadFromRepoRoot(repoDir); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("LoadFromRepoRoot() expected git reference error, got %v", err)
+	}
+
+	// Server review files can still be included
+	if err := os.WriteFile(filepath.Join(repoDir, ".verust-review.yaml"), []byte("include: [shared.yaml]\nrules:\n  - id: repo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := loader.LoadFromRepoRoot(repoDir)
+	if err != nil {
+		t.Fatalf("LoadFromRepoRoot() error = %v", err)
+	}
+	if got := strings.Join(config.GetRuleIDs(), ","); got != "shared,repo" {
+		t.Errorf("Unexpected rule IDs: %s", got)
+	}
+}
+
+func TestParser_Parse_ExtendsSameFile(t *testing.T) {
+	yamlContent := `
+rules:
+  - id: base
+    description: Base rule
+    goals:
+      areas: [security-vulnerabilities]
+    cache: false
+  - id: derived
+    extends: "#base"
+    constraints:
+      severity:
+        min_report: high
+`
+	parser := NewParser()
+	config, err := parser.Parse([]byte(yamlContent))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	derived := config.GetRuleByID("derived")
+	if derived.Description != "Base rule" || derived.Constraints.Severity.MinReport != "high" {
+		t.Errorf("Unexpected derived rule: %+v", derived)
+	}
+	if derived.IsCacheable() {
+		t.Error("Derived rule should inherit cache: false")
+	}
+
+	// Include needs a resolver
+	if _, err := parser.Parse([]byte("include: [a.yaml]\n" + yamlContent)); err == nil {
+		t.Error("Parse() expected error for include without resolver")
+	}
+
+	// Extends cycles are rejected
+	cycle := "rules:\n  - id: a\n    extends: \"#b\"\n  - id: b\n    extends: \"#a\"\n"
+	if _, err := parser.Parse([]byte(cycle)); err == nil || !strings.Contains(err.Error(), "extends cycle") {
+		t.Errorf("Parse() expected extends cycle error, got %v", err)
+	}
+}
+
+func TestParseGitRef(t *testing.T) {
+	tests := []struct {
+		ref     string
+		isGit   bool
+		wantErr bool
+		want    GitRef
+	}{
+		{ref: "shared/security.yaml", isGit: false},
+		{
+			ref:   "https://github.com/org/rules.git//reviews/security.yaml@v1.2.0",
+			isGit: true,
+			want:  GitRef{RepoURL: "https://github.com/org/rules.git", Path: "reviews/security.yaml", Version: "v1.2.0"},
+		},
+		{
+			ref:   "git@github.com:org/rules.git//security.yaml@3f2c1a9",
+			isGit: true,
+			want:  GitRef{RepoURL: "git@github.com:org/rules.git", Path: "security.yaml", Version: "3f2c1a9"},
+		},
+		{ref: "https://github.com/org/rules.git//security.yaml", isGit: true, wantErr: true},
+		{ref: "https://github.com/org/rules.git@v1", isGit: true, wantErr: true},
+		{ref: "https://github.com/org/rules.git//../x.yaml@v1", isGit: true, wantErr: true},
+		{ref: "https://github.com/org/rules.git//x.yaml@--upload-pack=touch /tmp/pwned", isGit: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, isGit, err := ParseGitRef(tt.ref)
+		if isGit != tt.isGit || (err != nil) != tt.wantErr {
+			t.Errorf("ParseGitRef(%q) isGit=%v err=%v", tt.ref, isGit, err)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseGitRef(%q) = %+v, want %+v", tt.ref, got, tt.want)
+		}
+	}
+}
@@ -16,25 +16,38 @@ import (
 
 // Loader loads DSL configuration from files
 type Loader struct {
-	parser *Parser
+	parser   *Parser
+	resolver *Resolver
 }
 
 // NewLoader creates a new DSL loader
 func NewLoader() *Loader {
 	return &Loader{
-		parser: NewParser(),
+		parser:   NewParser(),
+		resolver: NewResolver(config.ReviewsDir),
 	}
 }
 
 // NewStrictLoader creates a new DSL loader with strict validation
 func NewStrictLoader() *Loader {
 	return &Loader{
-		parser: NewStrictParser(),
+		parser:   NewStrictParser(),
+		resolver: NewResolver(config.ReviewsDir),
 	}
 }
 
+// SetResolver sets the resolver used for include and extends references
+func (l *Loader) SetResolver(resolver *Resolver) {
+	l.resolver = resolver
+}
+
 // Load loads and parses a DSL configuration file
 func (l *Loader) Load(path string) (*ReviewRulesConfig, error) {
+	return l.load(path, l.resolver)
+}
+
+// load loads and parses a DSL configuration file, resolving includes and extends with resolver
+func (l *Loader) load(path string, resolver *Resolver) (*ReviewRulesConfig, error) {
 	logger.Debug("Loading DSL configuration",
 		zap.String("path", path),
 	)
@@ -53,8 +66,8 @@ func (l *Loader) Load(path string) (*ReviewRulesConfig, error) {
 	// Expand environment variables
 	expanded := expandEnvVars(string(data))
 
-	// Parse configuration
-	config, err := l.parser.Parse([]byte(expanded))
+	// Parse configuration, resolving includes and extends
+	config, err := l.parser.ParseFile([]byte(expanded), path, resolver)
 	if err != nil {
 		return nil, err
 	}
@@ -262,7 +275,8 @@ func (l *Loader) LoadFromRepoRoot(repoPath string) (*ReviewRulesConfig, error) {
 		zap.String("path", rootPath),
 	)
 
-	return l.Load(rootPath)
+	// The file is controlled by the repository, so it may only include server review files
+	return l.load(rootPath, l.resolver.withoutGit())
 }
 
 // LoadFromRepoEmbedded loads review configuration from embedded .verustcode/review.yaml in repository
@@ -284,7 +298,7 @@ func (l *Loader) LoadFromRepoEmbedded(repoPath string) (*ReviewRulesConfig, erro
 		zap.String("path", embeddedPath),
 	)
 
-	return l.Load(embeddedPath)
+	return l.load(embeddedPath, l.resolver.withoutGit())
 }
 
 // LoadReviewFile loads a specific review file from the reviews directory
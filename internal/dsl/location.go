@@ -0,0 +1,51 @@
+// Package dsl provides DSL configuration parsing and validation.
+// This file parses the location of review findings, shared by policy statements,
+// suppressions and evaluations.
+package dsl
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Location is the file and line range of a finding
+type Location struct {
+	Path      string
+	StartLine int // 0 if the location has no lines
+	EndLine   int
+}
+
+// locationPattern matches "path:start-end", "path:line" and "path:Lstart-Lend"
+var locationPattern = regexp.MustCompile(`^(.+?):L?(\d+)(?:\s*-\s*L?(\d+))?$`)
+
+// ParseLocation parses the location of a finding (e.g. "src/main.go:10-20")
+func ParseLocation(location string) Location {
+	location = strings.TrimSpace(location)
+	if match := locationPattern.FindStringSubmatch(location); match != nil {
+		start, _ := strconv.Atoi(match[2])
+		end := start
+		if match[3] != "" {
+			end, _ = strconv.Atoi(match[3])
+		}
+		if end < start {
+			start, end = end, start
+		}
+		return Location{Path: normalizeLocationPath(match[1]), StartLine: start, EndLine: end}
+	}
+	return Location{Path: normalizeLocationPath(location)}
+}
+
+// findingLocation returns the parsed location field of a finding
+func findingLocation(finding map[string]interface{}) Location {
+	location, _ := finding["location"].(string)
+	return ParseLocation(location)
+}
+
+// normalizeLocationPath returns a repository-relative slash path
+func normalizeLocationPath(file string) string {
+	file = filepath.ToSlash(strings.TrimSpace(file))
+	file = strings.TrimPrefix(file, "./")
+	return strings.TrimPrefix(file, "/")
+}
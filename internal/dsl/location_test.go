@@ -0,0 +1,20 @@
+package dsl
+
+import "testing"
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Location
+	}{
+		{"src/main.go:10-20", Location{Path: "src/main.go", StartLine: 10, EndLine: 20}},
+		{"./src/main.go:7", Location{Path: "src/main.go", StartLine: 7, EndLine: 7}},
+		{"main.go:L3-L1", Location{Path: "main.go", StartLine: 1, EndLine: 3}},
+		{"README.md", Location{Path: "README.md"}},
+	}
+	for _, tt := range tests {
+		if got := ParseLocation(tt.input); got != tt.want {
+			t.Errorf("ParseLocation(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Package dsl provides DSL configuration parsing and validation.
+// This file upgrades review and report files written for older DSL versions.
+package dsl
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/verustcode/verustcode/pkg/errors"
+)
+
+// CurrentDSLVersion is the version of the review and report DSL written by `verustcode dsl migrate`
+const CurrentDSLVersion = "1.0"
+
+// dslMigration upgrades a DSL file from one version to the next
+type dslMigration struct {
+	from        string
+	to          string
+	description string
+
+	// apply rewrites the root mapping of the file; the version key is updated afterwards
+	apply func(root *yaml.Node, kind string) error
+}
+
+// dslMigrations are applied in order, starting from the version of the file.
+// When the DSL changes incompatibly, bump CurrentDSLVersion and add a migration from the previous version.
+var dslMigrations = []dslMigration{
+	{
+		from:        "",
+		to:          "1.0",
+		description: "set the DSL version",
+		apply:       func(root *yaml.Node, kind string) error { return nil },
+	},
+}
+
+// MigrateDSL upgrades a review or report file to CurrentDSLVersion.
+// Returns the upgraded file and a description of each applied migration;
+// the file is returned unchanged if it is already at the current version.
+func MigrateDSL(data []byte, kind string) ([]byte, []string, error) {
+	doc, err := parseDSLNode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if doc == nil {
+		return data, nil, nil
+	}
+	root := doc.Content[0]
+
+	version := ""
+	versionNode := mappingValue(root, "version")
+	if versionNode != nil {
+		version = versionNode.Value
+	}
+
+	var applied []string
+	for _, migration := range dslMigrations {
+		if migration.from != version {
+			continue
+		}
+		if err := migration.apply(root, kind); err != nil {
+			return nil, nil, errors.Wrap(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("failed to migrate from version %q to %s", version, migration.to), err)
+		}
+		applied = append(applied, fmt.Sprintf("%s -> %s: %s", displayVersion(version), migration.to, migration.description))
+		version = migration.to
+	}
+
+	if version != CurrentDSLVersion {
+		return nil, nil, errors.New(errors.ErrCodeConfigInvalid,
+			fmt.Sprintf("unsupported DSL version %q (current: %s)", version, CurrentDSLVersion))
+	}
+	if len(applied) == 0 {
+		return data, nil, nil
+	}
+
+	if versionNode == nil {
+		versionNode = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str"}
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+		root.Content = append([]*yaml.Node{key, versionNode}, root.Content...)
+	}
+	versionNode.Value = version
+	versionNode.Tag = "!!str"
+	versionNode.Style = yaml.DoubleQuotedStyle
+
+	out, err := encodeDSLNode(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, applied, nil
+}
+
+// mappingValue returns the value of a key in a mapping node, or nil if the key is not set
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// displayVersion returns a version for messages
+func displayVersion(version string) string {
+	if version == "" {
+		return "(none)"
+	}
+	return version
+}
@@ -1,7 +1,9 @@
 package dsl
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"strings"
 
 	"go.uber.org/zap"
@@ -64,23 +66,25 @@ func NewStrictParser() *Parser {
 	}
 }
 
-// Parse parses YAML content into ReviewRulesConfig
+// Parse parses YAML content into ReviewRulesConfig.
+// Rules may extend other rules in the same content; use ParseFile to resolve
+// references to other review files.
 func (p *Parser) Parse(data []byte) (*ReviewRulesConfig, error) {
-	var config ReviewRulesConfig
+	return p.ParseFile(data, "", nil)
+}
 
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, errors.Wrap(errors.ErrCodeConfigInvalid, "failed to parse YAML", err)
+// unmarshalYAML decodes a YAML document into out.
+// In strict mode, keys that don't map to a field (e.g. misspelled settings) are errors.
+func unmarshalYAML(data []byte, out interface{}, strict bool) error {
+	if !strict {
+		return yaml.Unmarshal(data, out)
 	}
-
-	// Apply rule base to each review rule
-	p.applyRuleBase(&config)
-
-	// Validate the configuration
-	if err := p.Validate(&config); err != nil {
-		return nil, err
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(out); err != nil && err != io.EOF {
+		return err
 	}
-
-	return &config, nil
+	return nil
 }
 
 // applyRuleBase applies base configuration to review rules
@@ -105,6 +109,9 @@ func (p *Parser) applyRuleBase(config *ReviewRulesConfig) {
 			}
 			// Note: defaultConfig.Agent.Model is empty, so no else branch needed
 		}
+		if len(rule.Agent.Fallback) == 0 && ruleBase != nil && len(ruleBase.Agent.Fallback) > 0 {
+			rule.Agent.Fallback = append([]string(nil), ruleBase.Agent.Fallback...)
+		}
 
 		// Apply Constraints defaults
 		if rule.Constraints == nil {
@@ -122,6 +129,11 @@ func (p *Parser) applyRuleBase(config *ReviewRulesConfig) {
 			}
 		}
 
+		// Apply Policy default (a rule's own policy replaces the base policy)
+		if len(rule.Policy) == 0 && ruleBase != nil && len(ruleBase.Policy) > 0 {
+			rule.Policy = append([]PolicyStatementConfig(nil), ruleBase.Policy...)
+		}
+
 		// Apply Output defaults
 		if rule.Output == nil {
 			if ruleBase != nil && ruleBase.Output != nil {
@@ -259,31 +271,53 @@ func (p *Parser) mergeOutputStyle(override, base *OutputStyleConfig) *OutputStyl
 
 // Validate validates the ReviewRulesConfig
 func (p *Parser) Validate(config *ReviewRulesConfig) error {
+	// Check include cycles before rules, since a cycle may leave a file without rules
+	if err := p.validateIncludes(config.includeGraph); err != nil {
+		return err
+	}
+
 	if len(config.Rules) == 0 {
 		return errors.New(errors.ErrCodeConfigInvalid, "at least one review rule is required")
 	}
 
-	// Track rule IDs for uniqueness check
-	ids := make(map[string]bool)
+	// Validate custom areas
+	fileAreas := make(map[string]bool, len(config.Areas))
+	for i, area := range config.Areas {
+		if err := ValidateAreaDefinition(area); err != nil {
+			return errors.New(errors.ErrCodeConfigInvalid, fmt.Sprintf("areas[%d]: %v", i, err))
+		}
+		if fileAreas[area.ID] {
+			return errors.New(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("areas[%d]: duplicate area ID %q with a different definition", i, area.ID))
+		}
+		fileAreas[area.ID] = true
+	}
+
+	// Track rule IDs (and the files defining them) for uniqueness check
+	ids := make(map[string]string)
 
 	for i, rule := range config.Rules {
-		if err := p.validateRule(&rule, i); err != nil {
+		if err := p.validateRule(&rule, i, fileAreas); err != nil {
 			return err
 		}
 
-		// Check for duplicate IDs
-		if ids[rule.ID] {
+		// Check for duplicate IDs, including collisions between included files
+		if source, ok := ids[rule.ID]; ok {
+			if source != rule.source {
+				return errors.New(errors.ErrCodeConfigInvalid,
+					fmt.Sprintf("duplicate review rule ID: %s (defined in %s and %s)", rule.ID, sourceName(source), sourceName(rule.source)))
+			}
 			return errors.New(errors.ErrCodeConfigInvalid,
 				fmt.Sprintf("duplicate review rule ID: %s", rule.ID))
 		}
-		ids[rule.ID] = true
+		ids[rule.ID] = rule.source
 	}
 
 	return nil
 }
 
 // validateRule validates a single review rule configuration
-func (p *Parser) validateRule(rule *ReviewRuleConfig, index int) error {
+func (p *Parser) validateRule(rule *ReviewRuleConfig, index int, fileAreas map[string]bool) error {
 	prefix := fmt.Sprintf("rule[%d]", index)
 
 	// ID is required
@@ -301,7 +335,12 @@ func (p *Parser) validateRule(rule *ReviewRuleConfig, index int) error {
 	}
 
 	// Validate area definitions (warn if unknown areas are used)
-	if err := p.validateAreas(rule.Goals.Areas, prefix, rule.ID); err != nil {
+	if err := p.validateAreas(rule.Goals.Areas, fileAreas, prefix, rule.ID); err != nil {
+		return err
+	}
+
+	// Validate agent fallback chain
+	if err := p.validateAgentFallback(&rule.Agent, prefix, rule.ID); err != nil {
 		return err
 	}
 
@@ -319,6 +358,13 @@ func (p *Parser) validateRule(rule *ReviewRuleConfig, index int) error {
 		}
 	}
 
+	// Validate Policy
+	if len(rule.Policy) > 0 {
+		if err := p.validatePolicy(rule.Policy, prefix, rule.ID); err != nil {
+			return err
+		}
+	}
+
 	// Validate Output
 	if rule.Output != nil {
 		if err := p.validateOutput(rule.Output, prefix, rule.ID); err != nil {
@@ -333,6 +379,34 @@ func (p *Parser) validateRule(rule *ReviewRuleConfig, index int) error {
 		}
 	}
 
+	// Validate Chunking
+	if rule.Chunking != nil {
+		if err := p.validateChunking(rule.Chunking, prefix, rule.ID); err != nil {
+			return err
+		}
+	}
+
+	// Validate When
+	if rule.When != nil {
+		if err := p.validateWhen(rule.When, prefix, rule.ID); err != nil {
+			return err
+		}
+	}
+
+	// Validate Context providers
+	if len(rule.Context) > 0 {
+		if err := p.validateContext(rule.Context, prefix, rule.ID); err != nil {
+			return err
+		}
+	}
+
+	// Validate PromptTemplate
+	if rule.PromptTemplate != nil {
+		if err := p.validatePromptTemplate(rule.PromptTemplate, prefix, rule.ID); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -370,11 +444,18 @@ func (p *Parser) validateSeverity(severity *SeverityConfig, prefix, id string) e
 	return nil
 }
 
+// Allowed values of output settings
+var (
+	validOutputFormats = []string{consts.OutputFormatMarkdown, consts.OutputFormatJSON}
+	validChannelTypes  = []string{"file", "comment", "webhook"}
+	validOutputTones   = []string{"strict", "constructive", "neutral", "friendly", "professional"}
+)
+
 // validateOutput validates output configuration
 func (p *Parser) validateOutput(output *OutputConfig, prefix, id string) error {
-	validFormats := []string{consts.OutputFormatMarkdown, consts.OutputFormatJSON}
-	validTypes := []string{"file", "comment", "webhook"}
-	validTones := []string{"strict", "constructive", "neutral", "friendly", "professional"}
+	validFormats := validOutputFormats
+	validTypes := validChannelTypes
+	validTones := validOutputTones
 
 	// Validate Style
 	if output.Style != nil {
@@ -472,6 +553,71 @@ func (p *Parser) validateMultiRun(multiRun *MultiRunConfig, prefix, id string) e
 	return nil
 }
 
+// validateChunking validates chunking configuration
+func (p *Parser) validateChunking(chunking *ChunkingConfig, prefix, id string) error {
+	if chunking.MaxFiles < 0 {
+		return errors.New(errors.ErrCodeConfigInvalid,
+			fmt.Sprintf("%s (%s): chunking.max_files must not be negative, got: %d",
+				prefix, id, chunking.MaxFiles))
+	}
+	if chunking.MaxDiffLines < 0 {
+		return errors.New(errors.ErrCodeConfigInvalid,
+			fmt.Sprintf("%s (%s): chunking.max_diff_lines must not be negative, got: %d",
+				prefix, id, chunking.MaxDiffLines))
+	}
+	return nil
+}
+
+// validateWhen validates a rule's when clause
+func (p *Parser) validateWhen(when *WhenConfig, prefix, id string) error {
+	patterns := map[string][]string{"when.branches": when.Branches}
+	if when.Paths != nil {
+		patterns["when.paths.include"] = when.Paths.Include
+		patterns["when.paths.exclude"] = when.Paths.Exclude
+	}
+	for field, list := range patterns {
+		for i, pattern := range list {
+			if pattern == "" || !validGlob(pattern) {
+				return errors.New(errors.ErrCodeConfigInvalid,
+					fmt.Sprintf("%s (%s): %s[%d]: invalid glob pattern %q", prefix, id, field, i, pattern))
+			}
+		}
+	}
+
+	if d := when.DiffSize; d != nil {
+		if d.MinLines < 0 || d.MaxLines < 0 || d.MinFiles < 0 || d.MaxFiles < 0 {
+			return errors.New(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("%s (%s): when.diff_size values must not be negative", prefix, id))
+		}
+		if d.MaxLines > 0 && d.MinLines > d.MaxLines {
+			return errors.New(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("%s (%s): when.diff_size.min_lines (%d) exceeds max_lines (%d)", prefix, id, d.MinLines, d.MaxLines))
+		}
+		if d.MaxFiles > 0 && d.MinFiles > d.MaxFiles {
+			return errors.New(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("%s (%s): when.diff_size.min_files (%d) exceeds max_files (%d)", prefix, id, d.MinFiles, d.MaxFiles))
+		}
+	}
+	return nil
+}
+
+// validateAgentFallback validates the agent fallback chain
+func (p *Parser) validateAgentFallback(agent *AgentConfig, prefix, id string) error {
+	seen := map[string]bool{agent.GetType(): true}
+	for i, name := range agent.Fallback {
+		if name == "" {
+			return errors.New(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("%s (%s): agent.fallback[%d] must not be empty", prefix, id, i))
+		}
+		if seen[name] {
+			return errors.New(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("%s (%s): agent.fallback[%d]: agent %q is already the primary agent or listed earlier", prefix, id, i, name))
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
 // GetRuleByID returns a review rule by ID
 func (config *ReviewRulesConfig) GetRuleByID(id string) *ReviewRuleConfig {
 	for i := range config.Rules {
@@ -491,12 +637,13 @@ func (config *ReviewRulesConfig) GetRuleIDs() []string {
 	return ids
 }
 
-// validateAreas validates if all areas are defined
+// validateAreas validates if all areas are defined (built-in, organization-wide custom areas,
+// or declared in the `areas:` section of the review file)
 // In both strict and non-strict modes, only logs warnings without returning errors
 // This maintains flexibility and allows users to use custom areas
-func (p *Parser) validateAreas(areas []string, prefix, ruleID string) error {
+func (p *Parser) validateAreas(areas []string, fileAreas map[string]bool, prefix, ruleID string) error {
 	for _, area := range areas {
-		if !IsValidArea(area) {
+		if !fileAreas[area] && !IsValidArea(area) {
 			logger.Warn("undefined area used in configuration",
 				zap.String("rule_id", ruleID),
 				zap.String("area", area),
@@ -1,6 +1,7 @@
 package dsl
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -1002,3 +1003,258 @@ rules:
 		t.Error("Schema.ExtraFields should NOT be inherited from rule_base")
 	}
 }
+
+func TestParser_Parse_Chunking(t *testing.T) {
+	yamlContent := `
+version: "1.0"
+rules:
+  - id: large-pr
+    description: Large PR Reviewer
+    goals:
+      areas:
+        - security
+    chunking:
+      max_files: 40
+      max_diff_lines: 3000
+      group_by_directory: true
+`
+
+	parser := NewParser()
+	config, err := parser.Parse([]byte(yamlContent))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	chunking := config.Rules[0].Chunking
+	if !chunking.IsEnabled() {
+		t.Fatal("Expected chunking to be enabled")
+	}
+	if chunking.MaxFiles != 40 || chunking.MaxDiffLines != 3000 || !chunking.GroupByDirectory {
+		t.Errorf("Unexpected chunking config: %+v", chunking)
+	}
+}
+
+func TestParser_Parse_InvalidChunking(t *testing.T) {
+	yamlContent := `
+version: "1.0"
+rules:
+  - id: large-pr
+    description: Large PR Reviewer
+    goals:
+      areas:
+        - security
+    chunking:
+      max_files: -1
+`
+
+	parser := NewParser()
+	_, err := parser.Parse([]byte(yamlContent))
+
+	if err == nil {
+		t.Error("Parse() expected error for negative max_files, got nil")
+	}
+}
+
+func TestParser_Parse_AgentFallback(t *testing.T) {
+	yamlContent := `
+version: "1.0"
+rule_base:
+  agent:
+    type: cursor
+    fallback: [gemini, qoder]
+rules:
+  - id: security
+    description: Security Reviewer
+    goals:
+      areas:
+        - security
+  - id: duplicate
+    description: Duplicate Fallback
+    agent:
+      type: gemini
+      fallback: [cursor, cursor]
+    goals:
+      areas:
+        - security
+`
+
+	parser := NewParser()
+	_, err := parser.Parse([]byte(yamlContent))
+	if err == nil {
+		t.Fatal("Parse() expected error for duplicate fallback agent, got nil")
+	}
+
+	// Without the invalid rule, fallback is inherited from rule_base
+	yamlContent = yamlContent[:strings.Index(yamlContent, "  - id: duplicate")]
+	config, err := parser.Parse([]byte(yamlContent))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := config.Rules[0].Agent.Fallback; len(got) != 2 || got[0] != "gemini" || got[1] != "qoder" {
+		t.Errorf("Expected fallback inherited from rule_base, got %v", got)
+	}
+}
+
+func TestParser_Parse_When(t *testing.T) {
+	yamlContent := `
+version: "1.0"
+rules:
+  - id: migrations
+    description: Migration Reviewer
+    when:
+      paths:
+        include: ["db/migrations/**"]
+      branches: [main, "release/*"]
+      exclude_authors: ["dependabot[bot]"]
+      diff_size:
+        max_lines: 2000
+    goals:
+      areas:
+        - security
+`
+
+	parser := NewParser()
+	config, err := parser.Parse([]byte(yamlContent))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	when := config.Rules[0].When
+	if when == nil || when.Paths == nil || len(when.Paths.Include) != 1 {
+		t.Fatalf("Expected when.paths to be parsed, got %+v", when)
+	}
+	if len(when.Branches) != 2 || when.DiffSize == nil || when.DiffSize.MaxLines != 2000 {
+		t.Errorf("Unexpected when clause: %+v", when)
+	}
+
+	invalid := []string{
+		"when:\n      paths:\n        include: [\"db/[migrations\"]",
+		"when:\n      branches: [\"\"]",
+		"when:\n      diff_size:\n        max_lines: -1",
+		"when:\n      diff_size:\n        min_files: 10\n        max_files: 5",
+	}
+	for _, when := range invalid {
+		content := `
+version: "1.0"
+rules:
+  - id: invalid
+    description: Invalid When
+    ` + when + `
+    goals:
+      areas:
+        - security
+`
+		if _, err := parser.Parse([]byte(content)); err == nil {
+			t.Errorf("Parse() expected error for %q, got nil", when)
+		}
+	}
+}
+
+// TestParser_Parse_Areas tests custom areas declared in a review file
+func TestParser_Parse_Areas(t *testing.T) {
+	yamlContent := `
+version: "1.0"
+areas:
+  - id: pci-dss-logging
+    group: compliance
+    description: PCI-DSS logging requirements
+rules:
+  - id: payments
+    description: Payments Reviewer
+    goals:
+      areas:
+        - pci-dss-logging
+        - security
+`
+
+	parser := NewParser()
+	config, err := parser.Parse([]byte(yamlContent))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(config.Areas) != 1 || config.Areas[0].Group != "compliance" {
+		t.Fatalf("Expected 1 custom area, got %+v", config.Areas)
+	}
+
+	invalid := []string{
+		"areas:\n  - id: Bad_ID\n    description: Bad",
+		"areas:\n  - id: business-logic\n    description: Built-in",
+		"areas:\n  - id: no-description",
+		"areas:\n  - id: dup\n    description: One\n  - id: dup\n    description: Two",
+	}
+	for _, areas := range invalid {
+		content := `
+version: "1.0"
+` + areas + `
+rules:
+  - id: invalid
+    description: Invalid Areas
+    goals:
+      areas:
+        - security
+`
+		if _, err := parser.Parse([]byte(content)); err == nil {
+			t.Errorf("Parse() expected error for %q, got nil", areas)
+		}
+	}
+}
+
+// TestParser_Parse_Context tests parsing and validating context providers
+func TestParser_Parse_Context(t *testing.T) {
+	yamlContent := `
+version: "1.0"
+rules:
+  - id: lint
+    description: Lint Reviewer
+    context:
+      - type: command
+        command: golangci-lint run --out-format json
+        timeout: 120
+      - type: file
+        paths: ["docs/**/*.md"]
+        max_files: 3
+      - type: diff
+        context_lines: 10
+      - type: git_log
+        max_commits: 5
+    goals:
+      areas:
+        - business-logic
+`
+
+	parser := NewParser()
+	config, err := parser.Parse([]byte(yamlContent))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	providers := config.Rules[0].Context
+	if len(providers) != 4 || providers[0].Timeout != 120 || *providers[2].ContextLines != 10 {
+		t.Fatalf("Unexpected context providers: %+v", providers)
+	}
+	if providers[0].Title() != "golangci-lint run --out-format json" {
+		t.Errorf("Title() = %q", providers[0].Title())
+	}
+
+	invalid := []string{
+		"context:\n      - type: shell\n        command: ls",
+		"context:\n      - type: command",
+		"context:\n      - type: command\n        command: go vet\n        timeout: 3600",
+		"context:\n      - type: file",
+		"context:\n      - type: file\n        paths: [\"docs/[x\"]",
+		"context:\n      - type: diff\n        context_lines: -1",
+	}
+	for _, ctx := range invalid {
+		content := `
+version: "1.0"
+rules:
+  - id: invalid
+    description: Invalid Context
+    ` + ctx + `
+    goals:
+      areas:
+        - business-logic
+`
+		if _, err := parser.Parse([]byte(content)); err == nil {
+			t.Errorf("Parse() expected error for %q, got nil", ctx)
+		}
+	}
+}
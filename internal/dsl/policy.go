@@ -0,0 +1,283 @@
+// Package dsl provides DSL configuration parsing and validation.
+// This file defines policy statements: deterministic transforms of the structured
+// findings of a rule (severity remapping, dropping, per-file caps), applied after
+// the agent output is parsed.
+package dsl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/verustcode/verustcode/pkg/errors"
+)
+
+// MaxPolicyStatements is the maximum number of policy statements per rule
+const MaxPolicyStatements = 50
+
+// PolicyStatementConfig is a policy statement. Statements are applied in order to the
+// findings their when expression matches (all findings if when is empty).
+// Each statement has exactly one action.
+// Example YAML:
+//
+//	policy:
+//	  - name: sql-injection-is-critical
+//	    when: category == "sql-injection"
+//	    set_severity: critical
+//	  - when: file.glob("**/*_test.go")
+//	    adjust_severity: -1
+//	  - when: title.matches("(?i)consider adding (a )?comment")
+//	    drop: true
+//	  - max_per_file: 5
+type PolicyStatementConfig struct {
+	// Name identifies the statement in logs and in the findings it changed (optional)
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	// When is an expression over the fields of a finding (see PolicyExpr)
+	When string `yaml:"when,omitempty" json:"when,omitempty"`
+
+	// SetSeverity sets the severity of matching findings
+	SetSeverity string `yaml:"set_severity,omitempty" json:"set_severity,omitempty"`
+
+	// AdjustSeverity raises (positive) or lowers (negative) the severity of matching findings
+	// by a number of levels, staying within info..critical
+	AdjustSeverity int `yaml:"adjust_severity,omitempty" json:"adjust_severity,omitempty"`
+
+	// Drop removes matching findings
+	Drop bool `yaml:"drop,omitempty" json:"drop,omitempty"`
+
+	// MaxPerFile keeps at most this many matching findings per file, the most severe first
+	MaxPerFile int `yaml:"max_per_file,omitempty" json:"max_per_file,omitempty"`
+}
+
+// label returns the name of a statement, or its position
+func (s *PolicyStatementConfig) label(index int) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return fmt.Sprintf("policy[%d]", index)
+}
+
+// actionCount returns the number of actions of a statement
+func (s *PolicyStatementConfig) actionCount() int {
+	count := 0
+	for _, set := range []bool{s.SetSeverity != "", s.AdjustSeverity != 0, s.Drop, s.MaxPerFile != 0} {
+		if set {
+			count++
+		}
+	}
+	return count
+}
+
+// validatePolicy validates the policy statements of a rule
+func (p *Parser) validatePolicy(policy []PolicyStatementConfig, prefix, id string) error {
+	if len(policy) > MaxPolicyStatements {
+		return errors.New(errors.ErrCodeConfigInvalid,
+			fmt.Sprintf("%s (%s): policy cannot exceed %d statements, got %d", prefix, id, MaxPolicyStatements, len(policy)))
+	}
+	for i := range policy {
+		statement := &policy[i]
+		where := fmt.Sprintf("%s (%s): %s", prefix, id, statement.label(i))
+
+		if statement.actionCount() != 1 {
+			return errors.New(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("%s: exactly one of set_severity, adjust_severity, drop and max_per_file is required", where))
+		}
+		if statement.SetSeverity != "" && !containsString(SeverityLevels, statement.SetSeverity) {
+			return errors.New(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("%s: invalid set_severity: %s (valid: %s)", where, statement.SetSeverity, strings.Join(SeverityLevels, ", ")))
+		}
+		if statement.MaxPerFile < 0 {
+			return errors.New(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("%s: max_per_file must be positive, got %d", where, statement.MaxPerFile))
+		}
+		if statement.When != "" {
+			if _, err := CompilePolicyExpr(statement.When); err != nil {
+				return errors.New(errors.ErrCodeConfigInvalid,
+					fmt.Sprintf("%s: invalid when expression: %s", where, exprMessage(err)))
+			}
+		}
+	}
+	return nil
+}
+
+// PolicyResult summarizes the changes of a policy to the findings of a rule
+type PolicyResult struct {
+	// SeverityChanged is the number of findings whose severity was changed
+	SeverityChanged int
+
+	// Dropped is the number of findings removed by drop or max_per_file
+	Dropped int
+
+	// Errors lists statements that failed to evaluate; the failing findings are left unchanged
+	Errors []string
+}
+
+// Changed returns true if the policy changed any finding
+func (r *PolicyResult) Changed() bool {
+	return r.SeverityChanged > 0 || r.Dropped > 0
+}
+
+// ApplyPolicy applies policy statements to findings (the items of a result's findings list).
+// Findings are changed in place: a finding whose severity changes records its original
+// severity in original_severity and the names of the statements in policy.
+// Returns the findings that are kept.
+func ApplyPolicy(findings []interface{}, policy []PolicyStatementConfig) ([]interface{}, PolicyResult) {
+	var result PolicyResult
+	changedSeverity := make(map[int]bool) // by index in findings
+
+	// Track findings by their original index so that changed findings are counted once
+	type indexed struct {
+		index   int
+		finding map[string]interface{}
+	}
+	current := make([]indexed, 0, len(findings))
+	var others []interface{} // items that are not findings objects are kept as they are
+	for i, item := range findings {
+		if finding, ok := item.(map[string]interface{}); ok {
+			current = append(current, indexed{i, finding})
+		} else {
+			others = append(others, item)
+		}
+	}
+
+	for i := range policy {
+		statement := &policy[i]
+		label := statement.label(i)
+
+		var expr *PolicyExpr
+		if statement.When != "" {
+			compiled, err := CompilePolicyExpr(statement.When)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", label, exprMessage(err)))
+				continue
+			}
+			expr = compiled
+		}
+		failed := false
+		matches := func(finding map[string]interface{}) bool {
+			if expr == nil {
+				return true
+			}
+			ok, err := expr.EvalBool(finding)
+			if err != nil {
+				if !failed {
+					// Report each failing statement once
+					failed = true
+					result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", label, exprMessage(err)))
+				}
+				return false
+			}
+			return ok
+		}
+
+		switch {
+		case statement.Drop:
+			kept := current[:0]
+			for _, f := range current {
+				if matches(f.finding) {
+					result.Dropped++
+					continue
+				}
+				kept = append(kept, f)
+			}
+			current = kept
+
+		case statement.MaxPerFile > 0:
+			// Rank matching findings per file, most severe first (stable for equal severities)
+			byFile := make(map[string][]int) // positions in current
+			for pos, f := range current {
+				if matches(f.finding) {
+					file := findingLocation(f.finding).Path
+					byFile[file] = append(byFile[file], pos)
+				}
+			}
+			drop := make(map[int]bool)
+			for _, positions := range byFile {
+				if len(positions) <= statement.MaxPerFile {
+					continue
+				}
+				sort.SliceStable(positions, func(a, b int) bool {
+					return findingSeverityLevel(current[positions[a]].finding) > findingSeverityLevel(current[positions[b]].finding)
+				})
+				for _, pos := range positions[statement.MaxPerFile:] {
+					drop[pos] = true
+				}
+			}
+			kept := current[:0]
+			for pos, f := range current {
+				if drop[pos] {
+					result.Dropped++
+					continue
+				}
+				kept = append(kept, f)
+			}
+			current = kept
+
+		default:
+			for _, f := range current {
+				if !matches(f.finding) {
+					continue
+				}
+				severity, _ := f.finding["severity"].(string)
+				newSeverity := statement.SetSeverity
+				if newSeverity == "" {
+					newSeverity = adjustSeverity(severity, statement.AdjustSeverity)
+				}
+				if newSeverity == "" || newSeverity == severity {
+					continue
+				}
+				if _, recorded := f.finding["original_severity"]; !recorded {
+					f.finding["original_severity"] = severity
+				}
+				f.finding["severity"] = newSeverity
+				f.finding["policy"] = appendPolicyName(f.finding["policy"], label)
+				changedSeverity[f.index] = true
+			}
+		}
+	}
+
+	kept := make([]interface{}, 0, len(current)+len(others))
+	for _, f := range current {
+		// Findings changed and then dropped only count as dropped
+		if changedSeverity[f.index] {
+			result.SeverityChanged++
+		}
+		kept = append(kept, f.finding)
+	}
+	return append(kept, others...), result
+}
+
+// adjustSeverity moves a severity by a number of levels, within info..critical.
+// Returns "" for unknown severities.
+func adjustSeverity(severity string, levels int) string {
+	level := SeverityLevel(severity)
+	if level < 0 {
+		return ""
+	}
+	level += levels
+	if level < 0 {
+		level = 0
+	}
+	if level >= len(SeverityLevels) {
+		level = len(SeverityLevels) - 1
+	}
+	return SeverityLevels[level]
+}
+
+// findingSeverityLevel returns the severity level of a finding (-1 if unknown)
+func findingSeverityLevel(finding map[string]interface{}) int {
+	severity, _ := finding["severity"].(string)
+	return SeverityLevel(severity)
+}
+
+// appendPolicyName adds a statement name to the policy field of a finding
+func appendPolicyName(existing interface{}, name string) []interface{} {
+	names, _ := existing.([]interface{})
+	for _, n := range names {
+		if n == name {
+			return names
+		}
+	}
+	return append(names, name)
+}
@@ -0,0 +1,1005 @@
+// Package dsl provides DSL configuration parsing and validation.
+// This file implements the expression language of policy statements, a small
+// CEL-like language evaluated against a single finding.
+package dsl
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/verustcode/verustcode/pkg/errors"
+)
+
+// PolicyExpr is a compiled policy expression.
+//
+// Expressions are evaluated against a finding. Identifiers are finding fields
+// (severity, category, title, description, location, suggestion and extra fields),
+// plus file, line and end_line parsed from location, and finding for the whole finding.
+// Unknown fields are null.
+//
+// Supported syntax:
+//
+//	literals     "text", 'text', 42, 1.5, true, false, null, [a, b]
+//	operators    ! - * / % + - < <= > >= == != in && ||
+//	access       finding.field, finding["field"], list[0]
+//	methods      s.matches(re), s.contains(x), s.startsWith(x), s.endsWith(x),
+//	             s.lower(), s.upper(), file.glob(pattern), x.size()
+//	functions    size(x), has(field), level(severity)
+//
+// Severity names compare by level: severity >= "high" matches high and critical.
+type PolicyExpr struct {
+	source string
+	root   exprNode
+}
+
+// String returns the source of the expression
+func (e *PolicyExpr) String() string {
+	return e.source
+}
+
+// CompilePolicyExpr parses a policy expression
+func CompilePolicyExpr(source string) (*PolicyExpr, error) {
+	tokens, err := lexExpr(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, exprErrorf("unexpected %s at position %d", tok, tok.pos)
+	}
+	return &PolicyExpr{source: source, root: root}, nil
+}
+
+// Eval evaluates the expression against a finding
+func (e *PolicyExpr) Eval(finding map[string]interface{}) (interface{}, error) {
+	return e.root.eval(newExprEnv(finding))
+}
+
+// EvalBool evaluates the expression against a finding; the result must be a boolean
+func (e *PolicyExpr) EvalBool(finding map[string]interface{}) (bool, error) {
+	value, err := e.Eval(finding)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, exprErrorf("expression %q returned %s, expected a boolean", e.source, typeName(value))
+	}
+	return b, nil
+}
+
+// exprErrorf returns a config error for an invalid expression
+func exprErrorf(format string, args ...interface{}) error {
+	return errors.New(errors.ErrCodeConfigInvalid, fmt.Sprintf(format, args...))
+}
+
+// exprMessage returns the message of an expression error without the error code
+func exprMessage(err error) string {
+	if appErr, ok := errors.AsAppError(err); ok {
+		return appErr.Message
+	}
+	return err.Error()
+}
+
+// ---- Lexer ----
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+)
+
+type exprToken struct {
+	kind  tokenKind
+	text  string
+	value interface{} // parsed value of number and string tokens
+	pos   int
+}
+
+func (t exprToken) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// exprOperators are the operator tokens, longest first
+var exprOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "!", "<", ">", "+", "-", "*", "/", "%", "(", ")", "[", "]", ",", "."}
+
+// lexExpr splits an expression into tokens
+func lexExpr(source string) ([]exprToken, error) {
+	var tokens []exprToken
+	i := 0
+	for i < len(source) {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			var sb strings.Builder
+			closed := false
+			for i < len(source) {
+				ch := source[i]
+				if ch == '\\' && i+1 < len(source) {
+					next := source[i+1]
+					switch next {
+					case 'n':
+						sb.WriteByte('\n')
+					case 't':
+						sb.WriteByte('\t')
+					case '\\', '"', '\'':
+						sb.WriteByte(next)
+					default:
+						// Keep unknown escapes (e.g. \d in regular expressions)
+						sb.WriteByte('\\')
+						sb.WriteByte(next)
+					}
+					i += 2
+					continue
+				}
+				if rune(ch) == c {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteByte(ch)
+				i++
+			}
+			if !closed {
+				return nil, exprErrorf("unterminated string at position %d", start)
+			}
+			tokens = append(tokens, exprToken{kind: tokString, text: source[start:i], value: sb.String(), pos: start})
+
+		case unicode.IsDigit(c):
+			start := i
+			for i < len(source) && (unicode.IsDigit(rune(source[i])) ||
+				(source[i] == '.' && i+1 < len(source) && unicode.IsDigit(rune(source[i+1])))) {
+				i++
+			}
+			value, err := strconv.ParseFloat(source[start:i], 64)
+			if err != nil {
+				return nil, exprErrorf("invalid number %q at position %d", source[start:i], start)
+			}
+			tokens = append(tokens, exprToken{kind: tokNumber, text: source[start:i], value: value, pos: start})
+
+		case c == '_' || unicode.IsLetter(c):
+			start := i
+			for i < len(source) && (source[i] == '_' || unicode.IsLetter(rune(source[i])) || unicode.IsDigit(rune(source[i]))) {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokIdent, text: source[start:i], pos: start})
+
+		default:
+			matched := false
+			for _, op := range exprOperators {
+				if strings.HasPrefix(source[i:], op) {
+					tokens = append(tokens, exprToken{kind: tokOp, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, exprErrorf("unexpected character %q at position %d", c, i)
+			}
+		}
+	}
+	return append(tokens, exprToken{kind: tokEOF, pos: len(source)}), nil
+}
+
+// ---- Parser ----
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the operator or keyword op
+func (p *exprParser) accept(op string) bool {
+	tok := p.peek()
+	if (tok.kind == tokOp || tok.kind == tokIdent) && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) expect(op string) error {
+	if !p.accept(op) {
+		tok := p.peek()
+		return exprErrorf("expected %q, got %s at position %d", op, tok, tok.pos)
+	}
+	return nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseRelation()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseRelation()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseRelation() (exprNode, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">", "in"} {
+		if p.accept(op) {
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			return &binaryNode{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAdditive() (exprNode, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek().text
+		if p.peek().kind != tokOp || (op != "+" && op != "-") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseMultiplicative() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek().text
+		if p.peek().kind != tokOp || (op != "*" && op != "/" && op != "%") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.peek().kind == tokOp && (p.peek().text == "!" || p.peek().text == "-") {
+		op := p.next().text
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *exprParser) parsePostfix() (exprNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			tok := p.next()
+			if tok.kind != tokIdent {
+				return nil, exprErrorf("expected a field or method name, got %s at position %d", tok, tok.pos)
+			}
+			if p.accept("(") {
+				args, err := p.parseArgs()
+				if err != nil {
+					return nil, err
+				}
+				if node, err = newCallNode(tok.text, node, args); err != nil {
+					return nil, err
+				}
+			} else {
+				node = &memberNode{target: node, name: tok.text}
+			}
+		case p.accept("["):
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			node = &indexNode{target: node, index: index}
+		default:
+			return node, nil
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber, tokString:
+		return &literalNode{value: tok.value}, nil
+
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		}
+		if p.accept("(") {
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			return newCallNode(tok.text, nil, args)
+		}
+		return &identNode{name: tok.text}, nil
+
+	case tokOp:
+		switch tok.text {
+		case "(":
+			node, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return node, nil
+		case "[":
+			var items []exprNode
+			if !p.accept("]") {
+				for {
+					item, err := p.parseOr()
+					if err != nil {
+						return nil, err
+					}
+					items = append(items, item)
+					if p.accept("]") {
+						break
+					}
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+			}
+			return &listNode{items: items}, nil
+		}
+	}
+	return nil, exprErrorf("unexpected %s at position %d", tok, tok.pos)
+}
+
+// parseArgs parses call arguments after the opening parenthesis
+func (p *exprParser) parseArgs() ([]exprNode, error) {
+	var args []exprNode
+	if p.accept(")") {
+		return args, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.accept(")") {
+			return args, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ---- Evaluation ----
+
+// exprEnv resolves identifiers against a finding
+type exprEnv struct {
+	finding map[string]interface{}
+	derived map[string]interface{}
+}
+
+// newExprEnv creates the environment of a finding, with file, line and end_line parsed from location
+func newExprEnv(finding map[string]interface{}) *exprEnv {
+	loc := findingLocation(finding)
+	derived := map[string]interface{}{
+		"finding":  finding,
+		"file":     loc.Path,
+		"line":     float64(loc.StartLine),
+		"end_line": float64(loc.EndLine),
+	}
+	return &exprEnv{finding: finding, derived: derived}
+}
+
+// lookup returns the value of an identifier and whether it is set
+func (env *exprEnv) lookup(name string) (interface{}, bool) {
+	if value, ok := env.finding[name]; ok {
+		return normalizeValue(value), true
+	}
+	value, ok := env.derived[name]
+	return value, ok
+}
+
+// exprNode is a node of a parsed expression
+type exprNode interface {
+	eval(env *exprEnv) (interface{}, error)
+}
+
+type literalNode struct{ value interface{} }
+
+func (n *literalNode) eval(*exprEnv) (interface{}, error) { return n.value, nil }
+
+type identNode struct{ name string }
+
+func (n *identNode) eval(env *exprEnv) (interface{}, error) {
+	value, _ := env.lookup(n.name)
+	return value, nil
+}
+
+type listNode struct{ items []exprNode }
+
+func (n *listNode) eval(env *exprEnv) (interface{}, error) {
+	list := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		value, err := item.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+	return list, nil
+}
+
+type memberNode struct {
+	target exprNode
+	name   string
+}
+
+func (n *memberNode) eval(env *exprEnv) (interface{}, error) {
+	target, err := n.target.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch t := target.(type) {
+	case map[string]interface{}:
+		return normalizeValue(t[n.name]), nil
+	case nil:
+		return nil, nil
+	}
+	return nil, exprErrorf("can't access field %s of %s", n.name, typeName(target))
+}
+
+type indexNode struct {
+	target exprNode
+	index  exprNode
+}
+
+func (n *indexNode) eval(env *exprEnv) (interface{}, error) {
+	target, err := n.target.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	index, err := n.index.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch t := target.(type) {
+	case map[string]interface{}:
+		key, ok := index.(string)
+		if !ok {
+			return nil, exprErrorf("map index must be a string, got %s", typeName(index))
+		}
+		return normalizeValue(t[key]), nil
+	case []interface{}:
+		i, ok := index.(float64)
+		if !ok || i != math.Trunc(i) {
+			return nil, exprErrorf("list index must be an integer, got %s", typeName(index))
+		}
+		if i < 0 || int(i) >= len(t) {
+			return nil, nil
+		}
+		return normalizeValue(t[int(i)]), nil
+	case nil:
+		return nil, nil
+	}
+	return nil, exprErrorf("can't index %s", typeName(target))
+}
+
+type unaryNode struct {
+	op      string
+	operand exprNode
+}
+
+func (n *unaryNode) eval(env *exprEnv) (interface{}, error) {
+	value, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		b, ok := value.(bool)
+		if !ok {
+			return nil, exprErrorf("operator ! expects a boolean, got %s", typeName(value))
+		}
+		return !b, nil
+	}
+	f, ok := value.(float64)
+	if !ok {
+		return nil, exprErrorf("operator - expects a number, got %s", typeName(value))
+	}
+	return -f, nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *binaryNode) eval(env *exprEnv) (interface{}, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	// Logical operators short-circuit
+	if n.op == "&&" || n.op == "||" {
+		l, ok := left.(bool)
+		if !ok {
+			return nil, exprErrorf("operator %s expects booleans, got %s", n.op, typeName(left))
+		}
+		if (n.op == "&&" && !l) || (n.op == "||" && l) {
+			return l, nil
+		}
+		right, err := n.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := right.(bool)
+		if !ok {
+			return nil, exprErrorf("operator %s expects booleans, got %s", n.op, typeName(right))
+		}
+		return r, nil
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return reflect.DeepEqual(left, right), nil
+	case "!=":
+		return !reflect.DeepEqual(left, right), nil
+	case "in":
+		return evalIn(left, right)
+	case "<", "<=", ">", ">=":
+		cmp, err := compareValues(left, right)
+		if err != nil {
+			return nil, err
+		}
+		switch n.op {
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		default:
+			return cmp >= 0, nil
+		}
+	}
+
+	// Arithmetic; + also concatenates strings
+	if n.op == "+" {
+		if l, ok := left.(string); ok {
+			if r, ok := right.(string); ok {
+				return l + r, nil
+			}
+		}
+	}
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+	if !lok || !rok {
+		return nil, exprErrorf("operator %s expects numbers, got %s and %s", n.op, typeName(left), typeName(right))
+	}
+	switch n.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, exprErrorf("division by zero")
+		}
+		return l / r, nil
+	default:
+		if r == 0 {
+			return nil, exprErrorf("division by zero")
+		}
+		return math.Mod(l, r), nil
+	}
+}
+
+// evalIn returns true if needle is an element of a list, a key of a map or a substring of a string
+func evalIn(needle, haystack interface{}) (interface{}, error) {
+	switch h := haystack.(type) {
+	case []interface{}:
+		for _, item := range h {
+			if reflect.DeepEqual(needle, normalizeValue(item)) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]interface{}:
+		key, ok := needle.(string)
+		if !ok {
+			return false, nil
+		}
+		_, exists := h[key]
+		return exists, nil
+	case string:
+		s, ok := needle.(string)
+		if !ok {
+			return nil, exprErrorf("operator in expects a string on the left of a string, got %s", typeName(needle))
+		}
+		return strings.Contains(h, s), nil
+	case nil:
+		return false, nil
+	}
+	return nil, exprErrorf("operator in expects a list, map or string, got %s", typeName(haystack))
+}
+
+// compareValues orders two numbers or two strings.
+// Two severity names are ordered by level (info < low < medium < high < critical).
+func compareValues(left, right interface{}) (int, error) {
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			switch {
+			case l < r:
+				return -1, nil
+			case l > r:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			lLevel, rLevel := SeverityLevel(l), SeverityLevel(r)
+			if lLevel >= 0 && rLevel >= 0 {
+				return lLevel - rLevel, nil
+			}
+			return strings.Compare(l, r), nil
+		}
+	}
+	return 0, exprErrorf("can't compare %s and %s", typeName(left), typeName(right))
+}
+
+// SeverityLevel returns the index of a severity in SeverityLevels (case-insensitive), or -1
+func SeverityLevel(severity string) int {
+	for i, level := range SeverityLevels {
+		if strings.EqualFold(level, severity) {
+			return i
+		}
+	}
+	return -1
+}
+
+// exprFunc is a built-in function or method. For methods, the receiver is the first argument.
+type exprFunc struct {
+	args   int  // number of arguments, including the receiver of methods
+	method bool // called as a method (x.f())
+	global bool // called as a function (f(x))
+	call   func(args []interface{}, n *callNode) (interface{}, error)
+}
+
+// exprFuncs are the built-in functions and methods
+var exprFuncs = map[string]exprFunc{
+	"matches": {args: 2, method: true, call: func(args []interface{}, n *callNode) (interface{}, error) {
+		s, pattern, err := stringArgs("matches", args)
+		if err != nil {
+			return nil, err
+		}
+		re, err := n.regexp(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString(s), nil
+	}},
+	"contains": {args: 2, method: true, call: func(args []interface{}, n *callNode) (interface{}, error) {
+		if list, ok := args[0].([]interface{}); ok {
+			return evalIn(args[1], list)
+		}
+		s, sub, err := stringArgs("contains", args)
+		if err != nil {
+			return nil, err
+		}
+		return strings.Contains(s, sub), nil
+	}},
+	"startsWith": {args: 2, method: true, call: func(args []interface{}, n *callNode) (interface{}, error) {
+		s, prefix, err := stringArgs("startsWith", args)
+		if err != nil {
+			return nil, err
+		}
+		return strings.HasPrefix(s, prefix), nil
+	}},
+	"endsWith": {args: 2, method: true, call: func(args []interface{}, n *callNode) (interface{}, error) {
+		s, suffix, err := stringArgs("endsWith", args)
+		if err != nil {
+			return nil, err
+		}
+		return strings.HasSuffix(s, suffix), nil
+	}},
+	"glob": {args: 2, method: true, call: func(args []interface{}, n *callNode) (interface{}, error) {
+		file, pattern, err := stringArgs("glob", args)
+		if err != nil {
+			return nil, err
+		}
+		return file != "" && MatchGlob(pattern, file), nil
+	}},
+	"lower": {args: 1, method: true, call: func(args []interface{}, n *callNode) (interface{}, error) {
+		s, err := stringArg("lower", args[0])
+		return strings.ToLower(s), err
+	}},
+	"upper": {args: 1, method: true, call: func(args []interface{}, n *callNode) (interface{}, error) {
+		s, err := stringArg("upper", args[0])
+		return strings.ToUpper(s), err
+	}},
+	"size": {args: 1, method: true, global: true, call: func(args []interface{}, n *callNode) (interface{}, error) {
+		switch v := args[0].(type) {
+		case string:
+			return float64(len([]rune(v))), nil
+		case []interface{}:
+			return float64(len(v)), nil
+		case map[string]interface{}:
+			return float64(len(v)), nil
+		case nil:
+			return float64(0), nil
+		}
+		return nil, exprErrorf("size() expects a string, list or map, got %s", typeName(args[0]))
+	}},
+	"level": {args: 1, global: true, call: func(args []interface{}, n *callNode) (interface{}, error) {
+		s, err := stringArg("level", args[0])
+		if err != nil {
+			return nil, err
+		}
+		return float64(SeverityLevel(s)), nil
+	}},
+}
+
+// callNode calls a built-in function or method
+type callNode struct {
+	name string
+	fn   exprFunc
+	args []exprNode // the receiver of a method is the first argument
+
+	// re is the compiled regular expression of a literal matches() pattern
+	re *regexp.Regexp
+}
+
+// newCallNode checks a call and precompiles literal regular expressions
+func newCallNode(name string, receiver exprNode, args []exprNode) (exprNode, error) {
+	if name == "has" && receiver == nil {
+		if len(args) != 1 {
+			return nil, exprErrorf("has() expects 1 argument, got %d", len(args))
+		}
+		switch args[0].(type) {
+		case *identNode, *memberNode:
+			return &hasNode{field: args[0]}, nil
+		}
+		return nil, exprErrorf("has() expects a field, e.g. has(cwe) or has(finding.cwe)")
+	}
+
+	fn, ok := exprFuncs[name]
+	if !ok || (receiver != nil && !fn.method) || (receiver == nil && !fn.global) {
+		if receiver != nil {
+			return nil, exprErrorf("unknown method %s()", name)
+		}
+		return nil, exprErrorf("unknown function %s()", name)
+	}
+	if receiver != nil {
+		args = append([]exprNode{receiver}, args...)
+	}
+	if len(args) != fn.args {
+		expected := fn.args
+		if receiver != nil {
+			expected--
+		}
+		return nil, exprErrorf("%s() expects %d argument(s)", name, expected)
+	}
+
+	n := &callNode{name: name, fn: fn, args: args}
+	if name == "matches" {
+		if lit, ok := args[1].(*literalNode); ok {
+			pattern, ok := lit.value.(string)
+			if !ok {
+				return nil, exprErrorf("matches() expects a string pattern")
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, exprErrorf("invalid regular expression %q: %v", pattern, err)
+			}
+			n.re = re
+		}
+	}
+	return n, nil
+}
+
+func (n *callNode) eval(env *exprEnv) (interface{}, error) {
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	return n.fn.call(args, n)
+}
+
+// regexp returns the compiled pattern, compiling non-literal patterns on each call
+func (n *callNode) regexp(pattern string) (*regexp.Regexp, error) {
+	if n.re != nil {
+		return n.re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, exprErrorf("invalid regular expression %q: %v", pattern, err)
+	}
+	return re, nil
+}
+
+// hasNode returns true if a field is set
+type hasNode struct {
+	field exprNode
+}
+
+func (n *hasNode) eval(env *exprEnv) (interface{}, error) {
+	switch f := n.field.(type) {
+	case *identNode:
+		value, ok := env.lookup(f.name)
+		return ok && value != nil, nil
+	case *memberNode:
+		target, err := f.target.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := target.(map[string]interface{})
+		if !ok {
+			return false, nil
+		}
+		value, exists := m[f.name]
+		return exists && value != nil, nil
+	}
+	return false, nil
+}
+
+// stringArg returns a string argument; null is the empty string
+func stringArg(fn string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", nil
+	}
+	return "", exprErrorf("%s() expects a string, got %s", fn, typeName(value))
+}
+
+// stringArgs returns the receiver and argument of a string method
+func stringArgs(fn string, args []interface{}) (string, string, error) {
+	s, err := stringArg(fn, args[0])
+	if err != nil {
+		return "", "", err
+	}
+	arg, ok := args[1].(string)
+	if !ok {
+		return "", "", exprErrorf("%s() expects a string argument, got %s", fn, typeName(args[1]))
+	}
+	return s, arg, nil
+}
+
+// normalizeValue converts finding values to the types of the expression language
+// (numbers are float64, lists []interface{}, maps map[string]interface{})
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case []string:
+		list := make([]interface{}, len(v))
+		for i, s := range v {
+			list[i] = s
+		}
+		return list
+	}
+	return value
+}
+
+// typeName returns the expression language type of a value for messages
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "map"
+	}
+	return fmt.Sprintf("%T", value)
+}
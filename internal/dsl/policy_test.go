@@ -0,0 +1,212 @@
+package dsl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPolicyExpr_Eval(t *testing.T) {
+	finding := map[string]interface{}{
+		"severity": "medium",
+		"category": "sql-injection",
+		"title":    "Consider adding a comment",
+		"location": "internal/db/query_test.go:10-12",
+		"cwe":      "CWE-89",
+		"tags":     []interface{}{"db", "security"},
+		"meta":     map[string]interface{}{"confidence": 0.4},
+	}
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{`category == "sql-injection"`, true},
+		{`category != 'sql-injection'`, false},
+		{`file.glob("**/*_test.go")`, true},
+		{`file.glob("*.go") && line == 10 && end_line == 12`, true},
+		{`title.matches("(?i)^consider adding")`, true},
+		{`title.lower().contains("comment")`, true},
+		{`file.startsWith("internal/") && file.endsWith(".go")`, true},
+		{`severity >= "medium" && severity < "high"`, true},
+		{`severity > "high"`, false},
+		{`level(severity) + 1 == level("high")`, true},
+		{`"security" in tags && !("ui" in tags)`, true},
+		{`category in ["xss", "sql-injection"]`, true},
+		{`meta.confidence < 0.5 || false`, true},
+		{`finding["cwe"] == "CWE-89"`, true},
+		{`has(cwe) && !has(owasp) && has(meta.confidence)`, true},
+		{`size(tags) == 2 && tags.size() * 2 == 4 && tags[1] == "security"`, true},
+		{`owasp == null`, true},
+		{`(1 + 2) * 3 - 4 / 2 == 7 && 7 % 4 == 3 && -1 < 0`, true},
+		{`"a" + "b"`, "ab"},
+	}
+	for _, tt := range tests {
+		expr, err := CompilePolicyExpr(tt.expr)
+		if err != nil {
+			t.Errorf("CompilePolicyExpr(%q) error = %v", tt.expr, err)
+			continue
+		}
+		got, err := expr.Eval(finding)
+		if err != nil {
+			t.Errorf("Eval(%q) error = %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Eval(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyExpr_Errors(t *testing.T) {
+	compileErrors := []string{
+		`category ==`,
+		`title.matches("(")`,
+		`unknown(title)`,
+		`title.glob()`,
+		`has("cwe")`,
+		`"unterminated`,
+		`severity # high`,
+		`(severity == "high"`,
+	}
+	for _, src := range compileErrors {
+		if _, err := CompilePolicyExpr(src); err == nil {
+			t.Errorf("CompilePolicyExpr(%q) expected an error", src)
+		}
+	}
+
+	evalErrors := []string{
+		`title`,             // not a boolean
+		`title && true`,     // not a boolean operand
+		`title < 3`,         // mismatched comparison
+		`line / 0 == 1`,     // division by zero
+		`title.lower() > 1`, // mismatched comparison
+	}
+	finding := map[string]interface{}{"title": "x", "location": "a.go:1"}
+	for _, src := range evalErrors {
+		expr, err := CompilePolicyExpr(src)
+		if err != nil {
+			t.Errorf("CompilePolicyExpr(%q) error = %v", src, err)
+			continue
+		}
+		if _, err := expr.EvalBool(finding); err == nil {
+			t.Errorf("EvalBool(%q) expected an error", src)
+		}
+	}
+}
+
+func TestApplyPolicy(t *testing.T) {
+	newFinding := func(severity, category, title, location string) map[string]interface{} {
+		return map[string]interface{}{"severity": severity, "category": category, "title": title, "location": location}
+	}
+	findings := []interface{}{
+		newFinding("medium", "sql-injection", "Query built from input", "db/query.go:10"),
+		newFinding("high", "logic", "Wrong check", "db/query_test.go:3"),
+		newFinding("low", "style", "Consider adding a comment", "api/handler.go:5"),
+		newFinding("low", "style", "Long line", "api/handler.go:6"),
+		newFinding("medium", "style", "Naming", "api/handler.go:7"),
+		newFinding("info", "style", "Typo", "api/handler.go:8"),
+	}
+
+	policy := []PolicyStatementConfig{
+		{Name: "sqli", When: `category == "sql-injection"`, SetSeverity: "critical"},
+		{Name: "tests", When: `file.glob("**/*_test.go")`, AdjustSeverity: -1},
+		{When: `title.matches("(?i)consider adding")`, Drop: true},
+		{When: `broken.size() > "x"`, Drop: true}, // fails to evaluate, leaves findings unchanged
+		{MaxPerFile: 2},
+	}
+
+	kept, result := ApplyPolicy(findings, policy)
+
+	if len(kept) != 4 {
+		t.Fatalf("kept %d findings, want 4", len(kept))
+	}
+	if result.SeverityChanged != 2 || result.Dropped != 2 {
+		t.Errorf("result = %+v, want 2 severity changes and 2 dropped", result)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "policy[3]") {
+		t.Errorf("Errors = %v, want one error for policy[3]", result.Errors)
+	}
+
+	sqli := kept[0].(map[string]interface{})
+	if sqli["severity"] != "critical" || sqli["original_severity"] != "medium" {
+		t.Errorf("sqli finding = %v, want critical with original severity", sqli)
+	}
+	if names, _ := sqli["policy"].([]interface{}); len(names) != 1 || names[0] != "sqli" {
+		t.Errorf("policy = %v, want [sqli]", sqli["policy"])
+	}
+	if test := kept[1].(map[string]interface{}); test["severity"] != "medium" {
+		t.Errorf("test finding severity = %v, want medium", test["severity"])
+	}
+
+	// max_per_file keeps the most severe findings of the file, in their original order
+	var handlerTitles []string
+	for _, item := range kept[2:] {
+		handlerTitles = append(handlerTitles, item.(map[string]interface{})["title"].(string))
+	}
+	if strings.Join(handlerTitles, ",") != "Long line,Naming" {
+		t.Errorf("handler.go findings = %v, want [Long line Naming]", handlerTitles)
+	}
+}
+
+func TestAdjustSeverity(t *testing.T) {
+	tests := []struct {
+		severity string
+		levels   int
+		want     string
+	}{
+		{"high", -1, "medium"},
+		{"info", -1, "info"},
+		{"high", 3, "critical"},
+		{"HIGH", 1, "critical"},
+		{"unknown", 1, ""},
+	}
+	for _, tt := range tests {
+		if got := adjustSeverity(tt.severity, tt.levels); got != tt.want {
+			t.Errorf("adjustSeverity(%q, %d) = %q, want %q", tt.severity, tt.levels, got, tt.want)
+		}
+	}
+}
+
+func TestParser_Policy(t *testing.T) {
+	valid := `
+rule_base:
+  policy:
+    - when: file.glob("**/*_test.go")
+      adjust_severity: -1
+rules:
+  - id: security
+    goals:
+      areas: [security]
+    policy:
+      - when: category == "sql-injection"
+        set_severity: critical
+  - id: style
+    goals:
+      areas: [readability]
+`
+	config, err := NewParser().Parse([]byte(valid))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := config.Rules[0].Policy; len(got) != 1 || got[0].SetSeverity != "critical" {
+		t.Errorf("security policy = %+v, want its own policy", got)
+	}
+	if got := config.Rules[1].Policy; len(got) != 1 || got[0].AdjustSeverity != -1 {
+		t.Errorf("style policy = %+v, want the rule_base policy", got)
+	}
+
+	invalid := map[string]string{
+		"no action":        `{when: "true"}`,
+		"two actions":      `{drop: true, max_per_file: 2}`,
+		"bad severity":     `{set_severity: urgent}`,
+		"bad expression":   `{when: "category ==", drop: true}`,
+		"negative max":     `{max_per_file: -1}`,
+		"unknown function": `{when: "foo(title)", drop: true}`,
+	}
+	for name, statement := range invalid {
+		data := "rules:\n  - id: r\n    goals:\n      areas: [security]\n    policy:\n      - " + statement + "\n"
+		if _, err := NewParser().Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Package dsl provides DSL configuration parsing and validation.
+// This file defines custom prompt templates (`prompt_template`).
+package dsl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/verustcode/verustcode/pkg/errors"
+)
+
+// PromptTemplateConfig overrides the built-in prompt template of a rule.
+// The template uses Go text/template syntax. A template consisting only of
+// {{define "<block>"}} sections overrides those blocks of the built-in prompt;
+// any other content replaces the whole prompt.
+type PromptTemplateConfig struct {
+	// File is a template file in the reviews directory (or in the repository of a review file fetched from git).
+	// It is loaded into Inline when the review file is loaded.
+	File string `yaml:"file,omitempty" json:"file,omitempty"`
+
+	// Inline is the template content
+	Inline string `yaml:"inline,omitempty" json:"inline,omitempty"`
+}
+
+// PromptTemplateBlocks lists the named blocks of the built-in prompt that can be overridden
+var PromptTemplateBlocks = []string{"role", "goals", "constraints", "context"}
+
+// PromptTemplateFuncs lists the helper functions available in prompt templates.
+// Must match the functions registered by prompt.Renderer.
+var PromptTemplateFuncs = []string{"join", "indent", "bullet", "numbered", "quote", "untrusted", "add"}
+
+// promptTemplateName is the name of the top-level custom prompt template
+const promptTemplateName = "prompt_template"
+
+// ParsePromptTemplate checks the syntax of a prompt template and returns
+// the names of the blocks it overrides, and whether it replaces the whole prompt.
+func ParsePromptTemplate(text string) (blocks []string, replacesMain bool, err error) {
+	funcs := make(template.FuncMap, len(PromptTemplateFuncs))
+	for _, name := range PromptTemplateFuncs {
+		funcs[name] = func(...interface{}) string { return "" }
+	}
+
+	tmpl, err := template.New(promptTemplateName).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, t := range tmpl.Templates() {
+		name := t.Name()
+		if name == promptTemplateName {
+			continue
+		}
+		if !isPromptTemplateBlock(name) {
+			return nil, false, fmt.Errorf("unknown block %q (valid blocks: %s)",
+				name, strings.Join(PromptTemplateBlocks, ", "))
+		}
+		blocks = append(blocks, name)
+	}
+	sort.Strings(blocks)
+
+	// Text outside of define sections (other than whitespace) replaces the whole prompt
+	if tmpl.Tree != nil && tmpl.Tree.Root != nil {
+		for _, node := range tmpl.Tree.Root.Nodes {
+			if strings.TrimSpace(node.String()) != "" {
+				replacesMain = true
+				break
+			}
+		}
+	}
+	if !replacesMain && len(blocks) == 0 {
+		return nil, false, fmt.Errorf("template is empty")
+	}
+	return blocks, replacesMain, nil
+}
+
+// isPromptTemplateBlock returns true if name is an overridable block
+func isPromptTemplateBlock(name string) bool {
+	for _, block := range PromptTemplateBlocks {
+		if block == name {
+			return true
+		}
+	}
+	return false
+}
+
+// loadPromptTemplates loads file-based prompt templates of the rules defined in source
+func (p *Parser) loadPromptTemplates(config *ReviewRulesConfig, source string, state *resolveState) error {
+	for i := range config.Rules {
+		tmpl := config.Rules[i].PromptTemplate
+		if tmpl == nil || tmpl.File == "" || tmpl.Inline != "" {
+			continue // inline templates are validated by Validate
+		}
+		if state.resolver == nil {
+			return errors.New(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("rule %s: prompt_template.file is only supported for review files", config.Rules[i].ID))
+		}
+
+		templateSource, err := state.resolver.canonical(tmpl.File, source)
+		if err != nil {
+			return errors.Wrap(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("rule %s: invalid prompt_template.file%s", config.Rules[i].ID, sourceSuffix(source)), err)
+		}
+		data, err := state.resolver.read(templateSource)
+		if err != nil {
+			return errors.Wrap(errors.ErrCodeConfigNotFound,
+				fmt.Sprintf("rule %s: failed to load prompt template %s", config.Rules[i].ID, templateSource), err)
+		}
+
+		config.Rules[i].PromptTemplate = &PromptTemplateConfig{Inline: string(data)}
+	}
+	return nil
+}
+
+// validatePromptTemplate validates the prompt template of a rule
+func (p *Parser) validatePromptTemplate(tmpl *PromptTemplateConfig, prefix, id string) error {
+	if tmpl.File != "" && tmpl.Inline != "" {
+		return errors.New(errors.ErrCodeConfigInvalid,
+			fmt.Sprintf("%s (%s): prompt_template.file and prompt_template.inline are mutually exclusive", prefix, id))
+	}
+	if tmpl.Inline == "" {
+		return errors.New(errors.ErrCodeConfigInvalid,
+			fmt.Sprintf("%s (%s): prompt_template requires file or inline", prefix, id))
+	}
+	if _, _, err := ParsePromptTemplate(tmpl.Inline); err != nil {
+		return errors.Wrap(errors.ErrCodeConfigInvalid,
+			fmt.Sprintf("%s (%s): invalid prompt_template", prefix, id), err)
+	}
+	return nil
+}
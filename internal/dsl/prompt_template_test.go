@@ -0,0 +1,160 @@
+package dsl
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParsePromptTemplate tests parsing prompt templates and detecting overridden blocks
+func TestParsePromptTemplate(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		wantBlocks   []string
+		wantReplaces bool
+		wantErr      string
+	}{
+		{
+			name:       "block overrides",
+			text:       "{{define \"goals\"}}## Focus\n{{range .Areas}}- {{.ID}}\n{{end}}{{end}}\n{{define \"role\"}}{{.Description}}{{end}}\n",
+			wantBlocks: []string{"goals", "role"},
+		},
+		{
+			name:         "whole prompt",
+			text:         "{{template \"role\" .SystemRole}}\n\nReview PR #{{.Build.PRNumber}}.\n{{bullet .Context.ChangedFiles}}",
+			wantReplaces: true,
+		},
+		{
+			name:    "unknown block",
+			text:    "{{define \"goal\"}}x{{end}}",
+			wantErr: "unknown block",
+		},
+		{
+			name:    "unknown function",
+			text:    "{{shout .Description}}",
+			wantErr: "not defined",
+		},
+		{
+			name:    "syntax error",
+			text:    "{{if .Description}}",
+			wantErr: "unexpected EOF",
+		},
+		{
+			name:    "empty",
+			text:    "  \n",
+			wantErr: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks, replaces, err := ParsePromptTemplate(tt.text)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParsePromptTemplate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePromptTemplate() error = %v", err)
+			}
+			if strings.Join(blocks, ",") != strings.Join(tt.wantBlocks, ",") {
+				t.Errorf("blocks = %v, want %v", blocks, tt.wantBlocks)
+			}
+			if replaces != tt.wantReplaces {
+				t.Errorf("replacesMain = %v, want %v", replaces, tt.wantReplaces)
+			}
+		})
+	}
+}
+
+// TestParser_Parse_PromptTemplate tests validation of prompt templates at parse time
+func TestParser_Parse_PromptTemplate(t *testing.T) {
+	parser := NewParser()
+
+	valid := `
+version: "1.0"
+rules:
+  - id: security
+    description: Security Reviewer
+    prompt_template:
+      inline: |
+        {{define "goals"}}## Goals
+        {{range .Areas}}- {{.ID}}
+        {{end}}{{end}}
+    goals:
+      areas: [business-logic]
+`
+	config, err := parser.Parse([]byte(valid))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if config.Rules[0].PromptTemplate == nil || config.Rules[0].PromptTemplate.Inline == "" {
+		t.Fatalf("Expected prompt_template to be parsed, got %+v", config.Rules[0].PromptTemplate)
+	}
+
+	invalid := []string{
+		"prompt_template:\n      inline: \"{{define \\\"goal\\\"}}x{{end}}\"",
+		"prompt_template:\n      inline: \"{{if .Description}}\"",
+		"prompt_template:\n      file: prompts/security.tmpl",
+		"prompt_template: {}",
+	}
+	for _, tmpl := range invalid {
+		content := `
+version: "1.0"
+rules:
+  - id: invalid
+    description: Invalid Template
+    ` + tmpl + `
+    goals:
+      areas: [business-logic]
+`
+		if _, err := parser.Parse([]byte(content)); err == nil {
+			t.Errorf("Parse() expected error for %q, got nil", tmpl)
+		}
+	}
+}
+
+// TestLoader_Load_PromptTemplateFile tests loading prompt templates from files
+func TestLoader_Load_PromptTemplateFile(t *testing.T) {
+	dir := writeReviewFiles(t, map[string]string{
+		"prompts/security.tmpl": "{{define \"role\"}}You are a security auditor.{{end}}",
+		"security.yaml": `
+version: "1.0"
+rules:
+  - id: security
+    description: Security Reviewer
+    prompt_template:
+      file: prompts/security.tmpl
+    goals:
+      areas: [business-logic]
+  - id: strict-security
+    extends: "#security"
+`,
+		"missing.yaml": `
+version: "1.0"
+rules:
+  - id: security
+    description: Security Reviewer
+    prompt_template:
+      file: prompts/missing.tmpl
+    goals:
+      areas: [business-logic]
+`,
+	})
+
+	config, err := loadWithResolver(dir, "security.yaml")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for _, rule := range config.Rules {
+		tmpl := rule.PromptTemplate
+		if tmpl == nil || tmpl.File != "" || !strings.Contains(tmpl.Inline, "security auditor") {
+			t.Errorf("Rule %s: expected template file to be loaded, got %+v", rule.ID, tmpl)
+		}
+	}
+
+	if _, err := loadWithResolver(dir, "missing.yaml"); err == nil {
+		t.Error("Load() expected error for missing template file")
+	}
+}
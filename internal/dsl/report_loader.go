@@ -8,7 +8,6 @@ import (
 	"sync"
 
 	"go.uber.org/zap"
-	"gopkg.in/yaml.v3"
 
 	"github.com/verustcode/verustcode/pkg/errors"
 	"github.com/verustcode/verustcode/pkg/logger"
@@ -19,6 +18,9 @@ type ReportLoader struct {
 	// configs stores loaded report configurations by ID
 	configs map[string]*ReportConfig
 	mu      sync.RWMutex
+
+	// strict mode rejects unknown keys
+	strict bool
 }
 
 // NewReportLoader creates a new report loader.
@@ -28,6 +30,13 @@ func NewReportLoader() *ReportLoader {
 	}
 }
 
+// NewStrictReportLoader creates a report loader that rejects unknown keys.
+func NewStrictReportLoader() *ReportLoader {
+	loader := NewReportLoader()
+	loader.strict = true
+	return loader
+}
+
 // LoadFile loads a single report configuration file.
 func (l *ReportLoader) LoadFile(path string) (*ReportConfig, error) {
 	logger.Debug("Loading report configuration",
@@ -51,7 +60,7 @@ func (l *ReportLoader) LoadFile(path string) (*ReportConfig, error) {
 
 	// Parse YAML
 	var config ReportConfig
-	if err := yaml.Unmarshal([]byte(expanded), &config); err != nil {
+	if err := unmarshalYAML([]byte(expanded), &config, l.strict); err != nil {
 		return nil, errors.Wrap(errors.ErrCodeConfigInvalid,
 			"failed to parse report configuration YAML", err)
 	}
@@ -131,7 +140,7 @@ func (l *ReportLoader) LoadFromBytes(data []byte) (*ReportConfig, error) {
 	expanded := expandEnvVars(string(data))
 
 	var config ReportConfig
-	if err := yaml.Unmarshal([]byte(expanded), &config); err != nil {
+	if err := unmarshalYAML([]byte(expanded), &config, l.strict); err != nil {
 		return nil, errors.Wrap(errors.ErrCodeConfigInvalid,
 			"failed to parse report configuration YAML", err)
 	}
@@ -24,6 +24,11 @@ type AgentConfig struct {
 	// Model specifies which model to use (optional, uses agent's default if not specified)
 	// Examples: "sonnet-4.5", "gemini-2.5-pro"
 	Model string `yaml:"model,omitempty" json:"model,omitempty"`
+
+	// Fallback lists agent types to switch to when the primary agent's circuit breaker is open
+	// Fallback agents use their own default model
+	// Example: [gemini, qoder]
+	Fallback []string `yaml:"fallback,omitempty" json:"fallback,omitempty"`
 }
 
 // GetType returns the agent type with default fallback.
@@ -43,11 +48,29 @@ type ReviewRulesConfig struct {
 	// Version of the DSL schema (for future compatibility)
 	Version string `yaml:"version,omitempty"`
 
+	// Include lists review files whose rules are added to this file's rules
+	// Entries are files in config/reviews (e.g., "shared/security.yaml") or versioned git URLs
+	// in the form <repo-url>//<path>@<version>
+	// Included rules keep the rule_base of the file they are defined in
+	Include []string `yaml:"include,omitempty"`
+
+	// Areas defines custom review areas that rules can use in goals.areas
+	// Areas of included files are available to this file's rules
+	// Example:
+	//   areas:
+	//     - id: gdpr-pii-handling
+	//       group: compliance
+	//       description: Handling of personal data as required by GDPR
+	Areas []AreaDefinition `yaml:"areas,omitempty"`
+
 	// RuleBase provides base configuration inherited by all review rules
 	RuleBase *RuleBaseConfig `yaml:"rule_base,omitempty"`
 
 	// Rules is the list of review rule configurations
 	Rules []ReviewRuleConfig `yaml:"rules"`
+
+	// includeGraph records include and extends references between review files (resolved files only)
+	includeGraph map[string][]string
 }
 
 // RuleBaseConfig provides base configuration that can be inherited by review rules
@@ -62,6 +85,9 @@ type RuleBaseConfig struct {
 	// Constraints provides default constraints configuration
 	Constraints *ConstraintsConfig `yaml:"constraints,omitempty"`
 
+	// Policy provides the default policy statements, used by rules without a policy
+	Policy []PolicyStatementConfig `yaml:"policy,omitempty"`
+
 	// Output provides default output configuration
 	Output *OutputConfig `yaml:"output,omitempty"`
 }
@@ -71,6 +97,11 @@ type ReviewRuleConfig struct {
 	// ID is the unique identifier for this review rule
 	ID string `yaml:"id" json:"id"`
 
+	// Extends inherits all settings from another rule, which this rule overrides
+	// Format: <file>#<rule-id>, e.g. "shared/security.yaml#security"; "#<rule-id>" refers to the same file
+	// The ID may be omitted to keep the ID of the extended rule
+	Extends string `yaml:"extends,omitempty" json:"extends,omitempty"`
+
 	// Description provides a detailed description of what this review rule does
 	// This describes the reviewer's role and focus areas
 	Description string `yaml:"description,omitempty" json:"description,omitempty"`
@@ -94,6 +125,10 @@ type ReviewRuleConfig struct {
 	// Constraints defines all constraints for the review (scope, severity)
 	Constraints *ConstraintsConfig `yaml:"constraints,omitempty" json:"constraints,omitempty"`
 
+	// Policy lists deterministic transforms of the findings (severity remapping, dropping,
+	// per-file caps), applied in order after the agent output is parsed
+	Policy []PolicyStatementConfig `yaml:"policy,omitempty" json:"policy,omitempty"`
+
 	// Output defines how results should be output
 	Output *OutputConfig `yaml:"output,omitempty" json:"output,omitempty"`
 
@@ -103,6 +138,33 @@ type ReviewRuleConfig struct {
 	// HistoryCompare configures historical review comparison
 	// When enabled, includes previous review result in prompt for comparison
 	HistoryCompare *HistoryCompareConfig `yaml:"history_compare,omitempty" json:"history_compare,omitempty"`
+
+	// Chunking splits large change sets into several agent calls
+	Chunking *ChunkingConfig `yaml:"chunking,omitempty" json:"chunking,omitempty"`
+
+	// When restricts the rule to matching changes; non-matching rules are skipped
+	When *WhenConfig `yaml:"when,omitempty" json:"when,omitempty"`
+
+	// Context lists providers whose output (files, command output, diff, git log) is added to the prompt
+	Context []ContextProviderConfig `yaml:"context,omitempty" json:"context,omitempty"`
+
+	// PromptTemplate overrides the built-in prompt template (whole prompt or named blocks)
+	PromptTemplate *PromptTemplateConfig `yaml:"prompt_template,omitempty" json:"prompt_template,omitempty"`
+
+	// Cache set to false keeps the rule's agent results out of the response cache (when enabled in the review settings)
+	Cache *bool `yaml:"cache,omitempty" json:"cache,omitempty"`
+
+	// Locked protects a server-side rule from in-repository configs (.verust-review.yaml) merged under it:
+	// they can't remove or weaken the rule, only add areas, reference docs and context providers
+	Locked bool `yaml:"locked,omitempty" json:"locked,omitempty"`
+
+	// source is the review file the rule is defined in (set when resolving includes)
+	source string
+}
+
+// IsCacheable returns true unless the rule opts out of the response cache
+func (r *ReviewRuleConfig) IsCacheable() bool {
+	return r.Cache == nil || *r.Cache
 }
 
 // MultiRunConfig configures multiple review runs for a single rule
@@ -122,6 +184,36 @@ type MultiRunConfig struct {
 	MergeModel string `yaml:"merge_model,omitempty" json:"merge_model,omitempty"`
 }
 
+// ChunkingConfig splits the changed files of a large PR into batches.
+// Each batch is reviewed by a separate agent call and the results are merged.
+// Chunking is enabled when MaxFiles or MaxDiffLines is set.
+// Example YAML:
+//
+//	chunking:
+//	  max_files: 40
+//	  max_diff_lines: 3000
+//	  group_by_directory: true
+type ChunkingConfig struct {
+	// MaxFiles is the maximum number of changed files per chunk (0 = no limit)
+	MaxFiles int `yaml:"max_files,omitempty" json:"max_files,omitempty"`
+
+	// MaxDiffLines is the maximum number of changed lines (added + deleted) per chunk (0 = no limit)
+	// A single file larger than the limit gets a chunk of its own
+	MaxDiffLines int `yaml:"max_diff_lines,omitempty" json:"max_diff_lines,omitempty"`
+
+	// GroupByDirectory keeps files of the same directory in the same chunk where possible
+	GroupByDirectory bool `yaml:"group_by_directory,omitempty" json:"group_by_directory,omitempty"`
+
+	// MergeModel is the model to use for merging chunk results (optional)
+	// If not specified, uses the same agent's default model
+	MergeModel string `yaml:"merge_model,omitempty" json:"merge_model,omitempty"`
+}
+
+// IsEnabled returns true if chunking limits are configured
+func (c *ChunkingConfig) IsEnabled() bool {
+	return c != nil && (c.MaxFiles > 0 || c.MaxDiffLines > 0)
+}
+
 // HistoryCompareConfig configures historical review comparison
 // When enabled, the prompt will include the last review result for the same PR + rule,
 // allowing the AI to compare and indicate status of each issue (FIXED/NEW/PERSISTS)
@@ -0,0 +1,256 @@
+// Package dsl provides DSL configuration parsing and validation.
+// This file loads in-repository review configs from a git revision and merges
+// them under the server-side config.
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/pkg/errors"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// lockedExtensibleFields are the rule fields an in-repository config may extend on a locked rule.
+// Entries are added; existing entries can't be removed.
+var lockedExtensibleFields = map[string]bool{
+	"goals":          true, // areas only, goals.avoid can't change
+	"reference_docs": true,
+	"context":        true,
+}
+
+// LoadFromRepoRevision loads .verust-review.yaml as committed at the given revision of a repository,
+// ignoring any changes in the working tree.
+// Returns nil if the file does not exist at that revision.
+func (l *Loader) LoadFromRepoRevision(repoPath, revision string) (*ReviewRulesConfig, error) {
+	if err := exec.Command("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", revision+"^{commit}").Run(); err != nil {
+		return nil, errors.New(errors.ErrCodeConfigNotFound, "revision not found in repository: "+revision)
+	}
+
+	object := revision + ":" + config.RepoRootReviewPath
+	if err := exec.Command("git", "-C", repoPath, "cat-file", "-e", object).Run(); err != nil {
+		logger.Debug("No review config found at repository revision",
+			zap.String("repo_path", repoPath),
+			zap.String("revision", revision),
+		)
+		return nil, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", "-C", repoPath, "show", object)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrap(errors.ErrCodeConfigInvalid,
+			"failed to read "+object+": "+strings.TrimSpace(stderr.String()), err)
+	}
+
+	logger.Info("Found review config at repository revision",
+		zap.String("repo_path", repoPath),
+		zap.String("revision", revision),
+	)
+
+	expanded := expandEnvVars(stdout.String())
+	return l.parser.ParseFile([]byte(expanded), object, l.resolver.withoutGit())
+}
+
+// MergeUnderBaseline merges an in-repository config under a server-side baseline config.
+// Rules of the in-repository config are added, and replace unlocked baseline rules with the same ID.
+// Baseline rules can't be removed. Locked baseline rules keep their settings: an override may only
+// add areas, reference docs and context providers, and every other change is rejected.
+// Returns the merged config and a description of each rejected override.
+func MergeUnderBaseline(baseline, repo *ReviewRulesConfig) (*ReviewRulesConfig, []string) {
+	merged := &ReviewRulesConfig{
+		Version:  baseline.Version,
+		RuleBase: baseline.RuleBase,
+		Areas:    append([]AreaDefinition(nil), baseline.Areas...),
+		Rules:    append([]ReviewRuleConfig(nil), baseline.Rules...),
+	}
+	if repo == nil {
+		return merged, nil
+	}
+
+	areaIDs := make(map[string]bool, len(baseline.Areas))
+	for _, area := range baseline.Areas {
+		areaIDs[area.ID] = true
+	}
+	var rejected []string
+	for _, area := range repo.Areas {
+		if areaIDs[area.ID] {
+			rejected = append(rejected, fmt.Sprintf("area %s: already defined by the server config", area.ID))
+			continue
+		}
+		areaIDs[area.ID] = true
+		merged.Areas = append(merged.Areas, area)
+	}
+
+	index := make(map[string]int, len(merged.Rules))
+	for i, rule := range merged.Rules {
+		index[rule.ID] = i
+	}
+	for _, rule := range repo.Rules {
+		i, exists := index[rule.ID]
+		switch {
+		case !exists:
+			rule.Locked = false
+			index[rule.ID] = len(merged.Rules)
+			merged.Rules = append(merged.Rules, rule)
+		case !merged.Rules[i].Locked:
+			rule.Locked = false
+			merged.Rules[i] = rule
+		default:
+			var ruleRejected []string
+			merged.Rules[i], ruleRejected = extendLockedRule(merged.Rules[i], rule)
+			rejected = append(rejected, ruleRejected...)
+		}
+	}
+
+	return merged, rejected
+}
+
+// extendLockedRule applies the allowed extensions of override to a locked rule.
+// Returns the extended rule and a description of each rejected change.
+func extendLockedRule(locked, override ReviewRuleConfig) (ReviewRuleConfig, []string) {
+	extended := locked
+	var rejected []string
+	rejectf := func(format string, args ...interface{}) {
+		rejected = append(rejected, fmt.Sprintf("rule %s (locked): ", locked.ID)+fmt.Sprintf(format, args...))
+	}
+
+	// Areas and reference docs: add new entries, keep removed ones.
+	// Lists the override leaves unset are not removals.
+	extended.Goals.Areas = extendStrings(locked.Goals.Areas, override.Goals.Areas, func(area string) {
+		rejectf("area %s can't be removed", area)
+	})
+	extended.ReferenceDocs = extendStrings(locked.ReferenceDocs, override.ReferenceDocs, func(doc string) {
+		rejectf("reference doc %s can't be removed", doc)
+	})
+	if len(extended.ReferenceDocs) > MaxReferenceDocs {
+		rejectf("reference docs beyond the limit of %d are ignored", MaxReferenceDocs)
+		extended.ReferenceDocs = extended.ReferenceDocs[:MaxReferenceDocs]
+	}
+	if len(override.Goals.Avoid) > 0 && !jsonEqual(locked.Goals.Avoid, override.Goals.Avoid) {
+		rejectf("goals.avoid can't be changed")
+	}
+
+	// Context providers: add new providers, keep removed ones
+	extended.Context = append([]ContextProviderConfig(nil), locked.Context...)
+	for _, provider := range override.Context {
+		found := false
+		for _, existing := range locked.Context {
+			if jsonEqual(existing, provider) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			extended.Context = append(extended.Context, provider)
+		}
+	}
+	for _, provider := range locked.Context {
+		found := false
+		for _, candidate := range override.Context {
+			if jsonEqual(provider, candidate) {
+				found = true
+				break
+			}
+		}
+		if !found && len(override.Context) > 0 {
+			rejectf("context provider %s can't be removed", contextProviderLabel(provider))
+		}
+	}
+
+	// Every other field the override sets must be unchanged
+	for _, field := range changedRuleFields(locked, override) {
+		if !lockedExtensibleFields[field] {
+			rejectf("%s can't be changed", field)
+		}
+	}
+
+	return extended, rejected
+}
+
+// extendStrings returns base followed by the entries of extra that are not in base.
+// If extra is set, removed is called for each entry of base missing from it.
+func extendStrings(base, extra []string, removed func(string)) []string {
+	result := append([]string(nil), base...)
+	seen := make(map[string]bool, len(base))
+	for _, s := range base {
+		seen[s] = true
+	}
+	inExtra := make(map[string]bool, len(extra))
+	for _, s := range extra {
+		inExtra[s] = true
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	for _, s := range base {
+		if len(extra) > 0 && !inExtra[s] {
+			removed(s)
+		}
+	}
+	return result
+}
+
+// changedRuleFields returns the (JSON) names of the top-level fields override sets to a different
+// value than rule, sorted by name. Fields override leaves unset are not changes.
+// Identity fields (id, extends, locked) are not compared.
+func changedRuleFields(rule, override ReviewRuleConfig) []string {
+	fields := ruleFields(rule)
+
+	var changed []string
+	for name, value := range ruleFields(override) {
+		if isUnsetJSON(value) {
+			continue
+		}
+		if !bytes.Equal(value, fields[name]) {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// isUnsetJSON returns true if value is the JSON encoding of an unset field
+func isUnsetJSON(value json.RawMessage) bool {
+	switch string(value) {
+	case "null", "{}", "[]", `""`:
+		return true
+	}
+	return false
+}
+
+// ruleFields returns the JSON encoding of each top-level field of a rule
+func ruleFields(rule ReviewRuleConfig) map[string]json.RawMessage {
+	data, _ := json.Marshal(rule)
+	var fields map[string]json.RawMessage
+	_ = json.Unmarshal(data, &fields)
+	delete(fields, "id")
+	delete(fields, "extends")
+	delete(fields, "locked")
+	return fields
+}
+
+// jsonEqual returns true if a and b have the same JSON encoding
+func jsonEqual(a, b interface{}) bool {
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
+
+// contextProviderLabel returns a short description of a context provider for messages
+func contextProviderLabel(provider ContextProviderConfig) string {
+	if provider.Name != "" {
+		return fmt.Sprintf("%q", provider.Name)
+	}
+	return provider.Type
+}
@@ -0,0 +1,168 @@
+package dsl
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// parseTestConfig parses a review config for tests
+func parseTestConfig(t *testing.T, content string) *ReviewRulesConfig {
+	t.Helper()
+	config, err := NewParser().Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	return config
+}
+
+// TestMergeUnderBaseline tests merging an in-repository config under a locked baseline
+func TestMergeUnderBaseline(t *testing.T) {
+	baseline := parseTestConfig(t, `
+version: "1.0"
+rule_base:
+  agent:
+    type: cursor
+rules:
+  - id: security
+    description: Security review
+    locked: true
+    goals:
+      areas: [security, authentication]
+  - id: style
+    description: Style review
+    goals:
+      areas: [style-consistency]
+`)
+	repo := parseTestConfig(t, `
+version: "1.0"
+rules:
+  - id: security
+    description: Only look at docs
+    goals:
+      areas: [security, business-logic]
+    when:
+      paths:
+        include: ["docs/**"]
+  - id: style
+    description: Relaxed style review
+    goals:
+      areas: [readability]
+  - id: docs
+    description: Docs review
+    goals:
+      areas: [documentation]
+`)
+
+	merged, rejected := MergeUnderBaseline(baseline, repo)
+
+	if got := merged.GetRuleIDs(); !reflect.DeepEqual(got, []string{"security", "style", "docs"}) {
+		t.Fatalf("Expected baseline rules followed by new rules, got %v", got)
+	}
+
+	security := merged.Rules[0]
+	if security.Description != "Security review" || security.When != nil || !security.Locked {
+		t.Errorf("Expected locked rule to keep its settings, got %+v", security)
+	}
+	if want := []string{"security", "authentication", "business-logic"}; !reflect.DeepEqual(security.Goals.Areas, want) {
+		t.Errorf("Expected areas %v, got %v", want, security.Goals.Areas)
+	}
+	if merged.Rules[1].Description != "Relaxed style review" {
+		t.Errorf("Expected unlocked rule to be replaced, got %q", merged.Rules[1].Description)
+	}
+
+	want := []string{
+		"rule security (locked): area authentication can't be removed",
+		"rule security (locked): description can't be changed",
+		"rule security (locked): when can't be changed",
+	}
+	if !reflect.DeepEqual(rejected, want) {
+		t.Errorf("Expected rejections %v, got %v", want, rejected)
+	}
+
+	// Baseline is not modified
+	if len(baseline.Rules) != 2 || len(baseline.Rules[0].Goals.Areas) != 2 {
+		t.Errorf("Expected baseline to be unchanged, got %+v", baseline.Rules)
+	}
+}
+
+// TestMergeUnderBaseline_ExtendOnly tests that extending a locked rule is not a rejection
+func TestMergeUnderBaseline_ExtendOnly(t *testing.T) {
+	baseline := parseTestConfig(t, `
+version: "1.0"
+rules:
+  - id: security
+    locked: true
+    agent:
+      type: cursor
+    goals:
+      areas: [security]
+`)
+	repo := parseTestConfig(t, `
+version: "1.0"
+rules:
+  - id: security
+    reference_docs: [docs/security.md]
+    context:
+      - type: diff
+`)
+
+	merged, rejected := MergeUnderBaseline(baseline, repo)
+	if len(rejected) != 0 {
+		t.Errorf("Expected no rejections, got %v", rejected)
+	}
+	rule := merged.Rules[0]
+	if !reflect.DeepEqual(rule.Goals.Areas, []string{"security"}) || rule.Agent.Type != "cursor" {
+		t.Errorf("Expected locked settings to be kept, got %+v", rule)
+	}
+	if !reflect.DeepEqual(rule.ReferenceDocs, []string{"docs/security.md"}) || len(rule.Context) != 1 {
+		t.Errorf("Expected reference docs and context to be added, got %+v", rule)
+	}
+}
+
+// TestLoader_LoadFromRepoRevision tests loading .verust-review.yaml from a git revision
+func TestLoader_LoadFromRepoRevision(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, ".verust-review.yaml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	git("commit", "-q", "--allow-empty", "-m", "empty")
+	git("tag", "empty")
+	write("version: \"1.0\"\nrules:\n  - id: base-rule\n    goals:\n      areas: [security]\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "config")
+	// Uncommitted changes of the working tree are ignored
+	write("version: \"1.0\"\nrules:\n  - id: head-rule\n    goals:\n      areas: [security]\n")
+
+	loader := NewLoader()
+	config, err := loader.LoadFromRepoRevision(dir, "HEAD")
+	if err != nil {
+		t.Fatalf("LoadFromRepoRevision() unexpected error: %v", err)
+	}
+	if config == nil || !reflect.DeepEqual(config.GetRuleIDs(), []string{"base-rule"}) {
+		t.Errorf("Expected the committed config, got %+v", config)
+	}
+
+	config, err = loader.LoadFromRepoRevision(dir, "empty")
+	if err != nil || config != nil {
+		t.Errorf("Expected nil config for a revision without the file, got %+v, %v", config, err)
+	}
+
+	if _, err := loader.LoadFromRepoRevision(dir, "0000000000000000000000000000000000000000"); err == nil {
+		t.Error("Expected error for an unknown revision")
+	}
+}
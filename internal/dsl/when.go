@@ -0,0 +1,351 @@
+// Package dsl provides DSL configuration parsing and validation.
+// This file defines conditional rule applicability (`when` clauses).
+package dsl
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// WhenConfig restricts when a review rule runs.
+// All configured conditions must match; within a list, any entry may match.
+// Example YAML:
+//
+//	when:
+//	  paths:
+//	    include: ["db/migrations/**"]
+//	  branches: [main, "release/*"]
+//	  labels: [database]
+//	  exclude_authors: ["dependabot[bot]"]
+//	  diff_size:
+//	    max_lines: 2000
+type WhenConfig struct {
+	// Paths matches changed file paths; the prompt is scoped to the matching files
+	Paths *PathsCondition `yaml:"paths,omitempty" json:"paths,omitempty"`
+
+	// Languages matches the languages of changed files (e.g., go, typescript, sql)
+	// The prompt is scoped to files of these languages
+	Languages []string `yaml:"languages,omitempty" json:"languages,omitempty"`
+
+	// Branches matches the target branch (PR base branch, or the pushed branch) using globs
+	Branches []string `yaml:"branches,omitempty" json:"branches,omitempty"`
+
+	// Labels matches if the PR/MR has any of these labels (case-insensitive)
+	Labels []string `yaml:"labels,omitempty" json:"labels,omitempty"`
+
+	// Authors matches if the PR/MR author is one of these users (case-insensitive)
+	Authors []string `yaml:"authors,omitempty" json:"authors,omitempty"`
+
+	// ExcludeAuthors skips the rule for these users (case-insensitive)
+	ExcludeAuthors []string `yaml:"exclude_authors,omitempty" json:"exclude_authors,omitempty"`
+
+	// DiffSize matches the size of the change set
+	DiffSize *DiffSizeCondition `yaml:"diff_size,omitempty" json:"diff_size,omitempty"`
+}
+
+// PathsCondition matches changed files with glob patterns.
+// Patterns without a slash match the file name in any directory (e.g., "*.go"),
+// "**" matches any number of directories, and a trailing slash matches a whole directory.
+type PathsCondition struct {
+	// Include lists patterns of files in scope (empty = all files)
+	Include []string `yaml:"include,omitempty" json:"include,omitempty"`
+
+	// Exclude lists patterns of files out of scope
+	Exclude []string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+}
+
+// DiffSizeCondition matches the size of the change set (0 = no limit)
+type DiffSizeCondition struct {
+	// MinLines is the minimum number of changed lines (added + deleted)
+	MinLines int `yaml:"min_lines,omitempty" json:"min_lines,omitempty"`
+
+	// MaxLines is the maximum number of changed lines (added + deleted)
+	MaxLines int `yaml:"max_lines,omitempty" json:"max_lines,omitempty"`
+
+	// MinFiles is the minimum number of changed files
+	MinFiles int `yaml:"min_files,omitempty" json:"min_files,omitempty"`
+
+	// MaxFiles is the maximum number of changed files
+	MaxFiles int `yaml:"max_files,omitempty" json:"max_files,omitempty"`
+}
+
+// WhenContext holds the change and PR metadata a when clause is evaluated against
+type WhenContext struct {
+	// ChangedFiles lists the changed files (nil if the change set is unknown, e.g. full audits)
+	ChangedFiles []string
+
+	// TargetBranch is the PR/MR base branch, or the branch for non-PR reviews
+	TargetBranch string
+
+	// Labels lists the PR/MR labels
+	Labels []string
+
+	// Author is the PR/MR author or commit author
+	Author string
+
+	// LinesChanged is the number of changed lines (added + deleted)
+	LinesChanged int
+
+	// FilesChanged is the number of changed files
+	FilesChanged int
+}
+
+// WhenResult is the outcome of evaluating a when clause
+type WhenResult struct {
+	// Matched is true if the rule should run
+	Matched bool
+
+	// Reason explains why the rule does not match
+	Reason string
+
+	// Files lists the changed files in scope (nil = no file scoping)
+	Files []string
+
+	// Languages lists the matched languages (nil = no language scoping)
+	Languages []string
+}
+
+// NeedsChangedFiles returns true if the clause has conditions on changed files
+func (w *WhenConfig) NeedsChangedFiles() bool {
+	return w != nil && (w.Paths != nil || len(w.Languages) > 0)
+}
+
+// Evaluate evaluates the when clause against the given context.
+// A nil clause always matches. Path and language conditions are ignored when
+// the change set is unknown, so full repository audits are never skipped by them.
+func (w *WhenConfig) Evaluate(ctx *WhenContext) WhenResult {
+	if w == nil {
+		return WhenResult{Matched: true}
+	}
+
+	if len(w.Branches) > 0 {
+		if ctx.TargetBranch == "" {
+			return notMatched("target branch is unknown")
+		}
+		if !matchAnyBranch(w.Branches, ctx.TargetBranch) {
+			return notMatched(fmt.Sprintf("target branch %q does not match %s", ctx.TargetBranch, strings.Join(w.Branches, ", ")))
+		}
+	}
+
+	if len(w.Labels) > 0 && !containsAnyFold(w.Labels, ctx.Labels) {
+		return notMatched(fmt.Sprintf("none of the labels %s is present", strings.Join(w.Labels, ", ")))
+	}
+
+	if len(w.Authors) > 0 && !containsAnyFold(w.Authors, []string{ctx.Author}) {
+		return notMatched(fmt.Sprintf("author %q is not one of %s", ctx.Author, strings.Join(w.Authors, ", ")))
+	}
+	if ctx.Author != "" && containsAnyFold(w.ExcludeAuthors, []string{ctx.Author}) {
+		return notMatched(fmt.Sprintf("author %q is excluded", ctx.Author))
+	}
+
+	if d := w.DiffSize; d != nil {
+		if d.MinLines > 0 && ctx.LinesChanged < d.MinLines {
+			return notMatched(fmt.Sprintf("%d changed lines is below min_lines %d", ctx.LinesChanged, d.MinLines))
+		}
+		if d.MaxLines > 0 && ctx.LinesChanged > d.MaxLines {
+			return notMatched(fmt.Sprintf("%d changed lines exceeds max_lines %d", ctx.LinesChanged, d.MaxLines))
+		}
+		if d.MinFiles > 0 && ctx.FilesChanged < d.MinFiles {
+			return notMatched(fmt.Sprintf("%d changed files is below min_files %d", ctx.FilesChanged, d.MinFiles))
+		}
+		if d.MaxFiles > 0 && ctx.FilesChanged > d.MaxFiles {
+			return notMatched(fmt.Sprintf("%d changed files exceeds max_files %d", ctx.FilesChanged, d.MaxFiles))
+		}
+	}
+
+	result := WhenResult{Matched: true}
+	if !w.NeedsChangedFiles() || ctx.ChangedFiles == nil {
+		return result
+	}
+
+	files := ctx.ChangedFiles
+	if w.Paths != nil {
+		files = filterPaths(files, w.Paths)
+		if len(files) == 0 {
+			return notMatched("no changed files match the path conditions")
+		}
+	}
+
+	if len(w.Languages) > 0 {
+		wanted := make(map[string]bool, len(w.Languages))
+		for _, lang := range w.Languages {
+			wanted[strings.ToLower(lang)] = true
+		}
+
+		var scoped []string
+		found := make(map[string]bool)
+		for _, f := range files {
+			if lang := DetectLanguage(f); wanted[lang] {
+				scoped = append(scoped, f)
+				found[lang] = true
+			}
+		}
+		if len(scoped) == 0 {
+			return notMatched(fmt.Sprintf("no changed files are written in %s", strings.Join(w.Languages, ", ")))
+		}
+
+		files = scoped
+		for lang := range found {
+			result.Languages = append(result.Languages, lang)
+		}
+		sort.Strings(result.Languages)
+	}
+
+	result.Files = files
+	return result
+}
+
+// notMatched returns a non-matching result with the given reason
+func notMatched(reason string) WhenResult {
+	return WhenResult{Matched: false, Reason: reason}
+}
+
+// filterPaths returns the files matching the include patterns and none of the exclude patterns
+func filterPaths(files []string, cond *PathsCondition) []string {
+	var matched []string
+	for _, f := range files {
+		if len(cond.Include) > 0 && !matchAnyGlob(cond.Include, f) {
+			continue
+		}
+		if matchAnyGlob(cond.Exclude, f) {
+			continue
+		}
+		matched = append(matched, f)
+	}
+	return matched
+}
+
+// containsAnyFold returns true if any value is in list (case-insensitive)
+func containsAnyFold(list, values []string) bool {
+	for _, v := range values {
+		for _, item := range list {
+			if strings.EqualFold(item, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchAnyGlob returns true if name matches any of the patterns
+func matchAnyGlob(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if MatchGlob(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAnyBranch returns true if branch matches any of the patterns.
+// Unlike file patterns, branch patterns always match the full branch name.
+func matchAnyBranch(patterns []string, branch string) bool {
+	for _, p := range patterns {
+		if matchSegments(strings.Split(p, "/"), strings.Split(branch, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchGlob matches a slash-separated path against a glob pattern.
+// Patterns without a slash match the last path element, "**" matches any
+// number of path elements, and a trailing slash matches everything below a directory.
+func MatchGlob(pattern, name string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	name = strings.TrimPrefix(name, "./")
+
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchSegments matches path segments, expanding "**" to zero or more segments
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+	return len(name) == 0
+}
+
+// validGlob reports whether a glob pattern is syntactically valid
+func validGlob(pattern string) bool {
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// languageExtensions maps file extensions to language identifiers
+var languageExtensions = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".js":    "javascript",
+	".jsx":   "javascript",
+	".mjs":   "javascript",
+	".cjs":   "javascript",
+	".ts":    "typescript",
+	".tsx":   "typescript",
+	".java":  "java",
+	".kt":    "kotlin",
+	".kts":   "kotlin",
+	".rs":    "rust",
+	".c":     "c",
+	".h":     "c",
+	".cc":    "cpp",
+	".cpp":   "cpp",
+	".cxx":   "cpp",
+	".hpp":   "cpp",
+	".cs":    "csharp",
+	".rb":    "ruby",
+	".php":   "php",
+	".swift": "swift",
+	".scala": "scala",
+	".sh":    "shell",
+	".bash":  "shell",
+	".sql":   "sql",
+	".html":  "html",
+	".css":   "css",
+	".scss":  "css",
+	".vue":   "vue",
+	".yaml":  "yaml",
+	".yml":   "yaml",
+	".json":  "json",
+	".md":    "markdown",
+	".proto": "protobuf",
+	".tf":    "terraform",
+}
+
+// DetectLanguage returns the language identifier of a file based on its name.
+// Returns an empty string if the language is unknown.
+func DetectLanguage(file string) string {
+	if path.Base(file) == "Dockerfile" {
+		return "dockerfile"
+	}
+	return languageExtensions[strings.ToLower(path.Ext(file))]
+}
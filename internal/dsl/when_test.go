@@ -0,0 +1,191 @@
+package dsl
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestMatchGlob tests glob matching of changed file paths
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "internal/dsl/when.go", true},
+		{"*.go", "internal/dsl/when.ts", false},
+		{"db/migrations/**", "db/migrations/001_init.sql", true},
+		{"db/migrations/**", "db/migrations/2024/002.sql", true},
+		{"db/migrations/**", "db/seed.sql", false},
+		{"db/migrations/", "db/migrations/001_init.sql", true},
+		{"**/*_test.go", "when_test.go", true},
+		{"**/*_test.go", "internal/dsl/when_test.go", true},
+		{"internal/*/when.go", "internal/dsl/when.go", true},
+		{"internal/*/when.go", "internal/dsl/sub/when.go", false},
+		{"./cmd/**", "cmd/verustcode/main.go", true},
+	}
+
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestDetectLanguage tests language detection from file names
+func TestDetectLanguage(t *testing.T) {
+	tests := map[string]string{
+		"main.go":              "go",
+		"web/src/App.TSX":      "typescript",
+		"db/001_init.sql":      "sql",
+		"build/Dockerfile":     "dockerfile",
+		"README":               "",
+		"config/settings.yaml": "yaml",
+	}
+
+	for file, want := range tests {
+		if got := DetectLanguage(file); got != want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+// TestWhenConfig_Evaluate tests when clause evaluation
+func TestWhenConfig_Evaluate(t *testing.T) {
+	files := []string{"db/migrations/001_init.sql", "internal/store/review.go", "web/src/App.tsx", "README.md"}
+
+	tests := []struct {
+		name          string
+		when          *WhenConfig
+		ctx           WhenContext
+		wantMatched   bool
+		wantFiles     []string
+		wantLanguages []string
+	}{
+		{
+			name:        "nil clause always matches",
+			when:        nil,
+			ctx:         WhenContext{ChangedFiles: files},
+			wantMatched: true,
+		},
+		{
+			name:        "paths include scopes files",
+			when:        &WhenConfig{Paths: &PathsCondition{Include: []string{"db/migrations/**"}}},
+			ctx:         WhenContext{ChangedFiles: files},
+			wantMatched: true,
+			wantFiles:   []string{"db/migrations/001_init.sql"},
+		},
+		{
+			name:        "paths exclude removes files",
+			when:        &WhenConfig{Paths: &PathsCondition{Exclude: []string{"*.md", "web/"}}},
+			ctx:         WhenContext{ChangedFiles: files},
+			wantMatched: true,
+			wantFiles:   []string{"db/migrations/001_init.sql", "internal/store/review.go"},
+		},
+		{
+			name:        "no matching paths",
+			when:        &WhenConfig{Paths: &PathsCondition{Include: []string{"deploy/**"}}},
+			ctx:         WhenContext{ChangedFiles: files},
+			wantMatched: false,
+		},
+		{
+			name:        "paths ignored when change set is unknown",
+			when:        &WhenConfig{Paths: &PathsCondition{Include: []string{"deploy/**"}}},
+			ctx:         WhenContext{},
+			wantMatched: true,
+		},
+		{
+			name:          "languages scope files",
+			when:          &WhenConfig{Languages: []string{"Go", "TypeScript"}},
+			ctx:           WhenContext{ChangedFiles: files},
+			wantMatched:   true,
+			wantFiles:     []string{"internal/store/review.go", "web/src/App.tsx"},
+			wantLanguages: []string{"go", "typescript"},
+		},
+		{
+			name:        "no matching languages",
+			when:        &WhenConfig{Languages: []string{"rust"}},
+			ctx:         WhenContext{ChangedFiles: files},
+			wantMatched: false,
+		},
+		{
+			name:        "branch glob matches",
+			when:        &WhenConfig{Branches: []string{"main", "release/*"}},
+			ctx:         WhenContext{TargetBranch: "release/1.2"},
+			wantMatched: true,
+		},
+		{
+			name:        "branch must match the full name",
+			when:        &WhenConfig{Branches: []string{"main"}},
+			ctx:         WhenContext{TargetBranch: "feature/main"},
+			wantMatched: false,
+		},
+		{
+			name:        "unknown branch does not match",
+			when:        &WhenConfig{Branches: []string{"main"}},
+			ctx:         WhenContext{},
+			wantMatched: false,
+		},
+		{
+			name:        "labels match case-insensitively",
+			when:        &WhenConfig{Labels: []string{"database"}},
+			ctx:         WhenContext{Labels: []string{"bug", "Database"}},
+			wantMatched: true,
+		},
+		{
+			name:        "missing label",
+			when:        &WhenConfig{Labels: []string{"database"}},
+			ctx:         WhenContext{Labels: []string{"bug"}},
+			wantMatched: false,
+		},
+		{
+			name:        "author allowed",
+			when:        &WhenConfig{Authors: []string{"alice"}},
+			ctx:         WhenContext{Author: "Alice"},
+			wantMatched: true,
+		},
+		{
+			name:        "author excluded",
+			when:        &WhenConfig{ExcludeAuthors: []string{"dependabot[bot]"}},
+			ctx:         WhenContext{Author: "dependabot[bot]"},
+			wantMatched: false,
+		},
+		{
+			name:        "diff too large",
+			when:        &WhenConfig{DiffSize: &DiffSizeCondition{MaxLines: 2000}},
+			ctx:         WhenContext{LinesChanged: 2500},
+			wantMatched: false,
+		},
+		{
+			name:        "diff too small",
+			when:        &WhenConfig{DiffSize: &DiffSizeCondition{MinFiles: 3}},
+			ctx:         WhenContext{FilesChanged: 2},
+			wantMatched: false,
+		},
+		{
+			name:        "diff within limits",
+			when:        &WhenConfig{DiffSize: &DiffSizeCondition{MinLines: 10, MaxLines: 2000, MaxFiles: 50}},
+			ctx:         WhenContext{LinesChanged: 100, FilesChanged: 4},
+			wantMatched: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.when.Evaluate(&tt.ctx)
+			if result.Matched != tt.wantMatched {
+				t.Fatalf("Evaluate() matched = %v, want %v (reason: %s)", result.Matched, tt.wantMatched, result.Reason)
+			}
+			if !result.Matched && result.Reason == "" {
+				t.Error("Evaluate() expected a reason for a non-matching clause")
+			}
+			if !reflect.DeepEqual(result.Files, tt.wantFiles) {
+				t.Errorf("Evaluate() files = %v, want %v", result.Files, tt.wantFiles)
+			}
+			if !reflect.DeepEqual(result.Languages, tt.wantLanguages) {
+				t.Errorf("Evaluate() languages = %v, want %v", result.Languages, tt.wantLanguages)
+			}
+		})
+	}
+}
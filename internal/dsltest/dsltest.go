@@ -0,0 +1,386 @@
+// Package dsltest runs review files against fixtures to regression-test review rules.
+//
+// A fixture directory contains a repository snapshot (repo/, and optionally base/ for the
+// base commit), the PR metadata (pr.yaml), canned agent responses (responses/<rule-id>.json)
+// and expectations (expect.yaml). Run executes the full review pipeline on it with the mock
+// agent, then checks rule statuses, findings and published outputs against the expectations
+// and the rendered prompts against golden files (golden/<rule-id>.prompt).
+package dsltest
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	agentmock "github.com/verustcode/verustcode/internal/agent/mock"
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/engine/executor"
+	"github.com/verustcode/verustcode/internal/engine/runner"
+	"github.com/verustcode/verustcode/internal/llm"
+	llmmock "github.com/verustcode/verustcode/internal/llm/mock"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/prompt"
+	"github.com/verustcode/verustcode/internal/store"
+	"github.com/verustcode/verustcode/pkg/idgen"
+)
+
+// Placeholders replacing run-specific values in rendered prompts, so golden files are stable
+const (
+	WorkspacePlaceholder  = "<workspace>"
+	BaseCommitPlaceholder = "<base>"
+	HeadCommitPlaceholder = "<head>"
+)
+
+// Options configures a test run
+type Options struct {
+	// Update rewrites the golden prompts instead of comparing them
+	Update bool
+}
+
+// Result is the outcome of running a review file against a fixture
+type Result struct {
+	// Fixture is the fixture directory
+	Fixture string `json:"fixture"`
+
+	// Rules holds the outcome of each rule, in review file order
+	Rules []RuleOutcome `json:"rules"`
+
+	// Outputs lists the published outputs (files, then comments, then webhooks)
+	Outputs []Output `json:"outputs"`
+
+	// Failures lists the failed assertions
+	Failures []string `json:"failures,omitempty"`
+}
+
+// RuleOutcome is the outcome of a rule
+type RuleOutcome struct {
+	RuleID     string `json:"rule_id"`
+	Status     string `json:"status"`
+	SkipReason string `json:"skip_reason,omitempty"`
+	Error      string `json:"error,omitempty"`
+
+	// Prompt is the rendered prompt, with run-specific values replaced by placeholders
+	Prompt string `json:"prompt,omitempty"`
+
+	// Findings lists the findings of the rule's result
+	Findings []map[string]interface{} `json:"findings,omitempty"`
+}
+
+// Passed returns true if all assertions passed
+func (r *Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// Rule returns the outcome of a rule, or nil if the rule was not run
+func (r *Result) Rule(ruleID string) *RuleOutcome {
+	for i := range r.Rules {
+		if r.Rules[i].RuleID == ruleID {
+			return &r.Rules[i]
+		}
+	}
+	return nil
+}
+
+// Run runs the rules of a review file against a fixture directory.
+// Every agent referenced by the rules is replaced by the mock agent answering with the
+// fixture's canned responses; comment and webhook channels are recorded instead of
+// delivered, and file channels write into a temporary directory.
+// Returns an error if the fixture cannot be loaded or run; failed assertions are reported
+// in Result.Failures.
+func Run(ctx context.Context, rulesConfig *dsl.ReviewRulesConfig, fixtureDir string, opts *Options) (*Result, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	f, err := LoadFixture(fixtureDir)
+	if err != nil {
+		return nil, err
+	}
+
+	workDir, err := os.MkdirTemp("", "verustcode-dsltest-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	ws, err := prepareWorkspace(ctx, f, filepath.Join(workDir, RepoDir))
+	if err != nil {
+		return nil, fmt.Errorf("fixture %s: failed to prepare repository: %w", fixtureDir, err)
+	}
+
+	s, closeStore, err := openStore(filepath.Join(workDir, "dsltest.db"))
+	if err != nil {
+		return nil, err
+	}
+	defer closeStore()
+
+	webhooks := newWebhookRecorder()
+	defer webhooks.close()
+	prov := &recordingProvider{}
+
+	rules := redirectOutputs(rulesConfig, webhooks)
+
+	exec := executor.NewExecutor(&config.Config{}, mockAgents(rules, f.Responses), prompt.NewBuilder(), s)
+	r := runner.NewRunner(&config.Config{}, s, exec, prompt.NewBuilder())
+
+	now := time.Now()
+	review := &model.Review{
+		ID:            idgen.NewReviewID(),
+		Ref:           f.PR.Ref,
+		CommitSHA:     ws.headCommit,
+		BaseCommitSHA: ws.baseCommit,
+		PRNumber:      f.PR.Number,
+		RepoURL:       f.PR.RepoURL,
+		RepoPath:      ws.repoPath,
+		Source:        "cli",
+		Author:        f.PR.Author,
+		Status:        model.ReviewStatusRunning,
+		StartedAt:     &now,
+		FilesChanged:  len(ws.changedFiles),
+	}
+	if f.PR.Number > 0 {
+		review.PRURL = fmt.Sprintf("%s/pull/%d", strings.TrimSuffix(f.PR.RepoURL, ".git"), f.PR.Number)
+	}
+	if err := s.Review().Create(review); err != nil {
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+
+	outputDir := filepath.Join(workDir, "output")
+	req := &runner.ReviewRequest{
+		RepoPath:          ws.repoPath,
+		RepoURL:           f.PR.RepoURL,
+		Ref:               f.PR.Ref,
+		CommitSHA:         ws.headCommit,
+		PRNumber:          f.PR.Number,
+		PRTitle:           f.PR.Title,
+		PRDescription:     f.PR.Description,
+		BaseCommitSHA:     ws.baseCommit,
+		Source:            review.Source,
+		TargetBranch:      f.PR.TargetBranch,
+		Author:            f.PR.Author,
+		Labels:            f.PR.Labels,
+		LinesChanged:      ws.linesChanged,
+		FilesChanged:      len(ws.changedFiles),
+		ChangedFiles:      ws.changedFiles,
+		Commits:           []string{ws.headCommit},
+		ReviewRulesConfig: rules,
+		OutputDir:         outputDir,
+	}
+
+	// Rule failures are reported through the rule outcomes
+	_, _ = r.RunReviewWithTracking(ctx, req, review, prov)
+
+	result := &Result{Fixture: fixtureDir}
+	if result.Rules, err = collectOutcomes(s, review.ID, rules, ws); err != nil {
+		return nil, err
+	}
+	if result.Outputs, err = collectFileOutputs(outputDir); err != nil {
+		return nil, err
+	}
+	result.Outputs = append(result.Outputs, prov.outputs()...)
+	result.Outputs = append(result.Outputs, webhooks.recorded()...)
+
+	if err := result.check(f, opts.Update); err != nil {
+		return nil, fmt.Errorf("fixture %s: failed to check golden prompts: %w", fixtureDir, err)
+	}
+	return result, nil
+}
+
+// openStore opens a SQLite database for the run
+func openStore(path string) (store.Store, func(), error) {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := db.AutoMigrate(model.AllModels()...); err != nil {
+		return nil, nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	closeFn := func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}
+	return store.NewStore(db), closeFn, nil
+}
+
+// redirectOutputs returns a copy of the review file whose file channels write below a
+// directory named after the rule, and whose webhook channels post to the recorder
+func redirectOutputs(rulesConfig *dsl.ReviewRulesConfig, webhooks *webhookRecorder) *dsl.ReviewRulesConfig {
+	copied := *rulesConfig
+	copied.Rules = make([]dsl.ReviewRuleConfig, len(rulesConfig.Rules))
+
+	for i, rule := range rulesConfig.Rules {
+		if rule.Output != nil {
+			out := *rule.Output
+			out.Channels = make([]dsl.OutputItemConfig, len(rule.Output.Channels))
+			for j, ch := range rule.Output.Channels {
+				switch ch.Type {
+				case ChannelFile:
+					ch.Dir = filepath.Join(rule.ID, filepath.Clean(string(filepath.Separator)+ch.Dir))
+				case ChannelWebhook:
+					ch.URL = webhooks.redirect(rule.ID, ch.URL)
+					ch.MaxRetries = 1
+				}
+				out.Channels[j] = ch
+			}
+			rule.Output = &out
+		}
+		copied.Rules[i] = rule
+	}
+	return &copied
+}
+
+// mockAgents returns the mock agent, answering with the canned responses, under the name
+// of every agent referenced by the rules
+func mockAgents(rulesConfig *dsl.ReviewRulesConfig, responses map[string][]string) map[string]base.Agent {
+	agent := agentmock.NewAgentWithClient(
+		llmmock.NewClientWithResponder(llm.NewClientConfig(llmmock.ClientName), newResponder(responses)))
+
+	agents := map[string]base.Agent{agentmock.AgentName: agent}
+	for i := range rulesConfig.Rules {
+		rule := &rulesConfig.Rules[i]
+		agents[rule.Agent.GetType()] = agent
+		for _, name := range rule.Agent.Fallback {
+			agents[name] = agent
+		}
+	}
+	return agents
+}
+
+// newResponder returns a responder answering each rule with its canned responses in order.
+// The last response of a rule is repeated; rules without responses fail.
+func newResponder(responses map[string][]string) llmmock.Responder {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+
+	return func(req *llm.Request) (string, error) {
+		ruleID := req.GetMetadata("rule_id")
+		seq := responses[ruleID]
+		if len(seq) == 0 {
+			return "", llm.NewClientError(llmmock.ClientName, "execute",
+				fmt.Sprintf("no canned response for rule %s (add %s/%s.json to the fixture)", ruleID, ResponsesDir, ruleID), nil)
+		}
+
+		mu.Lock()
+		i := calls[ruleID]
+		calls[ruleID]++
+		mu.Unlock()
+
+		if i >= len(seq) {
+			i = len(seq) - 1
+		}
+		return seq[i], nil
+	}
+}
+
+// collectOutcomes reads the outcome of each rule from the store
+func collectOutcomes(s store.Store, reviewID string, rulesConfig *dsl.ReviewRulesConfig, ws *workspace) ([]RuleOutcome, error) {
+	reviewRules, err := s.Review().GetRulesByReviewID(reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load review rules: %w", err)
+	}
+	byID := make(map[string]*model.ReviewRule, len(reviewRules))
+	for i := range reviewRules {
+		byID[reviewRules[i].RuleID] = &reviewRules[i]
+	}
+
+	replacer := strings.NewReplacer(
+		ws.repoPath, WorkspacePlaceholder,
+		ws.baseCommit, BaseCommitPlaceholder,
+		ws.headCommit, HeadCommitPlaceholder,
+	)
+
+	outcomes := make([]RuleOutcome, 0, len(rulesConfig.Rules))
+	for _, rule := range rulesConfig.Rules {
+		outcome := RuleOutcome{RuleID: rule.ID, Status: string(model.RuleStatusPending)}
+		reviewRule, ok := byID[rule.ID]
+		if !ok {
+			outcomes = append(outcomes, outcome)
+			continue
+		}
+
+		outcome.Status = string(reviewRule.Status)
+		outcome.SkipReason = reviewRule.SkipReason
+		outcome.Error = reviewRule.ErrorMessage
+		outcome.Prompt = replacer.Replace(reviewRule.Prompt)
+
+		results, err := s.Review().GetResultsByRuleID(reviewRule.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load results of rule %s: %w", rule.ID, err)
+		}
+		for _, res := range results {
+			outcome.Findings = append(outcome.Findings, extractFindings(res.Data)...)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes, nil
+}
+
+// extractFindings returns the findings array of a result
+func extractFindings(data model.JSONMap) []map[string]interface{} {
+	items, ok := data["findings"].([]interface{})
+	if !ok {
+		return nil
+	}
+	findings := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			findings = append(findings, m)
+		}
+	}
+	return findings
+}
+
+// collectFileOutputs reads the files written by file channels.
+// The first path segment is the rule ID (see redirectOutputs).
+func collectFileOutputs(outputDir string) ([]Output, error) {
+	var outputs []Output
+	err := filepath.WalkDir(outputDir, func(path string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(outputDir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		ruleID, target, _ := strings.Cut(filepath.ToSlash(rel), "/")
+		outputs = append(outputs, Output{Channel: ChannelFile, RuleID: ruleID, Target: target, Content: string(data)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file outputs: %w", err)
+	}
+	return outputs, nil
+}
+
+// sortedRuleIDs returns the rule IDs of the expectations in order
+func sortedRuleIDs(rules map[string]RuleExpectation) []string {
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,160 @@
+package dsltest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/llm"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+func init() {
+	logger.Init(logger.Config{
+		Level:  "error",
+		Format: "text",
+	})
+}
+
+const testFixture = "testdata/fixtures/sql-injection"
+
+// loadTestReview loads the review file used by the test fixtures
+func loadTestReview(t *testing.T) *dsl.ReviewRulesConfig {
+	config, err := dsl.NewLoader().Load("testdata/review.yaml")
+	require.NoError(t, err)
+	return config
+}
+
+// copyFixture copies the test fixture to a temporary directory
+func copyFixture(t *testing.T) string {
+	dir := filepath.Join(t.TempDir(), "fixture")
+	require.NoError(t, copyDir(testFixture, dir))
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	result, err := Run(context.Background(), loadTestReview(t), testFixture, nil)
+	require.NoError(t, err)
+	assert.Empty(t, result.Failures)
+	assert.True(t, result.Passed())
+
+	security := result.Rule("security")
+	require.NotNil(t, security)
+	assert.Contains(t, security.Prompt, "store/users.go")
+	assert.Contains(t, security.Prompt, "Simplify user lookup")
+	assert.NotContains(t, security.Prompt, os.TempDir())
+
+	assert.Equal(t, "skipped", result.Rule("docs").Status)
+	assert.Empty(t, result.Rule("docs").Prompt)
+
+	channels := make(map[string]string)
+	for _, out := range result.Outputs {
+		if out.RuleID == "security" {
+			channels[out.Channel] = out.Target
+		}
+	}
+	assert.Equal(t, "reports/review-repo-42-security.json", channels[ChannelFile])
+	assert.Equal(t, "pr #42", channels[ChannelComment])
+	assert.Equal(t, "https://example.com/hooks/review", channels[ChannelWebhook])
+}
+
+func TestRun_Update(t *testing.T) {
+	dir := copyFixture(t)
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, GoldenDir)))
+
+	// Missing golden prompts fail
+	result, err := Run(context.Background(), loadTestReview(t), dir, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Failures, 2)
+	assert.Contains(t, result.Failures[0], "missing golden prompt")
+
+	// Update writes them
+	result, err = Run(context.Background(), loadTestReview(t), dir, &Options{Update: true})
+	require.NoError(t, err)
+	assert.True(t, result.Passed())
+
+	data, err := os.ReadFile(filepath.Join(dir, GoldenDir, "security"+GoldenExt))
+	require.NoError(t, err)
+	assert.Equal(t, result.Rule("security").Prompt, string(data))
+	assert.NoFileExists(t, filepath.Join(dir, GoldenDir, "docs"+GoldenExt))
+
+	result, err = Run(context.Background(), loadTestReview(t), dir, nil)
+	require.NoError(t, err)
+	assert.True(t, result.Passed())
+}
+
+func TestRun_Failures(t *testing.T) {
+	dir := copyFixture(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ExpectFile), []byte(`
+rules:
+  security:
+    findings: 2
+    findings_include:
+      - severity: low
+    outputs:
+      - channel: comment
+        contains: ["not in the comment"]
+  docs:
+    status: completed
+  unknown:
+    status: completed
+`), 0644))
+	golden := filepath.Join(dir, GoldenDir, "security"+GoldenExt)
+	require.NoError(t, os.WriteFile(golden, []byte("outdated prompt\n"), 0644))
+
+	result, err := Run(context.Background(), loadTestReview(t), dir, nil)
+	require.NoError(t, err)
+	assert.False(t, result.Passed())
+	assert.Equal(t, []string{
+		"rule docs: status is skipped, expected completed",
+		"rule security: 1 findings, expected 2",
+		"rule security: no finding matches map[severity:low]",
+		`rule security: no comment output contains ["not in the comment"]`,
+		"rule unknown: not run (unknown rule ID)",
+		`rule security: prompt differs from ` + golden + `: line 1: expected "outdated prompt", got "## Role"`,
+	}, result.Failures)
+}
+
+func TestLoadFixture(t *testing.T) {
+	f, err := LoadFixture(testFixture)
+	require.NoError(t, err)
+	assert.Equal(t, 42, f.PR.Number)
+	assert.Equal(t, "main", f.PR.TargetBranch)
+	assert.Len(t, f.Responses["security"], 1)
+	assert.Contains(t, f.Expect.Rules, "security")
+
+	_, err = LoadFixture(t.TempDir())
+	assert.ErrorContains(t, err, "missing repo directory")
+}
+
+func TestNewResponder(t *testing.T) {
+	respond := newResponder(map[string][]string{"rule": {"first", "second"}})
+	// Responses are returned in order and the last one repeats
+	for _, want := range []string{"first", "second", "second"} {
+		got, err := respond(requestForRule("rule"))
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := respond(requestForRule("other"))
+	assert.ErrorContains(t, err, "no canned response for rule other")
+}
+
+// requestForRule returns an LLM request of a rule
+func requestForRule(ruleID string) *llm.Request {
+	return llm.NewRequest("prompt").WithOptions(&llm.RequestOptions{
+		Metadata: map[string]string{"rule_id": ruleID},
+	})
+}
+
+func TestFirstDiff(t *testing.T) {
+	assert.Empty(t, firstDiff("a\nb", "a\nb"))
+	assert.Equal(t, `line 2: expected "b", got "c"`, firstDiff("a\nb", "a\nc"))
+	assert.Equal(t, `line 3: expected "", got "c"`, firstDiff("a\nb", "a\nb\nc"))
+}
@@ -0,0 +1,195 @@
+// Package dsltest runs review files against fixtures to regression-test review rules.
+// This file defines fixture expectations and checks run outcomes against them.
+package dsltest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Expectations are the assertions of a fixture (expect.yaml)
+type Expectations struct {
+	// Rules holds the expectations of each rule, by rule ID.
+	// Rules without expectations are only checked against their golden prompt.
+	Rules map[string]RuleExpectation `yaml:"rules"`
+}
+
+// RuleExpectation holds the expectations of a rule
+type RuleExpectation struct {
+	// Status is the expected rule status: completed, skipped or failed
+	Status string `yaml:"status"`
+
+	// SkipReason must be contained in the skip reason of the rule
+	SkipReason string `yaml:"skip_reason"`
+
+	// Error must be contained in the error message of the rule
+	Error string `yaml:"error"`
+
+	// Findings is the expected number of findings
+	Findings *int `yaml:"findings"`
+
+	// FindingsInclude lists findings that must be reported.
+	// A finding matches if all listed fields are equal (compared as text).
+	FindingsInclude []map[string]string `yaml:"findings_include"`
+
+	// Outputs lists outputs the rule must publish
+	Outputs []OutputExpectation `yaml:"outputs"`
+}
+
+// OutputExpectation describes an output a rule must publish
+type OutputExpectation struct {
+	// Channel is the channel type: file, comment or webhook
+	Channel string `yaml:"channel"`
+
+	// Contains lists texts the output content must contain
+	Contains []string `yaml:"contains"`
+}
+
+// check checks the outcome of the run against the fixture's expectations and golden prompts.
+// With update set, golden prompts are rewritten instead of compared.
+func (r *Result) check(f *Fixture, update bool) error {
+	for _, ruleID := range sortedRuleIDs(f.Expect.Rules) {
+		exp := f.Expect.Rules[ruleID]
+		outcome := r.Rule(ruleID)
+		if outcome == nil {
+			r.failf("rule %s: not run (unknown rule ID)", ruleID)
+			continue
+		}
+		r.checkRule(outcome, &exp)
+	}
+
+	for i := range r.Rules {
+		outcome := &r.Rules[i]
+		if outcome.Prompt == "" {
+			continue // skipped rules render no prompt
+		}
+		if err := r.checkGolden(f, outcome, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRule checks the outcome of a rule against its expectations
+func (r *Result) checkRule(outcome *RuleOutcome, exp *RuleExpectation) {
+	id := outcome.RuleID
+
+	if exp.Status != "" && exp.Status != outcome.Status {
+		detail := ""
+		if outcome.Error != "" {
+			detail = " (" + outcome.Error + ")"
+		}
+		r.failf("rule %s: status is %s%s, expected %s", id, outcome.Status, detail, exp.Status)
+	}
+	if exp.SkipReason != "" && !strings.Contains(outcome.SkipReason, exp.SkipReason) {
+		r.failf("rule %s: skip reason %q does not contain %q", id, outcome.SkipReason, exp.SkipReason)
+	}
+	if exp.Error != "" && !strings.Contains(outcome.Error, exp.Error) {
+		r.failf("rule %s: error %q does not contain %q", id, outcome.Error, exp.Error)
+	}
+	if exp.Findings != nil && len(outcome.Findings) != *exp.Findings {
+		r.failf("rule %s: %d findings, expected %d", id, len(outcome.Findings), *exp.Findings)
+	}
+	for _, want := range exp.FindingsInclude {
+		if !containsFinding(outcome.Findings, want) {
+			r.failf("rule %s: no finding matches %v", id, want)
+		}
+	}
+	for _, want := range exp.Outputs {
+		if !r.hasOutput(id, &want) {
+			r.failf("rule %s: no %s output contains %q", id, want.Channel, want.Contains)
+		}
+	}
+}
+
+// containsFinding returns true if a finding has all fields of want
+func containsFinding(findings []map[string]interface{}, want map[string]string) bool {
+	for _, finding := range findings {
+		match := true
+		for field, value := range want {
+			got, ok := finding[field]
+			if !ok || fmt.Sprint(got) != value {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOutput returns true if the rule published an output on the channel containing all texts
+func (r *Result) hasOutput(ruleID string, want *OutputExpectation) bool {
+	for _, out := range r.Outputs {
+		if out.RuleID != ruleID || out.Channel != want.Channel {
+			continue
+		}
+		match := true
+		for _, text := range want.Contains {
+			if !strings.Contains(out.Content, text) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// checkGolden compares the rendered prompt of a rule with its golden file, or rewrites it
+func (r *Result) checkGolden(f *Fixture, outcome *RuleOutcome, update bool) error {
+	path := filepath.Join(f.Dir, GoldenDir, outcome.RuleID+GoldenExt)
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(outcome.Prompt), 0644)
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		r.failf("rule %s: missing golden prompt %s (run with --update to create it)", outcome.RuleID, path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if diff := firstDiff(string(data), outcome.Prompt); diff != "" {
+		r.failf("rule %s: prompt differs from %s: %s", outcome.RuleID, path, diff)
+	}
+	return nil
+}
+
+// firstDiff describes the first differing line of two texts, or returns "" if they are equal
+func firstDiff(want, got string) string {
+	if want == got {
+		return ""
+	}
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g || i >= len(wantLines) || i >= len(gotLines) {
+			return fmt.Sprintf("line %d: expected %q, got %q", i+1, w, g)
+		}
+	}
+	return ""
+}
+
+// failf records an assertion failure
+func (r *Result) failf(format string, args ...interface{}) {
+	r.Failures = append(r.Failures, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,286 @@
+// Package dsltest runs review files against fixtures to regression-test review rules.
+// This file loads fixtures and prepares their repository snapshot.
+package dsltest
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/verustcode/verustcode/internal/engine/utils"
+)
+
+// Fixture file and directory names
+const (
+	PRFile       = "pr.yaml"     // PR metadata
+	ExpectFile   = "expect.yaml" // expectations (optional)
+	RepoDir      = "repo"        // repository snapshot at the head commit
+	BaseDir      = "base"        // repository snapshot at the base commit (optional)
+	ResponsesDir = "responses"   // canned agent responses, one per rule
+	GoldenDir    = "golden"      // golden rendered prompts, one per rule
+)
+
+// GoldenExt is the file extension of golden prompt files (golden/<rule-id>.prompt)
+const GoldenExt = ".prompt"
+
+// Default PR metadata values
+const (
+	DefaultRepoURL = "https://github.com/example/repo"
+	DefaultRef     = "feature"
+)
+
+// fixedGitDate is the author and committer date of snapshot commits, so commit SHAs are stable
+const fixedGitDate = "2024-01-01T00:00:00Z"
+
+// PRMetadata describes the pull request of a fixture (pr.yaml)
+type PRMetadata struct {
+	Number       int      `yaml:"number"`
+	Title        string   `yaml:"title"`
+	Description  string   `yaml:"description"`
+	RepoURL      string   `yaml:"repo_url"`      // default: https://github.com/example/repo
+	Ref          string   `yaml:"ref"`           // default: feature
+	TargetBranch string   `yaml:"target_branch"` // default: main
+	Author       string   `yaml:"author"`
+	Labels       []string `yaml:"labels"`
+
+	// ChangedFiles overrides the files changed between the base and repo snapshots
+	ChangedFiles []string `yaml:"changed_files"`
+}
+
+// Fixture is a loaded fixture directory
+type Fixture struct {
+	// Dir is the fixture directory
+	Dir string
+
+	// PR is the pull request metadata
+	PR PRMetadata
+
+	// Responses holds the canned agent responses of each rule.
+	// A rule called several times (multi-run, chunking) gets them in order; the last one repeats.
+	Responses map[string][]string
+
+	// Expect holds the expectations (empty if the fixture has no expect.yaml)
+	Expect Expectations
+}
+
+// LoadFixture loads a fixture directory
+func LoadFixture(dir string) (*Fixture, error) {
+	if info, err := os.Stat(filepath.Join(dir, RepoDir)); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("fixture %s: missing %s directory", dir, RepoDir)
+	}
+
+	f := &Fixture{Dir: dir}
+
+	data, err := os.ReadFile(filepath.Join(dir, PRFile))
+	if err != nil {
+		return nil, fmt.Errorf("fixture %s: failed to read %s: %w", dir, PRFile, err)
+	}
+	if err := yaml.Unmarshal(data, &f.PR); err != nil {
+		return nil, fmt.Errorf("fixture %s: invalid %s: %w", dir, PRFile, err)
+	}
+	if f.PR.RepoURL == "" {
+		f.PR.RepoURL = DefaultRepoURL
+	}
+	if f.PR.Ref == "" {
+		f.PR.Ref = DefaultRef
+	}
+	if f.PR.TargetBranch == "" {
+		f.PR.TargetBranch = "main"
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, ExpectFile))
+	if err == nil {
+		if err := yaml.Unmarshal(data, &f.Expect); err != nil {
+			return nil, fmt.Errorf("fixture %s: invalid %s: %w", dir, ExpectFile, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("fixture %s: failed to read %s: %w", dir, ExpectFile, err)
+	}
+
+	f.Responses, err = loadResponses(filepath.Join(dir, ResponsesDir))
+	if err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", dir, err)
+	}
+
+	return f, nil
+}
+
+// loadResponses loads canned responses: responses/<rule-id>.<ext> holds the single response
+// of a rule, responses/<rule-id>/ holds a sequence of responses in file name order
+func loadResponses(dir string) (map[string][]string, error) {
+	responses := make(map[string][]string)
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return responses, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ResponsesDir, err)
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if !entry.IsDir() {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read response %s: %w", entry.Name(), err)
+			}
+			ruleID := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+			responses[ruleID] = append(responses[ruleID], string(data))
+			continue
+		}
+
+		files, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read responses of %s: %w", entry.Name(), err)
+		}
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(path, file.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read response %s/%s: %w", entry.Name(), file.Name(), err)
+			}
+			responses[entry.Name()] = append(responses[entry.Name()], string(data))
+		}
+	}
+	return responses, nil
+}
+
+// workspace is a git repository built from the fixture snapshots
+type workspace struct {
+	repoPath     string
+	baseCommit   string
+	headCommit   string
+	changedFiles []string
+	linesChanged int
+}
+
+// prepareWorkspace creates a git repository in repoPath with a base commit (the base snapshot,
+// or an empty tree) and a head commit (the repo snapshot)
+func prepareWorkspace(ctx context.Context, f *Fixture, repoPath string) (*workspace, error) {
+	if err := os.MkdirAll(repoPath, 0755); err != nil {
+		return nil, err
+	}
+	if _, err := git(ctx, repoPath, "init", "-q"); err != nil {
+		return nil, err
+	}
+
+	ws := &workspace{repoPath: repoPath}
+	var err error
+
+	if err := copyDir(filepath.Join(f.Dir, BaseDir), repoPath); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to copy %s snapshot: %w", BaseDir, err)
+	}
+	if ws.baseCommit, err = commitAll(ctx, repoPath, "base"); err != nil {
+		return nil, err
+	}
+
+	if err := clearWorktree(repoPath); err != nil {
+		return nil, err
+	}
+	if err := copyDir(filepath.Join(f.Dir, RepoDir), repoPath); err != nil {
+		return nil, fmt.Errorf("failed to copy %s snapshot: %w", RepoDir, err)
+	}
+	if ws.headCommit, err = commitAll(ctx, repoPath, f.PR.Title); err != nil {
+		return nil, err
+	}
+
+	stats := utils.GetFileDiffStats(ctx, repoPath, ws.baseCommit, ws.headCommit)
+	for _, s := range stats {
+		ws.changedFiles = append(ws.changedFiles, s.Path)
+		ws.linesChanged += s.LinesChanged
+	}
+	if len(f.PR.ChangedFiles) > 0 {
+		ws.changedFiles = f.PR.ChangedFiles
+	}
+	return ws, nil
+}
+
+// commitAll commits the whole worktree and returns the commit SHA
+func commitAll(ctx context.Context, repoPath, message string) (string, error) {
+	if message == "" {
+		message = "head"
+	}
+	if _, err := git(ctx, repoPath, "add", "-A"); err != nil {
+		return "", err
+	}
+	if _, err := git(ctx, repoPath, "commit", "-q", "--allow-empty", "--no-verify", "-m", message); err != nil {
+		return "", err
+	}
+	sha, err := git(ctx, repoPath, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(sha), nil
+}
+
+// git runs a git command in the repository with a fixed identity and date
+func git(ctx context.Context, repoPath string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", repoPath, "-c", "commit.gpgsign=false"}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=verustcode",
+		"GIT_AUTHOR_EMAIL=dsltest@verustcode.local",
+		"GIT_AUTHOR_DATE="+fixedGitDate,
+		"GIT_COMMITTER_NAME=verustcode",
+		"GIT_COMMITTER_EMAIL=dsltest@verustcode.local",
+		"GIT_COMMITTER_DATE="+fixedGitDate,
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// clearWorktree removes everything but .git from the repository
+func clearWorktree(repoPath string) error {
+	entries, err := os.ReadDir(repoPath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() == ".git" {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(repoPath, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyDir copies the regular files of src into dst
+func copyDir(src, dst string) error {
+	if _, err := os.Stat(src); err != nil {
+		return err
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+}
@@ -0,0 +1,295 @@
+// Package dsltest runs review files against fixtures to regression-test review rules.
+// This file records the outputs published to comment and webhook channels.
+package dsltest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/verustcode/verustcode/internal/git/provider"
+	"github.com/verustcode/verustcode/internal/output"
+)
+
+// Output channel types of recorded outputs
+const (
+	ChannelFile    = "file"
+	ChannelComment = "comment"
+	ChannelWebhook = "webhook"
+)
+
+// Output is an output published by a rule
+type Output struct {
+	// Channel is the channel type: file, comment or webhook
+	Channel string `json:"channel"`
+
+	// RuleID is the rule that published the output
+	RuleID string `json:"rule_id"`
+
+	// Target is the file path (relative to the output directory), the PR or commit of a comment,
+	// or the configured webhook URL
+	Target string `json:"target"`
+
+	// Content is the file content, comment body or decoded webhook data
+	Content string `json:"content"`
+}
+
+// errNotSupported is returned by provider operations the harness does not emulate
+var errNotSupported = fmt.Errorf("not supported in DSL tests")
+
+// commentMarkerPattern matches the [prefix:rule-id] marker on the first line of a comment
+var commentMarkerPattern = regexp.MustCompile(`^\[[^:\]]+:([^\]]+)\]`)
+
+// recordingProvider is a Git provider that keeps posted comments in memory
+type recordingProvider struct {
+	mu       sync.Mutex
+	nextID   int64
+	comments []*recordedComment
+}
+
+// recordedComment is a comment kept by recordingProvider
+type recordedComment struct {
+	provider.Comment
+	prNumber  int
+	commitSHA string
+}
+
+// outputs returns the comments in the order they were first posted
+func (p *recordingProvider) outputs() []Output {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	outputs := make([]Output, 0, len(p.comments))
+	for _, c := range p.comments {
+		target := fmt.Sprintf("pr #%d", c.prNumber)
+		if c.prNumber == 0 {
+			target = "commit " + c.commitSHA
+		}
+		ruleID := ""
+		if m := commentMarkerPattern.FindStringSubmatch(c.Body); m != nil {
+			ruleID = m[1]
+		}
+		outputs = append(outputs, Output{Channel: ChannelComment, RuleID: ruleID, Target: target, Content: c.Body})
+	}
+	return outputs
+}
+
+// Name returns the provider name
+func (p *recordingProvider) Name() string { return "dsltest" }
+
+// GetBaseURL returns the provider base URL
+func (p *recordingProvider) GetBaseURL() string { return "" }
+
+// Clone is not supported
+func (p *recordingProvider) Clone(ctx context.Context, owner, repo, destPath string, opts *provider.CloneOptions) error {
+	return errNotSupported
+}
+
+// ClonePR is not supported
+func (p *recordingProvider) ClonePR(ctx context.Context, owner, repo string, prNumber int, destPath string, opts *provider.CloneOptions) error {
+	return errNotSupported
+}
+
+// GetPRRef returns the Git ref of a PR
+func (p *recordingProvider) GetPRRef(prNumber int) string {
+	return fmt.Sprintf("refs/pull/%d/head", prNumber)
+}
+
+// GetPullRequest is not supported
+func (p *recordingProvider) GetPullRequest(ctx context.Context, owner, repo string, number int) (*provider.PullRequest, error) {
+	return nil, errNotSupported
+}
+
+// ListPullRequests is not supported
+func (p *recordingProvider) ListPullRequests(ctx context.Context, owner, repo string) ([]*provider.PullRequest, error) {
+	return nil, errNotSupported
+}
+
+// PostComment records a comment
+func (p *recordingProvider) PostComment(ctx context.Context, owner, repo string, opts *provider.CommentOptions, body string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nextID++
+	p.comments = append(p.comments, &recordedComment{
+		Comment:   provider.Comment{ID: p.nextID, Body: body, Author: "verustcode"},
+		prNumber:  opts.PRNumber,
+		commitSHA: opts.CommitSHA,
+	})
+	return nil
+}
+
+// ListComments lists the recorded comments of a PR
+func (p *recordingProvider) ListComments(ctx context.Context, owner, repo string, prNumber int) ([]*provider.Comment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var comments []*provider.Comment
+	for _, c := range p.comments {
+		if c.prNumber == prNumber {
+			comment := c.Comment
+			comments = append(comments, &comment)
+		}
+	}
+	return comments, nil
+}
+
+// DeleteComment deletes a recorded comment
+func (p *recordingProvider) DeleteComment(ctx context.Context, owner, repo string, commentID int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, c := range p.comments {
+		if c.ID == commentID {
+			p.comments = append(p.comments[:i], p.comments[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("comment %d not found", commentID)
+}
+
+// UpdateComment updates a recorded comment
+func (p *recordingProvider) UpdateComment(ctx context.Context, owner, repo string, commentID int64, prNumber int, body string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, c := range p.comments {
+		if c.ID == commentID {
+			c.Body = body
+			return nil
+		}
+	}
+	return fmt.Errorf("comment %d not found", commentID)
+}
+
+// ListCommitComments lists the recorded comments of a commit
+func (p *recordingProvider) ListCommitComments(ctx context.Context, owner, repo, commitSHA string) ([]*provider.Comment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var comments []*provider.Comment
+	for _, c := range p.comments {
+		if c.prNumber == 0 && c.commitSHA == commitSHA {
+			comment := c.Comment
+			comments = append(comments, &comment)
+		}
+	}
+	return comments, nil
+}
+
+// UpdateCommitComment updates a recorded commit comment
+func (p *recordingProvider) UpdateCommitComment(ctx context.Context, owner, repo, commitSHA string, commentID int64, body string) error {
+	return p.UpdateComment(ctx, owner, repo, commentID, 0, body)
+}
+
+// ParseWebhook is not supported
+func (p *recordingProvider) ParseWebhook(r *http.Request, secret string) (*provider.WebhookEvent, error) {
+	return nil, errNotSupported
+}
+
+// CreateWebhook is not supported
+func (p *recordingProvider) CreateWebhook(ctx context.Context, owner, repo, url, secret string, events []string) (string, error) {
+	return "", errNotSupported
+}
+
+// DeleteWebhook is not supported
+func (p *recordingProvider) DeleteWebhook(ctx context.Context, owner, repo, webhookID string) error {
+	return errNotSupported
+}
+
+// ValidateToken always succeeds
+func (p *recordingProvider) ValidateToken(ctx context.Context) error { return nil }
+
+// ParseRepoPath parses owner and repo from the last two segments of a repository URL
+func (p *recordingProvider) ParseRepoPath(repoURL string) (owner, repo string, err error) {
+	parts := strings.Split(strings.TrimSuffix(strings.TrimSuffix(repoURL, "/"), ".git"), "/")
+	if len(parts) < 2 {
+		return "", "", fmt.Errorf("invalid repository URL: %s", repoURL)
+	}
+	return parts[len(parts)-2], parts[len(parts)-1], nil
+}
+
+// ListBranches is not supported
+func (p *recordingProvider) ListBranches(ctx context.Context, owner, repo string) ([]string, error) {
+	return nil, errNotSupported
+}
+
+// MatchesURL matches any repository URL
+func (p *recordingProvider) MatchesURL(repoURL string) bool { return true }
+
+// webhookRecorder is a local HTTP server standing in for the webhook endpoints of the rules
+type webhookRecorder struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	targets []webhookTarget
+	outputs []Output
+}
+
+// webhookTarget is a webhook channel redirected to the recorder
+type webhookTarget struct {
+	ruleID string
+	url    string
+}
+
+// newWebhookRecorder starts a webhook recorder
+func newWebhookRecorder() *webhookRecorder {
+	r := &webhookRecorder{}
+	r.server = httptest.NewServer(http.HandlerFunc(r.handle))
+	return r
+}
+
+// redirect registers a webhook channel of a rule and returns the recorder URL to use instead
+func (r *webhookRecorder) redirect(ruleID, url string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.targets = append(r.targets, webhookTarget{ruleID: ruleID, url: url})
+	return fmt.Sprintf("%s/%d", r.server.URL, len(r.targets)-1)
+}
+
+// handle records a webhook delivery
+func (r *webhookRecorder) handle(w http.ResponseWriter, req *http.Request) {
+	index, err := strconv.Atoi(strings.TrimPrefix(req.URL.Path, "/"))
+	body, readErr := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil || index < 0 || index >= len(r.targets) || readErr != nil {
+		http.Error(w, "unknown webhook", http.StatusNotFound)
+		return
+	}
+
+	content := string(body)
+	var payload output.WebhookPayload
+	if json.Unmarshal(body, &payload) == nil {
+		if data, err := base64.StdEncoding.DecodeString(payload.Data); err == nil {
+			content = string(data)
+		}
+	}
+
+	target := r.targets[index]
+	r.outputs = append(r.outputs, Output{Channel: ChannelWebhook, RuleID: target.ruleID, Target: target.url, Content: content})
+	w.WriteHeader(http.StatusOK)
+}
+
+// recorded returns the recorded webhook deliveries
+func (r *webhookRecorder) recorded() []Output {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Output(nil), r.outputs...)
+}
+
+// close stops the recorder
+func (r *webhookRecorder) close() {
+	r.server.Close()
+}
@@ -0,0 +1,10 @@
+package store
+
+import "database/sql"
+
+// FindUser returns the name of a user
+func FindUser(db *sql.DB, id string) (string, error) {
+	var name string
+	err := db.QueryRow("SELECT name FROM users WHERE id = '" + id + "'").Scan(&name)
+	return name, err
+}
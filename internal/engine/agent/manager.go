@@ -6,6 +6,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/agent/pool"
 	"github.com/verustcode/verustcode/internal/config"
 	"github.com/verustcode/verustcode/internal/store"
 	"github.com/verustcode/verustcode/pkg/logger"
@@ -40,6 +41,9 @@ func (m *Manager) Initialize() error {
 			continue
 		}
 
+		// Limit concurrency and rate of its calls with the shared agent pool
+		agent = pool.Default().Wrap(agent)
+
 		// Inject store for runtime configuration reading
 		agent.SetStore(m.store)
 
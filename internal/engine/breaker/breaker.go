@@ -0,0 +1,390 @@
+// Package breaker implements per-agent circuit breakers for the review engine.
+// A breaker opens when an agent keeps failing, so that rules can switch to a
+// fallback agent instead of retrying an agent that is down or rate-limited.
+package breaker
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/pkg/logger"
+	"github.com/verustcode/verustcode/pkg/telemetry"
+)
+
+// Default breaker configuration values
+const (
+	DefaultConsecutiveFailures = 5
+	DefaultErrorRate           = 0.5
+	DefaultWindowSize          = 20
+	DefaultMinRequests         = 10
+	DefaultOpenTimeout         = 60 * time.Second
+	DefaultHalfOpenProbes      = 1
+)
+
+// State represents the state of a circuit breaker
+type State string
+
+const (
+	// StateClosed means calls are allowed
+	StateClosed State = "closed"
+	// StateOpen means calls are rejected until the open timeout elapses
+	StateOpen State = "open"
+	// StateHalfOpen means a limited number of probe calls are allowed
+	StateHalfOpen State = "half_open"
+)
+
+// metricValue returns the numeric value reported for the state gauge
+func (s State) metricValue() int64 {
+	switch s {
+	case StateHalfOpen:
+		return 1
+	case StateOpen:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// Settings holds the resolved breaker thresholds
+type Settings struct {
+	Disabled            bool
+	ConsecutiveFailures int
+	ErrorRate           float64
+	WindowSize          int
+	MinRequests         int
+	OpenTimeout         time.Duration
+	HalfOpenProbes      int
+}
+
+// SettingsFromConfig resolves breaker settings, applying defaults for zero values
+func SettingsFromConfig(cfg config.CircuitBreakerConfig) Settings {
+	s := Settings{
+		Disabled:            cfg.Disabled,
+		ConsecutiveFailures: cfg.ConsecutiveFailures,
+		ErrorRate:           cfg.ErrorRate,
+		WindowSize:          cfg.WindowSize,
+		MinRequests:         cfg.MinRequests,
+		OpenTimeout:         time.Duration(cfg.OpenTimeout) * time.Second,
+		HalfOpenProbes:      cfg.HalfOpenProbes,
+	}
+	if s.ConsecutiveFailures <= 0 {
+		s.ConsecutiveFailures = DefaultConsecutiveFailures
+	}
+	if s.ErrorRate <= 0 || s.ErrorRate > 1 {
+		s.ErrorRate = DefaultErrorRate
+	}
+	if s.WindowSize <= 0 {
+		s.WindowSize = DefaultWindowSize
+	}
+	if s.MinRequests <= 0 {
+		s.MinRequests = DefaultMinRequests
+	}
+	if s.MinRequests > s.WindowSize {
+		s.MinRequests = s.WindowSize
+	}
+	if s.OpenTimeout <= 0 {
+		s.OpenTimeout = DefaultOpenTimeout
+	}
+	if s.HalfOpenProbes <= 0 {
+		s.HalfOpenProbes = DefaultHalfOpenProbes
+	}
+	return s
+}
+
+// Status is a snapshot of a breaker, used by the admin API
+type Status struct {
+	Agent               string     `json:"agent"`
+	State               State      `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	ErrorRate           float64    `json:"error_rate"`      // failure ratio of recent calls
+	RecentRequests      int        `json:"recent_requests"` // number of calls in the error rate window
+	TotalRequests       int64      `json:"total_requests"`
+	TotalFailures       int64      `json:"total_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// Breaker is a circuit breaker for a single agent
+type Breaker struct {
+	name     string
+	settings func() Settings
+	now      func() time.Time
+
+	mu                  sync.Mutex
+	state               State
+	consecutiveFailures int
+	window              []bool // recent outcomes, true = failure
+	probesInFlight      int
+	probeSuccesses      int
+	totalRequests       int64
+	totalFailures       int64
+	openedAt            time.Time
+	lastFailureAt       time.Time
+	lastError           string
+}
+
+// Allow reports whether a call to the agent may proceed.
+// An open breaker moves to half-open once the open timeout has elapsed,
+// after which only a limited number of probe calls are allowed.
+func (b *Breaker) Allow() bool {
+	s := b.settings()
+	if s.Disabled {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < s.OpenTimeout {
+			return false
+		}
+		b.transition(StateHalfOpen)
+		fallthrough
+	case StateHalfOpen:
+		if b.probesInFlight >= s.HalfOpenProbes {
+			return false
+		}
+		b.probesInFlight++
+		return true
+	default:
+		return true
+	}
+}
+
+// Record records the outcome of a call allowed by Allow.
+// A nil error counts as success.
+func (b *Breaker) Record(err error) {
+	s := b.settings()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	failed := err != nil
+	b.totalRequests++
+	b.window = append(b.window, failed)
+	if len(b.window) > s.WindowSize {
+		b.window = b.window[len(b.window)-s.WindowSize:]
+	}
+
+	if failed {
+		b.totalFailures++
+		b.consecutiveFailures++
+		b.lastFailureAt = b.now()
+		b.lastError = err.Error()
+	} else {
+		b.consecutiveFailures = 0
+	}
+
+	if s.Disabled {
+		return
+	}
+
+	switch b.state {
+	case StateHalfOpen:
+		if b.probesInFlight > 0 {
+			b.probesInFlight--
+		}
+		if failed {
+			b.open()
+			return
+		}
+		b.probeSuccesses++
+		if b.probeSuccesses >= s.HalfOpenProbes {
+			b.transition(StateClosed)
+		}
+	case StateClosed:
+		if !failed {
+			return
+		}
+		if b.consecutiveFailures >= s.ConsecutiveFailures {
+			b.open()
+			return
+		}
+		if len(b.window) >= s.MinRequests && b.errorRate() >= s.ErrorRate {
+			b.open()
+		}
+	}
+}
+
+// Skip releases a call allowed by Allow without recording an outcome.
+// Used when the call was cancelled and says nothing about the agent's health.
+func (b *Breaker) Skip() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateHalfOpen && b.probesInFlight > 0 {
+		b.probesInFlight--
+	}
+}
+
+// Status returns a snapshot of the breaker
+func (b *Breaker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := Status{
+		Agent:               b.name,
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		ErrorRate:           b.errorRate(),
+		RecentRequests:      len(b.window),
+		TotalRequests:       b.totalRequests,
+		TotalFailures:       b.totalFailures,
+		LastError:           b.lastError,
+	}
+	if b.state != StateClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	if !b.lastFailureAt.IsZero() {
+		lastFailureAt := b.lastFailureAt
+		status.LastFailureAt = &lastFailureAt
+	}
+	return status
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Reset closes the breaker and clears its recent history
+func (b *Breaker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.window = nil
+	b.consecutiveFailures = 0
+	b.transition(StateClosed)
+}
+
+// open moves the breaker to the open state. Caller must hold b.mu.
+func (b *Breaker) open() {
+	b.openedAt = b.now()
+	b.transition(StateOpen)
+}
+
+// transition changes the breaker state and resets per-state counters. Caller must hold b.mu.
+func (b *Breaker) transition(to State) {
+	if b.state == to {
+		return
+	}
+	from := b.state
+	b.state = to
+	b.probesInFlight = 0
+	b.probeSuccesses = 0
+	if to == StateClosed {
+		b.window = nil
+	}
+
+	fields := []zap.Field{
+		zap.String("agent", b.name),
+		zap.String("from", string(from)),
+		zap.String("to", string(to)),
+		zap.Int("consecutive_failures", b.consecutiveFailures),
+	}
+	if to == StateOpen {
+		logger.Warn("Agent circuit breaker opened", append(fields, zap.String("last_error", b.lastError))...)
+	} else {
+		logger.Info("Agent circuit breaker state changed", fields...)
+	}
+
+	telemetry.GetMetrics().RecordCircuitState(context.Background(), b.name, string(to), to.metricValue())
+}
+
+// errorRate returns the failure ratio of the recent window. Caller must hold b.mu.
+func (b *Breaker) errorRate() float64 {
+	if len(b.window) == 0 {
+		return 0
+	}
+	failures := 0
+	for _, failed := range b.window {
+		if failed {
+			failures++
+		}
+	}
+	return float64(failures) / float64(len(b.window))
+}
+
+// Registry holds one breaker per agent name
+type Registry struct {
+	mu       sync.RWMutex
+	breakers map[string]*Breaker
+	settings Settings
+	now      func() time.Time
+}
+
+// NewRegistry creates a new breaker registry with default settings
+func NewRegistry() *Registry {
+	return &Registry{
+		breakers: make(map[string]*Breaker),
+		settings: SettingsFromConfig(config.CircuitBreakerConfig{}),
+		now:      time.Now,
+	}
+}
+
+// Configure updates the settings used by all breakers
+func (r *Registry) Configure(cfg config.CircuitBreakerConfig) {
+	s := SettingsFromConfig(cfg)
+	r.mu.Lock()
+	r.settings = s
+	r.mu.Unlock()
+}
+
+// Get returns the breaker for an agent, creating it if necessary
+func (r *Registry) Get(agent string) *Breaker {
+	r.mu.RLock()
+	b, ok := r.breakers[agent]
+	r.mu.RUnlock()
+	if ok {
+		return b
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b, ok := r.breakers[agent]; ok {
+		return b
+	}
+	b = &Breaker{
+		name:     agent,
+		settings: r.currentSettings,
+		now:      r.now,
+		state:    StateClosed,
+	}
+	r.breakers[agent] = b
+	return b
+}
+
+// Statuses returns snapshots of all breakers, sorted by agent name
+func (r *Registry) Statuses() []Status {
+	r.mu.RLock()
+	breakers := make([]*Breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	r.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(breakers))
+	for _, b := range breakers {
+		statuses = append(statuses, b.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Agent < statuses[j].Agent
+	})
+	return statuses
+}
+
+// currentSettings returns the current registry settings
+func (r *Registry) currentSettings() Settings {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.settings
+}
@@ -0,0 +1,135 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/verustcode/verustcode/internal/config"
+)
+
+// newTestRegistry creates a registry with a controllable clock
+func newTestRegistry(cfg config.CircuitBreakerConfig) (*Registry, *time.Time) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	r := NewRegistry()
+	r.now = func() time.Time { return now }
+	r.Configure(cfg)
+	return r, &now
+}
+
+var errAgent = errors.New("agent failed")
+
+func TestSettingsFromConfig_Defaults(t *testing.T) {
+	s := SettingsFromConfig(config.CircuitBreakerConfig{})
+	assert.Equal(t, DefaultConsecutiveFailures, s.ConsecutiveFailures)
+	assert.Equal(t, DefaultErrorRate, s.ErrorRate)
+	assert.Equal(t, DefaultWindowSize, s.WindowSize)
+	assert.Equal(t, DefaultMinRequests, s.MinRequests)
+	assert.Equal(t, DefaultOpenTimeout, s.OpenTimeout)
+	assert.Equal(t, DefaultHalfOpenProbes, s.HalfOpenProbes)
+
+	s = SettingsFromConfig(config.CircuitBreakerConfig{WindowSize: 5, MinRequests: 10, ErrorRate: 2})
+	assert.Equal(t, 5, s.MinRequests, "min requests is capped at window size")
+	assert.Equal(t, DefaultErrorRate, s.ErrorRate, "invalid error rate falls back to default")
+}
+
+func TestBreaker_ConsecutiveFailures(t *testing.T) {
+	r, _ := newTestRegistry(config.CircuitBreakerConfig{ConsecutiveFailures: 3, MinRequests: 100, WindowSize: 100})
+	b := r.Get("cursor")
+
+	for i := 0; i < 2; i++ {
+		require.True(t, b.Allow())
+		b.Record(errAgent)
+	}
+	assert.Equal(t, StateClosed, b.State())
+
+	// A success resets the consecutive failure count
+	require.True(t, b.Allow())
+	b.Record(nil)
+	for i := 0; i < 2; i++ {
+		b.Record(errAgent)
+	}
+	assert.Equal(t, StateClosed, b.State())
+
+	b.Record(errAgent)
+	assert.Equal(t, StateOpen, b.State())
+	assert.False(t, b.Allow())
+}
+
+func TestBreaker_ErrorRate(t *testing.T) {
+	r, _ := newTestRegistry(config.CircuitBreakerConfig{ConsecutiveFailures: 100, ErrorRate: 0.5, WindowSize: 4, MinRequests: 4})
+	b := r.Get("gemini")
+
+	b.Record(nil)
+	b.Record(errAgent)
+	b.Record(nil)
+	assert.Equal(t, StateClosed, b.State(), "below min requests")
+
+	b.Record(errAgent)
+	assert.Equal(t, StateOpen, b.State())
+
+	status := b.Status()
+	assert.Equal(t, 0.5, status.ErrorRate)
+	assert.Equal(t, int64(4), status.TotalRequests)
+	assert.Equal(t, int64(2), status.TotalFailures)
+	assert.NotNil(t, status.OpenedAt)
+	assert.Equal(t, "agent failed", status.LastError)
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	r, now := newTestRegistry(config.CircuitBreakerConfig{ConsecutiveFailures: 1, OpenTimeout: 30, HalfOpenProbes: 1})
+	b := r.Get("qoder")
+
+	b.Record(errAgent)
+	require.Equal(t, StateOpen, b.State())
+
+	*now = now.Add(10 * time.Second)
+	assert.False(t, b.Allow(), "still within open timeout")
+
+	*now = now.Add(30 * time.Second)
+	assert.True(t, b.Allow(), "probe allowed after open timeout")
+	assert.Equal(t, StateHalfOpen, b.State())
+	assert.False(t, b.Allow(), "only one probe in flight")
+
+	// Failed probe re-opens the breaker
+	b.Record(errAgent)
+	assert.Equal(t, StateOpen, b.State())
+
+	*now = now.Add(time.Minute)
+	require.True(t, b.Allow())
+	// Cancelled probe releases its slot without closing the breaker
+	b.Skip()
+	assert.Equal(t, StateHalfOpen, b.State())
+	require.True(t, b.Allow())
+
+	// Successful probe closes the breaker
+	b.Record(nil)
+	assert.Equal(t, StateClosed, b.State())
+	assert.True(t, b.Allow())
+}
+
+func TestBreaker_Disabled(t *testing.T) {
+	r, _ := newTestRegistry(config.CircuitBreakerConfig{Disabled: true, ConsecutiveFailures: 1})
+	b := r.Get("cursor")
+
+	b.Record(errAgent)
+	b.Record(errAgent)
+	assert.Equal(t, StateClosed, b.State())
+	assert.True(t, b.Allow())
+	assert.Equal(t, int64(2), b.Status().TotalFailures)
+}
+
+func TestRegistry_Statuses(t *testing.T) {
+	r := NewRegistry()
+	assert.Same(t, r.Get("gemini"), r.Get("gemini"))
+	r.Get("cursor")
+
+	statuses := r.Statuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "cursor", statuses[0].Agent)
+	assert.Equal(t, "gemini", statuses[1].Agent)
+	assert.Equal(t, StateClosed, statuses[0].State)
+}
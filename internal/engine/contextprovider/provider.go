@@ -0,0 +1,352 @@
+// Package contextprovider collects the outputs of rule context providers
+// (file contents, command output, diff and git log) that are added to review prompts.
+package contextprovider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/llm"
+	"github.com/verustcode/verustcode/internal/prompt"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// charsPerToken is the approximate number of characters per token used to apply token budgets
+const charsPerToken = 4
+
+// truncationNote is appended to outputs cut to their token budget
+const truncationNote = "\n... (truncated)"
+
+// Env describes the workspace the providers run in
+type Env struct {
+	// RepoPath is the local repository path
+	RepoPath string
+
+	// BaseCommit and HeadCommit delimit the change set (BaseCommit is empty for full audits)
+	BaseCommit string
+	HeadCommit string
+
+	// ChangedFiles lists the files in scope of the rule
+	ChangedFiles []string
+
+	// AllowedCommands lists the commands command providers may run (review context_commands setting)
+	AllowedCommands []string
+
+	// Sandbox isolates command providers (review context_sandbox setting, nil runs them unsandboxed)
+	Sandbox *llm.SandboxConfig
+}
+
+// Collect runs the providers and returns their outputs, each truncated to the provider's
+// token budget and all together to dsl.DefaultContextTotalTokens.
+// Providers that fail are logged and skipped, so a broken provider never fails the review.
+func Collect(ctx context.Context, providers []dsl.ContextProviderConfig, env *Env) []prompt.ContextItem {
+	remaining := dsl.DefaultContextTotalTokens
+	var items []prompt.ContextItem
+
+	for i := range providers {
+		p := &providers[i]
+		if remaining <= 0 {
+			logger.Warn("Context token budget exhausted, skipping provider",
+				zap.String("type", p.Type),
+				zap.String("title", p.Title()),
+			)
+			break
+		}
+
+		budget := p.MaxTokens
+		if budget == 0 {
+			budget = dsl.DefaultContextMaxTokens
+		}
+		if budget > remaining {
+			budget = remaining
+		}
+
+		content, err := run(ctx, p, env, budget*charsPerToken)
+		if err != nil {
+			logger.Warn("Context provider failed",
+				zap.String("type", p.Type),
+				zap.String("title", p.Title()),
+				zap.Error(err),
+			)
+			continue
+		}
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+
+		item := prompt.ContextItem{Title: p.Title(), Type: p.Type}
+		item.Content, item.Truncated = Truncate(content, budget)
+		remaining -= EstimateTokens(item.Content)
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// run runs a single provider. maxBytes bounds the output read from commands and files.
+func run(ctx context.Context, p *dsl.ContextProviderConfig, env *Env, maxBytes int) (string, error) {
+	switch p.Type {
+	case dsl.ContextTypeFile:
+		return collectFiles(p, env, maxBytes)
+	case dsl.ContextTypeCommand:
+		return runCommand(ctx, p, env, maxBytes)
+	case dsl.ContextTypeDiff:
+		return collectDiff(ctx, p, env, maxBytes)
+	case dsl.ContextTypeGitLog:
+		return collectGitLog(ctx, p, env, maxBytes)
+	}
+	return "", fmt.Errorf("unknown context provider type %q", p.Type)
+}
+
+// EstimateTokens returns the approximate number of tokens of s
+func EstimateTokens(s string) int {
+	return (len(s) + charsPerToken - 1) / charsPerToken
+}
+
+// Truncate cuts s to approximately maxTokens tokens, preferring a line boundary.
+// Returns the truncated text and whether it was cut.
+func Truncate(s string, maxTokens int) (string, bool) {
+	maxBytes := maxTokens * charsPerToken
+	if len(s) <= maxBytes {
+		return s, false
+	}
+
+	cut := maxBytes - len(truncationNote)
+	if cut < 0 {
+		cut = 0
+	}
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	if nl := strings.LastIndexByte(s[:cut], '\n'); nl > cut/2 {
+		cut = nl
+	}
+	return s[:cut] + truncationNote, true
+}
+
+// collectFiles returns the contents of the files matching the provider's globs
+func collectFiles(p *dsl.ContextProviderConfig, env *Env, maxBytes int) (string, error) {
+	maxFiles := p.MaxFiles
+	if maxFiles == 0 {
+		maxFiles = dsl.DefaultContextMaxFiles
+	}
+	maxFileSize := p.MaxFileSize
+	if maxFileSize == 0 {
+		maxFileSize = dsl.DefaultContextMaxFileSize
+	}
+
+	var matches []string
+	err := filepath.WalkDir(env.RepoPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip unreadable entries
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil // never follow symlinks out of the workspace
+		}
+		rel, err := filepath.Rel(env.RepoPath, path)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		for _, pattern := range p.Paths {
+			if dsl.MatchGlob(pattern, rel) {
+				matches = append(matches, rel)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(matches)
+
+	var sb strings.Builder
+	for i, rel := range matches {
+		if i >= maxFiles {
+			fmt.Fprintf(&sb, "(%d more files not shown)\n", len(matches)-maxFiles)
+			break
+		}
+		if sb.Len() >= maxBytes {
+			break
+		}
+		data, truncated, err := readFileHead(filepath.Join(env.RepoPath, filepath.FromSlash(rel)), maxFileSize)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&sb, "==> %s <==\n%s", rel, data)
+		if truncated {
+			fmt.Fprintf(&sb, "\n... (file truncated at %d bytes)", maxFileSize)
+		}
+		sb.WriteString("\n\n")
+	}
+	return sb.String(), nil
+}
+
+// readFileHead reads up to maxBytes bytes of a file
+func readFileHead(path string, maxBytes int) (string, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, int64(maxBytes)+1))
+	if err != nil {
+		return "", false, err
+	}
+	if len(data) > maxBytes {
+		return string(data[:maxBytes]), true, nil
+	}
+	return string(data), false, nil
+}
+
+// IsCommandAllowed reports whether command is allowed by one of the allowed commands.
+// A command is allowed if its arguments equal all arguments of an allowed command, so that
+// review configs can't add arguments (e.g. "go vet -vettool=./tool") to an allowed command.
+func IsCommandAllowed(command string, allowed []string) bool {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return false
+	}
+	for _, entry := range allowed {
+		if slices.Equal(strings.Fields(entry), args) {
+			return true
+		}
+	}
+	return false
+}
+
+// runCommand runs a whitelisted command in the workspace, in the sandbox of env, and returns its
+// combined output. A non-zero exit status is not an error: linters report findings that way.
+func runCommand(ctx context.Context, p *dsl.ContextProviderConfig, env *Env, maxBytes int) (string, error) {
+	if !IsCommandAllowed(p.Command, env.AllowedCommands) {
+		return "", fmt.Errorf("command %q is not allowed by the context_commands review setting", p.Command)
+	}
+
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = dsl.DefaultContextTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	args := strings.Fields(p.Command)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = env.RepoPath
+
+	out := &limitedBuffer{limit: maxBytes + len(truncationNote)}
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	// The command runs on code of the reviewed change
+	releaseSandbox, err := llm.SandboxCommand(cmd, env.Sandbox, logger.Get(), zap.String("command", p.Command))
+	if err != nil {
+		return "", err
+	}
+	err = cmd.Run()
+	releaseSandbox(err)
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("command timed out after %d seconds", timeout)
+	}
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return out.String() + fmt.Sprintf("\n(exit status %d)", exitErr.ExitCode()), nil
+		}
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// collectDiff returns the unified diff of the change set, limited to the rule's files
+func collectDiff(ctx context.Context, p *dsl.ContextProviderConfig, env *Env, maxBytes int) (string, error) {
+	if env.BaseCommit == "" || env.HeadCommit == "" {
+		return "", fmt.Errorf("diff requires a commit range")
+	}
+
+	contextLines := dsl.DefaultContextLines
+	if p.ContextLines != nil {
+		contextLines = *p.ContextLines
+	}
+
+	args := []string{"diff", "--no-color", "-U" + strconv.Itoa(contextLines),
+		env.BaseCommit + ".." + env.HeadCommit, "--"}
+	args = append(args, env.ChangedFiles...)
+	return runGit(ctx, env.RepoPath, maxBytes, args...)
+}
+
+// collectGitLog returns the recent history of the rule's files
+func collectGitLog(ctx context.Context, p *dsl.ContextProviderConfig, env *Env, maxBytes int) (string, error) {
+	maxCommits := p.MaxCommits
+	if maxCommits == 0 {
+		maxCommits = dsl.DefaultContextMaxCommits
+	}
+	head := env.HeadCommit
+	if head == "" {
+		head = "HEAD"
+	}
+
+	args := []string{"log", "--no-color", "-n", strconv.Itoa(maxCommits),
+		"--date=short", "--format=%h %ad %an: %s", head, "--"}
+	args = append(args, env.ChangedFiles...)
+	return runGit(ctx, env.RepoPath, maxBytes, args...)
+}
+
+// runGit runs a git command in the repository and returns its output
+func runGit(ctx context.Context, repoPath string, maxBytes int, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", repoPath}, args...)...)
+
+	out := &limitedBuffer{limit: maxBytes + len(truncationNote)}
+	var stderr bytes.Buffer
+	cmd.Stdout = out
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out.String(), nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the rest
+type limitedBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+// Write implements io.Writer
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room > 0 {
+		if len(p) > room {
+			b.buf.Write(p[:room])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// String returns the kept output
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,168 @@
+package contextprovider
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/llm"
+)
+
+// setupTestRepo creates a git repository with two commits and returns its path, base and head commits
+func setupTestRepo(t *testing.T) (string, string, string) {
+	repoPath := t.TempDir()
+	git := func(args ...string) string {
+		out, err := exec.Command("git", append([]string{"-C", repoPath}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+
+	git("init")
+	git("config", "user.name", "Test User")
+	git("config", "user.email", "test@example.com")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(repoPath, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "main.go"), []byte("package main\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "docs", "style.md"), []byte("# Style\nUse gofmt.\n"), 0644))
+	git("add", ".")
+	git("commit", "-m", "Initial commit")
+	base := git("rev-parse", "HEAD")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644))
+	git("commit", "-am", "Add main function")
+	head := git("rev-parse", "HEAD")
+
+	return repoPath, base, head
+}
+
+// TestCollect tests collecting file, diff and git log context
+func TestCollect(t *testing.T) {
+	repoPath, base, head := setupTestRepo(t)
+	env := &Env{RepoPath: repoPath, BaseCommit: base, HeadCommit: head, ChangedFiles: []string{"main.go"}}
+
+	items := Collect(context.Background(), []dsl.ContextProviderConfig{
+		{Type: dsl.ContextTypeFile, Paths: []string{"docs/*.md"}},
+		{Type: dsl.ContextTypeDiff},
+		{Type: dsl.ContextTypeGitLog, Name: "History"},
+	}, env)
+
+	require.Len(t, items, 3)
+	assert.Contains(t, items[0].Content, "==> docs/style.md <==")
+	assert.Contains(t, items[0].Content, "Use gofmt.")
+	assert.Contains(t, items[1].Content, "+func main() {}")
+	assert.Equal(t, "History", items[2].Title)
+	assert.Contains(t, items[2].Content, "Add main function")
+	assert.Contains(t, items[2].Content, "Initial commit")
+}
+
+// TestCollect_Command tests running whitelisted commands
+func TestCollect_Command(t *testing.T) {
+	repoPath, _, _ := setupTestRepo(t)
+	env := &Env{RepoPath: repoPath, AllowedCommands: []string{"git status --bogus-flag", "git ls-files"}}
+
+	items := Collect(context.Background(), []dsl.ContextProviderConfig{
+		{Type: dsl.ContextTypeCommand, Command: "git ls-files"},
+		{Type: dsl.ContextTypeCommand, Command: "git rm -r ."},
+		{Type: dsl.ContextTypeCommand, Command: "git ls-files --others"},
+		{Type: dsl.ContextTypeCommand, Command: "git status --bogus-flag"},
+	}, env)
+
+	require.Len(t, items, 2, "command not in context_commands must be skipped")
+	assert.Equal(t, "git ls-files", items[0].Title)
+	assert.Contains(t, items[0].Content, "main.go")
+	assert.Contains(t, items[1].Content, "exit status", "non-zero exit status is reported, not an error")
+
+	_, err := os.Stat(filepath.Join(repoPath, "main.go"))
+	assert.NoError(t, err)
+}
+
+// TestCollect_CommandSandbox tests that commands run in the configured sandbox
+func TestCollect_CommandSandbox(t *testing.T) {
+	repoPath, _, _ := setupTestRepo(t)
+	env := &Env{
+		RepoPath:        repoPath,
+		AllowedCommands: []string{"git ls-files"},
+		Sandbox:         &llm.SandboxConfig{Enabled: true, BwrapPath: filepath.Join(t.TempDir(), "no-bwrap")},
+	}
+
+	items := Collect(context.Background(), []dsl.ContextProviderConfig{
+		{Type: dsl.ContextTypeCommand, Command: "git ls-files"},
+	}, env)
+	assert.Empty(t, items, "command must not run outside the sandbox when bubblewrap is missing")
+}
+
+// TestCollect_DefaultSandbox tests that an allowed Go command runs in the default sandbox
+func TestCollect_DefaultSandbox(t *testing.T) {
+	if _, err := exec.LookPath("bwrap"); err != nil {
+		t.Skip("bubblewrap is not installed")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+	repoPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "go.mod"), []byte("module example.com/sandboxed\n\ngo 1.21\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "main.go"), []byte("package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Printf(\"%d\\n\", \"vet\")\n}\n"), 0644))
+
+	env := &Env{
+		RepoPath:        repoPath,
+		AllowedCommands: []string{"go vet ./..."},
+		Sandbox:         config.DefaultContextSandbox(),
+	}
+	items := Collect(context.Background(), []dsl.ContextProviderConfig{
+		{Type: dsl.ContextTypeCommand, Command: "go vet ./..."},
+	}, env)
+
+	// go vet builds offline in the read-only workspace and reports the finding
+	require.Len(t, items, 1)
+	assert.Contains(t, items[0].Content, "Printf format %d has arg")
+}
+
+// TestCollect_TokenBudget tests that outputs are truncated to the provider token budget
+func TestCollect_TokenBudget(t *testing.T) {
+	repoPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "big.txt"), []byte(strings.Repeat("line of text\n", 1000)), 0644))
+
+	items := Collect(context.Background(), []dsl.ContextProviderConfig{
+		{Type: dsl.ContextTypeFile, Paths: []string{"big.txt"}, MaxTokens: 50},
+		{Type: dsl.ContextTypeDiff}, // no commit range: skipped
+	}, &Env{RepoPath: repoPath})
+
+	require.Len(t, items, 1)
+	assert.True(t, items[0].Truncated)
+	assert.LessOrEqual(t, len(items[0].Content), 50*charsPerToken)
+	assert.True(t, strings.HasSuffix(items[0].Content, truncationNote))
+}
+
+// TestIsCommandAllowed tests command whitelist matching
+func TestIsCommandAllowed(t *testing.T) {
+	allowed := []string{"go vet ./...", "golangci-lint run --out-format json"}
+
+	assert.True(t, IsCommandAllowed("go vet ./...", allowed))
+	assert.True(t, IsCommandAllowed("golangci-lint  run --out-format json", allowed))
+	assert.False(t, IsCommandAllowed("go vet -vettool=./evil ./...", allowed), "extra arguments must not be allowed")
+	assert.False(t, IsCommandAllowed("golangci-lint run", allowed))
+	assert.False(t, IsCommandAllowed("go test ./...", allowed))
+	assert.False(t, IsCommandAllowed("go", allowed))
+	assert.False(t, IsCommandAllowed("go vet ./...", nil))
+}
+
+// TestTruncate tests truncating text to a token budget
+func TestTruncate(t *testing.T) {
+	text, truncated := Truncate("short", 10)
+	assert.Equal(t, "short", text)
+	assert.False(t, truncated)
+
+	text, truncated = Truncate(strings.Repeat("é", 100), 10)
+	assert.True(t, truncated)
+	assert.True(t, strings.HasSuffix(text, truncationNote))
+	assert.True(t, strings.HasPrefix(text, "é"))
+	assert.NotContains(t, text, "�")
+}
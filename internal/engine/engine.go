@@ -5,6 +5,7 @@ package engine
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -16,14 +17,17 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/agent/pool"
 	"github.com/verustcode/verustcode/internal/config"
 	"github.com/verustcode/verustcode/internal/dsl"
 	"github.com/verustcode/verustcode/internal/engine/agent"
+	"github.com/verustcode/verustcode/internal/engine/breaker"
 	"github.com/verustcode/verustcode/internal/engine/executor"
 	providermgr "github.com/verustcode/verustcode/internal/engine/provider"
 	"github.com/verustcode/verustcode/internal/engine/recovery"
 	"github.com/verustcode/verustcode/internal/engine/retry"
 	"github.com/verustcode/verustcode/internal/engine/runner"
+	"github.com/verustcode/verustcode/internal/engine/scheduler"
 	"github.com/verustcode/verustcode/internal/engine/task"
 	"github.com/verustcode/verustcode/internal/engine/utils"
 	"github.com/verustcode/verustcode/internal/git/provider"
@@ -48,12 +52,15 @@ type (
 
 	// PRInfo contains additional PR information from webhook
 	PRInfo = task.PRInfo
+
+	// Priority represents the dispatch priority of a task
+	Priority = task.Priority
 )
 
 // Engine manages code review tasks and orchestrates all sub-modules.
 // It serves as the main orchestration layer, delegating specific responsibilities
 // to specialized modules: ProviderManager, AgentManager, RecoveryService,
-// ReviewRunner, and RetryHandler.
+// ReviewRunner, RetryHandler, and Scheduler.
 type Engine struct {
 	cfg            *config.Config
 	store          store.Store
@@ -65,6 +72,7 @@ type Engine struct {
 	recovery     *recovery.Service
 	runner       *runner.Runner
 	retryHandler *retry.Handler
+	scheduler    *scheduler.Service
 
 	// DSL components
 	dslLoader     *dsl.Loader
@@ -146,6 +154,9 @@ func NewEngine(cfg *config.Config, s store.Store) (*Engine, error) {
 		ctx,
 	)
 
+	// Initialize scheduler for scheduled audits (submits reviews back into the engine)
+	e.scheduler = scheduler.NewService(s, e)
+
 	return e, nil
 }
 
@@ -170,8 +181,16 @@ func (e *Engine) runReviewWithTracking(ctx context.Context, req *ReviewRequest,
 		PRNumber:          req.PRNumber,
 		PRTitle:           req.PRTitle,
 		PRDescription:     req.PRDescription,
+		PushCommits:       req.PushCommits,
 		BaseCommitSHA:     req.BaseCommitSHA,
+		Source:            req.Source,
+		TargetBranch:      req.TargetBranch,
+		Author:            req.Author,
+		Labels:            req.Labels,
+		LinesChanged:      req.LinesChanged,
+		FilesChanged:      req.FilesChanged,
 		ChangedFiles:      req.ChangedFiles,
+		Commits:           req.Commits,
 		ReviewRulesConfig: req.ReviewRulesConfig,
 		OutputDir:         req.OutputDir,
 	}
@@ -235,32 +254,46 @@ func (e *Engine) loadDSLConfigForRepo(repoURL string) (*dsl.ReviewRulesConfig, e
 	return e.dslLoader.LoadDefaultReviewConfig()
 }
 
+// loadReviewFileOverride loads the review file pinned on a review (e.g. by a schedule).
+// Returns nil, nil if the review has no review file override.
+// Unlike the repository config, a missing override file is an error: running
+// different rules than the ones explicitly requested would be misleading.
+func (e *Engine) loadReviewFileOverride(review *model.Review) (*dsl.ReviewRulesConfig, error) {
+	if review == nil || review.ReviewFile == "" {
+		return nil, nil
+	}
+
+	logger.Info("Using review file override",
+		zap.String("review_id", review.ID),
+		zap.String("review_file", review.ReviewFile),
+	)
+	return e.dslLoader.Load(filepath.Join(config.ReviewsDir, review.ReviewFile))
+}
+
 // loadDSLConfigWithPriority loads review configuration with priority order:
-// 1. .verust-review.yaml at repository root (highest priority)
+// 1. .verust-review.yaml in the repository, as allowed by the repository's in-repo config policy
 // 2. Database configured review file for this repository
 // 3. config/reviews/default.yaml (fallback)
+// baseCommit is the base of the reviewed change, from which the base_branch policy reads .verust-review.yaml.
 // This method should be called after repository is cloned.
-func (e *Engine) loadDSLConfigWithPriority(repoPath, repoURL string) (*dsl.ReviewRulesConfig, error) {
-	// Priority 1: Check for .verust-review.yaml at repository root
-	if repoPath != "" {
-		rootConfig, err := e.dslLoader.LoadFromRepoRoot(repoPath)
-		if err != nil {
-			logger.Warn("Failed to load review config from repository root",
-				zap.String("repo_path", repoPath),
-				zap.Error(err),
-			)
-			// Continue to next priority
-		} else if rootConfig != nil {
-			logger.Info("Using review config from repository root (.verust-review.yaml)",
-				zap.String("repo_path", repoPath),
-				zap.Int("rules", len(rootConfig.Rules)),
-			)
-			return rootConfig, nil
-		}
+func (e *Engine) loadDSLConfigWithPriority(repoPath, repoURL, baseCommit string) (*utils.EffectiveReviewConfig, error) {
+	// Priority 2 & 3: Database configured review file > default.yaml
+	serverConfig, err := e.loadDSLConfigForRepo(repoURL)
+	if err != nil {
+		return nil, err
 	}
 
-	// Priority 2 & 3: Database configured review file > default.yaml
-	return e.loadDSLConfigForRepo(repoURL)
+	// Priority 1: .verust-review.yaml, subject to the trust policy
+	return utils.ApplyInRepoConfigPolicy(e.dslLoader, e.inRepoConfigPolicy(repoURL), serverConfig, repoPath, baseCommit), nil
+}
+
+// inRepoConfigPolicy returns how .verust-review.yaml is trusted for a repository
+func (e *Engine) inRepoConfigPolicy(repoURL string) model.InRepoConfigPolicy {
+	repoConfig, err := e.store.RepositoryConfig().GetByRepoURL(repoURL)
+	if err != nil {
+		repoConfig = nil
+	}
+	return utils.InRepoConfigPolicyOf(repoConfig)
 }
 
 // Start starts the engine workers (for server mode).
@@ -273,6 +306,10 @@ func (e *Engine) Start() {
 		QueueSize:  e.workers * 10,
 	}
 	e.dispatcher = NewDispatcher(e.ctx, e.repoQueue, dispatcherConfig, e.processTask)
+	e.repoQueue.SetWorkers(e.workers)
+
+	// Restore paused repositories and maintenance mode before any task is dispatched
+	e.restoreQueueState()
 
 	// Start the dispatcher (starts workers)
 	e.dispatcher.Start()
@@ -281,6 +318,16 @@ func (e *Engine) Start() {
 	// This must be done after dispatcher starts to ensure tasks are processed
 	e.recovery.RecoverToQueue(e.ctx)
 
+	// Load organization-wide custom review areas
+	if err := e.ReloadCustomAreas(); err != nil {
+		logger.Error("Failed to load custom review areas", zap.Error(err))
+	}
+
+	// Start scheduled audits
+	if err := e.scheduler.Start(); err != nil {
+		logger.Error("Failed to start scheduler", zap.Error(err))
+	}
+
 	logger.Info("Review engine started",
 		zap.Int("workers", e.workers),
 	)
@@ -291,6 +338,11 @@ func (e *Engine) Stop() {
 
 	logger.Info("Stopping review engine")
 
+	// Stop scheduling new audits first
+	if e.scheduler != nil {
+		e.scheduler.Stop()
+	}
+
 	// Stop the dispatcher (this stops workers and waits for completion)
 	if e.dispatcher != nil {
 		e.dispatcher.Stop()
@@ -336,12 +388,18 @@ func (e *Engine) Submit(review *model.Review, prInfo *PRInfo, outputDir ...strin
 		return nil, errors.Wrap(errors.ErrCodeValidation, "failed to parse repository URL", err)
 	}
 
-	// Load DSL config based on repository configuration
+	// Load DSL config based on review file override or repository configuration
 	// Note: This is a preliminary load. The actual config will be reloaded in processTask
 	// after cloning, where we can check for .verust-review.yaml in the repo root.
-	dslConfig, err := e.loadDSLConfigForRepo(review.RepoURL)
+	dslConfig, err := e.loadReviewFileOverride(review)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(errors.ErrCodeConfigInvalid, "failed to load review file", err)
+	}
+	if dslConfig == nil {
+		dslConfig, err = e.loadDSLConfigForRepo(review.RepoURL)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Update review source info
@@ -351,6 +409,9 @@ func (e *Engine) Submit(review *model.Review, prInfo *PRInfo, outputDir ...strin
 	if review.Source == "" {
 		updates["source"] = "webhook"
 	}
+	if prInfo != nil && prInfo.BaseSHA != "" {
+		updates["base_commit_sha"] = prInfo.BaseSHA
+	}
 	// Build PR URL if PR number is provided but PRURL is not set
 	if review.PRNumber > 0 && review.PRURL == "" {
 		prURL := utils.BuildPRURL(review.RepoURL, providerName, review.PRNumber)
@@ -426,6 +487,7 @@ func (e *Engine) Submit(review *model.Review, prInfo *PRInfo, outputDir ...strin
 		task.Request.PRTitle = prInfo.Title
 		task.Request.PRBody = prInfo.Description
 		task.BaseCommitSHA = prInfo.BaseSHA
+		task.PushCommits = prInfo.PushCommits
 		if prInfo.ChangedFiles != nil {
 			task.Request.ChangedFiles = prInfo.ChangedFiles
 		}
@@ -610,10 +672,14 @@ func (e *Engine) processTask(task *Task) {
 		defer utils.CleanupWorkspace(workDir)
 
 		// Clone repository branch
+		// A diff range (push since previous push, incremental scheduled audit) needs history, so use a full clone
 		cloneOpts := &provider.CloneOptions{
 			Branch: task.Request.Ref,
 			Depth:  1,
 		}
+		if task.BaseCommitSHA != "" {
+			cloneOpts.Depth = 0
+		}
 		repoPath = filepath.Join(workDir, "repo")
 		if err := prov.Clone(ctx, task.Request.Owner, task.Request.RepoName, repoPath, cloneOpts); err != nil {
 			telemetry.SetSpanError(span, err)
@@ -621,6 +687,28 @@ func (e *Engine) processTask(task *Task) {
 			e.handleError(task, errors.Wrap(errors.ErrCodeGitClone, "failed to clone repository", err))
 			return
 		}
+
+		// Branch reviews (e.g. scheduled audits) don't know the head commit until cloned.
+		// Push reviews know it, but the branch may have moved on since the push.
+		if task.Request.CommitSHA != "" {
+			if headSHA := utils.GetHeadCommit(ctx, repoPath); headSHA != task.Request.CommitSHA {
+				if err := utils.CheckoutCommit(ctx, repoPath, task.Request.CommitSHA); err != nil {
+					logger.Warn("Failed to check out pushed commit, reviewing branch head",
+						zap.String("review_id", task.Review.ID),
+						zap.String("commit_sha", task.Request.CommitSHA),
+						zap.Error(err),
+					)
+				}
+			}
+		} else {
+			if headSHA := utils.GetHeadCommit(ctx, repoPath); headSHA != "" {
+				task.Request.CommitSHA = headSHA
+				task.Review.CommitSHA = headSHA
+				if err := e.store.Review().UpdateMetadata(task.Review.ID, map[string]interface{}{"commit_sha": headSHA}); err != nil {
+					logger.Warn("Failed to update review commit SHA", zap.Error(err))
+				}
+			}
+		}
 	}
 
 	// Update review with repo path
@@ -628,41 +716,22 @@ func (e *Engine) processTask(task *Task) {
 		logger.Warn("Failed to update review with repo path", zap.Error(err))
 	}
 
-	// Load review configuration with priority:
-	// 1. .verust-review.yaml at repository root (highest priority)
-	// 2. Database configured review file for this repository
-	// 3. config/reviews/default.yaml (fallback)
-	dslConfig, err := e.loadDSLConfigWithPriority(repoPath, task.Review.RepoURL)
-	if err != nil {
-		logger.Error("Failed to load review configuration",
-			zap.String("review_id", task.Review.ID),
-			zap.String("repo_path", repoPath),
-			zap.Error(err),
-		)
-		metrics.RecordReviewCompleted(ctx, "failed", time.Since(startTime).Seconds())
-		e.handleError(task, errors.Wrap(errors.ErrCodeConfigInvalid, "failed to load review configuration", err))
-		return
-	}
-	task.ReviewRulesConfig = dslConfig
-	logger.Info("Loaded review configuration",
-		zap.String("review_id", task.Review.ID),
-		zap.Int("rules", len(dslConfig.Rules)),
-	)
-
 	// Build review request with complete PR information
 	// Use PR information from webhook payload if available, otherwise from Task.Request
 	req := &ReviewRequest{
-		RepoPath:          repoPath,
-		RepoURL:           task.Request.RepoURL,
-		Ref:               task.Request.Ref,
-		CommitSHA:         task.Request.CommitSHA,
-		PRNumber:          task.Request.PRNumber,
-		PRTitle:           task.Request.PRTitle,
-		PRDescription:     task.Request.PRBody,
-		BaseCommitSHA:     task.BaseCommitSHA,
-		ChangedFiles:      task.Request.ChangedFiles,
-		ReviewRulesConfig: task.ReviewRulesConfig,
-		OutputDir:         task.OutputDir,
+		RepoPath:      repoPath,
+		RepoURL:       task.Request.RepoURL,
+		Ref:           task.Request.Ref,
+		CommitSHA:     task.Request.CommitSHA,
+		PRNumber:      task.Request.PRNumber,
+		PRTitle:       task.Request.PRTitle,
+		PRDescription: task.Request.PRBody,
+		PushCommits:   task.PushCommits,
+		BaseCommitSHA: task.BaseCommitSHA,
+		Source:        task.Review.Source,
+		TargetBranch:  task.Request.Ref,
+		ChangedFiles:  task.Request.ChangedFiles,
+		OutputDir:     task.OutputDir,
 	}
 
 	// Track PR author for later update
@@ -687,6 +756,7 @@ func (e *Engine) processTask(task *Task) {
 		} else {
 			req.BaseCommitSHA = pr.BaseSHA
 			prAuthor = pr.Author
+			applyPRConditions(req, pr)
 			// Also update PR title and description if not already set
 			if req.PRTitle == "" {
 				req.PRTitle = pr.Title
@@ -710,6 +780,7 @@ func (e *Engine) processTask(task *Task) {
 			)
 		} else {
 			prAuthor = pr.Author
+			applyPRConditions(req, pr)
 		}
 	}
 
@@ -731,20 +802,62 @@ func (e *Engine) processTask(task *Task) {
 			filesChanged = diffStats.FilesChanged
 		}
 
-		// Get commit count in the range
-		commits := utils.GetCommitsInRange(ctx, repoPath, req.BaseCommitSHA, req.CommitSHA)
-		commitCount = len(commits)
+		// Get commits in the range
+		req.Commits = utils.GetCommitsInRange(ctx, repoPath, req.BaseCommitSHA, req.CommitSHA)
+		commitCount = len(req.Commits)
+	}
+
+	// Metadata used to evaluate rule when clauses
+	req.Author = prAuthor
+	if req.Author == "" {
+		req.Author = task.Review.Author
+	}
+	req.LinesChanged = linesAdded + linesDeleted
+	req.FilesChanged = filesChanged
+
+	// Load review configuration with priority:
+	// 1. .verust-review.yaml in the repository, as allowed by the repository's trust policy
+	// 2. Database configured review file for this repository
+	// 3. config/reviews/default.yaml (fallback)
+	// A review file override (e.g. from a schedule) takes precedence over all of them.
+	// Loaded once the base commit is known, since the base_branch policy reads the config from it.
+	effective := &utils.EffectiveReviewConfig{Source: model.ConfigSourceReviewFile}
+	effective.Config, err = e.loadReviewFileOverride(task.Review)
+	if err == nil && effective.Config == nil {
+		effective, err = e.loadDSLConfigWithPriority(repoPath, task.Review.RepoURL, req.BaseCommitSHA)
+	}
+	if err != nil {
+		logger.Error("Failed to load review configuration",
+			zap.String("review_id", task.Review.ID),
+			zap.String("repo_path", repoPath),
+			zap.Error(err),
+		)
+		metrics.RecordReviewCompleted(ctx, "failed", time.Since(startTime).Seconds())
+		e.handleError(task, errors.Wrap(errors.ErrCodeConfigInvalid, "failed to load review configuration", err))
+		return
 	}
+	task.ReviewRulesConfig = effective.Config
+	req.ReviewRulesConfig = effective.Config
+	logger.Info("Loaded review configuration",
+		zap.String("review_id", task.Review.ID),
+		zap.String("source", effective.Source),
+		zap.Int("rules", len(effective.Config.Rules)),
+	)
 
 	// Update review status to running and save metadata
 	now = time.Now()
 	updateFields := map[string]interface{}{
-		"status":        model.ReviewStatusRunning,
-		"started_at":    now,
-		"lines_added":   linesAdded,
-		"lines_deleted": linesDeleted,
-		"files_changed": filesChanged,
-		"commit_count":  commitCount,
+		"status":            model.ReviewStatusRunning,
+		"started_at":        now,
+		"lines_added":       linesAdded,
+		"lines_deleted":     linesDeleted,
+		"files_changed":     filesChanged,
+		"config_source":     effective.Source,
+		"config_rejections": model.StringArray(effective.Rejections),
+	}
+	// Keep the commit count reported by the webhook when there is no range to count
+	if commitCount > 0 || task.Review.CommitCount == 0 {
+		updateFields["commit_count"] = commitCount
 	}
 	if prAuthor != "" {
 		updateFields["author"] = prAuthor
@@ -759,6 +872,15 @@ func (e *Engine) processTask(task *Task) {
 
 	// Execute review with tracking
 	result, err := e.runReviewWithTracking(ctx, req, task.Review)
+	if err == runner.ErrReviewQuarantined {
+		// Not a failure: the review waits for an administrator to approve it
+		metrics.RecordReviewCompleted(ctx, "quarantined", time.Since(startTime).Seconds())
+		logger.Warn("Review quarantined, possible prompt injection awaits approval",
+			zap.String("review_id", task.Review.ID),
+			zap.Int("detections", len(task.Review.InjectionDetections)),
+		)
+		return
+	}
 	if err != nil {
 		telemetry.SetSpanError(span, err)
 		metrics.RecordReviewCompleted(ctx, "failed", time.Since(startTime).Seconds())
@@ -816,6 +938,14 @@ func (e *Engine) processTask(task *Task) {
 	)
 }
 
+// applyPRConditions copies the PR metadata used by rule when clauses into the review request
+func applyPRConditions(req *ReviewRequest, pr *provider.PullRequest) {
+	if pr.BaseBranch != "" {
+		req.TargetBranch = pr.BaseBranch
+	}
+	req.Labels = pr.Labels
+}
+
 // handleError handles task errors
 func (e *Engine) handleError(task *Task, err error) {
 	logger.Error("Task failed",
@@ -964,6 +1094,12 @@ func (e *Engine) Retry(reviewID string) error {
 	return e.retryHandler.Retry(reviewID)
 }
 
+// Approve releases a review quarantined because of a possible prompt injection.
+// Delegates to RetryHandler.
+func (e *Engine) Approve(reviewID, approvedBy string) error {
+	return e.retryHandler.Approve(reviewID, approvedBy)
+}
+
 // RetryRule retries a single failed rule within a review.
 // Delegates to RetryHandler.
 func (e *Engine) RetryRule(reviewID string, ruleID string) error {
@@ -994,3 +1130,192 @@ func (e *Engine) GetQueueRunningCount() int {
 	}
 	return e.repoQueue.GetRunningCount()
 }
+
+// ListQueuedTasks returns all running and pending tasks with their queue position and ETA
+func (e *Engine) ListQueuedTasks() []QueuedTaskInfo {
+	if e.repoQueue == nil {
+		return nil
+	}
+	return e.repoQueue.ListTasks()
+}
+
+// MoveTaskToFront moves a pending task to the front of its repository queue
+func (e *Engine) MoveTaskToFront(reviewID string) error {
+	if e.repoQueue == nil || !e.repoQueue.MoveToFront(reviewID) {
+		return errors.New(errors.ErrCodeNotFound, "task is not pending in the queue")
+	}
+	return nil
+}
+
+// PauseRepoQueue pauses dispatching for a repository.
+// New tasks for the repository are still accepted and persisted.
+// The paused state is stored and survives a restart.
+func (e *Engine) PauseRepoQueue(repoURL string) error {
+	if e.repoQueue == nil {
+		return errors.New(errors.ErrCodeInternal, "queue is not initialized")
+	}
+	e.repoQueue.PauseRepo(repoURL)
+	return e.saveQueueState()
+}
+
+// ResumeRepoQueue resumes dispatching for a paused repository
+func (e *Engine) ResumeRepoQueue(repoURL string) error {
+	if e.repoQueue == nil || !e.repoQueue.ResumeRepo(repoURL) {
+		return errors.New(errors.ErrCodeNotFound, "repository queue is not paused")
+	}
+	return e.saveQueueState()
+}
+
+// SetMaintenanceMode drains the engine into (or out of) maintenance mode.
+// While enabled, webhooks are still accepted and persisted as pending reviews,
+// but no task is dispatched. Running tasks are allowed to finish.
+// The flag is stored and survives a restart.
+func (e *Engine) SetMaintenanceMode(enabled bool) error {
+	if e.repoQueue == nil {
+		return errors.New(errors.ErrCodeInternal, "queue is not initialized")
+	}
+	e.repoQueue.SetMaintenance(enabled)
+	return e.saveQueueState()
+}
+
+// Queue state setting keys (category model.SettingCategoryQueue)
+const (
+	queueStateKeyMaintenance = "maintenance"
+	queueStateKeyPausedRepos = "paused_repos"
+)
+
+// saveQueueState stores the paused repositories and the maintenance flag
+func (e *Engine) saveQueueState() error {
+	if e.store == nil {
+		return nil
+	}
+
+	maintenance, _ := json.Marshal(e.repoQueue.InMaintenance())
+	pausedRepos, _ := json.Marshal(e.repoQueue.PausedRepos())
+	category := string(model.SettingCategoryQueue)
+	settings := []model.SystemSetting{
+		{Category: category, Key: queueStateKeyMaintenance, Value: string(maintenance), ValueType: string(model.SettingValueTypeBoolean)},
+		{Category: category, Key: queueStateKeyPausedRepos, Value: string(pausedRepos), ValueType: string(model.SettingValueTypeArray)},
+	}
+	if err := e.store.Settings().BatchUpsert(settings); err != nil {
+		logger.Error("Failed to save queue state", zap.Error(err))
+		return errors.Wrap(errors.ErrCodeDBQuery, "failed to save queue state", err)
+	}
+	return nil
+}
+
+// restoreQueueState restores the paused repositories and the maintenance flag saved by saveQueueState
+func (e *Engine) restoreQueueState() {
+	if e.store == nil {
+		return
+	}
+
+	settings, err := e.store.Settings().GetByCategory(string(model.SettingCategoryQueue))
+	if err != nil {
+		logger.Error("Failed to load queue state", zap.Error(err))
+		return
+	}
+
+	for _, setting := range settings {
+		switch setting.Key {
+		case queueStateKeyMaintenance:
+			var enabled bool
+			if err := json.Unmarshal([]byte(setting.Value), &enabled); err != nil {
+				logger.Warn("Invalid stored maintenance mode", zap.String("value", setting.Value), zap.Error(err))
+				continue
+			}
+			e.repoQueue.SetMaintenance(enabled)
+			if enabled {
+				logger.Warn("Maintenance mode restored, no tasks will be dispatched until it is disabled")
+			}
+		case queueStateKeyPausedRepos:
+			var repos []string
+			if err := json.Unmarshal([]byte(setting.Value), &repos); err != nil {
+				logger.Warn("Invalid stored paused repositories", zap.String("value", setting.Value), zap.Error(err))
+				continue
+			}
+			for _, repoURL := range repos {
+				e.repoQueue.PauseRepo(repoURL)
+			}
+			if len(repos) > 0 {
+				logger.Info("Paused repository queues restored", zap.Strings("repos", repos))
+			}
+		}
+	}
+}
+
+// InMaintenanceMode returns true if the engine is in maintenance mode
+func (e *Engine) InMaintenanceMode() bool {
+	if e.repoQueue == nil {
+		return false
+	}
+	return e.repoQueue.InMaintenance()
+}
+
+// ReloadSchedules rebuilds the scheduler's cron entries from the database.
+// Delegates to Scheduler.
+func (e *Engine) ReloadSchedules() error {
+	if e.scheduler == nil {
+		return nil
+	}
+	return e.scheduler.Reload()
+}
+
+// TriggerSchedule runs a schedule immediately.
+// Delegates to Scheduler.
+func (e *Engine) TriggerSchedule(scheduleID uint) (*model.ReviewScheduleRun, error) {
+	if e.scheduler == nil {
+		return nil, errors.New(errors.ErrCodeInternal, "scheduler is not initialized")
+	}
+	return e.scheduler.Trigger(scheduleID)
+}
+
+// GetScheduleNextRun returns the next run time of a registered schedule (nil if not scheduled).
+// Delegates to Scheduler.
+func (e *Engine) GetScheduleNextRun(scheduleID uint) *time.Time {
+	if e.scheduler == nil {
+		return nil
+	}
+	return e.scheduler.NextRun(scheduleID)
+}
+
+// GetAgentBreakerStatuses returns the circuit breaker state of every agent that has been called.
+// Delegates to Executor.
+func (e *Engine) GetAgentBreakerStatuses() []breaker.Status {
+	if e.executor == nil {
+		return nil
+	}
+	return e.executor.Breakers().Statuses()
+}
+
+// GetAgentPoolStatuses returns the running and queued calls of every agent in the agent pool,
+// shared with the report engine.
+func (e *Engine) GetAgentPoolStatuses() []pool.Status {
+	return pool.Default().Statuses()
+}
+
+// ReloadCustomAreas loads the organization-wide custom review areas from the database
+// into the DSL area registry, so that review files and prompts can use them.
+func (e *Engine) ReloadCustomAreas() error {
+	if e.store == nil || e.store.Area() == nil {
+		return nil
+	}
+
+	areas, err := e.store.Area().ListAll()
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeDBQuery, "failed to load custom areas", err)
+	}
+
+	defs := make([]dsl.AreaDefinition, 0, len(areas))
+	for _, area := range areas {
+		defs = append(defs, dsl.AreaDefinition{
+			ID:          area.AreaID,
+			Group:       dsl.AreaGroup(area.Group),
+			Description: area.Description,
+		})
+	}
+	dsl.SetCustomAreas(defs)
+
+	logger.Debug("Loaded custom review areas", zap.Int("count", len(defs)))
+	return nil
+}
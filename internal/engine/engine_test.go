@@ -309,6 +309,60 @@ func TestEngine_StartStop(t *testing.T) {
 	}
 }
 
+// TestEngine_QueueStatePersistence tests that paused repositories and maintenance mode survive a restart
+func TestEngine_QueueStatePersistence(t *testing.T) {
+	testStore, cleanup := store.SetupTestDB(t)
+	defer cleanup()
+
+	cfg := &config.Config{
+		Review: config.ReviewConfig{
+			MaxConcurrent: 1,
+		},
+		Git: config.GitConfig{
+			Providers: []config.ProviderConfig{},
+		},
+		Agents: make(map[string]config.AgentDetail),
+	}
+
+	pausedRepo := "https://github.com/test/paused"
+	resumedRepo := "https://github.com/test/resumed"
+
+	first, err := NewEngine(cfg, testStore)
+	if err != nil {
+		t.Fatalf("NewEngine() failed: %v", err)
+	}
+	if err := first.PauseRepoQueue(pausedRepo); err != nil {
+		t.Fatalf("PauseRepoQueue() failed: %v", err)
+	}
+	if err := first.PauseRepoQueue(resumedRepo); err != nil {
+		t.Fatalf("PauseRepoQueue() failed: %v", err)
+	}
+	if err := first.ResumeRepoQueue(resumedRepo); err != nil {
+		t.Fatalf("ResumeRepoQueue() failed: %v", err)
+	}
+	if err := first.SetMaintenanceMode(true); err != nil {
+		t.Fatalf("SetMaintenanceMode() failed: %v", err)
+	}
+	first.Stop()
+
+	second, err := NewEngine(cfg, testStore)
+	if err != nil {
+		t.Fatalf("NewEngine() failed: %v", err)
+	}
+	second.Start()
+	defer second.Stop()
+
+	if !second.InMaintenanceMode() {
+		t.Error("maintenance mode was not restored")
+	}
+	if !second.repoQueue.IsRepoPaused(pausedRepo) {
+		t.Errorf("%s is not paused after restart", pausedRepo)
+	}
+	if second.repoQueue.IsRepoPaused(resumedRepo) {
+		t.Errorf("%s is paused after restart, want resumed", resumedRepo)
+	}
+}
+
 // TestEngine_detectProviderFromURL tests detecting provider from URL
 func TestEngine_detectProviderFromURL(t *testing.T) {
 	testStore, cleanup := store.SetupTestDB(t)
@@ -0,0 +1,128 @@
+// Package executor handles review rule execution.
+// This file contains the response cache of agent executions.
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/prompt"
+	"github.com/verustcode/verustcode/pkg/logger"
+	"github.com/verustcode/verustcode/pkg/telemetry"
+)
+
+// responseCacheKeyVersion is part of every cache key; bump it when the cached format changes
+const responseCacheKeyVersion = "v1"
+
+// ResponseCacheKey returns the response cache key of an agent execution: a SHA-256 hash of the
+// rendered prompt, agent, effective model, head commit SHA and the rule's output schema.
+func ResponseCacheKey(rule *dsl.ReviewRuleConfig, agentName, modelName, commitSHA, promptText string) string {
+	schema, _ := json.Marshal(outputSchema(rule))
+	schemaHash := sha256.Sum256(schema)
+
+	h := sha256.New()
+	for _, part := range []string{responseCacheKeyVersion, agentName, modelName, commitSHA, hex.EncodeToString(schemaHash[:]), promptText} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseCacheTTL returns how long results are cached, or 0 if the response cache
+// is disabled or the execution can't be cached (rule opt-out, multi-run rule or unknown commit)
+func (e *Executor) responseCacheTTL(rule *dsl.ReviewRuleConfig, req *base.ReviewRequest) time.Duration {
+	if e.store == nil || !rule.IsCacheable() || req.CommitSHA == "" {
+		return 0
+	}
+	// The runs of a multi-run rule are meant to give independent answers
+	if rule.MultiRun != nil && rule.MultiRun.Runs >= 2 {
+		return 0
+	}
+	reviewCfg := e.getReviewConfig()
+	if reviewCfg == nil || !reviewCfg.ResponseCache.Enabled {
+		return 0
+	}
+	return reviewCfg.ResponseCache.GetTTL()
+}
+
+// effectiveModel returns the model an execution runs with: the requested model, or the
+// agent's default model like the agent pool resolves it
+func (e *Executor) effectiveModel(agentName, modelName string) string {
+	if modelName != "" {
+		return modelName
+	}
+	agentCfg, err := config.GetAgentConfig(e.store, agentName)
+	if err != nil || agentCfg == nil {
+		return ""
+	}
+	return agentCfg.DefaultModel
+}
+
+// lookupResponseCache returns the cached result of an execution, or nil if there is none
+func (e *Executor) lookupResponseCache(ctx context.Context, rule *dsl.ReviewRuleConfig, req *base.ReviewRequest, agentName, key string) *prompt.ReviewResult {
+	entry, err := e.store.ResponseCache().Get(key)
+	telemetry.GetMetrics().RecordResponseCacheLookup(ctx, agentName, err == nil)
+	if err != nil {
+		return nil
+	}
+
+	logger.Info("Using cached agent result",
+		zap.String("review_id", req.ReviewID),
+		zap.String("rule_id", rule.ID),
+		zap.String("agent", entry.AgentName),
+		zap.Int("hits", entry.Hits),
+	)
+
+	result := prompt.NewReviewResult(rule.ID)
+	result.Text = entry.Text
+	result.AgentName = entry.AgentName
+	result.ModelName = entry.ModelName
+	result.Cached = true
+	if len(entry.Data) > 0 {
+		result.Data = entry.Data
+	}
+	if entry.Validation != "" {
+		result.Validation = &model.OutputValidation{Status: entry.Validation}
+	}
+	return result
+}
+
+// storeResponseCache caches the result of an execution. Results whose output had to be
+// salvaged or couldn't be parsed are not cached, so that a retry calls the agent again.
+func (e *Executor) storeResponseCache(rule *dsl.ReviewRuleConfig, req *base.ReviewRequest, key string, ttl time.Duration, result *prompt.ReviewResult) {
+	if result.Validation != nil && result.Validation.Status != model.OutputValid && result.Validation.Status != model.OutputRepaired {
+		return
+	}
+
+	entry := &model.ResponseCacheEntry{
+		CacheKey:  key,
+		RepoURL:   req.RepoURL,
+		RuleID:    rule.ID,
+		CommitSHA: req.CommitSHA,
+		AgentName: result.AgentName,
+		ModelName: result.ModelName,
+		Text:      result.Text,
+		Data:      result.Data,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if result.Validation != nil {
+		// Repaired output is valid as cached
+		entry.Validation = model.OutputValid
+	}
+	if err := e.store.ResponseCache().Put(entry); err != nil {
+		logger.Warn("Failed to cache agent result",
+			zap.String("review_id", req.ReviewID),
+			zap.String("rule_id", rule.ID),
+			zap.Error(err),
+		)
+	}
+}
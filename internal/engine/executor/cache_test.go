@@ -0,0 +1,154 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/prompt"
+	"github.com/verustcode/verustcode/internal/store"
+)
+
+func newCacheTest(t *testing.T, agent *scriptedAgent, cache config.ResponseCacheConfig) (*Executor, *dsl.ReviewRuleConfig) {
+	testStore, cleanup := store.SetupTestDB(t)
+	t.Cleanup(cleanup)
+
+	settings := map[string]interface{}{
+		"response_cache": cache,
+		"output_repair":  config.OutputRepairConfig{Disabled: true},
+	}
+	require.NoError(t, config.NewSettingsService(testStore).SetCategory(string(model.SettingCategoryReview), settings, "test"))
+	executor := NewExecutor(&config.Config{}, map[string]base.Agent{agent.name: agent}, prompt.NewBuilder(), testStore)
+	rule := &dsl.ReviewRuleConfig{
+		ID:     "cached",
+		Agent:  dsl.AgentConfig{Type: agent.name},
+		Output: &dsl.OutputConfig{},
+	}
+	return executor, rule
+}
+
+func runCached(t *testing.T, executor *Executor, rule *dsl.ReviewRuleConfig, agent *scriptedAgent, commitSHA string) *prompt.ReviewResult {
+	buildCtx := &prompt.BuildContext{RepoURL: "https://github.com/org/repo", CommitSHA: commitSHA}
+	result, err := executor.executeSingleRun(context.Background(), rule, buildCtx, "review prompt", agent, nil)
+	require.NoError(t, err)
+	return result
+}
+
+func TestResponseCache_Hit(t *testing.T) {
+	agent := &scriptedAgent{mockAgent: mockAgent{name: "scripted"}, outputs: []string{validOutput, validOutput}}
+	executor, rule := newCacheTest(t, agent, config.ResponseCacheConfig{Enabled: true})
+
+	first := runCached(t, executor, rule, agent, "abc123")
+	assert.False(t, first.Cached)
+
+	second := runCached(t, executor, rule, agent, "abc123")
+	assert.True(t, second.Cached)
+	assert.Equal(t, first.Text, second.Text)
+	assert.Len(t, second.Data["findings"], 1)
+	require.NotNil(t, second.Validation)
+	assert.Equal(t, model.OutputValid, second.Validation.Status)
+	assert.Nil(t, second.Usage)
+	assert.Len(t, agent.prompts, 1)
+
+	// Another commit is a different execution
+	assert.False(t, runCached(t, executor, rule, agent, "def456").Cached)
+	assert.Len(t, agent.prompts, 2)
+
+	// Purging by repository removes the entries of both commits
+	deleted, err := executor.store.ResponseCache().Purge("https://github.com/org/repo", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+}
+
+func TestResponseCache_Bypassed(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name      string
+		cache     config.ResponseCacheConfig
+		ruleCache *bool
+		multiRun  *dsl.MultiRunConfig
+		commitSHA string
+		output    string
+	}{
+		{name: "disabled", output: validOutput, commitSHA: "abc123"},
+		{name: "rule opt-out", cache: config.ResponseCacheConfig{Enabled: true}, ruleCache: &disabled, output: validOutput, commitSHA: "abc123"},
+		{name: "multi-run rule", cache: config.ResponseCacheConfig{Enabled: true}, multiRun: &dsl.MultiRunConfig{Runs: 2}, output: validOutput, commitSHA: "abc123"},
+		{name: "unknown commit", cache: config.ResponseCacheConfig{Enabled: true}, output: validOutput},
+		{name: "invalid output", cache: config.ResponseCacheConfig{Enabled: true}, output: "not json", commitSHA: "abc123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &scriptedAgent{mockAgent: mockAgent{name: "scripted"}, outputs: []string{tt.output, tt.output}}
+			executor, rule := newCacheTest(t, agent, tt.cache)
+			rule.Cache = tt.ruleCache
+			rule.MultiRun = tt.multiRun
+
+			runCached(t, executor, rule, agent, tt.commitSHA)
+			assert.False(t, runCached(t, executor, rule, agent, tt.commitSHA).Cached)
+			assert.Len(t, agent.prompts, 2)
+		})
+	}
+}
+
+func TestResponseCache_DefaultModelChange(t *testing.T) {
+	agent := &scriptedAgent{mockAgent: mockAgent{name: "scripted"}, outputs: []string{validOutput, validOutput}}
+	executor, rule := newCacheTest(t, agent, config.ResponseCacheConfig{Enabled: true})
+	setDefaultModel := func(modelName string) {
+		settings := map[string]interface{}{agent.name: config.AgentDetail{DefaultModel: modelName}}
+		require.NoError(t, config.NewSettingsService(executor.store).SetCategory(string(model.SettingCategoryAgents), settings, "test"))
+	}
+
+	// The rule has no model, so the agent's default model is part of the key
+	setDefaultModel("sonnet")
+	assert.False(t, runCached(t, executor, rule, agent, "abc123").Cached)
+	setDefaultModel("opus")
+	assert.False(t, runCached(t, executor, rule, agent, "abc123").Cached)
+	assert.Len(t, agent.prompts, 2)
+}
+
+func TestResponseCache_Failover(t *testing.T) {
+	agent := &scriptedAgent{mockAgent: mockAgent{name: "scripted"}}
+	backup := &scriptedAgent{mockAgent: mockAgent{name: "backup"}, outputs: []string{validOutput}}
+	executor, rule := newCacheTest(t, agent, config.ResponseCacheConfig{Enabled: true})
+	executor.agents[backup.name] = backup
+	rule.Agent.Fallback = []string{backup.name}
+
+	// The primary agent's breaker is open, so the fallback agent runs the rule
+	executor.Breakers().Configure(config.CircuitBreakerConfig{ConsecutiveFailures: 1})
+	executor.Breakers().Get(agent.name).Record(errors.New("unavailable"))
+	result := runCached(t, executor, rule, agent, "abc123")
+	assert.Equal(t, backup.name, result.AgentName)
+	assert.Len(t, backup.prompts, 1)
+
+	// The result is cached under the fallback agent, not served as the primary agent's result
+	_, err := executor.store.ResponseCache().Get(ResponseCacheKey(rule, agent.name, "", "abc123", "review prompt"))
+	assert.Error(t, err)
+	_, err = executor.store.ResponseCache().Get(ResponseCacheKey(rule, backup.name, "", "abc123", "review prompt"))
+	assert.NoError(t, err)
+}
+
+func TestResponseCacheKey(t *testing.T) {
+	rule := &dsl.ReviewRuleConfig{ID: "keyed", Output: &dsl.OutputConfig{}}
+	key := ResponseCacheKey(rule, "cursor", "sonnet", "abc123", "prompt")
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, ResponseCacheKey(rule, "cursor", "sonnet", "abc123", "prompt"))
+
+	assert.NotEqual(t, key, ResponseCacheKey(rule, "gemini", "sonnet", "abc123", "prompt"))
+	assert.NotEqual(t, key, ResponseCacheKey(rule, "cursor", "opus", "abc123", "prompt"))
+	assert.NotEqual(t, key, ResponseCacheKey(rule, "cursor", "sonnet", "def456", "prompt"))
+	assert.NotEqual(t, key, ResponseCacheKey(rule, "cursor", "sonnet", "abc123", "other prompt"))
+
+	// The output schema is part of the key
+	extended := &dsl.ReviewRuleConfig{ID: "keyed", Output: &dsl.OutputConfig{Schema: &dsl.OutputSchemaConfig{
+		ExtraFields: []dsl.ExtraFieldConfig{{Name: "cwe", Type: "string", Description: "CWE ID"}},
+	}}}
+	assert.NotEqual(t, key, ResponseCacheKey(extended, "cursor", "sonnet", "abc123", "prompt"))
+}
@@ -0,0 +1,149 @@
+// Package executor handles review rule execution.
+// This file contains the recording of agent interactions into cassettes.
+package executor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/llm"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// cassetteRunKey is the context key of the run agent calls are recorded for
+type cassetteRunKey struct{}
+
+// cassetteRun identifies the review and ReviewRuleRun of recorded agent calls
+type cassetteRun struct {
+	reviewID string
+	runIndex int
+}
+
+// withCassetteRun returns a copy of ctx whose agent calls are recorded for the review's run
+func withCassetteRun(ctx context.Context, reviewID string, runIndex int) context.Context {
+	return context.WithValue(ctx, cassetteRunKey{}, cassetteRun{reviewID: reviewID, runIndex: runIndex})
+}
+
+// withCassetteRunIndex returns a copy of ctx whose agent calls are recorded for another run of the same review
+func withCassetteRunIndex(ctx context.Context, runIndex int) context.Context {
+	run, _ := ctx.Value(cassetteRunKey{}).(cassetteRun)
+	return withCassetteRun(ctx, run.reviewID, runIndex)
+}
+
+// recording collects the stream chunks of an agent call
+type recording struct {
+	run       cassetteRun
+	startedAt time.Time
+
+	mu     sync.Mutex
+	chunks model.CassetteChunks
+}
+
+// record is the stream recorder of the call
+func (r *recording) record(chunk *llm.StreamChunk) {
+	recorded := model.CassetteChunk{
+		Type:       string(chunk.Type),
+		Content:    chunk.Content,
+		Delta:      chunk.Delta,
+		IsComplete: chunk.IsComplete,
+		ToolName:   chunk.ToolName,
+		ToolInput:  chunk.ToolInput,
+		ToolOutput: chunk.ToolOutput,
+	}
+	if len(chunk.Metadata) > 0 {
+		recorded.Metadata = make(map[string]string, len(chunk.Metadata))
+		for k, v := range chunk.Metadata {
+			recorded.Metadata[k] = v
+		}
+	}
+
+	r.mu.Lock()
+	r.chunks = append(r.chunks, recorded)
+	r.mu.Unlock()
+}
+
+// recordingEnabled returns true if agent interactions are recorded
+func (e *Executor) recordingEnabled() bool {
+	if e.store == nil {
+		return false
+	}
+	reviewCfg := e.getReviewConfig()
+	return reviewCfg != nil && reviewCfg.Recording.Enabled
+}
+
+// startRecording returns the recording of an agent call and the context to execute it with,
+// or a nil recording if recording is disabled or the call belongs to no review
+func (e *Executor) startRecording(ctx context.Context, req *base.ReviewRequest) (context.Context, *recording) {
+	run, _ := ctx.Value(cassetteRunKey{}).(cassetteRun)
+	if run.reviewID == "" {
+		run.reviewID = req.ReviewID
+	}
+	if run.reviewID == "" || !e.recordingEnabled() {
+		return ctx, nil
+	}
+
+	rec := &recording{run: run, startedAt: time.Now()}
+	return llm.WithStreamRecorder(ctx, rec.record), rec
+}
+
+// finishRecording stores the recorded agent call
+func (e *Executor) finishRecording(rec *recording, agent base.Agent, req *base.ReviewRequest, promptText string, result *base.ReviewResult, err error) {
+	rec.mu.Lock()
+	chunks := rec.chunks
+	rec.mu.Unlock()
+
+	interaction := &model.CassetteInteraction{
+		ReviewID:       rec.run.reviewID,
+		RuleID:         req.RuleID,
+		RunIndex:       rec.run.runIndex,
+		Agent:          agent.Name(),
+		RequestedModel: req.Model,
+		RepoURL:        req.RepoURL,
+		Ref:            req.Ref,
+		CommitSHA:      req.CommitSHA,
+		PRNumber:       req.PRNumber,
+		PRTitle:        req.PRTitle,
+		PRDescription:  req.PRBody,
+		ChangedFiles:   req.ChangedFiles,
+		Prompt:         promptText,
+		Chunks:         chunks,
+		Duration:       time.Since(rec.startedAt).Milliseconds(),
+	}
+	if result != nil {
+		interaction.Model = result.ModelName
+		interaction.Content = result.Text
+		interaction.SessionID = result.SessionID
+		interaction.TokenUsage = tokenUsage(result.Usage)
+	}
+	if err != nil {
+		interaction.Error = err.Error()
+		interaction.Retryable = llm.IsRetryable(err)
+	}
+
+	if err := e.store.Cassette().Record(interaction); err != nil {
+		logger.Warn("Failed to record agent interaction",
+			zap.String("review_id", rec.run.reviewID),
+			zap.String("rule_id", req.RuleID),
+			zap.Error(err),
+		)
+	}
+}
+
+// resetRecording deletes the interactions recorded by an earlier execution of the rule
+func (e *Executor) resetRecording(reviewRule *model.ReviewRule) {
+	if !e.recordingEnabled() {
+		return
+	}
+	if err := e.store.Cassette().DeleteByRule(reviewRule.ReviewID, reviewRule.RuleID); err != nil {
+		logger.Warn("Failed to delete recorded agent interactions",
+			zap.String("review_id", reviewRule.ReviewID),
+			zap.String("rule_id", reviewRule.RuleID),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	agentmock "github.com/verustcode/verustcode/internal/agent/mock"
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/llm"
+	llmmock "github.com/verustcode/verustcode/internal/llm/mock"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/prompt"
+	"github.com/verustcode/verustcode/internal/store"
+)
+
+func newRecordingTest(t *testing.T, recording config.RecordingConfig) (*Executor, base.Agent) {
+	testStore, cleanup := store.SetupTestDB(t)
+	t.Cleanup(cleanup)
+
+	settings := map[string]interface{}{
+		"recording":     recording,
+		"output_repair": config.OutputRepairConfig{Disabled: true},
+	}
+	require.NoError(t, config.NewSettingsService(testStore).SetCategory(string(model.SettingCategoryReview), settings, "test"))
+
+	// The client is created through the registry, which reports its chunks to the recorder
+	client, err := llm.Create(llmmock.ClientName, nil)
+	require.NoError(t, err)
+	agent := agentmock.NewAgentWithClient(client)
+	return NewExecutor(&config.Config{}, map[string]base.Agent{agent.Name(): agent}, prompt.NewBuilder(), testStore), agent
+}
+
+func TestRecording_RecordsInteraction(t *testing.T) {
+	executor, agent := newRecordingTest(t, config.RecordingConfig{Enabled: true})
+	rule := &dsl.ReviewRuleConfig{ID: "recorded", Agent: dsl.AgentConfig{Type: agent.Name()}, Output: &dsl.OutputConfig{}}
+	buildCtx := &prompt.BuildContext{RepoURL: "https://github.com/org/repo", CommitSHA: "abc123", PRTitle: "Add feature"}
+
+	ctx := withCassetteRunIndex(withCassetteRun(context.Background(), "review-1", 0), 1)
+	result, err := executor.executeSingleRun(ctx, rule, buildCtx, "review prompt", agent, nil)
+	require.NoError(t, err)
+
+	interactions, err := executor.store.Cassette().ListByReviewID("review-1")
+	require.NoError(t, err)
+	require.Len(t, interactions, 1)
+
+	recorded := interactions[0]
+	assert.Equal(t, "recorded", recorded.RuleID)
+	assert.Equal(t, 1, recorded.RunIndex)
+	assert.Equal(t, agent.Name(), recorded.Agent)
+	assert.Equal(t, "abc123", recorded.CommitSHA)
+	assert.Equal(t, "Add feature", recorded.PRTitle)
+	assert.Equal(t, "review prompt", recorded.Prompt)
+	assert.Equal(t, result.Text, recorded.Content)
+
+	// A non-streaming execution is recorded as a single result chunk
+	require.Len(t, recorded.Chunks, 1)
+	assert.Equal(t, string(llm.ChunkTypeResult), recorded.Chunks[0].Type)
+	assert.Equal(t, result.Text, recorded.Chunks[0].Content)
+
+	// A new execution of the rule replaces its recording
+	executor.resetRecording(&model.ReviewRule{ReviewID: "review-1", RuleID: "recorded"})
+	interactions, err = executor.store.Cassette().ListByReviewID("review-1")
+	require.NoError(t, err)
+	assert.Empty(t, interactions)
+}
+
+func TestRecording_Disabled(t *testing.T) {
+	executor, agent := newRecordingTest(t, config.RecordingConfig{})
+	rule := &dsl.ReviewRuleConfig{ID: "recorded", Agent: dsl.AgentConfig{Type: agent.Name()}, Output: &dsl.OutputConfig{}}
+
+	ctx := withCassetteRun(context.Background(), "review-1", 0)
+	_, err := executor.executeSingleRun(ctx, rule, &prompt.BuildContext{}, "review prompt", agent, nil)
+	require.NoError(t, err)
+
+	interactions, err := executor.store.Cassette().ListByReviewID("review-1")
+	require.NoError(t, err)
+	assert.Empty(t, interactions)
+}
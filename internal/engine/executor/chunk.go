@@ -0,0 +1,466 @@
+// Package executor handles review rule execution.
+// This file contains chunked execution of large change sets.
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/engine/task"
+	"github.com/verustcode/verustcode/internal/engine/utils"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/prompt"
+	"github.com/verustcode/verustcode/pkg/errors"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// Chunk is a batch of changed files reviewed by a single agent call
+type Chunk struct {
+	// Files lists the file paths in this chunk
+	Files []string
+
+	// DiffLines is the total number of changed lines in this chunk
+	DiffLines int
+}
+
+// PlanChunks splits changed files into chunks according to the chunking config.
+// Files are packed in order until MaxFiles or MaxDiffLines would be exceeded.
+// With GroupByDirectory, files of the same directory are kept together unless
+// the directory alone exceeds the limits, in which case it is split.
+// Returns nil if chunking is disabled or there are no files.
+func PlanChunks(files []utils.FileDiffStat, cfg *dsl.ChunkingConfig) []Chunk {
+	if !cfg.IsEnabled() || len(files) == 0 {
+		return nil
+	}
+
+	// Build groups of files that should stay together
+	var groups [][]utils.FileDiffStat
+	if cfg.GroupByDirectory {
+		sorted := make([]utils.FileDiffStat, len(files))
+		copy(sorted, files)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return path.Dir(sorted[i].Path) < path.Dir(sorted[j].Path)
+		})
+		for _, f := range sorted {
+			if n := len(groups); n > 0 && path.Dir(groups[n-1][0].Path) == path.Dir(f.Path) {
+				groups[n-1] = append(groups[n-1], f)
+				continue
+			}
+			groups = append(groups, []utils.FileDiffStat{f})
+		}
+	} else {
+		for _, f := range files {
+			groups = append(groups, []utils.FileDiffStat{f})
+		}
+	}
+
+	fits := func(c *Chunk, fileCount, lines int) bool {
+		if cfg.MaxFiles > 0 && len(c.Files)+fileCount > cfg.MaxFiles {
+			return false
+		}
+		if cfg.MaxDiffLines > 0 && c.DiffLines+lines > cfg.MaxDiffLines {
+			return false
+		}
+		return true
+	}
+
+	var chunks []Chunk
+	current := &Chunk{}
+	flush := func() {
+		if len(current.Files) > 0 {
+			chunks = append(chunks, *current)
+			current = &Chunk{}
+		}
+	}
+	add := func(f utils.FileDiffStat) {
+		current.Files = append(current.Files, f.Path)
+		current.DiffLines += f.LinesChanged
+	}
+
+	for _, group := range groups {
+		groupLines := 0
+		for _, f := range group {
+			groupLines += f.LinesChanged
+		}
+
+		// Whole group fits into the current chunk
+		if fits(current, len(group), groupLines) {
+			for _, f := range group {
+				add(f)
+			}
+			continue
+		}
+
+		// Whole group fits into a fresh chunk
+		flush()
+		if fits(current, len(group), groupLines) {
+			for _, f := range group {
+				add(f)
+			}
+			continue
+		}
+
+		// Group is too large on its own, split it file by file
+		for _, f := range group {
+			if len(current.Files) > 0 && !fits(current, 1, f.LinesChanged) {
+				flush()
+			}
+			add(f)
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+// resolveChunkFiles returns the changed files with their diff sizes for chunk planning.
+// Diff sizes come from git when a commit range is known; files listed in
+// buildCtx.ChangedFiles restrict the result and keep their order.
+func resolveChunkFiles(ctx context.Context, buildCtx *prompt.BuildContext) []utils.FileDiffStat {
+	stats := utils.GetFileDiffStats(ctx, buildCtx.RepoPath, buildCtx.BaseCommitSHA, buildCtx.CommitSHA)
+	if len(buildCtx.ChangedFiles) == 0 {
+		return stats
+	}
+
+	lines := make(map[string]int, len(stats))
+	for _, s := range stats {
+		lines[s.Path] = s.LinesChanged
+	}
+
+	files := make([]utils.FileDiffStat, 0, len(buildCtx.ChangedFiles))
+	for _, f := range buildCtx.ChangedFiles {
+		files = append(files, utils.FileDiffStat{Path: f, LinesChanged: lines[f]})
+	}
+	return files
+}
+
+// executeChunked reviews each chunk with a separate agent call and merges the results.
+// Completed chunks from a previous attempt are reused when the chunk plan is unchanged.
+// The rule fails if any chunk fails, so that a partial review is never reported as complete.
+func (e *Executor) executeChunked(ctx context.Context, rule *dsl.ReviewRuleConfig, buildCtx *prompt.BuildContext, agent base.Agent, reviewRule *model.ReviewRule, chunks []Chunk) (*prompt.ReviewResult, error) {
+	reviewID := ""
+	if reviewRule != nil {
+		reviewID = reviewRule.ReviewID
+	}
+
+	logger.Info("Starting chunked review",
+		zap.String("review_id", reviewID),
+		zap.String("rule_id", rule.ID),
+		zap.Int("chunks", len(chunks)),
+	)
+
+	existingChunks := e.loadExistingChunks(reviewRule, chunks)
+
+	if reviewRule != nil {
+		reviewRule.ChunkCount = len(chunks)
+		if err := e.store.Review().UpdateRule(reviewRule); err != nil {
+			logger.Warn("Failed to update review rule chunk count",
+				zap.String("review_id", reviewID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	results := make([]task.RunResult, 0, len(chunks))
+	var failed []string
+	var agentName, modelName string
+
+	for i, chunk := range chunks {
+		// Reuse chunk result from a previous attempt (recovery)
+		record := existingChunks[i]
+		if record != nil && record.Status == model.RunStatusCompleted {
+			logger.Info("Skipping completed chunk during recovery",
+				zap.String("review_id", reviewID),
+				zap.String("rule_id", rule.ID),
+				zap.Int("chunk_index", i),
+			)
+			results = append(results, task.RunResult{
+				Index:    i + 1,
+				Model:    fmt.Sprintf("chunk-%d", i+1),
+				Data:     record.Data,
+				Text:     record.Text,
+				Duration: time.Duration(record.Duration) * time.Millisecond,
+			})
+			continue
+		}
+
+		now := time.Now()
+		if reviewRule != nil {
+			if record == nil {
+				record = &model.ReviewRuleChunk{
+					ReviewRuleID: reviewRule.ID,
+					ChunkIndex:   i,
+					Files:        strings.Join(chunk.Files, "\n"),
+					FileCount:    len(chunk.Files),
+					DiffLines:    chunk.DiffLines,
+					Agent:        agent.Name(),
+					Status:       model.RunStatusRunning,
+					StartedAt:    &now,
+				}
+				if err := e.store.Review().CreateChunk(record); err != nil {
+					logger.Warn("Failed to create review rule chunk",
+						zap.String("review_id", reviewID),
+						zap.Error(err),
+					)
+					record = nil
+				}
+			} else {
+				record.Status = model.RunStatusRunning
+				record.StartedAt = &now
+				record.ErrorMessage = ""
+				if err := e.store.Review().UpdateChunk(record); err != nil {
+					logger.Warn("Failed to update review rule chunk",
+						zap.String("review_id", reviewID),
+						zap.Error(err),
+					)
+				}
+			}
+
+			reviewRule.CurrentChunkIndex = i
+			if err := e.store.Review().UpdateRule(reviewRule); err != nil {
+				logger.Warn("Failed to update review rule chunk progress",
+					zap.String("review_id", reviewID),
+					zap.String("rule_id", rule.ID),
+					zap.Error(err),
+				)
+			}
+		}
+
+		logger.Info("Executing review chunk",
+			zap.String("review_id", reviewID),
+			zap.String("rule_id", rule.ID),
+			zap.Int("chunk", i+1),
+			zap.Int("total_chunks", len(chunks)),
+			zap.Int("files", len(chunk.Files)),
+			zap.Int("diff_lines", chunk.DiffLines),
+		)
+
+		chunkCtx := *buildCtx
+		chunkCtx.ChangedFiles = chunk.Files
+		chunkCtx.ChunkIndex = i + 1
+		chunkCtx.ChunkTotal = len(chunks)
+
+		startTime := time.Now()
+		result, err := e.renderAndExecute(ctx, rule, &chunkCtx, agent, reviewRule)
+		duration := time.Since(startTime)
+
+		if record != nil {
+			completed := time.Now()
+			record.CompletedAt = &completed
+			record.Duration = duration.Milliseconds()
+			if err != nil {
+				record.Status = model.RunStatusFailed
+				record.ErrorMessage = err.Error()
+			} else {
+				record.Status = model.RunStatusCompleted
+				record.Data = result.Data
+				record.Text = result.Text
+				if findings, ok := result.Data["findings"].([]interface{}); ok {
+					record.FindingsCount = len(findings)
+				}
+			}
+			if updateErr := e.store.Review().UpdateChunk(record); updateErr != nil {
+				logger.Warn("Failed to update review rule chunk",
+					zap.String("review_id", reviewID),
+					zap.Error(updateErr),
+				)
+			}
+		}
+
+		if err != nil {
+			logger.Error("Review chunk failed",
+				zap.String("review_id", reviewID),
+				zap.String("rule_id", rule.ID),
+				zap.Int("chunk", i+1),
+				zap.Error(err),
+			)
+			failed = append(failed, fmt.Sprintf("%d", i+1))
+			continue
+		}
+
+		if agentName == "" {
+			agentName = result.AgentName
+			modelName = result.ModelName
+		}
+		results = append(results, task.RunResult{
+			Index:      i + 1,
+			Model:      fmt.Sprintf("chunk-%d", i+1),
+			Data:       result.Data,
+			Text:       result.Text,
+			Duration:   duration,
+			Usage:      result.Usage,
+			Validation: result.Validation,
+		})
+	}
+
+	if len(failed) > 0 {
+		return nil, errors.New(errors.ErrCodeAgentExecution,
+			fmt.Sprintf("%d of %d chunks failed for rule %s (chunks %s)",
+				len(failed), len(chunks), rule.ID, strings.Join(failed, ", ")))
+	}
+
+	logger.Info("All review chunks completed",
+		zap.String("review_id", reviewID),
+		zap.String("rule_id", rule.ID),
+		zap.Int("chunks", len(chunks)),
+	)
+
+	result := prompt.NewReviewResult(rule.ID)
+	result.AgentName = agentName
+	result.ModelName = modelName
+
+	// Usage covers all chunks executed now and the merge
+	for _, r := range results {
+		addUsage(&result.Usage, r.Usage)
+	}
+
+	// Merge results using LLM, falling back to concatenating chunk outputs
+	var mergeValidation *model.OutputValidation
+	mergedText, mergeUsage, err := e.mergeReviewResults(ctx, rule, agent, results)
+	addUsage(&result.Usage, mergeUsage)
+	if err != nil {
+		logger.Warn("Failed to merge chunk results, concatenating chunk outputs",
+			zap.String("review_id", reviewID),
+			zap.String("rule_id", rule.ID),
+			zap.Error(err),
+		)
+		texts := make([]string, 0, len(results))
+		for _, r := range results {
+			if r.Text != "" {
+				texts = append(texts, r.Text)
+			}
+		}
+		mergedText = strings.Join(texts, "\n\n")
+	} else if mergedText != "" {
+		var parsed map[string]interface{}
+		if parseErr := json.Unmarshal([]byte(mergedText), &parsed); parseErr == nil {
+			result.Data, mergeValidation = salvageMergedOutput(rule, parsed)
+		}
+	}
+	result.Validation = mergeValidations(results, mergeValidation)
+
+	result.Text = mergedText
+	// Keep structured findings even if the merged output is not valid JSON
+	if len(result.Data) == 0 {
+		if data := mergeChunkData(results); data != nil {
+			result.Data = data
+		}
+	}
+
+	return result, nil
+}
+
+// renderAndExecute renders the prompt for buildCtx and executes a single run
+func (e *Executor) renderAndExecute(ctx context.Context, rule *dsl.ReviewRuleConfig, buildCtx *prompt.BuildContext, agent base.Agent, reviewRule *model.ReviewRule) (*prompt.ReviewResult, error) {
+	spec := e.promptBuilder.Build(rule, buildCtx)
+	promptText, err := RenderPrompt(rule, spec, buildCtx)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeInternal, "failed to render prompt", err)
+	}
+
+	return e.executeSingleRun(ctx, rule, buildCtx, promptText, agent, reviewRule)
+}
+
+// loadExistingChunks returns chunk records from a previous attempt indexed by chunk index.
+// Records are discarded when the chunk plan has changed since they were created.
+func (e *Executor) loadExistingChunks(reviewRule *model.ReviewRule, chunks []Chunk) map[int]*model.ReviewRuleChunk {
+	existing := make(map[int]*model.ReviewRuleChunk)
+	if reviewRule == nil || reviewRule.ID == 0 {
+		return existing
+	}
+
+	records, err := e.store.Review().GetChunksByRuleID(reviewRule.ID)
+	if err != nil {
+		logger.Warn("Failed to load existing review rule chunks, will create new ones",
+			zap.String("review_id", reviewRule.ReviewID),
+			zap.Uint("review_rule_id", reviewRule.ID),
+			zap.Error(err),
+		)
+		return existing
+	}
+	if len(records) == 0 {
+		return existing
+	}
+
+	planChanged := len(records) != len(chunks)
+	for i := range records {
+		r := &records[i]
+		if r.ChunkIndex < 0 || r.ChunkIndex >= len(chunks) || r.Files != strings.Join(chunks[r.ChunkIndex].Files, "\n") {
+			planChanged = true
+			break
+		}
+		existing[r.ChunkIndex] = r
+	}
+
+	if planChanged {
+		logger.Info("Chunk plan changed, discarding previous chunk results",
+			zap.String("review_id", reviewRule.ReviewID),
+			zap.Uint("review_rule_id", reviewRule.ID),
+		)
+		if err := e.store.Review().DeleteReviewRuleChunksByRuleID(reviewRule.ID); err != nil {
+			logger.Warn("Failed to delete review rule chunks",
+				zap.String("review_id", reviewRule.ReviewID),
+				zap.Error(err),
+			)
+		}
+		return make(map[int]*model.ReviewRuleChunk)
+	}
+
+	return existing
+}
+
+// mergeChunkData deterministically merges chunk results without an LLM.
+// Findings arrays are concatenated and exact duplicates removed; other fields
+// are taken from the first chunk that provides them.
+// Returns nil if no chunk produced structured data.
+func mergeChunkData(results []task.RunResult) map[string]interface{} {
+	var merged map[string]interface{}
+	var findings []interface{}
+	seen := make(map[string]bool)
+
+	for _, r := range results {
+		if len(r.Data) == 0 {
+			continue
+		}
+		if merged == nil {
+			merged = make(map[string]interface{})
+		}
+		for k, v := range r.Data {
+			if k == "findings" {
+				continue
+			}
+			if _, ok := merged[k]; !ok {
+				merged[k] = v
+			}
+		}
+
+		items, _ := r.Data["findings"].([]interface{})
+		for _, item := range items {
+			key, err := json.Marshal(item)
+			if err == nil {
+				if seen[string(key)] {
+					continue
+				}
+				seen[string(key)] = true
+			}
+			findings = append(findings, item)
+		}
+	}
+
+	if merged != nil {
+		if findings == nil {
+			findings = []interface{}{}
+		}
+		merged["findings"] = findings
+	}
+	return merged
+}
@@ -0,0 +1,164 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/engine/task"
+	"github.com/verustcode/verustcode/internal/engine/utils"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/prompt"
+	"github.com/verustcode/verustcode/internal/store"
+)
+
+func TestPlanChunks(t *testing.T) {
+	files := []utils.FileDiffStat{
+		{Path: "a/one.go", LinesChanged: 100},
+		{Path: "b/two.go", LinesChanged: 50},
+		{Path: "a/three.go", LinesChanged: 30},
+		{Path: "c/four.go", LinesChanged: 500},
+		{Path: "b/five.go", LinesChanged: 20},
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Nil(t, PlanChunks(files, nil))
+		assert.Nil(t, PlanChunks(files, &dsl.ChunkingConfig{}))
+	})
+
+	t.Run("no files", func(t *testing.T) {
+		assert.Nil(t, PlanChunks(nil, &dsl.ChunkingConfig{MaxFiles: 2}))
+	})
+
+	t.Run("max files", func(t *testing.T) {
+		chunks := PlanChunks(files, &dsl.ChunkingConfig{MaxFiles: 2})
+		require.Len(t, chunks, 3)
+		assert.Equal(t, []string{"a/one.go", "b/two.go"}, chunks[0].Files)
+		assert.Equal(t, 150, chunks[0].DiffLines)
+		assert.Equal(t, []string{"a/three.go", "c/four.go"}, chunks[1].Files)
+		assert.Equal(t, []string{"b/five.go"}, chunks[2].Files)
+	})
+
+	t.Run("max diff lines with oversized file", func(t *testing.T) {
+		chunks := PlanChunks(files, &dsl.ChunkingConfig{MaxDiffLines: 200})
+		require.Len(t, chunks, 3)
+		assert.Equal(t, []string{"a/one.go", "b/two.go", "a/three.go"}, chunks[0].Files)
+		assert.Equal(t, []string{"c/four.go"}, chunks[1].Files)
+		assert.Equal(t, 500, chunks[1].DiffLines)
+		assert.Equal(t, []string{"b/five.go"}, chunks[2].Files)
+	})
+
+	t.Run("group by directory", func(t *testing.T) {
+		chunks := PlanChunks(files, &dsl.ChunkingConfig{MaxFiles: 3, GroupByDirectory: true})
+		require.Len(t, chunks, 2)
+		assert.Equal(t, []string{"a/one.go", "a/three.go"}, chunks[0].Files)
+		assert.Equal(t, []string{"b/two.go", "b/five.go", "c/four.go"}, chunks[1].Files)
+	})
+
+	t.Run("group larger than limit is split", func(t *testing.T) {
+		dirFiles := []utils.FileDiffStat{
+			{Path: "pkg/a.go"}, {Path: "pkg/b.go"}, {Path: "pkg/c.go"}, {Path: "main.go"},
+		}
+		chunks := PlanChunks(dirFiles, &dsl.ChunkingConfig{MaxFiles: 2, GroupByDirectory: true})
+		require.Len(t, chunks, 3)
+		assert.Equal(t, []string{"main.go"}, chunks[0].Files)
+		assert.Equal(t, []string{"pkg/a.go", "pkg/b.go"}, chunks[1].Files)
+		assert.Equal(t, []string{"pkg/c.go"}, chunks[2].Files)
+	})
+}
+
+func TestMergeChunkData(t *testing.T) {
+	finding := map[string]interface{}{"file": "a.go", "line": float64(1), "message": "issue"}
+
+	t.Run("no structured data", func(t *testing.T) {
+		assert.Nil(t, mergeChunkData([]task.RunResult{{Index: 1, Text: "text only"}}))
+	})
+
+	t.Run("concatenates and dedupes findings", func(t *testing.T) {
+		results := []task.RunResult{
+			{Index: 1, Data: map[string]interface{}{"summary": "first", "findings": []interface{}{finding}}},
+			{Index: 2, Data: map[string]interface{}{"summary": "second", "findings": []interface{}{
+				finding,
+				map[string]interface{}{"file": "b.go", "line": float64(2), "message": "other"},
+			}}},
+		}
+
+		merged := mergeChunkData(results)
+		require.NotNil(t, merged)
+		assert.Equal(t, "first", merged["summary"])
+		assert.Len(t, merged["findings"], 2)
+	})
+}
+
+func TestExecuteRule_Chunked(t *testing.T) {
+	testStore, cleanup := store.SetupTestDB(t)
+	defer cleanup()
+
+	review := store.CreateTestReview(t, testStore)
+
+	mockAgent := newMockAgent("mock")
+	cfg := &config.Config{
+		Review: config.ReviewConfig{
+			MaxRetries: 1,
+			RetryDelay: 0,
+		},
+	}
+	executor := NewExecutor(cfg, map[string]base.Agent{"mock": mockAgent}, prompt.NewBuilder(), testStore)
+
+	rule := &dsl.ReviewRuleConfig{
+		ID:       "test-chunked",
+		Agent:    dsl.AgentConfig{Type: "mock"},
+		Goals:    dsl.GoalsConfig{Areas: []string{"business-logic"}},
+		Chunking: &dsl.ChunkingConfig{MaxFiles: 1},
+	}
+
+	buildCtx := &prompt.BuildContext{
+		RepoPath:     "/tmp/test-repo",
+		RepoURL:      "https://github.com/test/repo",
+		Ref:          "main",
+		CommitSHA:    "abc123",
+		ChangedFiles: []string{"a.go", "b.go", "c.go"},
+	}
+
+	reviewRule := &model.ReviewRule{
+		ReviewID: review.ID,
+		RuleID:   rule.ID,
+		Status:   model.RuleStatusPending,
+	}
+	require.NoError(t, testStore.Review().CreateRule(reviewRule))
+
+	result, err := executor.ExecuteRule(context.Background(), rule, buildCtx, reviewRule, 0)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	// 3 chunk calls + 1 merge call
+	assert.Equal(t, 4, mockAgent.execCount)
+	// Merge output is not JSON, so findings are concatenated and deduplicated
+	assert.Len(t, result.Data["findings"], 1)
+
+	chunks, err := testStore.Review().GetChunksByRuleID(reviewRule.ID)
+	require.NoError(t, err)
+	require.Len(t, chunks, 3)
+	for i, c := range chunks {
+		assert.Equal(t, i, c.ChunkIndex)
+		assert.Equal(t, model.RunStatusCompleted, c.Status)
+		assert.Equal(t, buildCtx.ChangedFiles[i], c.Files)
+		assert.Equal(t, 1, c.FindingsCount)
+	}
+
+	updatedRule, err := testStore.Review().GetRuleByID(reviewRule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, updatedRule.ChunkCount)
+	assert.Equal(t, model.RuleStatusCompleted, updatedRule.Status)
+
+	// Re-running reuses completed chunks and only performs the merge
+	mockAgent.execCount = 0
+	_, err = executor.ExecuteRule(context.Background(), rule, buildCtx, reviewRule, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, mockAgent.execCount)
+}
@@ -0,0 +1,41 @@
+package executor
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/engine/contextprovider"
+	"github.com/verustcode/verustcode/internal/prompt"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// withProviderContext returns a copy of buildCtx with the outputs of the rule's context providers.
+// Returns buildCtx unchanged if the rule has no context providers or there is no workspace.
+func (e *Executor) withProviderContext(ctx context.Context, rule *dsl.ReviewRuleConfig, buildCtx *prompt.BuildContext) *prompt.BuildContext {
+	if len(rule.Context) == 0 || buildCtx == nil || buildCtx.RepoPath == "" {
+		return buildCtx
+	}
+
+	env := &contextprovider.Env{
+		RepoPath:     buildCtx.RepoPath,
+		BaseCommit:   buildCtx.BaseCommitSHA,
+		HeadCommit:   buildCtx.CommitSHA,
+		ChangedFiles: buildCtx.ChangedFiles,
+	}
+	if reviewCfg := e.getReviewConfig(); reviewCfg != nil {
+		env.AllowedCommands = reviewCfg.ContextCommands
+		env.Sandbox = reviewCfg.GetContextSandbox()
+	}
+
+	withContext := *buildCtx
+	withContext.ExtraContext = contextprovider.Collect(ctx, rule.Context, env)
+
+	logger.Info("Collected rule context",
+		zap.String("rule_id", rule.ID),
+		zap.Int("providers", len(rule.Context)),
+		zap.Int("items", len(withContext.ExtraContext)),
+	)
+	return &withContext
+}
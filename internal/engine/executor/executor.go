@@ -5,6 +5,7 @@ package executor
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -12,6 +13,7 @@ import (
 	"github.com/verustcode/verustcode/internal/agent/base"
 	"github.com/verustcode/verustcode/internal/config"
 	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/engine/breaker"
 	"github.com/verustcode/verustcode/internal/engine/utils"
 	"github.com/verustcode/verustcode/internal/llm"
 	"github.com/verustcode/verustcode/internal/model"
@@ -38,6 +40,7 @@ type Executor struct {
 	agents         map[string]base.Agent
 	promptBuilder  *prompt.Builder
 	store          store.Store
+	breakers       *breaker.Registry
 }
 
 // NewExecutor creates a new Executor instance.
@@ -48,9 +51,21 @@ func NewExecutor(cfg *config.Config, agents map[string]base.Agent, promptBuilder
 		agents:         agents,
 		promptBuilder:  promptBuilder,
 		store:          s,
+		breakers:       breaker.NewRegistry(),
 	}
 }
 
+// Breakers returns the per-agent circuit breaker registry.
+func (e *Executor) Breakers() *breaker.Registry {
+	return e.breakers
+}
+
+// Agent returns the configured agent with the given name.
+func (e *Executor) Agent(name string) (base.Agent, bool) {
+	agent, ok := e.agents[name]
+	return agent, ok
+}
+
 // getReviewConfig retrieves review configuration from database with fallback to cached config.
 func (e *Executor) getReviewConfig() *config.ReviewConfig {
 	if e.configProvider != nil {
@@ -99,14 +114,20 @@ func (e *Executor) ExecuteRule(ctx context.Context, rule *dsl.ReviewRuleConfig,
 				zap.Error(err),
 			)
 		}
+
+		// Record the rule's agent calls afresh (a retried rule replaces its earlier recording)
+		e.resetRecording(reviewRule)
+		ctx = withCassetteRun(ctx, reviewRule.ReviewID, 0)
 	}
 
+	// Attach the outputs of the rule's context providers (files, command output, diff, git log)
+	buildCtx = e.withProviderContext(ctx, rule, buildCtx)
+
 	// Build prompt spec from DSL
 	spec := e.promptBuilder.Build(rule, buildCtx)
 
-	// Render prompt
-	renderer := prompt.NewRenderer()
-	promptText, err := renderer.Render(spec)
+	// Render prompt (with the rule's custom prompt template, if any) and format instructions
+	promptText, err := RenderPrompt(rule, spec, buildCtx)
 	if err != nil {
 		renderErr := errors.Wrap(errors.ErrCodeInternal, "failed to render prompt", err)
 		if reviewRule != nil {
@@ -115,12 +136,6 @@ func (e *Executor) ExecuteRule(ctx context.Context, rule *dsl.ReviewRuleConfig,
 		return nil, renderErr
 	}
 
-	// Add format instructions based on output schema configuration
-	formatInstructions := BuildFormatInstructions(rule, buildCtx)
-	if formatInstructions != "" {
-		promptText = promptText + formatInstructions
-	}
-
 	// Save complete prompt to reviewRule if available
 	if reviewRule != nil {
 		reviewRule.Prompt = promptText
@@ -176,11 +191,32 @@ func (e *Executor) ExecuteRule(ctx context.Context, rule *dsl.ReviewRuleConfig,
 		return nil, agentErr
 	}
 
+	// Enforce the repository's monthly budget (may downgrade the rule's models)
+	rule, err = e.applyBudget(rule, buildCtx.RepoURL)
+	if err != nil {
+		if reviewRule != nil {
+			e.UpdateReviewRuleAfterExecution(reviewRule, nil, err, nil)
+		}
+		return nil, err
+	}
+
 	// Get metadata config for appending to summary (from database)
 	var metadataConfig *config.OutputMetadataConfig
 	reviewCfg := e.getReviewConfig()
 	if reviewCfg != nil {
 		metadataConfig = &reviewCfg.OutputMetadata
+		e.breakers.Configure(reviewCfg.CircuitBreaker)
+	}
+
+	// Split large change sets into chunks (takes precedence over multi-run)
+	if rule.Chunking.IsEnabled() {
+		if chunks := PlanChunks(resolveChunkFiles(ctx, buildCtx), rule.Chunking); len(chunks) > 1 {
+			result, err := e.executeChunked(ctx, rule, buildCtx, agent, reviewRule, chunks)
+			if reviewRule != nil {
+				e.UpdateReviewRuleAfterExecution(reviewRule, result, err, metadataConfig)
+			}
+			return result, err
+		}
 	}
 
 	// Check if multi-run is enabled (automatically enabled when runs >= 2)
@@ -213,6 +249,7 @@ func (e *Executor) executeSingleRun(ctx context.Context, rule *dsl.ReviewRuleCon
 		RequestID:    idgen.NewRequestID(),
 		RuleID:       rule.ID,
 		ReviewID:     reviewID,
+		Model:        rule.Agent.Model, // empty uses the agent's default model
 		RepoPath:     buildCtx.RepoPath,
 		RepoURL:      buildCtx.RepoURL,
 		Ref:          buildCtx.Ref,
@@ -223,6 +260,16 @@ func (e *Executor) executeSingleRun(ctx context.Context, rule *dsl.ReviewRuleCon
 		ChangedFiles: buildCtx.ChangedFiles,
 	}
 
+	// Identical executions (e.g. retries of the same commit) reuse a cached result
+	cacheKey := ""
+	cacheTTL := e.responseCacheTTL(rule, req)
+	if cacheTTL > 0 {
+		cacheKey = ResponseCacheKey(rule, agent.Name(), e.effectiveModel(agent.Name(), req.Model), req.CommitSHA, promptText)
+		if cached := e.lookupResponseCache(ctx, rule, req, agent.Name(), cacheKey); cached != nil {
+			return cached, nil
+		}
+	}
+
 	// Get retry configuration from database
 	singleRunReviewCfg := e.getReviewConfig()
 	maxRetries := DefaultMaxRetries
@@ -238,6 +285,7 @@ func (e *Executor) executeSingleRun(ctx context.Context, rule *dsl.ReviewRuleCon
 
 	// Execute agent with the prompt (with retry logic)
 	var agentResult *base.ReviewResult
+	var producer base.Agent
 	var lastErr error
 
 	for attempt := 0; attempt <= maxRetries; attempt++ {
@@ -263,7 +311,7 @@ func (e *Executor) executeSingleRun(ctx context.Context, rule *dsl.ReviewRuleCon
 			}
 		}
 
-		agentResult, lastErr = agent.ExecuteWithPrompt(ctx, req, promptText)
+		agentResult, producer, lastErr = e.callAgent(ctx, rule, agent, req, promptText)
 		if lastErr == nil {
 			break
 		}
@@ -291,6 +339,9 @@ func (e *Executor) executeSingleRun(ctx context.Context, rule *dsl.ReviewRuleCon
 			fmt.Sprintf("review rule %s failed after %d retries", rule.ID, maxRetries), lastErr)
 	}
 
+	// Validate the output against the rule's output schema (repairs or salvages invalid output)
+	validation := e.validateOutput(ctx, rule, buildCtx, req, agentResult)
+
 	// Convert agent result to prompt.ReviewResult
 	// Data contains the complete AI response (JSON Schema mode)
 	// Text contains the raw text output (Markdown mode)
@@ -298,6 +349,8 @@ func (e *Executor) executeSingleRun(ctx context.Context, rule *dsl.ReviewRuleCon
 	result.Text = agentResult.Text
 	result.AgentName = agentResult.AgentName
 	result.ModelName = agentResult.ModelName
+	result.Usage = agentResult.Usage
+	result.Validation = validation
 
 	// If agent returned Data directly, use it
 	// Otherwise, try to extract JSON from Text (AI returns JSON in markdown code block)
@@ -310,11 +363,21 @@ func (e *Executor) executeSingleRun(ctx context.Context, rule *dsl.ReviewRuleCon
 		}
 	}
 
+	if cacheKey != "" {
+		if producer.Name() != agent.Name() {
+			// A fallback agent ran with its default model: cache the result under its own key,
+			// so it is never served as the primary agent's result
+			cacheKey = ResponseCacheKey(rule, producer.Name(), e.effectiveModel(producer.Name(), ""), req.CommitSHA, promptText)
+		}
+		e.storeResponseCache(rule, req, cacheKey, cacheTTL, result)
+	}
+
 	return result, nil
 }
 
 // UpdateReviewRuleAfterExecution updates review rule record after execution.
 // Results are stored in ReviewResult.Data, not in ReviewRule.Summary.
+// Token usage is added to the rule's usage, so usage of earlier attempts is kept.
 func (e *Executor) UpdateReviewRuleAfterExecution(reviewRule *model.ReviewRule, result *prompt.ReviewResult, err error, _ *config.OutputMetadataConfig) {
 	now := time.Now()
 
@@ -330,6 +393,21 @@ func (e *Executor) UpdateReviewRuleAfterExecution(reviewRule *model.ReviewRule,
 		}
 	}
 
+	if result != nil {
+		if result.ModelName != "" {
+			reviewRule.Model = result.ModelName
+		}
+		reviewRule.TokenUsage.Add(tokenUsage(result.Usage))
+		reviewRule.Cached = result.Cached
+
+		if result.Validation != nil {
+			reviewRule.OutputValidation = result.Validation.Status
+			reviewRule.OutputRepairs = result.Validation.Repairs
+			reviewRule.OutputDropped = result.Validation.Dropped
+			reviewRule.OutputErrors = strings.Join(result.Validation.Errors, "\n")
+		}
+	}
+
 	reviewRule.CompletedAt = &now
 	if reviewRule.StartedAt != nil {
 		reviewRule.Duration = now.Sub(*reviewRule.StartedAt).Milliseconds()
@@ -545,7 +545,7 @@ func TestMergeReviewResults(t *testing.T) {
 
 	t.Run("no results", func(t *testing.T) {
 		results := []task.RunResult{}
-		_, err := executor.mergeReviewResults(context.Background(), rule, mockAgent, results)
+		_, _, err := executor.mergeReviewResults(context.Background(), rule, mockAgent, results)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "no results to merge")
 	})
@@ -554,7 +554,7 @@ func TestMergeReviewResults(t *testing.T) {
 		results := []task.RunResult{
 			{Index: 1, Model: "model-1", Text: "Test summary 1"},
 		}
-		merged, err := executor.mergeReviewResults(context.Background(), rule, mockAgent, results)
+		merged, _, err := executor.mergeReviewResults(context.Background(), rule, mockAgent, results)
 		require.NoError(t, err)
 		assert.Equal(t, "Test summary 1", merged)
 	})
@@ -564,9 +564,51 @@ func TestMergeReviewResults(t *testing.T) {
 			{Index: 1, Model: "model-1", Text: "Test summary 1"},
 			{Index: 2, Model: "model-2", Text: "Test summary 2"},
 		}
-		merged, err := executor.mergeReviewResults(context.Background(), rule, mockAgent, results)
+		merged, _, err := executor.mergeReviewResults(context.Background(), rule, mockAgent, results)
 		require.NoError(t, err)
 		assert.NotEmpty(t, merged)
 		// Mock agent will return a response containing the merge prompt
 	})
 }
+
+func TestCallAgent_Failover(t *testing.T) {
+	testStore, cleanup := store.SetupTestDB(t)
+	defer cleanup()
+
+	primary := newMockAgent("primary")
+	primary.setFailure(100)
+	backup := newMockAgent("backup")
+	agents := map[string]base.Agent{"primary": primary, "backup": backup}
+
+	executor := NewExecutor(&config.Config{}, agents, prompt.NewBuilder(), testStore)
+	executor.Breakers().Configure(config.CircuitBreakerConfig{ConsecutiveFailures: 2})
+
+	rule := &dsl.ReviewRuleConfig{
+		ID:    "test-failover",
+		Agent: dsl.AgentConfig{Type: "primary", Model: "primary-model", Fallback: []string{"unknown", "backup"}},
+	}
+	req := &base.ReviewRequest{RuleID: rule.ID, Model: "primary-model"}
+
+	// Primary is used while its breaker is closed
+	for i := 0; i < 2; i++ {
+		_, _, err := executor.callAgent(context.Background(), rule, primary, req, "prompt")
+		require.Error(t, err)
+	}
+	assert.Equal(t, 2, primary.execCount)
+
+	// Breaker is open, so the fallback agent is used with its default model
+	result, used, err := executor.callAgent(context.Background(), rule, primary, req, "prompt")
+	require.NoError(t, err)
+	assert.Equal(t, "backup", result.AgentName)
+	assert.Equal(t, "backup", used.Name())
+	assert.Empty(t, result.ModelName)
+	assert.Equal(t, 2, primary.execCount)
+	assert.Equal(t, 1, backup.execCount)
+
+	// Without fallback agents, an open breaker yields a retryable error
+	noFallback := &dsl.ReviewRuleConfig{ID: "no-fallback", Agent: dsl.AgentConfig{Type: "primary"}}
+	_, _, err = executor.callAgent(context.Background(), noFallback, primary, req, "prompt")
+	require.Error(t, err)
+	assert.True(t, llm.IsRetryable(err))
+	assert.Equal(t, 2, primary.execCount)
+}
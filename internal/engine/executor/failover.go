@@ -0,0 +1,101 @@
+// Package executor handles review rule execution.
+// This file contains agent failover based on per-agent circuit breakers.
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/llm"
+	"github.com/verustcode/verustcode/pkg/logger"
+	"github.com/verustcode/verustcode/pkg/telemetry"
+)
+
+// agentChain returns the primary agent followed by the rule's configured fallback agents.
+// Fallback agents that are not configured or not available are skipped.
+func (e *Executor) agentChain(rule *dsl.ReviewRuleConfig, primary base.Agent) []base.Agent {
+	chain := []base.Agent{primary}
+	for _, name := range rule.Agent.Fallback {
+		if name == primary.Name() {
+			continue
+		}
+		agent, ok := e.agents[name]
+		if !ok || !agent.Available() {
+			logger.Debug("Skipping unavailable fallback agent",
+				zap.String("rule_id", rule.ID),
+				zap.String("agent", name),
+			)
+			continue
+		}
+		chain = append(chain, agent)
+	}
+	return chain
+}
+
+// callAgent executes the prompt on the first agent in the rule's agent chain whose
+// circuit breaker allows the call, and records the outcome on that breaker.
+// Fallback agents run with their default model since models are agent specific.
+// If every breaker is open, a retryable error is returned so the caller's retry
+// loop waits and tries again once a breaker allows a probe.
+// Also returns the agent that was called.
+func (e *Executor) callAgent(ctx context.Context, rule *dsl.ReviewRuleConfig, primary base.Agent, req *base.ReviewRequest, promptText string) (*base.ReviewResult, base.Agent, error) {
+	chain := e.agentChain(rule, primary)
+
+	for i, agent := range chain {
+		br := e.breakers.Get(agent.Name())
+		if !br.Allow() {
+			logger.Warn("Agent circuit breaker is open, skipping agent",
+				zap.String("review_id", req.ReviewID),
+				zap.String("rule_id", rule.ID),
+				zap.String("agent", agent.Name()),
+			)
+			continue
+		}
+
+		agentReq := req
+		if i > 0 {
+			logger.Info("Failing over to fallback agent",
+				zap.String("review_id", req.ReviewID),
+				zap.String("rule_id", rule.ID),
+				zap.String("primary_agent", primary.Name()),
+				zap.String("fallback_agent", agent.Name()),
+			)
+			fallbackReq := *req
+			fallbackReq.Model = ""
+			agentReq = &fallbackReq
+		}
+
+		result, err := e.invokeAgent(ctx, agent, agentReq, promptText)
+		return result, agent, err
+	}
+
+	return nil, nil, llm.NewRetryableError(primary.Name(), "execute",
+		fmt.Sprintf("circuit breaker is open for all %d agents of rule %s", len(chain), rule.ID), nil)
+}
+
+// invokeAgent executes the prompt on agent and records the outcome on its circuit breaker
+// (and the interaction in the review's cassette if recording is enabled).
+// The caller checks that the breaker allows the call.
+func (e *Executor) invokeAgent(ctx context.Context, agent base.Agent, req *base.ReviewRequest, promptText string) (*base.ReviewResult, error) {
+	br := e.breakers.Get(agent.Name())
+	ctx, rec := e.startRecording(ctx, req)
+	result, err := agent.ExecuteWithPrompt(ctx, req, promptText)
+	if err == nil {
+		e.priceUsage(result, req.Model)
+	}
+	if rec != nil {
+		e.finishRecording(rec, agent, req, promptText, result, err)
+	}
+	if err != nil && ctx.Err() != nil {
+		// Cancelled calls say nothing about the agent's health
+		br.Skip()
+		return result, err
+	}
+	br.Record(err)
+	telemetry.GetMetrics().RecordAgentExecution(ctx, agent.Name(), err == nil)
+	return result, err
+}
@@ -11,6 +11,20 @@ import (
 	"github.com/verustcode/verustcode/pkg/logger"
 )
 
+// RenderPrompt renders the complete prompt of a rule: the spec rendered with the
+// rule's prompt template (or the built-in one), followed by the format instructions.
+func RenderPrompt(rule *dsl.ReviewRuleConfig, spec *prompt.Spec, buildCtx *prompt.BuildContext) (string, error) {
+	renderer, err := prompt.NewRendererForRule(rule)
+	if err != nil {
+		return "", err
+	}
+	promptText, err := renderer.RenderWithContext(spec, buildCtx)
+	if err != nil {
+		return "", err
+	}
+	return promptText + BuildFormatInstructions(rule, buildCtx), nil
+}
+
 // BuildFormatInstructions generates format instructions for LLM output.
 // LLM always returns JSON structured data; output channels handle format conversion.
 func BuildFormatInstructions(rule *dsl.ReviewRuleConfig, buildCtx *prompt.BuildContext) string {
@@ -14,6 +14,7 @@ import (
 	"github.com/verustcode/verustcode/internal/agent/base"
 	"github.com/verustcode/verustcode/internal/dsl"
 	"github.com/verustcode/verustcode/internal/engine/task"
+	"github.com/verustcode/verustcode/internal/llm"
 	"github.com/verustcode/verustcode/internal/model"
 	"github.com/verustcode/verustcode/internal/prompt"
 	"github.com/verustcode/verustcode/pkg/errors"
@@ -92,7 +93,7 @@ func (e *Executor) executeMultiRun(ctx context.Context, rule *dsl.ReviewRuleConf
 				}
 			}
 
-			agentResult, lastErr = agent.ExecuteWithPrompt(ctx, req, promptText)
+			agentResult, _, lastErr = e.callAgent(ctx, rule, agent, req, promptText)
 			if lastErr == nil {
 				break
 			}
@@ -109,6 +110,7 @@ func (e *Executor) executeMultiRun(ctx context.Context, rule *dsl.ReviewRuleConf
 			return nil, errors.Wrap(errors.ErrCodeAgentExecution,
 				fmt.Sprintf("single run failed after %d retries", maxRetries), lastErr)
 		}
+		validation := e.validateOutput(ctx, rule, buildCtx, req, agentResult)
 
 		// Convert agent result to prompt.ReviewResult
 		// Data contains the complete AI response (JSON Schema mode)
@@ -116,6 +118,9 @@ func (e *Executor) executeMultiRun(ctx context.Context, rule *dsl.ReviewRuleConf
 		result := prompt.NewReviewResult(rule.ID)
 		result.Data = agentResult.Data
 		result.Text = agentResult.Text
+		result.ModelName = agentResult.ModelName
+		result.Usage = agentResult.Usage
+		result.Validation = validation
 
 		return result, nil
 	}
@@ -285,7 +290,7 @@ func (e *Executor) executeMultiRun(ctx context.Context, rule *dsl.ReviewRuleConf
 				}
 			}
 
-			agentResult, lastErr = agent.ExecuteWithPrompt(ctx, req, promptText)
+			agentResult, _, lastErr = e.callAgent(withCassetteRunIndex(ctx, i), rule, agent, req, promptText)
 			if lastErr == nil {
 				break
 			}
@@ -299,6 +304,12 @@ func (e *Executor) executeMultiRun(ctx context.Context, rule *dsl.ReviewRuleConf
 			)
 		}
 
+		// Validate the run's output against the rule's output schema
+		var validation *model.OutputValidation
+		if lastErr == nil {
+			validation = e.validateOutput(ctx, rule, buildCtx, req, agentResult)
+		}
+
 		duration := time.Since(startTime)
 
 		// Update ReviewRuleRun record
@@ -316,6 +327,11 @@ func (e *Executor) executeMultiRun(ctx context.Context, rule *dsl.ReviewRuleConf
 				if findings, ok := agentResult.Data["findings"].([]interface{}); ok {
 					ruleRun.FindingsCount = len(findings)
 				}
+				// Record the model reported by the agent and the run's usage
+				if agentResult.ModelName != "" {
+					ruleRun.Model = agentResult.ModelName
+				}
+				ruleRun.TokenUsage = tokenUsage(agentResult.Usage)
 			}
 
 			if err := e.store.Review().UpdateRun(ruleRun); err != nil {
@@ -354,12 +370,14 @@ func (e *Executor) executeMultiRun(ctx context.Context, rule *dsl.ReviewRuleConf
 		)
 
 		results = append(results, task.RunResult{
-			Index:    i + 1,
-			Model:    modelName,
-			Data:     agentResult.Data,
-			Text:     agentResult.Text,
-			Duration: duration,
-			Err:      nil,
+			Index:      i + 1,
+			Model:      modelName,
+			Data:       agentResult.Data,
+			Text:       agentResult.Text,
+			Duration:   duration,
+			Usage:      agentResult.Usage,
+			Validation: validation,
+			Err:        nil,
 		})
 	}
 
@@ -384,7 +402,7 @@ func (e *Executor) executeMultiRun(ctx context.Context, rule *dsl.ReviewRuleConf
 	)
 
 	// Merge results using LLM
-	mergedText, err := e.mergeReviewResults(ctx, rule, agent, successfulResults)
+	mergedText, mergeUsage, err := e.mergeReviewResults(ctx, rule, agent, successfulResults)
 	if err != nil {
 		logger.Error("Failed to merge review results",
 			zap.String("review_id", reviewRule.ReviewID),
@@ -399,26 +417,34 @@ func (e *Executor) executeMultiRun(ctx context.Context, rule *dsl.ReviewRuleConf
 	result := prompt.NewReviewResult(rule.ID)
 	// Store merged text as the complete AI response
 	result.Text = mergedText
-	// Try to parse merged text as JSON for Data
+	// Usage covers all runs executed now and the merge
+	for _, r := range results {
+		addUsage(&result.Usage, r.Usage)
+	}
+	addUsage(&result.Usage, mergeUsage)
+	// Try to parse merged text as JSON for Data, keeping only valid findings
+	var mergeValidation *model.OutputValidation
 	if mergedText != "" {
 		var parsed map[string]interface{}
 		if parseErr := json.Unmarshal([]byte(mergedText), &parsed); parseErr == nil {
-			result.Data = parsed
+			result.Data, mergeValidation = salvageMergedOutput(rule, parsed)
 		}
 	}
+	result.Validation = mergeValidations(results, mergeValidation)
 
 	return result, nil
 }
 
-// mergeReviewResults merges multiple review results using LLM
-func (e *Executor) mergeReviewResults(ctx context.Context, rule *dsl.ReviewRuleConfig, agent base.Agent, results []task.RunResult) (string, error) {
+// mergeReviewResults merges multiple review results using LLM.
+// Returns the merged text and the token usage of the merge call.
+func (e *Executor) mergeReviewResults(ctx context.Context, rule *dsl.ReviewRuleConfig, agent base.Agent, results []task.RunResult) (string, *llm.Usage, error) {
 	if len(results) == 0 {
-		return "", errors.New(errors.ErrCodeInternal, "no results to merge")
+		return "", nil, errors.New(errors.ErrCodeInternal, "no results to merge")
 	}
 
 	if len(results) == 1 {
 		// No need to merge if only one result
-		return results[0].Text, nil
+		return results[0].Text, nil, nil
 	}
 
 	// Build merge prompt using XML format to avoid conflicts with markdown review results
@@ -453,12 +479,8 @@ func (e *Executor) mergeReviewResults(ctx context.Context, rule *dsl.ReviewRuleC
 	mergePrompt.WriteString("Output the merged review result in markdown format below:\n")
 	mergePrompt.WriteString("</output_format>\n")
 
-	// Determine merge model
-	mergeModel := rule.MultiRun.MergeModel
-	if mergeModel == "" {
-		// Use agent's default model (empty string means use default)
-		mergeModel = ""
-	}
+	// Determine merge model (empty string means use agent's default model)
+	mergeModel := mergeModelFor(rule)
 
 	logger.Info("Merging review results with LLM",
 		zap.String("rule_id", rule.ID),
@@ -489,9 +511,9 @@ func (e *Executor) mergeReviewResults(ctx context.Context, rule *dsl.ReviewRuleC
 	}
 
 	startTime := time.Now()
-	mergeResult, err := agent.ExecuteWithPrompt(ctx, mergeReq, mergePromptText)
+	mergeResult, _, err := e.callAgent(ctx, rule, agent, mergeReq, mergePromptText)
 	if err != nil {
-		return "", errors.Wrap(errors.ErrCodeAgentExecution, "failed to merge review results", err)
+		return "", nil, errors.Wrap(errors.ErrCodeAgentExecution, "failed to merge review results", err)
 	}
 
 	duration := time.Since(startTime)
@@ -501,5 +523,17 @@ func (e *Executor) mergeReviewResults(ctx context.Context, rule *dsl.ReviewRuleC
 		zap.Int("merged_text_length", len(mergeResult.Text)),
 	)
 
-	return mergeResult.Text, nil
+	return mergeResult.Text, mergeResult.Usage, nil
+}
+
+// mergeModelFor returns the model used to merge results for a rule.
+// Chunking takes precedence over multi-run since a chunked rule never fans out into multiple runs.
+func mergeModelFor(rule *dsl.ReviewRuleConfig) string {
+	if rule.Chunking.IsEnabled() && rule.Chunking.MergeModel != "" {
+		return rule.Chunking.MergeModel
+	}
+	if rule.MultiRun != nil {
+		return rule.MultiRun.MergeModel
+	}
+	return ""
 }
@@ -0,0 +1,118 @@
+// Package executor handles review rule execution.
+// This file contains token usage pricing and monthly repository budgets.
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/engine/utils"
+	"github.com/verustcode/verustcode/internal/llm"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/pkg/errors"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// priceUsage sets the cost of the result's usage from the configured price table.
+// The model reported by the agent is priced; requestModel is used if the agent reports none.
+func (e *Executor) priceUsage(result *base.ReviewResult, requestModel string) {
+	if result == nil || result.Usage == nil {
+		return
+	}
+	reviewCfg := e.getReviewConfig()
+	if reviewCfg == nil {
+		return
+	}
+
+	modelName := result.ModelName
+	if modelName == "" {
+		modelName = requestModel
+	}
+	price, ok := config.PriceFor(reviewCfg.ModelPrices, modelName)
+	if !ok {
+		return
+	}
+	result.Usage.Cost = price.Cost(result.Usage.PromptTokens, result.Usage.CompletionTokens, result.Usage.CachedTokens)
+}
+
+// addUsage adds usage to total, allocating total on first use
+func addUsage(total **llm.Usage, usage *llm.Usage) {
+	if usage == nil {
+		return
+	}
+	if *total == nil {
+		*total = &llm.Usage{}
+	}
+	(*total).Add(usage)
+}
+
+// tokenUsage converts usage reported by an agent into its stored form
+func tokenUsage(usage *llm.Usage) model.TokenUsage {
+	if usage == nil {
+		return model.TokenUsage{}
+	}
+	return model.TokenUsage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		CachedTokens:     usage.CachedTokens,
+		Cost:             usage.Cost,
+	}
+}
+
+// applyBudget enforces the monthly budget of the reviewed repository.
+// Within budget, rule is returned unchanged. Once the budget is reached, a budget
+// exceeded error is returned, or with the downgrade action a copy of the rule that
+// runs every agent call (including merges) with the repository's budget model.
+func (e *Executor) applyBudget(rule *dsl.ReviewRuleConfig, repoURL string) (*dsl.ReviewRuleConfig, error) {
+	if repoURL == "" {
+		return rule, nil
+	}
+	repoConfig, err := e.store.RepositoryConfig().GetByRepoURL(repoURL)
+	if err != nil || repoConfig == nil || repoConfig.MonthlyBudget <= 0 {
+		return rule, nil
+	}
+
+	spent, err := utils.SpentSince(e.store, repoURL, utils.MonthStart(time.Now()))
+	if err != nil {
+		logger.Warn("Failed to load repository usage, skipping budget check",
+			zap.String("repo_url", repoURL),
+			zap.Error(err),
+		)
+		return rule, nil
+	}
+	if !utils.BudgetExceeded(repoConfig, spent) {
+		return rule, nil
+	}
+
+	if utils.BudgetActionOf(repoConfig) == model.BudgetActionBlock {
+		return nil, errors.New(errors.ErrCodeBudgetExceeded,
+			fmt.Sprintf("monthly budget of %s exceeded (spent $%.2f of $%.2f)", repoURL, spent, repoConfig.MonthlyBudget))
+	}
+
+	logger.Warn("Monthly budget exceeded, downgrading rule model",
+		zap.String("repo_url", repoURL),
+		zap.String("rule_id", rule.ID),
+		zap.String("model", repoConfig.BudgetModel),
+		zap.Float64("spent", spent),
+		zap.Float64("budget", repoConfig.MonthlyBudget),
+	)
+	downgraded := *rule
+	downgraded.Agent.Model = repoConfig.BudgetModel
+	if rule.MultiRun != nil {
+		multiRun := *rule.MultiRun
+		multiRun.Models = []string{repoConfig.BudgetModel}
+		multiRun.MergeModel = repoConfig.BudgetModel
+		downgraded.MultiRun = &multiRun
+	}
+	if rule.Chunking != nil {
+		chunking := *rule.Chunking
+		chunking.MergeModel = repoConfig.BudgetModel
+		downgraded.Chunking = &chunking
+	}
+	return &downgraded, nil
+}
@@ -0,0 +1,304 @@
+// Package executor handles review rule execution.
+// This file contains validation and repair of structured agent output.
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/engine/task"
+	"github.com/verustcode/verustcode/internal/llm"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/prompt"
+	"github.com/verustcode/verustcode/pkg/idgen"
+	"github.com/verustcode/verustcode/pkg/logger"
+	"github.com/verustcode/verustcode/pkg/telemetry"
+)
+
+// maxRepairPromptErrors is the number of validation errors listed in a repair prompt
+const maxRepairPromptErrors = 20
+
+// outputSchema returns the JSON Schema agent output of the rule must match, or nil if the
+// rule has no structured output (no format instructions are sent without an output config)
+func outputSchema(rule *dsl.ReviewRuleConfig) map[string]interface{} {
+	if rule == nil || rule.Output == nil {
+		return nil
+	}
+	historyCompareEnabled := rule.HistoryCompare != nil && rule.HistoryCompare.Enabled
+	return dsl.BuildJSONSchemaWithOptions(rule.Output.Schema, historyCompareEnabled)
+}
+
+// parseOutput returns the structured data of an agent result: the data reported by
+// the agent, or the JSON extracted from its text
+func parseOutput(agentResult *base.ReviewResult) (map[string]interface{}, error) {
+	if len(agentResult.Data) > 0 {
+		return agentResult.Data, nil
+	}
+	var parsed map[string]interface{}
+	if err := llm.ParseResponseJSON(agentResult.Text, &parsed); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// checkOutput parses an agent result and validates it against schema.
+// Returns the parsed data (nil if there is no JSON object) and the validation errors.
+func checkOutput(agentResult *base.ReviewResult, schema map[string]interface{}) (map[string]interface{}, []string) {
+	data, err := parseOutput(agentResult)
+	if err != nil {
+		return nil, []string{"response is not a valid JSON object: " + err.Error()}
+	}
+	schemaErrs := llm.ValidateSchema(data, schema)
+	errs := make([]string, len(schemaErrs))
+	for i, schemaErr := range schemaErrs {
+		errs[i] = schemaErr.Error()
+	}
+	return data, errs
+}
+
+// validateOutput validates the structured output of an agent against the rule's JSON Schema.
+// Invalid output is sent back to the agent that produced it with the validation errors,
+// continuing its session if it keeps one, up to the configured number of repair prompts.
+// Output that is still invalid is salvaged: only the findings matching the schema are kept.
+// The agent result is updated in place (text, data and usage of the repair calls).
+// Returns nil if the rule has no structured output.
+func (e *Executor) validateOutput(ctx context.Context, rule *dsl.ReviewRuleConfig, buildCtx *prompt.BuildContext, req *base.ReviewRequest, agentResult *base.ReviewResult) *model.OutputValidation {
+	schema := outputSchema(rule)
+	if schema == nil || agentResult == nil {
+		return nil
+	}
+
+	data, errs := checkOutput(agentResult, schema)
+	validation := &model.OutputValidation{Status: model.OutputValid}
+	if len(errs) == 0 {
+		agentResult.Data = data
+		recordValidation(ctx, agentResult.AgentName, validation, 0)
+		return validation
+	}
+	validation.Errors = capErrors(errs)
+	errorCount := len(errs)
+
+	logger.Warn("Agent output does not match the output schema",
+		zap.String("review_id", req.ReviewID),
+		zap.String("rule_id", rule.ID),
+		zap.String("agent", agentResult.AgentName),
+		zap.Int("errors", len(errs)),
+		zap.String("first_error", errs[0]),
+	)
+
+	repairCfg := config.OutputRepairConfig{}
+	if reviewCfg := e.getReviewConfig(); reviewCfg != nil {
+		repairCfg = reviewCfg.OutputRepair
+	}
+	maxAttempts := repairCfg.GetMaxAttempts()
+
+	// The latest output that parsed as a JSON object is the salvage candidate
+	best := data
+	for attempt := 1; attempt <= maxAttempts && ctx.Err() == nil; attempt++ {
+		repaired, ok := e.repairOutput(ctx, rule, buildCtx, req, agentResult, errs)
+		if !ok {
+			break
+		}
+		validation.Repairs++
+
+		var repairedData map[string]interface{}
+		repairedData, errs = checkOutput(repaired, schema)
+		agentResult.Text = repaired.Text
+		agentResult.SessionID = repaired.SessionID
+		if len(errs) == 0 {
+			agentResult.Data = repairedData
+			validation.Status = model.OutputRepaired
+			logger.Info("Agent output repaired",
+				zap.String("review_id", req.ReviewID),
+				zap.String("rule_id", rule.ID),
+				zap.Int("attempt", attempt),
+			)
+			recordValidation(ctx, agentResult.AgentName, validation, errorCount)
+			return validation
+		}
+		if repairedData != nil {
+			best = repairedData
+		}
+		errorCount += len(errs)
+		logger.Warn("Repaired agent output is still invalid",
+			zap.String("review_id", req.ReviewID),
+			zap.String("rule_id", rule.ID),
+			zap.Int("attempt", attempt),
+			zap.Int("errors", len(errs)),
+			zap.String("first_error", errs[0]),
+		)
+	}
+
+	if best == nil {
+		validation.Status = model.OutputInvalid
+		recordValidation(ctx, agentResult.AgentName, validation, errorCount)
+		return validation
+	}
+
+	salvaged, dropped := salvageOutput(best, schema)
+	agentResult.Data = salvaged
+	if text, err := json.MarshalIndent(salvaged, "", "  "); err == nil {
+		agentResult.Text = string(text)
+	}
+	validation.Status = model.OutputSalvaged
+	validation.Dropped = dropped
+	logger.Warn("Salvaged invalid agent output",
+		zap.String("review_id", req.ReviewID),
+		zap.String("rule_id", rule.ID),
+		zap.Int("dropped_findings", dropped),
+	)
+	recordValidation(ctx, agentResult.AgentName, validation, errorCount)
+	return validation
+}
+
+// repairOutput sends the validation errors of an agent output back to the agent that produced it.
+// Returns false if the agent is unknown, its circuit breaker is open or the call failed.
+func (e *Executor) repairOutput(ctx context.Context, rule *dsl.ReviewRuleConfig, buildCtx *prompt.BuildContext, req *base.ReviewRequest, agentResult *base.ReviewResult, errs []string) (*base.ReviewResult, bool) {
+	agent, ok := e.agents[agentResult.AgentName]
+	if !ok || !e.breakers.Get(agent.Name()).Allow() {
+		return nil, false
+	}
+
+	repairReq := *req
+	repairReq.RequestID = idgen.NewRequestID()
+	repairReq.SessionID = agentResult.SessionID
+	if agent.Name() != rule.Agent.GetType() {
+		// Fallback agents run with their default model
+		repairReq.Model = ""
+	}
+
+	repairPrompt := BuildRepairPrompt(errs, agentResult.SessionID == "", agentResult.Text, BuildFormatInstructions(rule, buildCtx))
+	repaired, err := e.invokeAgent(ctx, agent, &repairReq, repairPrompt)
+	if err != nil {
+		logger.Warn("Output repair call failed",
+			zap.String("review_id", req.ReviewID),
+			zap.String("rule_id", rule.ID),
+			zap.String("agent", agent.Name()),
+			zap.Error(err),
+		)
+		return nil, false
+	}
+
+	addUsage(&agentResult.Usage, repaired.Usage)
+	return repaired, true
+}
+
+// BuildRepairPrompt builds the prompt asking an agent to fix output that violates the output schema.
+// Without a session the agent doesn't know its previous response and the format instructions,
+// so both are included.
+func BuildRepairPrompt(errs []string, standalone bool, previous, formatInstructions string) string {
+	var sb strings.Builder
+	sb.WriteString("Your previous response does not match the required JSON Schema:\n\n")
+	for i, err := range errs {
+		if i == maxRepairPromptErrors {
+			sb.WriteString(fmt.Sprintf("- ... and %d more errors\n", len(errs)-maxRepairPromptErrors))
+			break
+		}
+		sb.WriteString("- " + err + "\n")
+	}
+	sb.WriteString("\nReturn the corrected JSON object: keep every finding, fix the problems listed above, ")
+	sb.WriteString("and do not include any text before or after the JSON object. Do not review the code again.\n")
+
+	if standalone {
+		sb.WriteString("\n## Previous Response\n\n```\n")
+		sb.WriteString(previous)
+		sb.WriteString("\n```\n")
+		sb.WriteString(formatInstructions)
+	}
+	return sb.String()
+}
+
+// salvageOutput keeps the parts of invalid output that match the schema: findings that
+// violate the finding schema are dropped and a missing or invalid summary is emptied.
+// Returns the salvaged data and the number of dropped findings.
+func salvageOutput(data map[string]interface{}, schema map[string]interface{}) (map[string]interface{}, int) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	findingsSchema, _ := properties["findings"].(map[string]interface{})
+	itemSchema, _ := findingsSchema["items"].(map[string]interface{})
+
+	salvaged := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		salvaged[k] = v
+	}
+
+	if summary, ok := data["summary"].(string); ok {
+		salvaged["summary"] = summary
+	} else {
+		salvaged["summary"] = ""
+	}
+
+	findings, _ := data["findings"].([]interface{})
+	kept := make([]interface{}, 0, len(findings))
+	for _, finding := range findings {
+		if len(llm.ValidateSchema(finding, itemSchema)) == 0 {
+			kept = append(kept, finding)
+		}
+	}
+	salvaged["findings"] = kept
+
+	return salvaged, len(findings) - len(kept)
+}
+
+// salvageMergedOutput validates the output of a merge call (multi-run or chunk merge) and
+// keeps only its valid findings. Merges are not repaired since their inputs are already valid.
+func salvageMergedOutput(rule *dsl.ReviewRuleConfig, data map[string]interface{}) (map[string]interface{}, *model.OutputValidation) {
+	schema := outputSchema(rule)
+	if schema == nil || len(data) == 0 {
+		return data, nil
+	}
+
+	schemaErrs := llm.ValidateSchema(data, schema)
+	if len(schemaErrs) == 0 {
+		return data, &model.OutputValidation{Status: model.OutputValid}
+	}
+	errs := make([]string, len(schemaErrs))
+	for i, schemaErr := range schemaErrs {
+		errs[i] = "merge: " + schemaErr.Error()
+	}
+	salvaged, dropped := salvageOutput(data, schema)
+	return salvaged, &model.OutputValidation{
+		Status:  model.OutputSalvaged,
+		Dropped: dropped,
+		Errors:  capErrors(errs),
+	}
+}
+
+// mergeValidations combines the validations of the runs or chunks of a rule and of their merge.
+// Returns nil if none of them was validated.
+func mergeValidations(results []task.RunResult, mergeValidation *model.OutputValidation) *model.OutputValidation {
+	var merged *model.OutputValidation
+	add := func(v *model.OutputValidation) {
+		if v == nil {
+			return
+		}
+		if merged == nil {
+			merged = &model.OutputValidation{}
+		}
+		merged.Merge(v)
+	}
+	for _, r := range results {
+		add(r.Validation)
+	}
+	add(mergeValidation)
+	return merged
+}
+
+// recordValidation records the outcome of an output validation in the metrics
+func recordValidation(ctx context.Context, agentName string, validation *model.OutputValidation, errorCount int) {
+	telemetry.GetMetrics().RecordOutputValidation(ctx, agentName, string(validation.Status), errorCount)
+}
+
+// capErrors returns at most model.MaxOutputValidationErrors errors
+func capErrors(errs []string) []string {
+	if len(errs) > model.MaxOutputValidationErrors {
+		return errs[:model.MaxOutputValidationErrors]
+	}
+	return errs
+}
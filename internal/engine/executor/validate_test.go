@@ -0,0 +1,189 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/prompt"
+	"github.com/verustcode/verustcode/internal/store"
+)
+
+// scriptedAgent returns its outputs in order and records the requests it receives
+type scriptedAgent struct {
+	mockAgent
+	outputs   []string
+	sessionID string // session reported with every output (empty = no session)
+	prompts   []string
+	sessions  []string
+}
+
+func (a *scriptedAgent) ExecuteWithPrompt(ctx context.Context, req *base.ReviewRequest, promptText string) (*base.ReviewResult, error) {
+	a.prompts = append(a.prompts, promptText)
+	a.sessions = append(a.sessions, req.SessionID)
+	text := a.outputs[len(a.prompts)-1]
+	return &base.ReviewResult{AgentName: a.name, Text: text, SessionID: a.sessionID, Success: true}, nil
+}
+
+const (
+	validOutput   = `{"summary":"ok","findings":[{"severity":"high","title":"SQL injection","description":"d","category":"security"}]}`
+	invalidOutput = `{"summary":"ok","findings":[{"severity":"urgent","title":"t","description":"d"},{"severity":"low","title":"t2","description":"d2"}]}`
+)
+
+func newValidationTest(t *testing.T, agent *scriptedAgent, repair config.OutputRepairConfig) (*Executor, *dsl.ReviewRuleConfig) {
+	testStore, cleanup := store.SetupTestDB(t)
+	t.Cleanup(cleanup)
+
+	settings := map[string]interface{}{"output_repair": repair}
+	require.NoError(t, config.NewSettingsService(testStore).SetCategory(string(model.SettingCategoryReview), settings, "test"))
+	executor := NewExecutor(&config.Config{}, map[string]base.Agent{agent.name: agent}, prompt.NewBuilder(), testStore)
+	rule := &dsl.ReviewRuleConfig{
+		ID:     "validated",
+		Agent:  dsl.AgentConfig{Type: agent.name},
+		Output: &dsl.OutputConfig{},
+	}
+	return executor, rule
+}
+
+func runValidation(t *testing.T, executor *Executor, rule *dsl.ReviewRuleConfig, agent *scriptedAgent) (*base.ReviewResult, *model.OutputValidation) {
+	req := &base.ReviewRequest{RuleID: rule.ID}
+	result, err := agent.ExecuteWithPrompt(context.Background(), req, "review prompt")
+	require.NoError(t, err)
+	return result, executor.validateOutput(context.Background(), rule, &prompt.BuildContext{}, req, result)
+}
+
+func TestValidateOutput_Valid(t *testing.T) {
+	agent := &scriptedAgent{mockAgent: mockAgent{name: "scripted"}, outputs: []string{validOutput}}
+	executor, rule := newValidationTest(t, agent, config.OutputRepairConfig{})
+
+	result, validation := runValidation(t, executor, rule, agent)
+	require.NotNil(t, validation)
+	assert.Equal(t, model.OutputValid, validation.Status)
+	assert.Len(t, result.Data["findings"], 1)
+	assert.Len(t, agent.prompts, 1)
+}
+
+func TestValidateOutput_RepairedInSession(t *testing.T) {
+	agent := &scriptedAgent{
+		mockAgent: mockAgent{name: "scripted"},
+		outputs:   []string{invalidOutput, validOutput},
+		sessionID: "session-1",
+	}
+	executor, rule := newValidationTest(t, agent, config.OutputRepairConfig{})
+
+	result, validation := runValidation(t, executor, rule, agent)
+	require.NotNil(t, validation)
+	assert.Equal(t, model.OutputRepaired, validation.Status)
+	assert.Equal(t, 1, validation.Repairs)
+	assert.Equal(t, []string{`findings[0].severity: value "urgent" is not one of ["critical", "high", "medium", "low", "info"]`}, validation.Errors)
+	assert.Equal(t, validOutput, result.Text)
+
+	// The repair prompt continues the session and lists the errors without repeating the output
+	require.Len(t, agent.prompts, 2)
+	assert.Equal(t, "session-1", agent.sessions[1])
+	assert.Contains(t, agent.prompts[1], `findings[0].severity: value "urgent"`)
+	assert.NotContains(t, agent.prompts[1], "## Previous Response")
+}
+
+func TestValidateOutput_Salvaged(t *testing.T) {
+	agent := &scriptedAgent{
+		mockAgent: mockAgent{name: "scripted"},
+		outputs:   []string{invalidOutput, "not json", invalidOutput},
+	}
+	executor, rule := newValidationTest(t, agent, config.OutputRepairConfig{MaxAttempts: 2})
+
+	result, validation := runValidation(t, executor, rule, agent)
+	require.NotNil(t, validation)
+	assert.Equal(t, model.OutputSalvaged, validation.Status)
+	assert.Equal(t, 2, validation.Repairs)
+	assert.Equal(t, 1, validation.Dropped)
+
+	findings := result.Data["findings"].([]interface{})
+	require.Len(t, findings, 1)
+	assert.Equal(t, "t2", findings[0].(map[string]interface{})["title"])
+
+	// Without a session, repair prompts include the previous output and the format instructions
+	require.Len(t, agent.prompts, 3)
+	assert.Empty(t, agent.sessions[1])
+	assert.Contains(t, agent.prompts[1], "## Previous Response")
+	assert.Contains(t, agent.prompts[1], invalidOutput)
+	assert.Contains(t, agent.prompts[1], "## Output Format")
+	assert.Contains(t, agent.prompts[2], "not a valid JSON object")
+}
+
+func TestValidateOutput_Invalid(t *testing.T) {
+	agent := &scriptedAgent{mockAgent: mockAgent{name: "scripted"}, outputs: []string{"I found no issues."}}
+	executor, rule := newValidationTest(t, agent, config.OutputRepairConfig{Disabled: true})
+
+	result, validation := runValidation(t, executor, rule, agent)
+	require.NotNil(t, validation)
+	assert.Equal(t, model.OutputInvalid, validation.Status)
+	assert.Zero(t, validation.Repairs)
+	assert.Equal(t, "I found no issues.", result.Text)
+	assert.Len(t, agent.prompts, 1)
+}
+
+func TestValidateOutput_NoOutputConfig(t *testing.T) {
+	agent := &scriptedAgent{mockAgent: mockAgent{name: "scripted"}, outputs: []string{"free-form text"}}
+	executor, rule := newValidationTest(t, agent, config.OutputRepairConfig{})
+	rule.Output = nil
+
+	_, validation := runValidation(t, executor, rule, agent)
+	assert.Nil(t, validation)
+}
+
+func TestValidateOutput_ExtraFields(t *testing.T) {
+	agent := &scriptedAgent{
+		mockAgent: mockAgent{name: "scripted"},
+		outputs:   []string{`{"summary":"ok","findings":[{"severity":"low","title":"t","description":"d","cwe":"CWE-89"}]}`},
+	}
+	executor, rule := newValidationTest(t, agent, config.OutputRepairConfig{Disabled: true})
+	rule.Output.Schema = &dsl.OutputSchemaConfig{ExtraFields: []dsl.ExtraFieldConfig{
+		{Name: "cwe", Type: "string", Description: "CWE ID"},
+		{Name: "effort", Type: "string", Description: "Fix effort", Required: true, Enum: []string{"small", "large"}},
+	}}
+
+	_, validation := runValidation(t, executor, rule, agent)
+	require.NotNil(t, validation)
+	assert.Equal(t, model.OutputSalvaged, validation.Status)
+	assert.Equal(t, []string{`findings[0]: missing required field "effort"`}, validation.Errors)
+}
+
+func TestBuildRepairPrompt(t *testing.T) {
+	errs := make([]string, maxRepairPromptErrors+5)
+	for i := range errs {
+		errs[i] = "error"
+	}
+	repairPrompt := BuildRepairPrompt(errs, false, "earlier output", "format")
+	assert.Equal(t, maxRepairPromptErrors, strings.Count(repairPrompt, "- error\n"))
+	assert.Contains(t, repairPrompt, "and 5 more errors")
+	assert.NotContains(t, repairPrompt, "earlier output")
+}
+
+func TestSalvageMergedOutput(t *testing.T) {
+	rule := &dsl.ReviewRuleConfig{ID: "merged", Output: &dsl.OutputConfig{}}
+	data := map[string]interface{}{
+		"findings": []interface{}{
+			map[string]interface{}{"severity": "high", "title": "t", "description": "d"},
+			map[string]interface{}{"title": "missing severity", "description": "d"},
+		},
+	}
+
+	salvaged, validation := salvageMergedOutput(rule, data)
+	require.NotNil(t, validation)
+	assert.Equal(t, model.OutputSalvaged, validation.Status)
+	assert.Equal(t, 1, validation.Dropped)
+	assert.Equal(t, "", salvaged["summary"])
+	assert.Len(t, salvaged["findings"], 1)
+
+	merged := mergeValidations(nil, validation)
+	assert.Equal(t, model.OutputSalvaged, merged.Status)
+	assert.Nil(t, mergeValidations(nil, nil))
+}
@@ -0,0 +1,255 @@
+// Package injection screens the PR-supplied content of a review (title, description,
+// commit messages and changed files) for prompt injection before its rules run.
+package injection
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/engine/utils"
+	"github.com/verustcode/verustcode/internal/llm"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// CustomPatternSet is the pattern set name of the custom patterns
+const CustomPatternSet = "custom"
+
+// RemovedMarker replaces text removed from the prompts by the strip action
+const RemovedMarker = "[removed: possible prompt injection]"
+
+// maxExcerpt is the maximum number of characters of a detection excerpt
+const maxExcerpt = 200
+
+// Input is a piece of PR-supplied content
+type Input struct {
+	Source   string // see model.InjectionSource* constants
+	Location string // commit SHA or file path, empty for PR metadata
+	Content  string
+}
+
+// Detection is a suspected prompt injection in an input
+type Detection struct {
+	model.InjectionDetection
+
+	// Input is the index of the input in the screened inputs
+	Input int
+
+	// Start and End are the byte offsets of the matched text (both 0 for classifier detections)
+	Start int
+	End   int
+}
+
+// Classifier asks a model whether content is a prompt injection
+type Classifier interface {
+	Classify(ctx context.Context, input *Input) (injection bool, reason string, err error)
+}
+
+// Screener matches inputs against pattern sets and optionally a classifier
+type Screener struct {
+	sets       []*llm.InjectionPatternSet
+	classifier Classifier
+}
+
+// NewScreener creates a screener for the prompt injection settings.
+// classifier may be nil to screen with the pattern sets only.
+func NewScreener(cfg *config.PromptInjectionConfig, classifier Classifier) (*Screener, error) {
+	sets, err := llm.InjectionPatternSetsByName(cfg.PatternSets)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.CustomPatterns) > 0 {
+		custom, err := llm.NewInjectionPatternSet(CustomPatternSet, cfg.CustomPatterns)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(append([]*llm.InjectionPatternSet(nil), sets...), custom)
+	}
+	return &Screener{sets: sets, classifier: classifier}, nil
+}
+
+// Screen returns the detections of the inputs, ordered by input and position.
+// Inputs without pattern matches are passed to the classifier; a failing classifier
+// is logged and its input counts as clean.
+func (s *Screener) Screen(ctx context.Context, inputs []Input) []Detection {
+	var detections []Detection
+	for i := range inputs {
+		input := &inputs[i]
+		if strings.TrimSpace(input.Content) == "" {
+			continue
+		}
+
+		matches := llm.FindPromptInjections(input.Content, s.sets)
+		for _, m := range matches {
+			detections = append(detections, Detection{
+				InjectionDetection: model.InjectionDetection{
+					Source:   input.Source,
+					Location: input.Location,
+					Detector: m.PatternSet,
+					Pattern:  m.Pattern,
+					Excerpt:  excerpt(m.Text),
+				},
+				Input: i,
+				Start: m.Start,
+				End:   m.End,
+			})
+		}
+		if len(matches) > 0 || s.classifier == nil {
+			continue
+		}
+
+		flagged, reason, err := s.classifier.Classify(ctx, input)
+		if err != nil {
+			logger.Warn("Prompt injection classifier failed",
+				zap.String("source", input.Source),
+				zap.String("location", input.Location),
+				zap.Error(err),
+			)
+			continue
+		}
+		if flagged {
+			detections = append(detections, Detection{
+				InjectionDetection: model.InjectionDetection{
+					Source:   input.Source,
+					Location: input.Location,
+					Detector: model.InjectionDetectorClassifier,
+					Excerpt:  excerpt(input.Content),
+					Reason:   reason,
+				},
+				Input: i,
+			})
+		}
+	}
+	return detections
+}
+
+// Strip removes the detected text of an input's detections from its content, replacing each
+// (merged) span with RemovedMarker. Classifier detections have no span, so the whole content
+// is replaced. Returns the content unchanged if no detection belongs to the input.
+func Strip(content string, input int, detections []Detection) string {
+	var spans [][2]int
+	for _, d := range detections {
+		if d.Input != input {
+			continue
+		}
+		if d.End <= d.Start {
+			return RemovedMarker
+		}
+		spans = append(spans, [2]int{d.Start, d.End})
+	}
+	if len(spans) == 0 {
+		return content
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+
+	var sb strings.Builder
+	pos := 0
+	for _, span := range spans {
+		if span[1] <= pos {
+			continue
+		}
+		if span[0] >= pos {
+			sb.WriteString(content[pos:span[0]])
+			sb.WriteString(RemovedMarker)
+		}
+		pos = span[1]
+	}
+	sb.WriteString(content[pos:])
+	return sb.String()
+}
+
+// ActionOf returns the injection action of a repository config.
+// Repositories without a config or a valid action use the default action.
+func ActionOf(repoConfig *model.RepositoryReviewConfig) model.InjectionAction {
+	if repoConfig == nil || !repoConfig.InjectionAction.IsValid() {
+		return model.DefaultInjectionAction
+	}
+	return repoConfig.InjectionAction
+}
+
+// Records returns the detections as recorded on the review
+func Records(detections []Detection) model.InjectionDetections {
+	records := make(model.InjectionDetections, 0, len(detections))
+	for _, d := range detections {
+		records = append(records, d.InjectionDetection)
+	}
+	return records
+}
+
+// Data returns detections in the format of result data (a list of maps, like the findings list).
+// Excerpts are left out, so the detected text is not repeated in published comments.
+func Data(records model.InjectionDetections) []interface{} {
+	data := make([]interface{}, 0, len(records))
+	for _, r := range records {
+		item := map[string]interface{}{
+			"source":   r.Source,
+			"detector": r.Detector,
+		}
+		if r.Location != "" {
+			item["location"] = r.Location
+		}
+		if r.Stripped {
+			item["stripped"] = true
+		}
+		data = append(data, item)
+	}
+	return data
+}
+
+// CommitInputs returns the messages of the commits between base and head as inputs
+func CommitInputs(ctx context.Context, repoPath, baseCommit, headCommit string) []Input {
+	var inputs []Input
+	for _, c := range utils.GetCommitMessages(ctx, repoPath, baseCommit, headCommit) {
+		inputs = append(inputs, Input{Source: model.InjectionSourceCommitMessage, Location: c.SHA, Content: c.Message})
+	}
+	return inputs
+}
+
+// FileInputs returns the first maxSize bytes of the changed files of a checkout as inputs.
+// Deleted, unreadable and binary files are skipped.
+func FileInputs(repoPath string, files []string, maxSize int) []Input {
+	var inputs []Input
+	for _, file := range files {
+		content, err := readHead(filepath.Join(repoPath, filepath.FromSlash(file)), maxSize)
+		if err != nil || bytes.IndexByte(content, 0) >= 0 {
+			continue
+		}
+		inputs = append(inputs, Input{Source: model.InjectionSourceFile, Location: file, Content: string(content)})
+	}
+	return inputs
+}
+
+// readHead reads the first maxSize bytes of a regular file (symbolic links are not followed)
+func readHead(path string, maxSize int) ([]byte, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, int64(maxSize)))
+}
+
+// excerpt returns text cut to maxExcerpt characters, on a single line
+func excerpt(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > maxExcerpt {
+		return string(runes[:maxExcerpt]) + "…"
+	}
+	return text
+}
@@ -5,15 +5,23 @@ package engine
 import (
 	"container/list"
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/verustcode/verustcode/internal/engine/task"
 	"github.com/verustcode/verustcode/pkg/logger"
 )
 
+// durationSmoothing is the weight of the newest sample in the moving average
+// of task durations used for ETA estimation.
+const durationSmoothing = 0.3
+
 // RepoTaskQueue manages task queues per repository.
-// Each repository has its own FIFO queue, and only one task per repo can run at a time.
+// Each repository has its own priority queue (FIFO within a priority lane), and only
+// one task per repo can run at a time.
 // This ensures repository-level serialization while allowing concurrent processing across repos.
 type RepoTaskQueue struct {
 	mu sync.RWMutex
@@ -27,6 +35,23 @@ type RepoTaskQueue struct {
 	// taskReady signals that there are tasks ready to be processed
 	taskReady chan struct{}
 
+	// pausedRepos holds repositories whose queue is paused, key is repo_url.
+	// Kept separately from queues so the paused state survives empty queue cleanup.
+	pausedRepos map[string]bool
+
+	// maintenance stops all dispatching while still accepting new tasks
+	maintenance bool
+
+	// seq is a monotonically increasing enqueue counter used to keep FIFO order
+	// between repositories for tasks with equal priority
+	seq int64
+
+	// avgDuration is an exponential moving average of completed task durations
+	avgDuration time.Duration
+
+	// workers is the number of tasks dispatched concurrently, used for ETA estimation (0 = unlimited)
+	workers int
+
 	// ctx and cancel for graceful shutdown
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -34,7 +59,8 @@ type RepoTaskQueue struct {
 
 // repoQueue represents a single repository's task queue
 type repoQueue struct {
-	// tasks is a FIFO list of pending tasks
+	// tasks is a list of pending *queuedTask, ordered by priority (highest first)
+	// and by enqueue order within the same priority
 	tasks *list.List
 
 	// running indicates if a task is currently running for this repo
@@ -46,6 +72,20 @@ type repoQueue struct {
 	// dequeued indicates if the recovered running task has already been dequeued.
 	// This prevents the same recovered task from being returned multiple times by Dequeue().
 	dequeued bool
+
+	// current is the currently running task (nil if none or unknown)
+	current *Task
+
+	// startedAt is when the current task was dequeued
+	startedAt time.Time
+}
+
+// queuedTask wraps a pending task with its queue bookkeeping
+type queuedTask struct {
+	task       *Task
+	priority   task.Priority
+	seq        int64
+	enqueuedAt time.Time
 }
 
 // NewRepoTaskQueue creates a new RepoTaskQueue instance
@@ -53,11 +93,12 @@ func NewRepoTaskQueue(ctx context.Context) *RepoTaskQueue {
 	queueCtx, cancel := context.WithCancel(ctx)
 
 	q := &RepoTaskQueue{
-		queues:    make(map[string]*repoQueue),
-		tasksByID: make(map[string]*Task),
-		taskReady: make(chan struct{}, 100), // buffered to avoid blocking
-		ctx:       queueCtx,
-		cancel:    cancel,
+		queues:      make(map[string]*repoQueue),
+		tasksByID:   make(map[string]*Task),
+		taskReady:   make(chan struct{}, 100), // buffered to avoid blocking
+		pausedRepos: make(map[string]bool),
+		ctx:         queueCtx,
+		cancel:      cancel,
 	}
 
 	logger.Info("RepoTaskQueue initialized")
@@ -65,6 +106,7 @@ func NewRepoTaskQueue(ctx context.Context) *RepoTaskQueue {
 }
 
 // Enqueue adds a task to the queue for its repository.
+// The task is placed behind all pending tasks with the same or higher priority.
 // Returns true if the task was added, false if it already exists.
 func (q *RepoTaskQueue) Enqueue(task *Task) bool {
 	if task == nil || task.Review == nil {
@@ -97,8 +139,15 @@ func (q *RepoTaskQueue) Enqueue(task *Task) bool {
 		q.queues[repoURL] = rq
 	}
 
-	// Add task to the end of the queue (FIFO)
-	rq.tasks.PushBack(task)
+	// Insert task behind all tasks of the same or higher priority (FIFO within a lane)
+	q.seq++
+	qt := &queuedTask{
+		task:       task,
+		priority:   task.EffectivePriority(),
+		seq:        q.seq,
+		enqueuedAt: time.Now(),
+	}
+	insertByPriority(rq.tasks, qt)
 	q.tasksByID[reviewID] = task
 
 	// Signal that a task is ready (non-blocking)
@@ -145,6 +194,8 @@ func (q *RepoTaskQueue) EnqueueAsRunning(task *Task) bool {
 	rq.running = true
 	rq.currentTaskID = reviewID
 	rq.dequeued = false // Reset dequeued flag for new recovered task
+	rq.current = task
+	rq.startedAt = time.Now()
 
 	// Add to tasksByID for tracking
 	q.tasksByID[reviewID] = task
@@ -162,15 +213,27 @@ func (q *RepoTaskQueue) EnqueueAsRunning(task *Task) bool {
 }
 
 // Dequeue returns the next task that can be processed.
-// Returns nil if no tasks are available or all repos have running tasks.
+// Across repositories, the pending task with the highest priority wins;
+// ties are broken by enqueue order.
+// Returns nil if no tasks are available, all repos have running tasks or are paused,
+// or the queue is in maintenance mode.
 func (q *RepoTaskQueue) Dequeue() *Task {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
+	// Nothing is dispatched in maintenance mode
+	if q.maintenance {
+		return nil
+	}
+
 	// First, check if there are running tasks that need to be re-processed (recovery case)
 	// When a server restarts, EnqueueAsRunning marks tasks as running but doesn't put them
 	// in rq.tasks. We need to return these tasks for re-processing.
 	for repoURL, rq := range q.queues {
+		if q.pausedRepos[repoURL] {
+			continue
+		}
+
 		// Skip if already dequeued - this prevents the same recovered task from being
 		// returned multiple times when tryDispatch() calls Dequeue() in a loop
 		if rq.running && rq.currentTaskID != "" && !rq.dequeued {
@@ -192,39 +255,70 @@ func (q *RepoTaskQueue) Dequeue() *Task {
 		}
 	}
 
-	// Find a repo with pending tasks that doesn't have a running task
-	for _, rq := range q.queues {
+	// Find the best head task among repos that are idle and not paused
+	var bestRQ *repoQueue
+	var bestElem *list.Element
+	for repoURL, rq := range q.queues {
 		if rq.running {
 			// This repo already has a task running, skip it
 			continue
 		}
 
-		if rq.tasks.Len() == 0 {
-			// No pending tasks for this repo
+		if q.pausedRepos[repoURL] {
+			// This repo's queue is paused by an operator
 			continue
 		}
 
-		// Get the first task (FIFO)
+		// The head is always the best candidate of its repo
 		elem := rq.tasks.Front()
 		if elem == nil {
+			// No pending tasks for this repo
 			continue
 		}
 
-		task := elem.Value.(*Task)
-		rq.tasks.Remove(elem)
+		if bestElem == nil || runsBefore(elem.Value.(*queuedTask), bestElem.Value.(*queuedTask)) {
+			bestRQ = rq
+			bestElem = elem
+		}
+	}
+
+	if bestElem == nil {
+		return nil
+	}
+
+	task := bestElem.Value.(*queuedTask).task
+	bestRQ.tasks.Remove(bestElem)
 
-		// Mark repo as running
-		rq.running = true
-		rq.currentTaskID = task.Review.ID
+	// Mark repo as running
+	bestRQ.running = true
+	bestRQ.currentTaskID = task.Review.ID
+	bestRQ.current = task
+	bestRQ.startedAt = time.Now()
 
-		// Remove from tasksByID - this distinguishes normal dequeue from recovery case
-		// Recovery tasks remain in tasksByID until they are dequeued via the recovery path
-		delete(q.tasksByID, task.Review.ID)
+	// Remove from tasksByID - this distinguishes normal dequeue from recovery case
+	// Recovery tasks remain in tasksByID until they are dequeued via the recovery path
+	delete(q.tasksByID, task.Review.ID)
 
-		return task
+	return task
+}
+
+// insertByPriority inserts qt behind the last element whose priority is >= qt's priority
+func insertByPriority(l *list.List, qt *queuedTask) {
+	for elem := l.Back(); elem != nil; elem = elem.Prev() {
+		if elem.Value.(*queuedTask).priority >= qt.priority {
+			l.InsertAfter(qt, elem)
+			return
+		}
 	}
+	l.PushFront(qt)
+}
 
-	return nil
+// runsBefore reports whether a should be dispatched before b
+func runsBefore(a, b *queuedTask) bool {
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	return a.seq < b.seq
 }
 
 // MarkComplete marks a task as complete and triggers scheduling of next task.
@@ -245,10 +339,17 @@ func (q *RepoTaskQueue) MarkComplete(repoURL string, reviewID string) {
 		return
 	}
 
+	// Record duration for ETA estimation (only for the task that actually ran)
+	if rq.currentTaskID == reviewID && !rq.startedAt.IsZero() {
+		q.recordDuration(time.Since(rq.startedAt))
+	}
+
 	// Clear running state
 	rq.running = false
 	rq.currentTaskID = ""
 	rq.dequeued = false // Reset dequeued flag for next task
+	rq.current = nil
+	rq.startedAt = time.Time{}
 
 	logger.Info("Task marked complete",
 		zap.String("review_id", reviewID),
@@ -268,6 +369,19 @@ func (q *RepoTaskQueue) MarkComplete(repoURL string, reviewID string) {
 	q.signalTaskReady()
 }
 
+// recordDuration folds a completed task duration into the moving average.
+// Caller must hold q.mu.
+func (q *RepoTaskQueue) recordDuration(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	if q.avgDuration == 0 {
+		q.avgDuration = d
+		return
+	}
+	q.avgDuration = time.Duration(durationSmoothing*float64(d) + (1-durationSmoothing)*float64(q.avgDuration))
+}
+
 // TaskReady returns the channel that signals when tasks are ready
 func (q *RepoTaskQueue) TaskReady() <-chan struct{} {
 	return q.taskReady
@@ -288,10 +402,14 @@ func (q *RepoTaskQueue) GetStats() QueueStats {
 	defer q.mu.RUnlock()
 
 	stats := QueueStats{
-		TotalPending: 0,
-		TotalRunning: 0,
-		RepoCount:    len(q.queues),
-		RepoStats:    make(map[string]RepoQueueStats),
+		TotalPending:    0,
+		TotalRunning:    0,
+		RepoCount:       len(q.queues),
+		RepoStats:       make(map[string]RepoQueueStats),
+		PendingByLane:   make(map[string]int),
+		Maintenance:     q.maintenance,
+		AvgTaskDuration: q.avgDuration,
+		PausedRepoCount: len(q.pausedRepos),
 	}
 
 	for repoURL, rq := range q.queues {
@@ -302,10 +420,15 @@ func (q *RepoTaskQueue) GetStats() QueueStats {
 			stats.TotalRunning++
 		}
 
+		for elem := rq.tasks.Front(); elem != nil; elem = elem.Next() {
+			stats.PendingByLane[elem.Value.(*queuedTask).priority.String()]++
+		}
+
 		stats.RepoStats[repoURL] = RepoQueueStats{
 			PendingCount: pendingCount,
 			Running:      rq.running,
 			CurrentTask:  rq.currentTaskID,
+			Paused:       q.pausedRepos[repoURL],
 		}
 	}
 
@@ -314,10 +437,14 @@ func (q *RepoTaskQueue) GetStats() QueueStats {
 
 // QueueStats holds queue statistics
 type QueueStats struct {
-	TotalPending int                       // Total pending tasks across all repos
-	TotalRunning int                       // Number of repos with running tasks
-	RepoCount    int                       // Number of repos with queued tasks
-	RepoStats    map[string]RepoQueueStats // Per-repo statistics
+	TotalPending    int                       // Total pending tasks across all repos
+	TotalRunning    int                       // Number of repos with running tasks
+	RepoCount       int                       // Number of repos with queued tasks
+	RepoStats       map[string]RepoQueueStats // Per-repo statistics
+	PendingByLane   map[string]int            // Pending tasks per priority lane (manual, webhook, scheduled)
+	PausedRepoCount int                       // Number of repos whose queue is paused
+	Maintenance     bool                      // Whether dispatching is stopped for maintenance
+	AvgTaskDuration time.Duration             // Moving average of task durations (0 if unknown)
 }
 
 // RepoQueueStats holds per-repo queue statistics
@@ -325,6 +452,286 @@ type RepoQueueStats struct {
 	PendingCount int    // Number of pending tasks
 	Running      bool   // Whether a task is running
 	CurrentTask  string // Review ID (UUID) of running task ("" if none)
+	Paused       bool   // Whether the repo queue is paused
+}
+
+// QueuedTaskInfo describes a running or pending task for queue administration
+type QueuedTaskInfo struct {
+	ReviewID   string
+	RepoURL    string
+	Ref        string
+	PRNumber   int
+	Source     string
+	Priority   task.Priority
+	Running    bool      // Whether the task is currently running
+	Position   int       // Position in the repo queue (0 = running, 1 = next)
+	EnqueuedAt time.Time // When the task entered the queue (start time for running tasks)
+
+	// EstimatedStart is the estimated time until the task starts.
+	// Nil when no estimate is possible (no completed tasks yet, repo paused or maintenance mode).
+	EstimatedStart *time.Duration
+}
+
+// ListTasks returns all running and pending tasks, grouped by repository
+// and ordered by their position in the repository queue.
+// ETAs assume every task takes the average task duration (see estimateStarts).
+func (q *RepoTaskQueue) ListTasks() []QueuedTaskInfo {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	repoURLs := make([]string, 0, len(q.queues))
+	for repoURL := range q.queues {
+		repoURLs = append(repoURLs, repoURL)
+	}
+	sort.Strings(repoURLs)
+
+	starts := q.estimateStarts()
+
+	var infos []QueuedTaskInfo
+	for _, repoURL := range repoURLs {
+		rq := q.queues[repoURL]
+		if rq.running {
+			info := QueuedTaskInfo{
+				ReviewID:   rq.currentTaskID,
+				RepoURL:    repoURL,
+				Running:    true,
+				Position:   0,
+				EnqueuedAt: rq.startedAt,
+			}
+			if rq.current != nil && rq.current.Review != nil {
+				info.Ref = rq.current.Review.Ref
+				info.PRNumber = rq.current.Review.PRNumber
+				info.Source = rq.current.Review.Source
+				info.Priority = rq.current.EffectivePriority()
+			}
+			infos = append(infos, info)
+		}
+
+		position := 1
+		for elem := rq.tasks.Front(); elem != nil; elem = elem.Next() {
+			qt := elem.Value.(*queuedTask)
+			info := QueuedTaskInfo{
+				ReviewID:   qt.task.Review.ID,
+				RepoURL:    repoURL,
+				Ref:        qt.task.Review.Ref,
+				PRNumber:   qt.task.Review.PRNumber,
+				Source:     qt.task.Review.Source,
+				Priority:   qt.priority,
+				Position:   position,
+				EnqueuedAt: qt.enqueuedAt,
+			}
+			if eta, ok := starts[qt]; ok {
+				info.EstimatedStart = &eta
+			}
+			infos = append(infos, info)
+			position++
+		}
+	}
+
+	return infos
+}
+
+// estimateStarts estimates the time until each pending task starts by replaying the dispatcher:
+// whenever a worker becomes free, the best head task of the idle repositories starts, and every
+// task takes the average task duration. Running tasks (also of paused repositories) occupy their
+// worker. Returns nil if no estimate is possible (no completed tasks yet or maintenance mode);
+// tasks of paused repositories have no estimate. The caller must hold the lock.
+func (q *RepoTaskQueue) estimateStarts() map[*queuedTask]time.Duration {
+	if q.avgDuration <= 0 || q.maintenance {
+		return nil
+	}
+
+	// Time until each worker and each repository becomes free
+	var workerFree []time.Duration
+	repoFree := make(map[string]time.Duration, len(q.queues))
+	heads := make(map[string]*list.Element, len(q.queues))
+	for repoURL, rq := range q.queues {
+		if rq.running {
+			remaining := q.avgDuration - time.Since(rq.startedAt)
+			if remaining < 0 {
+				remaining = 0
+			}
+			workerFree = append(workerFree, remaining)
+			repoFree[repoURL] = remaining
+		}
+		if !q.pausedRepos[repoURL] && rq.tasks.Len() > 0 {
+			heads[repoURL] = rq.tasks.Front()
+		}
+	}
+
+	// Without a limit, every repository effectively has its own worker
+	workers := q.workers
+	if workers <= 0 {
+		workers = len(workerFree) + len(heads)
+	}
+	for len(workerFree) < workers {
+		workerFree = append(workerFree, 0)
+	}
+
+	starts := make(map[*queuedTask]time.Duration)
+	for len(heads) > 0 {
+		worker := 0
+		for i := range workerFree {
+			if workerFree[i] < workerFree[worker] {
+				worker = i
+			}
+		}
+
+		// The worker waits for the first repository that becomes free if all are busy
+		now := workerFree[worker]
+		earliest := time.Duration(-1)
+		for repoURL := range heads {
+			if free := repoFree[repoURL]; earliest < 0 || free < earliest {
+				earliest = free
+			}
+		}
+		if earliest > now {
+			now = earliest
+		}
+
+		var bestRepo string
+		var best *queuedTask
+		for repoURL, elem := range heads {
+			qt := elem.Value.(*queuedTask)
+			if repoFree[repoURL] <= now && (best == nil || runsBefore(qt, best)) {
+				bestRepo, best = repoURL, qt
+			}
+		}
+
+		starts[best] = now
+		workerFree[worker] = now + q.avgDuration
+		repoFree[bestRepo] = now + q.avgDuration
+		if next := heads[bestRepo].Next(); next != nil {
+			heads[bestRepo] = next
+		} else {
+			delete(heads, bestRepo)
+		}
+	}
+	return starts
+}
+
+// SetWorkers sets the number of tasks dispatched concurrently, used for ETA estimation
+func (q *RepoTaskQueue) SetWorkers(workers int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.workers = workers
+}
+
+// MoveToFront moves a pending task to the front of its repository queue.
+// The task is promoted to the highest priority present in the queue so that
+// later arrivals of the same priority queue up behind it.
+// Returns false if the task is not pending.
+func (q *RepoTaskQueue) MoveToFront(reviewID string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	t, exists := q.tasksByID[reviewID]
+	if !exists {
+		return false
+	}
+
+	rq, ok := q.queues[t.Review.RepoURL]
+	if !ok {
+		return false
+	}
+
+	for elem := rq.tasks.Front(); elem != nil; elem = elem.Next() {
+		qt := elem.Value.(*queuedTask)
+		if qt.task.Review.ID != reviewID {
+			continue
+		}
+
+		front := rq.tasks.Front().Value.(*queuedTask)
+		if front != qt {
+			if front.priority > qt.priority {
+				qt.priority = front.priority
+			}
+			qt.seq = front.seq - 1
+			rq.tasks.MoveToFront(elem)
+		}
+
+		logger.Info("Task moved to front of queue",
+			zap.String("review_id", reviewID),
+			zap.String("repo_url", t.Review.RepoURL),
+			zap.String("priority", qt.priority.String()),
+		)
+		q.signalTaskReady()
+		return true
+	}
+
+	return false
+}
+
+// PauseRepo pauses dispatching for a repository.
+// Tasks can still be enqueued; a running task is not interrupted.
+func (q *RepoTaskQueue) PauseRepo(repoURL string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pausedRepos[repoURL] = true
+
+	logger.Info("Repository queue paused", zap.String("repo_url", repoURL))
+}
+
+// ResumeRepo resumes dispatching for a paused repository.
+// Returns false if the repository was not paused.
+func (q *RepoTaskQueue) ResumeRepo(repoURL string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.pausedRepos[repoURL] {
+		return false
+	}
+	delete(q.pausedRepos, repoURL)
+
+	logger.Info("Repository queue resumed", zap.String("repo_url", repoURL))
+	q.signalTaskReady()
+	return true
+}
+
+// PausedRepos returns the paused repositories, sorted
+func (q *RepoTaskQueue) PausedRepos() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	repos := make([]string, 0, len(q.pausedRepos))
+	for repoURL := range q.pausedRepos {
+		repos = append(repos, repoURL)
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+// IsRepoPaused returns true if the repository queue is paused
+func (q *RepoTaskQueue) IsRepoPaused(repoURL string) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.pausedRepos[repoURL]
+}
+
+// SetMaintenance enables or disables maintenance mode.
+// In maintenance mode tasks are still accepted but none are dispatched;
+// running tasks are allowed to finish.
+func (q *RepoTaskQueue) SetMaintenance(enabled bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.maintenance == enabled {
+		return
+	}
+	q.maintenance = enabled
+
+	logger.Info("Queue maintenance mode changed", zap.Bool("maintenance", enabled))
+	if !enabled {
+		q.signalTaskReady()
+	}
+}
+
+// InMaintenance returns true if the queue is in maintenance mode
+func (q *RepoTaskQueue) InMaintenance() bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.maintenance
 }
 
 // IsEmpty returns true if there are no tasks in any queue
@@ -340,13 +747,17 @@ func (q *RepoTaskQueue) IsEmpty() bool {
 	return true
 }
 
-// HasPendingTasks returns true if there are pending tasks that can be processed
+// HasPendingTasks returns true if there are pending tasks that can be processed now
 func (q *RepoTaskQueue) HasPendingTasks() bool {
 	q.mu.RLock()
 	defer q.mu.RUnlock()
 
-	for _, rq := range q.queues {
-		if !rq.running && rq.tasks.Len() > 0 {
+	if q.maintenance {
+		return false
+	}
+
+	for repoURL, rq := range q.queues {
+		if !rq.running && rq.tasks.Len() > 0 && !q.pausedRepos[repoURL] {
 			return true
 		}
 	}
@@ -429,7 +840,9 @@ func (q *RepoTaskQueue) RemoveTask(reviewID string) bool {
 			// This is a running task, mark as not running
 			rq.running = false
 			rq.currentTaskID = ""
-			rq.dequeued = false           // Reset dequeued flag
+			rq.dequeued = false // Reset dequeued flag
+			rq.current = nil
+			rq.startedAt = time.Time{}
 			delete(q.tasksByID, reviewID) // Delete if exists (recovery case)
 			logger.Info("Running task removed",
 				zap.String("review_id", reviewID),
@@ -455,7 +868,7 @@ func (q *RepoTaskQueue) RemoveTask(reviewID string) bool {
 
 	// Find and remove from pending list
 	for elem := rq.tasks.Front(); elem != nil; elem = elem.Next() {
-		t := elem.Value.(*Task)
+		t := elem.Value.(*queuedTask).task
 		if t.Review.ID == reviewID {
 			rq.tasks.Remove(elem)
 			delete(q.tasksByID, reviewID)
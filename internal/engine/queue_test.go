@@ -589,3 +589,261 @@ func TestRepoTaskQueue_HasTask(t *testing.T) {
 		}
 	})
 }
+
+// createTestTaskWithSource creates a task whose priority derives from the review source
+func createTestTaskWithSource(reviewID, repoURL, source string) *Task {
+	task := createTestTask(reviewID, repoURL)
+	task.Review.Source = source
+	return task
+}
+
+// TestRepoTaskQueue_PriorityLanes tests that higher priority tasks are dispatched first
+func TestRepoTaskQueue_PriorityLanes(t *testing.T) {
+	ctx := context.Background()
+	q := NewRepoTaskQueue(ctx)
+
+	repoURL := "https://github.com/test/repo"
+
+	q.Enqueue(createTestTaskWithSource("scheduled", repoURL, "schedule"))
+	q.Enqueue(createTestTaskWithSource("webhook-1", repoURL, "webhook"))
+	q.Enqueue(createTestTaskWithSource("manual", repoURL, "api"))
+	q.Enqueue(createTestTaskWithSource("webhook-2", repoURL, "webhook"))
+
+	expectedOrder := []string{"manual", "webhook-1", "webhook-2", "scheduled"}
+	for _, expectedID := range expectedOrder {
+		task := q.Dequeue()
+		if task == nil {
+			t.Fatalf("Dequeue() returned nil, expected task with ID %s", expectedID)
+		}
+		if task.Review.ID != expectedID {
+			t.Errorf("Task ID = %s, want %s", task.Review.ID, expectedID)
+		}
+		q.MarkComplete(repoURL, task.Review.ID)
+	}
+}
+
+// TestRepoTaskQueue_PriorityAcrossRepos tests that priority wins across repositories
+func TestRepoTaskQueue_PriorityAcrossRepos(t *testing.T) {
+	ctx := context.Background()
+	q := NewRepoTaskQueue(ctx)
+
+	q.Enqueue(createTestTaskWithSource("scheduled", "https://github.com/test/repo1", "schedule"))
+	q.Enqueue(createTestTaskWithSource("webhook", "https://github.com/test/repo2", "webhook"))
+	q.Enqueue(createTestTaskWithSource("manual", "https://github.com/test/repo3", "api"))
+
+	expectedOrder := []string{"manual", "webhook", "scheduled"}
+	for _, expectedID := range expectedOrder {
+		task := q.Dequeue()
+		if task == nil {
+			t.Fatalf("Dequeue() returned nil, expected task with ID %s", expectedID)
+		}
+		if task.Review.ID != expectedID {
+			t.Errorf("Task ID = %s, want %s", task.Review.ID, expectedID)
+		}
+	}
+}
+
+// TestRepoTaskQueue_MoveToFront tests moving a pending task to the front
+func TestRepoTaskQueue_MoveToFront(t *testing.T) {
+	ctx := context.Background()
+	q := NewRepoTaskQueue(ctx)
+
+	repoURL := "https://github.com/test/repo"
+	q.Enqueue(createTestTaskWithSource("manual", repoURL, "api"))
+	q.Enqueue(createTestTaskWithSource("webhook", repoURL, "webhook"))
+	q.Enqueue(createTestTaskWithSource("scheduled", repoURL, "schedule"))
+
+	if !q.MoveToFront("scheduled") {
+		t.Fatal("MoveToFront() returned false for pending task")
+	}
+	if q.MoveToFront("unknown") {
+		t.Error("MoveToFront() returned true for unknown task")
+	}
+
+	task := q.Dequeue()
+	if task == nil || task.Review.ID != "scheduled" {
+		t.Fatalf("Dequeue() = %v, want scheduled", task)
+	}
+	q.MarkComplete(repoURL, task.Review.ID)
+
+	// A later manual task queues up behind the earlier one in the same lane
+	q.Enqueue(createTestTaskWithSource("manual-2", repoURL, "api"))
+	task = q.Dequeue()
+	if task == nil || task.Review.ID != "manual" {
+		t.Fatalf("Dequeue() = %v, want manual", task)
+	}
+}
+
+// TestRepoTaskQueue_PauseResume tests pausing and resuming a repository queue
+func TestRepoTaskQueue_PauseResume(t *testing.T) {
+	ctx := context.Background()
+	q := NewRepoTaskQueue(ctx)
+
+	repo1 := "https://github.com/test/repo1"
+	repo2 := "https://github.com/test/repo2"
+
+	q.PauseRepo(repo1)
+	q.Enqueue(createTestTask("1", repo1))
+	q.Enqueue(createTestTask("2", repo2))
+
+	if !q.IsRepoPaused(repo1) {
+		t.Error("IsRepoPaused() = false, want true")
+	}
+
+	task := q.Dequeue()
+	if task == nil || task.Review.ID != "2" {
+		t.Fatalf("Dequeue() = %v, want task 2 from unpaused repo", task)
+	}
+	if next := q.Dequeue(); next != nil {
+		t.Errorf("Dequeue() returned %s from paused repo", next.Review.ID)
+	}
+
+	stats := q.GetStats()
+	if !stats.RepoStats[repo1].Paused {
+		t.Error("RepoStats.Paused = false for paused repo")
+	}
+
+	if !q.ResumeRepo(repo1) {
+		t.Fatal("ResumeRepo() returned false for paused repo")
+	}
+	if q.ResumeRepo(repo1) {
+		t.Error("ResumeRepo() returned true for repo that is not paused")
+	}
+
+	task = q.Dequeue()
+	if task == nil || task.Review.ID != "1" {
+		t.Fatalf("Dequeue() = %v, want task 1 after resume", task)
+	}
+}
+
+// TestRepoTaskQueue_Maintenance tests that maintenance mode stops dispatching
+func TestRepoTaskQueue_Maintenance(t *testing.T) {
+	ctx := context.Background()
+	q := NewRepoTaskQueue(ctx)
+
+	repoURL := "https://github.com/test/repo"
+	q.SetMaintenance(true)
+
+	if !q.Enqueue(createTestTask("1", repoURL)) {
+		t.Fatal("Enqueue() returned false in maintenance mode")
+	}
+	if task := q.Dequeue(); task != nil {
+		t.Errorf("Dequeue() returned %s in maintenance mode", task.Review.ID)
+	}
+	if q.HasPendingTasks() {
+		t.Error("HasPendingTasks() = true in maintenance mode")
+	}
+	if q.GetPendingCount() != 1 {
+		t.Errorf("GetPendingCount() = %d, want 1", q.GetPendingCount())
+	}
+
+	q.SetMaintenance(false)
+	if q.InMaintenance() {
+		t.Error("InMaintenance() = true after disabling")
+	}
+	if task := q.Dequeue(); task == nil {
+		t.Error("Dequeue() returned nil after leaving maintenance mode")
+	}
+}
+
+// TestRepoTaskQueue_ListTasks tests listing tasks with positions and ETAs
+func TestRepoTaskQueue_ListTasks(t *testing.T) {
+	ctx := context.Background()
+	q := NewRepoTaskQueue(ctx)
+
+	repoURL := "https://github.com/test/repo"
+	q.Enqueue(createTestTask("1", repoURL))
+	q.Enqueue(createTestTask("2", repoURL))
+	q.Enqueue(createTestTask("3", repoURL))
+
+	// No completed task yet, so ETAs are unknown
+	for _, info := range q.ListTasks() {
+		if info.EstimatedStart != nil {
+			t.Errorf("EstimatedStart for %s = %v, want nil", info.ReviewID, *info.EstimatedStart)
+		}
+	}
+
+	task := q.Dequeue()
+	time.Sleep(10 * time.Millisecond)
+	q.MarkComplete(repoURL, task.Review.ID)
+	q.Dequeue()
+
+	infos := q.ListTasks()
+	if len(infos) != 2 {
+		t.Fatalf("ListTasks() returned %d tasks, want 2", len(infos))
+	}
+	if !infos[0].Running || infos[0].ReviewID != "2" || infos[0].Position != 0 {
+		t.Errorf("first entry = %+v, want running task 2", infos[0])
+	}
+	if infos[1].ReviewID != "3" || infos[1].Position != 1 {
+		t.Errorf("second entry = %+v, want task 3 at position 1", infos[1])
+	}
+	if infos[1].EstimatedStart == nil {
+		t.Error("EstimatedStart is nil after a task completed")
+	}
+}
+
+// TestRepoTaskQueue_ListTasksWorkers tests that ETAs account for the worker limit
+func TestRepoTaskQueue_ListTasksWorkers(t *testing.T) {
+	repoA := "https://github.com/test/a"
+	repoB := "https://github.com/test/b"
+	avg := time.Minute
+
+	tests := []struct {
+		name    string
+		workers int
+		pauseB  bool
+		want    map[string]time.Duration // review ID -> ETA, missing = no estimate
+	}{
+		{
+			name:    "single worker",
+			workers: 1,
+			want:    map[string]time.Duration{"a1": 0, "a2": avg, "b1": 2 * avg},
+		},
+		{
+			name:    "two workers",
+			workers: 2,
+			want:    map[string]time.Duration{"a1": 0, "a2": avg, "b1": 0},
+		},
+		{
+			name:    "unlimited workers",
+			workers: 0,
+			want:    map[string]time.Duration{"a1": 0, "a2": avg, "b1": 0},
+		},
+		{
+			name:    "paused repository",
+			workers: 1,
+			pauseB:  true,
+			want:    map[string]time.Duration{"a1": 0, "a2": avg},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewRepoTaskQueue(context.Background())
+			q.SetWorkers(tt.workers)
+			q.avgDuration = avg
+			q.Enqueue(createTestTask("a1", repoA))
+			q.Enqueue(createTestTask("a2", repoA))
+			q.Enqueue(createTestTask("b1", repoB))
+			if tt.pauseB {
+				q.PauseRepo(repoB)
+			}
+
+			for _, info := range q.ListTasks() {
+				want, ok := tt.want[info.ReviewID]
+				if !ok {
+					if info.EstimatedStart != nil {
+						t.Errorf("EstimatedStart for %s = %v, want nil", info.ReviewID, *info.EstimatedStart)
+					}
+					continue
+				}
+				if info.EstimatedStart == nil {
+					t.Errorf("EstimatedStart for %s is nil, want %v", info.ReviewID, want)
+				} else if *info.EstimatedStart != want {
+					t.Errorf("EstimatedStart for %s = %v, want %v", info.ReviewID, *info.EstimatedStart, want)
+				}
+			}
+		})
+	}
+}
@@ -185,6 +185,7 @@ func (s *Service) buildRecoveryTask(ctx context.Context, review *model.Review) *
 		Review:            review,
 		ProviderName:      providerName,
 		CreatedAt:         time.Now(),
+		BaseCommitSHA:     review.BaseCommitSHA,
 		ReviewRulesConfig: nil, // Will be loaded dynamically in processTask
 		Request: &base.ReviewRequest{
 			RequestID:    idgen.NewRequestID(),
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -91,31 +92,29 @@ func (h *Handler) getReviewConfig() *config.ReviewConfig {
 }
 
 // loadDSLConfigWithPriority loads review configuration with priority order:
-// 1. .verust-review.yaml at repository root (highest priority)
+// 1. .verust-review.yaml in the repository, as allowed by the repository's in-repo config policy
 // 2. Database configured review file for this repository
 // 3. config/reviews/default.yaml (fallback)
-func (h *Handler) loadDSLConfigWithPriority(repoPath, repoURL string) (*dsl.ReviewRulesConfig, error) {
-	// Priority 1: Check for .verust-review.yaml at repository root
-	if repoPath != "" {
-		rootConfig, err := h.dslLoader.LoadFromRepoRoot(repoPath)
-		if err != nil {
-			logger.Warn("Failed to load review config from repository root",
-				zap.String("repo_path", repoPath),
-				zap.Error(err),
-			)
-			// Continue to next priority
-		} else if rootConfig != nil {
-			logger.Info("Using review config from repository root (.verust-review.yaml)",
-				zap.String("repo_path", repoPath),
-				zap.Int("rules", len(rootConfig.Rules)),
-			)
-			return rootConfig, nil
-		}
+func (h *Handler) loadDSLConfigWithPriority(repoPath, repoURL, baseCommit string) (*dsl.ReviewRulesConfig, error) {
+	serverConfig, repoConfig, err := h.loadServerDSLConfig(repoURL)
+	if err != nil {
+		return nil, err
 	}
 
-	// Priority 2: Database configured review file
+	policy := utils.InRepoConfigPolicyOf(repoConfig)
+	effective := utils.ApplyInRepoConfigPolicy(h.dslLoader, policy, serverConfig, repoPath, baseCommit)
+	return effective.Config, nil
+}
+
+// loadServerDSLConfig loads the server-side review configuration of a repository:
+// the database configured review file, or config/reviews/default.yaml.
+// Also returns the repository config, or nil if the repository has none.
+func (h *Handler) loadServerDSLConfig(repoURL string) (*dsl.ReviewRulesConfig, *model.RepositoryReviewConfig, error) {
 	repoConfig, err := h.store.RepositoryConfig().GetByRepoURL(repoURL)
-	if err == nil && repoConfig.ReviewFile != "" {
+	if err != nil {
+		repoConfig = nil
+	}
+	if repoConfig != nil && repoConfig.ReviewFile != "" {
 		reviewFilePath := filepath.Join(config.ReviewsDir, repoConfig.ReviewFile)
 		cfg, loadErr := h.dslLoader.Load(reviewFilePath)
 		if loadErr != nil {
@@ -129,15 +128,16 @@ func (h *Handler) loadDSLConfigWithPriority(repoPath, repoURL string) (*dsl.Revi
 				zap.String("repo_url", repoURL),
 				zap.String("review_file", repoConfig.ReviewFile),
 			)
-			return cfg, nil
+			return cfg, repoConfig, nil
 		}
 	}
 
-	// Priority 3: Default review configuration
+	// Default review configuration
 	logger.Debug("Using default review config",
 		zap.String("repo_url", repoURL),
 	)
-	return h.dslLoader.LoadDefaultReviewConfig()
+	cfg, err := h.dslLoader.LoadDefaultReviewConfig()
+	return cfg, repoConfig, err
 }
 
 // Retry retries a failed review by resetting its state and re-enqueuing.
@@ -193,6 +193,9 @@ func (h *Handler) Retry(reviewID string) error {
 		return errors.New(errors.ErrCodeInternal, "failed to build recovery task for retry")
 	}
 
+	// Manual reruns jump ahead of webhook and scheduled tasks
+	t.Priority = task.PriorityManual
+
 	// Submit to memory queue
 	if !h.taskEnqueuer.Enqueue(t) {
 		// Task already exists in queue (should not happen after our check above)
@@ -210,6 +213,65 @@ func (h *Handler) Retry(reviewID string) error {
 	return nil
 }
 
+// Approve releases a review that was quarantined because of a possible prompt injection.
+// The approval is recorded on the review, which is re-enqueued; its rules then run with the
+// detections noted in the comments instead of being quarantined again.
+func (h *Handler) Approve(reviewID, approvedBy string) error {
+	review, err := h.store.Review().GetByID(reviewID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.Wrap(errors.ErrCodeReviewNotFound, "review not found", err)
+		}
+		return errors.Wrap(errors.ErrCodeDBQuery, "failed to load review", err)
+	}
+
+	// Only quarantined reviews can be approved
+	if review.Status != model.ReviewStatusQuarantined {
+		return errors.New(errors.ErrCodeValidation, fmt.Sprintf("cannot approve review with status '%s', only quarantined reviews can be approved", review.Status))
+	}
+
+	if h.taskEnqueuer.HasTask(reviewID) {
+		return errors.New(errors.ErrCodeValidation, "review is already in the queue")
+	}
+
+	logger.Info("Approving quarantined review",
+		zap.String("review_id", reviewID),
+		zap.String("approved_by", approvedBy),
+		zap.Int("detections", len(review.InjectionDetections)),
+	)
+
+	if err := h.store.Review().UpdateMetadata(reviewID, map[string]interface{}{
+		"status":                model.ReviewStatusPending,
+		"error_message":         "",
+		"injection_approved_by": approvedBy,
+		"injection_approved_at": time.Now(),
+	}); err != nil {
+		return errors.Wrap(errors.ErrCodeDBQuery, "failed to approve review", err)
+	}
+
+	// Reload review with updated state
+	review, err = h.store.Review().GetByID(reviewID)
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeDBQuery, "failed to reload review", err)
+	}
+
+	t := h.taskBuilder.BuildRecoveryTask(h.ctx, review)
+	if t == nil {
+		h.store.Review().UpdateStatusWithError(review.ID, model.ReviewStatusFailed, "approval failed: could not build recovery task")
+		return errors.New(errors.ErrCodeInternal, "failed to build recovery task for approved review")
+	}
+
+	// Approved reviews jump ahead of webhook and scheduled tasks, like manual retries
+	t.Priority = task.PriorityManual
+
+	if !h.taskEnqueuer.Enqueue(t) {
+		logger.Warn("Task already in queue during approval",
+			zap.String("review_id", reviewID),
+		)
+	}
+	return nil
+}
+
 // RetryRule retries a single failed rule within a review.
 // This method allows parallel execution - the rule retry runs in a separate goroutine
 // while other rules may still be executing.
@@ -325,10 +387,10 @@ func (h *Handler) executeRuleRetry(reviewID, ruleID string, review *model.Review
 	)
 
 	// Dynamically load DSL config with priority:
-	// 1. .verust-review.yaml at repository root (if repo is cloned)
+	// 1. .verust-review.yaml in the repository (if repo is cloned and the trust policy allows it)
 	// 2. Database configured review file
 	// 3. config/reviews/default.yaml
-	dslConfig, err := h.loadDSLConfigWithPriority(review.RepoPath, review.RepoURL)
+	dslConfig, err := h.loadDSLConfigWithPriority(review.RepoPath, review.RepoURL, review.BaseCommitSHA)
 	if err != nil {
 		logger.Error("Failed to load DSL config for rule retry",
 			zap.String("review_id", reviewID),
@@ -376,8 +438,11 @@ func (h *Handler) executeRuleRetry(reviewID, ruleID string, review *model.Review
 		RepoURL:        review.RepoURL,
 		Ref:            review.Ref,
 		CommitSHA:      review.CommitSHA,
+		BaseCommitSHA:  review.BaseCommitSHA,
 		PRNumber:       review.PRNumber,
+		Source:         review.Source,
 		OutputLanguage: outputLanguage,
+		Areas:          dslConfig.Areas,
 	}
 
 	// Determine provider
@@ -412,6 +477,17 @@ func (h *Handler) executeRuleRetry(reviewID, ruleID string, review *model.Review
 		}
 	}
 
+	// PR-supplied content, as in a full run of the review: screened for prompt injection and
+	// rendered into the prompt
+	if prInfo != nil {
+		buildCtx.PRTitle = prInfo.Title
+		buildCtx.PRDescription = prInfo.Description
+	}
+	if review.RepoPath != "" {
+		buildCtx.ChangedFiles = utils.GetChangedFiles(ctx, review.RepoPath, review.BaseCommitSHA, review.CommitSHA)
+		buildCtx.Commits = utils.GetCommitsInRange(ctx, review.RepoPath, review.BaseCommitSHA, review.CommitSHA)
+	}
+
 	// Create execution context
 	execCtx := &runner.RuleExecutionContext{
 		BuildCtx:     buildCtx,
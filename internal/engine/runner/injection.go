@@ -0,0 +1,194 @@
+// Package runner provides the ReviewRunner which handles review execution logic.
+// This file screens the PR-supplied content of a review for prompt injection.
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/engine/injection"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// ErrReviewQuarantined is returned by RunReviewWithTracking when the review was quarantined
+// because of a possible prompt injection. No rule ran; the review waits for approval.
+var ErrReviewQuarantined = errors.New("review quarantined: possible prompt injection awaits approval")
+
+// Indexes of the PR metadata in the screened inputs
+const (
+	inputPRTitle = iota
+	inputPRDescription
+)
+
+// screenPromptInjection screens the PR title, description, commit messages and (if configured)
+// changed files of a review, records the detections on the review and applies the repository's
+// injection action: with strip, the detected text is removed from req's title and description,
+// and detections in commit messages or changed files, which can't be stripped, quarantine the
+// review; with quarantine, the review is quarantined and ErrReviewQuarantined is returned, unless
+// an administrator already approved it. Returns the recorded detections to note in the comments.
+func (r *Runner) screenPromptInjection(ctx context.Context, req *ReviewRequest, review *model.Review) (model.InjectionDetections, error) {
+	reviewCfg := r.getReviewConfig()
+	if reviewCfg == nil || !reviewCfg.PromptInjection.Enabled || req.Source == "schedule" {
+		return nil, nil
+	}
+	cfg := &reviewCfg.PromptInjection
+
+	classifier := r.injectionClassifier(cfg, req, review)
+	screener, err := injection.NewScreener(cfg, classifier)
+	if err != nil {
+		logger.Error("Invalid prompt injection settings, screening with the built-in patterns",
+			zap.String("review_id", review.ID),
+			zap.Error(err),
+		)
+		screener, _ = injection.NewScreener(&config.PromptInjectionConfig{}, classifier)
+	}
+
+	inputs := []injection.Input{
+		inputPRTitle:       {Source: model.InjectionSourcePRTitle, Content: req.PRTitle},
+		inputPRDescription: {Source: model.InjectionSourcePRDescription, Content: req.PRDescription},
+	}
+	inputs = append(inputs, injection.CommitInputs(ctx, req.RepoPath, req.BaseCommitSHA, req.CommitSHA)...)
+	if cfg.ScanFiles {
+		inputs = append(inputs, injection.FileInputs(req.RepoPath, req.ChangedFiles, cfg.GetMaxFileSize())...)
+	}
+
+	detections := screener.Screen(ctx, inputs)
+	if len(detections) == 0 {
+		r.recordInjections(review, nil, "")
+		return nil, nil
+	}
+
+	var repoConfig *model.RepositoryReviewConfig
+	if req.RepoURL != "" {
+		repoConfig, _ = r.store.RepositoryConfig().GetByRepoURL(req.RepoURL)
+	}
+	action := injection.ActionOf(repoConfig)
+	if action == model.InjectionActionStrip && review.InjectionApprovedAt == nil && !strippable(detections) {
+		// Commit messages and file contents can't be stripped: they reach the prompt and the agent
+		// tools unchanged, so the review is held instead
+		action = model.InjectionActionQuarantine
+	}
+	if action == model.InjectionActionQuarantine && review.InjectionApprovedAt != nil {
+		// Released by an administrator: run the review with a warning
+		action = model.InjectionActionWarn
+	}
+
+	if action == model.InjectionActionStrip {
+		req.PRTitle = injection.Strip(req.PRTitle, inputPRTitle, detections)
+		req.PRDescription = injection.Strip(req.PRDescription, inputPRDescription, detections)
+		for i := range detections {
+			if detections[i].Input == inputPRTitle || detections[i].Input == inputPRDescription {
+				detections[i].Stripped = true
+			}
+		}
+	}
+
+	records := injection.Records(detections)
+	for _, d := range records {
+		logger.Warn("Possible prompt injection in PR-supplied content",
+			zap.String("review_id", review.ID),
+			zap.String("source", d.Source),
+			zap.String("location", d.Location),
+			zap.String("detector", d.Detector),
+			zap.String("excerpt", d.Excerpt),
+			zap.String("action", string(action)),
+		)
+	}
+	r.recordInjections(review, records, action)
+
+	if action != model.InjectionActionQuarantine {
+		return records, nil
+	}
+
+	review.Status = model.ReviewStatusQuarantined
+	msg := fmt.Sprintf("quarantined: %d possible prompt injection(s) in the PR-supplied content await approval", len(records))
+	if err := r.store.Review().UpdateMetadata(review.ID, map[string]interface{}{
+		"status":        model.ReviewStatusQuarantined,
+		"error_message": msg,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to quarantine review: %w", err)
+	}
+	return nil, ErrReviewQuarantined
+}
+
+// strippable returns true if all detections are in the PR title or description,
+// the only PR-supplied content the strip action can remove
+func strippable(detections []injection.Detection) bool {
+	for _, d := range detections {
+		if d.Input != inputPRTitle && d.Input != inputPRDescription {
+			return false
+		}
+	}
+	return true
+}
+
+// injectionClassifier returns the classifier of the prompt injection settings, or nil if the
+// classifier pass is disabled or its agent is not configured
+func (r *Runner) injectionClassifier(cfg *config.PromptInjectionConfig, req *ReviewRequest, review *model.Review) injection.Classifier {
+	if !cfg.Classifier.Enabled || r.executor == nil {
+		return nil
+	}
+	agent, ok := r.executor.Agent(cfg.Classifier.Agent)
+	if !ok {
+		logger.Warn("Prompt injection classifier agent is not configured, screening with patterns only",
+			zap.String("review_id", review.ID),
+			zap.String("agent", cfg.Classifier.Agent),
+		)
+		return nil
+	}
+	return injection.NewAgentClassifier(agent, cfg.Classifier.Model, req.RepoPath, review.ID)
+}
+
+// recordInjections stores the detections of the last screening and the action taken on the review
+func (r *Runner) recordInjections(review *model.Review, records model.InjectionDetections, action model.InjectionAction) {
+	review.InjectionDetections = records
+	review.InjectionAction = action
+	if err := r.store.Review().UpdateMetadata(review.ID, map[string]interface{}{
+		"injection_detections": records,
+		"injection_action":     action,
+	}); err != nil {
+		logger.Warn("Failed to record prompt injection detections",
+			zap.String("review_id", review.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// screenRuleExecution screens the PR-supplied content of a single rule execution (a rule retry)
+// and applies the strip action to the build context. If the review is quarantined, the rule is
+// reset to pending, to run once the review is approved, and ErrReviewQuarantined is returned.
+func (r *Runner) screenRuleExecution(ctx context.Context, execCtx *RuleExecutionContext) (model.InjectionDetections, error) {
+	buildCtx := execCtx.BuildCtx
+	req := &ReviewRequest{
+		RepoPath:      buildCtx.RepoPath,
+		RepoURL:       buildCtx.RepoURL,
+		CommitSHA:     buildCtx.CommitSHA,
+		BaseCommitSHA: buildCtx.BaseCommitSHA,
+		PRTitle:       buildCtx.PRTitle,
+		PRDescription: buildCtx.PRDescription,
+		Source:        buildCtx.Source,
+		ChangedFiles:  buildCtx.ChangedFiles,
+	}
+	injections, err := r.screenPromptInjection(ctx, req, execCtx.Review)
+	if err != nil {
+		if errors.Is(err, ErrReviewQuarantined) {
+			execCtx.ReviewRule.Status = model.RuleStatusPending
+			if updateErr := r.store.Review().UpdateRule(execCtx.ReviewRule); updateErr != nil {
+				logger.Warn("Failed to reset review rule of quarantined review",
+					zap.String("review_id", execCtx.Review.ID),
+					zap.String("rule_id", execCtx.ReviewRule.RuleID),
+					zap.Error(updateErr),
+				)
+			}
+		}
+		return nil, err
+	}
+	buildCtx.PRTitle = req.PRTitle
+	buildCtx.PRDescription = req.PRDescription
+	return injections, nil
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/verustcode/verustcode/internal/config"
 	"github.com/verustcode/verustcode/internal/dsl"
 	"github.com/verustcode/verustcode/internal/engine/executor"
+	"github.com/verustcode/verustcode/internal/engine/injection"
+	"github.com/verustcode/verustcode/internal/engine/utils"
 	"github.com/verustcode/verustcode/internal/git/provider"
 	"github.com/verustcode/verustcode/internal/model"
 	"github.com/verustcode/verustcode/internal/output"
@@ -28,8 +30,16 @@ type ReviewRequest struct {
 	PRNumber          int
 	PRTitle           string
 	PRDescription     string
+	PushCommits       string
 	BaseCommitSHA     string
+	Source            string
+	TargetBranch      string
+	Author            string
+	Labels            []string
+	LinesChanged      int
+	FilesChanged      int
 	ChangedFiles      []string
+	Commits           []string
 	ReviewRulesConfig *dsl.ReviewRulesConfig
 	OutputDir         string
 }
@@ -93,6 +103,12 @@ func (r *Runner) RunReviewWithTracking(ctx context.Context, req *ReviewRequest,
 		return nil, nil
 	}
 
+	// Screen the PR-supplied content before any rule sees it
+	injections, err := r.screenPromptInjection(ctx, req, review)
+	if err != nil {
+		return nil, err
+	}
+
 	// Load existing review rules to resume if any
 	existingRules, err := r.loadExistingReviewRules(review.ID, rulesConfig.Rules)
 	if err != nil {
@@ -119,6 +135,9 @@ func (r *Runner) RunReviewWithTracking(ctx context.Context, req *ReviewRequest,
 		}
 	}
 
+	// Changed files used to evaluate when clauses, resolved once for all rules
+	changedFiles := r.resolveChangedFiles(ctx, req, rulesConfig.Rules)
+
 	// Execute each rule
 	for i, rule := range rulesConfig.Rules {
 		// Check if rule already exists in database (for resume)
@@ -137,9 +156,26 @@ func (r *Runner) RunReviewWithTracking(ctx context.Context, req *ReviewRequest,
 				)
 				continue
 			}
+		}
 
+		// Evaluate the rule's when clause before running it
+		when := rule.When.Evaluate(&dsl.WhenContext{
+			ChangedFiles: changedFiles,
+			TargetBranch: req.TargetBranch,
+			Labels:       req.Labels,
+			Author:       req.Author,
+			LinesChanged: req.LinesChanged,
+			FilesChanged: req.FilesChanged,
+		})
+		if !when.Matched {
+			r.skipRule(review, reviewRule, rule.ID, when.Reason)
+			continue
+		}
+
+		if reviewRule != nil {
 			// Update status to running for resume
 			reviewRule.Status = model.RuleStatusRunning
+			reviewRule.SkipReason = ""
 			if err := r.store.Review().UpdateRule(reviewRule); err != nil {
 				logger.Warn("Failed to update review rule status",
 					zap.String("review_id", review.ID),
@@ -182,13 +218,22 @@ func (r *Runner) RunReviewWithTracking(ctx context.Context, req *ReviewRequest,
 			PRNumber:       req.PRNumber,
 			PRTitle:        req.PRTitle,
 			PRDescription:  req.PRDescription,
+			PushCommits:    req.PushCommits,
 			BaseCommitSHA:  req.BaseCommitSHA,
+			Source:         req.Source,
 			ChangedFiles:   req.ChangedFiles,
+			Commits:        req.Commits,
 			OutputLanguage: outputLanguage,
+			Areas:          rulesConfig.Areas,
 		}
 
 		// Load base rule if using inheritance
 		ruleBuildCtx := buildCtx
+		if when.Files != nil {
+			// Scope the prompt to the files matched by the when clause
+			ruleBuildCtx.ChangedFiles = when.Files
+			ruleBuildCtx.Languages = when.Languages
+		}
 		if rule.HistoryCompare != nil && rule.HistoryCompare.Enabled && review.PRURL != "" {
 			previousResult, found, err := r.store.Review().FindPreviousReviewResult(review.PRURL, rule.ID, review.ID)
 			if err == nil && found {
@@ -216,21 +261,17 @@ func (r *Runner) RunReviewWithTracking(ctx context.Context, req *ReviewRequest,
 
 		lastResult = result
 
-		// Save complete AI response as JSON to ReviewResult
-		if len(result.Data) > 0 {
-			reviewResult := &model.ReviewResult{
-				ReviewRuleID: reviewRule.ID,
-				Data:         model.JSONMap(result.Data),
-			}
-			if err := r.store.Review().CreateResult(reviewResult); err != nil {
-				logger.Warn("Failed to save review result",
-					zap.String("review_id", review.ID),
-					zap.String("rule_id", rule.ID),
-					zap.Error(err),
-				)
+		// Warn about possible prompt injections in the published output
+		if len(injections) > 0 {
+			if result.Data == nil {
+				result.Data = make(map[string]any)
 			}
+			result.Data["prompt_injection"] = injection.Data(injections)
 		}
 
+		// Apply the rule's policy and suppressions before the result is published or saved
+		r.processFindings(result, &rule, reviewRule, &buildCtx)
+
 		// Publish result (skip if rule was already completed before this execution)
 		shouldPublish := true
 		if wasAlreadyCompleted && reviewRule.Status == model.RuleStatusCompleted {
@@ -242,8 +283,11 @@ func (r *Runner) RunReviewWithTracking(ctx context.Context, req *ReviewRequest,
 		}
 
 		if shouldPublish {
-			r.publishRuleResult(ctx, result, &rule, review, &buildCtx, prov, prInfo, req.OutputDir)
+			r.publishRuleResult(ctx, result, &rule, reviewRule, review, &buildCtx, prov, prInfo, req.OutputDir)
 		}
+
+		// Save complete AI response as JSON to ReviewResult (after policy and suppressions were applied)
+		r.saveRuleResult(result, reviewRule, review)
 	}
 
 	// Check if all rules are completed and update review status
@@ -257,6 +301,7 @@ func (r *Runner) RunReviewWithTracking(ctx context.Context, req *ReviewRequest,
 
 // ExecuteSingleRule executes a single rule with the given context.
 // This method can be used by both RunReviewWithTracking and RetryRule.
+// Returns ErrReviewQuarantined if screening the PR-supplied content quarantined the review.
 func (r *Runner) ExecuteSingleRule(ctx context.Context, execCtx *RuleExecutionContext) (*prompt.ReviewResult, error) {
 	rule := execCtx.Rule
 	reviewRule := execCtx.ReviewRule
@@ -295,6 +340,12 @@ func (r *Runner) ExecuteSingleRule(ctx context.Context, execCtx *RuleExecutionCo
 		}
 	}
 
+	// Screen the PR-supplied content again, like a full run of the review
+	injections, err := r.screenRuleExecution(ctx, execCtx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Execute rule using executor
 	result, err := r.executor.ExecuteRule(ctx, rule, buildCtx, reviewRule, execCtx.RuleIndex)
 	if err != nil {
@@ -307,25 +358,87 @@ func (r *Runner) ExecuteSingleRule(ctx context.Context, execCtx *RuleExecutionCo
 		result.Error = err.Error()
 	}
 
-	// Save complete AI response as JSON to ReviewResult
-	if len(result.Data) > 0 {
-		reviewResult := &model.ReviewResult{
-			ReviewRuleID: reviewRule.ID,
-			Data:         model.JSONMap(result.Data),
+	// Warn about possible prompt injections in the published output
+	if len(injections) > 0 {
+		if result.Data == nil {
+			result.Data = make(map[string]any)
+		}
+		result.Data["prompt_injection"] = injection.Data(injections)
+	}
+
+	// Apply the rule's policy and suppressions before the result is published or saved
+	r.processFindings(result, rule, reviewRule, buildCtx)
+
+	// Publish result
+	r.publishRuleResult(ctx, result, rule, reviewRule, review, buildCtx, execCtx.Provider, execCtx.PRInfo, execCtx.OutputDir)
+
+	// Save complete AI response as JSON to ReviewResult (after policy and suppressions were applied)
+	r.saveRuleResult(result, reviewRule, review)
+
+	return result, nil
+}
+
+// resolveChangedFiles returns the changed files used to evaluate when clauses.
+// The files are only looked up in git when a rule has path or language conditions.
+// Returns nil if the change set is unknown (e.g. full repository audits).
+func (r *Runner) resolveChangedFiles(ctx context.Context, req *ReviewRequest, rules []dsl.ReviewRuleConfig) []string {
+	if len(req.ChangedFiles) > 0 {
+		return req.ChangedFiles
+	}
+
+	needed := false
+	for i := range rules {
+		if rules[i].When.NeedsChangedFiles() {
+			needed = true
+			break
+		}
+	}
+	if !needed || req.BaseCommitSHA == "" || req.CommitSHA == "" {
+		return nil
+	}
+
+	return utils.GetChangedFiles(ctx, req.RepoPath, req.BaseCommitSHA, req.CommitSHA)
+}
+
+// skipRule records a rule as skipped because its when clause did not match.
+func (r *Runner) skipRule(review *model.Review, reviewRule *model.ReviewRule, ruleID, reason string) {
+	logger.Info("Rule when clause did not match, skipping",
+		zap.String("review_id", review.ID),
+		zap.String("rule_id", ruleID),
+		zap.String("reason", reason),
+	)
+
+	now := time.Now()
+	if reviewRule == nil {
+		reviewRule = &model.ReviewRule{
+			ReviewID:    review.ID,
+			RuleID:      ruleID,
+			Status:      model.RuleStatusSkipped,
+			SkipReason:  reason,
+			StartedAt:   &now,
+			CompletedAt: &now,
 		}
-		if err := r.store.Review().CreateResult(reviewResult); err != nil {
-			logger.Warn("Failed to save review result",
+		if err := r.store.Review().CreateRule(reviewRule); err != nil {
+			logger.Warn("Failed to create skipped review rule record",
 				zap.String("review_id", review.ID),
-				zap.String("rule_id", rule.ID),
+				zap.String("rule_id", ruleID),
 				zap.Error(err),
 			)
 		}
+		return
 	}
 
-	// Publish result
-	r.publishRuleResult(ctx, result, rule, review, buildCtx, execCtx.Provider, execCtx.PRInfo, execCtx.OutputDir)
-
-	return result, nil
+	reviewRule.Status = model.RuleStatusSkipped
+	reviewRule.SkipReason = reason
+	reviewRule.ErrorMessage = ""
+	reviewRule.CompletedAt = &now
+	if err := r.store.Review().UpdateRule(reviewRule); err != nil {
+		logger.Warn("Failed to update skipped review rule record",
+			zap.String("review_id", review.ID),
+			zap.String("rule_id", ruleID),
+			zap.Error(err),
+		)
+	}
 }
 
 // UpdateReviewStatusAfterRuleExecution checks all rules and updates review status accordingly.
@@ -342,6 +455,11 @@ func (r *Runner) UpdateReviewStatusAfterRuleExecution(review *model.Review) {
 		return
 	}
 
+	// A quarantined review waits for approval, which runs its remaining rules
+	if currentReview.Status == model.ReviewStatusQuarantined {
+		return
+	}
+
 	allRules, err := r.store.Review().GetRulesByReviewID(review.ID)
 	if err != nil {
 		logger.Warn("Failed to load all rules for status update",
@@ -351,11 +469,31 @@ func (r *Runner) UpdateReviewStatusAfterRuleExecution(review *model.Review) {
 		return
 	}
 
+	// Keep the review's token usage in sync with its rules, including failed ones
+	var usage model.TokenUsage
+	for _, rl := range allRules {
+		usage.Add(rl.TokenUsage)
+	}
+	if usage != currentReview.TokenUsage {
+		if err := r.store.Review().UpdateMetadata(review.ID, map[string]interface{}{
+			"prompt_tokens":     usage.PromptTokens,
+			"completion_tokens": usage.CompletionTokens,
+			"cached_tokens":     usage.CachedTokens,
+			"cost":              usage.Cost,
+		}); err != nil {
+			logger.Warn("Failed to update review token usage",
+				zap.String("review_id", review.ID),
+				zap.Error(err),
+			)
+		}
+	}
+
 	allCompleted := true
 	hasFailed := false
 	hasRunning := false
 	for _, rl := range allRules {
-		if rl.Status != model.RuleStatusCompleted {
+		// Rules skipped by their when clause count as completed
+		if rl.Status != model.RuleStatusCompleted && rl.Status != model.RuleStatusSkipped {
 			allCompleted = false
 		}
 		if rl.Status == model.RuleStatusFailed {
@@ -399,8 +537,27 @@ func (r *Runner) UpdateReviewStatusAfterRuleExecution(review *model.Review) {
 	}
 }
 
+// saveRuleResult saves the complete AI response of a rule as JSON to ReviewResult.
+func (r *Runner) saveRuleResult(result *prompt.ReviewResult, reviewRule *model.ReviewRule, review *model.Review) {
+	if len(result.Data) == 0 {
+		return
+	}
+	reviewResult := &model.ReviewResult{
+		ReviewRuleID: reviewRule.ID,
+		Data:         model.JSONMap(result.Data),
+	}
+	if err := r.store.Review().CreateResult(reviewResult); err != nil {
+		logger.Warn("Failed to save review result",
+			zap.String("review_id", review.ID),
+			zap.String("rule_id", reviewRule.RuleID),
+			zap.Error(err),
+		)
+	}
+}
+
 // publishRuleResult publishes the result of a single rule execution.
-func (r *Runner) publishRuleResult(ctx context.Context, result *prompt.ReviewResult, rule *dsl.ReviewRuleConfig, review *model.Review, buildCtx *prompt.BuildContext, prov provider.Provider, prInfo *provider.PullRequest, outputDir string) {
+// The findings must already have been processed by processFindings.
+func (r *Runner) publishRuleResult(ctx context.Context, result *prompt.ReviewResult, rule *dsl.ReviewRuleConfig, reviewRule *model.ReviewRule, review *model.Review, buildCtx *prompt.BuildContext, prov provider.Provider, prInfo *provider.PullRequest, outputDir string) {
 	var outputCfg *dsl.OutputConfig
 	if rule.Output != nil && len(rule.Output.Channels) > 0 {
 		outputCfg = rule.Output
@@ -430,11 +587,19 @@ func (r *Runner) publishRuleResult(ctx context.Context, result *prompt.ReviewRes
 		metadataConfig = &reviewCfg.OutputMetadata
 	}
 
+	// Only pushes are commented on their commit; other reviews without a PR
+	// (API, CLI and scheduled audits) are not tied to a change under discussion
+	var commentCommitSHA string
+	if buildCtx.PRNumber == 0 && buildCtx.Source == "webhook" {
+		commentCommitSHA = buildCtx.CommitSHA
+	}
+
 	publishOpts := &output.PublishOptions{
 		ReviewID:       review.ID,
 		RepoURL:        buildCtx.RepoURL,
 		Ref:            buildCtx.Ref,
 		PRNumber:       buildCtx.PRNumber,
+		CommitSHA:      commentCommitSHA,
 		PRInfo:         prInfo,
 		OutputDir:      outputDir,
 		RepoPath:       buildCtx.RepoPath,
@@ -0,0 +1,99 @@
+// Package runner provides the ReviewRunner which handles review execution logic.
+// This file post-processes the findings of rule results before they are published and saved.
+package runner
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/engine/suppression"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/prompt"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// processFindings applies the rule's policy statements to the findings of a rule result,
+// then removes the findings suppressed by verust:ignore directives or the repository baseline.
+// The removed findings are listed in result.Data["suppressed"] and the rule's findings
+// counts are updated.
+func (r *Runner) processFindings(result *prompt.ReviewResult, rule *dsl.ReviewRuleConfig, reviewRule *model.ReviewRule, buildCtx *prompt.BuildContext) {
+	if result == nil || result.Data == nil {
+		return
+	}
+	findings, ok := result.Data["findings"].([]interface{})
+	if !ok || len(findings) == 0 {
+		return
+	}
+
+	changed := false
+	if len(rule.Policy) > 0 {
+		var policyResult dsl.PolicyResult
+		findings, policyResult = dsl.ApplyPolicy(findings, rule.Policy)
+		for _, msg := range policyResult.Errors {
+			logger.Warn("Policy statement failed to evaluate",
+				zap.String("rule_id", rule.ID),
+				zap.String("error", msg),
+			)
+		}
+		if policyResult.Changed() {
+			logger.Info("Applied policy to findings",
+				zap.String("rule_id", rule.ID),
+				zap.Int("severity_changed", policyResult.SeverityChanged),
+				zap.Int("dropped", policyResult.Dropped),
+			)
+			result.Data["findings"] = findings
+			changed = true
+		}
+	}
+
+	suppressed := r.suppressFindings(result, findings, rule, buildCtx)
+	if len(suppressed) > 0 {
+		changed = true
+	}
+
+	if !changed || reviewRule == nil {
+		return
+	}
+	kept, _ := result.Data["findings"].([]interface{})
+	reviewRule.FindingsCount = len(kept)
+	reviewRule.SuppressedCount = len(suppressed)
+	if err := r.store.Review().UpdateRule(reviewRule); err != nil {
+		logger.Warn("Failed to update findings count of review rule",
+			zap.String("rule_id", rule.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// suppressFindings removes the findings suppressed by verust:ignore directives or the
+// repository baseline from a rule result. Returns the suppressed findings.
+func (r *Runner) suppressFindings(result *prompt.ReviewResult, findings []interface{}, rule *dsl.ReviewRuleConfig, buildCtx *prompt.BuildContext) []suppression.Suppressed {
+	if buildCtx == nil || buildCtx.RepoPath == "" || len(findings) == 0 {
+		return nil
+	}
+
+	// For pull requests the baseline is read from the base commit, so that a change
+	// can't accept its own findings
+	baseline, err := suppression.LoadBaseline(buildCtx.RepoPath, buildCtx.BaseCommitSHA)
+	if err != nil {
+		logger.Warn("Failed to load baseline, only suppression directives are applied",
+			zap.String("rule_id", rule.ID),
+			zap.Error(err),
+		)
+	}
+
+	kept, suppressed := suppression.Filter(findings, rule.ID, buildCtx.RepoPath, baseline)
+	if len(suppressed) == 0 {
+		return nil
+	}
+
+	result.Data["findings"] = kept
+	result.Data["suppressed"] = suppression.SuppressedData(suppressed)
+
+	logger.Info("Suppressed findings",
+		zap.String("rule_id", rule.ID),
+		zap.Int("suppressed", len(suppressed)),
+		zap.Int("remaining", len(kept)),
+	)
+	return suppressed
+}
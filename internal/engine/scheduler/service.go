@@ -0,0 +1,318 @@
+// Package scheduler provides the scheduled audit service.
+// It registers ReviewSchedule records with a cron scheduler and submits a review
+// with Source "schedule" each time a schedule fires.
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/verustcode/verustcode/internal/engine/task"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/store"
+	"github.com/verustcode/verustcode/pkg/errors"
+	"github.com/verustcode/verustcode/pkg/idgen"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+const (
+	// ReviewSource is the Review.Source value used for scheduled audits
+	ReviewSource = "schedule"
+
+	// historyLookback is how many past runs are searched for the last reviewed commit
+	historyLookback = 50
+)
+
+// ReviewSubmitter submits a review to the engine queue.
+// Implemented by engine.Engine.
+type ReviewSubmitter interface {
+	Submit(review *model.Review, prInfo *task.PRInfo, outputDir ...string) (*task.Task, error)
+}
+
+// Service manages cron entries for review schedules and triggers scheduled audits.
+type Service struct {
+	store     store.Store
+	submitter ReviewSubmitter
+	cron      *cron.Cron
+
+	// entries maps schedule ID to its cron entry
+	entries map[uint]cron.EntryID
+	running bool
+	mu      sync.Mutex
+}
+
+// NewService creates a new scheduler service
+func NewService(s store.Store, submitter ReviewSubmitter) *Service {
+	return &Service{
+		store:     s,
+		submitter: submitter,
+		cron:      cron.New(),
+		entries:   make(map[uint]cron.EntryID),
+	}
+}
+
+// ValidateCronExpr validates a standard 5-field cron expression.
+// Descriptors such as "@weekly" and a "CRON_TZ=" prefix are also accepted.
+func ValidateCronExpr(expr string) error {
+	if _, err := cron.ParseStandard(expr); err != nil {
+		return errors.New(errors.ErrCodeValidation, fmt.Sprintf("invalid cron expression %q: %v", expr, err))
+	}
+	return nil
+}
+
+// NextRunTime returns the next activation time of a cron expression after from
+func NextRunTime(expr string, from time.Time) (time.Time, error) {
+	sched, err := cron.ParseStandard(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sched.Next(from), nil
+}
+
+// Start registers all enabled schedules and starts the cron scheduler
+func (s *Service) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return nil
+	}
+
+	if err := s.loadEntriesLocked(); err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	s.running = true
+
+	logger.Info("Scheduler service started", zap.Int("schedules", len(s.entries)))
+	return nil
+}
+
+// Stop stops the cron scheduler and waits for running triggers to finish
+func (s *Service) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = false
+	s.mu.Unlock()
+
+	logger.Info("Stopping scheduler service")
+	ctx := s.cron.Stop()
+	<-ctx.Done()
+	logger.Info("Scheduler service stopped")
+}
+
+// Reload re-reads schedules from the database and rebuilds all cron entries.
+// Call this after a schedule is created, updated or deleted.
+func (s *Service) Reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, entryID := range s.entries {
+		s.cron.Remove(entryID)
+		delete(s.entries, id)
+	}
+
+	return s.loadEntriesLocked()
+}
+
+// loadEntriesLocked registers a cron entry for every enabled schedule.
+// Caller must hold s.mu.
+func (s *Service) loadEntriesLocked() error {
+	schedules, err := s.store.Schedule().ListEnabled()
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeDBQuery, "failed to load review schedules", err)
+	}
+
+	for _, sched := range schedules {
+		scheduleID := sched.ID
+		entryID, err := s.cron.AddFunc(sched.CronExpr, func() {
+			if _, err := s.Trigger(scheduleID); err != nil {
+				logger.Warn("Scheduled audit trigger failed",
+					zap.Uint("schedule_id", scheduleID),
+					zap.Error(err),
+				)
+			}
+		})
+		if err != nil {
+			// Skip invalid expressions instead of failing all schedules
+			logger.Warn("Skipping schedule with invalid cron expression",
+				zap.Uint("schedule_id", sched.ID),
+				zap.String("cron_expr", sched.CronExpr),
+				zap.Error(err),
+			)
+			continue
+		}
+		s.entries[sched.ID] = entryID
+	}
+
+	return nil
+}
+
+// NextRun returns the next run time of a registered schedule.
+// Returns nil if the schedule is disabled, not registered or the scheduler is not running.
+func (s *Service) NextRun(scheduleID uint) *time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entryID, ok := s.entries[scheduleID]
+	if !ok || !s.running {
+		return nil
+	}
+
+	next := s.cron.Entry(entryID).Next
+	if next.IsZero() {
+		return nil
+	}
+	return &next
+}
+
+// Trigger runs a schedule immediately and records the run in the schedule history.
+// A run is skipped if the review created by the previous run is still pending or running.
+func (s *Service) Trigger(scheduleID uint) (*model.ReviewScheduleRun, error) {
+	sched, err := s.store.Schedule().GetByID(scheduleID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.ErrCodeNotFound, "schedule not found")
+		}
+		return nil, errors.Wrap(errors.ErrCodeDBQuery, "failed to load schedule", err)
+	}
+
+	now := time.Now()
+	run := &model.ReviewScheduleRun{
+		ScheduleID:  sched.ID,
+		TriggeredAt: now,
+	}
+
+	// Do not pile up audits when the previous one has not finished yet
+	if sched.LastReviewID != "" {
+		last, err := s.store.Review().GetByID(sched.LastReviewID)
+		if err == nil && (last.Status == model.ReviewStatusPending || last.Status == model.ReviewStatusRunning) {
+			run.Status = model.ScheduleRunStatusSkipped
+			run.ErrorMessage = fmt.Sprintf("previous scheduled review %s is still %s", last.ID, last.Status)
+			return run, s.recordRun(run)
+		}
+	}
+
+	// Incremental audits review everything since the last completed scheduled review
+	if sched.Mode == model.ScheduleModeIncremental {
+		run.BaseCommitSHA = s.lastReviewedCommit(sched.ID)
+	}
+
+	review := &model.Review{
+		ID:            idgen.NewReviewID(),
+		RepoURL:       sched.RepoURL,
+		Ref:           sched.Ref,
+		Status:        model.ReviewStatusPending,
+		Source:        ReviewSource,
+		TriggeredBy:   "schedule:" + sched.Name,
+		BaseCommitSHA: run.BaseCommitSHA,
+		ReviewFile:    sched.ReviewFile,
+	}
+
+	if err := s.store.Review().Create(review); err != nil {
+		run.Status = model.ScheduleRunStatusFailed
+		run.ErrorMessage = "failed to create review: " + err.Error()
+		if recErr := s.recordRun(run); recErr != nil {
+			return run, recErr
+		}
+		return run, errors.Wrap(errors.ErrCodeDBQuery, "failed to create scheduled review", err)
+	}
+
+	// Ensure the repository appears in the Repositories list
+	if _, err := s.store.RepositoryConfig().EnsureConfig(sched.RepoURL); err != nil {
+		logger.Warn("Failed to ensure repository config for scheduled audit",
+			zap.String("repo_url", sched.RepoURL),
+			zap.Error(err),
+		)
+	}
+
+	var prInfo *task.PRInfo
+	if run.BaseCommitSHA != "" {
+		prInfo = &task.PRInfo{BaseSHA: run.BaseCommitSHA}
+	}
+
+	if _, err := s.submitter.Submit(review, prInfo); err != nil {
+		if dbErr := s.store.Review().UpdateStatusWithError(review.ID, model.ReviewStatusFailed, err.Error()); dbErr != nil {
+			logger.Error("Failed to update review status after schedule submission failure", zap.Error(dbErr))
+		}
+		run.ReviewID = review.ID
+		run.Status = model.ScheduleRunStatusFailed
+		run.ErrorMessage = err.Error()
+		if recErr := s.recordRun(run); recErr != nil {
+			return run, recErr
+		}
+		return run, err
+	}
+
+	run.ReviewID = review.ID
+	run.Status = model.ScheduleRunStatusSubmitted
+	if err := s.recordRun(run); err != nil {
+		return run, err
+	}
+
+	logger.Info("Scheduled audit submitted",
+		zap.Uint("schedule_id", sched.ID),
+		zap.String("schedule", sched.Name),
+		zap.String("review_id", review.ID),
+		zap.String("repo_url", sched.RepoURL),
+		zap.String("ref", sched.Ref),
+		zap.String("mode", string(sched.Mode)),
+		zap.String("base_commit_sha", run.BaseCommitSHA),
+	)
+
+	return run, nil
+}
+
+// lastReviewedCommit returns the head commit of the most recent completed scheduled review.
+// Failed or cancelled runs are skipped so their changes are covered by the next audit.
+// Returns empty string if there is none, which makes the run a full audit.
+func (s *Service) lastReviewedCommit(scheduleID uint) string {
+	runs, err := s.store.Schedule().ListRuns(scheduleID, historyLookback)
+	if err != nil {
+		logger.Warn("Failed to load schedule history",
+			zap.Uint("schedule_id", scheduleID),
+			zap.Error(err),
+		)
+		return ""
+	}
+
+	for _, run := range runs {
+		if run.Status != model.ScheduleRunStatusSubmitted || run.ReviewID == "" {
+			continue
+		}
+		review, err := s.store.Review().GetByID(run.ReviewID)
+		if err != nil {
+			continue
+		}
+		if review.Status == model.ReviewStatusCompleted && review.CommitSHA != "" {
+			return review.CommitSHA
+		}
+	}
+	return ""
+}
+
+// recordRun saves a run in the schedule history and updates the schedule's last run
+func (s *Service) recordRun(run *model.ReviewScheduleRun) error {
+	if err := s.store.Schedule().CreateRun(run); err != nil {
+		return errors.Wrap(errors.ErrCodeDBQuery, "failed to record schedule run", err)
+	}
+
+	// Skipped runs keep pointing at the review that is still in progress
+	if run.Status == model.ScheduleRunStatusSkipped {
+		return nil
+	}
+
+	if err := s.store.Schedule().UpdateLastRun(run.ScheduleID, run.TriggeredAt, run.ReviewID); err != nil {
+		return errors.Wrap(errors.ErrCodeDBQuery, "failed to update schedule last run", err)
+	}
+	return nil
+}
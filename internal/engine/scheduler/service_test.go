@@ -0,0 +1,215 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/verustcode/verustcode/internal/engine/task"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/store"
+)
+
+// fakeSubmitter records submitted reviews
+type fakeSubmitter struct {
+	reviews []*model.Review
+	prInfos []*task.PRInfo
+	err     error
+}
+
+func (f *fakeSubmitter) Submit(review *model.Review, prInfo *task.PRInfo, outputDir ...string) (*task.Task, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.reviews = append(f.reviews, review)
+	f.prInfos = append(f.prInfos, prInfo)
+	return &task.Task{Review: review}, nil
+}
+
+func createSchedule(t *testing.T, s store.Store, mode model.ScheduleMode) *model.ReviewSchedule {
+	t.Helper()
+	sched := &model.ReviewSchedule{
+		Name:       "nightly",
+		RepoURL:    "https://github.com/test/repo",
+		Ref:        "main",
+		CronExpr:   "0 2 * * *",
+		ReviewFile: "security.yaml",
+		Mode:       mode,
+		Enabled:    true,
+	}
+	if err := s.Schedule().Create(sched); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	return sched
+}
+
+func TestValidateCronExpr(t *testing.T) {
+	valid := []string{"0 2 * * *", "*/15 * * * *", "@weekly", "CRON_TZ=UTC 0 2 * * 1"}
+	for _, expr := range valid {
+		if err := ValidateCronExpr(expr); err != nil {
+			t.Errorf("ValidateCronExpr(%q) unexpected error: %v", expr, err)
+		}
+	}
+
+	invalid := []string{"", "0 2 * *", "61 * * * *", "every day"}
+	for _, expr := range invalid {
+		if err := ValidateCronExpr(expr); err == nil {
+			t.Errorf("ValidateCronExpr(%q) expected error", expr)
+		}
+	}
+}
+
+func TestNextRunTime(t *testing.T) {
+	from := time.Date(2025, 1, 6, 1, 0, 0, 0, time.Local) // Monday 01:00
+	next, err := NextRunTime("0 2 * * *", from)
+	if err != nil {
+		t.Fatalf("NextRunTime() failed: %v", err)
+	}
+	want := time.Date(2025, 1, 6, 2, 0, 0, 0, time.Local)
+	if !next.Equal(want) {
+		t.Errorf("NextRunTime() = %v, want %v", next, want)
+	}
+}
+
+func TestTrigger_SubmitsScheduledReview(t *testing.T) {
+	s, cleanup := store.SetupTestDB(t)
+	defer cleanup()
+
+	sched := createSchedule(t, s, model.ScheduleModeFull)
+	submitter := &fakeSubmitter{}
+	svc := NewService(s, submitter)
+
+	run, err := svc.Trigger(sched.ID)
+	if err != nil {
+		t.Fatalf("Trigger() failed: %v", err)
+	}
+	if run.Status != model.ScheduleRunStatusSubmitted {
+		t.Errorf("Expected submitted run, got %s", run.Status)
+	}
+	if len(submitter.reviews) != 1 {
+		t.Fatalf("Expected 1 submitted review, got %d", len(submitter.reviews))
+	}
+
+	review := submitter.reviews[0]
+	if review.Source != ReviewSource {
+		t.Errorf("Expected source %q, got %q", ReviewSource, review.Source)
+	}
+	if review.ReviewFile != "security.yaml" {
+		t.Errorf("Expected review file override, got %q", review.ReviewFile)
+	}
+	if submitter.prInfos[0] != nil {
+		t.Errorf("Expected full audit without base commit, got %+v", submitter.prInfos[0])
+	}
+
+	updated, _ := s.Schedule().GetByID(sched.ID)
+	if updated.LastReviewID != review.ID || updated.LastRunAt == nil {
+		t.Errorf("Expected last run to point at %s, got %+v", review.ID, updated)
+	}
+}
+
+func TestTrigger_SkipsWhilePreviousRunning(t *testing.T) {
+	s, cleanup := store.SetupTestDB(t)
+	defer cleanup()
+
+	sched := createSchedule(t, s, model.ScheduleModeFull)
+	submitter := &fakeSubmitter{}
+	svc := NewService(s, submitter)
+
+	first, err := svc.Trigger(sched.ID)
+	if err != nil {
+		t.Fatalf("Trigger() failed: %v", err)
+	}
+
+	// First review is still pending, so the next trigger is skipped
+	second, err := svc.Trigger(sched.ID)
+	if err != nil {
+		t.Fatalf("Trigger() failed: %v", err)
+	}
+	if second.Status != model.ScheduleRunStatusSkipped {
+		t.Errorf("Expected skipped run, got %s", second.Status)
+	}
+	if len(submitter.reviews) != 1 {
+		t.Errorf("Expected no new review while previous is pending, got %d", len(submitter.reviews))
+	}
+
+	updated, _ := s.Schedule().GetByID(sched.ID)
+	if updated.LastReviewID != first.ReviewID {
+		t.Errorf("Skipped run must not replace last review, got %s", updated.LastReviewID)
+	}
+
+	runs, _ := s.Schedule().ListRuns(sched.ID, 0)
+	if len(runs) != 2 {
+		t.Errorf("Expected 2 runs in history, got %d", len(runs))
+	}
+}
+
+func TestTrigger_IncrementalUsesLastCompletedCommit(t *testing.T) {
+	s, cleanup := store.SetupTestDB(t)
+	defer cleanup()
+
+	sched := createSchedule(t, s, model.ScheduleModeIncremental)
+	submitter := &fakeSubmitter{}
+	svc := NewService(s, submitter)
+
+	// First incremental run has no history, so it is a full audit
+	first, err := svc.Trigger(sched.ID)
+	if err != nil {
+		t.Fatalf("Trigger() failed: %v", err)
+	}
+	if first.BaseCommitSHA != "" {
+		t.Errorf("Expected no base commit for first run, got %q", first.BaseCommitSHA)
+	}
+
+	// Complete the first review at a known commit
+	if err := s.Review().UpdateMetadata(first.ReviewID, map[string]interface{}{
+		"commit_sha": "abc123",
+		"status":     model.ReviewStatusCompleted,
+	}); err != nil {
+		t.Fatalf("UpdateMetadata() failed: %v", err)
+	}
+
+	second, err := svc.Trigger(sched.ID)
+	if err != nil {
+		t.Fatalf("Trigger() failed: %v", err)
+	}
+	if second.BaseCommitSHA != "abc123" {
+		t.Errorf("Expected base commit abc123, got %q", second.BaseCommitSHA)
+	}
+	if info := submitter.prInfos[1]; info == nil || info.BaseSHA != "abc123" {
+		t.Errorf("Expected PRInfo with base SHA abc123, got %+v", info)
+	}
+}
+
+func TestTrigger_SubmitFailureRecorded(t *testing.T) {
+	s, cleanup := store.SetupTestDB(t)
+	defer cleanup()
+
+	sched := createSchedule(t, s, model.ScheduleModeFull)
+	svc := NewService(s, &fakeSubmitter{err: fmt.Errorf("engine is not running")})
+
+	run, err := svc.Trigger(sched.ID)
+	if err == nil {
+		t.Fatal("Expected Trigger() to return submit error")
+	}
+	if run.Status != model.ScheduleRunStatusFailed {
+		t.Errorf("Expected failed run, got %s", run.Status)
+	}
+
+	review, err := s.Review().GetByID(run.ReviewID)
+	if err != nil {
+		t.Fatalf("GetByID() failed: %v", err)
+	}
+	if review.Status != model.ReviewStatusFailed {
+		t.Errorf("Expected review to be marked failed, got %s", review.Status)
+	}
+}
+
+func TestTrigger_NotFound(t *testing.T) {
+	s, cleanup := store.SetupTestDB(t)
+	defer cleanup()
+
+	svc := NewService(s, &fakeSubmitter{})
+	if _, err := svc.Trigger(999); err == nil {
+		t.Error("Expected error for unknown schedule")
+	}
+}
@@ -0,0 +1,202 @@
+// Package suppression filters out review findings that a repository marks as intentional.
+// This file handles the baseline file (.verustcode/baseline.yaml) of accepted findings.
+package suppression
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/pkg/errors"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// BaselineVersion is the version of the baseline file format
+const BaselineVersion = "1.0"
+
+// ExpiresLayout is the date format of baseline entry expiry dates
+const ExpiresLayout = "2006-01-02"
+
+// Baseline lists the fingerprints of accepted findings
+type Baseline struct {
+	Version  string          `yaml:"version,omitempty" json:"version,omitempty"`
+	Findings []BaselineEntry `yaml:"findings" json:"findings"`
+
+	// byFingerprint indexes the entries that have not expired
+	byFingerprint map[string]*BaselineEntry
+}
+
+// BaselineEntry is an accepted finding
+type BaselineEntry struct {
+	// Fingerprint identifies the finding (see FindingFingerprint)
+	Fingerprint string `yaml:"fingerprint" json:"fingerprint"`
+
+	// Rule, Title and Location describe the finding for readers of the file
+	Rule     string `yaml:"rule,omitempty" json:"rule,omitempty"`
+	Title    string `yaml:"title,omitempty" json:"title,omitempty"`
+	Location string `yaml:"location,omitempty" json:"location,omitempty"`
+
+	// Reason explains why the finding is accepted
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty"`
+
+	// Expires is the date (YYYY-MM-DD) after which the finding is reported again (optional)
+	Expires string `yaml:"expires,omitempty" json:"expires,omitempty"`
+}
+
+// Expired returns true if the entry has expired at the given time
+func (e *BaselineEntry) Expired(now time.Time) bool {
+	if e.Expires == "" {
+		return false
+	}
+	expires, err := time.Parse(ExpiresLayout, e.Expires)
+	if err != nil {
+		return false
+	}
+	// The entry is valid through the whole expiry day
+	return now.After(expires.AddDate(0, 0, 1))
+}
+
+// ParseBaseline parses a baseline file. Expired entries are kept in Findings but not matched.
+func ParseBaseline(data []byte, now time.Time) (*Baseline, error) {
+	var baseline Baseline
+	if err := yaml.Unmarshal(data, &baseline); err != nil {
+		return nil, errors.Wrap(errors.ErrCodeConfigInvalid, "failed to parse baseline YAML", err)
+	}
+
+	baseline.byFingerprint = make(map[string]*BaselineEntry, len(baseline.Findings))
+	for i := range baseline.Findings {
+		entry := &baseline.Findings[i]
+		if entry.Fingerprint == "" {
+			return nil, errors.New(errors.ErrCodeConfigInvalid,
+				fmt.Sprintf("baseline findings[%d]: fingerprint is required", i))
+		}
+		if entry.Expires != "" {
+			if _, err := time.Parse(ExpiresLayout, entry.Expires); err != nil {
+				return nil, errors.New(errors.ErrCodeConfigInvalid,
+					fmt.Sprintf("baseline findings[%d]: invalid expires date %q (format: YYYY-MM-DD)", i, entry.Expires))
+			}
+		}
+		if entry.Expired(now) {
+			logger.Debug("Baseline entry has expired",
+				zap.String("fingerprint", entry.Fingerprint),
+				zap.String("expires", entry.Expires),
+			)
+			continue
+		}
+		baseline.byFingerprint[entry.Fingerprint] = entry
+	}
+	return &baseline, nil
+}
+
+// LoadBaseline loads the baseline file of a repository.
+// If revision is set, the file is read as committed at that revision (e.g. the base commit of
+// a pull request, so that a change can't accept its own findings); otherwise from the working tree.
+// Returns nil if the repository has no baseline file.
+func LoadBaseline(repoPath, revision string) (*Baseline, error) {
+	if repoPath == "" {
+		return nil, nil
+	}
+
+	var data []byte
+	if revision == "" {
+		content, err := os.ReadFile(filepath.Join(repoPath, filepath.FromSlash(config.RepoBaselinePath)))
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrCodeConfigInvalid, "failed to read "+config.RepoBaselinePath, err)
+		}
+		data = content
+	} else {
+		object := revision + ":" + config.RepoBaselinePath
+		if err := exec.Command("git", "-C", repoPath, "cat-file", "-e", object).Run(); err != nil {
+			return nil, nil
+		}
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command("git", "-C", repoPath, "show", object)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, errors.Wrap(errors.ErrCodeConfigInvalid,
+				"failed to read "+object+": "+strings.TrimSpace(stderr.String()), err)
+		}
+		data = stdout.Bytes()
+	}
+
+	return ParseBaseline(data, time.Now())
+}
+
+// Match returns the baseline entry of a fingerprint, or nil if the finding is not accepted.
+// A nil baseline matches nothing.
+func (b *Baseline) Match(fingerprint string) *BaselineEntry {
+	if b == nil {
+		return nil
+	}
+	return b.byFingerprint[fingerprint]
+}
+
+// Add adds a finding of a rule to the baseline, unless it is already listed
+func (b *Baseline) Add(ruleID string, finding Finding, reason, expires string) {
+	b.AddEntry(BaselineEntry{
+		Fingerprint: FindingFingerprint(ruleID, finding),
+		Rule:        ruleID,
+		Title:       stringField(finding, "title"),
+		Location:    stringField(finding, "location"),
+		Reason:      reason,
+		Expires:     expires,
+	})
+}
+
+// AddEntry adds an entry to the baseline, unless its fingerprint is already listed
+func (b *Baseline) AddEntry(entry BaselineEntry) {
+	for _, existing := range b.Findings {
+		if existing.Fingerprint == entry.Fingerprint {
+			return
+		}
+	}
+	b.Findings = append(b.Findings, entry)
+}
+
+// Marshal encodes the baseline as YAML
+func (b *Baseline) Marshal() ([]byte, error) {
+	if b.Version == "" {
+		b.Version = BaselineVersion
+	}
+	var buf bytes.Buffer
+	buf.WriteString("# Accepted findings, not reported by VerustCode.\n")
+	buf.WriteString("# Remove an entry (or let it expire) to report the finding again.\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(b); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// FindingFingerprint returns a stable identifier of a finding of a rule.
+// It is derived from the rule, the file, the category and the title of the finding,
+// but not from line numbers, so that it survives unrelated edits of the file.
+func FindingFingerprint(ruleID string, finding Finding) string {
+	parts := []string{
+		ruleID,
+		dsl.ParseLocation(stringField(finding, "location")).Path,
+		strings.ToLower(strings.TrimSpace(stringField(finding, "category"))),
+		strings.ToLower(strings.Join(strings.Fields(stringField(finding, "title")), " ")),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])[:16]
+}
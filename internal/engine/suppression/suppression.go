@@ -0,0 +1,236 @@
+// Package suppression filters out review findings that a repository marks as intentional,
+// with `verust:ignore` source comments or with fingerprints in the baseline file.
+package suppression
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// Suppression sources
+const (
+	SourceDirective = "directive" // verust:ignore source comment
+	SourceBaseline  = "baseline"  // .verustcode/baseline.yaml entry
+)
+
+// DirectiveKeyword starts a suppression directive in a source comment:
+//
+//	// verust:ignore <rule-id|category>[,<rule-id|category>...] <reason>
+//
+// A directive on a line with code covers that line; a directive on its own line
+// covers the next line with code.
+const DirectiveKeyword = "verust:ignore"
+
+// directivePattern matches a directive and captures its targets and reason
+var directivePattern = regexp.MustCompile(`verust:ignore\s+([A-Za-z0-9_,-]+)(?:\s+(.*))?`)
+
+// commentOnlyPattern matches lines that only hold a comment
+var commentOnlyPattern = regexp.MustCompile(`^\s*(//|#|--|/\*|\*|<!--|;|%)`)
+
+// Finding is a review finding as returned by the agent (an item of the findings array)
+type Finding = map[string]interface{}
+
+// Suppressed is a finding removed before publishing
+type Suppressed struct {
+	Fingerprint string `json:"fingerprint"`
+	Title       string `json:"title,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+	Location    string `json:"location,omitempty"`
+	Source      string `json:"source"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// SuppressedData returns suppressed findings in the format of result data
+// (a list of maps, like the findings list)
+func SuppressedData(suppressed []Suppressed) []interface{} {
+	data := make([]interface{}, 0, len(suppressed))
+	for _, s := range suppressed {
+		item := map[string]interface{}{
+			"fingerprint": s.Fingerprint,
+			"source":      s.Source,
+		}
+		for key, value := range map[string]string{
+			"title":    s.Title,
+			"severity": s.Severity,
+			"location": s.Location,
+			"reason":   s.Reason,
+		} {
+			if value != "" {
+				item[key] = value
+			}
+		}
+		data = append(data, item)
+	}
+	return data
+}
+
+// Directive is a verust:ignore comment
+type Directive struct {
+	// Targets are the rule IDs and finding categories the directive applies to.
+	// There is no wildcard: each suppressed rule or category is named explicitly.
+	Targets []string
+
+	// Reason explains why the finding is intentional
+	Reason string
+
+	// Line is the line covered by the directive (1-based)
+	Line int
+}
+
+// Matches returns true if the directive applies to findings of the rule with the given category
+func (d *Directive) Matches(ruleID, category string) bool {
+	for _, target := range d.Targets {
+		if target == ruleID || (category != "" && strings.EqualFold(target, category)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseDirectives returns the directives of a source file
+func ParseDirectives(content string) []Directive {
+	var directives []Directive
+	var pending []Directive // directives on comment-only lines, waiting for the next line with code
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		commentOnly := commentOnlyPattern.MatchString(text)
+
+		if !commentOnly && strings.TrimSpace(text) != "" {
+			for _, d := range pending {
+				d.Line = line
+				directives = append(directives, d)
+			}
+			pending = pending[:0]
+		}
+
+		match := directivePattern.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+		d := Directive{
+			Targets: strings.Split(strings.Trim(match[1], ","), ","),
+			Reason:  cleanReason(match[2]),
+			Line:    line,
+		}
+		if commentOnly {
+			pending = append(pending, d)
+		} else {
+			directives = append(directives, d)
+		}
+	}
+	return directives
+}
+
+// cleanReason strips comment terminators from a directive reason
+func cleanReason(reason string) string {
+	reason = strings.TrimSpace(reason)
+	for _, suffix := range []string{"*/", "-->"} {
+		reason = strings.TrimSpace(strings.TrimSuffix(reason, suffix))
+	}
+	return reason
+}
+
+// Filter removes suppressed findings of a rule.
+// Directives are read from the files of the finding locations in repoPath;
+// baseline may be nil. Returns the kept and the suppressed findings.
+func Filter(findings []interface{}, ruleID, repoPath string, baseline *Baseline) ([]interface{}, []Suppressed) {
+	directives := make(map[string][]Directive) // by file path, loaded on demand
+	fileDirectives := func(file string) []Directive {
+		if d, ok := directives[file]; ok {
+			return d
+		}
+		directives[file] = loadDirectives(repoPath, file)
+		return directives[file]
+	}
+
+	kept := make([]interface{}, 0, len(findings))
+	var suppressed []Suppressed
+	for _, item := range findings {
+		finding, ok := item.(Finding)
+		if !ok {
+			kept = append(kept, item)
+			continue
+		}
+
+		fingerprint := FindingFingerprint(ruleID, finding)
+		entry := Suppressed{
+			Fingerprint: fingerprint,
+			Title:       stringField(finding, "title"),
+			Severity:    stringField(finding, "severity"),
+			Location:    stringField(finding, "location"),
+		}
+
+		if d := matchDirective(finding, ruleID, fileDirectives); d != nil {
+			entry.Source = SourceDirective
+			entry.Reason = d.Reason
+			suppressed = append(suppressed, entry)
+			continue
+		}
+		if e := baseline.Match(fingerprint); e != nil {
+			entry.Source = SourceBaseline
+			entry.Reason = e.Reason
+			suppressed = append(suppressed, entry)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept, suppressed
+}
+
+// matchDirective returns the directive covering a finding, or nil
+func matchDirective(finding Finding, ruleID string, fileDirectives func(string) []Directive) *Directive {
+	loc := dsl.ParseLocation(stringField(finding, "location"))
+	if loc.Path == "" || loc.StartLine == 0 {
+		return nil
+	}
+	category := stringField(finding, "category")
+	directives := fileDirectives(loc.Path)
+	for i := range directives {
+		d := &directives[i]
+		if d.Line >= loc.StartLine && d.Line <= loc.EndLine && d.Matches(ruleID, category) {
+			return d
+		}
+	}
+	return nil
+}
+
+// loadDirectives reads the directives of a repository file.
+// Files outside the repository or that can't be read have no directives.
+func loadDirectives(repoPath, file string) []Directive {
+	file = path.Clean(file)
+	if repoPath == "" || file == ".." || strings.HasPrefix(file, "../") {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(repoPath, filepath.FromSlash(file)))
+	if err != nil {
+		logger.Debug("Can't read file for suppression directives",
+			zap.String("path", file),
+			zap.Error(err),
+		)
+		return nil
+	}
+	if !strings.Contains(string(data), DirectiveKeyword) {
+		return nil
+	}
+	return ParseDirectives(string(data))
+}
+
+// stringField returns a string field of a finding
+func stringField(finding Finding, key string) string {
+	s, _ := finding[key].(string)
+	return s
+}
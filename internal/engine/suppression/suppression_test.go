@@ -0,0 +1,168 @@
+package suppression
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDirectives(t *testing.T) {
+	content := strings.Join([]string{
+		"package main",
+		"",
+		"// verust:ignore security-check intentional for tests",
+		"",
+		"func insecure() {}",
+		"x := run() // verust:ignore performance,Style hot path */",
+		"y := run() # verust:ignore * everything",
+	}, "\n")
+
+	directives := ParseDirectives(content)
+	if len(directives) != 2 {
+		t.Fatalf("got %d directives, want 2: %+v", len(directives), directives)
+	}
+
+	if directives[0].Line != 5 {
+		t.Errorf("comment-only directive covers line %d, want 5", directives[0].Line)
+	}
+	if directives[0].Reason != "intentional for tests" {
+		t.Errorf("reason = %q", directives[0].Reason)
+	}
+	if directives[1].Line != 6 {
+		t.Errorf("trailing directive covers line %d, want 6", directives[1].Line)
+	}
+	if directives[1].Reason != "hot path" {
+		t.Errorf("reason = %q, want comment terminator stripped", directives[1].Reason)
+	}
+	if !directives[1].Matches("other-rule", "style") {
+		t.Error("directive should match the category case-insensitively")
+	}
+	if directives[1].Matches("other-rule", "security") {
+		t.Error("directive should not match other categories")
+	}
+	// A wildcard is not a valid target
+	for _, d := range directives {
+		if d.Line == 7 {
+			t.Errorf("wildcard directive should be ignored: %+v", d)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	repo := t.TempDir()
+	source := "package main\n\n// verust:ignore sec-review test fixture\nconst key = \"secret\"\n\nfunc other() {}\n"
+	if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	directiveFinding := map[string]interface{}{"title": "Hardcoded key", "location": "main.go:4-4", "severity": "high"}
+	otherFinding := map[string]interface{}{"title": "Unused function", "location": "main.go:6-6"}
+	baselineFinding := map[string]interface{}{"title": "Old issue", "location": "legacy.go:1-5", "category": "style"}
+	outside := map[string]interface{}{"title": "Escape", "location": "../etc/passwd:1"}
+
+	baseline, err := ParseBaseline([]byte(`findings:
+  - fingerprint: `+FindingFingerprint("sec-review", baselineFinding)+`
+    reason: accepted
+`), time.Now())
+	if err != nil {
+		t.Fatalf("ParseBaseline() error = %v", err)
+	}
+
+	findings := []interface{}{directiveFinding, otherFinding, baselineFinding, outside}
+	kept, suppressed := Filter(findings, "sec-review", repo, baseline)
+
+	if len(kept) != 2 {
+		t.Fatalf("kept %d findings, want 2", len(kept))
+	}
+	if len(suppressed) != 2 {
+		t.Fatalf("suppressed %d findings, want 2", len(suppressed))
+	}
+	if suppressed[0].Source != SourceDirective || suppressed[0].Reason != "test fixture" {
+		t.Errorf("suppressed[0] = %+v, want directive with reason", suppressed[0])
+	}
+	if suppressed[1].Source != SourceBaseline || suppressed[1].Reason != "accepted" {
+		t.Errorf("suppressed[1] = %+v, want baseline with reason", suppressed[1])
+	}
+
+	// Directives of other rules don't apply
+	kept, _ = Filter([]interface{}{directiveFinding}, "other-rule", repo, nil)
+	if len(kept) != 1 {
+		t.Error("directive should only apply to the named rule")
+	}
+}
+
+func TestBaseline_Expiry(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	baseline, err := ParseBaseline([]byte(`findings:
+  - fingerprint: aaa
+    expires: "2026-03-10"
+  - fingerprint: bbb
+    expires: "2026-03-09"
+  - fingerprint: ccc
+`), now)
+	if err != nil {
+		t.Fatalf("ParseBaseline() error = %v", err)
+	}
+
+	if baseline.Match("aaa") == nil {
+		t.Error("entry should be valid through its expiry day")
+	}
+	if baseline.Match("bbb") != nil {
+		t.Error("expired entry should not match")
+	}
+	if baseline.Match("ccc") == nil {
+		t.Error("entry without expiry should match")
+	}
+
+	var nilBaseline *Baseline
+	if nilBaseline.Match("aaa") != nil {
+		t.Error("nil baseline should match nothing")
+	}
+
+	if _, err := ParseBaseline([]byte("findings:\n  - reason: missing\n"), now); err == nil {
+		t.Error("expected an error for an entry without fingerprint")
+	}
+	if _, err := ParseBaseline([]byte("findings:\n  - fingerprint: x\n    expires: soon\n"), now); err == nil {
+		t.Error("expected an error for an invalid expiry date")
+	}
+}
+
+func TestFindingFingerprint(t *testing.T) {
+	a := map[string]interface{}{"title": "SQL  injection", "location": "db/query.go:10-12", "category": "Security"}
+	moved := map[string]interface{}{"title": "sql injection", "location": "db/query.go:40-42", "category": "security"}
+	other := map[string]interface{}{"title": "SQL injection", "location": "db/other.go:10-12", "category": "security"}
+
+	if FindingFingerprint("r", a) != FindingFingerprint("r", moved) {
+		t.Error("fingerprint should not depend on line numbers, case or spacing")
+	}
+	if FindingFingerprint("r", a) == FindingFingerprint("r", other) {
+		t.Error("fingerprint should depend on the file")
+	}
+	if FindingFingerprint("r", a) == FindingFingerprint("s", a) {
+		t.Error("fingerprint should depend on the rule")
+	}
+}
+
+func TestBaseline_MarshalRoundTrip(t *testing.T) {
+	baseline := &Baseline{}
+	finding := map[string]interface{}{"title": "Issue", "location": "a.go:1"}
+	baseline.Add("rule", finding, "legacy", "2030-01-01")
+	baseline.Add("rule", finding, "duplicate", "")
+
+	data, err := baseline.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	parsed, err := ParseBaseline(data, time.Now())
+	if err != nil {
+		t.Fatalf("ParseBaseline() error = %v", err)
+	}
+	if len(parsed.Findings) != 1 || parsed.Version != BaselineVersion {
+		t.Fatalf("parsed = %+v, want one entry with version", parsed)
+	}
+	if entry := parsed.Match(FindingFingerprint("rule", finding)); entry == nil || entry.Reason != "legacy" {
+		t.Errorf("Match() = %+v, want the first entry", entry)
+	}
+}
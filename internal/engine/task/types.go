@@ -7,6 +7,7 @@ import (
 
 	"github.com/verustcode/verustcode/internal/agent/base"
 	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/llm"
 	"github.com/verustcode/verustcode/internal/model"
 )
 
@@ -32,11 +33,72 @@ type Task struct {
 	// BaseCommitSHA is the base commit SHA for PR diff range (from webhook)
 	BaseCommitSHA string
 
+	// PushCommits lists the pushed commits of a push review (from webhook)
+	PushCommits string
+
 	// ReviewRulesConfig is the DSL configuration for this review
 	ReviewRulesConfig *dsl.ReviewRulesConfig
 
 	// OutputDir is the output directory for file channels
 	OutputDir string
+
+	// Priority is the dispatch priority of this task.
+	// Zero means "derive from Review.Source" (see EffectivePriority).
+	Priority Priority
+}
+
+// Priority represents the dispatch priority of a task.
+// Higher values are dispatched first; tasks with equal priority keep FIFO order.
+type Priority int
+
+const (
+	// PriorityScheduled is used for scheduled audits, which can always wait
+	PriorityScheduled Priority = 10
+
+	// PriorityWebhook is used for webhook-triggered reviews
+	PriorityWebhook Priority = 20
+
+	// PriorityManual is used for manual reruns, API requests and ChatOps commands
+	PriorityManual Priority = 30
+)
+
+// String returns the lane name of the priority
+func (p Priority) String() string {
+	switch {
+	case p >= PriorityManual:
+		return "manual"
+	case p >= PriorityWebhook:
+		return "webhook"
+	case p > 0:
+		return "scheduled"
+	default:
+		return "unset"
+	}
+}
+
+// PriorityForSource returns the default priority for a review source.
+// Unknown sources (including "cli" and "webhook") use PriorityWebhook.
+func PriorityForSource(source string) Priority {
+	switch source {
+	case "api", "retry", "chatops", "manual":
+		return PriorityManual
+	case "schedule":
+		return PriorityScheduled
+	default:
+		return PriorityWebhook
+	}
+}
+
+// EffectivePriority returns the priority used for dispatching this task.
+// An explicit Priority wins; otherwise the priority is derived from Review.Source.
+func (t *Task) EffectivePriority() Priority {
+	if t.Priority > 0 {
+		return t.Priority
+	}
+	if t.Review == nil {
+		return PriorityWebhook
+	}
+	return PriorityForSource(t.Review.Source)
 }
 
 // ReviewRequest represents a request to run a DSL-driven review.
@@ -67,9 +129,33 @@ type ReviewRequest struct {
 	// PRDescription is the PR/MR description
 	PRDescription string
 
+	// PushCommits lists the pushed commits of a push review, one "short-sha subject (author)" line each
+	PushCommits string
+
+	// Source is the review trigger source ("webhook", "api", "schedule", ...)
+	Source string
+
+	// TargetBranch is the PR/MR base branch, or the reviewed branch for non-PR reviews
+	TargetBranch string
+
+	// Author is the PR/MR author or commit author
+	Author string
+
+	// Labels lists the PR/MR labels
+	Labels []string
+
+	// LinesChanged is the number of changed lines (added + deleted)
+	LinesChanged int
+
+	// FilesChanged is the number of changed files
+	FilesChanged int
+
 	// ChangedFiles lists changed files (for diff-based context)
 	ChangedFiles []string
 
+	// Commits lists the commit SHAs in BaseCommitSHA..CommitSHA, oldest first
+	Commits []string
+
 	// ReviewRulesConfig is the DSL configuration
 	ReviewRulesConfig *dsl.ReviewRulesConfig
 
@@ -91,6 +177,9 @@ type PRInfo struct {
 
 	// ChangedFiles lists the files changed in this PR
 	ChangedFiles []string
+
+	// PushCommits lists the pushed commits of a push event, one "short-sha subject (author)" line each
+	PushCommits string
 }
 
 // RunResult represents a single review run result.
@@ -111,6 +200,12 @@ type RunResult struct {
 	// Duration is how long the run took
 	Duration time.Duration
 
+	// Usage is the token usage and cost of the run (nil if not reported)
+	Usage *llm.Usage
+
+	// Validation is the outcome of validating the run's structured output (nil if not validated)
+	Validation *model.OutputValidation
+
 	// Err is any error that occurred during the run
 	Err error
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 
 	"go.uber.org/zap"
@@ -146,6 +147,90 @@ func GetCommitsInRange(ctx context.Context, repoPath, baseCommit, headCommit str
 	return commits
 }
 
+// CommitMessage is the full message of a commit
+type CommitMessage struct {
+	SHA     string
+	Message string
+}
+
+// GetCommitMessages returns the messages of the commits between base and head (exclusive base,
+// inclusive head), oldest first. Returns nil if base or head is empty, or if the command fails.
+func GetCommitMessages(ctx context.Context, repoPath, baseCommit, headCommit string) []CommitMessage {
+	if baseCommit == "" || headCommit == "" {
+		return nil
+	}
+
+	// Records are separated by RS (0x1e), the SHA from the message by NUL
+	commitRange := fmt.Sprintf("%s..%s", baseCommit, headCommit)
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "log", "--reverse", "--format=%H%x00%B%x1e", commitRange)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Warn("Failed to get commit messages in range",
+			zap.String("repo_path", repoPath),
+			zap.String("range", commitRange),
+			zap.String("stderr", stderr.String()),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	var messages []CommitMessage
+	for _, record := range strings.Split(stdout.String(), "\x1e") {
+		sha, message, ok := strings.Cut(strings.TrimLeft(record, "\n"), "\x00")
+		if !ok {
+			continue
+		}
+		messages = append(messages, CommitMessage{SHA: sha, Message: strings.TrimSpace(message)})
+	}
+	return messages
+}
+
+// GetHeadCommit returns the SHA of the commit checked out in the repository (HEAD).
+// Returns empty string if the command fails.
+func GetHeadCommit(ctx context.Context, repoPath string) string {
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "rev-parse", "HEAD")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Warn("Failed to get head commit",
+			zap.String("repo_path", repoPath),
+			zap.String("stderr", stderr.String()),
+			zap.Error(err),
+		)
+		return ""
+	}
+
+	return strings.TrimSpace(stdout.String())
+}
+
+// commitSHAPattern matches hex object IDs, full or abbreviated
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-fA-F]{4,64}$`)
+
+// CheckoutCommit checks out a specific commit (detached HEAD) in the repository.
+// Used when the branch has moved on since the commit to review was pushed.
+// commitSHA must be a hex object ID (e.g. from a webhook payload), so it can't be read as an option.
+func CheckoutCommit(ctx context.Context, repoPath, commitSHA string) error {
+	if !commitSHAPattern.MatchString(commitSHA) {
+		return fmt.Errorf("invalid commit SHA %q", commitSHA)
+	}
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "checkout", "--detach", commitSHA)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git checkout %s failed: %w: %s", commitSHA, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
 // CleanupWorkspace removes the task workspace directory.
 func CleanupWorkspace(path string) {
 	if err := os.RemoveAll(path); err != nil {
@@ -295,3 +380,69 @@ func GetDiffStats(ctx context.Context, repoPath, baseCommit, headCommit string)
 
 	return stats
 }
+
+// FileDiffStat holds the number of changed lines of a single file
+type FileDiffStat struct {
+	Path         string
+	LinesChanged int // added + deleted lines (0 for binary files)
+}
+
+// GetFileDiffStats returns per-file diff statistics between base and head, in git's path order.
+// Renames are reported as delete + add so every path exists in either base or head.
+// Returns nil if base or head is empty, or if the command fails.
+func GetFileDiffStats(ctx context.Context, repoPath, baseCommit, headCommit string) []FileDiffStat {
+	if baseCommit == "" || headCommit == "" {
+		return nil
+	}
+
+	commitRange := fmt.Sprintf("%s..%s", baseCommit, headCommit)
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "diff", "--numstat", "--no-renames", commitRange)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Warn("Failed to get file diff stats",
+			zap.String("repo_path", repoPath),
+			zap.String("range", commitRange),
+			zap.String("stderr", stderr.String()),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	var stats []FileDiffStat
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		// Format: added<TAB>deleted<TAB>filename ("-" for binary files)
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+
+		stat := FileDiffStat{Path: parts[2]}
+		for _, n := range parts[:2] {
+			var count int
+			if _, err := fmt.Sscanf(n, "%d", &count); err == nil {
+				stat.LinesChanged += count
+			}
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats
+}
+
+// GetChangedFiles returns the paths of the files changed between base and head, in git's path order.
+// Returns nil if base or head is empty, or if the command fails.
+func GetChangedFiles(ctx context.Context, repoPath, baseCommit, headCommit string) []string {
+	stats := GetFileDiffStats(ctx, repoPath, baseCommit, headCommit)
+	if stats == nil {
+		return nil
+	}
+	files := make([]string, 0, len(stats))
+	for _, s := range stats {
+		files = append(files, s.Path)
+	}
+	return files
+}
@@ -346,6 +346,20 @@ func TestGetCommitsInRange_MultipleCommits(t *testing.T) {
 	assert.Len(t, commits, 3) // Should have 3 commits between base and head
 }
 
+// TestGetCommitMessages_WithRealRepo tests GetCommitMessages with a real git repository
+func TestGetCommitMessages_WithRealRepo(t *testing.T) {
+	repoPath, baseCommit, headCommit := setupTestRepo(t)
+	ctx := context.Background()
+
+	messages := GetCommitMessages(ctx, repoPath, baseCommit, headCommit)
+	require.Len(t, messages, 1)
+	assert.Equal(t, headCommit, messages[0].SHA)
+	assert.Equal(t, "Add file2", messages[0].Message)
+
+	assert.Nil(t, GetCommitMessages(ctx, repoPath, "", headCommit))
+	assert.Nil(t, GetCommitMessages(ctx, "/nonexistent/path", baseCommit, headCommit))
+}
+
 // TestGetCommitsInRange_InvalidPath tests GetCommitsInRange with invalid repository path
 func TestGetCommitsInRange_InvalidPath(t *testing.T) {
 	ctx := context.Background()
@@ -563,3 +577,41 @@ func TestGetDiffStats_ContextCancellation(t *testing.T) {
 	stats := GetDiffStats(ctx, repoPath, baseCommit, headCommit)
 	assert.Nil(t, stats)
 }
+
+// TestGetHeadCommit tests GetHeadCommit with a real git repository
+func TestGetHeadCommit(t *testing.T) {
+	repoPath, _, headCommit := setupTestRepo(t)
+	ctx := context.Background()
+
+	assert.Equal(t, headCommit, GetHeadCommit(ctx, repoPath))
+	assert.Empty(t, GetHeadCommit(ctx, t.TempDir()))
+}
+
+// TestGetFileDiffStats_WithRealRepo tests GetFileDiffStats with a real git repository
+func TestGetFileDiffStats_WithRealRepo(t *testing.T) {
+	repoPath, baseCommit, headCommit := setupTestRepo(t)
+	ctx := context.Background()
+
+	stats := GetFileDiffStats(ctx, repoPath, baseCommit, headCommit)
+	require.Len(t, stats, 1)
+	assert.Equal(t, "file2.txt", stats[0].Path)
+	assert.Equal(t, 1, stats[0].LinesChanged)
+
+	assert.Nil(t, GetFileDiffStats(ctx, repoPath, "", headCommit))
+	assert.Nil(t, GetFileDiffStats(ctx, repoPath, "invalid", headCommit))
+}
+
+// TestCheckoutCommit tests CheckoutCommit with a real git repository
+func TestCheckoutCommit(t *testing.T) {
+	repoPath, baseCommit, _ := setupTestRepo(t)
+	ctx := context.Background()
+
+	require.NoError(t, CheckoutCommit(ctx, repoPath, baseCommit))
+	assert.Equal(t, baseCommit, GetHeadCommit(ctx, repoPath))
+
+	// Option-like or symbolic revisions are rejected before git runs
+	for _, sha := range []string{"--orphan=x", "-b", "HEAD~1", ""} {
+		assert.Error(t, CheckoutCommit(ctx, repoPath, sha), sha)
+	}
+	assert.Equal(t, baseCommit, GetHeadCommit(ctx, repoPath))
+}
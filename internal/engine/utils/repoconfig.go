@@ -0,0 +1,95 @@
+// Package utils provides utility functions for the engine.
+// This file applies the trust policy for in-repository review configs (.verust-review.yaml).
+package utils
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/pkg/logger"
+)
+
+// EffectiveReviewConfig is the review configuration selected for a review
+type EffectiveReviewConfig struct {
+	Config *dsl.ReviewRulesConfig
+
+	// Source is where the configuration comes from (model.ConfigSource*)
+	Source string
+
+	// Rejections describes the in-repository overrides rejected by the trust policy
+	Rejections []string
+}
+
+// InRepoConfigPolicyOf returns the in-repository config policy of a repository config.
+// Repositories without a config or a valid policy use the default policy.
+func InRepoConfigPolicyOf(repoConfig *model.RepositoryReviewConfig) model.InRepoConfigPolicy {
+	if repoConfig == nil || !repoConfig.InRepoConfigPolicy.IsValid() {
+		return model.DefaultInRepoConfigPolicy
+	}
+	return repoConfig.InRepoConfigPolicy
+}
+
+// ApplyInRepoConfigPolicy selects the review configuration of a repository checked out at repoPath.
+// server is the server-side configuration (repository review file or default.yaml);
+// baseCommit is the base of the reviewed change, used by the base_branch policy.
+// An in-repository config that can't be loaded is ignored, so the server configuration applies.
+func ApplyInRepoConfigPolicy(loader *dsl.Loader, policy model.InRepoConfigPolicy, server *dsl.ReviewRulesConfig, repoPath, baseCommit string) *EffectiveReviewConfig {
+	effective := &EffectiveReviewConfig{Config: server, Source: model.ConfigSourceServer}
+	if repoPath == "" {
+		return effective
+	}
+
+	switch policy {
+	case model.InRepoConfigIgnore:
+		logger.Debug("Ignoring in-repository review config by policy",
+			zap.String("repo_path", repoPath),
+		)
+
+	case model.InRepoConfigBaseBranch:
+		if baseCommit == "" {
+			logger.Info("No base commit to read the in-repository review config from, using server config",
+				zap.String("repo_path", repoPath),
+			)
+			break
+		}
+		baseConfig, err := loader.LoadFromRepoRevision(repoPath, baseCommit)
+		if err != nil {
+			logger.Warn("Failed to load review config from base commit",
+				zap.String("repo_path", repoPath),
+				zap.String("base_commit", baseCommit),
+				zap.Error(err),
+			)
+			effective.Rejections = []string{config.RepoRootReviewPath + " of the base commit: " + err.Error()}
+		} else if baseConfig != nil {
+			logger.Info("Using review config from base commit ("+config.RepoRootReviewPath+")",
+				zap.String("repo_path", repoPath),
+				zap.String("base_commit", baseCommit),
+				zap.Int("rules", len(baseConfig.Rules)),
+			)
+			effective.Config = baseConfig
+			effective.Source = model.ConfigSourceBaseBranch
+		}
+
+	case model.InRepoConfigMerge:
+		repoConfig, err := loader.LoadFromRepoRoot(repoPath)
+		if err != nil {
+			logger.Warn("Failed to load review config from repository root",
+				zap.String("repo_path", repoPath),
+				zap.Error(err),
+			)
+			effective.Rejections = []string{config.RepoRootReviewPath + ": " + err.Error()}
+		} else if repoConfig != nil {
+			effective.Config, effective.Rejections = dsl.MergeUnderBaseline(server, repoConfig)
+			effective.Source = model.ConfigSourceMerged
+			logger.Info("Merged review config from repository root ("+config.RepoRootReviewPath+") under server config",
+				zap.String("repo_path", repoPath),
+				zap.Int("rules", len(effective.Config.Rules)),
+				zap.Strings("rejected", effective.Rejections),
+			)
+		}
+	}
+
+	return effective
+}
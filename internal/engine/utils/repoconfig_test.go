@@ -0,0 +1,103 @@
+// Package utils provides utility functions for the engine.
+// This file contains unit tests for the in-repository review config trust policy.
+package utils
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+// setupConfigRepo creates a git repository whose base commit and working tree
+// have different .verust-review.yaml files. Returns the repository path and the base commit.
+func setupConfigRepo(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(ruleID string) {
+		t.Helper()
+		content := "version: \"1.0\"\nrules:\n  - id: " + ruleID + "\n    goals:\n      areas: [security]\n"
+		if err := os.WriteFile(filepath.Join(dir, ".verust-review.yaml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("base-rule")
+	git("add", ".")
+	git("commit", "-q", "-m", "base")
+	base := git("rev-parse", "HEAD")
+	write("head-rule")
+	git("commit", "-q", "-am", "head")
+	return dir, base
+}
+
+// TestApplyInRepoConfigPolicy tests the review config selected by each policy
+func TestApplyInRepoConfigPolicy(t *testing.T) {
+	repoPath, base := setupConfigRepo(t)
+	server := &dsl.ReviewRulesConfig{
+		Rules: []dsl.ReviewRuleConfig{{ID: "server-rule", Locked: true, Goals: dsl.GoalsConfig{Areas: []string{"security"}}}},
+	}
+	loader := dsl.NewLoader()
+
+	tests := []struct {
+		name       string
+		policy     model.InRepoConfigPolicy
+		baseCommit string
+		wantSource string
+		wantRules  []string
+	}{
+		{"ignore", model.InRepoConfigIgnore, base, model.ConfigSourceServer, []string{"server-rule"}},
+		{"base branch", model.InRepoConfigBaseBranch, base, model.ConfigSourceBaseBranch, []string{"base-rule"}},
+		{"base branch without base commit", model.InRepoConfigBaseBranch, "", model.ConfigSourceServer, []string{"server-rule"}},
+		{"merge", model.InRepoConfigMerge, base, model.ConfigSourceMerged, []string{"server-rule", "head-rule"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			effective := ApplyInRepoConfigPolicy(loader, tt.policy, server, repoPath, tt.baseCommit)
+			if effective.Source != tt.wantSource {
+				t.Errorf("Source = %q, want %q", effective.Source, tt.wantSource)
+			}
+			if got := effective.Config.GetRuleIDs(); !reflect.DeepEqual(got, tt.wantRules) {
+				t.Errorf("Rules = %v, want %v", got, tt.wantRules)
+			}
+			if len(effective.Rejections) != 0 {
+				t.Errorf("Rejections = %v, want none", effective.Rejections)
+			}
+		})
+	}
+
+	t.Run("unknown base commit", func(t *testing.T) {
+		effective := ApplyInRepoConfigPolicy(loader, model.InRepoConfigBaseBranch, server, repoPath, "0000000000000000000000000000000000000000")
+		if effective.Source != model.ConfigSourceServer || len(effective.Rejections) != 1 {
+			t.Errorf("Expected server config with a rejection, got %q, %v", effective.Source, effective.Rejections)
+		}
+	})
+}
+
+// TestInRepoConfigPolicyOf tests the policy of repository configs
+func TestInRepoConfigPolicyOf(t *testing.T) {
+	if got := InRepoConfigPolicyOf(nil); got != model.DefaultInRepoConfigPolicy {
+		t.Errorf("InRepoConfigPolicyOf(nil) = %q, want default", got)
+	}
+	if got := InRepoConfigPolicyOf(&model.RepositoryReviewConfig{InRepoConfigPolicy: "bogus"}); got != model.DefaultInRepoConfigPolicy {
+		t.Errorf("InRepoConfigPolicyOf(invalid) = %q, want default", got)
+	}
+	if got := InRepoConfigPolicyOf(&model.RepositoryReviewConfig{InRepoConfigPolicy: model.InRepoConfigMerge}); got != model.InRepoConfigMerge {
+		t.Errorf("InRepoConfigPolicyOf(merge) = %q, want merge", got)
+	}
+}
@@ -0,0 +1,69 @@
+// Package utils provides utility functions for the engine.
+// This file contains token usage accounting and monthly repository budgets.
+package utils
+
+import (
+	"time"
+
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/store"
+)
+
+// MonthStart returns the start of the calendar month of t, which is the budget period
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// LoadUsage returns the token usage of review rules and report sections created since start.
+// An empty repoURL loads the usage of all repositories.
+func LoadUsage(s store.Store, repoURL string, start time.Time) ([]model.UsageRecord, error) {
+	records, err := s.Review().ListUsageByRepoSince(repoURL, start)
+	if err != nil {
+		return nil, err
+	}
+	reportRecords, err := s.Report().ListUsageByRepoSince(repoURL, start)
+	if err != nil {
+		return nil, err
+	}
+	return append(records, reportRecords...), nil
+}
+
+// SpentSince returns the cost of review rules and report sections created since start,
+// summed by the database
+func SpentSince(s store.Store, repoURL string, start time.Time) (float64, error) {
+	spent, err := s.Review().SumCostByRepoSince(repoURL, start)
+	if err != nil {
+		return 0, err
+	}
+	reportSpent, err := s.Report().SumCostByRepoSince(repoURL, start)
+	if err != nil {
+		return 0, err
+	}
+	return spent + reportSpent, nil
+}
+
+// SumUsage returns the total usage of records
+func SumUsage(records []model.UsageRecord) model.TokenUsage {
+	var total model.TokenUsage
+	for _, r := range records {
+		total.Add(r.TokenUsage)
+	}
+	return total
+}
+
+// BudgetExceeded returns true if the repository has a monthly budget and spent reached it
+func BudgetExceeded(repoConfig *model.RepositoryReviewConfig, spent float64) bool {
+	return repoConfig != nil && repoConfig.MonthlyBudget > 0 && spent >= repoConfig.MonthlyBudget
+}
+
+// BudgetActionOf returns the budget action of a repository config.
+// Downgrading requires a budget model; without one, or without a valid action, rules are blocked.
+func BudgetActionOf(repoConfig *model.RepositoryReviewConfig) model.BudgetAction {
+	if repoConfig == nil || !repoConfig.BudgetAction.IsValid() {
+		return model.DefaultBudgetAction
+	}
+	if repoConfig.BudgetAction == model.BudgetActionDowngrade && repoConfig.BudgetModel == "" {
+		return model.BudgetActionBlock
+	}
+	return repoConfig.BudgetAction
+}
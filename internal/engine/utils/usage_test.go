@@ -0,0 +1,69 @@
+// Package utils provides utility functions for the engine.
+// This file contains unit tests for token usage accounting and repository budgets.
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+// TestMonthStart tests the start of the budget period
+func TestMonthStart(t *testing.T) {
+	now := time.Date(2026, 3, 17, 15, 4, 5, 0, time.UTC)
+	want := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	if got := MonthStart(now); !got.Equal(want) {
+		t.Errorf("MonthStart() = %v, want %v", got, want)
+	}
+}
+
+// TestSumUsage tests summing usage records
+func TestSumUsage(t *testing.T) {
+	records := []model.UsageRecord{
+		{TokenUsage: model.TokenUsage{PromptTokens: 100, CompletionTokens: 10, CachedTokens: 50, Cost: 0.25}},
+		{TokenUsage: model.TokenUsage{PromptTokens: 20, CompletionTokens: 5, Cost: 0.5}},
+	}
+	got := SumUsage(records)
+	want := model.TokenUsage{PromptTokens: 120, CompletionTokens: 15, CachedTokens: 50, Cost: 0.75}
+	if got != want {
+		t.Errorf("SumUsage() = %+v, want %+v", got, want)
+	}
+}
+
+// TestBudget tests budget checks and the effective budget action
+func TestBudget(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       *model.RepositoryReviewConfig
+		spent        float64
+		wantExceeded bool
+		wantAction   model.BudgetAction
+	}{
+		{"no config", nil, 100, false, model.BudgetActionBlock},
+		{"no budget", &model.RepositoryReviewConfig{}, 100, false, model.BudgetActionBlock},
+		{"under budget", &model.RepositoryReviewConfig{MonthlyBudget: 50}, 49.99, false, model.BudgetActionBlock},
+		{"budget reached", &model.RepositoryReviewConfig{MonthlyBudget: 50}, 50, true, model.BudgetActionBlock},
+		{
+			"downgrade",
+			&model.RepositoryReviewConfig{MonthlyBudget: 50, BudgetAction: model.BudgetActionDowngrade, BudgetModel: "gpt-4o-mini"},
+			60, true, model.BudgetActionDowngrade,
+		},
+		{
+			"downgrade without model blocks",
+			&model.RepositoryReviewConfig{MonthlyBudget: 50, BudgetAction: model.BudgetActionDowngrade},
+			60, true, model.BudgetActionBlock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BudgetExceeded(tt.config, tt.spent); got != tt.wantExceeded {
+				t.Errorf("BudgetExceeded() = %v, want %v", got, tt.wantExceeded)
+			}
+			if got := BudgetActionOf(tt.config); got != tt.wantAction {
+				t.Errorf("BudgetActionOf() = %v, want %v", got, tt.wantAction)
+			}
+		})
+	}
+}
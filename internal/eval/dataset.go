@@ -0,0 +1,163 @@
+// Package eval measures the precision and recall of review rules against labeled pull requests.
+// This file loads datasets.
+package eval
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Default PR metadata values
+const (
+	DefaultRepoURL = "https://github.com/example/repo"
+	DefaultRef     = "feature"
+)
+
+// Dataset is a set of labeled pull requests (a YAML file)
+type Dataset struct {
+	// Name identifies the dataset in stored results (default: file name without extension)
+	Name string `yaml:"name"`
+
+	// Cases lists the labeled pull requests
+	Cases []Case `yaml:"cases"`
+}
+
+// Case is a labeled pull request: a commit range of a repository snapshot and the findings
+// a review of it is expected to report
+type Case struct {
+	// ID identifies the case in results
+	ID string `yaml:"id"`
+
+	// Repo is the git repository: a local path (relative to the dataset file) or a clone URL
+	Repo string `yaml:"repo"`
+
+	// Base and Head are the commits of the PR range
+	Base string `yaml:"base"`
+	Head string `yaml:"head"`
+
+	// PR is the pull request metadata passed to the rules
+	PR PRMetadata `yaml:"pr"`
+
+	// Expected lists the findings a review should report
+	Expected []ExpectedFinding `yaml:"expected"`
+}
+
+// PRMetadata describes the pull request of a case
+type PRMetadata struct {
+	Number       int      `yaml:"number"`
+	Title        string   `yaml:"title"`
+	Description  string   `yaml:"description"`
+	RepoURL      string   `yaml:"repo_url"`      // default: https://github.com/example/repo
+	Ref          string   `yaml:"ref"`           // default: feature
+	TargetBranch string   `yaml:"target_branch"` // default: main
+	Author       string   `yaml:"author"`
+	Labels       []string `yaml:"labels"`
+}
+
+// ExpectedFinding is a labeled finding of a case
+type ExpectedFinding struct {
+	// File is the repository-relative path of the finding
+	File string `yaml:"file"`
+
+	// Lines is the line range [start, end] of the finding (empty = anywhere in the file)
+	Lines []int `yaml:"lines"`
+
+	// Category, Title and Description are compared with the predicted findings (optional)
+	Category    string `yaml:"category"`
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+}
+
+// LineRange returns the start and end line of the finding (0, 0 if it has no lines)
+func (f *ExpectedFinding) LineRange() (int, int) {
+	switch len(f.Lines) {
+	case 0:
+		return 0, 0
+	case 1:
+		return f.Lines[0], f.Lines[0]
+	}
+	start, end := f.Lines[0], f.Lines[1]
+	if end < start {
+		start, end = end, start
+	}
+	return start, end
+}
+
+// String returns the finding as "file:start-end title"
+func (f *ExpectedFinding) String() string {
+	start, end := f.LineRange()
+	return formatFinding(f.File, start, end, f.Title)
+}
+
+// LoadDataset reads a dataset file. Local repository paths are resolved against the
+// directory of the file.
+func LoadDataset(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+
+	var ds Dataset
+	if err := yaml.Unmarshal(data, &ds); err != nil {
+		return nil, fmt.Errorf("failed to parse dataset %s: %w", path, err)
+	}
+	if ds.Name == "" {
+		ds.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if len(ds.Cases) == 0 {
+		return nil, fmt.Errorf("dataset %s has no cases", path)
+	}
+
+	seen := make(map[string]bool, len(ds.Cases))
+	for i := range ds.Cases {
+		c := &ds.Cases[i]
+		if c.ID == "" {
+			return nil, fmt.Errorf("dataset %s: case %d has no id", path, i+1)
+		}
+		if seen[c.ID] {
+			return nil, fmt.Errorf("dataset %s: duplicate case id %s", path, c.ID)
+		}
+		seen[c.ID] = true
+
+		if c.Repo == "" || c.Base == "" || c.Head == "" {
+			return nil, fmt.Errorf("dataset %s: case %s needs repo, base and head", path, c.ID)
+		}
+		// Values starting with "-" would be read as options by git
+		for _, value := range []string{c.Repo, c.Base, c.Head} {
+			if strings.HasPrefix(value, "-") {
+				return nil, fmt.Errorf("dataset %s: case %s has an invalid repo or revision %q", path, c.ID, value)
+			}
+		}
+		if !isRemoteRepo(c.Repo) && !filepath.IsAbs(c.Repo) {
+			c.Repo = filepath.Join(filepath.Dir(path), c.Repo)
+		}
+		for j, expected := range c.Expected {
+			if expected.File == "" {
+				return nil, fmt.Errorf("dataset %s: expected finding %d of case %s has no file", path, j+1, c.ID)
+			}
+			if len(expected.Lines) > 2 {
+				return nil, fmt.Errorf("dataset %s: expected finding %d of case %s has an invalid line range", path, j+1, c.ID)
+			}
+		}
+
+		if c.PR.RepoURL == "" {
+			c.PR.RepoURL = DefaultRepoURL
+		}
+		if c.PR.Ref == "" {
+			c.PR.Ref = DefaultRef
+		}
+		if c.PR.TargetBranch == "" {
+			c.PR.TargetBranch = "main"
+		}
+	}
+	return &ds, nil
+}
+
+// isRemoteRepo returns true if repo is a clone URL rather than a local path
+func isRemoteRepo(repo string) bool {
+	return strings.Contains(repo, "://") || strings.HasPrefix(repo, "git@")
+}
@@ -0,0 +1,450 @@
+// Package eval measures the precision and recall of review rules against labeled pull requests.
+//
+// A dataset lists cases: a commit range of a git repository, the PR metadata and the findings
+// a review is expected to report (file, line range, category, title). Run reviews every case
+// with the selected rules through the normal runner and executor, once per configuration
+// (agent and model), matches the predicted findings to the expected ones by location overlap
+// and similarity, and reports precision, recall, cost and latency per configuration.
+// Results are stored as model.EvalRun records so configurations can be compared over time.
+package eval
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/engine/executor"
+	"github.com/verustcode/verustcode/internal/engine/runner"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/prompt"
+	"github.com/verustcode/verustcode/internal/store"
+	"github.com/verustcode/verustcode/pkg/idgen"
+)
+
+// DefaultConfigurationName is the name of the configuration running the rules as configured
+const DefaultConfigurationName = "default"
+
+// Configuration is an agent and model the rules are evaluated with
+type Configuration struct {
+	Name string `json:"name"`
+
+	// Agent and Model replace the agent and model of every rule (empty = as configured)
+	Agent string `json:"agent,omitempty"`
+	Model string `json:"model,omitempty"`
+}
+
+// ParseConfiguration parses a configuration given as "agent" or "agent:model";
+// an empty spec runs the rules as configured
+func ParseConfiguration(spec string) Configuration {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return Configuration{Name: DefaultConfigurationName}
+	}
+	agent, modelName, _ := strings.Cut(spec, ":")
+	return Configuration{Name: spec, Agent: agent, Model: modelName}
+}
+
+// Options configures an evaluation
+type Options struct {
+	// Rules is the review file whose rules are evaluated
+	Rules *dsl.ReviewRulesConfig
+
+	// RuleIDs selects the evaluated rules (empty = all rules)
+	RuleIDs []string
+
+	// Configurations lists the evaluated configurations (empty = the rules as configured)
+	Configurations []Configuration
+
+	// Agents holds the agents the rules may use, by name
+	Agents map[string]base.Agent
+
+	// ReviewConfig holds the review settings (model prices, retries, output repair)
+	ReviewConfig *config.ReviewConfig
+
+	// Match configures the matching of predicted findings to expected findings
+	// (zero value = DefaultLineTolerance and DefaultMinSimilarity)
+	Match MatchOptions
+}
+
+// Report is the outcome of an evaluation
+type Report struct {
+	Dataset string                `json:"dataset"`
+	Rules   []string              `json:"rules"`
+	Results []ConfigurationResult `json:"results"`
+}
+
+// ConfigurationResult is the outcome of a configuration over all cases
+type ConfigurationResult struct {
+	Configuration
+
+	// Cases holds the result of each case, in dataset order
+	Cases []CaseResult `json:"cases"`
+
+	// FailedCases counts the cases with a failed rule
+	FailedCases int `json:"failed_cases"`
+
+	TruePositives  int     `json:"true_positives"`
+	FalsePositives int     `json:"false_positives"`
+	FalseNegatives int     `json:"false_negatives"`
+	Precision      float64 `json:"precision"`
+	Recall         float64 `json:"recall"`
+	F1             float64 `json:"f1"`
+
+	// Usage is the token usage and estimated cost of all agent calls
+	Usage model.TokenUsage `json:"usage"`
+
+	// Latency is the mean review duration of a case; Duration is the total (milliseconds)
+	Latency  int64 `json:"latency"`
+	Duration int64 `json:"duration"`
+}
+
+// CaseResult is the outcome of a configuration on a case
+type CaseResult struct {
+	CaseID string `json:"case_id"`
+
+	// Predictions lists the findings reported by the rules
+	Predictions []Prediction `json:"predictions,omitempty"`
+
+	// Match is the matching of the predictions to the expected findings
+	Match *MatchResult `json:"match"`
+
+	TruePositives  int `json:"true_positives"`
+	FalsePositives int `json:"false_positives"`
+	FalseNegatives int `json:"false_negatives"`
+
+	// Missed and Unexpected describe the unmatched expected findings and predictions
+	Missed     []string `json:"missed,omitempty"`
+	Unexpected []string `json:"unexpected,omitempty"`
+
+	Usage    model.TokenUsage `json:"usage"`
+	Duration int64            `json:"duration"` // milliseconds
+
+	// Error describes the failed rules of the case
+	Error string `json:"error,omitempty"`
+}
+
+// Run evaluates the selected rules on every case of the dataset, once per configuration.
+// Nothing is published: rule outputs are dropped. A case whose rules fail still counts,
+// with its expected findings missed.
+func Run(ctx context.Context, ds *Dataset, opts *Options) (*Report, error) {
+	if opts == nil || opts.Rules == nil {
+		return nil, fmt.Errorf("no review rules to evaluate")
+	}
+	matchOpts := opts.Match
+	if matchOpts == (MatchOptions{}) {
+		matchOpts = MatchOptions{LineTolerance: DefaultLineTolerance, MinSimilarity: DefaultMinSimilarity}
+	}
+
+	selected, err := selectRules(opts.Rules, opts.RuleIDs)
+	if err != nil {
+		return nil, err
+	}
+	configurations := opts.Configurations
+	if len(configurations) == 0 {
+		configurations = []Configuration{{Name: DefaultConfigurationName}}
+	}
+	configured := make([]*dsl.ReviewRulesConfig, len(configurations))
+	for i, cfg := range configurations {
+		configured[i] = configureRules(selected, cfg)
+		if err := checkAgents(configured[i], opts.Agents); err != nil {
+			return nil, fmt.Errorf("configuration %s: %w", cfg.Name, err)
+		}
+	}
+
+	workDir, err := os.MkdirTemp("", "verustcode-eval-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	workspaces := make([]*workspace, len(ds.Cases))
+	for i := range ds.Cases {
+		c := &ds.Cases[i]
+		if workspaces[i], err = prepareWorkspace(ctx, c, filepath.Join(workDir, "cases", fmt.Sprint(i))); err != nil {
+			return nil, fmt.Errorf("case %s: failed to prepare repository: %w", c.ID, err)
+		}
+	}
+
+	s, closeStore, err := openStore(filepath.Join(workDir, "eval.db"), opts.ReviewConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer closeStore()
+
+	report := &Report{Dataset: ds.Name}
+	for _, rule := range selected.Rules {
+		report.Rules = append(report.Rules, rule.ID)
+	}
+
+	for i, cfg := range configurations {
+		exec := executor.NewExecutor(&config.Config{}, opts.Agents, prompt.NewBuilder(), s)
+		r := runner.NewRunner(&config.Config{}, s, exec, prompt.NewBuilder())
+
+		result := ConfigurationResult{Configuration: cfg}
+		for j := range ds.Cases {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			caseResult, err := runCase(ctx, r, s, &ds.Cases[j], workspaces[j], configured[i], filepath.Join(workDir, "output"), matchOpts)
+			if err != nil {
+				return nil, fmt.Errorf("case %s: %w", ds.Cases[j].ID, err)
+			}
+			result.add(caseResult)
+		}
+		result.finish()
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// runCase reviews a case and matches the reported findings to the expected ones
+func runCase(ctx context.Context, r *runner.Runner, s store.Store, c *Case, ws *workspace, rules *dsl.ReviewRulesConfig, outputDir string, matchOpts MatchOptions) (*CaseResult, error) {
+	if err := ws.reset(ctx); err != nil {
+		return nil, fmt.Errorf("failed to reset repository: %w", err)
+	}
+
+	now := time.Now()
+	review := &model.Review{
+		ID:            idgen.NewReviewID(),
+		Ref:           c.PR.Ref,
+		CommitSHA:     ws.headCommit,
+		BaseCommitSHA: ws.baseCommit,
+		PRNumber:      c.PR.Number,
+		RepoURL:       c.PR.RepoURL,
+		RepoPath:      ws.repoPath,
+		Source:        "cli",
+		Author:        c.PR.Author,
+		Status:        model.ReviewStatusRunning,
+		StartedAt:     &now,
+		FilesChanged:  len(ws.changedFiles),
+	}
+	if err := s.Review().Create(review); err != nil {
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+
+	req := &runner.ReviewRequest{
+		RepoPath:          ws.repoPath,
+		RepoURL:           c.PR.RepoURL,
+		Ref:               c.PR.Ref,
+		CommitSHA:         ws.headCommit,
+		PRNumber:          c.PR.Number,
+		PRTitle:           c.PR.Title,
+		PRDescription:     c.PR.Description,
+		BaseCommitSHA:     ws.baseCommit,
+		Source:            review.Source,
+		TargetBranch:      c.PR.TargetBranch,
+		Author:            c.PR.Author,
+		Labels:            c.PR.Labels,
+		LinesChanged:      ws.linesChanged,
+		FilesChanged:      len(ws.changedFiles),
+		ChangedFiles:      ws.changedFiles,
+		Commits:           ws.commits,
+		ReviewRulesConfig: rules,
+		OutputDir:         outputDir,
+	}
+
+	// Rule failures are reported through the rule statuses
+	_, _ = r.RunReviewWithTracking(ctx, req, review, nil)
+
+	result := &CaseResult{CaseID: c.ID, Duration: time.Since(now).Milliseconds()}
+	if err := collectPredictions(s, review.ID, result); err != nil {
+		return nil, err
+	}
+
+	result.Match = MatchFindings(c.Expected, result.Predictions, matchOpts)
+	result.TruePositives = len(result.Match.Matches)
+	result.FalsePositives = len(result.Match.Unexpected)
+	result.FalseNegatives = len(result.Match.Missed)
+	for _, i := range result.Match.Missed {
+		result.Missed = append(result.Missed, c.Expected[i].String())
+	}
+	for _, j := range result.Match.Unexpected {
+		result.Unexpected = append(result.Unexpected, result.Predictions[j].String())
+	}
+	return result, nil
+}
+
+// collectPredictions reads the findings, usage and failures of a review's rules from the store
+func collectPredictions(s store.Store, reviewID string, result *CaseResult) error {
+	reviewRules, err := s.Review().GetRulesByReviewID(reviewID)
+	if err != nil {
+		return fmt.Errorf("failed to load review rules: %w", err)
+	}
+
+	var failures []string
+	for _, reviewRule := range reviewRules {
+		result.Usage.Add(reviewRule.TokenUsage)
+		if reviewRule.Status == model.RuleStatusFailed {
+			failures = append(failures, fmt.Sprintf("%s: %s", reviewRule.RuleID, reviewRule.ErrorMessage))
+			continue
+		}
+
+		results, err := s.Review().GetResultsByRuleID(reviewRule.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load results of rule %s: %w", reviewRule.RuleID, err)
+		}
+		for _, res := range results {
+			items, _ := res.Data["findings"].([]interface{})
+			for _, item := range items {
+				if finding, ok := item.(map[string]interface{}); ok {
+					result.Predictions = append(result.Predictions, NewPrediction(reviewRule.RuleID, finding))
+				}
+			}
+		}
+	}
+	result.Error = strings.Join(failures, "; ")
+	return nil
+}
+
+// selectRules returns the rules with the given IDs (all rules if none), without outputs
+func selectRules(rulesConfig *dsl.ReviewRulesConfig, ruleIDs []string) (*dsl.ReviewRulesConfig, error) {
+	selected := *rulesConfig
+	selected.Rules = nil
+
+	wanted := make(map[string]bool, len(ruleIDs))
+	for _, id := range ruleIDs {
+		wanted[id] = true
+	}
+	found := make(map[string]bool, len(ruleIDs))
+	for _, rule := range rulesConfig.Rules {
+		if len(wanted) == 0 || wanted[rule.ID] {
+			rule.Output = nil
+			selected.Rules = append(selected.Rules, rule)
+			found[rule.ID] = true
+		}
+	}
+	for _, id := range ruleIDs {
+		if !found[id] {
+			return nil, fmt.Errorf("rule %s not found in review file", id)
+		}
+	}
+	if len(selected.Rules) == 0 {
+		return nil, fmt.Errorf("review file has no rules to evaluate")
+	}
+	return &selected, nil
+}
+
+// configureRules returns a copy of the rules using the configuration's agent and model.
+// Fallback agents are dropped when the agent is replaced, so only the evaluated agent answers.
+func configureRules(rulesConfig *dsl.ReviewRulesConfig, cfg Configuration) *dsl.ReviewRulesConfig {
+	configured := *rulesConfig
+	configured.Rules = append([]dsl.ReviewRuleConfig(nil), rulesConfig.Rules...)
+	for i := range configured.Rules {
+		agent := &configured.Rules[i].Agent
+		if cfg.Agent != "" {
+			agent.Type = cfg.Agent
+			agent.Fallback = nil
+		}
+		if cfg.Model != "" {
+			agent.Model = cfg.Model
+		}
+	}
+	return &configured
+}
+
+// checkAgents returns an error if a rule uses an agent that is not available
+func checkAgents(rulesConfig *dsl.ReviewRulesConfig, agents map[string]base.Agent) error {
+	for i := range rulesConfig.Rules {
+		rule := &rulesConfig.Rules[i]
+		if _, ok := agents[rule.Agent.GetType()]; !ok {
+			return fmt.Errorf("agent %s of rule %s is not configured", rule.Agent.GetType(), rule.ID)
+		}
+	}
+	return nil
+}
+
+// add adds the result of a case
+func (r *ConfigurationResult) add(c *CaseResult) {
+	r.Cases = append(r.Cases, *c)
+	r.TruePositives += c.TruePositives
+	r.FalsePositives += c.FalsePositives
+	r.FalseNegatives += c.FalseNegatives
+	r.Usage.Add(c.Usage)
+	r.Duration += c.Duration
+	if c.Error != "" {
+		r.FailedCases++
+	}
+}
+
+// finish computes the metrics over all cases. Precision is 1 without predictions and
+// recall is 1 without expected findings, as nothing was reported or missed wrongly.
+func (r *ConfigurationResult) finish() {
+	r.Precision = ratio(r.TruePositives, r.TruePositives+r.FalsePositives)
+	r.Recall = ratio(r.TruePositives, r.TruePositives+r.FalseNegatives)
+	if r.Precision+r.Recall > 0 {
+		r.F1 = 2 * r.Precision * r.Recall / (r.Precision + r.Recall)
+	}
+	if len(r.Cases) > 0 {
+		r.Latency = r.Duration / int64(len(r.Cases))
+	}
+}
+
+// ratio returns n/d, or 1 if d is 0
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 1
+	}
+	return float64(n) / float64(d)
+}
+
+// EvalRun returns the stored form of the result
+func (r *ConfigurationResult) EvalRun(dataset string, rules []string) *model.EvalRun {
+	run := &model.EvalRun{
+		Dataset:        dataset,
+		Configuration:  r.Name,
+		Agent:          r.Agent,
+		Model:          r.Model,
+		Rules:          rules,
+		Cases:          len(r.Cases),
+		FailedCases:    r.FailedCases,
+		TruePositives:  r.TruePositives,
+		FalsePositives: r.FalsePositives,
+		FalseNegatives: r.FalseNegatives,
+		Precision:      r.Precision,
+		Recall:         r.Recall,
+		F1:             r.F1,
+		TokenUsage:     r.Usage,
+		Latency:        r.Latency,
+		Duration:       r.Duration,
+		CaseResults:    make([]model.EvalCaseResult, 0, len(r.Cases)),
+	}
+	for _, c := range r.Cases {
+		run.CaseResults = append(run.CaseResults, model.EvalCaseResult{
+			CaseID:         c.CaseID,
+			TruePositives:  c.TruePositives,
+			FalsePositives: c.FalsePositives,
+			FalseNegatives: c.FalseNegatives,
+			Missed:         c.Missed,
+			Unexpected:     c.Unexpected,
+			TokenUsage:     c.Usage,
+			Duration:       c.Duration,
+			Error:          c.Error,
+		})
+	}
+	return run
+}
+
+// Save stores the result of every configuration
+func (r *Report) Save(s store.Store) ([]*model.EvalRun, error) {
+	runs := make([]*model.EvalRun, 0, len(r.Results))
+	err := s.Transaction(func(tx store.Store) error {
+		for i := range r.Results {
+			run := r.Results[i].EvalRun(r.Dataset, r.Rules)
+			if err := tx.Eval().CreateRun(run); err != nil {
+				return fmt.Errorf("failed to save results of %s: %w", r.Results[i].Name, err)
+			}
+			runs = append(runs, run)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
@@ -0,0 +1,212 @@
+package eval
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/dsl"
+	"github.com/verustcode/verustcode/internal/llm"
+	"github.com/verustcode/verustcode/internal/store"
+)
+
+const securityOutput = `{"summary":"found issues","findings":[
+{"severity":"critical","title":"SQL injection in user lookup","description":"The query concatenates the name","category":"security","location":"store/users.go:4-5"},
+{"severity":"low","title":"Missing comment","description":"Exported function without comment","category":"style","location":"store/users.go:3"}]}`
+
+const emptyOutput = `{"summary":"looks good","findings":[]}`
+
+// createRepo creates a git repository with a base and a head commit and returns its path
+func createRepo(t *testing.T, dir string) string {
+	t.Helper()
+	repo := filepath.Join(dir, "repo")
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "store"), 0o755))
+
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	run("init", "-q", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "README.md"), []byte("# repo\n"), 0o644))
+	run("add", "-A")
+	run("commit", "-q", "-m", "base")
+	run("tag", "base")
+
+	users := "package store\n\nfunc FindUser(db DB, name string) {\n\tq := \"SELECT * FROM users WHERE name = '\" + name + \"'\"\n\tdb.Query(q)\n}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "store", "users.go"), []byte(users), 0o644))
+	run("add", "-A")
+	run("commit", "-q", "-m", "add user lookup")
+	run("tag", "head")
+	return repo
+}
+
+// writeDataset writes a dataset with a case expecting the SQL injection
+func writeDataset(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, "bench.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`cases:
+  - id: sqli
+    repo: repo
+    base: base
+    head: head
+    pr:
+      number: 3
+      title: Add user lookup
+    expected:
+      - file: store/users.go
+        lines: [4, 4]
+        category: security
+        title: SQL injection
+  - id: clean
+    repo: repo
+    base: base
+    head: base
+`), 0o644))
+	return path
+}
+
+// scriptedAgent answers every call with the same output and records the requested models
+type scriptedAgent struct {
+	name   string
+	output string
+
+	mu     sync.Mutex
+	models []string
+}
+
+func (a *scriptedAgent) Name() string           { return a.name }
+func (a *scriptedAgent) Version() string        { return "test" }
+func (a *scriptedAgent) Available() bool        { return true }
+func (a *scriptedAgent) SetStore(s store.Store) {}
+
+func (a *scriptedAgent) ExecuteWithPrompt(ctx context.Context, req *base.ReviewRequest, prompt string) (*base.ReviewResult, error) {
+	a.mu.Lock()
+	a.models = append(a.models, req.Model)
+	a.mu.Unlock()
+
+	result := base.NewResult(req.RequestID, a.name)
+	result.Text = a.output
+	result.Usage = &llm.Usage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120}
+	return result, nil
+}
+
+func TestLoadDataset(t *testing.T) {
+	dir := t.TempDir()
+	ds, err := LoadDataset(writeDataset(t, dir))
+	require.NoError(t, err)
+
+	assert.Equal(t, "bench", ds.Name)
+	require.Len(t, ds.Cases, 2)
+	assert.Equal(t, filepath.Join(dir, "repo"), ds.Cases[0].Repo)
+	assert.Equal(t, DefaultRepoURL, ds.Cases[0].PR.RepoURL)
+	assert.Equal(t, "store/users.go:4 SQL injection", ds.Cases[0].Expected[0].String())
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("cases:\n  - id: a\n    repo: repo\n    head: head\n"), 0o644))
+	_, err = LoadDataset(invalid)
+	assert.ErrorContains(t, err, "needs repo, base and head")
+
+	require.NoError(t, os.WriteFile(invalid, []byte("cases:\n  - id: a\n    repo: repo\n    base: --output=/tmp/x\n    head: head\n"), 0o644))
+	_, err = LoadDataset(invalid)
+	assert.ErrorContains(t, err, "invalid repo or revision")
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	createRepo(t, dir)
+	ds, err := LoadDataset(writeDataset(t, dir))
+	require.NoError(t, err)
+
+	rules := &dsl.ReviewRulesConfig{Rules: []dsl.ReviewRuleConfig{
+		{ID: "security", Agent: dsl.AgentConfig{Type: "cursor", Fallback: []string{"gemini"}},
+			Goals: dsl.GoalsConfig{Areas: []string{"security-vulnerabilities"}}},
+		{ID: "docs", Agent: dsl.AgentConfig{Type: "cursor"},
+			Goals: dsl.GoalsConfig{Areas: []string{"documentation"}}},
+	}}
+
+	cursor := &scriptedAgent{name: "cursor", output: securityOutput}
+	gemini := &scriptedAgent{name: "gemini", output: emptyOutput}
+	report, err := Run(context.Background(), ds, &Options{
+		Rules:          rules,
+		RuleIDs:        []string{"security"},
+		Configurations: []Configuration{ParseConfiguration(""), ParseConfiguration("gemini:gemini-2.5-pro")},
+		Agents: map[string]base.Agent{
+			"cursor": cursor,
+			"gemini": gemini,
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "bench", report.Dataset)
+	assert.Equal(t, []string{"security"}, report.Rules)
+	require.Len(t, report.Results, 2)
+
+	def := report.Results[0]
+	assert.Equal(t, DefaultConfigurationName, def.Name)
+	require.Len(t, def.Cases, 2)
+	assert.Equal(t, 1, def.TruePositives)
+	assert.Equal(t, 3, def.FalsePositives, "the style finding of sqli and both findings of clean")
+	assert.Equal(t, 0, def.FalseNegatives)
+	assert.InDelta(t, 0.25, def.Precision, 1e-9)
+	assert.InDelta(t, 1.0, def.Recall, 1e-9)
+	assert.InDelta(t, 0.4, def.F1, 1e-9)
+	assert.Equal(t, []string{"store/users.go:3 Missing comment"}, def.Cases[0].Unexpected)
+	assert.Empty(t, def.Cases[0].Error)
+	assert.Equal(t, 240, def.Usage.TotalTokens(), "usage of both cases")
+
+	other := report.Results[1]
+	assert.Equal(t, "gemini", other.Agent)
+	assert.Equal(t, 0, other.TruePositives)
+	assert.Equal(t, 1, other.FalseNegatives)
+	assert.InDelta(t, 1.0, other.Precision, 1e-9, "no predictions means no false positives")
+	assert.InDelta(t, 0.0, other.Recall, 1e-9)
+	assert.Equal(t, []string{"store/users.go:4 SQL injection"}, other.Cases[0].Missed)
+
+	assert.Len(t, cursor.models, 2, "only the selected rule should run")
+	assert.Equal(t, []string{"gemini-2.5-pro", "gemini-2.5-pro"}, gemini.models)
+
+	s, cleanup := store.SetupTestDB(t)
+	defer cleanup()
+	runs, err := report.Save(s)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+
+	stored, err := s.Eval().GetRun(runs[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "bench", stored.Dataset)
+	assert.Equal(t, 3, stored.FalsePositives)
+	require.Len(t, stored.CaseResults, 2)
+	assert.Equal(t, "sqli", stored.CaseResults[0].CaseID)
+}
+
+func TestRun_Errors(t *testing.T) {
+	dir := t.TempDir()
+	createRepo(t, dir)
+	ds, err := LoadDataset(writeDataset(t, dir))
+	require.NoError(t, err)
+
+	rules := &dsl.ReviewRulesConfig{Rules: []dsl.ReviewRuleConfig{{ID: "security", Agent: dsl.AgentConfig{Type: "cursor"}}}}
+	agents := map[string]base.Agent{"cursor": &scriptedAgent{name: "cursor", output: emptyOutput}}
+
+	_, err = Run(context.Background(), ds, &Options{Rules: rules, RuleIDs: []string{"missing"}, Agents: agents})
+	assert.ErrorContains(t, err, "rule missing not found")
+
+	_, err = Run(context.Background(), ds, &Options{Rules: rules, Agents: agents,
+		Configurations: []Configuration{ParseConfiguration("qoder")}})
+	assert.ErrorContains(t, err, "agent qoder of rule security is not configured")
+
+	ds.Cases[0].Head = "unknown"
+	_, err = Run(context.Background(), ds, &Options{Rules: rules, Agents: agents})
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "unknown commit unknown"), err.Error())
+}
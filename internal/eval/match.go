@@ -0,0 +1,194 @@
+// Package eval measures the precision and recall of review rules against labeled pull requests.
+// This file matches predicted findings to expected findings.
+package eval
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/verustcode/verustcode/internal/dsl"
+)
+
+// Default matching thresholds
+const (
+	DefaultLineTolerance = 3
+	DefaultMinSimilarity = 0.5
+)
+
+// MatchOptions configures the matching of predicted findings to expected findings
+type MatchOptions struct {
+	// LineTolerance is the number of lines line ranges may be apart and still overlap
+	LineTolerance int
+
+	// MinSimilarity is the minimum similarity (0.0-1.0) of the category and text of a
+	// prediction to an expected finding at an overlapping location
+	MinSimilarity float64
+}
+
+// Prediction is a finding reported by a rule
+type Prediction struct {
+	RuleID      string `json:"rule_id"`
+	File        string `json:"file"`
+	StartLine   int    `json:"start_line,omitempty"` // 0 if the location has no lines
+	EndLine     int    `json:"end_line,omitempty"`
+	Category    string `json:"category,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// NewPrediction returns the prediction of a finding of a rule's result
+func NewPrediction(ruleID string, finding map[string]interface{}) Prediction {
+	location, _ := finding["location"].(string)
+	loc := dsl.ParseLocation(location)
+	p := Prediction{RuleID: ruleID, File: loc.Path, StartLine: loc.StartLine, EndLine: loc.EndLine}
+	p.Category, _ = finding["category"].(string)
+	p.Title, _ = finding["title"].(string)
+	p.Description, _ = finding["description"].(string)
+	return p
+}
+
+// String returns the prediction as "file:start-end title"
+func (p *Prediction) String() string {
+	return formatFinding(p.File, p.StartLine, p.EndLine, p.Title)
+}
+
+// Match is a prediction matched to an expected finding
+type Match struct {
+	Expected   int     `json:"expected"`   // index in the expected findings
+	Predicted  int     `json:"predicted"`  // index in the predictions
+	Similarity float64 `json:"similarity"` // similarity of category and text
+}
+
+// MatchResult is the matching of a case's predictions to its expected findings
+type MatchResult struct {
+	Matches []Match `json:"matches,omitempty"`
+
+	// Missed and Unexpected are the indexes of the unmatched expected findings and predictions
+	Missed     []int `json:"missed,omitempty"`
+	Unexpected []int `json:"unexpected,omitempty"`
+}
+
+// MatchFindings matches predictions one-to-one to expected findings. A prediction matches an
+// expected finding if both are in the same file, their line ranges overlap (within the line
+// tolerance; a finding without lines overlaps the whole file) and the similarity of their
+// category and text reaches the minimum. Pairs are matched greedily by descending similarity.
+func MatchFindings(expected []ExpectedFinding, predicted []Prediction, opts MatchOptions) *MatchResult {
+	var candidates []Match
+	for i := range expected {
+		for j := range predicted {
+			if !overlaps(&expected[i], &predicted[j], opts.LineTolerance) {
+				continue
+			}
+			sim := similarity(&expected[i], &predicted[j])
+			if sim < opts.MinSimilarity {
+				continue
+			}
+			candidates = append(candidates, Match{Expected: i, Predicted: j, Similarity: sim})
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].Similarity > candidates[b].Similarity
+	})
+
+	result := &MatchResult{}
+	matchedExpected := make(map[int]bool)
+	matchedPredicted := make(map[int]bool)
+	for _, c := range candidates {
+		if matchedExpected[c.Expected] || matchedPredicted[c.Predicted] {
+			continue
+		}
+		matchedExpected[c.Expected] = true
+		matchedPredicted[c.Predicted] = true
+		result.Matches = append(result.Matches, c)
+	}
+	sort.Slice(result.Matches, func(a, b int) bool {
+		return result.Matches[a].Expected < result.Matches[b].Expected
+	})
+
+	for i := range expected {
+		if !matchedExpected[i] {
+			result.Missed = append(result.Missed, i)
+		}
+	}
+	for j := range predicted {
+		if !matchedPredicted[j] {
+			result.Unexpected = append(result.Unexpected, j)
+		}
+	}
+	return result
+}
+
+// overlaps returns true if the prediction is in the file of the expected finding and their
+// line ranges overlap within tolerance lines
+func overlaps(e *ExpectedFinding, p *Prediction, tolerance int) bool {
+	if dsl.ParseLocation(e.File).Path != p.File {
+		return false
+	}
+	start, end := e.LineRange()
+	if start == 0 || p.StartLine == 0 {
+		return true
+	}
+	return p.StartLine <= end+tolerance && start <= p.EndLine+tolerance
+}
+
+// similarity returns the mean of the category similarity (1 if equal, else 0) and the text
+// similarity of the labeled parts of the expected finding; 1 if it only has a location
+func similarity(e *ExpectedFinding, p *Prediction) float64 {
+	total, parts := 0.0, 0
+	if e.Category != "" {
+		parts++
+		if strings.EqualFold(e.Category, p.Category) {
+			total++
+		}
+	}
+	if expectedWords := words(e.Title + " " + e.Description); len(expectedWords) > 0 {
+		parts++
+		total += overlapCoefficient(expectedWords, words(p.Title+" "+p.Description))
+	}
+	if parts == 0 {
+		return 1
+	}
+	return total / float64(parts)
+}
+
+// words returns the set of lowercase words of text with at least 3 letters or digits
+func words(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) >= 3 {
+			set[word] = true
+		}
+	}
+	return set
+}
+
+// overlapCoefficient returns |a ∩ b| / min(|a|, |b|), so a short label matches a longer
+// description that contains its words
+func overlapCoefficient(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for word := range a {
+		if b[word] {
+			common++
+		}
+	}
+	return float64(common) / float64(min(len(a), len(b)))
+}
+
+// formatFinding returns a finding as "file:start-end title"
+func formatFinding(file string, start, end int, title string) string {
+	location := file
+	switch {
+	case start > 0 && end > start:
+		location = fmt.Sprintf("%s:%d-%d", file, start, end)
+	case start > 0:
+		location = fmt.Sprintf("%s:%d", file, start)
+	}
+	return strings.TrimSpace(location + " " + title)
+}
@@ -0,0 +1,75 @@
+package eval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPrediction(t *testing.T) {
+	p := NewPrediction("security", map[string]interface{}{
+		"location":    "./store/users.go:L12-L10",
+		"category":    "security",
+		"title":       "SQL injection",
+		"description": "Query is built from user input",
+	})
+	assert.Equal(t, Prediction{
+		RuleID: "security", File: "store/users.go", StartLine: 10, EndLine: 12,
+		Category: "security", Title: "SQL injection", Description: "Query is built from user input",
+	}, p)
+	assert.Equal(t, "store/users.go:10-12 SQL injection", p.String())
+}
+
+func TestMatchFindings(t *testing.T) {
+	opts := MatchOptions{LineTolerance: DefaultLineTolerance, MinSimilarity: DefaultMinSimilarity}
+
+	expected := []ExpectedFinding{
+		{File: "store/users.go", Lines: []int{10, 12}, Category: "security", Title: "SQL injection in user lookup"},
+		{File: "api/handler.go", Lines: []int{40}, Category: "error-handling"},
+		{File: "README.md"},
+	}
+	predicted := []Prediction{
+		{File: "store/users.go", StartLine: 14, EndLine: 15, Category: "security", Title: "Possible SQL injection"},
+		{File: "store/users.go", StartLine: 11, EndLine: 11, Category: "security", Title: "SQL injection in user lookup query"},
+		{File: "api/handler.go", StartLine: 80, EndLine: 80, Category: "error-handling"},
+		{File: "README.md", StartLine: 3, EndLine: 3, Title: "Typo"},
+	}
+
+	result := MatchFindings(expected, predicted, opts)
+	require.Len(t, result.Matches, 2)
+	assert.Equal(t, 0, result.Matches[0].Expected)
+	assert.Equal(t, 1, result.Matches[0].Predicted, "the most similar prediction should be matched")
+	assert.Equal(t, 2, result.Matches[1].Expected)
+	assert.Equal(t, 3, result.Matches[1].Predicted, "a finding without lines matches anywhere in its file")
+	assert.Equal(t, []int{1}, result.Missed, "the handler prediction is too far from the expected line")
+	assert.Equal(t, []int{0, 2}, result.Unexpected)
+}
+
+func TestMatchFindings_Similarity(t *testing.T) {
+	opts := MatchOptions{LineTolerance: 0, MinSimilarity: DefaultMinSimilarity}
+	expected := []ExpectedFinding{{File: "a.go", Lines: []int{5, 6}, Category: "security", Title: "Hardcoded credentials"}}
+
+	tests := []struct {
+		name      string
+		predicted Prediction
+		matched   bool
+	}{
+		{"same category and text", Prediction{File: "a.go", StartLine: 6, Category: "SECURITY", Title: "Hardcoded credentials"}, true},
+		{"other category, same text", Prediction{File: "a.go", StartLine: 5, Category: "style", Title: "Credentials are hardcoded"}, true},
+		{"same category, other text", Prediction{File: "a.go", StartLine: 5, Category: "security", Title: "Weak hash"}, true},
+		{"other category and text", Prediction{File: "a.go", StartLine: 5, Category: "style", Title: "Long line"}, false},
+		{"outside line range", Prediction{File: "a.go", StartLine: 7, Category: "security", Title: "Hardcoded credentials"}, false},
+		{"other file", Prediction{File: "b.go", StartLine: 5, Category: "security", Title: "Hardcoded credentials"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.predicted
+			if p.EndLine == 0 {
+				p.EndLine = p.StartLine
+			}
+			result := MatchFindings(expected, []Prediction{p}, opts)
+			assert.Equal(t, tt.matched, len(result.Matches) == 1)
+		})
+	}
+}
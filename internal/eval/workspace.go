@@ -0,0 +1,122 @@
+// Package eval measures the precision and recall of review rules against labeled pull requests.
+// This file prepares the repository checkouts and the database of an evaluation.
+package eval
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/verustcode/verustcode/internal/config"
+	"github.com/verustcode/verustcode/internal/engine/utils"
+	"github.com/verustcode/verustcode/internal/model"
+	"github.com/verustcode/verustcode/internal/store"
+)
+
+// workspace is a checkout of a case's head commit
+type workspace struct {
+	repoPath     string
+	baseCommit   string
+	headCommit   string
+	changedFiles []string
+	linesChanged int
+	commits      []string
+}
+
+// prepareWorkspace clones the case's repository into repoPath and checks out its head commit
+func prepareWorkspace(ctx context.Context, c *Case, repoPath string) (*workspace, error) {
+	if _, err := git(ctx, "", "clone", "--quiet", "--no-checkout", "--", c.Repo, repoPath); err != nil {
+		return nil, err
+	}
+
+	ws := &workspace{repoPath: repoPath}
+	var err error
+	if ws.baseCommit, err = resolveCommit(ctx, repoPath, c.Base); err != nil {
+		return nil, err
+	}
+	if ws.headCommit, err = resolveCommit(ctx, repoPath, c.Head); err != nil {
+		return nil, err
+	}
+	if err := ws.reset(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, s := range utils.GetFileDiffStats(ctx, repoPath, ws.baseCommit, ws.headCommit) {
+		ws.changedFiles = append(ws.changedFiles, s.Path)
+		ws.linesChanged += s.LinesChanged
+	}
+	ws.commits = utils.GetCommitsInRange(ctx, repoPath, ws.baseCommit, ws.headCommit)
+	if len(ws.commits) == 0 {
+		ws.commits = []string{ws.headCommit}
+	}
+	return ws, nil
+}
+
+// reset restores a clean checkout of the head commit, discarding changes of earlier reviews
+func (ws *workspace) reset(ctx context.Context) error {
+	if _, err := git(ctx, ws.repoPath, "checkout", "--quiet", "--force", "--detach", ws.headCommit); err != nil {
+		return err
+	}
+	_, err := git(ctx, ws.repoPath, "clean", "-fdxq")
+	return err
+}
+
+// resolveCommit returns the commit SHA of a revision
+func resolveCommit(ctx context.Context, repoPath, rev string) (string, error) {
+	sha, err := git(ctx, repoPath, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown commit %s", rev)
+	}
+	return strings.TrimSpace(sha), nil
+}
+
+// git runs a git command, in repoPath if not empty
+func git(ctx context.Context, repoPath string, args ...string) (string, error) {
+	name := args[0]
+	if repoPath != "" {
+		args = append([]string{"-C", repoPath}, args...)
+	}
+	out, err := exec.CommandContext(ctx, "git", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// openStore opens the SQLite database of the evaluated reviews, with the given review
+// settings. The response cache and recording are turned off so every case calls the agents.
+func openStore(path string, reviewCfg *config.ReviewConfig) (store.Store, func(), error) {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	closeFn := func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}
+	if err := db.AutoMigrate(model.AllModels()...); err != nil {
+		closeFn()
+		return nil, nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	s := store.NewStore(db)
+	cfg := &config.Config{}
+	if reviewCfg != nil {
+		cfg.Review = *reviewCfg
+	}
+	cfg.Review.ResponseCache.Enabled = false
+	cfg.Review.Recording.Enabled = false
+	if err := config.SaveSettingsToDatabase(cfg, s, "eval"); err != nil {
+		closeFn()
+		return nil, nil, err
+	}
+	return s, closeFn, nil
+}
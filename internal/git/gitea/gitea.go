@@ -340,9 +340,21 @@ func (p *GiteaProvider) GetPullRequest(ctx context.Context, owner, repo string,
 		BaseSHA:     baseSHA,
 		Author:      authorUsername,
 		URL:         pr.HTMLURL,
+		Labels:      giteaLabelNames(pr.Labels),
 	}, nil
 }
 
+// giteaLabelNames returns the names of Gitea labels
+func giteaLabelNames(labels []*gitea.Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		if l != nil {
+			names = append(names, l.Name)
+		}
+	}
+	return names
+}
+
 // ListPullRequests lists open pull requests
 func (p *GiteaProvider) ListPullRequests(ctx context.Context, owner, repo string) ([]*provider.PullRequest, error) {
 	state := gitea.StateOpen
@@ -532,6 +544,24 @@ func (p *GiteaProvider) UpdateComment(ctx context.Context, owner, repo string, c
 	return nil
 }
 
+// ListCommitComments lists comments on a commit
+// Note: Gitea SDK does not support commit comments
+func (p *GiteaProvider) ListCommitComments(ctx context.Context, owner, repo, commitSHA string) ([]*provider.Comment, error) {
+	return nil, &provider.ProviderError{
+		Provider: "gitea",
+		Message:  "commit comments are not supported via Gitea SDK",
+	}
+}
+
+// UpdateCommitComment updates an existing commit comment by ID
+// Note: Gitea SDK does not support commit comments
+func (p *GiteaProvider) UpdateCommitComment(ctx context.Context, owner, repo, commitSHA string, commentID int64, body string) error {
+	return &provider.ProviderError{
+		Provider: "gitea",
+		Message:  "commit comments are not supported via Gitea SDK",
+	}
+}
+
 // ParseWebhook parses an incoming webhook request
 func (p *GiteaProvider) ParseWebhook(r *http.Request, secret string) (*provider.WebhookEvent, error) {
 	// Read body
@@ -596,8 +626,22 @@ func (p *GiteaProvider) ParseWebhook(r *http.Request, secret string) (*provider.
 // parsePushEvent parses a push webhook event
 func (p *GiteaProvider) parsePushEvent(body []byte, event *provider.WebhookEvent) (*provider.WebhookEvent, error) {
 	var payload struct {
-		Ref    string `json:"ref"`
-		After  string `json:"after"`
+		Ref     string `json:"ref"`
+		Before  string `json:"before"`
+		After   string `json:"after"`
+		Commits []struct {
+			ID      string `json:"id"`
+			Message string `json:"message"`
+			URL     string `json:"url"`
+			Author  struct {
+				Name     string `json:"name"`
+				Email    string `json:"email"`
+				Username string `json:"username"`
+			} `json:"author"`
+			Added    []string `json:"added"`
+			Modified []string `json:"modified"`
+			Removed  []string `json:"removed"`
+		} `json:"commits"`
 		Sender struct {
 			Login string `json:"login"`
 		} `json:"sender"`
@@ -621,7 +665,22 @@ func (p *GiteaProvider) parsePushEvent(body []byte, event *provider.WebhookEvent
 	event.Repo = payload.Repository.Name
 	event.Ref = strings.TrimPrefix(payload.Ref, "refs/heads/")
 	event.CommitSHA = payload.After
+	event.BeforeSHA = payload.Before
 	event.Sender = payload.Sender.Login
+	for _, c := range payload.Commits {
+		event.Commits = append(event.Commits, provider.PushCommit{
+			SHA:         c.ID,
+			Message:     c.Message,
+			AuthorName:  c.Author.Name,
+			AuthorEmail: c.Author.Email,
+			AuthorLogin: c.Author.Username,
+			URL:         c.URL,
+			Added:       c.Added,
+			Modified:    c.Modified,
+			Removed:     c.Removed,
+		})
+	}
+	event.ChangedFiles = provider.PushChangedFiles(event.Commits)
 
 	return event, nil
 }
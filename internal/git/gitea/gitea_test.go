@@ -311,8 +311,22 @@ func TestParseWebhook_Push(t *testing.T) {
 	p := &GiteaProvider{}
 
 	payload := map[string]interface{}{
-		"ref":   "refs/heads/main",
-		"after": "newsha123456",
+		"ref":    "refs/heads/main",
+		"before": "oldsha123456",
+		"after":  "newsha123456",
+		"commits": []map[string]interface{}{
+			{
+				"id":      "newsha123456",
+				"message": "Fix login\n\nDetails",
+				"author": map[string]interface{}{
+					"name":     "Alice",
+					"email":    "alice@example.com",
+					"username": "alice",
+				},
+				"added":    []string{"auth.go"},
+				"modified": []string{"main.go"},
+			},
+		},
 		"sender": map[string]interface{}{
 			"login": "pusher",
 		},
@@ -344,6 +358,15 @@ func TestParseWebhook_Push(t *testing.T) {
 	if event.CommitSHA != "newsha123456" {
 		t.Errorf("CommitSHA = %v, want newsha123456", event.CommitSHA)
 	}
+	if event.BeforeSHA != "oldsha123456" {
+		t.Errorf("BeforeSHA = %v, want oldsha123456", event.BeforeSHA)
+	}
+	if len(event.Commits) != 1 || event.Commits[0].Author() != "alice" {
+		t.Errorf("Commits = %+v, want one commit by alice", event.Commits)
+	}
+	if len(event.ChangedFiles) != 2 {
+		t.Errorf("ChangedFiles = %v, want [auth.go main.go]", event.ChangedFiles)
+	}
 }
 
 func TestParseWebhook_SignatureValidation(t *testing.T) {
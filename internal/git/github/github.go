@@ -376,9 +376,19 @@ func (p *GitHubProvider) GetPullRequest(ctx context.Context, owner, repo string,
 		BaseSHA:     pr.GetBase().GetSHA(),
 		Author:      pr.GetUser().GetLogin(),
 		URL:         pr.GetHTMLURL(),
+		Labels:      githubLabelNames(pr.Labels),
 	}, nil
 }
 
+// githubLabelNames returns the names of GitHub labels
+func githubLabelNames(labels []*github.Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, l.GetName())
+	}
+	return names
+}
+
 // ListPullRequests lists open pull requests
 func (p *GitHubProvider) ListPullRequests(ctx context.Context, owner, repo string) ([]*provider.PullRequest, error) {
 	prs, _, err := p.client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{
@@ -541,6 +551,63 @@ func (p *GitHubProvider) UpdateComment(ctx context.Context, owner, repo string,
 	return nil
 }
 
+// ListCommitComments lists comments on a commit
+func (p *GitHubProvider) ListCommitComments(ctx context.Context, owner, repo, commitSHA string) ([]*provider.Comment, error) {
+	comments, _, err := p.client.Repositories.ListCommitComments(ctx, owner, repo, commitSHA, &github.ListOptions{PerPage: defaultPerPage})
+	if err != nil {
+		logger.Error("Failed to list commit comments",
+			zap.Error(err),
+			zap.String("owner", owner),
+			zap.String("repo", repo),
+			zap.String("commit", commitSHA),
+		)
+		return nil, &provider.ProviderError{
+			Provider: "github",
+			Message:  "failed to list commit comments",
+			Err:      err,
+		}
+	}
+
+	result := make([]*provider.Comment, len(comments))
+	for i, c := range comments {
+		result[i] = &provider.Comment{
+			ID:        c.GetID(),
+			Body:      c.GetBody(),
+			Author:    c.GetUser().GetLogin(),
+			CreatedAt: c.GetCreatedAt().Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	return result, nil
+}
+
+// UpdateCommitComment updates an existing commit comment by ID
+// Note: commitSHA is not needed for GitHub as comments are identified by ID only
+func (p *GitHubProvider) UpdateCommitComment(ctx context.Context, owner, repo, commitSHA string, commentID int64, body string) error {
+	comment := &github.RepositoryComment{Body: &body}
+	_, _, err := p.client.Repositories.UpdateComment(ctx, owner, repo, commentID, comment)
+	if err != nil {
+		logger.Error("Failed to update commit comment",
+			zap.Error(err),
+			zap.String("owner", owner),
+			zap.String("repo", repo),
+			zap.Int64("comment_id", commentID),
+		)
+		return &provider.ProviderError{
+			Provider: "github",
+			Message:  "failed to update commit comment",
+			Err:      err,
+		}
+	}
+
+	logger.Info("Updated commit comment",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.Int64("comment_id", commentID),
+	)
+	return nil
+}
+
 // ParseWebhook parses an incoming webhook request
 func (p *GitHubProvider) ParseWebhook(r *http.Request, secret string) (*provider.WebhookEvent, error) {
 	var body []byte
@@ -595,7 +662,22 @@ func (p *GitHubProvider) ParseWebhook(r *http.Request, secret string) (*provider
 		event.Repo = payload.GetRepo().GetName()
 		event.Ref = strings.TrimPrefix(payload.GetRef(), "refs/heads/")
 		event.CommitSHA = payload.GetAfter()
+		event.BeforeSHA = payload.GetBefore()
 		event.Sender = payload.GetSender().GetLogin()
+		for _, c := range payload.Commits {
+			event.Commits = append(event.Commits, provider.PushCommit{
+				SHA:         c.GetID(),
+				Message:     c.GetMessage(),
+				AuthorName:  c.GetAuthor().GetName(),
+				AuthorEmail: c.GetAuthor().GetEmail(),
+				AuthorLogin: c.GetAuthor().GetLogin(),
+				URL:         c.GetURL(),
+				Added:       c.Added,
+				Modified:    c.Modified,
+				Removed:     c.Removed,
+			})
+		}
+		event.ChangedFiles = provider.PushChangedFiles(event.Commits)
 
 	case "pull_request":
 		var payload github.PullRequestEvent
@@ -346,6 +346,7 @@ func (p *GitLabProvider) GetPullRequest(ctx context.Context, owner, repo string,
 		BaseSHA:     baseSHA,
 		Author:      authorUsername,
 		URL:         mr.WebURL,
+		Labels:      []string(mr.Labels),
 	}, nil
 }
 
@@ -599,6 +600,111 @@ func (p *GitLabProvider) UpdateComment(ctx context.Context, owner, repo string,
 	return nil
 }
 
+// ListCommitComments lists comments (discussion notes) on a commit
+func (p *GitLabProvider) ListCommitComments(ctx context.Context, owner, repo, commitSHA string) ([]*provider.Comment, error) {
+	discussions, err := p.listCommitDiscussions(owner, repo, commitSHA)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*provider.Comment
+	for _, discussion := range discussions {
+		for _, note := range discussion.Notes {
+			if note.System {
+				continue
+			}
+
+			createdAt := ""
+			if note.CreatedAt != nil {
+				createdAt = note.CreatedAt.Format("2006-01-02T15:04:05Z")
+			}
+
+			result = append(result, &provider.Comment{
+				ID:        note.ID,
+				Body:      note.Body,
+				Author:    note.Author.Username,
+				CreatedAt: createdAt,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// UpdateCommitComment updates an existing commit note by ID.
+// GitLab addresses commit notes through their discussion, which is looked up first.
+func (p *GitLabProvider) UpdateCommitComment(ctx context.Context, owner, repo, commitSHA string, commentID int64, body string) error {
+	discussions, err := p.listCommitDiscussions(owner, repo, commitSHA)
+	if err != nil {
+		return err
+	}
+
+	discussionID := ""
+	for _, discussion := range discussions {
+		for _, note := range discussion.Notes {
+			if note.ID == commentID {
+				discussionID = discussion.ID
+			}
+		}
+	}
+	if discussionID == "" {
+		return &provider.ProviderError{
+			Provider: "gitlab",
+			Message:  fmt.Sprintf("commit comment %d not found", commentID),
+		}
+	}
+
+	pid := projectPath(owner, repo)
+	_, _, err = p.client.Discussions.UpdateCommitDiscussionNote(pid, commitSHA, discussionID, commentID, &gitlab.UpdateCommitDiscussionNoteOptions{
+		Body: &body,
+	})
+	if err != nil {
+		logger.Error("Failed to update commit comment",
+			zap.Error(err),
+			zap.String("owner", owner),
+			zap.String("repo", repo),
+			zap.String("commit", commitSHA),
+			zap.Int64("comment_id", commentID),
+		)
+		return &provider.ProviderError{
+			Provider: "gitlab",
+			Message:  "failed to update commit comment",
+			Err:      err,
+		}
+	}
+
+	logger.Info("Updated commit comment",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.String("commit", commitSHA),
+		zap.Int64("comment_id", commentID),
+	)
+	return nil
+}
+
+// listCommitDiscussions lists the discussions on a commit
+func (p *GitLabProvider) listCommitDiscussions(owner, repo, commitSHA string) ([]*gitlab.Discussion, error) {
+	pid := projectPath(owner, repo)
+
+	discussions, _, err := p.client.Discussions.ListCommitDiscussions(pid, commitSHA, &gitlab.ListCommitDiscussionsOptions{
+		ListOptions: gitlab.ListOptions{PerPage: defaultPerPage},
+	})
+	if err != nil {
+		logger.Error("Failed to list commit comments",
+			zap.Error(err),
+			zap.String("owner", owner),
+			zap.String("repo", repo),
+			zap.String("commit", commitSHA),
+		)
+		return nil, &provider.ProviderError{
+			Provider: "gitlab",
+			Message:  "failed to list commit comments",
+			Err:      err,
+		}
+	}
+	return discussions, nil
+}
+
 // ParseWebhook parses an incoming webhook request
 func (p *GitLabProvider) ParseWebhook(r *http.Request, secret string) (*provider.WebhookEvent, error) {
 	// Read body
@@ -670,9 +776,22 @@ func (p *GitLabProvider) ParseWebhook(r *http.Request, secret string) (*provider
 func (p *GitLabProvider) parsePushEvent(body []byte, event *provider.WebhookEvent) (*provider.WebhookEvent, error) {
 	var payload struct {
 		Ref      string `json:"ref"`
+		Before   string `json:"before"`
 		After    string `json:"after"`
 		UserName string `json:"user_name"`
-		Project  struct {
+		Commits  []struct {
+			ID      string `json:"id"`
+			Message string `json:"message"`
+			URL     string `json:"url"`
+			Author  struct {
+				Name  string `json:"name"`
+				Email string `json:"email"`
+			} `json:"author"`
+			Added    []string `json:"added"`
+			Modified []string `json:"modified"`
+			Removed  []string `json:"removed"`
+		} `json:"commits"`
+		Project struct {
 			PathWithNamespace string `json:"path_with_namespace"`
 			WebURL            string `json:"web_url"`
 		} `json:"project"`
@@ -698,7 +817,21 @@ func (p *GitLabProvider) parsePushEvent(body []byte, event *provider.WebhookEven
 	event.Repo = parts[1]
 	event.Ref = strings.TrimPrefix(payload.Ref, "refs/heads/")
 	event.CommitSHA = payload.After
+	event.BeforeSHA = payload.Before
 	event.Sender = payload.UserName
+	for _, c := range payload.Commits {
+		event.Commits = append(event.Commits, provider.PushCommit{
+			SHA:         c.ID,
+			Message:     c.Message,
+			AuthorName:  c.Author.Name,
+			AuthorEmail: c.Author.Email,
+			URL:         c.URL,
+			Added:       c.Added,
+			Modified:    c.Modified,
+			Removed:     c.Removed,
+		})
+	}
+	event.ChangedFiles = provider.PushChangedFiles(event.Commits)
 
 	return event, nil
 }
@@ -10,16 +10,17 @@ import (
 
 // PullRequest represents a pull/merge request
 type PullRequest struct {
-	Number      int    `json:"number"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	State       string `json:"state"` // open, closed, merged
-	HeadBranch  string `json:"head_branch"`
-	HeadSHA     string `json:"head_sha"`
-	BaseBranch  string `json:"base_branch"`
-	BaseSHA     string `json:"base_sha"` // Base commit SHA for diff range
-	Author      string `json:"author"`
-	URL         string `json:"url"`
+	Number      int      `json:"number"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	State       string   `json:"state"` // open, closed, merged
+	HeadBranch  string   `json:"head_branch"`
+	HeadSHA     string   `json:"head_sha"`
+	BaseBranch  string   `json:"base_branch"`
+	BaseSHA     string   `json:"base_sha"` // Base commit SHA for diff range
+	Author      string   `json:"author"`
+	URL         string   `json:"url"`
+	Labels      []string `json:"labels,omitempty"`
 }
 
 // WebhookEventType represents the type of webhook event
@@ -57,10 +58,92 @@ type WebhookEvent struct {
 	PRTitle       string           `json:"pr_title,omitempty"`        // PR/MR title
 	PRDescription string           `json:"pr_description,omitempty"`  // PR/MR description/body
 	BaseCommitSHA string           `json:"base_commit_sha,omitempty"` // Base commit SHA for PR diff range
-	ChangedFiles  []string         `json:"changed_files,omitempty"`   // Files changed in PR/MR
+	ChangedFiles  []string         `json:"changed_files,omitempty"`   // Files changed in PR/MR or push
+	BeforeSHA     string           `json:"before_sha,omitempty"`      // Branch head before the push (push events)
+	Commits       []PushCommit     `json:"commits,omitempty"`         // Pushed commits in payload order (push events)
 	RawPayload    []byte           `json:"-"`
 }
 
+// PushCommit represents a commit included in a push event
+type PushCommit struct {
+	SHA         string   `json:"sha"`
+	Message     string   `json:"message"`
+	AuthorName  string   `json:"author_name"`
+	AuthorEmail string   `json:"author_email,omitempty"`
+	AuthorLogin string   `json:"author_login,omitempty"` // platform username, if the provider sends it
+	URL         string   `json:"url,omitempty"`
+	Added       []string `json:"added,omitempty"`
+	Modified    []string `json:"modified,omitempty"`
+	Removed     []string `json:"removed,omitempty"`
+}
+
+// Author returns the best available author identifier (login, then name, then email)
+func (c *PushCommit) Author() string {
+	switch {
+	case c.AuthorLogin != "":
+		return c.AuthorLogin
+	case c.AuthorName != "":
+		return c.AuthorName
+	default:
+		return c.AuthorEmail
+	}
+}
+
+// HeadCommit returns the pushed commit matching CommitSHA (falling back to the last commit),
+// or nil if the push carried no commits
+func (e *WebhookEvent) HeadCommit() *PushCommit {
+	if len(e.Commits) == 0 {
+		return nil
+	}
+	for i := range e.Commits {
+		if e.Commits[i].SHA == e.CommitSHA {
+			return &e.Commits[i]
+		}
+	}
+	return &e.Commits[len(e.Commits)-1]
+}
+
+// IsBranchDeletion reports whether a push event deleted the branch
+func (e *WebhookEvent) IsBranchDeletion() bool {
+	return e.Type == EventTypePush && IsNullSHA(e.CommitSHA)
+}
+
+// nullSHA is sent by providers as before/after SHA when a branch is created/deleted
+const nullSHA = "0000000000000000000000000000000000000000"
+
+// IsNullSHA reports whether sha is the all-zero SHA used for created or deleted branches
+func IsNullSHA(sha string) bool {
+	return sha == nullSHA
+}
+
+// PushChangedFiles returns the files touched by a list of pushed commits.
+// Files are returned in first-seen order; files removed by the last commit touching them are excluded.
+func PushChangedFiles(commits []PushCommit) []string {
+	seen := make(map[string]bool)
+	var order []string
+	mark := func(files []string, present bool) {
+		for _, f := range files {
+			if _, ok := seen[f]; !ok {
+				order = append(order, f)
+			}
+			seen[f] = present
+		}
+	}
+	for _, c := range commits {
+		mark(c.Added, true)
+		mark(c.Modified, true)
+		mark(c.Removed, false)
+	}
+
+	files := make([]string, 0, len(order))
+	for _, f := range order {
+		if seen[f] {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
 // CloneOptions holds options for cloning a repository
 type CloneOptions struct {
 	Depth   int    // shallow clone depth (0 for full clone)
@@ -134,6 +217,12 @@ type Provider interface {
 	// For GitLab, prNumber is required to identify the MR containing the note
 	UpdateComment(ctx context.Context, owner, repo string, commentID int64, prNumber int, body string) error
 
+	// ListCommitComments lists comments on a commit
+	ListCommitComments(ctx context.Context, owner, repo, commitSHA string) ([]*Comment, error)
+
+	// UpdateCommitComment updates an existing comment on a commit by ID
+	UpdateCommitComment(ctx context.Context, owner, repo, commitSHA string, commentID int64, body string) error
+
 	// ParseWebhook parses an incoming webhook request
 	ParseWebhook(r *http.Request, secret string) (*WebhookEvent, error)
 
@@ -204,6 +204,48 @@ func TestIsPRUpdateEvent(t *testing.T) {
 	}
 }
 
+// ====================
+// Tests for push event helpers
+// ====================
+
+func TestPushChangedFiles(t *testing.T) {
+	commits := []PushCommit{
+		{SHA: "a", Added: []string{"new.go", "tmp.go"}, Modified: []string{"main.go"}},
+		{SHA: "b", Modified: []string{"new.go"}, Removed: []string{"tmp.go", "old.go"}},
+	}
+	assert.Equal(t, []string{"new.go", "main.go"}, PushChangedFiles(commits))
+	assert.Empty(t, PushChangedFiles(nil))
+}
+
+func TestWebhookEvent_HeadCommit(t *testing.T) {
+	event := &WebhookEvent{Type: EventTypePush, CommitSHA: "b"}
+	assert.Nil(t, event.HeadCommit())
+
+	event.Commits = []PushCommit{
+		{SHA: "b", AuthorName: "Bob"},
+		{SHA: "a", AuthorName: "Alice"},
+	}
+	require.NotNil(t, event.HeadCommit())
+	assert.Equal(t, "Bob", event.HeadCommit().Author())
+
+	event.CommitSHA = "unknown"
+	assert.Equal(t, "Alice", event.HeadCommit().Author())
+}
+
+func TestPushCommit_Author(t *testing.T) {
+	assert.Equal(t, "alice", (&PushCommit{AuthorLogin: "alice", AuthorName: "Alice"}).Author())
+	assert.Equal(t, "Alice", (&PushCommit{AuthorName: "Alice", AuthorEmail: "a@example.com"}).Author())
+	assert.Equal(t, "a@example.com", (&PushCommit{AuthorEmail: "a@example.com"}).Author())
+}
+
+func TestWebhookEvent_IsBranchDeletion(t *testing.T) {
+	assert.True(t, (&WebhookEvent{Type: EventTypePush, CommitSHA: nullSHA}).IsBranchDeletion())
+	assert.False(t, (&WebhookEvent{Type: EventTypePush, CommitSHA: "abc"}).IsBranchDeletion())
+	assert.False(t, (&WebhookEvent{Type: EventTypePullRequest, CommitSHA: nullSHA}).IsBranchDeletion())
+	assert.True(t, IsNullSHA(nullSHA))
+	assert.False(t, IsNullSHA(""))
+}
+
 // ====================
 // Tests for ProviderError
 // ====================
@@ -330,6 +372,14 @@ func (m *mockProvider) UpdateComment(ctx context.Context, owner, repo string, co
 	return nil
 }
 
+func (m *mockProvider) ListCommitComments(ctx context.Context, owner, repo, commitSHA string) ([]*Comment, error) {
+	return nil, nil
+}
+
+func (m *mockProvider) UpdateCommitComment(ctx context.Context, owner, repo, commitSHA string, commentID int64, body string) error {
+	return nil
+}
+
 func (m *mockProvider) ParseWebhook(r *http.Request, secret string) (*WebhookEvent, error) {
 	return nil, nil
 }
@@ -90,6 +90,14 @@ func (m *mockProvider) UpdateComment(ctx context.Context, owner, repo string, co
 	return nil
 }
 
+func (m *mockProvider) ListCommitComments(ctx context.Context, owner, repo, commitSHA string) ([]*provider.Comment, error) {
+	return nil, nil
+}
+
+func (m *mockProvider) UpdateCommitComment(ctx context.Context, owner, repo, commitSHA string, commentID int64, body string) error {
+	return nil
+}
+
 func (m *mockProvider) ParseWebhook(r *http.Request, secret string) (*provider.WebhookEvent, error) {
 	return nil, nil
 }
@@ -0,0 +1,402 @@
+// Package anthropic implements the LLM Client interface for the Anthropic Messages API.
+// It supports tool use through the shared tool-calling loop (see llm.RunToolLoop),
+// prompt caching, and extended thinking streamed as ChunkTypeThinking chunks.
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/llm"
+)
+
+// ClientName is the identifier for the Anthropic client
+const ClientName = "anthropic"
+
+// DefaultBaseURL is the endpoint used when no base URL is configured
+const DefaultBaseURL = "https://api.anthropic.com"
+
+// APIVersion is the Messages API version sent in the anthropic-version header
+const APIVersion = "2023-06-01"
+
+// promptCachingBeta enables prompt caching on endpoints that still require the beta header
+const promptCachingBeta = "prompt-caching-2024-07-31"
+
+// DefaultMaxTokens is the maximum number of output tokens per response (thinking excluded)
+const DefaultMaxTokens = 16384
+
+// minThinkingBudget is the smallest extended thinking budget accepted by the API
+const minThinkingBudget = 1024
+
+// apiKeyEnv is the environment variable read when no API key is configured
+const apiKeyEnv = "ANTHROPIC_API_KEY"
+
+func init() {
+	// Register the Anthropic client factory
+	llm.Register(ClientName, NewClient)
+}
+
+// Client implements the llm.Client interface for the Anthropic Messages API
+type Client struct {
+	*llm.BaseClient
+	httpClient *http.Client
+	sessions   *llm.SessionStore
+}
+
+// NewClient creates a new Anthropic client
+func NewClient(config *llm.ClientConfig) (llm.Client, error) {
+	if config == nil {
+		config = llm.NewClientConfig(ClientName)
+	}
+
+	return &Client{
+		BaseClient: llm.NewBaseClient(config),
+		// Requests are bounded by the context timeout of each execution
+		httpClient: &http.Client{},
+		sessions:   llm.NewSessionStore(),
+	}, nil
+}
+
+// Available checks if the client has credentials or a custom endpoint
+func (c *Client) Available() bool {
+	return c.apiKey(nil) != "" || c.baseURL() != DefaultBaseURL
+}
+
+// Execute performs a synchronous execution and returns the complete response
+func (c *Client) Execute(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	return c.ExecuteStream(ctx, req, nil)
+}
+
+// ExecuteStream performs a streaming execution with callback.
+// A nil callback performs a non-streaming execution.
+func (c *Client) ExecuteStream(ctx context.Context, req *llm.Request, callback llm.StreamCallback) (*llm.Response, error) {
+	startTime := time.Now()
+	operation := "execute"
+	if callback != nil {
+		operation = "execute_stream"
+	}
+	c.LogRequest(req, operation)
+
+	// Prepare the request
+	prepared, err := c.PrepareRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.ExecuteWithFallback(ctx, prepared, func(ctx context.Context, req *llm.Request, model string) (*llm.Response, error) {
+		return c.run(ctx, req, model, callback)
+	})
+
+	c.LogResponse(resp, time.Since(startTime), err)
+	return resp, err
+}
+
+// CreateSession creates a new conversation session.
+// The conversation history is kept in memory until the client is closed.
+func (c *Client) CreateSession(ctx context.Context) (string, error) {
+	return c.sessions.Create(ClientName), nil
+}
+
+// Close releases the conversation history of all sessions
+func (c *Client) Close() error {
+	c.sessions.Clear()
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// run sends the prompt and executes the tool calls of the model until it answers.
+// Responses are streamed to callback if it is not nil.
+func (c *Client) run(ctx context.Context, req *llm.Request, model string, callback llm.StreamCallback) (*llm.Response, error) {
+	timeout := c.GetConfig().GetTimeout(req)
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body := c.buildRequest(req, model)
+	turn := func(ctx context.Context, messages []llm.Message, final bool) (*llm.Turn, error) {
+		body.Messages = toMessages(messages)
+		body.ToolChoice = nil
+		if final && len(body.Tools) > 0 {
+			body.ToolChoice = &toolChoice{Type: "none"}
+		}
+		var answer *llm.Turn
+		err := c.DoWithRetry(ctx, req, func() error {
+			var err error
+			answer, err = c.complete(ctx, req, body, callback)
+			return err
+		})
+		return answer, err
+	}
+
+	messages := append(c.sessions.History(req.SessionID), llm.Message{Role: llm.RoleUser, Content: req.Prompt})
+	result, err := llm.RunToolLoop(execCtx, messages, req.Tools, llm.DefaultMaxToolRounds, turn, callback)
+	if err != nil {
+		if execCtx.Err() == context.DeadlineExceeded {
+			return nil, llm.NewClientError(ClientName, "execute", "execution timeout", llm.ErrTimeout)
+		}
+		return nil, err
+	}
+
+	c.sessions.Save(req.SessionID, result.Messages)
+	return c.BuildToolLoopResponse(req, model, result, callback), nil
+}
+
+// buildRequest builds the request body shared by all rounds of an execution
+func (c *Client) buildRequest(req *llm.Request, model string) *messagesRequest {
+	body := &messagesRequest{Model: model, MaxTokens: DefaultMaxTokens}
+
+	if budget := c.GetConfig().ThinkingBudget; budget > 0 {
+		if budget < minThinkingBudget {
+			budget = minThinkingBudget
+		}
+		body.Thinking = &thinkingConfig{Type: "enabled", BudgetTokens: budget}
+		body.MaxTokens += budget
+	}
+
+	for _, tool := range req.Tools {
+		body.Tools = append(body.Tools, toolSpec{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: tool.Parameters,
+		})
+	}
+	if len(body.Tools) > 0 {
+		// Cache the tool definitions, they are sent with every round
+		body.Tools[len(body.Tools)-1].CacheControl = &cacheControl{Type: "ephemeral"}
+	}
+	return body
+}
+
+// complete sends one Messages API request
+func (c *Client) complete(ctx context.Context, req *llm.Request, body *messagesRequest, callback llm.StreamCallback) (*llm.Turn, error) {
+	body.Stream = callback != nil
+	headers := map[string]string{
+		"anthropic-version": APIVersion,
+		"anthropic-beta":    promptCachingBeta,
+	}
+	if body.Stream {
+		headers["Accept"] = "text/event-stream"
+	}
+	if key := c.apiKey(req); key != "" {
+		headers["x-api-key"] = key
+	}
+
+	c.Logger().Debug("Sending messages request",
+		zap.String("model", body.Model),
+		zap.Int("messages", len(body.Messages)),
+		zap.Int("tools", len(body.Tools)),
+		zap.Bool("stream", body.Stream),
+		zap.Bool("thinking", body.Thinking != nil),
+	)
+
+	httpResp, err := llm.PostJSON(ctx, c.httpClient, ClientName, c.baseURL()+"/v1/messages", headers, body)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if body.Stream {
+		return c.readStream(httpResp.Body, callback)
+	}
+
+	var resp messagesResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, llm.NewClientError(ClientName, "execute", "failed to decode response", err)
+	}
+	return toTurn(resp.Content, resp.Usage), nil
+}
+
+// readStream reads a server-sent events response, forwarding deltas to callback
+func (c *Client) readStream(r io.Reader, callback llm.StreamCallback) (*llm.Turn, error) {
+	var blocks []*contentBlock
+	var text, thinking strings.Builder
+	inputs := make(map[int]*strings.Builder) // partial JSON input of tool_use blocks
+	var u usage
+
+	block := func(index int) *contentBlock {
+		if index >= 0 && index < len(blocks) {
+			return blocks[index]
+		}
+		return nil
+	}
+
+	err := llm.ReadSSE(r, func(event, data string) (bool, error) {
+		var e streamEvent
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			c.Logger().Debug("Failed to parse stream event", zap.String("data", data), zap.Error(err))
+			return false, nil
+		}
+
+		switch e.Type {
+		case "message_start":
+			if e.Message != nil {
+				u = e.Message.Usage
+			}
+		case "content_block_start":
+			if e.ContentBlock != nil {
+				for len(blocks) <= e.Index {
+					blocks = append(blocks, &contentBlock{})
+				}
+				blocks[e.Index] = e.ContentBlock
+				if e.ContentBlock.Type == "tool_use" {
+					inputs[e.Index] = &strings.Builder{}
+				}
+			}
+		case "content_block_delta":
+			b := block(e.Index)
+			if b == nil || e.Delta == nil {
+				return false, nil
+			}
+			switch e.Delta.Type {
+			case "text_delta":
+				b.Text += e.Delta.Text
+				text.WriteString(e.Delta.Text)
+				callback(&llm.StreamChunk{Type: llm.ChunkTypeText, Content: text.String(), Delta: e.Delta.Text})
+			case "thinking_delta":
+				b.Thinking += e.Delta.Thinking
+				thinking.WriteString(e.Delta.Thinking)
+				callback(&llm.StreamChunk{Type: llm.ChunkTypeThinking, Content: thinking.String(), Delta: e.Delta.Thinking})
+			case "signature_delta":
+				b.Signature += e.Delta.Signature
+			case "input_json_delta":
+				if input, ok := inputs[e.Index]; ok {
+					input.WriteString(e.Delta.PartialJSON)
+				}
+			}
+		case "content_block_stop":
+			if b := block(e.Index); b != nil && b.Type == "tool_use" {
+				if input := inputs[e.Index]; input != nil && input.Len() > 0 {
+					b.Input = json.RawMessage(input.String())
+				}
+			}
+		case "message_delta":
+			if e.Usage != nil {
+				// Output tokens are cumulative
+				u.OutputTokens = e.Usage.OutputTokens
+			}
+		case "message_stop":
+			return true, nil
+		case "error":
+			if e.Error != nil {
+				if e.Error.Type == "overloaded_error" {
+					return true, llm.NewRetryableError(ClientName, "execute_stream", e.Error.Message, nil)
+				}
+				return true, llm.NewClientError(ClientName, "execute_stream", e.Error.Message, llm.ErrInvalidResponse)
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		var clientErr *llm.ClientError
+		if errors.As(err, &clientErr) {
+			return nil, err
+		}
+		return nil, llm.NewClientError(ClientName, "execute_stream", "failed to read stream", err)
+	}
+
+	content := make([]contentBlock, 0, len(blocks))
+	for _, b := range blocks {
+		content = append(content, *b)
+	}
+	return toTurn(content, u), nil
+}
+
+// toTurn converts the content blocks and usage of a response.
+// The blocks are kept as the native content of the message: thinking blocks
+// must be sent back unchanged with the tool results.
+func toTurn(content []contentBlock, u usage) *llm.Turn {
+	message := llm.Message{Role: llm.RoleAssistant, Native: content}
+	var text strings.Builder
+	for _, b := range content {
+		switch b.Type {
+		case "text":
+			text.WriteString(b.Text)
+		case "tool_use":
+			arguments := string(b.Input)
+			if arguments == "" {
+				arguments = "{}"
+			}
+			message.ToolCalls = append(message.ToolCalls, llm.ToolCall{ID: b.ID, Name: b.Name, Arguments: arguments})
+		}
+	}
+	message.Content = text.String()
+
+	return &llm.Turn{
+		Message: message,
+		Usage: &llm.Usage{
+			// Cached input tokens are part of the prompt
+			PromptTokens:     u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens,
+			CompletionTokens: u.OutputTokens,
+			CachedTokens:     u.CacheReadInputTokens,
+		},
+	}
+}
+
+// toMessages converts a conversation to Messages API messages.
+// Consecutive tool results are sent in one user message, and the last block is a
+// prompt caching breakpoint so that the next round reuses the conversation prefix.
+func toMessages(messages []llm.Message) []message {
+	result := make([]message, 0, len(messages))
+	for _, m := range messages {
+		switch m.Role {
+		case llm.RoleAssistant:
+			if native, ok := m.Native.([]contentBlock); ok {
+				result = append(result, message{Role: "assistant", Content: native})
+				continue
+			}
+			var content []contentBlock
+			if m.Content != "" {
+				content = append(content, contentBlock{Type: "text", Text: m.Content})
+			}
+			for _, call := range m.ToolCalls {
+				arguments := call.Arguments
+				if arguments == "" {
+					arguments = "{}"
+				}
+				content = append(content, contentBlock{Type: "tool_use", ID: call.ID, Name: call.Name, Input: json.RawMessage(arguments)})
+			}
+			result = append(result, message{Role: "assistant", Content: content})
+
+		case llm.RoleTool:
+			block := contentBlock{Type: "tool_result", ToolUseID: m.ToolCallID, Content: m.Content, IsError: m.IsError}
+			if last := len(result) - 1; last >= 0 && result[last].Role == "user" && result[last].Content[0].Type == "tool_result" {
+				result[last].Content = append(result[last].Content, block)
+				continue
+			}
+			result = append(result, message{Role: "user", Content: []contentBlock{block}})
+
+		default:
+			result = append(result, message{Role: "user", Content: []contentBlock{{Type: "text", Text: m.Content}}})
+		}
+	}
+
+	if last := len(result) - 1; last >= 0 && result[last].Role == "user" {
+		content := append([]contentBlock(nil), result[last].Content...)
+		content[len(content)-1].CacheControl = &cacheControl{Type: "ephemeral"}
+		result[last].Content = content
+	}
+	return result
+}
+
+// baseURL returns the configured endpoint without trailing slash
+func (c *Client) baseURL() string {
+	if url := c.GetConfig().BaseURL; url != "" {
+		return strings.TrimRight(url, "/")
+	}
+	return DefaultBaseURL
+}
+
+// apiKey returns the API key of req or the configured API key, or the ANTHROPIC_API_KEY environment variable
+func (c *Client) apiKey(req *llm.Request) string {
+	if key := c.GetConfig().GetAPIKey(req); key != "" {
+		return key
+	}
+	return os.Getenv(apiKeyEnv)
+}
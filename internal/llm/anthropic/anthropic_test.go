@@ -0,0 +1,262 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/verustcode/verustcode/internal/llm"
+)
+
+// fakeServer is an httptest stand-in for the Messages API.
+// Each request is answered by the next handler; the decoded bodies are recorded.
+type fakeServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	handlers []http.HandlerFunc
+	requests []messagesRequest
+}
+
+func newFakeServer(t *testing.T, handlers ...http.HandlerFunc) *fakeServer {
+	s := &fakeServer{handlers: handlers}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			http.NotFound(w, r)
+			return
+		}
+		var body messagesRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		s.mu.Lock()
+		index := len(s.requests)
+		s.requests = append(s.requests, body)
+		s.mu.Unlock()
+		if index >= len(s.handlers) {
+			t.Errorf("unexpected request %d", index+1)
+			http.Error(w, "unexpected request", http.StatusInternalServerError)
+			return
+		}
+		s.handlers[index](w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeServer) client(t *testing.T, thinkingBudget int) llm.Client {
+	config := llm.NewClientConfig(ClientName).
+		WithBaseURL(s.URL + "/").
+		WithAPIKey("sk-ant-test").
+		WithDefaultModel("claude-test").
+		WithThinkingBudget(thinkingBudget).
+		WithRetryDelay(time.Millisecond)
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+func jsonReply(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}
+}
+
+func TestClientRegistration(t *testing.T) {
+	if !llm.IsRegistered(ClientName) {
+		t.Fatalf("Anthropic client is not registered")
+	}
+	client, err := llm.Create(ClientName, llm.NewClientConfig(ClientName).WithAPIKey("sk-ant-test"))
+	if err != nil {
+		t.Fatalf("Failed to create Anthropic client via factory: %v", err)
+	}
+	if !client.Available() {
+		t.Error("client with an API key should be available")
+	}
+}
+
+func TestExecute_ToolCalls(t *testing.T) {
+	server := newFakeServer(t,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("x-api-key") != "sk-ant-test" || r.Header.Get("anthropic-version") != APIVersion {
+				t.Errorf("headers = %v", r.Header)
+			}
+			if !strings.Contains(r.Header.Get("anthropic-beta"), "prompt-caching") {
+				t.Errorf("anthropic-beta = %q", r.Header.Get("anthropic-beta"))
+			}
+			jsonReply(`{"model":"claude-test","stop_reason":"tool_use","content":[
+				{"type":"thinking","thinking":"Need the file","signature":"sig-1"},
+				{"type":"tool_use","id":"toolu_1","name":"read_file","input":{"path":"main.go"}},
+				{"type":"tool_use","id":"toolu_2","name":"list_files","input":{}}],
+				"usage":{"input_tokens":10,"output_tokens":4,"cache_creation_input_tokens":100}}`)(w, r)
+		},
+		jsonReply(`{"content":[{"type":"text","text":"No issues in main.go"}],
+			"usage":{"input_tokens":20,"output_tokens":6,"cache_read_input_tokens":100}}`),
+	)
+
+	var gotArgs []string
+	handler := func(ctx context.Context, arguments string) (string, error) {
+		gotArgs = append(gotArgs, arguments)
+		return "package main", nil
+	}
+	tools := []*llm.Tool{
+		{Name: "read_file", Parameters: map[string]interface{}{"type": "object"}, Handler: handler},
+		{Name: "list_files", Parameters: map[string]interface{}{"type": "object"}, Handler: handler},
+	}
+
+	resp, err := server.client(t, 2048).Execute(context.Background(), llm.NewRequest("Review").WithTools(tools))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Content != "No issues in main.go" {
+		t.Errorf("Content = %q", resp.Content)
+	}
+	if strings.Join(gotArgs, ";") != `{"path":"main.go"};{}` {
+		t.Errorf("tool arguments = %q", gotArgs)
+	}
+	if resp.Usage.PromptTokens != 230 || resp.Usage.CompletionTokens != 10 || resp.Usage.TotalTokens != 240 || resp.Usage.CachedTokens != 100 {
+		t.Errorf("Usage = %+v", resp.Usage)
+	}
+
+	first := server.requests[0]
+	if first.Thinking == nil || first.Thinking.BudgetTokens != 2048 || first.MaxTokens != DefaultMaxTokens+2048 {
+		t.Errorf("thinking = %+v, max_tokens = %d", first.Thinking, first.MaxTokens)
+	}
+	if len(first.Tools) != 2 || first.Tools[0].CacheControl != nil || first.Tools[1].CacheControl == nil {
+		t.Errorf("tools = %+v, want a cache breakpoint on the last tool", first.Tools)
+	}
+
+	messages := server.requests[1].Messages
+	if len(messages) != 3 {
+		t.Fatalf("second request has %d messages, want user, assistant and tool results", len(messages))
+	}
+	if thinking := messages[1].Content[0]; thinking.Type != "thinking" || thinking.Signature != "sig-1" {
+		t.Errorf("assistant content = %+v, want the signed thinking block", messages[1].Content)
+	}
+	results := messages[2].Content
+	if messages[2].Role != "user" || len(results) != 2 || results[0].ToolUseID != "toolu_1" || results[0].Content != "package main" {
+		t.Errorf("tool results = %+v", messages[2])
+	}
+	if results[0].CacheControl != nil || results[1].CacheControl == nil {
+		t.Errorf("tool results = %+v, want a cache breakpoint on the last block", results)
+	}
+}
+
+func TestExecuteStream(t *testing.T) {
+	events := []string{
+		`{"type":"message_start","message":{"usage":{"input_tokens":8,"output_tokens":1}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"Checking"}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"sig"}}`,
+		`{"type":"content_block_stop","index":0}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Looks "}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"good"}}`,
+		`{"type":"content_block_stop","index":1}`,
+		`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":5}}`,
+		`{"type":"message_stop"}`,
+	}
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			var e struct{ Type string }
+			json.Unmarshal([]byte(event), &e)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, event)
+		}
+	})
+
+	var chunks []*llm.StreamChunk
+	resp, err := server.client(t, 0).ExecuteStream(context.Background(), llm.NewRequest("Review"), func(chunk *llm.StreamChunk) {
+		chunks = append(chunks, chunk)
+	})
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+
+	if !server.requests[0].Stream || server.requests[0].Thinking != nil {
+		t.Errorf("request = %+v, want streaming without thinking", server.requests[0])
+	}
+	if resp.Content != "Looks good" || resp.Usage.TotalTokens != 13 {
+		t.Errorf("Content = %q, Usage = %+v", resp.Content, resp.Usage)
+	}
+
+	var types []string
+	for _, chunk := range chunks {
+		types = append(types, string(chunk.Type))
+	}
+	if got := strings.Join(types, ","); got != "thinking,text,text,result" {
+		t.Errorf("chunk types = %s", got)
+	}
+	if chunks[0].Content != "Checking" || chunks[2].Content != "Looks good" || chunks[2].Delta != "good" {
+		t.Errorf("chunks = %+v", chunks)
+	}
+}
+
+func TestExecute_Retries(t *testing.T) {
+	server := newFakeServer(t,
+		func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`, 529)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n")
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"type\":\"content_block_start\",\"index\":0,\"content_block\":{\"type\":\"text\",\"text\":\"done\"}}\n\n")
+			fmt.Fprint(w, "data: {\"type\":\"message_stop\"}\n\n")
+		},
+	)
+	resp, err := server.client(t, 0).ExecuteStream(context.Background(), llm.NewRequest("Review"), func(*llm.StreamChunk) {})
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+	if resp.Content != "done" || len(server.requests) != 3 {
+		t.Errorf("Content = %q after %d requests, want done after 3", resp.Content, len(server.requests))
+	}
+
+	// Client errors are not retried
+	server = newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`, http.StatusUnauthorized)
+	})
+	_, err = server.client(t, 0).Execute(context.Background(), llm.NewRequest("Review"))
+	if err == nil || !strings.Contains(err.Error(), "invalid x-api-key") {
+		t.Errorf("Execute() error = %v, want the API error message", err)
+	}
+	if llm.IsRetryable(err) || len(server.requests) != 1 {
+		t.Errorf("401 should fail without retry, got %d requests", len(server.requests))
+	}
+}
+
+func TestToMessages(t *testing.T) {
+	// Messages restored without native content (e.g. from another client) are rebuilt
+	messages := toMessages([]llm.Message{
+		{Role: llm.RoleUser, Content: "go"},
+		{Role: llm.RoleAssistant, Content: "Reading", ToolCalls: []llm.ToolCall{{ID: "a", Name: "read_file"}, {ID: "b", Name: "grep", Arguments: `{"pattern":"x"}`}}},
+		{Role: llm.RoleTool, ToolCallID: "a", Content: "data"},
+		{Role: llm.RoleTool, ToolCallID: "b", Content: "error: bad pattern", IsError: true},
+	})
+	if len(messages) != 3 {
+		t.Fatalf("messages = %+v, want user, assistant and one tool results message", messages)
+	}
+
+	assistant := messages[1].Content
+	if len(assistant) != 3 || assistant[0].Text != "Reading" || string(assistant[1].Input) != "{}" || assistant[2].Name != "grep" {
+		t.Errorf("assistant content = %+v", assistant)
+	}
+	results := messages[2].Content
+	if len(results) != 2 || results[0].ToolUseID != "a" || !results[1].IsError || results[1].CacheControl == nil {
+		t.Errorf("tool results = %+v", results)
+	}
+	if messages[0].Content[0].CacheControl != nil {
+		t.Error("only the last message should be a cache breakpoint")
+	}
+}
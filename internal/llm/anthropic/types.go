@@ -0,0 +1,111 @@
+package anthropic
+
+import (
+	"encoding/json"
+)
+
+// messagesRequest is the body of a Messages API request
+type messagesRequest struct {
+	Model      string          `json:"model"`
+	MaxTokens  int             `json:"max_tokens"`
+	Messages   []message       `json:"messages"`
+	Tools      []toolSpec      `json:"tools,omitempty"`
+	ToolChoice *toolChoice     `json:"tool_choice,omitempty"`
+	Thinking   *thinkingConfig `json:"thinking,omitempty"`
+	Stream     bool            `json:"stream,omitempty"`
+}
+
+// message is a message of the conversation
+type message struct {
+	Role    string         `json:"role"`
+	Content []contentBlock `json:"content"`
+}
+
+// contentBlock is a content block of a message (text, thinking, tool_use, tool_result)
+type contentBlock struct {
+	Type string `json:"type"`
+
+	// text blocks
+	Text string `json:"text,omitempty"`
+
+	// thinking and redacted_thinking blocks, sent back as they were received
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Data      string `json:"data,omitempty"`
+
+	// tool_use blocks
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+
+	// tool_result blocks
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+	IsError   bool   `json:"is_error,omitempty"`
+
+	CacheControl *cacheControl `json:"cache_control,omitempty"`
+}
+
+// toolSpec declares a tool the model may call
+type toolSpec struct {
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description,omitempty"`
+	InputSchema  map[string]interface{} `json:"input_schema"`
+	CacheControl *cacheControl          `json:"cache_control,omitempty"`
+}
+
+// toolChoice controls whether the model may call tools
+type toolChoice struct {
+	Type string `json:"type"`
+}
+
+// thinkingConfig enables extended thinking
+type thinkingConfig struct {
+	Type         string `json:"type"`
+	BudgetTokens int    `json:"budget_tokens"`
+}
+
+// cacheControl marks a prompt caching breakpoint
+type cacheControl struct {
+	Type string `json:"type"`
+}
+
+// messagesResponse is a non-streaming Messages API response
+type messagesResponse struct {
+	Model      string         `json:"model"`
+	Content    []contentBlock `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Usage      usage          `json:"usage"`
+}
+
+// usage is the token usage of a response
+type usage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// streamEvent is a server-sent event of a streaming response
+type streamEvent struct {
+	Type         string            `json:"type"`
+	Index        int               `json:"index"`
+	Message      *messagesResponse `json:"message"`
+	ContentBlock *contentBlock     `json:"content_block"`
+	Delta        *struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		Thinking    string `json:"thinking"`
+		Signature   string `json:"signature"`
+		PartialJSON string `json:"partial_json"`
+		StopReason  string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage *usage    `json:"usage"`
+	Error *apiError `json:"error"`
+}
+
+// apiError is the error object of an error event
+type apiError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
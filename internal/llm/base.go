@@ -193,6 +193,7 @@ func (b *BaseClient) PrepareRequest(req *Request) (*Request, error) {
 		SessionID:      req.SessionID,
 		WorkDir:        req.WorkDir,
 		ResponseSchema: req.ResponseSchema,
+		Tools:          req.Tools,
 		Options:        req.Options,
 	}
 
@@ -19,6 +19,9 @@ type ClientConfig struct {
 	// CLIPath is the path to the CLI tool executable
 	CLIPath string
 
+	// BaseURL is the endpoint of API clients (e.g., "https://api.openai.com/v1")
+	BaseURL string
+
 	// APIKey is the API key for authentication (if required)
 	APIKey string
 
@@ -34,9 +37,15 @@ type ClientConfig struct {
 	// RetryDelay is the default delay between retry attempts
 	RetryDelay time.Duration
 
+	// ThinkingBudget enables extended thinking with this token budget (API clients that support it, 0 disables)
+	ThinkingBudget int
+
 	// ExtraArgs contains additional command line arguments to append when executing the CLI
 	// These arguments will be added after the default arguments (space-separated string)
 	ExtraArgs string
+
+	// Sandbox isolates the CLI process of CLI clients (nil runs the CLI unsandboxed)
+	Sandbox *SandboxConfig
 }
 
 // NewClientConfig creates a new ClientConfig with default values
@@ -55,6 +64,12 @@ func (c *ClientConfig) WithCLIPath(path string) *ClientConfig {
 	return c
 }
 
+// WithBaseURL sets the API endpoint
+func (c *ClientConfig) WithBaseURL(url string) *ClientConfig {
+	c.BaseURL = url
+	return c
+}
+
 // WithAPIKey sets the API key
 func (c *ClientConfig) WithAPIKey(key string) *ClientConfig {
 	c.APIKey = key
@@ -85,12 +100,24 @@ func (c *ClientConfig) WithRetryDelay(delay time.Duration) *ClientConfig {
 	return c
 }
 
+// WithThinkingBudget sets the extended thinking token budget
+func (c *ClientConfig) WithThinkingBudget(budget int) *ClientConfig {
+	c.ThinkingBudget = budget
+	return c
+}
+
 // WithExtraArgs sets additional command line arguments (space-separated string)
 func (c *ClientConfig) WithExtraArgs(args string) *ClientConfig {
 	c.ExtraArgs = args
 	return c
 }
 
+// WithSandbox sets the sandbox configuration of CLI executions
+func (c *ClientConfig) WithSandbox(sandbox *SandboxConfig) *ClientConfig {
+	c.Sandbox = sandbox
+	return c
+}
+
 // GetTimeout returns the timeout to use, considering request options
 func (c *ClientConfig) GetTimeout(req *Request) time.Duration {
 	if req != nil {
@@ -106,3 +133,11 @@ func (c *ClientConfig) GetModel(req *Request) string {
 	}
 	return c.DefaultModel
 }
+
+// GetAPIKey returns the API key to use, considering request and configuration
+func (c *ClientConfig) GetAPIKey(req *Request) string {
+	if req != nil && req.APIKey != "" {
+		return req.APIKey
+	}
+	return c.APIKey
+}
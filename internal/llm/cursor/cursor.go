@@ -121,8 +121,15 @@ func (c *Client) CreateSession(ctx context.Context) (string, error) {
 	cmd.Stdout = &output
 	cmd.Stderr = &output
 
+	releaseSandbox, err := llm.SandboxCommand(cmd, c.GetConfig().Sandbox, c.Logger())
+	if err != nil {
+		return "", llm.NewClientError(ClientName, "create_session", "failed to prepare sandbox", err)
+	}
+
 	// Execute
-	if err := cmd.Run(); err != nil {
+	err = cmd.Run()
+	releaseSandbox(err)
+	if err != nil {
 		outputStr := output.String()
 		c.Logger().Error("Failed to create session",
 			zap.Error(err),
@@ -163,10 +170,11 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 
 	// Append extra arguments from config
 	config := c.GetConfig()
+	apiKey := config.GetAPIKey(req)
 
 	// Add API key if configured
-	if config.APIKey != "" {
-		args = append(args, "--api-key", config.APIKey)
+	if apiKey != "" {
+		args = append(args, "--api-key", apiKey)
 	}
 
 	if config.ExtraArgs != "" {
@@ -180,9 +188,9 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 
 	// Log full command info for debugging
 	apiKeyMasked := ""
-	if config.APIKey != "" {
-		if len(config.APIKey) > 8 {
-			apiKeyMasked = config.APIKey[:4] + "..." + config.APIKey[len(config.APIKey)-4:]
+	if apiKey != "" {
+		if len(apiKey) > 8 {
+			apiKeyMasked = apiKey[:4] + "..." + apiKey[len(apiKey)-4:]
 		} else {
 			apiKeyMasked = "***"
 		}
@@ -202,7 +210,7 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 		zap.Strings("args", maskedArgs),
 		zap.String("extra_args", config.ExtraArgs),
 		zap.String("api_key", apiKeyMasked),
-		zap.Bool("has_api_key", config.APIKey != ""),
+		zap.Bool("has_api_key", apiKey != ""),
 	)
 
 	// Create stdin pipe for prompt input
@@ -216,8 +224,15 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	// Run the CLI in the sandbox if configured (violations are logged with the review ID)
+	releaseSandbox, err := llm.SandboxCommand(cmd, config.Sandbox, c.Logger(), zap.String("review_id", req.GetMetadata("review_id")))
+	if err != nil {
+		return nil, llm.NewClientError(ClientName, "execute", "failed to prepare sandbox", err)
+	}
+
 	// Start command
 	if err := cmd.Start(); err != nil {
+		releaseSandbox(err)
 		return nil, llm.NewClientError(ClientName, "execute", "failed to start cursor-agent", err)
 	}
 
@@ -237,7 +252,9 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 	}()
 
 	// Wait for command to finish
-	if err := cmd.Wait(); err != nil {
+	err = cmd.Wait()
+	releaseSandbox(err)
+	if err != nil {
 		// Check for timeout
 		if execCtx.Err() == context.DeadlineExceeded {
 			return nil, llm.NewClientError(ClientName, "execute", "execution timeout", llm.ErrTimeout)
@@ -316,10 +333,11 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 
 	// Append extra arguments from config
 	config := c.GetConfig()
+	apiKey := config.GetAPIKey(req)
 
 	// Add API key if configured
-	if config.APIKey != "" {
-		args = append(args, "--api-key", config.APIKey)
+	if apiKey != "" {
+		args = append(args, "--api-key", apiKey)
 	}
 
 	if config.ExtraArgs != "" {
@@ -333,9 +351,9 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 
 	// Log full command info for debugging
 	apiKeyMasked := ""
-	if config.APIKey != "" {
-		if len(config.APIKey) > 8 {
-			apiKeyMasked = config.APIKey[:4] + "..." + config.APIKey[len(config.APIKey)-4:]
+	if apiKey != "" {
+		if len(apiKey) > 8 {
+			apiKeyMasked = apiKey[:4] + "..." + apiKey[len(apiKey)-4:]
 		} else {
 			apiKeyMasked = "***"
 		}
@@ -355,7 +373,7 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 		zap.Strings("args", maskedArgs),
 		zap.String("extra_args", config.ExtraArgs),
 		zap.String("api_key", apiKeyMasked),
-		zap.Bool("has_api_key", config.APIKey != ""),
+		zap.Bool("has_api_key", apiKey != ""),
 	)
 
 	// Create stdin pipe for prompt input
@@ -375,8 +393,15 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 		return nil, llm.NewClientError(ClientName, "execute_stream", "failed to create stderr pipe", err)
 	}
 
+	// Run the CLI in the sandbox if configured (violations are logged with the review ID)
+	releaseSandbox, err := llm.SandboxCommand(cmd, config.Sandbox, c.Logger(), zap.String("review_id", req.GetMetadata("review_id")))
+	if err != nil {
+		return nil, llm.NewClientError(ClientName, "execute_stream", "failed to prepare sandbox", err)
+	}
+
 	// Start command
 	if err := cmd.Start(); err != nil {
+		releaseSandbox(err)
 		return nil, llm.NewClientError(ClientName, "execute_stream", "failed to start cursor-agent", err)
 	}
 
@@ -494,6 +519,13 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 				chunk.Content = result
 			}
 
+			// Extract usage stats if available
+			if usage, ok := data["usage"].(map[string]interface{}); ok {
+				if usageJSON, err := json.Marshal(usage); err == nil {
+					chunk.Metadata["usage"] = string(usageJSON)
+				}
+			}
+
 		case "error":
 			chunk.Type = llm.ChunkTypeError
 			if msg, ok := data["message"].(string); ok {
@@ -528,7 +560,9 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 		done <- true
 	}()
 
-	// Process chunks and call callback
+	// Process chunks and call callback.
+	// Token usage is reported by the result message.
+	var usage *llm.Usage
 	processingDone := make(chan bool)
 	go func() {
 		defer func() { processingDone <- true }()
@@ -540,6 +574,7 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 					// Result replaces the buffer
 					textBuffer.Reset()
 					textBuffer.WriteString(chunk.Delta)
+					usage = llm.ParseUsageJSON(chunk.Metadata["usage"])
 				} else {
 					textBuffer.WriteString(chunk.Delta)
 				}
@@ -562,6 +597,7 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 
 	// Wait for command to finish
 	err = cmd.Wait()
+	releaseSandbox(err)
 
 	// Get final session ID
 	sessionIDMu.Lock()
@@ -599,6 +635,7 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 
 	// Build response
 	resp := c.BuildResponse(finalContent, model, finalSessionID, req.ResponseSchema)
+	resp.Usage = usage
 
 	// Copy metadata from request to response (including rule_id)
 	if req.Options != nil && req.Options.Metadata != nil {
@@ -21,7 +21,8 @@ func Register(name string, factory ClientFactory) {
 	registry[name] = factory
 }
 
-// Create creates a client by name using the registered factory
+// Create creates a client by name using the registered factory.
+// The client reports its stream chunks to the stream recorder of the execution context, if any.
 func Create(name string, config *ClientConfig) (Client, error) {
 	registryLock.RLock()
 	factory, ok := registry[name]
@@ -38,7 +39,11 @@ func Create(name string, config *ClientConfig) (Client, error) {
 		config.Name = name
 	}
 
-	return factory(config)
+	client, err := factory(config)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingClient{Client: client}, nil
 }
 
 // List returns all registered client names
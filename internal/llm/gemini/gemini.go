@@ -141,10 +141,11 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 
 	// Append extra arguments from config
 	config := c.GetConfig()
+	apiKey := config.GetAPIKey(req)
 
 	// Add API key if configured
-	if config.APIKey != "" {
-		args = append(args, "--api-key", config.APIKey)
+	if apiKey != "" {
+		args = append(args, "--api-key", apiKey)
 	}
 
 	if config.ExtraArgs != "" {
@@ -157,7 +158,7 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 	c.setupCommandEnv(cmd, req.WorkDir)
 
 	// Log full command info for debugging (with masked sensitive args)
-	apiKeyMasked := maskAPIKey(config.APIKey)
+	apiKeyMasked := maskAPIKey(apiKey)
 	maskedArgs := maskSensitiveArgs(args)
 	cmdStr := c.cliPath + " " + strings.Join(maskedArgs, " ") + " < [stdin prompt]"
 	c.Logger().Info("Executing gemini command",
@@ -169,7 +170,7 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 		zap.Strings("args", maskedArgs),
 		zap.String("extra_args", config.ExtraArgs),
 		zap.String("api_key", apiKeyMasked),
-		zap.Bool("has_api_key", config.APIKey != ""),
+		zap.Bool("has_api_key", apiKey != ""),
 	)
 
 	// Create stdin pipe for prompt input
@@ -183,8 +184,15 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	// Run the CLI in the sandbox if configured (violations are logged with the review ID)
+	releaseSandbox, err := llm.SandboxCommand(cmd, config.Sandbox, c.Logger(), zap.String("review_id", req.GetMetadata("review_id")))
+	if err != nil {
+		return nil, llm.NewClientError(ClientName, "execute", "failed to prepare sandbox", err)
+	}
+
 	// Start command
 	if err := cmd.Start(); err != nil {
+		releaseSandbox(err)
 		return nil, llm.NewClientError(ClientName, "execute", "failed to start gemini", err)
 	}
 
@@ -198,7 +206,9 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 	}()
 
 	// Wait for command to finish
-	if err := cmd.Wait(); err != nil {
+	err = cmd.Wait()
+	releaseSandbox(err)
+	if err != nil {
 		// Check for timeout
 		if execCtx.Err() == context.DeadlineExceeded {
 			return nil, llm.NewClientError(ClientName, "execute", "execution timeout", llm.ErrTimeout)
@@ -243,10 +253,11 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 
 	// Append extra arguments from config
 	config := c.GetConfig()
+	apiKey := config.GetAPIKey(req)
 
 	// Add API key if configured
-	if config.APIKey != "" {
-		args = append(args, "--api-key", config.APIKey)
+	if apiKey != "" {
+		args = append(args, "--api-key", apiKey)
 	}
 
 	if config.ExtraArgs != "" {
@@ -259,7 +270,7 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 	c.setupCommandEnv(cmd, req.WorkDir)
 
 	// Log full command info for debugging (with masked sensitive args)
-	apiKeyMasked := maskAPIKey(config.APIKey)
+	apiKeyMasked := maskAPIKey(apiKey)
 	maskedArgs := maskSensitiveArgs(args)
 	cmdStr := c.cliPath + " " + strings.Join(maskedArgs, " ") + " < [stdin prompt]"
 	c.Logger().Info("Executing gemini command (streaming)",
@@ -271,7 +282,7 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 		zap.Strings("args", maskedArgs),
 		zap.String("extra_args", config.ExtraArgs),
 		zap.String("api_key", apiKeyMasked),
-		zap.Bool("has_api_key", config.APIKey != ""),
+		zap.Bool("has_api_key", apiKey != ""),
 	)
 
 	// Create stdin pipe for prompt input
@@ -291,8 +302,15 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 		return nil, llm.NewClientError(ClientName, "execute_stream", "failed to create stderr pipe", err)
 	}
 
+	// Run the CLI in the sandbox if configured (violations are logged with the review ID)
+	releaseSandbox, err := llm.SandboxCommand(cmd, config.Sandbox, c.Logger(), zap.String("review_id", req.GetMetadata("review_id")))
+	if err != nil {
+		return nil, llm.NewClientError(ClientName, "execute_stream", "failed to prepare sandbox", err)
+	}
+
 	// Start command
 	if err := cmd.Start(); err != nil {
+		releaseSandbox(err)
 		return nil, llm.NewClientError(ClientName, "execute_stream", "failed to start gemini", err)
 	}
 
@@ -463,11 +481,17 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 		done <- true
 	}()
 
-	// Process chunks and call callback
+	// Process chunks and call callback.
+	// Token usage is reported by the result message.
+	var usage *llm.Usage
 	processingDone := make(chan bool)
 	go func() {
 		defer func() { processingDone <- true }()
 		for chunk := range outputChan {
+			if chunk.Type == llm.ChunkTypeResult {
+				usage = llm.ParseUsageJSON(chunk.Metadata["stats"])
+			}
+
 			// Call callback
 			if callback != nil {
 				callback(chunk)
@@ -483,6 +507,7 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 
 	// Wait for command to finish
 	err = cmd.Wait()
+	releaseSandbox(err)
 
 	// Get final session ID
 	sessionIDMu.Lock()
@@ -520,6 +545,7 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 
 	// Build response
 	resp := c.BuildResponse(finalContent, model, finalSessionID, req.ResponseSchema)
+	resp.Usage = usage
 
 	// Copy metadata from request to response (including rule_id)
 	if req.Options != nil && req.Options.Metadata != nil {
@@ -0,0 +1,195 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxErrorBody is the maximum number of bytes of an error response that are read
+const maxErrorBody = 4096
+
+// maxSSELine is the maximum size of a line of a streaming response
+const maxSSELine = 4 * 1024 * 1024
+
+// StatusError is an HTTP error response of an API endpoint
+type StatusError struct {
+	// StatusCode is the HTTP status code
+	StatusCode int
+
+	// Message is the error message of the response
+	Message string
+
+	// RetryAfter is the delay requested by the Retry-After header (0 if absent)
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// NewStatusError builds the client error of a failed HTTP response.
+// Rate limits (429, and 529 "overloaded") and server errors (5xx) are retryable.
+func NewStatusError(client string, resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+	statusErr := &StatusError{StatusCode: resp.StatusCode, Message: errorMessage(data)}
+	if statusErr.Message == "" {
+		statusErr.Message = http.StatusText(resp.StatusCode)
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		statusErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return NewRetryableError(client, "execute", "API request failed", statusErr)
+	}
+	return NewClientError(client, "execute", "API request failed", statusErr)
+}
+
+// errorMessage extracts the message of an error response body:
+// {"error": {"message": "..."}}, {"error": "..."} or the raw body
+func errorMessage(data []byte) string {
+	var body struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(data, &body) == nil && len(body.Error) > 0 {
+		var object struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body.Error, &object) == nil && object.Message != "" {
+			return object.Message
+		}
+		var message string
+		if json.Unmarshal(body.Error, &message) == nil && message != "" {
+			return message
+		}
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// PostJSON sends a JSON request to an API endpoint.
+// Returns the response for 200 OK (the caller closes its body), a StatusError otherwise.
+// Transport errors are retryable.
+func PostJSON(ctx context.Context, httpClient *http.Client, client, url string, headers map[string]string, body interface{}) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, NewClientError(client, "execute", "failed to encode request", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, NewClientError(client, "execute", "failed to create request", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, NewClientError(client, "execute", "request canceled", ctx.Err())
+		}
+		return nil, NewRetryableError(client, "execute", "request failed", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, NewStatusError(client, resp)
+	}
+	return resp, nil
+}
+
+// DoWithRetry calls fn until it succeeds, fails with an error that is not retryable,
+// or the retries of the request are exhausted. It waits the Retry-After delay of
+// StatusErrors, and otherwise backs off exponentially from the retry delay.
+func (b *BaseClient) DoWithRetry(ctx context.Context, req *Request, fn func() error) error {
+	maxRetries := req.GetMaxRetries(b.config.MaxRetries)
+	delay := req.GetRetryDelay(b.config.RetryDelay)
+
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || !IsRetryable(err) || attempt >= maxRetries {
+			return err
+		}
+
+		wait := delay << attempt
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			wait = statusErr.RetryAfter
+		}
+		b.logger.Warn("API request failed, retrying",
+			zap.Int("attempt", attempt+1),
+			zap.Int("max_retries", maxRetries),
+			zap.Duration("wait", wait),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// ReadSSE reads a server-sent events stream and calls fn with the event name and data
+// of each event. Reading stops at the end of the stream, when fn returns done or an error,
+// or at a "[DONE]" data line.
+func ReadSSE(r io.Reader, fn func(event, data string) (done bool, err error)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxSSELine)
+
+	event := ""
+	var data strings.Builder
+	dispatch := func() (bool, error) {
+		if data.Len() == 0 {
+			event = ""
+			return false, nil
+		}
+		payload := data.String()
+		name := event
+		data.Reset()
+		event = ""
+		if payload == "[DONE]" {
+			return true, nil
+		}
+		return fn(name, payload)
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// A blank line ends an event
+			if done, err := dispatch(); done || err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, ":"):
+			// Comments are used as keep-alives
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	_, err := dispatch()
+	return err
+}
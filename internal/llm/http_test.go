@@ -0,0 +1,111 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadSSE(t *testing.T) {
+	stream := strings.Join([]string{
+		": ping",
+		"event: message_start",
+		`data: {"a":1}`,
+		"",
+		"data: line one",
+		"data: line two",
+		"",
+		"data: [DONE]",
+		"",
+		"data: ignored",
+		"",
+	}, "\n")
+
+	var events []string
+	err := ReadSSE(strings.NewReader(stream), func(event, data string) (bool, error) {
+		events = append(events, event+"|"+data)
+		return false, nil
+	})
+	if err != nil {
+		t.Fatalf("ReadSSE() error = %v", err)
+	}
+	if strings.Join(events, ";") != `message_start|{"a":1};|line one`+"\n"+`line two` {
+		t.Errorf("events = %q", events)
+	}
+
+	// The last event doesn't need a trailing blank line, and fn can stop reading
+	count := 0
+	stop := errors.New("stop")
+	err = ReadSSE(strings.NewReader("data: 1\n\ndata: 2"), func(event, data string) (bool, error) {
+		count++
+		if data == "2" {
+			return true, stop
+		}
+		return false, nil
+	})
+	if err != stop || count != 2 {
+		t.Errorf("ReadSSE() = %v after %d events", err, count)
+	}
+}
+
+func TestPostJSON_StatusErrors(t *testing.T) {
+	tests := []struct {
+		status    int
+		body      string
+		message   string
+		retryable bool
+	}{
+		{http.StatusTooManyRequests, `{"error":{"message":"slow down"}}`, "slow down", true},
+		{http.StatusServiceUnavailable, `{"error":"model is loading"}`, "model is loading", true},
+		{http.StatusBadRequest, "bad input", "bad input", false},
+		{http.StatusNotFound, "", "Not Found", false},
+	}
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		}))
+		_, err := PostJSON(context.Background(), server.Client(), "test", server.URL, nil, map[string]string{})
+		server.Close()
+
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) {
+			t.Errorf("status %d: error = %v, want a StatusError", tt.status, err)
+			continue
+		}
+		if statusErr.Message != tt.message || statusErr.RetryAfter != 2*time.Second {
+			t.Errorf("status %d: StatusError = %+v", tt.status, statusErr)
+		}
+		if IsRetryable(err) != tt.retryable {
+			t.Errorf("status %d: retryable = %v, want %v", tt.status, IsRetryable(err), tt.retryable)
+		}
+	}
+}
+
+func TestDoWithRetry(t *testing.T) {
+	client := NewBaseClient(NewClientConfig("test").WithMaxRetries(2).WithRetryDelay(time.Millisecond))
+	req := NewRequest("prompt")
+
+	calls := 0
+	err := client.DoWithRetry(context.Background(), req, func() error {
+		calls++
+		return NewRetryableError("test", "execute", "unavailable", nil)
+	})
+	if err == nil || calls != 3 {
+		t.Errorf("DoWithRetry() = %v after %d calls, want an error after 3", err, calls)
+	}
+
+	calls = 0
+	err = client.DoWithRetry(context.Background(), req, func() error {
+		calls++
+		return NewClientError("test", "execute", "bad request", nil)
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("non-retryable errors should not be retried, got %d calls", calls)
+	}
+}
@@ -1,5 +1,6 @@
 // Package mock implements a mock LLM Client for testing and development.
-// It returns hardcoded responses with timestamps and random IDs to verify execution.
+// It returns hardcoded responses with timestamps and random IDs to verify execution,
+// or canned responses supplied by a Responder (used by the DSL test harness).
 package mock
 
 import (
@@ -22,9 +23,16 @@ func init() {
 	llm.Register(ClientName, NewClient)
 }
 
+// Responder returns the canned response content for a prepared request.
+// An error fails the request as a real client error would.
+type Responder func(req *llm.Request) (string, error)
+
 // Client implements the llm.Client interface for mock responses
 type Client struct {
 	*llm.BaseClient
+
+	// responder supplies canned responses (nil for generated mock responses)
+	responder Responder
 }
 
 // NewClient creates a new Mock client
@@ -38,6 +46,19 @@ func NewClient(config *llm.ClientConfig) (llm.Client, error) {
 	}, nil
 }
 
+// NewClientWithResponder creates a new Mock client that answers with the responder's content
+// instead of the generated mock response
+func NewClientWithResponder(config *llm.ClientConfig, responder Responder) llm.Client {
+	if config == nil {
+		config = llm.NewClientConfig(ClientName)
+	}
+
+	return &Client{
+		BaseClient: llm.NewBaseClient(config),
+		responder:  responder,
+	}
+}
+
 // Available always returns true for mock client
 func (c *Client) Available() bool {
 	return true
@@ -80,7 +101,11 @@ func (c *Client) Execute(ctx context.Context, req *llm.Request) (*llm.Response,
 	ruleID := prepared.GetMetadata("rule_id")
 
 	// DSL mode always returns markdown format
-	content := c.generateMarkdownResponse(timestamp, requestID, agentName, agentVersion, model, ruleID)
+	content, err := c.respond(prepared, timestamp, requestID, agentName, agentVersion, model, ruleID)
+	if err != nil {
+		c.LogResponse(nil, time.Since(startTime), err)
+		return nil, err
+	}
 
 	resp := c.BuildResponse(content, model, prepared.SessionID, prepared.ResponseSchema)
 
@@ -135,7 +160,11 @@ func (c *Client) ExecuteStream(ctx context.Context, req *llm.Request, callback l
 	ruleID := prepared.GetMetadata("rule_id")
 
 	// DSL mode always returns markdown format
-	content := c.generateMarkdownResponse(timestamp, requestID, agentName, agentVersion, model, ruleID)
+	content, err := c.respond(prepared, timestamp, requestID, agentName, agentVersion, model, ruleID)
+	if err != nil {
+		c.LogResponse(nil, time.Since(startTime), err)
+		return nil, err
+	}
 
 	// Simulate streaming by sending chunks
 	if callback != nil {
@@ -190,6 +219,14 @@ func (c *Client) Close() error {
 	return nil
 }
 
+// respond returns the responder's canned content, or a generated markdown response if no responder is set
+func (c *Client) respond(req *llm.Request, timestamp, requestID, agentName, agentVersion, model, ruleID string) (string, error) {
+	if c.responder != nil {
+		return c.responder(req)
+	}
+	return c.generateMarkdownResponse(timestamp, requestID, agentName, agentVersion, model, ruleID), nil
+}
+
 // generateRandomID generates a random hexadecimal string of 16 characters
 func (c *Client) generateRandomID() string {
 	bytes := make([]byte, 8)
@@ -160,3 +160,38 @@ func TestClient_generateMarkdownResponse(t *testing.T) {
 	assert.Contains(t, resp.Content, "rule-123")
 	assert.Contains(t, resp.Content, "Findings")
 }
+
+func TestNewClientWithResponder(t *testing.T) {
+	client := NewClientWithResponder(nil, func(req *llm.Request) (string, error) {
+		return "canned response for " + req.GetMetadata("rule_id"), nil
+	})
+
+	req := llm.NewRequest("test prompt").
+		WithOptions(&llm.RequestOptions{
+			Metadata: map[string]string{"rule_id": "rule-123"},
+		})
+
+	resp, err := client.Execute(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "canned response for rule-123", resp.Content)
+	assert.Equal(t, "rule-123", resp.Metadata["rule_id"])
+
+	var streamed string
+	resp, err = client.ExecuteStream(context.Background(), req, func(chunk *llm.StreamChunk) {
+		streamed = chunk.Content
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "canned response for rule-123", resp.Content)
+	assert.Equal(t, resp.Content, streamed)
+}
+
+func TestNewClientWithResponder_Error(t *testing.T) {
+	client := NewClientWithResponder(nil, func(req *llm.Request) (string, error) {
+		return "", llm.NewClientError(ClientName, "execute", "no canned response", nil)
+	})
+
+	resp, err := client.Execute(context.Background(), llm.NewRequest("test prompt"))
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.False(t, llm.IsRetryable(err))
+}
@@ -0,0 +1,310 @@
+// Package ollama implements the LLM Client interface for a local Ollama endpoint,
+// for deployments where code must not leave the network. It supports tool calling
+// through the shared tool-calling loop (see llm.RunToolLoop) and structured output
+// through the format parameter of /api/chat.
+package ollama
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/llm"
+)
+
+// ClientName is the identifier for the Ollama client
+const ClientName = "ollama"
+
+// DefaultBaseURL is the endpoint used when no base URL is configured
+const DefaultBaseURL = "http://localhost:11434"
+
+// maxLine is the maximum size of a line of a streaming response
+const maxLine = 4 * 1024 * 1024
+
+func init() {
+	// Register the Ollama client factory
+	llm.Register(ClientName, NewClient)
+}
+
+// Client implements the llm.Client interface for Ollama endpoints
+type Client struct {
+	*llm.BaseClient
+	httpClient *http.Client
+	sessions   *llm.SessionStore
+}
+
+// NewClient creates a new Ollama client
+func NewClient(config *llm.ClientConfig) (llm.Client, error) {
+	if config == nil {
+		config = llm.NewClientConfig(ClientName)
+	}
+
+	return &Client{
+		BaseClient: llm.NewBaseClient(config),
+		// Requests are bounded by the context timeout of each execution
+		httpClient: &http.Client{},
+		sessions:   llm.NewSessionStore(),
+	}, nil
+}
+
+// Available always returns true: a local endpoint needs no credentials.
+// Connectivity is only checked when a request is sent.
+func (c *Client) Available() bool {
+	return true
+}
+
+// Execute performs a synchronous execution and returns the complete response
+func (c *Client) Execute(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	return c.ExecuteStream(ctx, req, nil)
+}
+
+// ExecuteStream performs a streaming execution with callback.
+// A nil callback performs a non-streaming execution.
+func (c *Client) ExecuteStream(ctx context.Context, req *llm.Request, callback llm.StreamCallback) (*llm.Response, error) {
+	startTime := time.Now()
+	operation := "execute"
+	if callback != nil {
+		operation = "execute_stream"
+	}
+	c.LogRequest(req, operation)
+
+	// Prepare the request
+	prepared, err := c.PrepareRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.ExecuteWithFallback(ctx, prepared, func(ctx context.Context, req *llm.Request, model string) (*llm.Response, error) {
+		return c.run(ctx, req, model, callback)
+	})
+
+	c.LogResponse(resp, time.Since(startTime), err)
+	return resp, err
+}
+
+// CreateSession creates a new conversation session.
+// The conversation history is kept in memory until the client is closed.
+func (c *Client) CreateSession(ctx context.Context) (string, error) {
+	return c.sessions.Create(ClientName), nil
+}
+
+// Close releases the conversation history of all sessions
+func (c *Client) Close() error {
+	c.sessions.Clear()
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// run sends the prompt and executes the tool calls of the model until it answers.
+// Responses are streamed to callback if it is not nil.
+func (c *Client) run(ctx context.Context, req *llm.Request, model string, callback llm.StreamCallback) (*llm.Response, error) {
+	timeout := c.GetConfig().GetTimeout(req)
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := c.buildRequest(req, model)
+	if err != nil {
+		return nil, err
+	}
+	tools := body.Tools
+
+	calls := 0
+	turn := func(ctx context.Context, messages []llm.Message, final bool) (*llm.Turn, error) {
+		body.Messages = toChatMessages(messages)
+		// Ollama has no tool choice: the last round is sent without tools
+		body.Tools = tools
+		if final {
+			body.Tools = nil
+		}
+		var answer *llm.Turn
+		err := c.DoWithRetry(ctx, req, func() error {
+			var err error
+			answer, err = c.complete(ctx, req, body, callback)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		// Ollama doesn't identify tool calls, IDs are generated for the conversation
+		for i := range answer.Message.ToolCalls {
+			calls++
+			answer.Message.ToolCalls[i].ID = fmt.Sprintf("call_%d", calls)
+		}
+		return answer, nil
+	}
+
+	messages := append(c.sessions.History(req.SessionID), llm.Message{Role: llm.RoleUser, Content: req.Prompt})
+	result, err := llm.RunToolLoop(execCtx, messages, req.Tools, llm.DefaultMaxToolRounds, turn, callback)
+	if err != nil {
+		if execCtx.Err() == context.DeadlineExceeded {
+			return nil, llm.NewClientError(ClientName, "execute", "execution timeout", llm.ErrTimeout)
+		}
+		return nil, err
+	}
+
+	c.sessions.Save(req.SessionID, result.Messages)
+	return c.BuildToolLoopResponse(req, model, result, callback), nil
+}
+
+// buildRequest builds the request body shared by all rounds of an execution
+func (c *Client) buildRequest(req *llm.Request, model string) (*chatRequest, error) {
+	body := &chatRequest{Model: model, Think: c.GetConfig().ThinkingBudget > 0}
+
+	if req.ResponseSchema != nil && req.ResponseSchema.Schema != nil {
+		schema, err := req.ResponseSchema.JSONSchema()
+		if err != nil {
+			return nil, llm.NewClientError(ClientName, "execute", "invalid response schema", err)
+		}
+		body.Format = schema
+	}
+
+	for _, tool := range req.Tools {
+		body.Tools = append(body.Tools, toolSpec{
+			Type: "function",
+			Function: functionSpec{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+	return body, nil
+}
+
+// complete sends one chat request
+func (c *Client) complete(ctx context.Context, req *llm.Request, body *chatRequest, callback llm.StreamCallback) (*llm.Turn, error) {
+	body.Stream = callback != nil
+	headers := map[string]string{}
+	if key := c.GetConfig().GetAPIKey(req); key != "" {
+		// Ollama has no authentication, but it is often deployed behind a reverse proxy
+		headers["Authorization"] = "Bearer " + key
+	}
+
+	c.Logger().Debug("Sending chat request",
+		zap.String("model", body.Model),
+		zap.Int("messages", len(body.Messages)),
+		zap.Int("tools", len(body.Tools)),
+		zap.Bool("stream", body.Stream),
+	)
+
+	httpResp, err := llm.PostJSON(ctx, c.httpClient, ClientName, c.baseURL()+"/api/chat", headers, body)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if body.Stream {
+		return c.readStream(httpResp.Body, callback)
+	}
+
+	var resp chatResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, llm.NewClientError(ClientName, "execute", "failed to decode response", err)
+	}
+	if resp.Error != "" {
+		return nil, llm.NewClientError(ClientName, "execute", resp.Error, llm.ErrInvalidResponse)
+	}
+	return toTurn(resp.Message, &resp), nil
+}
+
+// readStream reads a newline-delimited JSON response, forwarding deltas to callback
+func (c *Client) readStream(r io.Reader, callback llm.StreamCallback) (*llm.Turn, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLine)
+
+	var text, thinking strings.Builder
+	message := chatMessage{Role: "assistant"}
+	var last *chatResponse
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var chunk chatResponse
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			c.Logger().Debug("Failed to parse stream line", zap.String("line", line), zap.Error(err))
+			continue
+		}
+		if chunk.Error != "" {
+			return nil, llm.NewClientError(ClientName, "execute_stream", chunk.Error, llm.ErrInvalidResponse)
+		}
+
+		if delta := chunk.Message.Thinking; delta != "" {
+			thinking.WriteString(delta)
+			callback(&llm.StreamChunk{Type: llm.ChunkTypeThinking, Content: thinking.String(), Delta: delta})
+		}
+		if delta := chunk.Message.Content; delta != "" {
+			text.WriteString(delta)
+			callback(&llm.StreamChunk{Type: llm.ChunkTypeText, Content: text.String(), Delta: delta})
+		}
+		// Tool calls are sent whole, not in fragments
+		message.ToolCalls = append(message.ToolCalls, chunk.Message.ToolCalls...)
+
+		if chunk.Done {
+			last = &chunk
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, llm.NewClientError(ClientName, "execute_stream", "failed to read stream", err)
+	}
+
+	message.Content = text.String()
+	return toTurn(message, last), nil
+}
+
+// toTurn converts the assistant message and token counts of a response
+func toTurn(m chatMessage, resp *chatResponse) *llm.Turn {
+	message := llm.Message{Role: llm.RoleAssistant, Content: m.Content}
+	for _, call := range m.ToolCalls {
+		arguments := string(call.Function.Arguments)
+		if arguments == "" || arguments == "null" {
+			arguments = "{}"
+		}
+		message.ToolCalls = append(message.ToolCalls, llm.ToolCall{Name: call.Function.Name, Arguments: arguments})
+	}
+
+	turn := &llm.Turn{Message: message}
+	if resp != nil {
+		turn.Usage = &llm.Usage{
+			PromptTokens:     resp.PromptEvalCount,
+			CompletionTokens: resp.EvalCount,
+		}
+	}
+	return turn
+}
+
+// toChatMessages converts a conversation to Ollama chat messages
+func toChatMessages(messages []llm.Message) []chatMessage {
+	result := make([]chatMessage, 0, len(messages))
+	for _, m := range messages {
+		message := chatMessage{Role: m.Role, Content: m.Content, ToolName: m.ToolName}
+		for _, call := range m.ToolCalls {
+			arguments := json.RawMessage(call.Arguments)
+			if !json.Valid(arguments) {
+				arguments = json.RawMessage("{}")
+			}
+			message.ToolCalls = append(message.ToolCalls, toolCall{
+				Function: functionCall{Name: call.Name, Arguments: arguments},
+			})
+		}
+		result = append(result, message)
+	}
+	return result
+}
+
+// baseURL returns the configured endpoint without trailing slash
+func (c *Client) baseURL() string {
+	if url := c.GetConfig().BaseURL; url != "" {
+		return strings.TrimRight(url, "/")
+	}
+	return DefaultBaseURL
+}
@@ -0,0 +1,221 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/verustcode/verustcode/internal/llm"
+)
+
+// fakeServer is an httptest stand-in for an Ollama /api/chat endpoint.
+// Each request is answered by the next handler; the decoded bodies are recorded.
+type fakeServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	handlers []http.HandlerFunc
+	requests []chatRequest
+}
+
+func newFakeServer(t *testing.T, handlers ...http.HandlerFunc) *fakeServer {
+	s := &fakeServer{handlers: handlers}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			http.NotFound(w, r)
+			return
+		}
+		var body chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		s.mu.Lock()
+		index := len(s.requests)
+		s.requests = append(s.requests, body)
+		s.mu.Unlock()
+		if index >= len(s.handlers) {
+			t.Errorf("unexpected request %d", index+1)
+			http.Error(w, "unexpected request", http.StatusInternalServerError)
+			return
+		}
+		s.handlers[index](w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeServer) client(t *testing.T) llm.Client {
+	config := llm.NewClientConfig(ClientName).
+		WithBaseURL(s.URL).
+		WithDefaultModel("qwen-test").
+		WithRetryDelay(time.Millisecond)
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+func jsonReply(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}
+}
+
+func TestClientRegistration(t *testing.T) {
+	if !llm.IsRegistered(ClientName) {
+		t.Fatalf("Ollama client is not registered")
+	}
+	client, err := llm.Create(ClientName, nil)
+	if err != nil {
+		t.Fatalf("Failed to create Ollama client via factory: %v", err)
+	}
+	if !client.Available() {
+		t.Error("Ollama client should be available without credentials")
+	}
+}
+
+func TestExecute_StructuredOutput(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("Authorization = %q, want none without API key", got)
+		}
+		jsonReply(`{"model":"qwen-test","message":{"role":"assistant","content":"{\"summary\":\"ok\"}"},
+			"done":true,"prompt_eval_count":12,"eval_count":5}`)(w, r)
+	})
+
+	schema := &llm.ResponseSchema{
+		Name: "review",
+		Schema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"summary": map[string]interface{}{"type": "string"}},
+		},
+	}
+	resp, err := server.client(t).Execute(context.Background(), llm.NewRequest("Review").WithSchema(schema))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	body := server.requests[0]
+	if body.Model != "qwen-test" || body.Stream {
+		t.Errorf("request = %+v, want non-streaming qwen-test", body)
+	}
+	if format, ok := body.Format.(map[string]interface{}); !ok || format["type"] != "object" {
+		t.Errorf("format = %#v, want the JSON schema", body.Format)
+	}
+
+	parsed, ok := resp.Parsed.(*map[string]interface{})
+	if !ok || (*parsed)["summary"] != "ok" {
+		t.Errorf("Parsed = %#v, ParseErr = %v", resp.Parsed, resp.ParseErr)
+	}
+	if resp.Usage == nil || resp.Usage.PromptTokens != 12 || resp.Usage.CompletionTokens != 5 || resp.Usage.TotalTokens != 17 {
+		t.Errorf("Usage = %+v", resp.Usage)
+	}
+}
+
+func TestExecute_ToolCalls(t *testing.T) {
+	server := newFakeServer(t,
+		jsonReply(`{"message":{"role":"assistant","content":"","tool_calls":[
+			{"function":{"name":"read_file","arguments":{"path":"main.go"}}}]},"done":true}`),
+		jsonReply(`{"message":{"role":"assistant","content":"No issues in main.go"},"done":true}`),
+	)
+
+	var gotArgs string
+	tools := []*llm.Tool{{
+		Name:       "read_file",
+		Parameters: map[string]interface{}{"type": "object"},
+		Handler: func(ctx context.Context, arguments string) (string, error) {
+			gotArgs = arguments
+			return "package main", nil
+		},
+	}}
+
+	resp, err := server.client(t).Execute(context.Background(), llm.NewRequest("Review").WithTools(tools))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Content != "No issues in main.go" || gotArgs != `{"path":"main.go"}` {
+		t.Errorf("Content = %q, tool arguments = %q", resp.Content, gotArgs)
+	}
+
+	messages := server.requests[1].Messages
+	if len(messages) != 3 {
+		t.Fatalf("second request has %d messages, want user, assistant and tool result", len(messages))
+	}
+	if call := messages[1].ToolCalls; len(call) != 1 || string(call[0].Function.Arguments) != `{"path":"main.go"}` {
+		t.Errorf("assistant tool calls = %+v", call)
+	}
+	if messages[2].Role != "tool" || messages[2].ToolName != "read_file" || messages[2].Content != "package main" {
+		t.Errorf("tool result = %+v", messages[2])
+	}
+}
+
+func TestExecuteStream(t *testing.T) {
+	lines := []string{
+		`{"message":{"role":"assistant","content":"","thinking":"Checking"},"done":false}`,
+		`{"message":{"role":"assistant","content":"Looks "},"done":false}`,
+		`{"message":{"role":"assistant","content":"good"},"done":false}`,
+		`{"message":{"role":"assistant","content":""},"done":true,"prompt_eval_count":8,"eval_count":2}`,
+	}
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		fmt.Fprint(w, strings.Join(lines, "\n")+"\n")
+	})
+
+	var chunks []*llm.StreamChunk
+	resp, err := server.client(t).ExecuteStream(context.Background(), llm.NewRequest("Review"), func(chunk *llm.StreamChunk) {
+		chunks = append(chunks, chunk)
+	})
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+
+	if !server.requests[0].Stream {
+		t.Errorf("request = %+v, want streaming", server.requests[0])
+	}
+	if resp.Content != "Looks good" || resp.Usage.TotalTokens != 10 {
+		t.Errorf("Content = %q, Usage = %+v", resp.Content, resp.Usage)
+	}
+
+	var types []string
+	for _, chunk := range chunks {
+		types = append(types, string(chunk.Type))
+	}
+	if got := strings.Join(types, ","); got != "thinking,text,text,result" {
+		t.Errorf("chunk types = %s", got)
+	}
+}
+
+func TestExecute_Errors(t *testing.T) {
+	server := newFakeServer(t,
+		func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"error":"model is loading"}`, http.StatusServiceUnavailable)
+		},
+		jsonReply(`{"message":{"content":"done"},"done":true}`),
+	)
+	resp, err := server.client(t).Execute(context.Background(), llm.NewRequest("Review"))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Content != "done" || len(server.requests) != 2 {
+		t.Errorf("Content = %q after %d requests, want done after 2", resp.Content, len(server.requests))
+	}
+
+	// Unknown models are not retried
+	server = newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"model \"qwen-test\" not found, try pulling it first"}`, http.StatusNotFound)
+	})
+	_, err = server.client(t).Execute(context.Background(), llm.NewRequest("Review"))
+	if err == nil || !strings.Contains(err.Error(), "try pulling it first") {
+		t.Errorf("Execute() error = %v, want the API error message", err)
+	}
+	if llm.IsRetryable(err) || len(server.requests) != 1 {
+		t.Errorf("404 should fail without retry, got %d requests", len(server.requests))
+	}
+}
@@ -0,0 +1,60 @@
+package ollama
+
+import (
+	"encoding/json"
+)
+
+// chatRequest is the body of an /api/chat request
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Tools    []toolSpec    `json:"tools,omitempty"`
+	Format   interface{}   `json:"format,omitempty"`
+	Think    bool          `json:"think,omitempty"`
+	Stream   bool          `json:"stream"`
+}
+
+// chatMessage is a message of the conversation
+type chatMessage struct {
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	Thinking  string     `json:"thinking,omitempty"`
+	ToolCalls []toolCall `json:"tool_calls,omitempty"`
+	ToolName  string     `json:"tool_name,omitempty"`
+}
+
+// toolCall is a function call requested by the model
+type toolCall struct {
+	Function functionCall `json:"function"`
+}
+
+// functionCall is the function name and arguments of a tool call.
+// Unlike OpenAI, arguments are a JSON object rather than a string.
+type functionCall struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// toolSpec declares a tool the model may call
+type toolSpec struct {
+	Type     string       `json:"type"`
+	Function functionSpec `json:"function"`
+}
+
+// functionSpec describes a function tool
+type functionSpec struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// chatResponse is a non-streaming response, or one line of a streaming response
+type chatResponse struct {
+	Model           string      `json:"model"`
+	Message         chatMessage `json:"message"`
+	Done            bool        `json:"done"`
+	DoneReason      string      `json:"done_reason"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+	EvalCount       int         `json:"eval_count"`
+	Error           string      `json:"error"`
+}
@@ -0,0 +1,365 @@
+// Package openai implements the LLM Client interface for OpenAI-compatible
+// chat completions endpoints (OpenAI, model gateways, vLLM, LiteLLM, etc.).
+// Unlike the CLI clients, it talks HTTP directly and executes the tool calls
+// of the model itself (see llm.RunToolLoop).
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/verustcode/verustcode/internal/llm"
+)
+
+// ClientName is the identifier for the OpenAI-compatible client
+const ClientName = "openai"
+
+// DefaultBaseURL is the endpoint used when no base URL is configured
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// apiKeyEnv is the environment variable read when no API key is configured
+const apiKeyEnv = "OPENAI_API_KEY"
+
+// MaxToolRounds is the maximum number of tool-calling round trips per execution
+const MaxToolRounds = llm.DefaultMaxToolRounds
+
+func init() {
+	// Register the OpenAI-compatible client factory
+	llm.Register(ClientName, NewClient)
+}
+
+// Client implements the llm.Client interface for OpenAI-compatible endpoints
+type Client struct {
+	*llm.BaseClient
+	httpClient *http.Client
+	sessions   *llm.SessionStore
+}
+
+// NewClient creates a new OpenAI-compatible client
+func NewClient(config *llm.ClientConfig) (llm.Client, error) {
+	if config == nil {
+		config = llm.NewClientConfig(ClientName)
+	}
+
+	return &Client{
+		BaseClient: llm.NewBaseClient(config),
+		// Requests are bounded by the context timeout of each execution
+		httpClient: &http.Client{},
+		sessions:   llm.NewSessionStore(),
+	}, nil
+}
+
+// Available checks if the client has credentials or a custom endpoint
+func (c *Client) Available() bool {
+	return c.apiKey(nil) != "" || c.baseURL() != DefaultBaseURL
+}
+
+// Execute performs a synchronous execution and returns the complete response
+func (c *Client) Execute(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	return c.ExecuteStream(ctx, req, nil)
+}
+
+// ExecuteStream performs a streaming execution with callback.
+// A nil callback performs a non-streaming execution.
+func (c *Client) ExecuteStream(ctx context.Context, req *llm.Request, callback llm.StreamCallback) (*llm.Response, error) {
+	startTime := time.Now()
+	operation := "execute"
+	if callback != nil {
+		operation = "execute_stream"
+	}
+	c.LogRequest(req, operation)
+
+	// Prepare the request
+	prepared, err := c.PrepareRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.ExecuteWithFallback(ctx, prepared, func(ctx context.Context, req *llm.Request, model string) (*llm.Response, error) {
+		return c.run(ctx, req, model, callback)
+	})
+
+	c.LogResponse(resp, time.Since(startTime), err)
+	return resp, err
+}
+
+// CreateSession creates a new conversation session.
+// The conversation history is kept in memory until the client is closed.
+func (c *Client) CreateSession(ctx context.Context) (string, error) {
+	return c.sessions.Create(ClientName), nil
+}
+
+// Close releases the conversation history of all sessions
+func (c *Client) Close() error {
+	c.sessions.Clear()
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// run sends the prompt and executes the tool calls of the model until it answers.
+// Responses are streamed to callback if it is not nil.
+func (c *Client) run(ctx context.Context, req *llm.Request, model string, callback llm.StreamCallback) (*llm.Response, error) {
+	timeout := c.GetConfig().GetTimeout(req)
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := c.buildRequest(req, model)
+	if err != nil {
+		return nil, err
+	}
+
+	turn := func(ctx context.Context, messages []llm.Message, final bool) (*llm.Turn, error) {
+		body.Messages = toChatMessages(messages)
+		body.ToolChoice = ""
+		if final && len(body.Tools) > 0 {
+			body.ToolChoice = "none"
+		}
+		var answer *llm.Turn
+		err := c.DoWithRetry(ctx, req, func() error {
+			var err error
+			answer, err = c.complete(ctx, req, body, callback)
+			return err
+		})
+		return answer, err
+	}
+
+	messages := append(c.sessions.History(req.SessionID), llm.Message{Role: llm.RoleUser, Content: req.Prompt})
+	result, err := llm.RunToolLoop(execCtx, messages, req.Tools, MaxToolRounds, turn, callback)
+	if err != nil {
+		if execCtx.Err() == context.DeadlineExceeded {
+			return nil, llm.NewClientError(ClientName, "execute", "execution timeout", llm.ErrTimeout)
+		}
+		return nil, err
+	}
+
+	c.sessions.Save(req.SessionID, result.Messages)
+	return c.BuildToolLoopResponse(req, model, result, callback), nil
+}
+
+// buildRequest builds the request body shared by all rounds of an execution
+func (c *Client) buildRequest(req *llm.Request, model string) (*chatRequest, error) {
+	body := &chatRequest{Model: model}
+
+	if req.ResponseSchema != nil && req.ResponseSchema.Schema != nil {
+		schema, err := req.ResponseSchema.JSONSchema()
+		if err != nil {
+			return nil, llm.NewClientError(ClientName, "execute", "invalid response schema", err)
+		}
+		name := req.ResponseSchema.Name
+		if name == "" {
+			name = "response"
+		}
+		body.ResponseFormat = &responseFormat{
+			Type: "json_schema",
+			JSONSchema: &jsonSchemaFormat{
+				Name:        name,
+				Description: req.ResponseSchema.Description,
+				Schema:      schema,
+				Strict:      req.ResponseSchema.Strict,
+			},
+		}
+	}
+
+	for _, tool := range req.Tools {
+		body.Tools = append(body.Tools, toolSpec{
+			Type: "function",
+			Function: functionSpec{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+	return body, nil
+}
+
+// complete sends one chat completion request
+func (c *Client) complete(ctx context.Context, req *llm.Request, body *chatRequest, callback llm.StreamCallback) (*llm.Turn, error) {
+	body.Stream = callback != nil
+	body.StreamOptions = nil
+	headers := map[string]string{}
+	if body.Stream {
+		body.StreamOptions = &streamOptions{IncludeUsage: true}
+		headers["Accept"] = "text/event-stream"
+	}
+	if key := c.apiKey(req); key != "" {
+		headers["Authorization"] = "Bearer " + key
+	}
+
+	c.Logger().Debug("Sending chat completion request",
+		zap.String("model", body.Model),
+		zap.Int("messages", len(body.Messages)),
+		zap.Int("tools", len(body.Tools)),
+		zap.Bool("stream", body.Stream),
+	)
+
+	httpResp, err := llm.PostJSON(ctx, c.httpClient, ClientName, c.baseURL()+"/chat/completions", headers, body)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if body.Stream {
+		return c.readStream(httpResp.Body, callback)
+	}
+	return readCompletion(httpResp.Body)
+}
+
+// readCompletion decodes a non-streaming chat completion response
+func readCompletion(r io.Reader) (*llm.Turn, error) {
+	var resp chatResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, llm.NewClientError(ClientName, "execute", "failed to decode response", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, llm.NewClientError(ClientName, "execute", "response has no choices", llm.ErrInvalidResponse)
+	}
+	return &llm.Turn{Message: fromChatMessage(resp.Choices[0].Message), Usage: toUsage(resp.Usage)}, nil
+}
+
+// readStream reads a server-sent events response, forwarding deltas to callback
+func (c *Client) readStream(r io.Reader, callback llm.StreamCallback) (*llm.Turn, error) {
+	var text, thinking strings.Builder
+	calls := make(map[int]*toolCall)
+	turn := &llm.Turn{}
+
+	err := llm.ReadSSE(r, func(event, data string) (bool, error) {
+		var chunk streamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			c.Logger().Debug("Failed to parse stream chunk", zap.String("data", data), zap.Error(err))
+			return false, nil
+		}
+		if chunk.Error != nil {
+			return true, llm.NewClientError(ClientName, "execute_stream", chunk.Error.Message, llm.ErrInvalidResponse)
+		}
+		if chunk.Usage != nil {
+			turn.Usage = toUsage(chunk.Usage)
+		}
+		if len(chunk.Choices) == 0 {
+			return false, nil
+		}
+
+		delta := chunk.Choices[0].Delta
+		if delta.ReasoningContent != "" {
+			thinking.WriteString(delta.ReasoningContent)
+			callback(&llm.StreamChunk{
+				Type:    llm.ChunkTypeThinking,
+				Content: thinking.String(),
+				Delta:   delta.ReasoningContent,
+			})
+		}
+		if delta.Content != "" {
+			text.WriteString(delta.Content)
+			callback(&llm.StreamChunk{
+				Type:    llm.ChunkTypeText,
+				Content: text.String(),
+				Delta:   delta.Content,
+			})
+		}
+		// Tool calls arrive in fragments identified by their index
+		for _, fragment := range delta.ToolCalls {
+			call, ok := calls[fragment.Index]
+			if !ok {
+				call = &toolCall{Type: "function"}
+				calls[fragment.Index] = call
+			}
+			if fragment.ID != "" {
+				call.ID = fragment.ID
+			}
+			call.Function.Name += fragment.Function.Name
+			call.Function.Arguments += fragment.Function.Arguments
+		}
+		return false, nil
+	})
+	if err != nil {
+		var clientErr *llm.ClientError
+		if errors.As(err, &clientErr) {
+			return nil, err
+		}
+		return nil, llm.NewClientError(ClientName, "execute_stream", "failed to read stream", err)
+	}
+
+	indexes := make([]int, 0, len(calls))
+	for index := range calls {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	message := chatMessage{Role: "assistant", Content: text.String()}
+	for _, index := range indexes {
+		message.ToolCalls = append(message.ToolCalls, *calls[index])
+	}
+	turn.Message = fromChatMessage(message)
+	return turn, nil
+}
+
+// toChatMessages converts a conversation to chat completions messages
+func toChatMessages(messages []llm.Message) []chatMessage {
+	result := make([]chatMessage, 0, len(messages))
+	for _, m := range messages {
+		message := chatMessage{Role: m.Role, Content: m.Content, ToolCallID: m.ToolCallID}
+		for _, call := range m.ToolCalls {
+			message.ToolCalls = append(message.ToolCalls, toolCall{
+				ID:       call.ID,
+				Type:     "function",
+				Function: functionCall{Name: call.Name, Arguments: call.Arguments},
+			})
+		}
+		result = append(result, message)
+	}
+	return result
+}
+
+// fromChatMessage converts an assistant message of a response
+func fromChatMessage(m chatMessage) llm.Message {
+	message := llm.Message{Role: llm.RoleAssistant, Content: m.Content}
+	for _, call := range m.ToolCalls {
+		message.ToolCalls = append(message.ToolCalls, llm.ToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		})
+	}
+	return message
+}
+
+// toUsage converts the token usage of a response
+func toUsage(u *usage) *llm.Usage {
+	if u == nil {
+		return nil
+	}
+	result := &llm.Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+	if u.PromptTokensDetails != nil {
+		result.CachedTokens = u.PromptTokensDetails.CachedTokens
+	}
+	return result
+}
+
+// baseURL returns the configured endpoint without trailing slash
+func (c *Client) baseURL() string {
+	if url := c.GetConfig().BaseURL; url != "" {
+		return strings.TrimRight(url, "/")
+	}
+	return DefaultBaseURL
+}
+
+// apiKey returns the API key of req or the configured API key, or the OPENAI_API_KEY environment variable
+func (c *Client) apiKey(req *llm.Request) string {
+	if key := c.GetConfig().GetAPIKey(req); key != "" {
+		return key
+	}
+	return os.Getenv(apiKeyEnv)
+}
@@ -0,0 +1,273 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/verustcode/verustcode/internal/llm"
+)
+
+// fakeServer is an httptest stand-in for a chat completions endpoint.
+// Each request is answered by the next handler; the decoded bodies are recorded.
+type fakeServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	handlers []http.HandlerFunc
+	requests []chatRequest
+}
+
+func newFakeServer(t *testing.T, handlers ...http.HandlerFunc) *fakeServer {
+	s := &fakeServer{handlers: handlers}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		var body chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		s.mu.Lock()
+		index := len(s.requests)
+		s.requests = append(s.requests, body)
+		s.mu.Unlock()
+		if index >= len(s.handlers) {
+			t.Errorf("unexpected request %d", index+1)
+			http.Error(w, "unexpected request", http.StatusInternalServerError)
+			return
+		}
+		s.handlers[index](w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeServer) client(t *testing.T) llm.Client {
+	config := llm.NewClientConfig(ClientName).
+		WithBaseURL(s.URL + "/v1/").
+		WithAPIKey("sk-test").
+		WithDefaultModel("gpt-test").
+		WithRetryDelay(time.Millisecond)
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+func jsonReply(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}
+}
+
+func TestClientRegistration(t *testing.T) {
+	if !llm.IsRegistered(ClientName) {
+		t.Fatalf("OpenAI client is not registered")
+	}
+	client, err := llm.Create(ClientName, llm.NewClientConfig(ClientName).WithBaseURL("http://localhost:8000/v1"))
+	if err != nil {
+		t.Fatalf("Failed to create OpenAI client via factory: %v", err)
+	}
+	if !client.Available() {
+		t.Error("client with a custom endpoint should be available")
+	}
+}
+
+func TestExecute_StructuredOutput(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer sk-test" {
+			t.Errorf("Authorization = %q", got)
+		}
+		jsonReply(`{"choices":[{"message":{"role":"assistant","content":"{\"summary\":\"ok\"}"}}],
+			"usage":{"prompt_tokens":12,"completion_tokens":5,"total_tokens":17}}`)(w, r)
+	})
+
+	schema := &llm.ResponseSchema{
+		Name: "review",
+		Schema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"summary": map[string]interface{}{"type": "string"}},
+		},
+		Strict: true,
+	}
+	resp, err := server.client(t).Execute(context.Background(), llm.NewRequest("Review").WithSchema(schema))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	body := server.requests[0]
+	if body.Model != "gpt-test" || body.Stream {
+		t.Errorf("request = %+v, want non-streaming gpt-test", body)
+	}
+	if body.ResponseFormat == nil || body.ResponseFormat.Type != "json_schema" ||
+		body.ResponseFormat.JSONSchema.Name != "review" || !body.ResponseFormat.JSONSchema.Strict {
+		t.Errorf("response_format = %+v, want strict json_schema named review", body.ResponseFormat)
+	}
+
+	parsed, ok := resp.Parsed.(*map[string]interface{})
+	if !ok || (*parsed)["summary"] != "ok" {
+		t.Errorf("Parsed = %#v, ParseErr = %v", resp.Parsed, resp.ParseErr)
+	}
+	if resp.Usage == nil || resp.Usage.PromptTokens != 12 || resp.Usage.CompletionTokens != 5 || resp.Usage.TotalTokens != 17 {
+		t.Errorf("Usage = %+v", resp.Usage)
+	}
+}
+
+func TestExecute_ToolCalls(t *testing.T) {
+	server := newFakeServer(t,
+		jsonReply(`{"choices":[{"message":{"role":"assistant","content":"","tool_calls":[
+			{"id":"call_1","type":"function","function":{"name":"read_file","arguments":"{\"path\":\"main.go\"}"}},
+			{"id":"call_2","type":"function","function":{"name":"delete_repo","arguments":"{}"}}]}}],
+			"usage":{"prompt_tokens":10,"completion_tokens":3,"total_tokens":13}}`),
+		jsonReply(`{"choices":[{"message":{"role":"assistant","content":"No issues in main.go"}}],
+			"usage":{"prompt_tokens":30,"completion_tokens":6,"total_tokens":36,"prompt_tokens_details":{"cached_tokens":8}}}`),
+	)
+
+	var gotArgs string
+	tools := []*llm.Tool{{
+		Name:        "read_file",
+		Description: "Read a file",
+		Parameters:  map[string]interface{}{"type": "object"},
+		Handler: func(ctx context.Context, arguments string) (string, error) {
+			gotArgs = arguments
+			return "package main", nil
+		},
+	}}
+
+	resp, err := server.client(t).Execute(context.Background(), llm.NewRequest("Review").WithTools(tools))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Content != "No issues in main.go" {
+		t.Errorf("Content = %q", resp.Content)
+	}
+	if gotArgs != `{"path":"main.go"}` {
+		t.Errorf("tool arguments = %q", gotArgs)
+	}
+	if resp.Usage.TotalTokens != 49 || resp.Usage.CachedTokens != 8 || resp.Metadata["tool_calls"] != "2" {
+		t.Errorf("Usage = %+v, Metadata = %v", resp.Usage, resp.Metadata)
+	}
+
+	if len(server.requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(server.requests))
+	}
+	if tools := server.requests[0].Tools; len(tools) != 1 || tools[0].Function.Name != "read_file" {
+		t.Errorf("tools = %+v", tools)
+	}
+	messages := server.requests[1].Messages
+	if len(messages) != 4 {
+		t.Fatalf("second request has %d messages, want user, assistant and two tool results", len(messages))
+	}
+	if messages[2].Role != "tool" || messages[2].ToolCallID != "call_1" || messages[2].Content != "package main" {
+		t.Errorf("tool result = %+v", messages[2])
+	}
+	if !strings.Contains(messages[3].Content, "unknown tool") {
+		t.Errorf("unknown tool result = %+v", messages[3])
+	}
+}
+
+func TestExecuteStream(t *testing.T) {
+	events := []string{
+		`{"choices":[{"delta":{"reasoning_content":"Checking"}}]}`,
+		`{"choices":[{"delta":{"content":"Looks "}}]}`,
+		`{"choices":[{"delta":{"content":"good"}}]}`,
+		`{"choices":[],"usage":{"prompt_tokens":8,"completion_tokens":2,"total_tokens":10}}`,
+	}
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		for _, event := range events {
+			fmt.Fprintf(w, "data: %s\n\n", event)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+
+	var chunks []*llm.StreamChunk
+	resp, err := server.client(t).ExecuteStream(context.Background(), llm.NewRequest("Review"), func(chunk *llm.StreamChunk) {
+		chunks = append(chunks, chunk)
+	})
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+
+	body := server.requests[0]
+	if !body.Stream || body.StreamOptions == nil || !body.StreamOptions.IncludeUsage {
+		t.Errorf("request = %+v, want streaming with usage", body)
+	}
+	if resp.Content != "Looks good" || resp.Usage.TotalTokens != 10 {
+		t.Errorf("Content = %q, Usage = %+v", resp.Content, resp.Usage)
+	}
+
+	var types []string
+	for _, chunk := range chunks {
+		types = append(types, string(chunk.Type))
+	}
+	if got := strings.Join(types, ","); got != "thinking,text,text,result" {
+		t.Errorf("chunk types = %s", got)
+	}
+	if chunks[2].Content != "Looks good" || chunks[2].Delta != "good" || !chunks[3].IsComplete {
+		t.Errorf("chunks = %+v", chunks)
+	}
+}
+
+func TestExecute_Retries(t *testing.T) {
+	server := newFakeServer(t,
+		func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"error":{"message":"rate limited"}}`, http.StatusTooManyRequests)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "upstream unavailable", http.StatusBadGateway)
+		},
+		jsonReply(`{"choices":[{"message":{"content":"done"}}]}`),
+	)
+	resp, err := server.client(t).Execute(context.Background(), llm.NewRequest("Review"))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Content != "done" || len(server.requests) != 3 {
+		t.Errorf("Content = %q after %d requests, want done after 3", resp.Content, len(server.requests))
+	}
+
+	// Client errors are not retried
+	server = newFakeServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"invalid api key"}}`, http.StatusUnauthorized)
+	})
+	_, err = server.client(t).Execute(context.Background(), llm.NewRequest("Review"))
+	if err == nil || !strings.Contains(err.Error(), "invalid api key") {
+		t.Errorf("Execute() error = %v, want the API error message", err)
+	}
+	if llm.IsRetryable(err) || len(server.requests) != 1 {
+		t.Errorf("401 should fail without retry, got %d requests", len(server.requests))
+	}
+}
+
+func TestSession(t *testing.T) {
+	server := newFakeServer(t,
+		jsonReply(`{"choices":[{"message":{"content":"first"}}]}`),
+		jsonReply(`{"choices":[{"message":{"content":"second"}}]}`),
+	)
+	client := server.client(t)
+	sessionID, err := client.CreateSession(context.Background())
+	if err != nil || sessionID == "" {
+		t.Fatalf("CreateSession() = %q, %v", sessionID, err)
+	}
+
+	for _, prompt := range []string{"one", "two"} {
+		if _, err := client.Execute(context.Background(), llm.NewRequest(prompt).WithSessionID(sessionID)); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+	messages := server.requests[1].Messages
+	if len(messages) != 3 || messages[1].Content != "first" {
+		t.Errorf("second request messages = %+v, want the session history", messages)
+	}
+}
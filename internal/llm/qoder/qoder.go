@@ -147,6 +147,7 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 
 	// Append extra arguments from config
 	config := c.GetConfig()
+	apiKey := config.GetAPIKey(req)
 	if config.ExtraArgs != "" {
 		extraArgs := strings.Fields(config.ExtraArgs)
 		args = append(args, extraArgs...)
@@ -154,10 +155,10 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 
 	// Create command
 	cmd := exec.CommandContext(execCtx, c.cliPath, args...)
-	c.setupCommandEnv(cmd, req.WorkDir, config.APIKey)
+	c.setupCommandEnv(cmd, req.WorkDir, apiKey)
 
 	// Log full command info for debugging (with masked sensitive args)
-	apiKeyMasked := maskAPIKey(config.APIKey)
+	apiKeyMasked := maskAPIKey(apiKey)
 	maskedArgs := maskSensitiveArgs(args)
 	cmdStr := c.cliPath + " " + strings.Join(maskedArgs, " ") + " < [stdin prompt]"
 	reviewID := req.GetMetadata("review_id")
@@ -171,7 +172,7 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 		zap.Strings("args", maskedArgs),
 		zap.String("extra_args", config.ExtraArgs),
 		zap.String("api_key", apiKeyMasked),
-		zap.Bool("has_api_key", config.APIKey != ""),
+		zap.Bool("has_api_key", apiKey != ""),
 	)
 
 	// Create stdin pipe for prompt input
@@ -185,8 +186,15 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	// Run the CLI in the sandbox if configured (violations are logged with the review ID)
+	releaseSandbox, err := llm.SandboxCommand(cmd, config.Sandbox, c.Logger(), zap.String("review_id", req.GetMetadata("review_id")))
+	if err != nil {
+		return nil, llm.NewClientError(ClientName, "execute", "failed to prepare sandbox", err)
+	}
+
 	// Start command
 	if err := cmd.Start(); err != nil {
+		releaseSandbox(err)
 		return nil, llm.NewClientError(ClientName, "execute", "failed to start qodercli", err)
 	}
 
@@ -206,7 +214,9 @@ func (c *Client) doExecute(ctx context.Context, req *llm.Request, model string)
 	}()
 
 	// Wait for command to finish
-	if err := cmd.Wait(); err != nil {
+	err = cmd.Wait()
+	releaseSandbox(err)
+	if err != nil {
 		// Check for timeout
 		if execCtx.Err() == context.DeadlineExceeded {
 			return nil, llm.NewClientError(ClientName, "execute", "execution timeout", llm.ErrTimeout)
@@ -289,6 +299,7 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 
 	// Append extra arguments from config
 	config := c.GetConfig()
+	apiKey := config.GetAPIKey(req)
 	if config.ExtraArgs != "" {
 		extraArgs := strings.Fields(config.ExtraArgs)
 		args = append(args, extraArgs...)
@@ -296,10 +307,10 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 
 	// Create command
 	cmd := exec.CommandContext(execCtx, c.cliPath, args...)
-	c.setupCommandEnv(cmd, req.WorkDir, config.APIKey)
+	c.setupCommandEnv(cmd, req.WorkDir, apiKey)
 
 	// Log full command info for debugging (with masked sensitive args)
-	apiKeyMasked := maskAPIKey(config.APIKey)
+	apiKeyMasked := maskAPIKey(apiKey)
 	maskedArgs := maskSensitiveArgs(args)
 	cmdStr := c.cliPath + " " + strings.Join(maskedArgs, " ") + " < [stdin prompt]"
 	c.Logger().Info("Executing qodercli command (streaming)",
@@ -311,7 +322,7 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 		zap.Strings("args", maskedArgs),
 		zap.String("extra_args", config.ExtraArgs),
 		zap.String("api_key", apiKeyMasked),
-		zap.Bool("has_api_key", config.APIKey != ""),
+		zap.Bool("has_api_key", apiKey != ""),
 	)
 
 	// Create stdin pipe for prompt input
@@ -331,8 +342,15 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 		return nil, llm.NewClientError(ClientName, "execute_stream", "failed to create stderr pipe", err)
 	}
 
+	// Run the CLI in the sandbox if configured (violations are logged with the review ID)
+	releaseSandbox, err := llm.SandboxCommand(cmd, config.Sandbox, c.Logger(), zap.String("review_id", req.GetMetadata("review_id")))
+	if err != nil {
+		return nil, llm.NewClientError(ClientName, "execute_stream", "failed to prepare sandbox", err)
+	}
+
 	// Start command
 	if err := cmd.Start(); err != nil {
+		releaseSandbox(err)
 		return nil, llm.NewClientError(ClientName, "execute_stream", "failed to start qodercli", err)
 	}
 
@@ -394,7 +412,9 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 		done <- true
 	}()
 
-	// Process chunks and call callback
+	// Process chunks and call callback.
+	// Token usage is reported by the result message.
+	var usage *llm.Usage
 	processingDone := make(chan bool)
 	go func() {
 		defer func() { processingDone <- true }()
@@ -406,6 +426,7 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 					// Result contains final content, use it directly
 					textBuffer.Reset()
 					textBuffer.WriteString(chunk.Delta)
+					usage = llm.ParseUsageJSON(chunk.Metadata["usage"])
 				} else {
 					textBuffer.WriteString(chunk.Delta)
 				}
@@ -428,6 +449,7 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 
 	// Wait for command to finish
 	err = cmd.Wait()
+	releaseSandbox(err)
 
 	// Get final session ID
 	sessionIDMu.Lock()
@@ -465,6 +487,7 @@ func (c *Client) doExecuteStream(ctx context.Context, req *llm.Request, model st
 
 	// Build response
 	resp := c.BuildResponse(finalContent, model, finalSessionID, req.ResponseSchema)
+	resp.Usage = usage
 
 	// Copy metadata from request to response (including rule_id)
 	if req.Options != nil && req.Options.Metadata != nil {
@@ -549,11 +572,15 @@ func (c *Client) parseStreamJSON(line string, sessionID *string, sessionIDMu *sy
 		}
 
 		// Extract usage stats if available
+		usage, _ := data["usage"].(map[string]interface{})
 		if message, ok := data["message"].(map[string]interface{}); ok {
-			if usage, ok := message["usage"].(map[string]interface{}); ok {
-				if usageJSON, err := json.Marshal(usage); err == nil {
-					chunk.Metadata["usage"] = string(usageJSON)
-				}
+			if messageUsage, ok := message["usage"].(map[string]interface{}); ok {
+				usage = messageUsage
+			}
+		}
+		if usage != nil {
+			if usageJSON, err := json.Marshal(usage); err == nil {
+				chunk.Metadata["usage"] = string(usageJSON)
 			}
 		}
 
@@ -1,6 +1,7 @@
 package qoder
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/verustcode/verustcode/internal/llm"
@@ -317,3 +318,27 @@ func TestExtractTextFromMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestParseStreamJSON_ResultUsage(t *testing.T) {
+	client := &Client{BaseClient: llm.NewBaseClient(llm.NewClientConfig(ClientName))}
+
+	var sessionID string
+	var sessionIDMu, resultMu sync.Mutex
+	resultReceived := false
+
+	line := `{"type":"result","subtype":"success","session_id":"s-1","done":true,` +
+		`"message":{"content":[{"type":"text","text":"done"}],` +
+		`"usage":{"input_tokens":10,"output_tokens":4,"cache_read_input_tokens":90}}}`
+	chunk := client.parseStreamJSON(line, &sessionID, &sessionIDMu, &resultReceived, &resultMu)
+	if chunk == nil || chunk.Type != llm.ChunkTypeResult || !resultReceived {
+		t.Fatalf("chunk = %+v, want a result chunk", chunk)
+	}
+	if sessionID != "s-1" || chunk.Content != "done" {
+		t.Errorf("session ID = %q, content = %q", sessionID, chunk.Content)
+	}
+
+	usage := llm.ParseUsageJSON(chunk.Metadata["usage"])
+	if usage == nil || usage.PromptTokens != 100 || usage.CompletionTokens != 4 || usage.CachedTokens != 90 {
+		t.Errorf("usage = %+v", usage)
+	}
+}
@@ -0,0 +1,49 @@
+package llm
+
+import (
+	"context"
+)
+
+// streamRecorderKey is the context key of the stream recorder
+type streamRecorderKey struct{}
+
+// WithStreamRecorder returns a copy of ctx whose client executions report every stream chunk
+// to recorder, in addition to the caller's callback. Clients created with Create honor it;
+// a non-streaming Execute is reported as a single result chunk.
+func WithStreamRecorder(ctx context.Context, recorder StreamCallback) context.Context {
+	return context.WithValue(ctx, streamRecorderKey{}, recorder)
+}
+
+// StreamRecorder returns the stream recorder of ctx, or nil if there is none
+func StreamRecorder(ctx context.Context) StreamCallback {
+	recorder, _ := ctx.Value(streamRecorderKey{}).(StreamCallback)
+	return recorder
+}
+
+// recordingClient reports the stream chunks of executions to the context's stream recorder
+type recordingClient struct {
+	Client
+}
+
+// Execute reports the response as a single result chunk to the context's stream recorder
+func (c *recordingClient) Execute(ctx context.Context, req *Request) (*Response, error) {
+	resp, err := c.Client.Execute(ctx, req)
+	if recorder := StreamRecorder(ctx); recorder != nil && resp != nil {
+		recorder(&StreamChunk{Type: ChunkTypeResult, Content: resp.Content, IsComplete: true})
+	}
+	return resp, err
+}
+
+// ExecuteStream reports every chunk to the context's stream recorder and then to callback
+func (c *recordingClient) ExecuteStream(ctx context.Context, req *Request, callback StreamCallback) (*Response, error) {
+	recorder := StreamRecorder(ctx)
+	if recorder == nil {
+		return c.Client.ExecuteStream(ctx, req, callback)
+	}
+	return c.Client.ExecuteStream(ctx, req, func(chunk *StreamChunk) {
+		recorder(chunk)
+		if callback != nil {
+			callback(chunk)
+		}
+	})
+}
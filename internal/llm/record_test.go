@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamingClient streams a single text chunk
+type streamingClient struct {
+	*BaseClient
+	streamed bool
+}
+
+func (c *streamingClient) Available() bool { return true }
+
+func (c *streamingClient) Execute(ctx context.Context, req *Request) (*Response, error) {
+	return &Response{Content: "answer"}, nil
+}
+
+func (c *streamingClient) ExecuteStream(ctx context.Context, req *Request, callback StreamCallback) (*Response, error) {
+	c.streamed = true
+	if callback != nil {
+		callback(&StreamChunk{Type: ChunkTypeText, Content: "answer", Delta: "answer"})
+	}
+	return &Response{Content: "answer"}, nil
+}
+
+func (c *streamingClient) CreateSession(ctx context.Context) (string, error) { return "", nil }
+func (c *streamingClient) Close() error                                      { return nil }
+
+func TestRecordingClient(t *testing.T) {
+	inner := &streamingClient{BaseClient: NewBaseClient(NewClientConfig("streaming"))}
+	client := &recordingClient{Client: inner}
+
+	// Without recorder, executions are passed through
+	_, err := client.Execute(context.Background(), NewRequest("prompt"))
+	require.NoError(t, err)
+	assert.False(t, inner.streamed)
+
+	var recorded, received []*StreamChunk
+	ctx := WithStreamRecorder(context.Background(), func(chunk *StreamChunk) {
+		recorded = append(recorded, chunk)
+	})
+
+	_, err = client.Execute(ctx, NewRequest("prompt"))
+	require.NoError(t, err)
+	assert.False(t, inner.streamed, "Execute is not switched to a streaming execution while recording")
+	require.Len(t, recorded, 1)
+	assert.Equal(t, ChunkTypeResult, recorded[0].Type)
+	assert.Equal(t, "answer", recorded[0].Content)
+	assert.True(t, recorded[0].IsComplete)
+
+	_, err = client.ExecuteStream(ctx, NewRequest("prompt"), func(chunk *StreamChunk) {
+		received = append(received, chunk)
+	})
+	require.NoError(t, err)
+	assert.Len(t, recorded, 2)
+	assert.Len(t, received, 1, "the caller's callback still receives the chunks")
+}
@@ -0,0 +1,174 @@
+// Package replay implements an LLM Client that serves the recorded interactions of a cassette.
+// Each rule's requests are answered with the rule's recorded interactions in recording order,
+// so a review replays deterministically without calling any agent. Prompts that differ from
+// the recorded ones are still answered, and reported as mismatches.
+package replay
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/verustcode/verustcode/internal/llm"
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+// ClientName is the identifier for the Replay client
+const ClientName = "replay"
+
+// Mismatch is a request whose prompt differs from the recorded prompt it was answered with
+type Mismatch struct {
+	// RuleID is the rule of the request
+	RuleID string `json:"rule_id"`
+
+	// Index is the position of the interaction among the rule's interactions (0-based)
+	Index int `json:"index"`
+
+	// Recorded is the recorded prompt; Actual is the prompt of the request
+	Recorded string `json:"recorded"`
+	Actual   string `json:"actual"`
+}
+
+// Client implements the llm.Client interface by serving recorded interactions
+type Client struct {
+	*llm.BaseClient
+
+	mu         sync.Mutex
+	byRule     map[string][]*model.CassetteInteraction
+	served     map[string]int
+	mismatches []Mismatch
+}
+
+// NewClient creates a Replay client serving the interactions of cassette
+func NewClient(cassette *model.Cassette) *Client {
+	c := &Client{
+		BaseClient: llm.NewBaseClient(llm.NewClientConfig(ClientName)),
+		byRule:     make(map[string][]*model.CassetteInteraction),
+		served:     make(map[string]int),
+	}
+	for i := range cassette.Interactions {
+		interaction := &cassette.Interactions[i]
+		c.byRule[interaction.RuleID] = append(c.byRule[interaction.RuleID], interaction)
+	}
+	return c
+}
+
+// Available always returns true for replay client
+func (c *Client) Available() bool {
+	return true
+}
+
+// Mismatches returns the requests whose prompt differs from the recorded prompt, in request order
+func (c *Client) Mismatches() []Mismatch {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Mismatch(nil), c.mismatches...)
+}
+
+// Unserved returns the number of recorded interactions no request was answered with, by rule
+func (c *Client) Unserved() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	unserved := make(map[string]int)
+	for ruleID, interactions := range c.byRule {
+		if n := len(interactions) - c.served[ruleID]; n > 0 {
+			unserved[ruleID] = n
+		}
+	}
+	return unserved
+}
+
+// Execute answers the request with the rule's next recorded interaction
+func (c *Client) Execute(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	return c.ExecuteStream(ctx, req, nil)
+}
+
+// ExecuteStream answers the request with the rule's next recorded interaction,
+// sending its recorded chunks to callback (if not nil)
+func (c *Client) ExecuteStream(ctx context.Context, req *llm.Request, callback llm.StreamCallback) (*llm.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	interaction, err := c.next(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if callback != nil {
+		for _, chunk := range interaction.Chunks {
+			callback(&llm.StreamChunk{
+				Type:       llm.ChunkType(chunk.Type),
+				Content:    chunk.Content,
+				Delta:      chunk.Delta,
+				IsComplete: chunk.IsComplete,
+				ToolName:   chunk.ToolName,
+				ToolInput:  chunk.ToolInput,
+				ToolOutput: chunk.ToolOutput,
+				Metadata:   chunk.Metadata,
+			})
+		}
+	}
+
+	if interaction.Error != "" {
+		if interaction.Retryable {
+			return nil, llm.NewRetryableError(ClientName, "execute", interaction.Error, nil)
+		}
+		return nil, llm.NewClientError(ClientName, "execute", interaction.Error, nil)
+	}
+
+	resp := &llm.Response{
+		Content:   interaction.Content,
+		SessionID: interaction.SessionID,
+		Model:     interaction.Model,
+		Metadata:  map[string]string{"rule_id": interaction.RuleID},
+	}
+	if usage := interaction.TokenUsage; usage.TotalTokens() > 0 {
+		resp.Usage = &llm.Usage{
+			PromptTokens:     usage.PromptTokens,
+			CompletionTokens: usage.CompletionTokens,
+			CachedTokens:     usage.CachedTokens,
+			TotalTokens:      usage.TotalTokens(),
+		}
+	}
+	return resp, nil
+}
+
+// next returns the rule's next recorded interaction and records a mismatch if its prompt differs
+func (c *Client) next(req *llm.Request) (*model.CassetteInteraction, error) {
+	ruleID := req.GetMetadata("rule_id")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i := c.served[ruleID]
+	interactions := c.byRule[ruleID]
+	if i >= len(interactions) {
+		return nil, llm.NewClientError(ClientName, "execute",
+			fmt.Sprintf("no recorded interaction left for rule %s (%d recorded)", ruleID, len(interactions)), nil)
+	}
+	c.served[ruleID]++
+
+	interaction := interactions[i]
+	if interaction.Prompt != req.Prompt {
+		c.mismatches = append(c.mismatches, Mismatch{
+			RuleID:   ruleID,
+			Index:    i,
+			Recorded: interaction.Prompt,
+			Actual:   req.Prompt,
+		})
+	}
+	return interaction, nil
+}
+
+// CreateSession is not supported: recorded conversations are continued through
+// the session IDs of recorded responses
+func (c *Client) CreateSession(ctx context.Context) (string, error) {
+	return "", llm.NewClientError(ClientName, "create_session", "sessions are not supported when replaying", nil)
+}
+
+// Close releases any resources held by the client
+func (c *Client) Close() error {
+	return nil
+}
@@ -0,0 +1,76 @@
+package replay
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/verustcode/verustcode/internal/llm"
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+func request(ruleID, prompt string) *llm.Request {
+	return llm.NewRequest(prompt).WithOptions(&llm.RequestOptions{Metadata: map[string]string{"rule_id": ruleID}})
+}
+
+func testCassette() *model.Cassette {
+	return &model.Cassette{
+		Version: model.CassetteVersion,
+		Interactions: []model.CassetteInteraction{
+			{RuleID: "security", Prompt: "p1", Error: "rate limited", Retryable: true},
+			{RuleID: "style", Prompt: "s1", Content: "style answer"},
+			{
+				RuleID:     "security",
+				Prompt:     "p1",
+				Content:    "security answer",
+				Model:      "sonnet",
+				TokenUsage: model.TokenUsage{PromptTokens: 10, CompletionTokens: 5},
+				Chunks: model.CassetteChunks{
+					{Type: string(llm.ChunkTypeToolCall), ToolName: "read_file", ToolInput: `{"path":"a.go"}`},
+					{Type: string(llm.ChunkTypeResult), Content: "security answer", IsComplete: true},
+				},
+			},
+		},
+	}
+}
+
+func TestClient_ServesRuleInteractionsInOrder(t *testing.T) {
+	client := NewClient(testCassette())
+
+	_, err := client.Execute(context.Background(), request("security", "p1"))
+	require.Error(t, err)
+	assert.True(t, llm.IsRetryable(err), "recorded retryable errors are replayed as retryable")
+
+	var chunks []*llm.StreamChunk
+	resp, err := client.ExecuteStream(context.Background(), request("security", "p1"), func(chunk *llm.StreamChunk) {
+		chunks = append(chunks, chunk)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "security answer", resp.Content)
+	assert.Equal(t, "sonnet", resp.Model)
+	require.NotNil(t, resp.Usage)
+	assert.Equal(t, 15, resp.Usage.TotalTokens)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, llm.ChunkTypeToolCall, chunks[0].Type)
+	assert.Equal(t, "read_file", chunks[0].ToolName)
+
+	_, err = client.Execute(context.Background(), request("security", "p1"))
+	assert.ErrorContains(t, err, "no recorded interaction left for rule security")
+
+	assert.Empty(t, client.Mismatches())
+	assert.Equal(t, map[string]int{"style": 1}, client.Unserved())
+}
+
+func TestClient_ReportsMismatches(t *testing.T) {
+	client := NewClient(testCassette())
+
+	resp, err := client.Execute(context.Background(), request("style", "changed prompt"))
+	require.NoError(t, err)
+	assert.Equal(t, "style answer", resp.Content, "changed prompts are still answered")
+
+	mismatches := client.Mismatches()
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, Mismatch{RuleID: "style", Index: 0, Recorded: "s1", Actual: "changed prompt"}, mismatches[0])
+}
@@ -20,11 +20,17 @@ type Request struct {
 	// WorkDir is the working directory for the CLI tool
 	WorkDir string
 
+	// APIKey overrides the API key of the client configuration for this request (optional)
+	APIKey string
+
 	// ResponseSchema defines the expected response structure (optional)
 	// When provided, the client will add JSON format instructions to the prompt
 	// and attempt to parse the response into the specified structure
 	ResponseSchema *ResponseSchema
 
+	// Tools are functions the model may call while answering (API clients only)
+	Tools []*Tool
+
 	// Options contains optional configuration
 	Options *RequestOptions
 }
@@ -95,8 +101,31 @@ type Usage struct {
 	// CompletionTokens is the number of tokens in the completion
 	CompletionTokens int
 
+	// CachedTokens is the number of prompt tokens read from the provider's prompt cache
+	CachedTokens int
+
 	// TotalTokens is the total number of tokens used
 	TotalTokens int
+
+	// Cost is the estimated cost in USD. Backends don't report it; it is set by
+	// callers from the configured price table (zero if the model has no price).
+	Cost float64
+}
+
+// Add adds other to the usage; TotalTokens defaults to prompt plus completion tokens
+func (u *Usage) Add(other *Usage) {
+	if other == nil {
+		return
+	}
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.CachedTokens += other.CachedTokens
+	u.Cost += other.Cost
+	if other.TotalTokens > 0 {
+		u.TotalTokens += other.TotalTokens
+	} else {
+		u.TotalTokens += other.PromptTokens + other.CompletionTokens
+	}
 }
 
 // NewRequest creates a new Request with default values
@@ -124,12 +153,24 @@ func (r *Request) WithWorkDir(workDir string) *Request {
 	return r
 }
 
+// WithAPIKey sets the API key used for this request
+func (r *Request) WithAPIKey(key string) *Request {
+	r.APIKey = key
+	return r
+}
+
 // WithSchema sets the response schema for structured output
 func (r *Request) WithSchema(schema *ResponseSchema) *Request {
 	r.ResponseSchema = schema
 	return r
 }
 
+// WithTools sets the tools the model may call
+func (r *Request) WithTools(tools []*Tool) *Request {
+	r.Tools = tools
+	return r
+}
+
 // WithOptions sets the request options
 func (r *Request) WithOptions(opts *RequestOptions) *Request {
 	r.Options = opts
@@ -0,0 +1,316 @@
+package llm
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Sandbox mount points and environment shared with the sandbox helper
+const (
+	// SandboxHelperCommand is the (hidden) command of the server binary that runs a CLI inside the sandbox
+	SandboxHelperCommand = "sandbox-exec"
+
+	sandboxHelperPath = "/run/verust/verustcode"
+	sandboxIODir      = "/run/verust/io"
+	sandboxProxySock  = "proxy.sock"
+	sandboxStatusFile = "violation"
+
+	sandboxEnvPrefix = "VERUST_SANDBOX_"
+	sandboxEnvIO     = sandboxEnvPrefix + "IO"
+	sandboxEnvProxy  = sandboxEnvPrefix + "PROXY"
+	sandboxEnvCPU    = sandboxEnvPrefix + "CPU"
+	sandboxEnvMemory = sandboxEnvPrefix + "MEMORY"
+	sandboxEnvWall   = sandboxEnvPrefix + "WALL"
+	sandboxEnvExec   = sandboxEnvPrefix + "EXEC"
+
+	defaultBwrapPath = "bwrap"
+)
+
+// SandboxProtectedPaths are the server directories never visible inside the sandbox
+// (relative paths are resolved against the working directory of the server)
+var SandboxProtectedPaths = []string{"data", "config"}
+
+// sandboxSystemPaths are the system directories mounted read-only in the sandbox, when they exist
+var sandboxSystemPaths = []string{"/usr", "/bin", "/sbin", "/lib", "/lib32", "/lib64", "/etc", "/opt"}
+
+// sandboxEnv are the server environment variables passed into the sandbox.
+// Variables set by the client itself (e.g. API keys) are always passed.
+var sandboxEnv = []string{"PATH", "HOME", "USER", "LOGNAME", "LANG", "LC_ALL", "TERM", "TZ"}
+
+// SandboxConfig configures the isolation of agent CLI processes with bubblewrap (Linux namespaces).
+// The CLI sees the system directories and its own installation read-only, the workspace of the
+// review read-only, and a private /tmp and home directory. Network access is limited to AllowedHosts.
+type SandboxConfig struct {
+	// Enabled runs the CLI in the sandbox
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+
+	// BwrapPath is the path of the bubblewrap executable (default: bwrap in PATH)
+	BwrapPath string `yaml:"bwrap_path,omitempty" json:"bwrap_path,omitempty"`
+
+	// AllowedHosts lists the hosts the CLI may connect to, through an egress proxy
+	// ("api.example.com", "*.example.com", optionally with ":port"; ports default to 443 and 80).
+	// Empty disables network access.
+	AllowedHosts []string `yaml:"allowed_hosts,omitempty" json:"allowed_hosts,omitempty"`
+
+	// ReadOnlyPaths are additional host paths mounted read-only (e.g. a CLI runtime)
+	ReadOnlyPaths []string `yaml:"read_only_paths,omitempty" json:"read_only_paths,omitempty"`
+
+	// WritablePaths are host paths mounted writable (e.g. the CLI's credential and session directory)
+	WritablePaths []string `yaml:"writable_paths,omitempty" json:"writable_paths,omitempty"`
+
+	// PassEnv lists additional server environment variables passed to the CLI
+	PassEnv []string `yaml:"pass_env,omitempty" json:"pass_env,omitempty"`
+
+	// CPUSeconds limits the CPU time of each process of an invocation (RLIMIT_CPU, 0 = no limit)
+	CPUSeconds int `yaml:"cpu_seconds,omitempty" json:"cpu_seconds,omitempty"`
+
+	// MemoryMB limits the address space of each process of an invocation (RLIMIT_AS, 0 = no limit)
+	MemoryMB int `yaml:"memory_mb,omitempty" json:"memory_mb,omitempty"`
+
+	// WallTime limits the duration of an invocation in seconds (0 = only the agent timeout applies)
+	WallTime int `yaml:"wall_time,omitempty" json:"wall_time,omitempty"`
+}
+
+// SandboxCommand prepares cmd, created with exec.Command for a CLI, to run in the sandbox of config.
+// Returns a function that must be called with the result of the command (the error of Wait or Run)
+// once it has exited: it releases the sandbox and logs limit and network violations with fields,
+// so that violations of review executions appear in the task logs.
+// With a nil or disabled config, cmd is left unchanged.
+func SandboxCommand(cmd *exec.Cmd, config *SandboxConfig, log *zap.Logger, fields ...zap.Field) (func(error), error) {
+	if config == nil || !config.Enabled {
+		return func(error) {}, nil
+	}
+	if cmd.Err != nil {
+		return nil, cmd.Err
+	}
+
+	ioDir, err := os.MkdirTemp("", "verust-sandbox-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sandbox directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(ioDir) }
+
+	args, err := sandboxArgs(cmd, config, ioDir)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	bwrap := config.BwrapPath
+	if bwrap == "" {
+		bwrap = defaultBwrapPath
+	}
+	bwrapPath, err := exec.LookPath(bwrap)
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("sandbox: bubblewrap not found: %w", err)
+	}
+
+	var proxy *egressProxy
+	if len(config.AllowedHosts) > 0 {
+		proxy, err = startEgressProxy(filepath.Join(ioDir, sandboxProxySock), config.AllowedHosts, func(host string) {
+			log.Warn("Sandbox violation: blocked outbound connection", append(fields, zap.String("host", host))...)
+		})
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+	}
+
+	cmd.Path = bwrapPath
+	cmd.Args = append([]string{bwrapPath}, args...)
+	cmd.Env = append(sandboxEnviron(cmd.Env, config.PassEnv), sandboxHelperEnv(config, proxy != nil)...)
+	cmd.Dir = ""
+
+	return func(runErr error) {
+		if proxy != nil {
+			proxy.Close()
+		}
+		if violation, readErr := os.ReadFile(filepath.Join(ioDir, sandboxStatusFile)); readErr == nil && len(violation) > 0 {
+			log.Warn("Sandbox violation: "+strings.TrimSpace(string(violation)), append(fields, zap.Error(runErr))...)
+		}
+		cleanup()
+	}, nil
+}
+
+// sandboxArgs returns the bubblewrap arguments running the command cmd in the sandbox
+func sandboxArgs(cmd *exec.Cmd, config *SandboxConfig, ioDir string) ([]string, error) {
+	helper, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: failed to locate the server binary: %w", err)
+	}
+
+	args := []string{"--die-with-parent", "--new-session", "--unshare-all", "--proc", "/proc", "--dev", "/dev"}
+	for _, path := range sandboxSystemPaths {
+		args = append(args, "--ro-bind-try", path, path)
+	}
+	for _, protected := range protectedPaths() {
+		// Hide protected directories of a server installed in a system directory (e.g. /opt)
+		for _, path := range sandboxSystemPaths {
+			if pathContains(path, protected) {
+				args = append(args, "--tmpfs", protected)
+				break
+			}
+		}
+	}
+	args = append(args, "--tmpfs", "/tmp")
+	if home, err := os.UserHomeDir(); err == nil && home != "/" {
+		args = append(args, "--tmpfs", home)
+	}
+
+	var readOnly []string
+	if cliDir := cliInstallDir(cmd.Path); cliDir != "" {
+		readOnly = append(readOnly, cliDir)
+	}
+	readOnly = append(readOnly, config.ReadOnlyPaths...)
+	for _, path := range readOnly {
+		abs, err := sandboxPath(path)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--ro-bind-try", abs, abs)
+	}
+	for _, path := range config.WritablePaths {
+		abs, err := sandboxPath(path)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--bind-try", abs, abs)
+	}
+
+	if cmd.Dir != "" {
+		workDir, err := sandboxPath(cmd.Dir)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--ro-bind", workDir, workDir, "--chdir", workDir)
+	}
+
+	args = append(args,
+		"--ro-bind", helper, sandboxHelperPath,
+		"--bind", ioDir, sandboxIODir,
+		"--", sandboxHelperPath, SandboxHelperCommand, "--")
+
+	// The CLI is started by its resolved path: launchers in the home directory are hidden
+	cliPath := cmd.Path
+	if resolved, err := filepath.EvalSymlinks(cliPath); err == nil {
+		cliPath = resolved
+	}
+	args = append(args, cliPath)
+	return append(args, cmd.Args[1:]...), nil
+}
+
+// sandboxPath returns the absolute path of a path mounted in the sandbox.
+// Returns an error if the path is, contains or is inside a protected server directory.
+func sandboxPath(path string) (string, error) {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("sandbox: invalid path %q: %w", path, err)
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+
+	for _, protected := range protectedPaths() {
+		if pathContains(abs, protected) || pathContains(protected, abs) {
+			return "", fmt.Errorf("sandbox: path %s exposes the protected directory %s", abs, protected)
+		}
+	}
+	return abs, nil
+}
+
+// protectedPaths returns the absolute paths of SandboxProtectedPaths
+func protectedPaths() []string {
+	paths := make([]string, 0, len(SandboxProtectedPaths))
+	for _, protected := range SandboxProtectedPaths {
+		abs, err := filepath.Abs(protected)
+		if err != nil {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+			abs = resolved
+		}
+		paths = append(paths, abs)
+	}
+	return paths
+}
+
+// pathContains returns true if path is dir or inside dir
+func pathContains(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// cliInstallDir returns the directory of the CLI installation (following symlinks, so that a
+// launcher in PATH brings its runtime along), or "" if it is a system directory
+func cliInstallDir(cliPath string) string {
+	resolved, err := filepath.EvalSymlinks(cliPath)
+	if err != nil {
+		return ""
+	}
+	dir := filepath.Dir(resolved)
+	for _, system := range sandboxSystemPaths {
+		if pathContains(system, dir) {
+			return ""
+		}
+	}
+	return dir
+}
+
+// sandboxEnviron filters the environment of a CLI command: variables inherited from the
+// server are only kept if they are listed in sandboxEnv or passEnv
+func sandboxEnviron(env, passEnv []string) []string {
+	serverEnv := os.Environ()
+	if env == nil {
+		env = serverEnv
+	}
+	inherited := make(map[string]bool, len(serverEnv))
+	for _, kv := range serverEnv {
+		inherited[kv] = true
+	}
+	allowed := make(map[string]bool, len(sandboxEnv)+len(passEnv))
+	for _, name := range append(append([]string{}, sandboxEnv...), passEnv...) {
+		allowed[name] = true
+	}
+
+	filtered := make([]string, 0, len(env))
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(name, sandboxEnvPrefix) {
+			continue
+		}
+		if !inherited[kv] || allowed[name] {
+			filtered = append(filtered, kv)
+		}
+	}
+	return filtered
+}
+
+// sandboxHelperEnv returns the environment configuring the sandbox helper
+func sandboxHelperEnv(config *SandboxConfig, proxy bool) []string {
+	env := []string{sandboxEnvIO + "=" + sandboxIODir}
+	if proxy {
+		env = append(env, sandboxEnvProxy+"="+filepath.Join(sandboxIODir, sandboxProxySock))
+	}
+	if config.CPUSeconds > 0 {
+		env = append(env, sandboxEnvCPU+"="+strconv.Itoa(config.CPUSeconds))
+	}
+	if config.MemoryMB > 0 {
+		env = append(env, sandboxEnvMemory+"="+strconv.Itoa(config.MemoryMB))
+	}
+	if config.WallTime > 0 {
+		env = append(env, sandboxEnvWall+"="+strconv.Itoa(config.WallTime))
+	}
+	return env
+}
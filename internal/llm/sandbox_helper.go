@@ -0,0 +1,202 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// sandboxLimits are the resource limits of the CLI (zero = no limit)
+type sandboxLimits struct {
+	CPU    time.Duration
+	Memory int64
+	Wall   time.Duration
+}
+
+// sandboxLimitsFromEnv returns the limits passed to the sandbox helper in its environment
+func sandboxLimitsFromEnv() sandboxLimits {
+	return sandboxLimits{
+		CPU:    time.Duration(envInt(sandboxEnvCPU)) * time.Second,
+		Memory: int64(envInt(sandboxEnvMemory)) << 20,
+		Wall:   time.Duration(envInt(sandboxEnvWall)) * time.Second,
+	}
+}
+
+// violation returns a description of the limit that stopped the CLI, or "".
+// wallExceeded is true if the CLI was killed after the wall time; cpu is the CPU time of
+// the CLI if it was terminated by a signal (the kernel signals a process at its CPU limit).
+func (l sandboxLimits) violation(wallExceeded bool, cpu time.Duration) string {
+	switch {
+	case wallExceeded:
+		return fmt.Sprintf("wall time limit of %s exceeded", l.Wall)
+	case l.CPU > 0 && cpu >= l.CPU:
+		return fmt.Sprintf("CPU time limit of %s exceeded (%s used)", l.CPU, cpu.Round(time.Millisecond))
+	}
+	return ""
+}
+
+// RunSandboxHelper runs a CLI command inside the sandbox and returns its exit code.
+// It is the entry point of the sandbox-exec command of the server binary, started by bubblewrap:
+// it forwards the CLI's proxy connections to the egress proxy of the server, enforces the wall
+// time and records violations for the server. The CPU and memory limits are kernel resource
+// limits, set by a second sandbox-exec process right before it executes the CLI.
+func RunSandboxHelper(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "sandbox-exec: missing command")
+		return 2
+	}
+	if os.Getenv(sandboxEnvExec) != "" {
+		return execSandboxed(args)
+	}
+	if !inSandbox() {
+		fmt.Fprintln(os.Stderr, "sandbox-exec: not running inside the sandbox")
+		return 2
+	}
+
+	ioDir := os.Getenv(sandboxEnvIO)
+	limits := sandboxLimitsFromEnv()
+	env := make([]string, 0, len(os.Environ()))
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, sandboxEnvPrefix) {
+			env = append(env, kv)
+		}
+	}
+
+	if socket := os.Getenv(sandboxEnvProxy); socket != "" {
+		addr, err := forwardProxy(socket)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "sandbox-exec:", err)
+			return 2
+		}
+		proxyURL := "http://" + addr
+		for _, name := range []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy", "ALL_PROXY", "all_proxy"} {
+			env = append(env, name+"="+proxyURL)
+		}
+		// Node.js only uses the proxy variables when asked to
+		env = append(env, "NO_PROXY=", "no_proxy=", "NODE_USE_ENV_PROXY=1")
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "sandbox-exec:", err)
+		return 127
+	}
+	cmd := exec.Command(self, append([]string{SandboxHelperCommand, "--"}, args...)...)
+	cmd.Env = append(env, sandboxEnvExec+"=1")
+	if limits.CPU > 0 {
+		cmd.Env = append(cmd.Env, sandboxEnvCPU+"="+os.Getenv(sandboxEnvCPU))
+	}
+	if limits.Memory > 0 {
+		cmd.Env = append(cmd.Env, sandboxEnvMemory+"="+os.Getenv(sandboxEnvMemory))
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.SysProcAttr = sandboxProcAttr()
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, "sandbox-exec:", err)
+		return 127
+	}
+
+	var wallExceeded atomic.Bool
+	if limits.Wall > 0 {
+		timer := time.AfterFunc(limits.Wall, func() {
+			wallExceeded.Store(true)
+			killSandboxProcesses(cmd.Process.Pid)
+		})
+		defer timer.Stop()
+	}
+
+	err = cmd.Wait()
+
+	var exitErr *exec.ExitError
+	var cpu time.Duration
+	if errors.As(err, &exitErr) && exitSignal(exitErr) != 0 {
+		cpu = exitErr.UserTime() + exitErr.SystemTime()
+	}
+	if violation := limits.violation(wallExceeded.Load(), cpu); violation != "" {
+		fmt.Fprintln(os.Stderr, "sandbox-exec:", violation)
+		if ioDir != "" {
+			os.WriteFile(filepath.Join(ioDir, sandboxStatusFile), []byte(violation), 0o600)
+		}
+	}
+
+	switch {
+	case err == nil:
+		return 0
+	case exitErr != nil:
+		if code := exitErr.ExitCode(); code >= 0 {
+			return code
+		}
+		return 128 + exitSignal(exitErr)
+	default:
+		return 1
+	}
+}
+
+// execSandboxed replaces the process with the CLI, under the CPU and memory limits of its
+// environment. The limits are inherited by all processes the CLI starts.
+func execSandboxed(args []string) int {
+	limits := sandboxLimitsFromEnv()
+	env := make([]string, 0, len(os.Environ()))
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, sandboxEnvPrefix) {
+			env = append(env, kv)
+		}
+	}
+
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "sandbox-exec:", err)
+		return 127
+	}
+	if err := setSandboxRlimits(limits); err != nil {
+		fmt.Fprintln(os.Stderr, "sandbox-exec:", err)
+		return 2
+	}
+	err = syscallExec(path, args, env)
+	fmt.Fprintln(os.Stderr, "sandbox-exec:", err)
+	return 127
+}
+
+// forwardProxy listens on a loopback port inside the sandbox and forwards its connections to the
+// unix socket of the egress proxy. Returns the address of the listener.
+func forwardProxy(socket string) (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("failed to listen for proxy connections: %w", err)
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				upstream, err := net.Dial("unix", socket)
+				if err != nil {
+					return
+				}
+				defer upstream.Close()
+				go io.Copy(upstream, conn)
+				io.Copy(conn, upstream)
+			}()
+		}
+	}()
+	return listener.Addr().String(), nil
+}
+
+// envInt returns the integer value of an environment variable (0 if unset or invalid)
+func envInt(name string) int {
+	n, _ := strconv.Atoi(os.Getenv(name))
+	return n
+}
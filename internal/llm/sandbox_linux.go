@@ -0,0 +1,75 @@
+//go:build linux
+
+package llm
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// setSandboxRlimits sets the kernel resource limits of the process, inherited by the CLI it
+// executes and all its children. A process reaching its CPU time gets SIGXCPU, and SIGKILL one
+// second later; allocations beyond the address space limit fail.
+func setSandboxRlimits(limits sandboxLimits) error {
+	if limits.CPU > 0 {
+		seconds := uint64((limits.CPU + time.Second - 1) / time.Second)
+		if err := lowerRlimit(syscall.RLIMIT_CPU, seconds, seconds+1); err != nil {
+			return fmt.Errorf("failed to set the CPU time limit: %w", err)
+		}
+	}
+	if limits.Memory > 0 {
+		if err := lowerRlimit(syscall.RLIMIT_AS, uint64(limits.Memory), uint64(limits.Memory)); err != nil {
+			return fmt.Errorf("failed to set the memory limit: %w", err)
+		}
+	}
+	return nil
+}
+
+// lowerRlimit sets the soft and hard limits of a resource, keeping lower current limits
+// (an unprivileged process can't raise its hard limit)
+func lowerRlimit(resource int, soft, hard uint64) error {
+	var current syscall.Rlimit
+	if err := syscall.Getrlimit(resource, &current); err == nil {
+		hard = min(hard, current.Max)
+		soft = min(soft, hard)
+	}
+	return syscall.Setrlimit(resource, &syscall.Rlimit{Cur: soft, Max: hard})
+}
+
+// syscallExec replaces the process with the program at path
+func syscallExec(path string, args, env []string) error {
+	return syscall.Exec(path, args, env)
+}
+
+// inSandbox returns true if the helper runs in the PID namespace created by bubblewrap,
+// whose init process is bubblewrap itself
+func inSandbox() bool {
+	if os.Getenv(sandboxEnvIO) == "" {
+		return false
+	}
+	comm, err := os.ReadFile("/proc/1/comm")
+	return err == nil && strings.TrimSpace(string(comm)) == "bwrap"
+}
+
+// sandboxProcAttr starts the CLI in its own process group, so that it is killed with its children
+func sandboxProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killSandboxProcesses kills the process group of the CLI started with pid. Processes that left
+// the group are killed by the kernel when bubblewrap, the init of the PID namespace, exits.
+func killSandboxProcesses(pid int) {
+	syscall.Kill(-pid, syscall.SIGKILL)
+}
+
+// exitSignal returns the number of the signal that terminated a process
+func exitSignal(exitErr *exec.ExitError) int {
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return int(status.Signal())
+	}
+	return 0
+}
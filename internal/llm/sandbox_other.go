@@ -0,0 +1,37 @@
+//go:build !linux
+
+package llm
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// setSandboxRlimits is only supported on Linux, where the sandbox runs
+func setSandboxRlimits(limits sandboxLimits) error {
+	return errors.New("resource limits are only available on Linux")
+}
+
+// syscallExec is only supported on Linux, where the sandbox runs
+func syscallExec(path string, args, env []string) error {
+	return errors.New("exec is only available on Linux")
+}
+
+// inSandbox is false outside Linux, where there is no sandbox
+func inSandbox() bool {
+	return false
+}
+
+// sandboxProcAttr has no attributes outside Linux
+func sandboxProcAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+// killSandboxProcesses is a no-op outside Linux
+func killSandboxProcesses(pid int) {}
+
+// exitSignal is unknown outside Linux
+func exitSignal(*exec.ExitError) int {
+	return 0
+}
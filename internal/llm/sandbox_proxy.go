@@ -0,0 +1,197 @@
+package llm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// egressDialTimeout is the timeout of connections opened by the egress proxy
+const egressDialTimeout = 30 * time.Second
+
+// egressProxy is the HTTP proxy through which sandboxed CLIs reach the network.
+// It listens on a unix socket mounted in the sandbox and only connects to allowed hosts.
+type egressProxy struct {
+	listener net.Listener
+	allowed  []string
+	onDenied func(host string)
+	wg       sync.WaitGroup
+}
+
+// startEgressProxy starts an egress proxy on the unix socket path.
+// onDenied is called with the host:port of every blocked connection.
+func startEgressProxy(path string, allowedHosts []string, onDenied func(host string)) (*egressProxy, error) {
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: failed to start egress proxy: %w", err)
+	}
+	p := &egressProxy{listener: listener, allowed: allowedHosts, onDenied: onDenied}
+	p.wg.Add(1)
+	go p.serve()
+	return p, nil
+}
+
+// Close stops the proxy and waits for open connections to finish
+func (p *egressProxy) Close() {
+	p.listener.Close()
+	p.wg.Wait()
+}
+
+// serve accepts proxy connections until the listener is closed
+func (p *egressProxy) serve() {
+	defer p.wg.Done()
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.handle(conn)
+		}()
+	}
+}
+
+// handle serves one client connection: a CONNECT tunnel or plain HTTP requests
+func (p *egressProxy) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		req, err := http.ReadRequest(reader)
+		if err != nil {
+			return
+		}
+
+		if req.Method == http.MethodConnect {
+			p.tunnel(conn, reader, req)
+			return
+		}
+		if !p.forward(conn, req) {
+			return
+		}
+	}
+}
+
+// tunnel connects a CONNECT request to its target and copies data both ways
+func (p *egressProxy) tunnel(conn net.Conn, reader *bufio.Reader, req *http.Request) {
+	target := withDefaultPort(req.Host, "443")
+	if !p.allow(target) {
+		writeProxyStatus(conn, http.StatusForbidden)
+		return
+	}
+
+	upstream, err := net.DialTimeout("tcp", target, egressDialTimeout)
+	if err != nil {
+		writeProxyStatus(conn, http.StatusBadGateway)
+		return
+	}
+	defer upstream.Close()
+	writeProxyStatus(conn, http.StatusOK)
+
+	done := make(chan struct{}, 2)
+	go func() {
+		// Data the client sent along with the CONNECT request is buffered in reader
+		io.Copy(upstream, reader)
+		if tcp, ok := upstream.(*net.TCPConn); ok {
+			tcp.CloseWrite()
+		}
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(conn, upstream)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// forward sends a plain HTTP request to its target and writes back the response.
+// Returns false if the connection must be closed.
+func (p *egressProxy) forward(conn net.Conn, req *http.Request) bool {
+	if req.URL.Scheme != "http" || req.URL.Host == "" {
+		writeProxyStatus(conn, http.StatusBadRequest)
+		return false
+	}
+	if !p.allow(withDefaultPort(req.URL.Host, "80")) {
+		writeProxyStatus(conn, http.StatusForbidden)
+		return false
+	}
+
+	req.RequestURI = ""
+	resp, err := egressTransport.RoundTrip(req)
+	if err != nil {
+		writeProxyStatus(conn, http.StatusBadGateway)
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.Write(conn) == nil && !req.Close && !resp.Close
+}
+
+// egressTransport sends the plain HTTP requests of the egress proxy (without proxies of its own)
+var egressTransport = &http.Transport{
+	DialContext:           (&net.Dialer{Timeout: egressDialTimeout}).DialContext,
+	ResponseHeaderTimeout: DefaultTimeout,
+}
+
+// allow returns true if the host:port target is allowed, and reports it otherwise
+func (p *egressProxy) allow(target string) bool {
+	if hostAllowed(target, p.allowed) {
+		return true
+	}
+	if p.onDenied != nil {
+		p.onDenied(target)
+	}
+	return false
+}
+
+// hostAllowed returns true if the host:port target matches an allowed host.
+// Entries without a port allow ports 443 and 80; "*.example.com" matches subdomains of example.com.
+func hostAllowed(target string, allowedHosts []string) bool {
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		return false
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	for _, entry := range allowedHosts {
+		pattern, allowedPort, err := net.SplitHostPort(entry)
+		if err != nil {
+			pattern, allowedPort = entry, ""
+		}
+		pattern = strings.ToLower(pattern)
+		if allowedPort == "" && port != "443" && port != "80" {
+			continue
+		}
+		if allowedPort != "" && allowedPort != port {
+			continue
+		}
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// withDefaultPort adds port to host if it has none
+func withDefaultPort(host, port string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// writeProxyStatus writes a proxy response without body
+func writeProxyStatus(conn net.Conn, status int) {
+	fmt.Fprintf(conn, "HTTP/1.1 %d %s\r\nContent-Length: 0\r\n\r\n", status, http.StatusText(status))
+}
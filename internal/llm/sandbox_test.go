@@ -0,0 +1,163 @@
+package llm
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSandboxCommand_Disabled(t *testing.T) {
+	cmd := exec.Command("true")
+	cmd.Dir = "/tmp"
+	path, args := cmd.Path, cmd.Args
+
+	for _, config := range []*SandboxConfig{nil, {Enabled: false}} {
+		release, err := SandboxCommand(cmd, config, zap.NewNop())
+		require.NoError(t, err)
+		release(nil)
+		assert.Equal(t, path, cmd.Path)
+		assert.Equal(t, args, cmd.Args)
+		assert.Equal(t, "/tmp", cmd.Dir)
+	}
+}
+
+func TestSandboxArgs(t *testing.T) {
+	workDir := t.TempDir()
+	cmd := exec.Command("true", "--flag", "value")
+	cmd.Dir = workDir
+
+	args, err := sandboxArgs(cmd, &SandboxConfig{Enabled: true}, "/tmp/io")
+	require.NoError(t, err)
+
+	joined := strings.Join(args, " ")
+	resolved, _ := filepath.EvalSymlinks(workDir)
+	assert.Contains(t, joined, "--unshare-all")
+	assert.Contains(t, joined, "--ro-bind "+resolved+" "+resolved+" --chdir "+resolved)
+	assert.Contains(t, joined, "--bind /tmp/io "+sandboxIODir)
+	assert.Contains(t, joined, "--tmpfs /tmp")
+	assert.NotContains(t, joined, "--bind "+resolved)
+	assert.Equal(t, []string{"--flag", "value"}, args[len(args)-2:])
+}
+
+func TestSandboxPath_Protected(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer os.Chdir(wd)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "data", "db"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "workspace"), 0o755))
+
+	_, err = sandboxPath(filepath.Join(dir, "data", "db"))
+	assert.Error(t, err, "path inside data/")
+	_, err = sandboxPath(dir)
+	assert.Error(t, err, "path containing data/")
+	_, err = sandboxPath("config")
+	assert.Error(t, err, "relative config/")
+
+	path, err := sandboxPath(filepath.Join(dir, "workspace"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(path, "workspace"))
+}
+
+func TestSandboxEnviron(t *testing.T) {
+	t.Setenv("VERUST_TEST_SECRET", "secret")
+	t.Setenv("VERUST_TEST_PASSED", "passed")
+
+	env := append(os.Environ(), "CLIENT_API_KEY=key", sandboxEnvIO+"=/spoofed")
+	filtered := sandboxEnviron(env, []string{"VERUST_TEST_PASSED"})
+
+	assert.Contains(t, filtered, "CLIENT_API_KEY=key")
+	assert.Contains(t, filtered, "VERUST_TEST_PASSED=passed")
+	assert.NotContains(t, filtered, "VERUST_TEST_SECRET=secret")
+	assert.NotContains(t, filtered, sandboxEnvIO+"=/spoofed")
+}
+
+func TestSandboxLimits_Violation(t *testing.T) {
+	limits := sandboxLimits{CPU: time.Second, Memory: 64 << 20, Wall: time.Minute}
+
+	assert.Empty(t, limits.violation(false, time.Millisecond))
+	assert.Contains(t, limits.violation(true, 0), "wall time")
+	assert.Contains(t, limits.violation(false, 2*time.Second), "CPU time")
+	assert.Empty(t, sandboxLimits{}.violation(false, time.Hour))
+}
+
+func TestRunSandboxHelper_OutsideSandbox(t *testing.T) {
+	// Without the PID namespace of bubblewrap, the helper refuses to run (and to kill processes)
+	t.Setenv(sandboxEnvIO, t.TempDir())
+	assert.Equal(t, 2, RunSandboxHelper([]string{"true"}))
+}
+
+func TestExecSandboxed(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the sandbox runs on Linux only")
+	}
+	if os.Getenv("VERUST_TEST_EXEC_SANDBOXED") == "1" {
+		// The CLI sees the kernel limits and none of the helper variables
+		os.Exit(execSandboxed([]string{"sh", "-c", "ulimit -t; ulimit -v; echo ${" + sandboxEnvCPU + ":-unset}"}))
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestExecSandboxed$")
+	cmd.Env = append(os.Environ(), "VERUST_TEST_EXEC_SANDBOXED=1", sandboxEnvCPU+"=5", sandboxEnvMemory+"=512")
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "5\n524288\nunset\n", string(out))
+}
+
+func TestHostAllowed(t *testing.T) {
+	allowed := []string{"api.example.com", "*.cdn.example.org", "git.example.net:8443"}
+
+	tests := []struct {
+		target   string
+		expected bool
+	}{
+		{"api.example.com:443", true},
+		{"API.example.com:80", true},
+		{"api.example.com:22", false},
+		{"other.example.com:443", false},
+		{"assets.cdn.example.org:443", true},
+		{"cdn.example.org:443", false},
+		{"git.example.net:8443", true},
+		{"git.example.net:443", false},
+		{"api.example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			assert.Equal(t, tt.expected, hostAllowed(tt.target, allowed))
+		})
+	}
+}
+
+func TestEgressProxy_Denied(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "proxy.sock")
+	denied := make(chan string, 1)
+	proxy, err := startEgressProxy(socket, []string{"allowed.example.com"}, func(host string) {
+		denied <- host
+	})
+	require.NoError(t, err)
+	defer proxy.Close()
+
+	conn, err := net.Dial("unix", socket)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("CONNECT blocked.example.com:443 HTTP/1.1\r\nHost: blocked.example.com:443\r\n\r\n"))
+	require.NoError(t, err)
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, "blocked.example.com:443", <-denied)
+}
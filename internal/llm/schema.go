@@ -147,6 +147,18 @@ func (g *SchemaGenerator) ToJSONString(schema map[string]interface{}) (string, e
 	return string(data), nil
 }
 
+// JSONSchema returns the JSON Schema of a response schema.
+// Schemas given as maps are returned as they are, Go values are converted.
+func (s *ResponseSchema) JSONSchema() (map[string]interface{}, error) {
+	if s == nil || s.Schema == nil {
+		return nil, fmt.Errorf("response schema is empty")
+	}
+	if schemaMap, ok := s.Schema.(map[string]interface{}); ok {
+		return schemaMap, nil
+	}
+	return NewSchemaGenerator().Generate(s.Schema)
+}
+
 // BuildSchemaPrompt builds a prompt instruction for structured output
 func BuildSchemaPrompt(schema *ResponseSchema) string {
 	if schema == nil {
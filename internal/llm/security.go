@@ -2,6 +2,8 @@ package llm
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 )
@@ -28,39 +30,191 @@ func DefaultSecurityConfig() *SecurityConfig {
 	}
 }
 
-// injectionPatterns contains patterns that may indicate prompt injection attempts
-var injectionPatterns = []string{
-	"</absolute_rules>",
-	"</system>",
-	"<system_override",
-	"system override",
-	"忘记上述",
-	"忘掉上述",
-	"忽略上述",
-	"绕过规则",
-	"bypass rule",
-	"ignore previous",
-	"forget previous",
-	"system prompt",
-	"actual instruction",
-	"real instruction",
-	"priority=\"critical\"",
-	"priority=\"highest\"",
-	"管理员权限",
-	"admin override",
-}
-
-// DetectPromptInjection checks if the prompt contains potential injection patterns
-func DetectPromptInjection(prompt string) bool {
-	lower := strings.ToLower(prompt)
+// InjectionPatternSet is a named set of regular expressions matching prompt injection attempts
+type InjectionPatternSet struct {
+	Name     string
+	Patterns []*regexp.Regexp
+}
+
+// InjectionMatch is a match of an injection pattern in a text
+type InjectionMatch struct {
+	PatternSet string // name of the pattern set
+	Pattern    string // the matching regular expression
+	Text       string // the matched text
+	Start      int    // byte offsets of the match
+	End        int
+}
+
+// Built-in pattern set names
+const (
+	PatternSetInstructionOverride = "instruction_override"
+	PatternSetRoleHijack          = "role_hijack"
+	PatternSetPromptExfiltration  = "prompt_exfiltration"
+	PatternSetReviewManipulation  = "review_manipulation"
+	PatternSetDelimiterEscape     = "delimiter_escape"
+	PatternSetMultilingual        = "multilingual"
+)
+
+// builtinInjectionPatterns contains the patterns of the built-in pattern sets.
+// Patterns are matched case-insensitively.
+var builtinInjectionPatterns = []struct {
+	name     string
+	patterns []string
+}{
+	{PatternSetInstructionOverride, []string{
+		`\b(ignore|disregard|forget|skip|override)\s+(all\s+|any\s+|the\s+|your\s+)*(previous|prior|above|earlier|preceding|system|original)\b`,
+		`\bbypass\s+(the\s+|all\s+|any\s+)*(rules?|restrictions?|instructions?|guidelines?|filters?)\b`,
+		`\b(actual|real|new|updated|true)\s+instructions?\b`,
+		`\bsystem\s+override\b`,
+		`\badmin(istrator)?\s+override\b`,
+		`\bfrom\s+now\s+on,?\s+(you|ignore|always|only)\b`,
+	}},
+	{PatternSetRoleHijack, []string{
+		`\byou\s+are\s+(now|no\s+longer)\b`,
+		`\b(act|behave|respond)\s+as\s+(if\s+you\s+(are|were)\s+)?(an?\s+)?(unrestricted|jailbroken|different|new)\b`,
+		`\bpretend\s+(to\s+be|you\s+are)\b`,
+		`\b(developer|dan|jailbreak|god)\s+mode\b`,
+	}},
+	{PatternSetPromptExfiltration, []string{
+		`\bsystem\s+prompt\b`,
+		`\b(reveal|print|show|output|repeat|leak)\s+(me\s+)?(your|the)\s+(instructions|prompt|rules|configuration)\b`,
+		`\b(send|upload|exfiltrate|leak)\b.{0,40}(\.env\b|api[_\s-]?keys?|secrets|credentials)`,
+	}},
+	{PatternSetReviewManipulation, []string{
+		`\b(approve|lgtm)\s+(this|the)\s+(pr|pull\s+request|merge\s+request|change)`,
+		`\b(do\s+not|don'?t|never)\s+(report|flag|mention|comment\s+on)\s+(any\s+)?(issues?|findings?|problems?|vulnerabilit(y|ies))\b`,
+		`\b(report|return|output)\s+(no|zero|an\s+empty\s+list\s+of)\s+(issues|findings|problems)\b`,
+		`\b(mark|treat)\s+(this|the\s+code|all\s+code)\s+as\s+(safe|secure|approved)\b`,
+	}},
+	{PatternSetDelimiterEscape, []string{
+		`</?\s*(system|absolute_rules|user_request|untrusted_input|instructions?)\b[^>]*>`,
+		`<system_override`,
+		`priority\s*=\s*"(critical|highest)"`,
+		`\[/?(inst|system)\]`,
+		`<\|(im_start|im_end|system|endoftext)\|>`,
+	}},
+	{PatternSetMultilingual, []string{
+		`(忘记|忘掉|忽略|无视)(上述|以上|之前|前面)`,
+		`绕过(规则|限制)`,
+		`管理员权限`,
+		`系统提示(词)?`,
+		`(以上|之前)的(指令|规则)(都)?无效`,
+	}},
+}
+
+// injectionPatternSets holds the compiled built-in pattern sets
+var injectionPatternSets = compileBuiltinPatternSets()
+
+// compileBuiltinPatternSets compiles the built-in pattern sets
+func compileBuiltinPatternSets() []*InjectionPatternSet {
+	sets := make([]*InjectionPatternSet, 0, len(builtinInjectionPatterns))
+	for _, b := range builtinInjectionPatterns {
+		set, err := NewInjectionPatternSet(b.name, b.patterns)
+		if err != nil {
+			panic(err)
+		}
+		sets = append(sets, set)
+	}
+	return sets
+}
+
+// NewInjectionPatternSet compiles a pattern set. Patterns are matched case-insensitively.
+func NewInjectionPatternSet(name string, patterns []string) (*InjectionPatternSet, error) {
+	set := &InjectionPatternSet{Name: name}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid injection pattern %q: %w", pattern, err)
+		}
+		set.Patterns = append(set.Patterns, re)
+	}
+	return set, nil
+}
+
+// InjectionPatternSetNames returns the names of the built-in pattern sets
+func InjectionPatternSetNames() []string {
+	names := make([]string, 0, len(injectionPatternSets))
+	for _, set := range injectionPatternSets {
+		names = append(names, set.Name)
+	}
+	return names
+}
+
+// DefaultInjectionPatternSets returns all built-in pattern sets
+func DefaultInjectionPatternSets() []*InjectionPatternSet {
+	return injectionPatternSets
+}
+
+// InjectionPatternSetsByName returns the built-in pattern sets with the given names.
+// Empty names select all built-in sets.
+func InjectionPatternSetsByName(names []string) ([]*InjectionPatternSet, error) {
+	if len(names) == 0 {
+		return injectionPatternSets, nil
+	}
+	sets := make([]*InjectionPatternSet, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, set := range injectionPatternSets {
+			if set.Name == name {
+				sets = append(sets, set)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown injection pattern set %q (expected one of %s)", name, strings.Join(InjectionPatternSetNames(), ", "))
+		}
+	}
+	return sets, nil
+}
 
-	for _, pattern := range injectionPatterns {
-		if strings.Contains(lower, strings.ToLower(pattern)) {
-			return true
+// FindPromptInjections returns the matches of the pattern sets in text, ordered by position.
+// Overlapping matches of different patterns are all returned.
+func FindPromptInjections(text string, sets []*InjectionPatternSet) []InjectionMatch {
+	var matches []InjectionMatch
+	for _, set := range sets {
+		for _, re := range set.Patterns {
+			for _, loc := range re.FindAllStringIndex(text, -1) {
+				matches = append(matches, InjectionMatch{
+					PatternSet: set.Name,
+					Pattern:    strings.TrimPrefix(re.String(), "(?i)"),
+					Text:       text[loc[0]:loc[1]],
+					Start:      loc[0],
+					End:        loc[1],
+				})
+			}
 		}
 	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+	return matches
+}
+
+// DetectPromptInjection checks if the prompt matches any of the built-in injection patterns
+func DetectPromptInjection(prompt string) bool {
+	return len(FindPromptInjections(prompt, injectionPatternSets)) > 0
+}
+
+// UntrustedInputTag is the tag delimiting untrusted input in prompts
+const UntrustedInputTag = "untrusted_input"
+
+// WrapUntrusted delimits untrusted input (PR metadata, commit messages, file contents) for a prompt.
+// The content is escaped with EscapeXMLChars, so it cannot close the tag or open other tags.
+func WrapUntrusted(source, content string) string {
+	return fmt.Sprintf("<%s source=\"%s\">\n%s\n</%s>",
+		UntrustedInputTag, EscapeXMLChars(source), EscapeXMLChars(strings.TrimRight(content, "\n")), UntrustedInputTag)
+}
+
+// untrustedCloseTag matches closing tags of untrusted input, including variants with spaces or capitals
+var untrustedCloseTag = regexp.MustCompile(`(?i)<(\s*/\s*` + UntrustedInputTag + `)`)
 
-	return false
+// WrapUntrustedCode delimits untrusted code or tool output (file contents, diffs, command output)
+// for a prompt. Unlike WrapUntrusted, only closing tags of the delimiter are escaped, so that
+// the code reaches the model as written (e.g. "&&" and "<" are kept).
+func WrapUntrustedCode(source, content string) string {
+	return fmt.Sprintf("<%s source=\"%s\">\n%s\n</%s>",
+		UntrustedInputTag, EscapeXMLChars(source), untrustedCloseTag.ReplaceAllString(strings.TrimRight(content, "\n"), "&lt;$1"), UntrustedInputTag)
 }
 
 // EscapeXMLChars escapes XML special characters in the input
@@ -0,0 +1,71 @@
+package llm
+
+import (
+	"sync"
+
+	"github.com/verustcode/verustcode/pkg/idgen"
+)
+
+// MaxSessions is the number of sessions a SessionStore keeps; creating more evicts the oldest
+const MaxSessions = 256
+
+// SessionStore keeps the conversation history of API client sessions in memory.
+// CLI clients don't need it, their CLI keeps the sessions.
+type SessionStore struct {
+	mu       sync.Mutex
+	sessions map[string][]Message
+	order    []string // session IDs, oldest first
+}
+
+// NewSessionStore creates an empty SessionStore
+func NewSessionStore() *SessionStore {
+	return &SessionStore{sessions: make(map[string][]Message)}
+}
+
+// Create creates a session and returns its ID (prefixed with the client name).
+// The oldest session is evicted when the store already holds MaxSessions sessions.
+func (s *SessionStore) Create(client string) string {
+	sessionID := client + "-" + idgen.NewID()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.order) >= MaxSessions {
+		delete(s.sessions, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.sessions[sessionID] = nil
+	s.order = append(s.order, sessionID)
+
+	return sessionID
+}
+
+// History returns a copy of the conversation history of a session (nil for unknown sessions)
+func (s *SessionStore) History(sessionID string) []Message {
+	if sessionID == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.sessions[sessionID]...)
+}
+
+// Save replaces the conversation history of a session.
+// Evicted and unknown sessions are not recreated.
+func (s *SessionStore) Save(sessionID string, messages []Message) {
+	if sessionID == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[sessionID]; ok {
+		s.sessions[sessionID] = messages
+	}
+}
+
+// Clear removes all sessions
+func (s *SessionStore) Clear() {
+	s.mu.Lock()
+	s.sessions = make(map[string][]Message)
+	s.order = nil
+	s.mu.Unlock()
+}
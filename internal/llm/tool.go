@@ -0,0 +1,35 @@
+package llm
+
+import (
+	"context"
+)
+
+// ToolHandler executes a tool call with the JSON arguments chosen by the model
+// and returns the tool output passed back to the model
+type ToolHandler func(ctx context.Context, arguments string) (string, error)
+
+// Tool is a function the model may call through function calling.
+// Only API clients use tools; CLI clients bring their own.
+type Tool struct {
+	// Name is the function name exposed to the model
+	Name string
+
+	// Description tells the model what the tool does
+	Description string
+
+	// Parameters is the JSON Schema of the tool arguments
+	Parameters map[string]interface{}
+
+	// Handler executes the tool
+	Handler ToolHandler
+}
+
+// FindTool returns the tool with the given name, or nil
+func FindTool(tools []*Tool, name string) *Tool {
+	for _, tool := range tools {
+		if tool.Name == name {
+			return tool
+		}
+	}
+	return nil
+}
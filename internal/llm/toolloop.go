@@ -0,0 +1,170 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// DefaultMaxToolRounds is the default maximum number of tool-calling round trips per execution.
+// The last round asks the model to answer without calling tools.
+const DefaultMaxToolRounds = 25
+
+// Message roles of a tool-calling conversation
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// Message is a message of a tool-calling conversation with an API client.
+// API clients convert messages to and from the format of their backend.
+type Message struct {
+	// Role is RoleUser, RoleAssistant or RoleTool
+	Role string
+
+	// Content is the text of the message (the output of a tool for tool messages)
+	Content string
+
+	// ToolCalls are the tool calls requested by an assistant message
+	ToolCalls []ToolCall
+
+	// ToolCallID and ToolName identify the call a tool message answers
+	ToolCallID string
+	ToolName   string
+
+	// IsError indicates that a tool call failed
+	IsError bool
+
+	// Native holds backend-specific content of assistant messages that must be sent back
+	// as it was received (e.g., signed thinking blocks). Nil for other backends.
+	Native interface{}
+}
+
+// ToolCall is a tool call requested by the model
+type ToolCall struct {
+	// ID identifies the call (generated by the client if the backend has no IDs)
+	ID string
+
+	// Name is the name of the tool
+	Name string
+
+	// Arguments are the JSON arguments of the call
+	Arguments string
+}
+
+// Turn is the answer of the model to a conversation
+type Turn struct {
+	// Message is the assistant message
+	Message Message
+
+	// Usage is the token usage of the turn (optional)
+	Usage *Usage
+}
+
+// TurnFunc sends a conversation to the model and returns its answer.
+// When final is true, the model must answer without calling tools.
+type TurnFunc func(ctx context.Context, messages []Message, final bool) (*Turn, error)
+
+// ToolLoopResult is the outcome of a tool-calling conversation
+type ToolLoopResult struct {
+	// Messages is the whole conversation, including the final answer
+	Messages []Message
+
+	// Content is the final answer of the model
+	Content string
+
+	// Usage is the token usage of all turns
+	Usage *Usage
+
+	// ToolCalls is the number of tool calls executed
+	ToolCalls int
+}
+
+// RunToolLoop runs a tool-calling conversation: the model is called with the conversation,
+// the tool calls it requests are executed and their results are sent back, until it answers
+// without calling tools or maxRounds is reached. Tool calls and results are reported to
+// callback (if not nil) as ChunkTypeToolCall and ChunkTypeToolResult chunks.
+func RunToolLoop(ctx context.Context, messages []Message, tools []*Tool, maxRounds int, turn TurnFunc, callback StreamCallback) (*ToolLoopResult, error) {
+	if maxRounds <= 0 {
+		maxRounds = DefaultMaxToolRounds
+	}
+	result := &ToolLoopResult{Usage: &Usage{}}
+
+	for round := 0; ; round++ {
+		final := len(tools) == 0 || round >= maxRounds
+		answer, err := turn(ctx, messages, final)
+		if err != nil {
+			return nil, err
+		}
+		result.Usage.Add(answer.Usage)
+		messages = append(messages, answer.Message)
+
+		if final || len(answer.Message.ToolCalls) == 0 {
+			result.Messages = messages
+			result.Content = answer.Message.Content
+			return result, nil
+		}
+
+		for _, call := range answer.Message.ToolCalls {
+			result.ToolCalls++
+			messages = append(messages, ExecuteTool(ctx, tools, call, callback))
+		}
+	}
+}
+
+// ExecuteTool executes a tool call and returns the tool message for the model.
+// Unknown tools and tool failures are reported to the model rather than failing the execution.
+func ExecuteTool(ctx context.Context, tools []*Tool, call ToolCall, callback StreamCallback) Message {
+	if callback != nil {
+		callback(&StreamChunk{
+			Type:      ChunkTypeToolCall,
+			ToolName:  call.Name,
+			ToolInput: call.Arguments,
+		})
+	}
+
+	message := Message{Role: RoleTool, ToolCallID: call.ID, ToolName: call.Name}
+	if tool := FindTool(tools, call.Name); tool == nil {
+		message.Content = fmt.Sprintf("error: unknown tool %q", call.Name)
+		message.IsError = true
+	} else if output, err := tool.Handler(ctx, call.Arguments); err != nil {
+		message.Content = "error: " + err.Error()
+		message.IsError = true
+	} else {
+		message.Content = output
+	}
+
+	if callback != nil {
+		callback(&StreamChunk{
+			Type:       ChunkTypeToolResult,
+			ToolName:   call.Name,
+			ToolOutput: message.Content,
+		})
+	}
+	return message
+}
+
+// BuildToolLoopResponse builds the response of an API client execution from the outcome
+// of its tool-calling conversation, and sends the final result chunk to callback (if not nil)
+func (b *BaseClient) BuildToolLoopResponse(req *Request, model string, result *ToolLoopResult, callback StreamCallback) *Response {
+	resp := b.BuildResponse(result.Content, model, req.SessionID, req.ResponseSchema)
+	resp.Usage = result.Usage
+
+	// Copy metadata from request to response (including rule_id)
+	if req.Options != nil {
+		for k, v := range req.Options.Metadata {
+			resp.Metadata[k] = v
+		}
+	}
+	resp.Metadata["tool_calls"] = strconv.Itoa(result.ToolCalls)
+
+	if callback != nil {
+		callback(&StreamChunk{
+			Type:       ChunkTypeResult,
+			Content:    result.Content,
+			IsComplete: true,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,120 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRunToolLoop(t *testing.T) {
+	tools := []*Tool{{
+		Name: "echo",
+		Handler: func(ctx context.Context, arguments string) (string, error) {
+			if arguments == "fail" {
+				return "", errors.New("boom")
+			}
+			return "echo " + arguments, nil
+		},
+	}}
+
+	var finals []bool
+	turn := func(ctx context.Context, messages []Message, final bool) (*Turn, error) {
+		finals = append(finals, final)
+		if len(finals) == 1 {
+			return &Turn{
+				Message: Message{Role: RoleAssistant, ToolCalls: []ToolCall{
+					{ID: "1", Name: "echo", Arguments: "hi"},
+					{ID: "2", Name: "echo", Arguments: "fail"},
+					{ID: "3", Name: "missing"},
+				}},
+				Usage: &Usage{PromptTokens: 10, CompletionTokens: 2},
+			}, nil
+		}
+		return &Turn{Message: Message{Role: RoleAssistant, Content: "done"}, Usage: &Usage{PromptTokens: 20, CompletionTokens: 3, TotalTokens: 23}}, nil
+	}
+
+	var chunks []string
+	result, err := RunToolLoop(context.Background(), []Message{{Role: RoleUser, Content: "go"}}, tools, 5, turn, func(chunk *StreamChunk) {
+		chunks = append(chunks, string(chunk.Type)+":"+chunk.ToolName)
+	})
+	if err != nil {
+		t.Fatalf("RunToolLoop() error = %v", err)
+	}
+
+	if result.Content != "done" || result.ToolCalls != 3 || len(result.Messages) != 6 {
+		t.Fatalf("result = %+v", result)
+	}
+	if result.Usage.TotalTokens != 35 || result.Usage.PromptTokens != 30 {
+		t.Errorf("Usage = %+v", result.Usage)
+	}
+	if got := result.Messages[2]; got.Role != RoleTool || got.ToolCallID != "1" || got.Content != "echo hi" || got.IsError {
+		t.Errorf("tool result = %+v", got)
+	}
+	if got := result.Messages[3]; !got.IsError || got.Content != "error: boom" {
+		t.Errorf("failed tool result = %+v", got)
+	}
+	if got := result.Messages[4]; !got.IsError || !strings.Contains(got.Content, "unknown tool") {
+		t.Errorf("unknown tool result = %+v", got)
+	}
+	if len(chunks) != 6 || chunks[0] != "tool_call:echo" || chunks[1] != "tool_result:echo" {
+		t.Errorf("chunks = %v", chunks)
+	}
+	if len(finals) != 2 || finals[0] || finals[1] {
+		t.Errorf("final flags = %v, want two non-final turns", finals)
+	}
+}
+
+func TestRunToolLoop_MaxRounds(t *testing.T) {
+	tools := []*Tool{{Name: "loop", Handler: func(ctx context.Context, arguments string) (string, error) { return "", nil }}}
+	rounds := 0
+	turn := func(ctx context.Context, messages []Message, final bool) (*Turn, error) {
+		rounds++
+		// A model ignoring the final round still ends the loop
+		return &Turn{Message: Message{Role: RoleAssistant, Content: "partial", ToolCalls: []ToolCall{{Name: "loop"}}}}, nil
+	}
+	result, err := RunToolLoop(context.Background(), nil, tools, 2, turn, nil)
+	if err != nil {
+		t.Fatalf("RunToolLoop() error = %v", err)
+	}
+	if rounds != 3 || result.ToolCalls != 2 || result.Content != "partial" {
+		t.Errorf("rounds = %d, result = %+v", rounds, result)
+	}
+}
+
+func TestSessionStore(t *testing.T) {
+	store := NewSessionStore()
+	id := store.Create("openai")
+	if !strings.HasPrefix(id, "openai-") {
+		t.Errorf("session ID = %s", id)
+	}
+
+	store.Save(id, []Message{{Role: RoleUser, Content: "hi"}})
+	history := store.History(id)
+	history[0].Content = "changed"
+	if store.History(id)[0].Content != "hi" {
+		t.Error("History() should return a copy")
+	}
+
+	store.Clear()
+	if store.History(id) != nil {
+		t.Error("Clear() should remove all sessions")
+	}
+}
+
+func TestSessionStore_Eviction(t *testing.T) {
+	store := NewSessionStore()
+	first := store.Create("openai")
+	store.Save(first, []Message{{Role: RoleUser, Content: "hi"}})
+	for i := 0; i < MaxSessions; i++ {
+		store.Create("openai")
+	}
+
+	if store.History(first) != nil {
+		t.Error("oldest session should be evicted")
+	}
+	store.Save(first, []Message{{Role: RoleUser, Content: "again"}})
+	if store.History(first) != nil {
+		t.Error("Save() should not recreate an evicted session")
+	}
+}
@@ -0,0 +1,71 @@
+package llm
+
+import "encoding/json"
+
+// Token count keys of the usage objects reported by the supported backends,
+// in order of preference
+var (
+	promptTokenKeys     = []string{"prompt_tokens", "input_tokens", "promptTokens", "inputTokens"}
+	completionTokenKeys = []string{"completion_tokens", "output_tokens", "completionTokens", "outputTokens"}
+	cachedTokenKeys     = []string{"cached_tokens", "cache_read_input_tokens", "cached", "cachedTokens", "cacheReadTokens"}
+	totalTokenKeys      = []string{"total_tokens", "totalTokens"}
+)
+
+// ParseUsage extracts token usage from a decoded usage or stats object of a
+// CLI result event. It returns nil if the object contains no token counts.
+//
+// Anthropic-style objects (with cache_read_input_tokens or cache_creation_input_tokens)
+// report cached input separately from input_tokens; both are counted as prompt tokens.
+func ParseUsage(data map[string]interface{}) *Usage {
+	if data == nil {
+		return nil
+	}
+
+	usage := &Usage{
+		PromptTokens:     intValue(data, promptTokenKeys),
+		CompletionTokens: intValue(data, completionTokenKeys),
+		CachedTokens:     intValue(data, cachedTokenKeys),
+		TotalTokens:      intValue(data, totalTokenKeys),
+	}
+	if _, ok := data["input_tokens"]; ok {
+		if _, ok := data["prompt_tokens"]; !ok {
+			usage.PromptTokens += intValue(data, []string{"cache_creation_input_tokens"}) +
+				intValue(data, []string{"cache_read_input_tokens"})
+		}
+	}
+
+	if usage.PromptTokens == 0 && usage.CompletionTokens == 0 && usage.TotalTokens == 0 {
+		return nil
+	}
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	return usage
+}
+
+// intValue returns the first numeric value found under keys
+func intValue(data map[string]interface{}, keys []string) int {
+	for _, key := range keys {
+		switch v := data[key].(type) {
+		case float64:
+			return int(v)
+		case int:
+			return v
+		}
+	}
+	return 0
+}
+
+// ParseUsageJSON extracts token usage from a JSON encoded usage or stats object,
+// as stored in the metadata of result stream chunks. It returns nil if the
+// object is empty, invalid or contains no token counts.
+func ParseUsageJSON(value string) *Usage {
+	if value == "" {
+		return nil
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return nil
+	}
+	return ParseUsage(data)
+}
@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseUsage(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *Usage
+	}{
+		{
+			name:  "gemini stats",
+			input: `{"total_tokens":130,"input_tokens":100,"output_tokens":30,"cached":40,"duration_ms":1200,"tool_calls":2}`,
+			want:  &Usage{PromptTokens: 100, CompletionTokens: 30, CachedTokens: 40, TotalTokens: 130},
+		},
+		{
+			name:  "anthropic style usage",
+			input: `{"input_tokens":10,"output_tokens":5,"cache_creation_input_tokens":20,"cache_read_input_tokens":70}`,
+			want:  &Usage{PromptTokens: 100, CompletionTokens: 5, CachedTokens: 70, TotalTokens: 105},
+		},
+		{
+			name:  "openai style usage",
+			input: `{"prompt_tokens":12,"completion_tokens":3,"cached_tokens":4}`,
+			want:  &Usage{PromptTokens: 12, CompletionTokens: 3, CachedTokens: 4, TotalTokens: 15},
+		},
+		{
+			name:  "camel case",
+			input: `{"inputTokens":7,"outputTokens":2,"cacheReadTokens":1}`,
+			want:  &Usage{PromptTokens: 7, CompletionTokens: 2, CachedTokens: 1, TotalTokens: 9},
+		},
+		{
+			name:  "no token counts",
+			input: `{"duration_ms":1200}`,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.input), &data); err != nil {
+				t.Fatal(err)
+			}
+			got := ParseUsage(data)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("ParseUsage() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsage_Add(t *testing.T) {
+	total := &Usage{}
+	total.Add(&Usage{PromptTokens: 10, CompletionTokens: 2, CachedTokens: 4, Cost: 0.25})
+	total.Add(&Usage{PromptTokens: 5, CompletionTokens: 1, TotalTokens: 7, Cost: 0.5})
+	total.Add(nil)
+	if *total != (Usage{PromptTokens: 15, CompletionTokens: 3, CachedTokens: 4, TotalTokens: 19, Cost: 0.75}) {
+		t.Errorf("Add() = %+v", total)
+	}
+}
@@ -0,0 +1,246 @@
+package llm
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SchemaError is a violation of a JSON Schema by a value
+type SchemaError struct {
+	// Path is the location of the invalid value, e.g. "findings[2].severity" (empty for the root)
+	Path string
+
+	// Message describes the violation
+	Message string
+}
+
+// Error returns the error with its path
+func (e SchemaError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// ValidateSchema validates a decoded JSON value against a JSON Schema and returns all violations.
+// It supports the keywords of the output schemas built from the DSL: type, properties,
+// required, items, enum and pattern. Other keywords are ignored.
+// Keyword values may be Go values (e.g. []string) or decoded JSON (e.g. []interface{}).
+func ValidateSchema(value interface{}, schema map[string]interface{}) []SchemaError {
+	var errs []SchemaError
+	validateValue(value, schema, "", &errs)
+	return errs
+}
+
+// validateValue validates value against schema and appends the violations to errs
+func validateValue(value interface{}, schema map[string]interface{}, path string, errs *[]SchemaError) {
+	if schema == nil {
+		return
+	}
+
+	if types := stringList(schema["type"]); len(types) > 0 {
+		if !matchesAnyType(value, types) {
+			*errs = append(*errs, SchemaError{Path: path,
+				Message: fmt.Sprintf("expected %s, got %s", strings.Join(types, " or "), jsonTypeOf(value))})
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"]; ok {
+		allowed := enumValues(enum)
+		if !containsValue(allowed, value) {
+			*errs = append(*errs, SchemaError{Path: path,
+				Message: fmt.Sprintf("value %s is not one of %s", formatValue(value), formatValues(allowed))})
+		}
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		if s, isString := value.(string); isString {
+			re, err := regexp.Compile(pattern)
+			if err == nil && !re.MatchString(s) {
+				*errs = append(*errs, SchemaError{Path: path,
+					Message: fmt.Sprintf("value %q does not match pattern %s", s, pattern)})
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range stringList(schema["required"]) {
+			if _, ok := v[name]; !ok {
+				*errs = append(*errs, SchemaError{Path: path, Message: fmt.Sprintf("missing required field %q", name)})
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propValue, ok := v[name]
+			if !ok {
+				continue
+			}
+			propSchema, _ := properties[name].(map[string]interface{})
+			validateValue(propValue, propSchema, joinPath(path, name), errs)
+		}
+	case []interface{}:
+		items, _ := schema["items"].(map[string]interface{})
+		for i, item := range v {
+			validateValue(item, items, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// joinPath appends a field name to a path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// matchesAnyType returns true if value has one of the JSON types
+func matchesAnyType(value interface{}, types []string) bool {
+	for _, t := range types {
+		if matchesType(value, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesType returns true if value has the JSON type t. Unknown types match any value.
+func matchesType(value interface{}, t string) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "integer":
+		f, ok := toFloat(value)
+		return ok && f == math.Trunc(f)
+	default:
+		return true
+	}
+}
+
+// jsonTypeOf returns the JSON type name of a decoded value
+func jsonTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	}
+	if _, ok := toFloat(value); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// toFloat converts a numeric value to float64
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// stringList returns a keyword value given as a string or a list of strings
+func stringList(v interface{}) []string {
+	switch list := v.(type) {
+	case string:
+		return []string{list}
+	case []string:
+		return list
+	case []interface{}:
+		result := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// enumValues returns the allowed values of an enum keyword
+func enumValues(v interface{}) []interface{} {
+	switch list := v.(type) {
+	case []interface{}:
+		return list
+	case []string:
+		result := make([]interface{}, len(list))
+		for i, s := range list {
+			result[i] = s
+		}
+		return result
+	}
+	return nil
+}
+
+// containsValue returns true if value equals one of the scalar values
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+		if a, ok := toFloat(v); ok {
+			if b, ok := toFloat(value); ok && a == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// formatValue formats a value for error messages
+func formatValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// formatValues formats a list of values for error messages
+func formatValues(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = formatValue(v)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
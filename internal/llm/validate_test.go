@@ -0,0 +1,107 @@
+package llm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testOutputSchema mirrors the shape of the review output schema
+func testOutputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []string{"summary", "findings"},
+		"properties": map[string]interface{}{
+			"summary": map[string]interface{}{"type": "string"},
+			"findings": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type":     "object",
+					"required": []string{"severity", "title"},
+					"properties": map[string]interface{}{
+						"severity": map[string]interface{}{"type": "string", "enum": []string{"high", "low"}},
+						"title":    map[string]interface{}{"type": "string"},
+						"category": map[string]interface{}{"type": "string", "pattern": "^[a-z-]+$"},
+						"count":    map[string]interface{}{"type": "integer"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func decodeJSON(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+	return v
+}
+
+func TestValidateSchema(t *testing.T) {
+	schema := testOutputSchema()
+
+	tests := []struct {
+		name   string
+		input  string
+		errors []string
+	}{
+		{
+			name:  "valid",
+			input: `{"summary":"ok","findings":[{"severity":"high","title":"t","category":"sql-injection","count":2}]}`,
+		},
+		{
+			name:   "missing required fields",
+			input:  `{"findings":[{"title":"t"}]}`,
+			errors: []string{`missing required field "summary"`, `findings[0]: missing required field "severity"`},
+		},
+		{
+			name:   "invalid enum value",
+			input:  `{"summary":"ok","findings":[{"severity":"urgent","title":"t"}]}`,
+			errors: []string{`findings[0].severity: value "urgent" is not one of ["high", "low"]`},
+		},
+		{
+			name:   "pattern mismatch",
+			input:  `{"summary":"ok","findings":[{"severity":"low","title":"t","category":"SQL Injection"}]}`,
+			errors: []string{`findings[0].category: value "SQL Injection" does not match pattern ^[a-z-]+$`},
+		},
+		{
+			name:   "wrong types",
+			input:  `{"summary":1,"findings":[{"severity":"low","title":"t","count":1.5}]}`,
+			errors: []string{"findings[0].count: expected integer, got number", "summary: expected string, got number"},
+		},
+		{
+			name:   "wrong root type",
+			input:  `[]`,
+			errors: []string{"expected object, got array"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateSchema(decodeJSON(t, tt.input), schema)
+			messages := make([]string, len(errs))
+			for i, err := range errs {
+				messages[i] = err.Error()
+			}
+			if len(tt.errors) == 0 {
+				assert.Empty(t, messages)
+				return
+			}
+			assert.Equal(t, tt.errors, messages)
+		})
+	}
+}
+
+func TestValidateSchema_DecodedSchema(t *testing.T) {
+	// Schemas read from JSON use []interface{} for keyword lists
+	data, err := json.Marshal(testOutputSchema())
+	require.NoError(t, err)
+	schema := decodeJSON(t, string(data)).(map[string]interface{})
+
+	errs := ValidateSchema(decodeJSON(t, `{"summary":"ok","findings":[{"severity":"none"}]}`), schema)
+	require.Len(t, errs, 2)
+	assert.Equal(t, `findings[0]: missing required field "title"`, errs[0].Error())
+	assert.Equal(t, "findings[0].severity", errs[1].Path)
+}
@@ -0,0 +1,30 @@
+// Package model defines the data models for the application.
+package model
+
+import (
+	"time"
+)
+
+// CustomArea is an organization-wide review area defined via the admin API.
+// Custom areas can be used in goals.areas of any review file, like built-in areas.
+type CustomArea struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// AreaID is the area identifier used in review files (kebab-case, e.g. "pci-dss-logging")
+	AreaID string `gorm:"size:100;not null;uniqueIndex" json:"area_id"`
+
+	// Group is the area group (e.g. "compliance"), defaults to "custom"
+	Group string `gorm:"size:100;not null" json:"group"`
+
+	// Description is the area description used in review prompts
+	Description string `gorm:"size:1024;not null" json:"description"`
+}
+
+// AreaAllModels returns all area-related models for auto-migration
+func AreaAllModels() []interface{} {
+	return []interface{}{
+		&CustomArea{},
+	}
+}
@@ -0,0 +1,41 @@
+// Package model defines the data models for the application.
+package model
+
+import (
+	"time"
+)
+
+// ResponseCacheEntry is a cached agent result. CacheKey is a SHA-256 hash of everything that
+// determines the agent's answer: rendered prompt, agent, model, head commit SHA and output schema.
+type ResponseCacheEntry struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// CacheKey is the hex-encoded cache key
+	CacheKey string `gorm:"size:64;not null;uniqueIndex" json:"cache_key"`
+
+	// Execution the result was produced for (used to purge entries)
+	RepoURL   string `gorm:"size:512;index" json:"repo_url"`
+	RuleID    string `gorm:"size:255;index" json:"rule_id"`
+	CommitSHA string `gorm:"size:64" json:"commit_sha"`
+
+	// Parsed agent result
+	AgentName  string                 `gorm:"size:100" json:"agent_name"`
+	ModelName  string                 `gorm:"size:255" json:"model_name,omitempty"`
+	Text       string                 `gorm:"type:text" json:"text,omitempty"`
+	Data       JSONMap                `gorm:"type:json" json:"data,omitempty"`
+	Validation OutputValidationStatus `gorm:"size:20" json:"validation,omitempty"`
+
+	// ExpiresAt is the time after which the entry is no longer used
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+
+	// Hits counts the executions served from this entry
+	Hits int `gorm:"default:0" json:"hits"`
+}
+
+// CacheAllModels returns all cache-related models for auto-migration
+func CacheAllModels() []interface{} {
+	return []interface{}{
+		&ResponseCacheEntry{},
+	}
+}
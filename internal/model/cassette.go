@@ -0,0 +1,128 @@
+// Package model defines the data models for the application.
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// CassetteVersion is the format version of downloaded cassettes
+const CassetteVersion = 1
+
+// CassetteChunk is a recorded stream chunk of an agent execution (see llm.StreamChunk)
+type CassetteChunk struct {
+	Type       string            `json:"type"`
+	Content    string            `json:"content,omitempty"`
+	Delta      string            `json:"delta,omitempty"`
+	IsComplete bool              `json:"is_complete,omitempty"`
+	ToolName   string            `json:"tool_name,omitempty"`
+	ToolInput  string            `json:"tool_input,omitempty"`
+	ToolOutput string            `json:"tool_output,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+// CassetteChunks is a custom type for storing recorded stream chunks in SQLite
+type CassetteChunks []CassetteChunk
+
+// Value implements driver.Valuer interface
+func (c CassetteChunks) Value() (driver.Value, error) {
+	if len(c) == 0 {
+		return "[]", nil
+	}
+	data, err := json.Marshal(c)
+	return string(data), err
+}
+
+// Scan implements sql.Scanner interface
+func (c *CassetteChunks) Scan(value interface{}) error {
+	if value == nil {
+		*c = CassetteChunks{}
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+// CassetteInteraction is a recorded agent execution: the exact prompt sent to the agent,
+// the raw stream of chunks it answered with (including tool calls) and its final response.
+// Interactions are recorded when recording is enabled in the review settings.
+type CassetteInteraction struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Execution the interaction belongs to
+	ReviewID string `gorm:"size:20;not null;index" json:"review_id"`
+	RuleID   string `gorm:"size:255;not null" json:"rule_id"` // rule.id from DSL ("<rule>-merge" for merges of multi-run results)
+	RunIndex int    `gorm:"default:0" json:"run_index"`       // ReviewRuleRun index (0 for single runs)
+
+	// Agent and model: the requested model (empty for the agent's default) and the one reported
+	Agent          string `gorm:"size:100;not null" json:"agent"`
+	RequestedModel string `gorm:"size:255" json:"requested_model,omitempty"`
+	Model          string `gorm:"size:255" json:"model,omitempty"`
+
+	// Workspace and change under review
+	RepoURL       string      `gorm:"size:512" json:"repo_url"`
+	Ref           string      `gorm:"size:255" json:"ref,omitempty"`
+	CommitSHA     string      `gorm:"size:64" json:"commit_sha"`
+	PRNumber      int         `json:"pr_number,omitempty"`
+	PRTitle       string      `gorm:"type:text" json:"pr_title,omitempty"`
+	PRDescription string      `gorm:"type:text" json:"pr_description,omitempty"`
+	ChangedFiles  StringArray `gorm:"type:json" json:"changed_files,omitempty"`
+
+	// Prompt is the exact prompt sent to the agent
+	Prompt string `gorm:"type:text" json:"prompt"`
+
+	// Chunks is the raw stream of the execution
+	Chunks CassetteChunks `gorm:"type:json" json:"chunks"`
+
+	// Final response
+	Content   string `gorm:"type:text" json:"content,omitempty"`
+	SessionID string `gorm:"size:255" json:"session_id,omitempty"`
+	TokenUsage
+
+	// Error of a failed execution; Retryable tells whether the executor retried it
+	Error     string `gorm:"type:text" json:"error,omitempty"`
+	Retryable bool   `gorm:"default:false" json:"retryable,omitempty"`
+
+	Duration int64 `json:"duration"` // milliseconds
+}
+
+// CassetteRule is the configuration snapshot of a recorded rule
+type CassetteRule struct {
+	RuleID     string  `json:"rule_id"`
+	RuleConfig JSONMap `json:"rule_config"`
+}
+
+// Cassette is the downloadable recording of a review: the reviewed change, the rule
+// configurations and every agent interaction in execution order. It is replayed offline
+// with `verustcode replay`.
+type Cassette struct {
+	Version       int    `json:"version"`
+	ReviewID      string `json:"review_id"`
+	RepoURL       string `json:"repo_url"`
+	Ref           string `json:"ref"`
+	CommitSHA     string `json:"commit_sha"`
+	BaseCommitSHA string `json:"base_commit_sha,omitempty"`
+	PRNumber      int    `json:"pr_number,omitempty"`
+	PRTitle       string `json:"pr_title,omitempty"`
+	PRDescription string `json:"pr_description,omitempty"`
+	Author        string `json:"author,omitempty"`
+
+	ChangedFiles []string              `json:"changed_files,omitempty"`
+	Rules        []CassetteRule        `json:"rules"`
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+// CassetteAllModels returns all cassette-related models for auto-migration
+func CassetteAllModels() []interface{} {
+	return []interface{}{
+		&CassetteInteraction{},
+	}
+}
@@ -0,0 +1,76 @@
+// Package model defines the data models for the application.
+package model
+
+import (
+	"time"
+)
+
+// EvalRun is the stored result of evaluating one configuration (agent and model) of a set of
+// rules against a labeled dataset with `verustcode eval run`. Runs of the same dataset are
+// compared with `verustcode eval list`.
+type EvalRun struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Dataset is the name of the evaluated dataset
+	Dataset string `gorm:"size:255;not null;index" json:"dataset"`
+
+	// Configuration is the name of the evaluated configuration; Agent and Model override
+	// the rules' agent and model (empty = as configured in the review file)
+	Configuration string      `gorm:"size:255;not null;index" json:"configuration"`
+	Agent         string      `gorm:"size:100" json:"agent,omitempty"`
+	Model         string      `gorm:"size:255" json:"model,omitempty"`
+	Rules         StringArray `gorm:"type:json" json:"rules"`
+
+	// Cases is the number of evaluated cases; FailedCases counts the cases whose review failed
+	Cases       int `gorm:"default:0" json:"cases"`
+	FailedCases int `gorm:"default:0" json:"failed_cases"`
+
+	// Matching of predicted findings to expected findings over all cases
+	TruePositives  int     `gorm:"default:0" json:"true_positives"`
+	FalsePositives int     `gorm:"default:0" json:"false_positives"`
+	FalseNegatives int     `gorm:"default:0" json:"false_negatives"`
+	Precision      float64 `gorm:"default:0" json:"precision"`
+	Recall         float64 `gorm:"default:0" json:"recall"`
+	F1             float64 `gorm:"default:0" json:"f1"`
+
+	// Token usage and estimated cost of all agent calls
+	TokenUsage
+
+	// Latency is the mean review duration of a case; Duration is the total (milliseconds)
+	Latency  int64 `json:"latency"`
+	Duration int64 `json:"duration"`
+
+	// CaseResults holds the result of each case
+	CaseResults []EvalCaseResult `gorm:"foreignKey:EvalRunID" json:"case_results,omitempty"`
+}
+
+// EvalCaseResult is the result of a dataset case in an evaluation run
+type EvalCaseResult struct {
+	ID        uint   `gorm:"primarykey" json:"id"`
+	EvalRunID uint   `gorm:"not null;index" json:"eval_run_id"`
+	CaseID    string `gorm:"size:255;not null" json:"case_id"`
+
+	TruePositives  int `gorm:"default:0" json:"true_positives"`
+	FalsePositives int `gorm:"default:0" json:"false_positives"`
+	FalseNegatives int `gorm:"default:0" json:"false_negatives"`
+
+	// Missed lists the expected findings no prediction matched; Unexpected lists the
+	// predictions that matched no expected finding (as "file:start-end title")
+	Missed     StringArray `gorm:"type:json" json:"missed,omitempty"`
+	Unexpected StringArray `gorm:"type:json" json:"unexpected,omitempty"`
+
+	TokenUsage
+	Duration int64 `json:"duration"` // milliseconds
+
+	// Error is the failure of the case's review or of a rule
+	Error string `gorm:"type:text" json:"error,omitempty"`
+}
+
+// EvalAllModels returns all evaluation models for auto-migration
+func EvalAllModels() []interface{} {
+	return []interface{}{
+		&EvalRun{},
+		&EvalCaseResult{},
+	}
+}
@@ -0,0 +1,83 @@
+// Package model defines the data models for the application.
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// InjectionAction controls what happens to reviews of a repository whose PR-supplied content
+// (title, description, commit messages, changed files) looks like a prompt injection
+type InjectionAction string
+
+const (
+	// InjectionActionWarn runs the review unchanged and adds a warning to its comments
+	InjectionActionWarn InjectionAction = "warn"
+	// InjectionActionStrip removes the suspicious text of the PR title and description from the prompts
+	// and adds a warning to its comments; detections in commit messages or files quarantine the review
+	InjectionActionStrip InjectionAction = "strip"
+	// InjectionActionQuarantine holds the review until an administrator approves it
+	InjectionActionQuarantine InjectionAction = "quarantine"
+)
+
+// DefaultInjectionAction is the action of repositories without a configured action
+const DefaultInjectionAction = InjectionActionWarn
+
+// IsValid returns true if a is a known injection action
+func (a InjectionAction) IsValid() bool {
+	switch a {
+	case InjectionActionWarn, InjectionActionStrip, InjectionActionQuarantine:
+		return true
+	}
+	return false
+}
+
+// Sources of screened PR-supplied content
+const (
+	InjectionSourcePRTitle       = "pr_title"
+	InjectionSourcePRDescription = "pr_description"
+	InjectionSourceCommitMessage = "commit_message"
+	InjectionSourceFile          = "file"
+)
+
+// InjectionDetectorClassifier is the detector of detections reported by the classifier model
+const InjectionDetectorClassifier = "classifier"
+
+// InjectionDetection is a suspected prompt injection in PR-supplied content
+type InjectionDetection struct {
+	Source   string `json:"source"`             // see InjectionSource* constants
+	Location string `json:"location,omitempty"` // commit SHA or file path
+	Detector string `json:"detector"`           // pattern set name or "classifier"
+	Pattern  string `json:"pattern,omitempty"`  // matching regular expression (pattern sets)
+	Excerpt  string `json:"excerpt"`            // matched text, or the start of the content (classifier)
+	Reason   string `json:"reason,omitempty"`   // explanation of the classifier
+	Stripped bool   `json:"stripped,omitempty"` // removed from the prompts by the strip action
+}
+
+// InjectionDetections is a custom type for storing prompt injection detections in SQLite
+type InjectionDetections []InjectionDetection
+
+// Value implements driver.Valuer interface
+func (d InjectionDetections) Value() (driver.Value, error) {
+	if len(d) == 0 {
+		return "[]", nil
+	}
+	data, err := json.Marshal(d)
+	return string(data), err
+}
+
+// Scan implements sql.Scanner interface
+func (d *InjectionDetections) Scan(value interface{}) error {
+	if value == nil {
+		*d = InjectionDetections{}
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	}
+	return json.Unmarshal(bytes, d)
+}
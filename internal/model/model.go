@@ -75,6 +75,10 @@ const (
 	ReviewStatusCompleted ReviewStatus = "completed"
 	ReviewStatusFailed    ReviewStatus = "failed"
 	ReviewStatusCancelled ReviewStatus = "cancelled"
+
+	// ReviewStatusQuarantined means a possible prompt injection was detected and the
+	// review waits for an administrator to approve it
+	ReviewStatusQuarantined ReviewStatus = "quarantined"
 )
 
 // Review represents a code review task
@@ -85,21 +89,30 @@ type Review struct {
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Review identification
-	Ref       string `gorm:"size:255;not null" json:"ref"`                                             // branch, tag, or commit
-	CommitSHA string `gorm:"size:64;index;uniqueIndex:idx_pr_url_commit,priority:2" json:"commit_sha"` // commit hash
-	PRNumber  int    `gorm:"index" json:"pr_number,omitempty"`                                         // PR/MR number if applicable
+	Ref       string `gorm:"size:255;not null" json:"ref"`                                                     // branch, tag, or commit
+	CommitSHA string `gorm:"size:64;index;uniqueIndex:idx_reviews_pr_url_commit,priority:2" json:"commit_sha"` // commit hash
+	PRNumber  int    `gorm:"index" json:"pr_number,omitempty"`                                                 // PR/MR number if applicable
 
 	// PR information
-	PRURL string `gorm:"size:512;uniqueIndex:idx_pr_url_commit,priority:1" json:"pr_url,omitempty"` // PR/MR URL (unique with commit SHA)
+	// The unique index only covers PR reviews, so non-PR reviews (push, API, schedule) may share a commit
+	PRURL string `gorm:"size:512;uniqueIndex:idx_reviews_pr_url_commit,priority:1,where:pr_url <> ''" json:"pr_url,omitempty"` // PR/MR URL (unique with commit SHA)
 
 	// Repository information (no longer linked to repositories table)
 	RepoURL  string `gorm:"size:512;not null;index" json:"repo_url"` // full repository URL
 	RepoPath string `gorm:"size:1024" json:"repo_path"`              // local workspace path
 
 	// Source information
-	Source      string `gorm:"size:50;not null;default:cli" json:"source"` // "cli" or "webhook"
+	Source      string `gorm:"size:50;not null;default:cli" json:"source"` // "cli", "webhook", "api" or "schedule"
 	TriggeredBy string `gorm:"size:255" json:"triggered_by,omitempty"`     // trigger source (for webhook)
 
+	// Review scope
+	BaseCommitSHA string `gorm:"size:64" json:"base_commit_sha,omitempty"` // start of the reviewed range (base..commit_sha)
+	ReviewFile    string `gorm:"size:255" json:"review_file,omitempty"`    // review file override, e.g. "security.yaml"
+
+	// Effective review configuration
+	ConfigSource     string      `gorm:"size:50" json:"config_source,omitempty"`       // see ConfigSource* constants
+	ConfigRejections StringArray `gorm:"type:json" json:"config_rejections,omitempty"` // in-repository overrides rejected by the trust policy
+
 	// Status and progress
 	Status           ReviewStatus `gorm:"size:50;not null;default:pending;index" json:"status"`
 	CurrentRuleIndex int          `gorm:"default:0" json:"current_rule_index"`   // current rule index (0-based)
@@ -124,6 +137,15 @@ type Review struct {
 	LinesDeleted int `gorm:"default:0" json:"lines_deleted"`
 	FilesChanged int `gorm:"default:0" json:"files_changed"`
 
+	// Token usage and cost of all rules (updated as rules finish)
+	TokenUsage
+
+	// Prompt injection screening of the PR-supplied content (audit trail)
+	InjectionDetections InjectionDetections `gorm:"type:json" json:"injection_detections,omitempty"` // suspected injections of the last screening
+	InjectionAction     InjectionAction     `gorm:"size:50" json:"injection_action,omitempty"`       // action taken on the detections
+	InjectionApprovedBy string              `gorm:"size:255" json:"injection_approved_by,omitempty"` // administrator who released the quarantined review
+	InjectionApprovedAt *time.Time          `json:"injection_approved_at,omitempty"`
+
 	// Error handling
 	ErrorMessage string `gorm:"type:text" json:"error_message,omitempty"`
 
@@ -131,6 +153,42 @@ type Review struct {
 	Rules []ReviewRule `gorm:"foreignKey:ReviewID" json:"rules,omitempty"`
 }
 
+// Sources of the effective review configuration of a review
+const (
+	ConfigSourceReviewFile = "review_file" // review file pinned on the review (e.g. by a schedule)
+	ConfigSourceServer     = "server"      // repository review file or default.yaml
+	ConfigSourceBaseBranch = "base_branch" // .verust-review.yaml of the base commit
+	ConfigSourceMerged     = "merged"      // .verust-review.yaml of the head commit merged under the server config
+)
+
+// InRepoConfigPolicy controls how a repository's own .verust-review.yaml is trusted.
+// The file is read from the reviewed change, so its author could otherwise rewrite
+// the rules that review it.
+type InRepoConfigPolicy string
+
+const (
+	// InRepoConfigIgnore ignores .verust-review.yaml and always uses the server config
+	InRepoConfigIgnore InRepoConfigPolicy = "ignore"
+	// InRepoConfigBaseBranch reads .verust-review.yaml from the base commit only,
+	// so changes to it take effect once merged
+	InRepoConfigBaseBranch InRepoConfigPolicy = "base_branch"
+	// InRepoConfigMerge merges .verust-review.yaml of the change under the server config;
+	// locked server rules can't be removed or weakened
+	InRepoConfigMerge InRepoConfigPolicy = "merge"
+)
+
+// DefaultInRepoConfigPolicy is the policy of repositories without a configured policy
+const DefaultInRepoConfigPolicy = InRepoConfigBaseBranch
+
+// IsValid returns true if p is a known policy
+func (p InRepoConfigPolicy) IsValid() bool {
+	switch p {
+	case InRepoConfigIgnore, InRepoConfigBaseBranch, InRepoConfigMerge:
+		return true
+	}
+	return false
+}
+
 // RuleStatus represents the status of a review rule
 type RuleStatus string
 
@@ -165,8 +223,26 @@ type ReviewRule struct {
 	MultiRunRuns    int  `gorm:"default:1" json:"multi_run_runs"`    // total number of runs
 	CurrentRunIndex int  `gorm:"default:0" json:"current_run_index"` // current run index (0-based)
 
+	// Chunking progress (large change sets split into several agent calls)
+	ChunkCount        int `gorm:"default:0" json:"chunk_count"`         // total number of chunks (0 = not chunked)
+	CurrentChunkIndex int `gorm:"default:0" json:"current_chunk_index"` // current chunk index (0-based)
+
 	// Results
-	FindingsCount int `gorm:"default:0" json:"findings_count"` // number of findings
+	FindingsCount   int `gorm:"default:0" json:"findings_count"`   // number of findings
+	SuppressedCount int `gorm:"default:0" json:"suppressed_count"` // number of findings suppressed by directives or the baseline
+
+	// Model reported by the agent and token usage of all agent calls (runs, chunks and merges)
+	Model string `gorm:"size:255" json:"model,omitempty"`
+	TokenUsage
+
+	// Cached is true if the agent result was served from the response cache
+	Cached bool `gorm:"default:false" json:"cached"`
+
+	// Validation of the structured agent output against the rule's JSON Schema
+	OutputValidation OutputValidationStatus `gorm:"size:20" json:"output_validation,omitempty"`
+	OutputRepairs    int                    `gorm:"default:0" json:"output_repairs"`          // repair prompts sent
+	OutputDropped    int                    `gorm:"default:0" json:"output_dropped"`          // invalid findings dropped
+	OutputErrors     string                 `gorm:"type:text" json:"output_errors,omitempty"` // validation errors, one per line
 
 	// Prompt stores the rendered prompt text used for execution
 	Prompt string `gorm:"type:text" json:"prompt,omitempty"`
@@ -179,15 +255,19 @@ type ReviewRule struct {
 	// Error handling
 	ErrorMessage string `gorm:"type:text" json:"error_message,omitempty"`
 
+	// SkipReason explains why the rule was skipped (e.g. its when clause did not match)
+	SkipReason string `gorm:"type:text" json:"skip_reason,omitempty"`
+
 	// Retry
 	RetryCount int `gorm:"default:0" json:"retry_count"` // number of retry attempts for this rule
 
 	// Relations
 	// Note: Not using OnDelete:CASCADE to avoid SQLite migration issues
 	// Soft delete is used for all records, physical deletion should be handled explicitly
-	Review  Review          `json:"-"`
-	Runs    []ReviewRuleRun `gorm:"foreignKey:ReviewRuleID" json:"runs,omitempty"`
-	Results []ReviewResult  `gorm:"foreignKey:ReviewRuleID" json:"results,omitempty"`
+	Review  Review            `json:"-"`
+	Runs    []ReviewRuleRun   `gorm:"foreignKey:ReviewRuleID" json:"runs,omitempty"`
+	Chunks  []ReviewRuleChunk `gorm:"foreignKey:ReviewRuleID" json:"chunks,omitempty"`
+	Results []ReviewResult    `gorm:"foreignKey:ReviewRuleID" json:"results,omitempty"`
 }
 
 // RunStatus represents the status of a review rule run
@@ -221,6 +301,48 @@ type ReviewRuleRun struct {
 	// Results
 	FindingsCount int `gorm:"default:0" json:"findings_count"` // number of findings
 
+	// Token usage and cost of the run
+	TokenUsage
+
+	// Timing
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Duration    int64      `json:"duration,omitempty"` // milliseconds
+
+	// Error handling
+	ErrorMessage string `gorm:"type:text" json:"error_message,omitempty"`
+
+	// Relations
+	// Note: Not using OnDelete:CASCADE to avoid SQLite migration issues
+	ReviewRule ReviewRule `json:"-"`
+}
+
+// ReviewRuleChunk represents a single chunk in chunked execution.
+// A chunk reviews a subset of the changed files; chunk results are merged into the rule result.
+type ReviewRuleChunk struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Association
+	ReviewRuleID uint `gorm:"not null;index" json:"review_rule_id"`
+	ChunkIndex   int  `gorm:"not null" json:"chunk_index"` // chunk number (0-based)
+
+	// Chunk scope
+	Files     string `gorm:"type:text" json:"files"`         // newline-separated file paths
+	FileCount int    `gorm:"default:0" json:"file_count"`    // number of files in this chunk
+	DiffLines int    `gorm:"default:0" json:"diff_lines"`    // added + deleted lines in this chunk
+	Agent     string `gorm:"size:100;not null" json:"agent"` // agent used
+
+	// Execution status
+	Status RunStatus `gorm:"size:50;not null;default:pending;index" json:"status"`
+
+	// Results
+	FindingsCount int     `gorm:"default:0" json:"findings_count"` // number of findings
+	Data          JSONMap `gorm:"type:json" json:"-"`              // chunk result, reused when recovering
+	Text          string  `gorm:"type:text" json:"-"`              // raw chunk output, reused when recovering
+
 	// Timing
 	StartedAt   *time.Time `json:"started_at,omitempty"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
@@ -304,6 +426,17 @@ type RepositoryReviewConfig struct {
 	// Review configuration
 	ReviewFile string `gorm:"size:255" json:"review_file"` // associated review file name, e.g. "frontend.yaml"
 
+	// InRepoConfigPolicy controls how .verust-review.yaml in the repository is trusted
+	InRepoConfigPolicy InRepoConfigPolicy `gorm:"size:50;not null;default:base_branch" json:"in_repo_config_policy"`
+
+	// Monthly cost budget (reviews and reports of the current calendar month)
+	MonthlyBudget float64      `gorm:"default:0" json:"monthly_budget"`        // USD, 0 = no budget
+	BudgetAction  BudgetAction `gorm:"size:50" json:"budget_action,omitempty"` // block or downgrade (default: block)
+	BudgetModel   string       `gorm:"size:255" json:"budget_model,omitempty"` // model used by downgrade
+
+	// InjectionAction is applied to reviews whose PR-supplied content looks like a prompt injection
+	InjectionAction InjectionAction `gorm:"size:50" json:"injection_action,omitempty"` // warn, strip or quarantine (default: warn)
+
 	// Metadata
 	Description string `gorm:"size:1024" json:"description,omitempty"` // optional description
 }
@@ -314,6 +447,7 @@ func AllModels() []interface{} {
 		&Review{},
 		&ReviewRule{},
 		&ReviewRuleRun{},
+		&ReviewRuleChunk{},
 		&ReviewResult{},
 		&ReviewResultWebhookLog{},
 		&RepositoryReviewConfig{},
@@ -322,6 +456,16 @@ func AllModels() []interface{} {
 	models = append(models, ReportAllModels()...)
 	// Add settings models
 	models = append(models, SettingsAllModels()...)
+	// Add schedule models
+	models = append(models, ScheduleAllModels()...)
+	// Add custom area models
+	models = append(models, AreaAllModels()...)
+	// Add response cache models
+	models = append(models, CacheAllModels()...)
+	// Add recorded agent interaction models
+	models = append(models, CassetteAllModels()...)
+	// Add evaluation models
+	models = append(models, EvalAllModels()...)
 	return models
 }
 
@@ -394,4 +538,6 @@ type RepoStats struct {
 	RevisionStats      []WeeklyRevision     `json:"revision_stats"`
 	IssueSeverityStats []IssueSeverityStats `json:"issue_severity_stats"` // issues grouped by severity
 	IssueCategoryStats []IssueCategoryStats `json:"issue_category_stats"` // issues grouped by category
+	UsageStats         []WeeklyUsage        `json:"usage_stats"`          // token usage and cost per week
+	TotalUsage         UsageStats           `json:"total_usage"`          // token usage and cost of the time range
 }
@@ -107,6 +107,10 @@ type ReportSection struct {
 	// Short summary used as input for Phase 3 report summary generation
 	Summary string `gorm:"type:text" json:"summary,omitempty"`
 
+	// Model reported by the agent and token usage of the section generation
+	Model string `gorm:"size:255" json:"model,omitempty"`
+	TokenUsage
+
 	// Execution status
 	Status SectionStatus `gorm:"size:50;not null;default:pending;index" json:"status"`
 
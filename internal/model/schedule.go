@@ -0,0 +1,93 @@
+// Package model defines the data models for the application.
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ScheduleMode defines what a scheduled audit reviews
+type ScheduleMode string
+
+const (
+	// ScheduleModeFull reviews the whole repository tree at the branch head
+	ScheduleModeFull ScheduleMode = "full"
+	// ScheduleModeIncremental reviews everything changed since the last scheduled run
+	ScheduleModeIncremental ScheduleMode = "incremental"
+)
+
+// ScheduleRunStatus represents the outcome of a schedule trigger
+type ScheduleRunStatus string
+
+const (
+	ScheduleRunStatusSubmitted ScheduleRunStatus = "submitted" // review created and queued
+	ScheduleRunStatusSkipped   ScheduleRunStatus = "skipped"   // previous scheduled review still in progress
+	ScheduleRunStatusFailed    ScheduleRunStatus = "failed"    // review could not be created or queued
+)
+
+// ReviewSchedule defines a cron-style scheduled audit of a repository branch.
+// Each trigger creates a Review with Source "schedule".
+type ReviewSchedule struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Name is a human-readable schedule name
+	Name string `gorm:"size:255;not null" json:"name"`
+
+	// Target repository and branch
+	RepoURL string `gorm:"size:512;not null;index" json:"repo_url"` // full repository URL
+	Ref     string `gorm:"size:255;not null" json:"ref"`            // branch to audit, e.g. "main"
+
+	// CronExpr is a standard 5-field cron expression (CRON_TZ= prefix supported), e.g. "0 2 * * 1"
+	CronExpr string `gorm:"size:255;not null" json:"cron_expr"`
+
+	// ReviewFile overrides the review file, e.g. "security.yaml".
+	// Empty means the repository's configured review file (or default.yaml).
+	ReviewFile string `gorm:"size:255" json:"review_file,omitempty"`
+
+	// Mode is either "full" or "incremental"
+	Mode ScheduleMode `gorm:"size:50;not null;default:full" json:"mode"`
+
+	// Enabled controls whether the schedule is registered with the scheduler
+	Enabled bool `gorm:"not null" json:"enabled"`
+
+	// Metadata
+	Description string `gorm:"size:1024" json:"description,omitempty"`
+
+	// Last trigger information
+	LastRunAt    *time.Time `json:"last_run_at,omitempty"`
+	LastReviewID string     `gorm:"size:20" json:"last_review_id,omitempty"`
+}
+
+// ReviewScheduleRun records a single trigger of a ReviewSchedule
+type ReviewScheduleRun struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Association
+	ScheduleID uint   `gorm:"not null;index" json:"schedule_id"`
+	ReviewID   string `gorm:"size:20;index" json:"review_id,omitempty"` // empty when skipped or failed
+
+	// TriggeredAt is when the schedule fired
+	TriggeredAt time.Time `json:"triggered_at"`
+
+	// BaseCommitSHA is the start of the reviewed range for incremental runs
+	BaseCommitSHA string `gorm:"size:64" json:"base_commit_sha,omitempty"`
+
+	// Outcome
+	Status       ScheduleRunStatus `gorm:"size:50;not null;index" json:"status"`
+	ErrorMessage string            `gorm:"type:text" json:"error_message,omitempty"`
+}
+
+// ScheduleAllModels returns all schedule-related models for auto-migration
+func ScheduleAllModels() []interface{} {
+	return []interface{}{
+		&ReviewSchedule{},
+		&ReviewScheduleRun{},
+	}
+}
@@ -18,6 +18,10 @@ const (
 	SettingCategoryReview        SettingCategory = "review"        // Review process settings
 	SettingCategoryReport        SettingCategory = "report"        // Report generation settings
 	SettingCategoryNotifications SettingCategory = "notifications" // Notification settings
+
+	// SettingCategoryQueue holds internal queue state (paused repositories, maintenance mode).
+	// It is managed by the engine and is not part of AllSettingCategories.
+	SettingCategoryQueue SettingCategory = "queue"
 )
 
 // AllSettingCategories returns all valid setting categories
@@ -0,0 +1,81 @@
+// Package model defines the data models for the application.
+package model
+
+import "time"
+
+// TokenUsage records the token usage and estimated cost of agent executions.
+// It is embedded in reviews, review rules, rule runs and report sections.
+type TokenUsage struct {
+	PromptTokens     int     `gorm:"default:0" json:"prompt_tokens"`     // prompt tokens, including cached tokens
+	CompletionTokens int     `gorm:"default:0" json:"completion_tokens"` // completion tokens
+	CachedTokens     int     `gorm:"default:0" json:"cached_tokens"`     // prompt tokens read from the provider's prompt cache
+	Cost             float64 `gorm:"default:0" json:"cost"`              // estimated cost in USD
+}
+
+// Add adds other to the usage
+func (u *TokenUsage) Add(other TokenUsage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.CachedTokens += other.CachedTokens
+	u.Cost += other.Cost
+}
+
+// TotalTokens returns prompt plus completion tokens
+func (u TokenUsage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// UsageRecord is the token usage of a single review rule or report section,
+// as loaded for cost accounting
+type UsageRecord struct {
+	RepoURL   string
+	CreatedAt time.Time
+	TokenUsage
+}
+
+// BudgetAction controls what happens to reviews of a repository whose monthly budget is exceeded
+type BudgetAction string
+
+const (
+	// BudgetActionBlock fails rules until the next month
+	BudgetActionBlock BudgetAction = "block"
+	// BudgetActionDowngrade runs rules with the repository's budget model instead
+	BudgetActionDowngrade BudgetAction = "downgrade"
+)
+
+// DefaultBudgetAction is the action of repositories with a budget but without a configured action
+const DefaultBudgetAction = BudgetActionBlock
+
+// IsValid returns true if a is a known budget action
+func (a BudgetAction) IsValid() bool {
+	switch a {
+	case BudgetActionBlock, BudgetActionDowngrade:
+		return true
+	}
+	return false
+}
+
+// UsageStats contains aggregated token usage and cost
+type UsageStats struct {
+	TokenUsage
+	TotalTokens int `json:"total_tokens"`
+}
+
+// NewUsageStats creates usage statistics from a usage total
+func NewUsageStats(u TokenUsage) UsageStats {
+	return UsageStats{TokenUsage: u, TotalTokens: u.TotalTokens()}
+}
+
+// WeeklyUsage represents token usage and cost for a week
+type WeeklyUsage struct {
+	Week string `json:"week"` // ISO week format: 2024-W01
+	UsageStats
+}
+
+// RepoUsage represents month-to-date usage and budget of a repository
+type RepoUsage struct {
+	RepoURL       string       `json:"repo_url"`
+	MonthlyBudget float64      `json:"monthly_budget,omitempty"` // USD, 0 = no budget
+	BudgetAction  BudgetAction `json:"budget_action,omitempty"`
+	UsageStats
+}
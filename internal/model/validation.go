@@ -0,0 +1,54 @@
+// Package model defines the data models for the application.
+package model
+
+// OutputValidationStatus is the outcome of validating structured agent output against the rule's JSON Schema
+type OutputValidationStatus string
+
+const (
+	// OutputValid means the output matched the schema
+	OutputValid OutputValidationStatus = "valid"
+	// OutputRepaired means the output matched the schema after repair prompts
+	OutputRepaired OutputValidationStatus = "repaired"
+	// OutputSalvaged means the output stayed invalid and only its valid findings were kept
+	OutputSalvaged OutputValidationStatus = "salvaged"
+	// OutputInvalid means no JSON could be recovered from the output
+	OutputInvalid OutputValidationStatus = "invalid"
+)
+
+// outputValidationRank orders statuses from best to worst
+var outputValidationRank = map[OutputValidationStatus]int{
+	OutputValid:    0,
+	OutputRepaired: 1,
+	OutputSalvaged: 2,
+	OutputInvalid:  3,
+}
+
+// MaxOutputValidationErrors is the number of validation errors kept per rule
+const MaxOutputValidationErrors = 20
+
+// OutputValidation records the validation of the structured output of one or more agent calls
+type OutputValidation struct {
+	Status  OutputValidationStatus `json:"status"`
+	Repairs int                    `json:"repairs"`          // repair prompts sent
+	Dropped int                    `json:"dropped"`          // invalid findings dropped by salvaging
+	Errors  []string               `json:"errors,omitempty"` // validation errors of the invalid outputs
+}
+
+// Merge adds the validation of another agent call (a run, chunk or merge of the same rule).
+// The status becomes the worse of both.
+func (v *OutputValidation) Merge(other *OutputValidation) {
+	if other == nil {
+		return
+	}
+	if v.Status == "" || outputValidationRank[other.Status] > outputValidationRank[v.Status] {
+		v.Status = other.Status
+	}
+	v.Repairs += other.Repairs
+	v.Dropped += other.Dropped
+	for _, err := range other.Errors {
+		if len(v.Errors) >= MaxOutputValidationErrors {
+			break
+		}
+		v.Errors = append(v.Errors, err)
+	}
+}
@@ -43,6 +43,26 @@ func (m *mockStore) RepositoryConfig() store.RepositoryConfigStore {
 	return nil
 }
 
+func (m *mockStore) Schedule() store.ScheduleStore {
+	return nil
+}
+
+func (m *mockStore) Area() store.AreaStore {
+	return nil
+}
+
+func (m *mockStore) ResponseCache() store.ResponseCacheStore {
+	return nil
+}
+
+func (m *mockStore) Cassette() store.CassetteStore {
+	return nil
+}
+
+func (m *mockStore) Eval() store.EvalStore {
+	return nil
+}
+
 func (m *mockStore) DB() *gorm.DB {
 	return nil
 }
@@ -60,6 +60,9 @@ type PublishOptions struct {
 	// PRNumber is the PR/MR number (for Git comments)
 	PRNumber int
 
+	// CommitSHA is the reviewed head commit (for commit comments when there is no PR)
+	CommitSHA string
+
 	// PRInfo contains PR/MR information (URL, title, etc.)
 	// This is populated when available from provider API
 	PRInfo *provider.PullRequest
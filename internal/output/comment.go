@@ -66,10 +66,11 @@ func (c *CommentChannel) Name() string {
 	return "comment"
 }
 
-// Publish posts the review result as a comment on the PR/MR
+// Publish posts the review result as a comment on the PR/MR.
+// Reviews without a PR (e.g. push events) are posted as a commit comment on the reviewed commit.
 func (c *CommentChannel) Publish(ctx context.Context, result *prompt.ReviewResult, opts *PublishOptions) error {
-	if opts.PRNumber == 0 {
-		logger.Warn("Comment channel: no PR number provided, skipping comment")
+	if opts.PRNumber == 0 && opts.CommitSHA == "" {
+		logger.Warn("Comment channel: no PR number or commit SHA provided, skipping comment")
 		return nil
 	}
 
@@ -123,6 +124,39 @@ func (c *CommentChannel) Publish(ctx context.Context, result *prompt.ReviewResul
 	// Generate comment body with marker
 	body := c.generateCommentBodyWithMarker(result, fullMarker, opts.MetadataConfig, opts.AgentName, opts.ModelName)
 
+	// Without a PR, post on the commit. A commit is reviewed once, so an earlier comment
+	// of the same rule (e.g. from a failed attempt before a retry) is always updated.
+	if opts.PRNumber == 0 {
+		updated, err := c.updateCommitComment(ctx, opts.Provider, owner, repo, opts.CommitSHA, fullMarker, body)
+		if err != nil {
+			return fmt.Errorf("failed to update commit comment: %w", err)
+		}
+		if updated {
+			logger.Info("Updated existing review commit comment",
+				zap.String("provider", opts.Provider.Name()),
+				zap.String("repo", fmt.Sprintf("%s/%s", owner, repo)),
+				zap.String("commit", opts.CommitSHA),
+				zap.String("reviewer_id", result.ReviewerID),
+			)
+			return nil
+		}
+
+		commentOpts := &provider.CommentOptions{
+			CommitSHA: opts.CommitSHA,
+		}
+		if err := opts.Provider.PostComment(ctx, owner, repo, commentOpts, body); err != nil {
+			return fmt.Errorf("failed to post commit comment: %w", err)
+		}
+
+		logger.Info("Posted review commit comment",
+			zap.String("provider", opts.Provider.Name()),
+			zap.String("repo", fmt.Sprintf("%s/%s", owner, repo)),
+			zap.String("commit", opts.CommitSHA),
+			zap.String("reviewer_id", result.ReviewerID),
+		)
+		return nil
+	}
+
 	// If overwrite mode, try to update existing comment instead of delete+create
 	if overwrite {
 		updated, err := c.updateOrCreateComment(ctx, opts.Provider, owner, repo, opts.PRNumber, fullMarker, body)
@@ -237,6 +271,26 @@ func (c *CommentChannel) updateOrCreateComment(ctx context.Context, prov provide
 	return true, nil
 }
 
+// updateCommitComment updates the first comment on a commit containing marker.
+// Returns false if the commit has no such comment.
+func (c *CommentChannel) updateCommitComment(ctx context.Context, prov provider.Provider, owner, repo, commitSHA, marker, body string) (bool, error) {
+	comments, err := prov.ListCommitComments(ctx, owner, repo, commitSHA)
+	if err != nil {
+		return false, err
+	}
+
+	for _, comment := range comments {
+		if !strings.Contains(comment.Body, marker) {
+			continue
+		}
+		if err := prov.UpdateCommitComment(ctx, owner, repo, commitSHA, comment.ID, body); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
 // generateCommentBodyWithMarker generates the comment body with the specified marker
 // Uses channel's Format setting to determine output format
 func (c *CommentChannel) generateCommentBodyWithMarker(result *prompt.ReviewResult, marker string, metadataConfig *config.OutputMetadataConfig, agentName, modelName string) string {
@@ -83,6 +83,19 @@ func (m *mockProvider) UpdateComment(ctx context.Context, owner, repo string, co
 	return args.Error(0)
 }
 
+func (m *mockProvider) ListCommitComments(ctx context.Context, owner, repo, commitSHA string) ([]*provider.Comment, error) {
+	args := m.Called(ctx, owner, repo, commitSHA)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*provider.Comment), args.Error(1)
+}
+
+func (m *mockProvider) UpdateCommitComment(ctx context.Context, owner, repo, commitSHA string, commentID int64, body string) error {
+	args := m.Called(ctx, owner, repo, commitSHA, commentID, body)
+	return args.Error(0)
+}
+
 func (m *mockProvider) ParseWebhook(r *http.Request, secret string) (*provider.WebhookEvent, error) {
 	args := m.Called(r, secret)
 	if args.Get(0) == nil {
@@ -198,6 +211,63 @@ func TestCommentChannel_Publish_NoPRNumber(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCommentChannel_Publish_CommitComment(t *testing.T) {
+	channel := NewCommentChannelWithConfig(true, "", "markdown")
+	mockProv := new(mockProvider)
+	mockProv.On("Name").Return("github")
+
+	result := &prompt.ReviewResult{
+		ReviewerID: "test-reviewer",
+		Data:       map[string]any{"summary": "Test summary"},
+	}
+	opts := &PublishOptions{
+		CommitSHA: "abc123",
+		RepoURL:   "https://github.com/test/repo",
+		Provider:  mockProv,
+	}
+
+	// Without an earlier comment on the commit, a new one is posted; ListComments is PR-only and must not be used
+	mockProv.On("ListCommitComments", mock.Anything, "test", "repo", "abc123").Return([]*provider.Comment{
+		{ID: 1, Body: "Nice commit"},
+	}, nil)
+	mockProv.On("PostComment", mock.Anything, "test", "repo", mock.MatchedBy(func(o *provider.CommentOptions) bool {
+		return o.CommitSHA == "abc123" && o.PRNumber == 0
+	}), mock.AnythingOfType("string")).Return(nil)
+
+	err := channel.Publish(context.Background(), result, opts)
+	assert.NoError(t, err)
+	mockProv.AssertExpectations(t)
+	mockProv.AssertNotCalled(t, "ListComments", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCommentChannel_Publish_CommitCommentUpdatesExisting(t *testing.T) {
+	// Append mode for PRs still updates the comment of an earlier attempt on the same commit
+	channel := NewCommentChannelWithConfig(false, "", "markdown")
+	mockProv := new(mockProvider)
+	mockProv.On("Name").Return("github")
+
+	result := &prompt.ReviewResult{
+		ReviewerID: "test-reviewer",
+		Data:       map[string]any{"summary": "Test summary"},
+	}
+	opts := &PublishOptions{
+		CommitSHA: "abc123",
+		RepoURL:   "https://github.com/test/repo",
+		Provider:  mockProv,
+	}
+
+	mockProv.On("ListCommitComments", mock.Anything, "test", "repo", "abc123").Return([]*provider.Comment{
+		{ID: 1, Body: "Nice commit"},
+		{ID: 2, Body: "[review_by_scopeview:test-reviewer]\n\nOld summary"},
+	}, nil)
+	mockProv.On("UpdateCommitComment", mock.Anything, "test", "repo", "abc123", int64(2), mock.AnythingOfType("string")).Return(nil)
+
+	err := channel.Publish(context.Background(), result, opts)
+	assert.NoError(t, err)
+	mockProv.AssertExpectations(t)
+	mockProv.AssertNotCalled(t, "PostComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestCommentChannel_Publish_NoProvider(t *testing.T) {
 	channel := NewCommentChannel()
 	result := &prompt.ReviewResult{
@@ -133,12 +133,22 @@ func ConvertToMarkdown(result *prompt.ReviewResult, opts *MarkdownOptions) strin
 
 	// Try to get summary from Data (LLM always returns JSON)
 	if len(result.Data) > 0 {
+		if note := injectionNote(result.Data["prompt_injection"]); note != "" {
+			sb.WriteString(note)
+			sb.WriteString("\n\n")
+		}
+
 		if summary, ok := result.Data["summary"].(string); ok && summary != "" {
 			sb.WriteString(summary)
 			sb.WriteString("\n\n")
 			hasContent = true
 		}
 
+		if note := suppressedNote(result.Data["suppressed"]); note != "" {
+			sb.WriteString(note)
+			sb.WriteString("\n\n")
+		}
+
 		// Add raw data section if enabled
 		if opts.IncludeRawData {
 			// Check if there's more than just summary
@@ -195,6 +205,94 @@ func ConvertToMarkdown(result *prompt.ReviewResult, opts *MarkdownOptions) strin
 	return sb.String()
 }
 
+// suppressedNote returns the line that reports the findings suppressed by
+// verust:ignore directives and the repository baseline, or "" if there are none
+func suppressedNote(suppressed interface{}) string {
+	items, ok := suppressed.([]interface{})
+	if !ok || len(items) == 0 {
+		return ""
+	}
+
+	bySource := make(map[string]int)
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			source, _ := m["source"].(string)
+			bySource[source]++
+		}
+	}
+
+	var parts []string
+	if n := bySource["directive"]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d by `verust:ignore` directives", n))
+	}
+	if n := bySource["baseline"]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d by the baseline", n))
+	}
+
+	noun := "findings"
+	if len(items) == 1 {
+		noun = "finding"
+	}
+	note := fmt.Sprintf("_%d %s suppressed", len(items), noun)
+	if len(parts) > 0 {
+		note += ": " + strings.Join(parts, ", ")
+	}
+	return note + "._"
+}
+
+// injectionSourceNames are the names of the sources of prompt injection detections in notes
+var injectionSourceNames = map[string]string{
+	"pr_title":       "PR title",
+	"pr_description": "PR description",
+	"commit_message": "commit message",
+	"file":           "changed file",
+}
+
+// injectionNote returns the warning about possible prompt injections in the PR-supplied
+// content, or "" if there are none. The detected text itself is not repeated.
+func injectionNote(detections interface{}) string {
+	items, ok := detections.([]interface{})
+	if !ok || len(items) == 0 {
+		return ""
+	}
+
+	var places []string
+	seen := make(map[string]bool)
+	stripped := 0
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		source, _ := m["source"].(string)
+		place := injectionSourceNames[source]
+		if place == "" {
+			place = source
+		}
+		if location, _ := m["location"].(string); location != "" {
+			if source == "commit_message" && len(location) > 7 {
+				location = location[:7]
+			}
+			place += " `" + strings.ReplaceAll(location, "`", "") + "`"
+		}
+		if !seen[place] {
+			seen[place] = true
+			places = append(places, place)
+		}
+		if s, _ := m["stripped"].(bool); s {
+			stripped++
+		}
+	}
+
+	note := fmt.Sprintf("> ⚠️ **Possible prompt injection**: %d suspicious passage(s) found in the %s. "+
+		"The content was treated as data, but verify this review before relying on it.",
+		len(items), strings.Join(places, ", "))
+	if stripped > 0 {
+		note += fmt.Sprintf(" %d passage(s) were removed from the prompts.", stripped)
+	}
+	return note
+}
+
 // ConvertToJSON converts structured result to JSON string
 func ConvertToJSON(result *prompt.ReviewResult) (string, error) {
 	if len(result.Data) == 0 {
@@ -80,6 +80,50 @@ func TestConvertToMarkdown_WithSummary(t *testing.T) {
 	assert.Contains(t, markdown, "This is a test summary")
 }
 
+func TestConvertToMarkdown_WithSuppressed(t *testing.T) {
+	result := &prompt.ReviewResult{
+		ReviewerID: "test-reviewer",
+		Data: map[string]any{
+			"summary": "This is a test summary",
+			"suppressed": []interface{}{
+				map[string]interface{}{"fingerprint": "a", "source": "directive"},
+				map[string]interface{}{"fingerprint": "b", "source": "baseline"},
+				map[string]interface{}{"fingerprint": "c", "source": "baseline"},
+			},
+		},
+	}
+
+	markdown := ConvertToMarkdown(result, &MarkdownOptions{})
+
+	assert.Contains(t, markdown, "_3 findings suppressed: 1 by `verust:ignore` directives, 2 by the baseline._")
+
+	result.Data["suppressed"] = []interface{}{}
+	assert.NotContains(t, ConvertToMarkdown(result, &MarkdownOptions{}), "suppressed")
+}
+
+func TestConvertToMarkdown_WithPromptInjection(t *testing.T) {
+	result := &prompt.ReviewResult{
+		ReviewerID: "test-reviewer",
+		Data: map[string]any{
+			"summary": "This is a test summary",
+			"prompt_injection": []interface{}{
+				map[string]interface{}{"source": "pr_description", "detector": "instruction_override", "stripped": true},
+				map[string]interface{}{"source": "pr_description", "detector": "role_hijack", "stripped": true},
+				map[string]interface{}{"source": "commit_message", "location": "0123456789abcdef", "detector": "classifier"},
+			},
+		},
+	}
+
+	markdown := ConvertToMarkdown(result, &MarkdownOptions{})
+
+	assert.Contains(t, markdown, "**Possible prompt injection**: 3 suspicious passage(s) found in the PR description, commit message `0123456`.")
+	assert.Contains(t, markdown, "2 passage(s) were removed from the prompts.")
+	assert.Less(t, strings.Index(markdown, "Possible prompt injection"), strings.Index(markdown, "This is a test summary"))
+
+	delete(result.Data, "prompt_injection")
+	assert.NotContains(t, ConvertToMarkdown(result, &MarkdownOptions{}), "prompt injection")
+}
+
 func TestConvertToMarkdown_WithPRInfo(t *testing.T) {
 	result := &prompt.ReviewResult{
 		ReviewerID: "test-reviewer",
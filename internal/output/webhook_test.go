@@ -40,6 +40,26 @@ func (m *mockStore) RepositoryConfig() store.RepositoryConfigStore {
 	return nil
 }
 
+func (m *mockStore) Schedule() store.ScheduleStore {
+	return nil
+}
+
+func (m *mockStore) Area() store.AreaStore {
+	return nil
+}
+
+func (m *mockStore) ResponseCache() store.ResponseCacheStore {
+	return nil
+}
+
+func (m *mockStore) Cassette() store.CassetteStore {
+	return nil
+}
+
+func (m *mockStore) Eval() store.EvalStore {
+	return nil
+}
+
 func (m *mockStore) DB() *gorm.DB {
 	return nil
 }
@@ -119,8 +139,14 @@ func (m *mockReviewStore) GetRunsByRuleID(ruleID uint) ([]model.ReviewRuleRun, e
 func (m *mockReviewStore) DeleteReviewRuleRunsByRuleID(ruleID uint) error        { return nil }
 func (m *mockReviewStore) UpdateRun(run *model.ReviewRuleRun) error              { return nil }
 func (m *mockReviewStore) UpdateRunStatus(id uint, status model.RunStatus) error { return nil }
-func (m *mockReviewStore) CreateResult(result *model.ReviewResult) error         { return nil }
-func (m *mockReviewStore) DeleteReviewResultsByRuleID(ruleID uint) error         { return nil }
+func (m *mockReviewStore) CreateChunk(chunk *model.ReviewRuleChunk) error        { return nil }
+func (m *mockReviewStore) GetChunksByRuleID(ruleID uint) ([]model.ReviewRuleChunk, error) {
+	return nil, nil
+}
+func (m *mockReviewStore) DeleteReviewRuleChunksByRuleID(ruleID uint) error { return nil }
+func (m *mockReviewStore) UpdateChunk(chunk *model.ReviewRuleChunk) error   { return nil }
+func (m *mockReviewStore) CreateResult(result *model.ReviewResult) error    { return nil }
+func (m *mockReviewStore) DeleteReviewResultsByRuleID(ruleID uint) error    { return nil }
 func (m *mockReviewStore) GetResultsByRuleID(ruleID uint) ([]model.ReviewResult, error) {
 	return nil, nil
 }
@@ -149,6 +175,12 @@ func (m *mockReviewStore) GetAverageDurationAfter(start time.Time) (float64, err
 func (m *mockReviewStore) ListCompletedByRepoAndDateRange(repoURL string, start time.Time) ([]model.Review, error) {
 	return nil, nil
 }
+func (m *mockReviewStore) ListUsageByRepoSince(repoURL string, start time.Time) ([]model.UsageRecord, error) {
+	return nil, nil
+}
+func (m *mockReviewStore) SumCostByRepoSince(repoURL string, start time.Time) (float64, error) {
+	return 0, nil
+}
 func (m *mockReviewStore) GetReviewResultsByReviewIDs(reviewIDs []string) ([]model.ReviewResult, error) {
 	return nil, nil
 }
@@ -17,7 +17,7 @@ func (b *Builder) Build(rule *dsl.ReviewRuleConfig, ctx *BuildContext) *Spec {
 	spec := &Spec{
 		ReviewerID:  rule.ID,
 		SystemRole:  b.buildSystemRole(rule),
-		Goals:       b.buildGoals(rule),
+		Goals:       b.buildGoals(rule, ctx),
 		Constraints: b.buildConstraints(rule, ctx),
 		Context:     b.buildContext(rule, ctx),
 	}
@@ -52,13 +52,29 @@ type BuildContext struct {
 	// PRDescription is the PR/MR description
 	PRDescription string
 
+	// PushCommits lists the pushed commits of a push review, one "short-sha subject (author)" line each
+	PushCommits string
+
+	// Source is the review trigger source ("webhook", "api", "schedule", ...)
+	// Used to describe non-PR reviews such as scheduled audits
+	Source string
+
 	// ChangedFiles lists changed files
 	ChangedFiles []string
 
+	// Languages lists the languages in scope (set when a rule's when clause matches by language)
+	Languages []string
+
 	// Commits lists all commit SHAs in the PR (from BaseCommitSHA to CommitSHA)
 	// This is obtained using git rev-list base..head
 	Commits []string
 
+	// ChunkIndex is the 1-based index of the chunk when a rule is executed in chunks
+	ChunkIndex int
+
+	// ChunkTotal is the total number of chunks (0 or 1 when not chunked)
+	ChunkTotal int
+
 	// OutputLanguage is the language instruction for the AI to respond in
 	// This is a human-readable instruction like "Please respond in Chinese."
 	// This is used as a fallback if not specified in DSL output.style.language
@@ -67,6 +83,25 @@ type BuildContext struct {
 	// PreviousReviewForComparison is the summary from the previous review of the same PR + rule
 	// Used for historical comparison when history_compare is enabled
 	PreviousReviewForComparison string
+
+	// Areas lists the custom areas declared in the review file (`areas:` section)
+	Areas []dsl.AreaDefinition
+
+	// ExtraContext contains the outputs of the rule's context providers
+	ExtraContext []ContextItem
+}
+
+// areaDescription returns the description of a custom area declared in the review file
+func (ctx *BuildContext) areaDescription(areaID string) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	for _, area := range ctx.Areas {
+		if area.ID == areaID {
+			return area.Description, true
+		}
+	}
+	return "", false
 }
 
 // buildSystemRole builds the system role specification (identity only)
@@ -77,10 +112,14 @@ func (b *Builder) buildSystemRole(rule *dsl.ReviewRuleConfig) SystemRoleSpec {
 }
 
 // buildGoals builds the goals specification (focus areas with descriptions)
-func (b *Builder) buildGoals(rule *dsl.ReviewRuleConfig) GoalsSpec {
+// Areas declared in the review file take precedence over organization-wide custom areas
+func (b *Builder) buildGoals(rule *dsl.ReviewRuleConfig, ctx *BuildContext) GoalsSpec {
 	items := make([]AreaItem, 0, len(rule.Goals.Areas))
 	for _, areaID := range rule.Goals.Areas {
-		desc, _ := dsl.GetAreaDescription(areaID)
+		desc, ok := ctx.areaDescription(areaID)
+		if !ok {
+			desc, _ = dsl.GetAreaDescription(areaID)
+		}
 		items = append(items, AreaItem{ID: areaID, Description: desc})
 	}
 	return GoalsSpec{
@@ -165,9 +204,15 @@ func (b *Builder) buildContext(rule *dsl.ReviewRuleConfig, ctx *BuildContext) Co
 		spec.PRNumber = ctx.PRNumber
 		spec.PRTitle = ctx.PRTitle
 		spec.PRDescription = ctx.PRDescription
+		spec.PushCommits = ctx.PushCommits
+		spec.Source = ctx.Source
 		spec.ChangedFiles = ctx.ChangedFiles
+		spec.Languages = ctx.Languages
 		spec.Commits = ctx.Commits
+		spec.ChunkIndex = ctx.ChunkIndex
+		spec.ChunkTotal = ctx.ChunkTotal
 		spec.PreviousReviewForComparison = ctx.PreviousReviewForComparison
+		spec.ExtraContext = ctx.ExtraContext
 	}
 
 	// Apply reference docs from rule configuration
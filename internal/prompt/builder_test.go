@@ -236,6 +236,31 @@ func TestBuilder_BuildGoals(t *testing.T) {
 		}
 	})
 
+	t.Run("custom areas", func(t *testing.T) {
+		dsl.SetCustomAreas([]dsl.AreaDefinition{{ID: "feature-flags", Description: "Feature flag hygiene"}})
+		defer dsl.SetCustomAreas(nil)
+
+		rule := &dsl.ReviewRuleConfig{
+			ID:          "test",
+			Description: "Reviewer",
+			Goals: dsl.GoalsConfig{
+				Areas: []string{"pci-dss-logging", "feature-flags"},
+			},
+		}
+		ctx := &BuildContext{
+			Areas: []dsl.AreaDefinition{{ID: "pci-dss-logging", Description: "PCI-DSS logging requirements"}},
+		}
+
+		spec := builder.Build(rule, ctx)
+
+		if spec.Goals.Areas[0].Description != "PCI-DSS logging requirements" {
+			t.Errorf("Goals.Areas[0].Description = %q, want file area description", spec.Goals.Areas[0].Description)
+		}
+		if spec.Goals.Areas[1].Description != "Feature flag hygiene" {
+			t.Errorf("Goals.Areas[1].Description = %q, want custom area description", spec.Goals.Areas[1].Description)
+		}
+	})
+
 	t.Run("empty goals", func(t *testing.T) {
 		rule := &dsl.ReviewRuleConfig{
 			ID:          "test",
@@ -101,8 +101,7 @@ func (b *FormatInstructionBuilder) buildJSONFormatInstructions(jsonSchema map[st
 	// Add language instruction if specified
 	if language != "" {
 		sb.WriteString(fmt.Sprintf("\nAll output content MUST be in %s.\n", language))
-		sb.WriteString("Also use appropriate field names in the target language.\n")
-		sb.WriteString("For example: If language is Chinese, use the following field names: Summary → 汇总, Location → 位置, ...\n")
+		sb.WriteString("Keep the JSON field names and enum values exactly as defined in the schema; only translate the text values.\n")
 	}
 
 	return sb.String()
@@ -2,6 +2,11 @@
 // It transforms reviewer DSL into structured prompts for AI agents.
 package prompt
 
+import (
+	"github.com/verustcode/verustcode/internal/llm"
+	"github.com/verustcode/verustcode/internal/model"
+)
+
 // Spec represents a structured prompt specification
 // This is the intermediate representation between DSL and the final prompt text
 // Note: Output format is handled by llm client layer (via ResponseSchema or MarkdownOutputPrompt)
@@ -106,12 +111,24 @@ type ContextSpec struct {
 	// PRDescription is the PR/MR description
 	PRDescription string
 
+	// PushCommits lists the pushed commits of a push review
+	PushCommits string
+
+	// Source is the review trigger source ("webhook", "api", "schedule", ...)
+	Source string
+
 	// ChangedFiles lists files changed in the PR
 	ChangedFiles []string
 
 	// Commits lists all commit SHAs in the PR (from BaseCommitSHA to CommitSHA)
 	Commits []string
 
+	// ChunkIndex is the 1-based index of the chunk being reviewed
+	ChunkIndex int
+
+	// ChunkTotal is the total number of chunks (0 or 1 when not chunked)
+	ChunkTotal int
+
 	// Languages lists programming languages in scope
 	Languages []string
 
@@ -123,6 +140,30 @@ type ContextSpec struct {
 	// Only populated when history_compare is enabled
 	// The AI will compare current findings with previous ones and mark status
 	PreviousReviewForComparison string
+
+	// ExtraContext contains the outputs of the rule's context providers
+	ExtraContext []ContextItem
+}
+
+// ContextItem is the output of a context provider (file contents, command output, diff, git log)
+type ContextItem struct {
+	// Title is the title of the output in the prompt
+	Title string
+
+	// Type is the provider type (file, command, diff, git_log)
+	Type string
+
+	// Content is the provider output, truncated to the provider's token budget
+	Content string
+
+	// Truncated is true if Content was cut to fit the token budget
+	Truncated bool
+}
+
+// Untrusted returns Content delimited as untrusted input (see llm.WrapUntrustedCode),
+// with the provider type as its source
+func (c ContextItem) Untrusted() string {
+	return llm.WrapUntrustedCode(c.Type, c.Content)
 }
 
 // ReviewResult represents the raw AI response for a reviewer
@@ -146,6 +187,15 @@ type ReviewResult struct {
 	// ModelName is the name of the model used
 	ModelName string `json:"model_name,omitempty"`
 
+	// Usage is the token usage and cost of all agent calls that produced this result (nil if not reported)
+	Usage *llm.Usage `json:"usage,omitempty"`
+
+	// Validation is the outcome of validating the structured output (nil if the rule has none)
+	Validation *model.OutputValidation `json:"validation,omitempty"`
+
+	// Cached is true if the result was served from the response cache (no agent was called)
+	Cached bool `json:"cached,omitempty"`
+
 	// Error contains any error message during review execution
 	Error string `json:"error,omitempty"`
 }
@@ -458,6 +458,8 @@ func (e *Engine) runPhase2(ctx context.Context, report *model.Report, structure
 		completedAt := time.Now()
 		section.Content = result.Content
 		section.Summary = result.Summary
+		section.Model = result.Model
+		section.TokenUsage = result.Usage
 		section.Status = model.SectionStatusCompleted
 		section.CompletedAt = &completedAt
 		section.Duration = completedAt.Sub(startTime).Milliseconds()
@@ -46,8 +46,10 @@ func (g *SectionGenerator) getReportConfig() (*config.ReportConfig, error) {
 
 // SectionResult contains the generated content and summary for a section
 type SectionResult struct {
-	Content string // Full section content in Markdown
-	Summary string // Short summary for Phase 3 aggregation
+	Content string           // Full section content in Markdown
+	Summary string           // Short summary for Phase 3 aggregation
+	Model   string           // Model reported by the agent
+	Usage   model.TokenUsage // Token usage and cost of the successful agent call
 }
 
 // GenerateSection generates content for a single section
@@ -201,6 +203,11 @@ func (g *SectionGenerator) GenerateSection(
 
 	// Post-process and parse content + summary
 	sectionResult := g.postProcessContentWithSummary(response, config)
+	sectionResult.Model = result.ModelName
+	if sectionResult.Model == "" {
+		sectionResult.Model = req.Model
+	}
+	sectionResult.Usage = g.sectionUsage(result.Usage, sectionResult.Model)
 
 	logger.Info("Section content generated",
 		zap.String("section_id", section.SectionID),
@@ -612,3 +619,21 @@ func (g *SectionGenerator) postProcessContent(content string) string {
 func MergeSections(report *model.Report, sections []model.ReportSection) string {
 	return exporter.MergeSections(report, sections)
 }
+
+// sectionUsage converts the usage reported by the agent, priced with the configured price table
+func (g *SectionGenerator) sectionUsage(usage *llm.Usage, modelName string) model.TokenUsage {
+	if usage == nil {
+		return model.TokenUsage{}
+	}
+	result := model.TokenUsage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		CachedTokens:     usage.CachedTokens,
+	}
+	if reviewCfg, err := g.configProvider.GetReviewConfig(); err == nil && reviewCfg != nil {
+		if price, ok := config.PriceFor(reviewCfg.ModelPrices, modelName); ok {
+			result.Cost = price.Cost(usage.PromptTokens, usage.CompletionTokens, usage.CachedTokens)
+		}
+	}
+	return result
+}
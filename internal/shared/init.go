@@ -6,6 +6,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/verustcode/verustcode/internal/agent/base"
+	"github.com/verustcode/verustcode/internal/agent/pool"
 	"github.com/verustcode/verustcode/internal/config"
 	"github.com/verustcode/verustcode/internal/git/provider"
 	"github.com/verustcode/verustcode/internal/store"
@@ -70,6 +71,9 @@ func InitAgents(cfg *config.Config, s store.Store) map[string]base.Agent {
 			continue
 		}
 
+		// Limit concurrency and rate of its calls with the shared agent pool
+		agent = pool.Default().Wrap(agent)
+
 		// Inject store for runtime configuration reading
 		agent.SetStore(s)
 
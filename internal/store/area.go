@@ -0,0 +1,55 @@
+package store
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+// AreaStore defines operations for CustomArea model.
+type AreaStore interface {
+	// Custom area CRUD
+	Create(area *model.CustomArea) error
+	GetByAreaID(areaID string) (*model.CustomArea, error)
+	Save(area *model.CustomArea) error
+	Delete(areaID string) error
+
+	// ListAll returns all custom areas ordered by area ID.
+	ListAll() ([]model.CustomArea, error)
+}
+
+// areaStore implements AreaStore using GORM.
+type areaStore struct {
+	db *gorm.DB
+}
+
+func newAreaStore(db *gorm.DB) AreaStore {
+	return &areaStore{db: db}
+}
+
+func (s *areaStore) Create(area *model.CustomArea) error {
+	return s.db.Create(area).Error
+}
+
+func (s *areaStore) GetByAreaID(areaID string) (*model.CustomArea, error) {
+	var area model.CustomArea
+	err := s.db.Where("area_id = ?", areaID).First(&area).Error
+	if err != nil {
+		return nil, err
+	}
+	return &area, nil
+}
+
+func (s *areaStore) Save(area *model.CustomArea) error {
+	return s.db.Save(area).Error
+}
+
+func (s *areaStore) Delete(areaID string) error {
+	return s.db.Where("area_id = ?", areaID).Delete(&model.CustomArea{}).Error
+}
+
+func (s *areaStore) ListAll() ([]model.CustomArea, error) {
+	var areas []model.CustomArea
+	err := s.db.Order("area_id ASC").Find(&areas).Error
+	return areas, err
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+// TestAreaStore_CRUD tests creating, updating, listing and deleting custom areas
+func TestAreaStore_CRUD(t *testing.T) {
+	store, cleanup := SetupTestDB(t)
+	defer cleanup()
+
+	for _, area := range []*model.CustomArea{
+		{AreaID: "pci-dss-logging", Group: "compliance", Description: "PCI-DSS logging"},
+		{AreaID: "feature-flags", Group: "custom", Description: "Feature flag hygiene"},
+	} {
+		if err := store.Area().Create(area); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+	}
+
+	duplicate := &model.CustomArea{AreaID: "feature-flags", Group: "custom", Description: "Duplicate"}
+	if err := store.Area().Create(duplicate); err == nil {
+		t.Error("Create() should fail for duplicate area ID")
+	}
+
+	all, err := store.Area().ListAll()
+	if err != nil {
+		t.Fatalf("ListAll() failed: %v", err)
+	}
+	if len(all) != 2 || all[0].AreaID != "feature-flags" {
+		t.Errorf("Expected 2 areas ordered by ID, got %+v", all)
+	}
+
+	area, err := store.Area().GetByAreaID("pci-dss-logging")
+	if err != nil {
+		t.Fatalf("GetByAreaID() failed: %v", err)
+	}
+	area.Description = "PCI-DSS logging requirements"
+	if err := store.Area().Save(area); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	updated, _ := store.Area().GetByAreaID("pci-dss-logging")
+	if updated.Description != "PCI-DSS logging requirements" {
+		t.Errorf("Expected updated description, got %q", updated.Description)
+	}
+
+	if err := store.Area().Delete("pci-dss-logging"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := store.Area().GetByAreaID("pci-dss-logging"); err == nil {
+		t.Error("Expected area to be deleted")
+	}
+}
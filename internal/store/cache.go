@@ -0,0 +1,68 @@
+package store
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+// ResponseCacheStore defines operations for ResponseCacheEntry model.
+type ResponseCacheStore interface {
+	// Get returns the unexpired entry with the key and counts the hit.
+	// Returns gorm.ErrRecordNotFound if there is none.
+	Get(key string) (*model.ResponseCacheEntry, error)
+
+	// Put stores an entry, replacing any entry with the same key, and removes expired entries.
+	Put(entry *model.ResponseCacheEntry) error
+
+	// Purge deletes the entries of a repository and rule (empty matches all) and returns their number.
+	Purge(repoURL, ruleID string) (int64, error)
+}
+
+// responseCacheStore implements ResponseCacheStore using GORM.
+type responseCacheStore struct {
+	db *gorm.DB
+}
+
+func newResponseCacheStore(db *gorm.DB) ResponseCacheStore {
+	return &responseCacheStore{db: db}
+}
+
+func (s *responseCacheStore) Get(key string) (*model.ResponseCacheEntry, error) {
+	var entry model.ResponseCacheEntry
+	err := s.db.Where("cache_key = ? AND expires_at > ?", key, time.Now()).First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.Model(&entry).UpdateColumn("hits", gorm.Expr("hits + 1")).Error
+	if err != nil {
+		return nil, err
+	}
+	entry.Hits++
+	return &entry, nil
+}
+
+func (s *responseCacheStore) Put(entry *model.ResponseCacheEntry) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("cache_key = ? OR expires_at <= ?", entry.CacheKey, time.Now()).
+			Delete(&model.ResponseCacheEntry{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+func (s *responseCacheStore) Purge(repoURL, ruleID string) (int64, error) {
+	query := s.db.Where("1 = 1")
+	if repoURL != "" {
+		query = query.Where("repo_url = ?", repoURL)
+	}
+	if ruleID != "" {
+		query = query.Where("rule_id = ?", ruleID)
+	}
+	result := query.Delete(&model.ResponseCacheEntry{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+// TestResponseCacheStore tests storing, reading, expiring and purging cache entries
+func TestResponseCacheStore(t *testing.T) {
+	store, cleanup := SetupTestDB(t)
+	defer cleanup()
+
+	cache := store.ResponseCache()
+	expires := time.Now().Add(time.Hour)
+	for _, entry := range []*model.ResponseCacheEntry{
+		{CacheKey: "a", RepoURL: "https://github.com/org/one", RuleID: "security", Text: "one", ExpiresAt: expires},
+		{CacheKey: "b", RepoURL: "https://github.com/org/one", RuleID: "style", ExpiresAt: expires},
+		{CacheKey: "c", RepoURL: "https://github.com/org/two", RuleID: "security", ExpiresAt: expires},
+		{CacheKey: "expired", RepoURL: "https://github.com/org/two", ExpiresAt: time.Now().Add(-time.Minute)},
+	} {
+		if err := cache.Put(entry); err != nil {
+			t.Fatalf("Put() failed: %v", err)
+		}
+	}
+
+	entry, err := cache.Get("a")
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if entry.Text != "one" || entry.Hits != 1 {
+		t.Errorf("Expected entry with 1 hit, got %+v", entry)
+	}
+	if _, err := cache.Get("expired"); err == nil {
+		t.Error("Get() should not return expired entries")
+	}
+
+	// Replacing an entry resets it
+	if err := cache.Put(&model.ResponseCacheEntry{CacheKey: "a", Text: "replaced", ExpiresAt: expires}); err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+	if entry, _ := cache.Get("a"); entry == nil || entry.Text != "replaced" || entry.Hits != 1 {
+		t.Errorf("Expected replaced entry, got %+v", entry)
+	}
+
+	deleted, err := cache.Purge("https://github.com/org/two", "security")
+	if err != nil {
+		t.Fatalf("Purge() failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected 1 purged entry, got %d", deleted)
+	}
+	if deleted, _ := cache.Purge("", ""); deleted != 2 {
+		t.Errorf("Expected 2 purged entries, got %d", deleted)
+	}
+}
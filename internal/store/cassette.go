@@ -0,0 +1,43 @@
+package store
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+// CassetteStore defines operations for CassetteInteraction model.
+type CassetteStore interface {
+	// Record stores a recorded agent interaction
+	Record(interaction *model.CassetteInteraction) error
+
+	// ListByReviewID returns the interactions of a review in execution order
+	ListByReviewID(reviewID string) ([]model.CassetteInteraction, error)
+
+	// DeleteByRule deletes the interactions of a rule of a review, including the merge of its runs
+	DeleteByRule(reviewID, ruleID string) error
+}
+
+// cassetteStore implements CassetteStore using GORM.
+type cassetteStore struct {
+	db *gorm.DB
+}
+
+func newCassetteStore(db *gorm.DB) CassetteStore {
+	return &cassetteStore{db: db}
+}
+
+func (s *cassetteStore) Record(interaction *model.CassetteInteraction) error {
+	return s.db.Create(interaction).Error
+}
+
+func (s *cassetteStore) ListByReviewID(reviewID string) ([]model.CassetteInteraction, error) {
+	var interactions []model.CassetteInteraction
+	err := s.db.Where("review_id = ?", reviewID).Order("id ASC").Find(&interactions).Error
+	return interactions, err
+}
+
+func (s *cassetteStore) DeleteByRule(reviewID, ruleID string) error {
+	return s.db.Where("review_id = ? AND rule_id IN ?", reviewID, []string{ruleID, ruleID + "-merge"}).
+		Delete(&model.CassetteInteraction{}).Error
+}
@@ -0,0 +1,53 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+// TestCassetteStore tests recording, listing and deleting agent interactions
+func TestCassetteStore(t *testing.T) {
+	store, cleanup := SetupTestDB(t)
+	defer cleanup()
+
+	cassettes := store.Cassette()
+	for _, interaction := range []*model.CassetteInteraction{
+		{ReviewID: "r1", RuleID: "security", Agent: "cursor", Prompt: "first",
+			Chunks: model.CassetteChunks{{Type: "tool_call", ToolName: "read_file"}}},
+		{ReviewID: "r2", RuleID: "security", Agent: "cursor"},
+		{ReviewID: "r1", RuleID: "style", Agent: "cursor"},
+		{ReviewID: "r1", RuleID: "security-merge", Agent: "cursor"},
+	} {
+		if err := cassettes.Record(interaction); err != nil {
+			t.Fatalf("Record() failed: %v", err)
+		}
+	}
+
+	interactions, err := cassettes.ListByReviewID("r1")
+	if err != nil {
+		t.Fatalf("ListByReviewID() failed: %v", err)
+	}
+	if len(interactions) != 3 {
+		t.Fatalf("Expected 3 interactions, got %d", len(interactions))
+	}
+	if interactions[0].Prompt != "first" || interactions[1].RuleID != "style" {
+		t.Errorf("Interactions are not in recording order: %+v", interactions)
+	}
+	if len(interactions[0].Chunks) != 1 || interactions[0].Chunks[0].ToolName != "read_file" {
+		t.Errorf("Chunks were not stored: %+v", interactions[0].Chunks)
+	}
+
+	// Deleting a rule's interactions includes the merge of its runs, but not other reviews
+	if err := cassettes.DeleteByRule("r1", "security"); err != nil {
+		t.Fatalf("DeleteByRule() failed: %v", err)
+	}
+	interactions, _ = cassettes.ListByReviewID("r1")
+	if len(interactions) != 1 || interactions[0].RuleID != "style" {
+		t.Errorf("Expected only the style interaction, got %+v", interactions)
+	}
+	interactions, _ = cassettes.ListByReviewID("r2")
+	if len(interactions) != 1 {
+		t.Errorf("Expected the interaction of r2 to be kept, got %d", len(interactions))
+	}
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+// EvalStore defines operations for EvalRun and EvalCaseResult models.
+type EvalStore interface {
+	// CreateRun stores an evaluation run with its case results
+	CreateRun(run *model.EvalRun) error
+
+	// GetRun returns an evaluation run with its case results
+	GetRun(id uint) (*model.EvalRun, error)
+
+	// ListRuns returns the evaluation runs of a dataset (all datasets if empty), newest first.
+	// Case results are not loaded. limit <= 0 returns all runs.
+	ListRuns(dataset string, limit int) ([]model.EvalRun, error)
+}
+
+// evalStore implements EvalStore using GORM.
+type evalStore struct {
+	db *gorm.DB
+}
+
+func newEvalStore(db *gorm.DB) EvalStore {
+	return &evalStore{db: db}
+}
+
+func (s *evalStore) CreateRun(run *model.EvalRun) error {
+	return s.db.Create(run).Error
+}
+
+func (s *evalStore) GetRun(id uint) (*model.EvalRun, error) {
+	var run model.EvalRun
+	err := s.db.Preload("CaseResults", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).First(&run, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (s *evalStore) ListRuns(dataset string, limit int) ([]model.EvalRun, error) {
+	var runs []model.EvalRun
+	query := s.db.Order("created_at DESC").Order("id DESC")
+	if dataset != "" {
+		query = query.Where("dataset = ?", dataset)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&runs).Error
+	return runs, err
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+// TestEvalStore tests storing, loading and listing evaluation runs
+func TestEvalStore(t *testing.T) {
+	store, cleanup := SetupTestDB(t)
+	defer cleanup()
+
+	evals := store.Eval()
+	for _, run := range []*model.EvalRun{
+		{Dataset: "bench", Configuration: "cursor", Agent: "cursor", Precision: 0.5,
+			CaseResults: []model.EvalCaseResult{
+				{CaseID: "sqli", TruePositives: 1, Missed: model.StringArray{"db.go:10-12 SQL injection"}},
+				{CaseID: "clean", FalsePositives: 2},
+			}},
+		{Dataset: "other", Configuration: "cursor"},
+		{Dataset: "bench", Configuration: "gemini:gemini-2.5-pro", Agent: "gemini", Model: "gemini-2.5-pro"},
+	} {
+		if err := evals.CreateRun(run); err != nil {
+			t.Fatalf("CreateRun() failed: %v", err)
+		}
+	}
+
+	run, err := evals.GetRun(1)
+	if err != nil {
+		t.Fatalf("GetRun() failed: %v", err)
+	}
+	if len(run.CaseResults) != 2 || run.CaseResults[0].CaseID != "sqli" || run.CaseResults[1].FalsePositives != 2 {
+		t.Fatalf("Case results were not stored: %+v", run.CaseResults)
+	}
+	if len(run.CaseResults[0].Missed) != 1 {
+		t.Errorf("Missed findings were not stored: %+v", run.CaseResults[0])
+	}
+
+	runs, err := evals.ListRuns("bench", 0)
+	if err != nil {
+		t.Fatalf("ListRuns() failed: %v", err)
+	}
+	if len(runs) != 2 || runs[0].Configuration != "gemini:gemini-2.5-pro" || runs[1].Configuration != "cursor" {
+		t.Fatalf("Expected the bench runs newest first, got %+v", runs)
+	}
+	if len(runs[1].CaseResults) != 0 {
+		t.Errorf("ListRuns() should not load case results")
+	}
+
+	runs, err = evals.ListRuns("", 1)
+	if err != nil {
+		t.Fatalf("ListRuns() failed: %v", err)
+	}
+	if len(runs) != 1 {
+		t.Errorf("Expected limit to apply, got %d runs", len(runs))
+	}
+}
@@ -1,6 +1,8 @@
 package store
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 
 	"github.com/verustcode/verustcode/internal/model"
@@ -34,6 +36,11 @@ type ReportStore interface {
 	GetDistinctRepositories() ([]string, error)
 	CountAll() (int64, error)
 
+	// ListUsageByRepoSince returns the token usage of report sections created since start (empty repoURL = all repos)
+	ListUsageByRepoSince(repoURL string, start time.Time) ([]model.UsageRecord, error)
+	// SumCostByRepoSince returns the total cost of report sections created since start (empty repoURL = all repos)
+	SumCostByRepoSince(repoURL string, start time.Time) (float64, error)
+
 	// Report cancel
 	CancelByID(id string) (int64, error)
 
@@ -180,6 +187,41 @@ func (s *reportStore) CountAll() (int64, error) {
 	return count, err
 }
 
+// ListUsageByRepoSince returns the token usage of report sections created since start.
+func (s *reportStore) ListUsageByRepoSince(repoURL string, start time.Time) ([]model.UsageRecord, error) {
+	var records []model.UsageRecord
+	query := s.db.Table("report_sections").
+		Select("reports.repo_url, report_sections.created_at, report_sections.prompt_tokens, report_sections.completion_tokens, report_sections.cached_tokens, report_sections.cost").
+		Joins("JOIN reports ON reports.id = report_sections.report_id").
+		Where("report_sections.created_at >= ?", start).
+		Where("report_sections.deleted_at IS NULL AND reports.deleted_at IS NULL").
+		Where("report_sections.prompt_tokens > 0 OR report_sections.completion_tokens > 0")
+
+	if repoURL != "" {
+		query = query.Where("reports.repo_url = ?", repoURL)
+	}
+
+	err := query.Order("report_sections.created_at ASC").Scan(&records).Error
+	return records, err
+}
+
+// SumCostByRepoSince returns the total cost of report sections created since start.
+func (s *reportStore) SumCostByRepoSince(repoURL string, start time.Time) (float64, error) {
+	var cost float64
+	query := s.db.Table("report_sections").
+		Select("COALESCE(SUM(report_sections.cost), 0)").
+		Joins("JOIN reports ON reports.id = report_sections.report_id").
+		Where("report_sections.created_at >= ?", start).
+		Where("report_sections.deleted_at IS NULL AND reports.deleted_at IS NULL")
+
+	if repoURL != "" {
+		query = query.Where("reports.repo_url = ?", repoURL)
+	}
+
+	err := query.Row().Scan(&cost)
+	return cost, err
+}
+
 // GetByIDWithSections retrieves a report by ID and preloads its sections.
 func (s *reportStore) GetByIDWithSections(id string) (*model.Report, error) {
 	var report model.Report
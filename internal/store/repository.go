@@ -77,14 +77,19 @@ func (nt NullTimeString) Value() (driver.Value, error) {
 
 // RepositoryWithStats represents a repository config with review statistics.
 type RepositoryWithStats struct {
-	ID           uint
-	RepoURL      string
-	ReviewFile   string
-	Description  string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	ReviewCount  int64
-	LastReviewAt NullTimeString
+	ID                 uint
+	RepoURL            string
+	ReviewFile         string
+	Description        string
+	InRepoConfigPolicy model.InRepoConfigPolicy
+	MonthlyBudget      float64
+	BudgetAction       model.BudgetAction
+	BudgetModel        string
+	InjectionAction    model.InjectionAction
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	ReviewCount        int64
+	LastReviewAt       NullTimeString
 }
 
 // RepositoryConfigStore defines operations for RepositoryReviewConfig model.
@@ -212,7 +217,8 @@ func (s *repositoryConfigStore) ListWithStats(search, sortBy, sortOrder string,
 	// Build the base query with LEFT JOIN to get review stats
 	baseQuery := `
 		SELECT 
-			rrc.id, rrc.repo_url, rrc.review_file, rrc.description, 
+			rrc.id, rrc.repo_url, rrc.review_file, rrc.description, rrc.in_repo_config_policy,
+			rrc.monthly_budget, rrc.budget_action, rrc.budget_model, rrc.injection_action,
 			rrc.created_at, rrc.updated_at,
 			COALESCE(stats.review_count, 0) as review_count,
 			stats.last_review_at
@@ -65,6 +65,12 @@ type ReviewStore interface {
 	UpdateRun(run *model.ReviewRuleRun) error
 	UpdateRunStatus(id uint, status model.RunStatus) error
 
+	// ReviewRuleChunk operations
+	CreateChunk(chunk *model.ReviewRuleChunk) error
+	GetChunksByRuleID(ruleID uint) ([]model.ReviewRuleChunk, error)
+	DeleteReviewRuleChunksByRuleID(ruleID uint) error
+	UpdateChunk(chunk *model.ReviewRuleChunk) error
+
 	// ReviewResult operations
 	CreateResult(result *model.ReviewResult) error
 	DeleteReviewResultsByRuleID(ruleID uint) error
@@ -93,6 +99,12 @@ type ReviewStore interface {
 	ListCompletedByRepoAndDateRange(repoURL string, start time.Time) ([]model.Review, error)
 	GetReviewResultsByReviewIDs(reviewIDs []string) ([]model.ReviewResult, error)
 
+	// Usage queries (for cost accounting and budgets)
+	// ListUsageByRepoSince returns the token usage of review rules created since start (empty repoURL = all repos)
+	ListUsageByRepoSince(repoURL string, start time.Time) ([]model.UsageRecord, error)
+	// SumCostByRepoSince returns the total cost of review rules created since start (empty repoURL = all repos)
+	SumCostByRepoSince(repoURL string, start time.Time) (float64, error)
+
 	// Findings queries (for findings handler)
 	// GetAllFindingsWithRepoInfo returns all review results with their associated review info
 	GetAllFindingsWithRepoInfo(repoURL string) ([]FindingWithRepoInfo, error)
@@ -132,7 +144,7 @@ func (s *reviewStore) GetByID(id string) (*model.Review, error) {
 
 func (s *reviewStore) GetByIDWithDetails(id string) (*model.Review, error) {
 	var review model.Review
-	err := s.db.Preload("Rules").Preload("Rules.Runs").Preload("Rules.Results").First(&review, "id = ?", id).Error
+	err := s.db.Preload("Rules").Preload("Rules.Runs").Preload("Rules.Chunks").Preload("Rules.Results").First(&review, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -353,6 +365,26 @@ func (s *reviewStore) UpdateRunStatus(id uint, status model.RunStatus) error {
 	return s.db.Model(&model.ReviewRuleRun{}).Where("id = ?", id).Update("status", status).Error
 }
 
+// ReviewRuleChunk operations
+
+func (s *reviewStore) CreateChunk(chunk *model.ReviewRuleChunk) error {
+	return s.db.Create(chunk).Error
+}
+
+func (s *reviewStore) GetChunksByRuleID(ruleID uint) ([]model.ReviewRuleChunk, error) {
+	var chunks []model.ReviewRuleChunk
+	err := s.db.Where("review_rule_id = ?", ruleID).Order("chunk_index ASC").Find(&chunks).Error
+	return chunks, err
+}
+
+func (s *reviewStore) DeleteReviewRuleChunksByRuleID(ruleID uint) error {
+	return s.db.Where("review_rule_id = ?", ruleID).Delete(&model.ReviewRuleChunk{}).Error
+}
+
+func (s *reviewStore) UpdateChunk(chunk *model.ReviewRuleChunk) error {
+	return s.db.Save(chunk).Error
+}
+
 // ReviewResult operations
 
 func (s *reviewStore) CreateResult(result *model.ReviewResult) error {
@@ -498,6 +530,41 @@ func (s *reviewStore) GetReviewResultsByReviewIDs(reviewIDs []string) ([]model.R
 	return results, err
 }
 
+// Usage queries
+
+func (s *reviewStore) ListUsageByRepoSince(repoURL string, start time.Time) ([]model.UsageRecord, error) {
+	var records []model.UsageRecord
+	query := s.db.Table("review_rules").
+		Select("reviews.repo_url, review_rules.created_at, review_rules.prompt_tokens, review_rules.completion_tokens, review_rules.cached_tokens, review_rules.cost").
+		Joins("JOIN reviews ON reviews.id = review_rules.review_id").
+		Where("review_rules.created_at >= ?", start).
+		Where("review_rules.deleted_at IS NULL AND reviews.deleted_at IS NULL").
+		Where("review_rules.prompt_tokens > 0 OR review_rules.completion_tokens > 0")
+
+	if repoURL != "" {
+		query = query.Where("reviews.repo_url = ?", repoURL)
+	}
+
+	err := query.Order("review_rules.created_at ASC").Scan(&records).Error
+	return records, err
+}
+
+func (s *reviewStore) SumCostByRepoSince(repoURL string, start time.Time) (float64, error) {
+	var cost float64
+	query := s.db.Table("review_rules").
+		Select("COALESCE(SUM(review_rules.cost), 0)").
+		Joins("JOIN reviews ON reviews.id = review_rules.review_id").
+		Where("review_rules.created_at >= ?", start).
+		Where("review_rules.deleted_at IS NULL AND reviews.deleted_at IS NULL")
+
+	if repoURL != "" {
+		query = query.Where("reviews.repo_url = ?", repoURL)
+	}
+
+	err := query.Row().Scan(&cost)
+	return cost, err
+}
+
 // Webhook-specific queries
 
 func (s *reviewStore) GetMaxRevisionByPRURL(prURL string) (int, error) {
@@ -595,6 +662,7 @@ func (s *reviewStore) ResetReviewState(reviewID string, retryCount int) error {
 			if err := tx.Model(&rule).Updates(map[string]interface{}{
 				"status":        model.RuleStatusPending,
 				"error_message": "",
+				"skip_reason":   "",
 				"started_at":    nil,
 				"completed_at":  nil,
 			}).Error; err != nil {
@@ -606,6 +674,11 @@ func (s *reviewStore) ResetReviewState(reviewID string, retryCount int) error {
 				return err
 			}
 
+			// Delete old ReviewRuleChunk records
+			if err := tx.Where("review_rule_id = ?", rule.ID).Delete(&model.ReviewRuleChunk{}).Error; err != nil {
+				return err
+			}
+
 			// Delete old ReviewResult records
 			if err := tx.Where("review_rule_id = ?", rule.ID).Delete(&model.ReviewResult{}).Error; err != nil {
 				return err
@@ -661,6 +734,11 @@ func (s *reviewStore) ResetRuleState(ruleID string, reviewID string, ruleRetryCo
 			return err
 		}
 
+		// Delete old ReviewRuleChunk records
+		if err := tx.Where("review_rule_id = ?", rule.ID).Delete(&model.ReviewRuleChunk{}).Error; err != nil {
+			return err
+		}
+
 		// Delete old ReviewResult records
 		if err := tx.Where("review_rule_id = ?", rule.ID).Delete(&model.ReviewResult{}).Error; err != nil {
 			return err
@@ -1220,3 +1220,96 @@ func TestReviewStore_UpdateMergedAtByPRURL(t *testing.T) {
 		}
 	}
 }
+
+// TestReviewStore_ListUsageByRepoSince tests loading rule usage for cost accounting
+func TestReviewStore_ListUsageByRepoSince(t *testing.T) {
+	store, cleanup := SetupTestDB(t)
+	defer cleanup()
+
+	review := CreateTestReview(t, store, func(r *model.Review) {
+		r.ID = "review-usage-1"
+	})
+	other := CreateTestReview(t, store, func(r *model.Review) {
+		r.ID = "review-usage-2"
+		r.RepoURL = "https://github.com/test/other"
+	})
+
+	CreateTestReviewRule(t, store, review.ID, func(r *model.ReviewRule) {
+		r.TokenUsage = model.TokenUsage{PromptTokens: 1000, CompletionTokens: 200, CachedTokens: 400, Cost: 0.5}
+	})
+	CreateTestReviewRule(t, store, review.ID, func(r *model.ReviewRule) {
+		r.RuleID = "test-rule-002" // no usage reported
+	})
+	CreateTestReviewRule(t, store, other.ID, func(r *model.ReviewRule) {
+		r.TokenUsage = model.TokenUsage{PromptTokens: 10, CompletionTokens: 5, Cost: 0.01}
+	})
+
+	records, err := store.Review().ListUsageByRepoSince("https://github.com/test/repo", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListUsageByRepoSince() failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 usage record, got %d", len(records))
+	}
+	if records[0].RepoURL != "https://github.com/test/repo" || records[0].PromptTokens != 1000 ||
+		records[0].CachedTokens != 400 || records[0].Cost != 0.5 {
+		t.Errorf("Unexpected usage record: %+v", records[0])
+	}
+
+	all, err := store.Review().ListUsageByRepoSince("", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListUsageByRepoSince() failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected 2 usage records for all repos, got %d", len(all))
+	}
+
+	future, err := store.Review().ListUsageByRepoSince("", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ListUsageByRepoSince() failed: %v", err)
+	}
+	if len(future) != 0 {
+		t.Errorf("Expected no usage records after start, got %d", len(future))
+	}
+}
+
+// TestReviewStore_SumCostByRepoSince tests summing rule costs for budgets
+func TestReviewStore_SumCostByRepoSince(t *testing.T) {
+	store, cleanup := SetupTestDB(t)
+	defer cleanup()
+
+	review := CreateTestReview(t, store, func(r *model.Review) {
+		r.ID = "review-cost-1"
+	})
+	other := CreateTestReview(t, store, func(r *model.Review) {
+		r.ID = "review-cost-2"
+		r.RepoURL = "https://github.com/test/other"
+	})
+
+	CreateTestReviewRule(t, store, review.ID, func(r *model.ReviewRule) {
+		r.TokenUsage = model.TokenUsage{PromptTokens: 1000, CompletionTokens: 200, Cost: 0.5}
+	})
+	CreateTestReviewRule(t, store, review.ID, func(r *model.ReviewRule) {
+		r.RuleID = "test-rule-002"
+		r.TokenUsage = model.TokenUsage{PromptTokens: 100, CompletionTokens: 20, Cost: 0.25}
+	})
+	CreateTestReviewRule(t, store, other.ID, func(r *model.ReviewRule) {
+		r.TokenUsage = model.TokenUsage{PromptTokens: 10, CompletionTokens: 5, Cost: 0.01}
+	})
+
+	cost, err := store.Review().SumCostByRepoSince("https://github.com/test/repo", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("SumCostByRepoSince() failed: %v", err)
+	}
+	if cost != 0.75 {
+		t.Errorf("Expected cost 0.75, got %v", cost)
+	}
+
+	future, err := store.Review().SumCostByRepoSince("", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SumCostByRepoSince() failed: %v", err)
+	}
+	if future != 0 {
+		t.Errorf("Expected no cost after start, got %v", future)
+	}
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+// ScheduleStore defines operations for ReviewSchedule and ReviewScheduleRun models.
+type ScheduleStore interface {
+	// Schedule CRUD
+	Create(schedule *model.ReviewSchedule) error
+	GetByID(id uint) (*model.ReviewSchedule, error)
+	Save(schedule *model.ReviewSchedule) error
+	Delete(id uint) error
+
+	// Schedule queries
+	ListAll() ([]model.ReviewSchedule, error)
+	ListEnabled() ([]model.ReviewSchedule, error)
+	ListByRepoURL(repoURL string) ([]model.ReviewSchedule, error)
+
+	// UpdateLastRun records the latest trigger of a schedule.
+	UpdateLastRun(id uint, runAt time.Time, reviewID string) error
+
+	// Run history
+	CreateRun(run *model.ReviewScheduleRun) error
+	ListRuns(scheduleID uint, limit int) ([]model.ReviewScheduleRun, error)
+}
+
+// scheduleStore implements ScheduleStore using GORM.
+type scheduleStore struct {
+	db *gorm.DB
+}
+
+func newScheduleStore(db *gorm.DB) ScheduleStore {
+	return &scheduleStore{db: db}
+}
+
+// CRUD implementations
+
+func (s *scheduleStore) Create(schedule *model.ReviewSchedule) error {
+	return s.db.Create(schedule).Error
+}
+
+func (s *scheduleStore) GetByID(id uint) (*model.ReviewSchedule, error) {
+	var schedule model.ReviewSchedule
+	err := s.db.First(&schedule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (s *scheduleStore) Save(schedule *model.ReviewSchedule) error {
+	return s.db.Save(schedule).Error
+}
+
+func (s *scheduleStore) Delete(id uint) error {
+	return s.db.Delete(&model.ReviewSchedule{}, id).Error
+}
+
+// Query operations
+
+func (s *scheduleStore) ListAll() ([]model.ReviewSchedule, error) {
+	var schedules []model.ReviewSchedule
+	err := s.db.Order("created_at DESC").Find(&schedules).Error
+	return schedules, err
+}
+
+func (s *scheduleStore) ListEnabled() ([]model.ReviewSchedule, error) {
+	var schedules []model.ReviewSchedule
+	err := s.db.Where("enabled = ?", true).Order("id ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+func (s *scheduleStore) ListByRepoURL(repoURL string) ([]model.ReviewSchedule, error) {
+	var schedules []model.ReviewSchedule
+	err := s.db.Where("repo_url = ?", repoURL).Order("created_at DESC").Find(&schedules).Error
+	return schedules, err
+}
+
+func (s *scheduleStore) UpdateLastRun(id uint, runAt time.Time, reviewID string) error {
+	return s.db.Model(&model.ReviewSchedule{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_run_at":    runAt,
+			"last_review_id": reviewID,
+		}).Error
+}
+
+// Run history
+
+func (s *scheduleStore) CreateRun(run *model.ReviewScheduleRun) error {
+	return s.db.Create(run).Error
+}
+
+func (s *scheduleStore) ListRuns(scheduleID uint, limit int) ([]model.ReviewScheduleRun, error) {
+	var runs []model.ReviewScheduleRun
+	query := s.db.Where("schedule_id = ?", scheduleID).Order("triggered_at DESC, id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&runs).Error
+	return runs, err
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/verustcode/verustcode/internal/model"
+)
+
+// TestScheduleStore_CreateAndList tests creating schedules and listing enabled ones
+func TestScheduleStore_CreateAndList(t *testing.T) {
+	store, cleanup := SetupTestDB(t)
+	defer cleanup()
+
+	enabled := &model.ReviewSchedule{
+		Name:     "nightly",
+		RepoURL:  "https://github.com/test/repo",
+		Ref:      "main",
+		CronExpr: "0 2 * * *",
+		Mode:     model.ScheduleModeFull,
+		Enabled:  true,
+	}
+	disabled := &model.ReviewSchedule{
+		Name:     "weekly",
+		RepoURL:  "https://github.com/test/other",
+		Ref:      "main",
+		CronExpr: "0 3 * * 1",
+		Mode:     model.ScheduleModeIncremental,
+		Enabled:  false,
+	}
+	if err := store.Schedule().Create(enabled); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if err := store.Schedule().Create(disabled); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	all, err := store.Schedule().ListAll()
+	if err != nil {
+		t.Fatalf("ListAll() failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected 2 schedules, got %d", len(all))
+	}
+
+	active, err := store.Schedule().ListEnabled()
+	if err != nil {
+		t.Fatalf("ListEnabled() failed: %v", err)
+	}
+	if len(active) != 1 || active[0].Name != "nightly" {
+		t.Errorf("Expected only 'nightly' to be enabled, got %+v", active)
+	}
+
+	byRepo, err := store.Schedule().ListByRepoURL("https://github.com/test/other")
+	if err != nil {
+		t.Fatalf("ListByRepoURL() failed: %v", err)
+	}
+	if len(byRepo) != 1 || byRepo[0].Name != "weekly" {
+		t.Errorf("Expected 'weekly' for repo filter, got %+v", byRepo)
+	}
+}
+
+// TestScheduleStore_Runs tests recording run history and last run
+func TestScheduleStore_Runs(t *testing.T) {
+	store, cleanup := SetupTestDB(t)
+	defer cleanup()
+
+	sched := &model.ReviewSchedule{
+		Name:     "nightly",
+		RepoURL:  "https://github.com/test/repo",
+		Ref:      "main",
+		CronExpr: "@daily",
+		Mode:     model.ScheduleModeFull,
+		Enabled:  true,
+	}
+	if err := store.Schedule().Create(sched); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 3; i++ {
+		run := &model.ReviewScheduleRun{
+			ScheduleID:  sched.ID,
+			ReviewID:    "rev-" + string(rune('a'+i)),
+			TriggeredAt: base.Add(time.Duration(i) * time.Minute),
+			Status:      model.ScheduleRunStatusSubmitted,
+		}
+		if err := store.Schedule().CreateRun(run); err != nil {
+			t.Fatalf("CreateRun() failed: %v", err)
+		}
+	}
+
+	runs, err := store.Schedule().ListRuns(sched.ID, 2)
+	if err != nil {
+		t.Fatalf("ListRuns() failed: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("Expected 2 runs with limit, got %d", len(runs))
+	}
+	if runs[0].ReviewID != "rev-c" {
+		t.Errorf("Expected newest run first, got %s", runs[0].ReviewID)
+	}
+
+	runAt := time.Now()
+	if err := store.Schedule().UpdateLastRun(sched.ID, runAt, "rev-c"); err != nil {
+		t.Fatalf("UpdateLastRun() failed: %v", err)
+	}
+	updated, err := store.Schedule().GetByID(sched.ID)
+	if err != nil {
+		t.Fatalf("GetByID() failed: %v", err)
+	}
+	if updated.LastReviewID != "rev-c" || updated.LastRunAt == nil {
+		t.Errorf("Expected last run to be recorded, got %+v", updated)
+	}
+}
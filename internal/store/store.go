@@ -12,6 +12,11 @@ type Store interface {
 	Report() ReportStore
 	Settings() SettingsStore
 	RepositoryConfig() RepositoryConfigStore
+	Schedule() ScheduleStore
+	Area() AreaStore
+	ResponseCache() ResponseCacheStore
+	Cassette() CassetteStore
+	Eval() EvalStore
 
 	// DB returns the underlying database connection for advanced operations.
 	// Use sparingly - prefer using specific store methods.
@@ -28,6 +33,11 @@ type gormStore struct {
 	reportStore      ReportStore
 	settingsStore    SettingsStore
 	repoConfigStore  RepositoryConfigStore
+	scheduleStore    ScheduleStore
+	areaStore        AreaStore
+	cacheStore       ResponseCacheStore
+	cassetteStore    CassetteStore
+	evalStore        EvalStore
 }
 
 // NewStore creates a new Store instance with GORM backend.
@@ -38,6 +48,11 @@ func NewStore(db *gorm.DB) Store {
 		reportStore:      newReportStore(db),
 		settingsStore:    newSettingsStore(db),
 		repoConfigStore:  newRepositoryConfigStore(db),
+		scheduleStore:    newScheduleStore(db),
+		areaStore:        newAreaStore(db),
+		cacheStore:       newResponseCacheStore(db),
+		cassetteStore:    newCassetteStore(db),
+		evalStore:        newEvalStore(db),
 	}
 }
 
@@ -57,6 +72,26 @@ func (s *gormStore) RepositoryConfig() RepositoryConfigStore {
 	return s.repoConfigStore
 }
 
+func (s *gormStore) Schedule() ScheduleStore {
+	return s.scheduleStore
+}
+
+func (s *gormStore) Area() AreaStore {
+	return s.areaStore
+}
+
+func (s *gormStore) ResponseCache() ResponseCacheStore {
+	return s.cacheStore
+}
+
+func (s *gormStore) Cassette() CassetteStore {
+	return s.cassetteStore
+}
+
+func (s *gormStore) Eval() EvalStore {
+	return s.evalStore
+}
+
 func (s *gormStore) DB() *gorm.DB {
 	return s.db
 }
@@ -69,6 +104,11 @@ func (s *gormStore) Transaction(fn func(Store) error) error {
 			reportStore:      newReportStore(tx),
 			settingsStore:    newSettingsStore(tx),
 			repoConfigStore:  newRepositoryConfigStore(tx),
+			scheduleStore:    newScheduleStore(tx),
+			areaStore:        newAreaStore(tx),
+			cacheStore:       newResponseCacheStore(tx),
+			cassetteStore:    newCassetteStore(tx),
+			evalStore:        newEvalStore(tx),
 		}
 		return fn(txStore)
 	})
@@ -36,6 +36,7 @@ const (
 	ErrCodeReviewNotFound ErrorCode = "E4001"
 	ErrCodeReviewFailed   ErrorCode = "E4002"
 	ErrCodeReviewPending  ErrorCode = "E4003"
+	ErrCodeBudgetExceeded ErrorCode = "E4004"
 
 	// Database errors (5xxx)
 	ErrCodeDBConnection ErrorCode = "E5001"
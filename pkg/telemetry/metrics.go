@@ -34,6 +34,16 @@ type Metrics struct {
 	// Agent metrics
 	AgentExecutionsTotal metric.Int64Counter
 	AgentExecutionErrors metric.Int64Counter
+	AgentCircuitState    metric.Int64Gauge
+	AgentCircuitChanges  metric.Int64Counter
+
+	// Output validation metrics
+	OutputValidations      metric.Int64Counter
+	OutputValidationErrors metric.Int64Counter
+
+	// Response cache metrics
+	ResponseCacheHits   metric.Int64Counter
+	ResponseCacheMisses metric.Int64Counter
 
 	// Git metrics
 	GitCloneTotal    metric.Int64Counter
@@ -152,6 +162,62 @@ func initMetrics() (*Metrics, error) {
 		return nil, err
 	}
 
+	m.AgentCircuitState, err = meter.Int64Gauge(
+		"scopeview_agent_circuit_state",
+		metric.WithDescription("Agent circuit breaker state (0=closed, 1=half_open, 2=open)"),
+		metric.WithUnit("{state}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.AgentCircuitChanges, err = meter.Int64Counter(
+		"scopeview_agent_circuit_transitions_total",
+		metric.WithDescription("Total number of agent circuit breaker state transitions"),
+		metric.WithUnit("{transition}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Output validation metrics
+	m.OutputValidations, err = meter.Int64Counter(
+		"scopeview_output_validations_total",
+		metric.WithDescription("Total number of validated agent outputs by outcome (valid, repaired, salvaged, invalid)"),
+		metric.WithUnit("{output}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.OutputValidationErrors, err = meter.Int64Counter(
+		"scopeview_output_validation_errors_total",
+		metric.WithDescription("Total number of schema violations found in agent outputs"),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Response cache metrics
+	m.ResponseCacheHits, err = meter.Int64Counter(
+		"scopeview_response_cache_hits_total",
+		metric.WithDescription("Total number of agent executions served from the response cache"),
+		metric.WithUnit("{lookup}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.ResponseCacheMisses, err = meter.Int64Counter(
+		"scopeview_response_cache_misses_total",
+		metric.WithDescription("Total number of response cache lookups without a usable entry"),
+		metric.WithUnit("{lookup}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	// Git metrics
 	m.GitCloneTotal, err = meter.Int64Counter(
 		"scopeview_git_clone_total",
@@ -257,6 +323,53 @@ func (m *Metrics) RecordAgentExecution(ctx context.Context, agentName string, su
 	}
 }
 
+// RecordCircuitState records an agent circuit breaker state transition
+func (m *Metrics) RecordCircuitState(ctx context.Context, agentName, state string, value int64) {
+	if m.AgentCircuitState != nil {
+		m.AgentCircuitState.Record(ctx, value,
+			metric.WithAttributes(attribute.String("agent.name", agentName)),
+		)
+	}
+	if m.AgentCircuitChanges != nil {
+		m.AgentCircuitChanges.Add(ctx, 1,
+			metric.WithAttributes(
+				attribute.String("agent.name", agentName),
+				attribute.String("state", state),
+			),
+		)
+	}
+}
+
+// RecordOutputValidation records the validation outcome of an agent output and its number of schema violations
+func (m *Metrics) RecordOutputValidation(ctx context.Context, agentName, status string, errorCount int) {
+	if m.OutputValidations != nil {
+		m.OutputValidations.Add(ctx, 1,
+			metric.WithAttributes(
+				attribute.String("agent.name", agentName),
+				attribute.String("status", status),
+			),
+		)
+	}
+	if errorCount > 0 && m.OutputValidationErrors != nil {
+		m.OutputValidationErrors.Add(ctx, int64(errorCount),
+			metric.WithAttributes(attribute.String("agent.name", agentName)),
+		)
+	}
+}
+
+// RecordResponseCacheLookup records a response cache lookup for an agent execution
+func (m *Metrics) RecordResponseCacheLookup(ctx context.Context, agentName string, hit bool) {
+	counter := m.ResponseCacheMisses
+	if hit {
+		counter = m.ResponseCacheHits
+	}
+	if counter != nil {
+		counter.Add(ctx, 1,
+			metric.WithAttributes(attribute.String("agent.name", agentName)),
+		)
+	}
+}
+
 // RecordGitClone records a git clone operation
 func (m *Metrics) RecordGitClone(ctx context.Context, provider string, success bool, durationSeconds float64) {
 	if m.GitCloneTotal != nil {